@@ -82,6 +82,14 @@ var (
 	InvalidParams            = &EngineAPIError{code: -32602, msg: "Invalid parameters"}
 	UnsupportedFork          = &EngineAPIError{code: -38005, msg: "Unsupported fork"}
 
+	// ChainFrozen is returned when a payload build is requested while the
+	// emergency stop of the node is active.
+	ChainFrozen = &EngineAPIError{code: -38100, msg: "Chain frozen"}
+
+	// ReplicaMode is returned when a payload build from the transaction pool is
+	// requested from a replica not promoted to sequencer.
+	ReplicaMode = &EngineAPIError{code: -38101, msg: "Replica mode"}
+
 	STATUS_INVALID         = ForkChoiceResponse{PayloadStatus: PayloadStatusV1{Status: INVALID}, PayloadID: nil}
 	STATUS_SYNCING         = ForkChoiceResponse{PayloadStatus: PayloadStatusV1{Status: SYNCING}, PayloadID: nil}
 	INVALID_TERMINAL_BLOCK = PayloadStatusV1{Status: INVALID, LatestValidHash: &common.Hash{}}
@@ -23,6 +23,7 @@ import (
 	"os"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -34,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/txwal"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/log"
@@ -122,6 +124,26 @@ Optional second and third arguments control the first and
 last block to write. In this mode, the file will be appended
 if already existing. If the file ends with .gz, the output will
 be gzipped.`,
+	}
+	importLegacyCommand = &cli.Command{
+		Action:    importLegacy,
+		Name:      "import-legacy",
+		Usage:     "Import pre-bedrock blocks and receipts into the ancient store",
+		ArgsUsage: "<filename> (<filename 2> ... <filename N>) ",
+		Flags: flags.Merge([]cli.Flag{
+			utils.CacheFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `
+The import-legacy command imports the pre-bedrock history of a migrated chain into
+the ancient store, so it is served locally instead of through the historical RPC
+endpoint. The files contain RLP-encoded [block, receipts] items of the legacy blocks
+in ascending order, with receipts in their storage encoding. Several files can be
+imported one after another, and an interrupted import resumes after the last
+imported block.
+
+Each block is checked against its transactions and receipts, and the chain of
+hashes is verified against the stored blocks. Once the history links to the
+bedrock block, the boundary is recorded in the database.`,
 	}
 	importPreimagesCommand = &cli.Command{
 		Action:    importPreimages,
@@ -150,6 +172,37 @@ It's deprecated, please use "geth db import" instead.
 The export-preimages command exports hash preimages to an RLP encoded stream.
 It's deprecated, please use "geth db export" instead.
 `,
+	}
+	exportTxWALCommand = &cli.Command{
+		Action:    exportTxWAL,
+		Name:      "export-txwal",
+		Usage:     "Export the transactions of a sequencer write-ahead log",
+		ArgsUsage: "<walfile> <dumpfile>",
+		Description: `
+The export-txwal command exports the transactions recorded in the write-ahead log
+of a sequencer (--rollup.txwal) as JSON, one object per line holding the time the
+transaction was accepted at, its hash and its raw encoding, which can be submitted
+again with eth_sendRawTransaction.`,
+	}
+	payloadRangeFlag = &cli.StringFlag{
+		Name:  "range",
+		Usage: "Range of blocks to export, as <first>-<last>",
+	}
+	exportPayloadsCommand = &cli.Command{
+		Action:    exportPayloads,
+		Name:      "export-payloads",
+		Usage:     "Export a range of the chain as engine API execution payloads",
+		ArgsUsage: "<filename>",
+		Flags: flags.Merge([]cli.Flag{
+			payloadRangeFlag,
+			utils.CacheFlag,
+		}, utils.DatabaseFlags),
+		Description: `
+The export-payloads command exports the blocks of the given range as JSON, one object
+per line holding the execution payload envelope of the block, the expected blob
+versioned hashes and the parent beacon block root, that is the arguments of the
+engine_newPayload call importing it. If the file ends with .gz, the output will be
+gzipped.`,
 	}
 	dumpCommand = &cli.Command{
 		Action:    dump,
@@ -398,7 +451,96 @@ func importPreimages(ctx *cli.Context) error {
 	return nil
 }
 
+// importLegacy imports the pre-bedrock history from the given files.
+func importLegacy(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	config, err := core.LoadChainConfig(db, utils.MakeGenesis(ctx))
+	if err != nil {
+		utils.Fatalf("Failed to load chain config: %v", err)
+	}
+	start := time.Now()
+	for _, arg := range ctx.Args().Slice() {
+		if err := utils.ImportLegacyHistory(db, config, arg); err != nil {
+			utils.Fatalf("Import error: %v", err)
+		}
+	}
+	fmt.Printf("Import done in %v\n", time.Since(start))
+	return nil
+}
+
 // exportPreimages dumps the preimage data to specified json file in streaming way.
+// exportTxWAL dumps the transactions of a write-ahead log as JSON lines.
+func exportPayloads(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	if !ctx.IsSet(payloadRangeFlag.Name) {
+		utils.Fatalf("The block range must be given with --%s.", payloadRangeFlag.Name)
+	}
+	firstArg, lastArg, ok := strings.Cut(ctx.String(payloadRangeFlag.Name), "-")
+	first, ferr := strconv.ParseUint(firstArg, 10, 64)
+	last, lerr := strconv.ParseUint(lastArg, 10, 64)
+	if !ok || ferr != nil || lerr != nil {
+		utils.Fatalf("Export error: invalid block range %q, want <first>-<last>", ctx.String(payloadRangeFlag.Name))
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack, true)
+	defer db.Close()
+
+	if head := chain.CurrentBlock(); last > head.Number.Uint64() {
+		utils.Fatalf("Export error: block number %d larger than head block %d\n", last, head.Number.Uint64())
+	}
+	start := time.Now()
+	if err := utils.ExportPayloads(chain, ctx.Args().First(), first, last); err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+	fmt.Printf("Export done in %v\n", time.Since(start))
+	return nil
+}
+
+func exportTxWAL(ctx *cli.Context) error {
+	if ctx.Args().Len() < 2 {
+		utils.Fatalf("This command requires two arguments.")
+	}
+	out, err := os.OpenFile(ctx.Args().Get(1), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		utils.Fatalf("Failed to create dump file: %v", err)
+	}
+	defer out.Close()
+
+	var (
+		enc   = json.NewEncoder(out)
+		count int
+	)
+	err = txwal.Read(ctx.Args().First(), func(entry *txwal.Entry) error {
+		raw, err := entry.Tx.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		count++
+		return enc.Encode(struct {
+			Time time.Time     `json:"time"`
+			Hash common.Hash   `json:"hash"`
+			Raw  hexutil.Bytes `json:"raw"`
+		}{time.UnixMilli(int64(entry.Time)).UTC(), entry.Tx.Hash(), raw})
+	})
+	if err != nil {
+		utils.Fatalf("Export error: %v", err)
+	}
+	fmt.Printf("Exported %d transactions\n", count)
+	return nil
+}
+
 func exportPreimages(ctx *cli.Context) error {
 	if ctx.Args().Len() < 1 {
 		utils.Fatalf("This command requires an argument.")
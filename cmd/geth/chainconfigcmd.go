@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	chainConfigForceFlag = &cli.BoolFlag{
+		Name:  "force",
+		Usage: "Apply the parameter changes needing a review along with the safe ones",
+	}
+	chainConfigCommand = &cli.Command{
+		Name:  "chainconfig",
+		Usage: "Compare and migrate the stored chain config",
+		Subcommands: []*cli.Command{
+			{
+				Action:    chainConfigDiff,
+				Name:      "diff",
+				Usage:     "Compare the stored chain config with a new genesis or config file",
+				ArgsUsage: "<genesis.json>",
+				Flags:     flags.Merge(utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth chainconfig diff <genesis.json>
+
+The diff command compares the chain config stored in the database with the one
+of the given genesis file, or of a file containing only the chain config, e.g.
+when rebasing onto a new upstream release. Each difference is classified against
+the head of the chain:
+
+    safe    a fork block or timestamp not activated at the head, neither in the
+            stored config nor in the new one
+    rewind  a fork block or timestamp activated at the head, only applicable
+            after rewinding the chain before the activation
+    review  a parameter other than a fork schedule, to be reviewed
+
+The command exits with an error if a change requires rewinding the chain.`,
+			},
+			{
+				Action:    chainConfigApply,
+				Name:      "apply",
+				Usage:     "Store the chain config of a new genesis or config file if safe",
+				ArgsUsage: "<genesis.json>",
+				Flags:     flags.Merge([]cli.Flag{chainConfigForceFlag}, utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth chainconfig apply [--force] <genesis.json>
+
+The apply command stores the chain config of the given file in the database if
+all its differences with the stored config are safe, e.g. new forks scheduled in
+the future. Parameter changes needing a review are only applied with --force.
+Changes requiring a rewind of the chain are never applied.`,
+			},
+		},
+	}
+)
+
+// readChainConfigFile reads the chain config of a genesis file, or of a file
+// containing only the chain config.
+func readChainConfigFile(path string) (*params.ChainConfig, error) {
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	genesis := new(core.Genesis)
+	if err := json.Unmarshal(blob, genesis); err == nil && genesis.Config != nil {
+		return genesis.Config, nil
+	}
+	config := new(params.ChainConfig)
+	if err := json.Unmarshal(blob, config); err != nil {
+		return nil, fmt.Errorf("invalid genesis or config file: %v", err)
+	}
+	if config.ChainID == nil {
+		return nil, errors.New("no chain config in file")
+	}
+	return config, nil
+}
+
+// diffStoredChainConfig compares the stored chain config with the one of the
+// file given as argument, printing the differences.
+func diffStoredChainConfig(ctx *cli.Context, db ethdb.Database) (*core.ChainConfigDiff, *params.ChainConfig, error) {
+	if ctx.Args().Len() != 1 {
+		utils.Fatalf("This command requires the genesis or config file as argument.")
+	}
+	newcfg, err := readChainConfigFile(ctx.Args().First())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := newcfg.CheckConfigForkOrder(); err != nil {
+		return nil, nil, fmt.Errorf("invalid new chain config: %v", err)
+	}
+	genesisHash := rawdb.ReadCanonicalHash(db, 0)
+	stored := rawdb.ReadChainConfig(db, genesisHash)
+	if stored == nil {
+		return nil, nil, errors.New("chain config not found")
+	}
+	head := rawdb.ReadHeadHeader(db)
+	if head == nil {
+		return nil, nil, errors.New("no head block")
+	}
+	diff, err := core.DiffChainConfig(stored, newcfg, head)
+	if err != nil {
+		return nil, nil, err
+	}
+	fmt.Printf("Head block: #%d, timestamp %d\n", head.Number, head.Time)
+	if len(diff.Changes) == 0 {
+		fmt.Println("No difference")
+	}
+	for _, change := range diff.Changes {
+		fmt.Printf("%-7s %s: %v -> %v\n", change.Class, change.Field, printableConfigValue(change.Stored), printableConfigValue(change.New))
+	}
+	if diff.Compat != nil {
+		fmt.Printf("Incompatible: %v\n", diff.Compat)
+	}
+	if diff.CompatError != "" {
+		fmt.Printf("Incompatible: %s\n", diff.CompatError)
+	}
+	return diff, newcfg, nil
+}
+
+func printableConfigValue(value interface{}) interface{} {
+	if value == nil {
+		return "unset"
+	}
+	return value
+}
+
+func chainConfigDiff(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	diff, _, err := diffStoredChainConfig(ctx, db)
+	if err != nil {
+		return err
+	}
+	if diff.Rewind() {
+		return errors.New("new chain config requires rewinding the chain")
+	}
+	return nil
+}
+
+func chainConfigApply(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	diff, newcfg, err := diffStoredChainConfig(ctx, db)
+	if err != nil {
+		return err
+	}
+	if len(diff.Changes) == 0 {
+		return nil
+	}
+	if diff.Rewind() {
+		return errors.New("new chain config requires rewinding the chain, not applied")
+	}
+	if diff.CompatError != "" {
+		return errors.New("new chain config incompatible with the chain, not applied")
+	}
+	if !diff.Safe() && !ctx.Bool(chainConfigForceFlag.Name) {
+		return errors.New("new chain config changes parameters needing a review, apply them with --force")
+	}
+	rawdb.WriteChainConfig(db, rawdb.ReadCanonicalHash(db, 0), newcfg)
+	log.Info("Stored new chain config", "changes", len(diff.Changes))
+	return nil
+}
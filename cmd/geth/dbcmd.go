@@ -205,6 +205,14 @@ func removeDB(ctx *cli.Context) error {
 	} else {
 		log.Info("Full node state database missing", "path", path)
 	}
+	// Remove the full node state database kept apart from the chain data, if any
+	if path = stack.ResolveState("chaindata"); path != "" {
+		if common.FileExist(path) {
+			confirmAndRemoveDB(path, "full node separate state database")
+		} else {
+			log.Info("Full node separate state database missing", "path", path)
+		}
+	}
 	// Remove the full node ancient database
 	path = config.Eth.DatabaseFreezer
 	switch {
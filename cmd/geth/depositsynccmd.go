@@ -0,0 +1,98 @@
+package main
+
+import (
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/eth/depositsync"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	depositSyncEndpointFlag = &cli.StringFlag{
+		Name:     "endpoint",
+		Usage:    "RPC endpoint of the L2 node followed",
+		Required: true,
+	}
+	depositSyncFromFlag = &cli.Uint64Flag{
+		Name:  "from",
+		Usage: "Number of the first block synced (default = genesis)",
+	}
+	depositSyncPollFlag = &cli.DurationFlag{
+		Name:  "poll",
+		Usage: "Interval between the checks of the endpoint head",
+		Value: depositsync.DefaultConfig.PollInterval,
+	}
+	depositSyncCommand = &cli.Command{
+		Action: depositSync,
+		Name:   "depositsync",
+		Usage:  "Run a deposit-only node following an L2 node, for bridge monitoring",
+		Flags: flags.Merge([]cli.Flag{
+			depositSyncEndpointFlag,
+			depositSyncFromFlag,
+			depositSyncPollFlag,
+			utils.DataDirFlag,
+			utils.DBEngineFlag,
+			utils.IPCDisabledFlag,
+			utils.IPCPathFlag,
+		}, rpcFlags),
+		Description: `
+geth depositsync --endpoint <url> [--from <number>]
+
+The depositsync command runs a lightweight node for the monitoring of the bridge.
+It follows the chain of the L2 node behind the endpoint, downloading the headers
+and the receipts of the blocks but only storing the deposit transactions with
+their receipts and the withdrawals initiated on L2. Neither block bodies nor
+state are stored, and no block is executed: the receipts are verified against
+the receipt root of their header, the headers being trusted as served by the
+endpoint, which must serve eth_getBlockReceipts.
+
+The node serves the deposits namespace only, unless other namespaces are enabled
+with --http.api and --ws.api:
+
+    deposits_syncStatus()
+    deposits_getDeposits(block)
+    deposits_getDeposit(hash)
+    deposits_getWithdrawals(fromBlock, toBlock)`,
+	}
+)
+
+// depositSync runs a deposit-only node until interrupted.
+func depositSync(ctx *cli.Context) error {
+	cfg := loadBaseConfig(ctx)
+	if !ctx.IsSet(utils.HTTPApiFlag.Name) {
+		cfg.Node.HTTPModules = []string{"deposits"}
+	}
+	if !ctx.IsSet(utils.WSApiFlag.Name) {
+		cfg.Node.WSModules = []string{"deposits"}
+	}
+	stack, err := node.New(&cfg.Node)
+	if err != nil {
+		utils.Fatalf("Failed to create the protocol stack: %v", err)
+	}
+	defer stack.Close()
+
+	client, err := rpc.DialContext(ctx.Context, ctx.String(depositSyncEndpointFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to dial the endpoint: %v", err)
+	}
+	defer client.Close()
+
+	db, err := stack.OpenDatabase("depositdata", 16, 16, "eth/db/depositdata/", false)
+	if err != nil {
+		utils.Fatalf("Failed to open the database: %v", err)
+	}
+	syncer := depositsync.New(db, client, depositsync.Config{
+		From:         ctx.Uint64(depositSyncFromFlag.Name),
+		PollInterval: ctx.Duration(depositSyncPollFlag.Name),
+	})
+	stack.RegisterAPIs(syncer.APIs())
+	stack.RegisterLifecycle(syncer)
+
+	log.Info("Starting deposit-only node", "endpoint", ctx.String(depositSyncEndpointFlag.Name), "from", ctx.Uint64(depositSyncFromFlag.Name))
+	utils.StartNode(ctx, stack, false)
+	stack.Wait()
+	return nil
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	devnetChainIDFlag = &cli.Uint64Flag{
+		Name:  "chainid",
+		Usage: "Chain ID of the devnet",
+		Value: 1337,
+	}
+	devnetSeedFlag = &cli.StringFlag{
+		Name:  "seed",
+		Usage: "Seed the keys of the funded accounts and the JWT secret are derived from",
+		Value: "oasys-devnet",
+	}
+	devnetAccountsFlag = &cli.IntFlag{
+		Name:  "accounts",
+		Usage: "Number of funded accounts",
+		Value: 10,
+	}
+	devnetBalanceFlag = &cli.Uint64Flag{
+		Name:  "balance",
+		Usage: "Balance of the funded accounts, in ether",
+		Value: 10000,
+	}
+	devnetGasLimitFlag = &cli.Uint64Flag{
+		Name:  "gaslimit",
+		Usage: "Gas limit of the genesis block",
+		Value: 30_000_000,
+	}
+	devnetZeroFeeFlag = &cli.StringFlag{
+		Name:  "zerofee",
+		Usage: "Comma separated timestamps alternately starting and ending zero-fee windows",
+	}
+	devnetAllocsFlag = &cli.StringFlag{
+		Name:  "allocs",
+		Usage: "JSON file of genesis allocations merged into the genesis, e.g. the OP predeploys and Oasys system contracts",
+	}
+	devnetCommand = &cli.Command{
+		Name:  "devnet",
+		Usage: "Manage local development networks",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "init",
+				Usage:     "Generate the genesis and node configuration of a devnet",
+				ArgsUsage: "<outdir>",
+				Action:    devnetInit,
+				Flags: []cli.Flag{
+					devnetChainIDFlag,
+					devnetSeedFlag,
+					devnetAccountsFlag,
+					devnetBalanceFlag,
+					devnetGasLimitFlag,
+					devnetZeroFeeFlag,
+					devnetAllocsFlag,
+				},
+				Description: `
+geth devnet init [--chainid <id>] [--accounts <n>] [--allocs <file>] <outdir>
+
+Generates the genesis of an OP Stack devnet with all the supported forks active
+at genesis, along with the configuration of a node serving it. The output is
+deterministic: the keys of the funded accounts and the JWT secret of the engine
+API are derived from the seed, and printed so that they can be used by tooling.
+
+The bytecode of the OP predeploys and of the Oasys system contracts is not part
+of geth, it is merged from the allocations file given with --allocs, such as the
+L2 allocations dumped by the contracts deployment. The fee parameters of the
+L1Block predeploy are initialized unless provided by the allocations.
+
+The devnet is started with blocks produced by the simulated beacon by running:
+
+    geth init --datadir <outdir>/data <outdir>/genesis.json
+    geth --dev --config <outdir>/config.toml --datadir <outdir>/data`,
+			},
+		},
+	}
+)
+
+var (
+	// devnetL1BaseFee, devnetL1FeeOverhead and devnetL1FeeScalar are the L1 fee
+	// parameters the L1Block predeploy of devnets is initialized with.
+	devnetL1BaseFee     = big.NewInt(params.GWei)
+	devnetL1FeeOverhead = big.NewInt(188)
+	devnetL1FeeScalar   = big.NewInt(684_000)
+)
+
+// devnetInit generates the genesis, node configuration and JWT secret of a devnet
+// in the given directory.
+func devnetInit(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		utils.Fatalf("This command requires the output directory as argument.")
+	}
+	outdir, err := filepath.Abs(flags.ExpandPath(ctx.Args().First()))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(outdir, 0700); err != nil {
+		return err
+	}
+	seed := ctx.String(devnetSeedFlag.Name)
+
+	// Assemble the genesis, funding the accounts derived from the seed
+	genesis, err := devnetGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	balance := new(big.Int).Mul(new(big.Int).SetUint64(ctx.Uint64(devnetBalanceFlag.Name)), big.NewInt(params.Ether))
+	for i := 0; i < ctx.Int(devnetAccountsFlag.Name); i++ {
+		index := make([]byte, 8)
+		binary.BigEndian.PutUint64(index, uint64(i))
+		key, err := crypto.ToECDSA(crypto.Keccak256([]byte(seed), index))
+		if err != nil {
+			return err
+		}
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		genesis.Alloc[addr] = core.GenesisAccount{Balance: balance}
+		fmt.Printf("Account #%d: %s (key %x)\n", i, addr.Hex(), crypto.FromECDSA(key))
+	}
+	data, err := json.MarshalIndent(genesis, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(outdir, "genesis.json"), data, 0644); err != nil {
+		return err
+	}
+	// Write the JWT secret and the configuration of the node
+	jwtSecret := crypto.Keccak256([]byte(seed), []byte("jwt"))
+	if err := os.WriteFile(filepath.Join(outdir, "jwt.hex"), []byte(hexutil.Encode(jwtSecret)), 0600); err != nil {
+		return err
+	}
+	cfg := gethConfig{
+		Eth:     ethconfig.Defaults,
+		Node:    defaultNodeConfig(),
+		Metrics: metrics.DefaultConfig,
+	}
+	cfg.Eth.NetworkId = genesis.Config.ChainID.Uint64()
+	cfg.Node.DataDir = filepath.Join(outdir, "data")
+	cfg.Node.JWTSecret = filepath.Join(outdir, "jwt.hex")
+	cfg.Node.HTTPHost = "127.0.0.1"
+	cfg.Node.HTTPModules = append(cfg.Node.HTTPModules, "dev", "debug")
+	cfg.Node.P2P.NoDiscovery = true
+	cfg.Node.P2P.MaxPeers = 0
+
+	out, err := tomlSettings.Marshal(&cfg)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(outdir, "config.toml"), out, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Devnet %d generated in %s, genesis %s\n", genesis.Config.ChainID, outdir, genesis.ToBlock().Hash())
+	return nil
+}
+
+// devnetGenesis creates the genesis of a devnet with all the supported forks
+// active, without the funded accounts.
+func devnetGenesis(ctx *cli.Context) (*core.Genesis, error) {
+	var zero uint64
+	config := &params.ChainConfig{
+		ChainID:                       new(big.Int).SetUint64(ctx.Uint64(devnetChainIDFlag.Name)),
+		HomesteadBlock:                big.NewInt(0),
+		EIP150Block:                   big.NewInt(0),
+		EIP155Block:                   big.NewInt(0),
+		EIP158Block:                   big.NewInt(0),
+		ByzantiumBlock:                big.NewInt(0),
+		ConstantinopleBlock:           big.NewInt(0),
+		PetersburgBlock:               big.NewInt(0),
+		IstanbulBlock:                 big.NewInt(0),
+		MuirGlacierBlock:              big.NewInt(0),
+		BerlinBlock:                   big.NewInt(0),
+		LondonBlock:                   big.NewInt(0),
+		ArrowGlacierBlock:             big.NewInt(0),
+		GrayGlacierBlock:              big.NewInt(0),
+		MergeNetsplitBlock:            big.NewInt(0),
+		ShanghaiTime:                  &zero,
+		BedrockBlock:                  big.NewInt(0),
+		RegolithTime:                  &zero,
+		CanyonTime:                    &zero,
+		OasysOperatorFeeTime:          &zero,
+		TerminalTotalDifficulty:       big.NewInt(0),
+		TerminalTotalDifficultyPassed: true,
+		IsDevMode:                     true,
+		Optimism: &params.OptimismConfig{
+			EIP1559Elasticity:        6,
+			EIP1559Denominator:       50,
+			EIP1559DenominatorCanyon: 250,
+		},
+	}
+	if ctx.IsSet(devnetZeroFeeFlag.Name) {
+		for _, arg := range utils.SplitAndTrim(ctx.String(devnetZeroFeeFlag.Name)) {
+			time, err := strconv.ParseUint(arg, 0, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid zero-fee timestamp %q", arg)
+			}
+			config.ZeroFeeTimes = append(config.ZeroFeeTimes, time)
+		}
+	}
+	if err := config.CheckConfigForkOrder(); err != nil {
+		return nil, err
+	}
+	genesis := &core.Genesis{
+		Config:     config,
+		GasLimit:   ctx.Uint64(devnetGasLimitFlag.Name),
+		BaseFee:    big.NewInt(params.InitialBaseFee),
+		Difficulty: big.NewInt(0),
+		Alloc: core.GenesisAlloc{
+			types.L1BlockAddr: {
+				Balance: new(big.Int),
+				Storage: map[common.Hash]common.Hash{
+					types.L1BaseFeeSlot: common.BigToHash(devnetL1BaseFee),
+					types.OverheadSlot:  common.BigToHash(devnetL1FeeOverhead),
+					types.ScalarSlot:    common.BigToHash(devnetL1FeeScalar),
+				},
+			},
+		},
+	}
+	if file := ctx.String(devnetAllocsFlag.Name); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var allocs core.GenesisAlloc
+		if err := json.Unmarshal(data, &allocs); err != nil {
+			return nil, fmt.Errorf("invalid allocations file: %v", err)
+		}
+		for addr, account := range allocs {
+			genesis.Alloc[addr] = account
+		}
+	}
+	return genesis, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TestDevnetInit tests that "geth devnet init" generates a deterministic genesis
+// which can be initialized.
+func TestDevnetInit(t *testing.T) {
+	var genesis [][]byte
+	for i := 0; i < 2; i++ {
+		outdir := t.TempDir()
+		geth := runGeth(t, "devnet", "init", "--chainid", "4242", "--accounts", "3", "--zerofee", "100,200", outdir)
+		geth.WaitExit()
+		if have, want := geth.ExitStatus(), 0; have != want {
+			t.Fatalf("exit error, have %d want %d", have, want)
+		}
+		data, err := os.ReadFile(filepath.Join(outdir, "genesis.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		genesis = append(genesis, data)
+
+		for _, file := range []string{"config.toml", "jwt.hex"} {
+			if _, err := os.Stat(filepath.Join(outdir, file)); err != nil {
+				t.Fatalf("missing %s: %v", file, err)
+			}
+		}
+	}
+	if !bytes.Equal(genesis[0], genesis[1]) {
+		t.Fatal("genesis not deterministic")
+	}
+	var spec core.Genesis
+	if err := json.Unmarshal(genesis[0], &spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.Config.ChainID.Uint64() != 4242 || !spec.Config.IsOptimism() || !spec.Config.IsDevMode {
+		t.Fatalf("unexpected chain config: %v", spec.Config)
+	}
+	if len(spec.Config.ZeroFeeTimes) != 2 || !spec.Config.IsFeeZero(150) || spec.Config.IsFeeZero(250) {
+		t.Fatalf("zero-fee windows mismatch: %v", spec.Config.ZeroFeeTimes)
+	}
+	if _, ok := spec.Alloc[types.L1BlockAddr]; !ok {
+		t.Fatal("L1Block predeploy missing")
+	}
+	if len(spec.Alloc) != 4 {
+		t.Fatalf("allocation count mismatch: have %d, want 4", len(spec.Alloc))
+	}
+}
@@ -18,11 +18,14 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 // TestExport does a basic test of "geth export", exporting the test-genesis.
@@ -43,3 +46,45 @@ func TestExport(t *testing.T) {
 		t.Fatalf("wrong content exported")
 	}
 }
+
+// TestExportPayloads does a basic test of "geth export-payloads", exporting the
+// test-genesis as an execution payload.
+func TestExportPayloads(t *testing.T) {
+	datadir := initGeth(t)
+	outfile := filepath.Join(t.TempDir(), "payloads.jsonl")
+
+	geth := runGeth(t, "--datadir", datadir, "export-payloads", "--range", "0-0", outfile)
+	geth.WaitExit()
+	if have, want := geth.ExitStatus(), 0; have != want {
+		t.Fatalf("exit error, have %d want %d", have, want)
+	}
+	content, err := os.ReadFile(outfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record struct {
+		Envelope struct {
+			ExecutionPayload struct {
+				Number hexutil.Uint64 `json:"blockNumber"`
+			} `json:"executionPayload"`
+		} `json:"envelope"`
+	}
+	if err := json.Unmarshal(content, &record); err != nil {
+		t.Fatalf("invalid payload exported: %v", err)
+	}
+	if number := record.Envelope.ExecutionPayload.Number; number != 0 {
+		t.Fatalf("wrong block exported: have #%d, want #0", number)
+	}
+	// Missing, malformed and out of range block ranges are rejected
+	for _, args := range [][]string{
+		{"export-payloads", outfile},
+		{"export-payloads", "--range", "0", outfile},
+		{"export-payloads", "--range", "0-1", outfile},
+	} {
+		geth := runGeth(t, append([]string{"--datadir", datadir}, args...)...)
+		geth.WaitExit()
+		if geth.ExitStatus() == 0 {
+			t.Errorf("%v: invalid export succeeded", args)
+		}
+	}
+}
@@ -45,6 +45,9 @@ import (
 	_ "github.com/ethereum/go-ethereum/eth/tracers/js"
 	_ "github.com/ethereum/go-ethereum/eth/tracers/native"
 
+	// Force-load the custom chain indexes to trigger registration
+	_ "github.com/ethereum/go-ethereum/eth/withdrawalindex"
+
 	"github.com/urfave/cli/v2"
 )
 
@@ -68,6 +71,7 @@ var (
 		utils.OverrideCancun,
 		utils.OverrideVerkle,
 		utils.OverrideOptimismCanyon,
+		utils.OverrideOasysOperatorFee,
 		utils.OverrideOptimismInterop,
 		utils.EnablePersonal,
 		utils.TxPoolLocalsFlag,
@@ -82,6 +86,9 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.TxPoolPaymasterFlag,
+		utils.TxPoolPaymasterGasFlag,
+		utils.TxPoolPaymasterTimeoutFlag,
 		utils.BlobPoolDataDirFlag,
 		utils.BlobPoolDataCapFlag,
 		utils.BlobPoolPriceBumpFlag,
@@ -90,8 +97,10 @@ var (
 		utils.ExitWhenSyncedFlag,
 		utils.GCModeFlag,
 		utils.SnapshotFlag,
+		utils.SnapshotGenRateFlag,
 		utils.TxLookupLimitFlag,
 		utils.TransactionHistoryFlag,
+		utils.TransactionSenderIndexFlag,
 		utils.StateHistoryFlag,
 		utils.LightServeFlag,
 		utils.LightIngressFlag,
@@ -151,9 +160,53 @@ var (
 		utils.RollupHistoricalRPCFlag,
 		utils.RollupHistoricalRPCTimeoutFlag,
 		utils.RollupDisableTxPoolGossipFlag,
+		utils.RollupBlockGossipFlag,
+		utils.RollupForwardPrecheckFlag,
 		utils.RollupComputePendingBlock,
+		utils.RollupPendingViewFlag,
+		utils.RollupTxTimeBudgetFlag,
+		utils.RollupTxTimeBudgetEvictFlag,
+		utils.RollupOrderingAuditFlag,
 		utils.RollupHaltOnIncompatibleProtocolVersionFlag,
+		utils.RollupHaltDelayFlag,
 		utils.RollupSuperchainUpgradesFlag,
+		utils.RollupFeeCheckFlag,
+		utils.RollupFeeCheckHaltFlag,
+		utils.RollupFeeCheckL1Flag,
+		utils.RollupFeeCheckSystemConfigFlag,
+		utils.RollupDepositCheckFlag,
+		utils.RollupTxWALFlag,
+		utils.RollupHealthMaxHeadAgeFlag,
+		utils.RollupHealthMaxEngineAgeFlag,
+		utils.RollupRuntimeConfigFlag,
+		utils.RollupDrainTimeoutFlag,
+		utils.RollupDrainNotifyFlag,
+		utils.RollupFreezeMarkerFlag,
+		utils.RollupReplicaCheckFlag,
+		utils.RollupReplicaCheckIntervalFlag,
+		utils.RollupReplicaModeFlag,
+		utils.RollupInclusionMonitorFlag,
+		utils.RollupNonceGapThresholdFlag,
+		utils.RollupNonceGapEvictFlag,
+		utils.RollupForkRehearsalWindowFlag,
+		utils.RollupForkRehearsalIntervalFlag,
+		utils.RollupGasGovernorTargetFlag,
+		utils.RollupGasGovernorMinFlag,
+		utils.RollupGasGovernorMaxFlag,
+		utils.RollupFeeRecipientsFlag,
+		utils.RollupFeeRecipientRotationFlag,
+		utils.RollupGasProfileFlag,
+		utils.RollupWarmCacheFlag,
+		utils.RollupDAServerFlag,
+		utils.RollupDATargetsFlag,
+		utils.RollupDATimeoutFlag,
+		utils.RollupDAResolveFlag,
+		utils.RollupDAFailurePolicyFlag,
+		utils.RollupBlockUsageFlag,
+		utils.RollupIndexersFlag,
+		utils.RollupAncientCheckIntervalFlag,
+		utils.RollupAncientCheckEndpointFlag,
+		utils.RollupMinConsensusVersionsFlag,
 		configFileFlag,
 	}, utils.NetworkFlags, utils.DatabaseFlags)
 
@@ -165,7 +218,12 @@ var (
 		utils.AuthListenFlag,
 		utils.AuthPortFlag,
 		utils.AuthVirtualHostsFlag,
+		utils.AuthTLSCertFlag,
+		utils.AuthTLSKeyFlag,
+		utils.AuthTLSClientCAFlag,
 		utils.JWTSecretFlag,
+		utils.JWTSecretRefreshFlag,
+		utils.JWTSecretGraceFlag,
 		utils.HTTPVirtualHostsFlag,
 		utils.GraphQLEnabledFlag,
 		utils.GraphQLCORSDomainFlag,
@@ -178,15 +236,29 @@ var (
 		utils.WSApiFlag,
 		utils.WSAllowedOriginsFlag,
 		utils.WSPathPrefixFlag,
+		utils.WSMaxConnsFlag,
+		utils.WSMaxConnsPerIPFlag,
+		utils.WSIdleTimeoutFlag,
+		utils.WSMaxSubscriptionsFlag,
 		utils.IPCDisabledFlag,
 		utils.IPCPathFlag,
 		utils.InsecureUnlockAllowedFlag,
 		utils.RPCGlobalGasCapFlag,
 		utils.RPCGlobalEVMTimeoutFlag,
+		utils.RPCGlobalEVMMemoryFlag,
+		utils.RPCGlobalEVMReadsFlag,
 		utils.RPCGlobalTxFeeCapFlag,
+		utils.RPCLatestBlockTagFlag,
+		utils.RPCLatestBlockTagNamespacesFlag,
+		utils.RPCAPIKeysFlag,
+		utils.RPCCacheFlag,
+		utils.RPCCacheTTLFlag,
 		utils.AllowUnprotectedTxs,
 		utils.BatchRequestLimit,
 		utils.BatchResponseMaxSize,
+		utils.RequestMaxSize,
+		utils.InflightRequestLimit,
+		utils.ExecutionPoolsFlag,
 	}
 
 	metricsFlags = []cli.Flag{
@@ -204,6 +276,8 @@ var (
 		utils.MetricsInfluxDBTokenFlag,
 		utils.MetricsInfluxDBBucketFlag,
 		utils.MetricsInfluxDBOrganizationFlag,
+		utils.TracingEndpointFlag,
+		utils.TracingServiceFlag,
 	}
 )
 
@@ -217,9 +291,12 @@ func init() {
 		// See chaincmd.go:
 		initCommand,
 		importCommand,
+		importLegacyCommand,
 		exportCommand,
 		importPreimagesCommand,
 		exportPreimagesCommand,
+		exportTxWALCommand,
+		exportPayloadsCommand,
 		removedbCommand,
 		dumpCommand,
 		dumpGenesisCommand,
@@ -244,6 +321,16 @@ func init() {
 		snapshotCommand,
 		// See verkle.go
 		verkleCommand,
+		// See shadowfork.go
+		shadowForkCommand,
+		// See chainconfigcmd.go
+		chainConfigCommand,
+		// See depositsynccmd.go
+		depositSyncCommand,
+		// See devnetcmd.go
+		devnetCommand,
+		// See fuzzpayloadscmd.go
+		fuzzPayloadsCommand,
 	}
 	if logTestCommand != nil {
 		app.Commands = append(app.Commands, logTestCommand)
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	shadowForkBlockFlag = &cli.Uint64Flag{
+		Name:  "block",
+		Usage: "Number of the block whose state is forked (default = head block)",
+	}
+	shadowForkChainIDFlag = &cli.Uint64Flag{
+		Name:  "chainid",
+		Usage: "Chain ID of the forked chain (default = chain ID of the source chain)",
+	}
+	shadowForkCommand = &cli.Command{
+		Action:    shadowFork,
+		Name:      "shadowfork",
+		Usage:     "Fork the state of the chain into an isolated development chain",
+		ArgsUsage: "<targetdir>",
+		Flags: flags.Merge([]cli.Flag{
+			shadowForkBlockFlag,
+			shadowForkChainIDFlag,
+			utils.OverrideCancun,
+			utils.OverrideVerkle,
+			utils.OverrideOptimismCanyon,
+			utils.OverrideOasysOperatorFee,
+			utils.OverrideOptimismInterop,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `
+geth shadowfork --block <number> --chainid <id> <targetdir>
+
+The shadowfork command copies the state of the canonical chain at the given block
+into a new data directory, whose genesis block starts from that state. The blocks
+preceding the forked one are not copied: block numbers restart from zero while
+timestamps continue from the forked block, and forks scheduled by block number are
+moved accordingly. The fork override flags schedule the timestamp based forks of
+the forked chain, e.g. to rehearse an upgrade against the production state.
+
+The forked chain is a development chain isolated from the network, which blocks
+are produced by the simulated beacon when running:
+
+    geth --dev --datadir <targetdir>`,
+	}
+)
+
+// shadowFork copies the state at the requested block of the chain into a new
+// development chain.
+func shadowFork(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		utils.Fatalf("This command requires the target directory as argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, true)
+	defer chaindb.Close()
+
+	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, true)
+	defer triedb.Close()
+
+	// Resolve the block to fork and the configuration of the forked chain
+	genesisHash := rawdb.ReadCanonicalHash(chaindb, 0)
+	config := rawdb.ReadChainConfig(chaindb, genesisHash)
+	if config == nil {
+		return errors.New("chain config not found")
+	}
+	header := rawdb.ReadHeadHeader(chaindb)
+	if header == nil {
+		return errors.New("no head block")
+	}
+	if ctx.IsSet(shadowForkBlockFlag.Name) {
+		number := ctx.Uint64(shadowForkBlockFlag.Name)
+		if header = rawdb.ReadHeader(chaindb, rawdb.ReadCanonicalHash(chaindb, number), number); header == nil {
+			return fmt.Errorf("block #%d not found", number)
+		}
+	}
+	if ctx.IsSet(shadowForkChainIDFlag.Name) {
+		config.ChainID = new(big.Int).SetUint64(ctx.Uint64(shadowForkChainIDFlag.Name))
+	}
+	shadowForkConfig(ctx, config, header.Number)
+
+	// Create the database of the forked chain, refusing to overwrite a chain
+	target := filepath.Join(flags.ExpandPath(ctx.Args().First()), "geth", "chaindata")
+	db, err := rawdb.Open(rawdb.OpenOptions{
+		Type:      ctx.String(utils.DBEngineFlag.Name),
+		Directory: target,
+		Namespace: "eth/db/shadowfork/",
+		Cache:     16,
+		Handles:   16,
+	})
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if rawdb.ReadCanonicalHash(db, 0) != (common.Hash{}) {
+		return fmt.Errorf("chain already present in %s", target)
+	}
+	log.Info("Forking state", "number", header.Number, "hash", header.Hash(), "root", header.Root)
+	if err := copyShadowForkState(chaindb, triedb, db, header.Root); err != nil {
+		return err
+	}
+	genesis := &core.Genesis{
+		Config:        config,
+		Nonce:         header.Nonce.Uint64(),
+		Timestamp:     header.Time,
+		ExtraData:     header.Extra,
+		GasLimit:      header.GasLimit,
+		Difficulty:    new(big.Int),
+		Mixhash:       header.MixDigest,
+		Coinbase:      header.Coinbase,
+		BaseFee:       header.BaseFee,
+		ExcessBlobGas: header.ExcessBlobGas,
+		BlobGasUsed:   header.BlobGasUsed,
+		StateHash:     &header.Root,
+	}
+	block, err := genesis.Commit(db, trie.NewDatabase(db, trie.HashDefaults))
+	if err != nil {
+		return err
+	}
+	log.Info("Created shadow fork", "chainid", config.ChainID, "genesis", block.Hash(), "datadir", ctx.Args().First())
+	return nil
+}
+
+// shadowForkConfig adapts the chain config of the source chain to a development
+// chain whose genesis is the given block.
+func shadowForkConfig(ctx *cli.Context, config *params.ChainConfig, number *big.Int) {
+	for _, fork := range []**big.Int{
+		&config.HomesteadBlock, &config.DAOForkBlock, &config.EIP150Block, &config.EIP155Block,
+		&config.EIP158Block, &config.ByzantiumBlock, &config.ConstantinopleBlock, &config.PetersburgBlock,
+		&config.IstanbulBlock, &config.MuirGlacierBlock, &config.BerlinBlock, &config.LondonBlock,
+		&config.ArrowGlacierBlock, &config.GrayGlacierBlock, &config.MergeNetsplitBlock, &config.BedrockBlock,
+	} {
+		switch {
+		case *fork == nil:
+		case (*fork).Cmp(number) <= 0:
+			*fork = new(big.Int)
+		default:
+			*fork = new(big.Int).Sub(*fork, number)
+		}
+	}
+	// Blocks of the forked chain are produced by the simulated beacon
+	config.TerminalTotalDifficulty = new(big.Int)
+	config.TerminalTotalDifficultyPassed = true
+	config.IsDevMode = true
+
+	if ctx.IsSet(utils.OverrideCancun.Name) {
+		v := ctx.Uint64(utils.OverrideCancun.Name)
+		config.CancunTime = &v
+	}
+	if ctx.IsSet(utils.OverrideVerkle.Name) {
+		v := ctx.Uint64(utils.OverrideVerkle.Name)
+		config.VerkleTime = &v
+	}
+	if ctx.IsSet(utils.OverrideOptimismCanyon.Name) {
+		v := ctx.Uint64(utils.OverrideOptimismCanyon.Name)
+		config.CanyonTime = &v
+		config.ShanghaiTime = &v
+		if config.Optimism != nil && config.Optimism.EIP1559DenominatorCanyon == 0 {
+			config.Optimism.EIP1559DenominatorCanyon = 250
+		}
+	}
+	if ctx.IsSet(utils.OverrideOasysOperatorFee.Name) {
+		v := ctx.Uint64(utils.OverrideOasysOperatorFee.Name)
+		config.OasysOperatorFeeTime = &v
+	}
+	if ctx.IsSet(utils.OverrideOptimismInterop.Name) {
+		v := ctx.Uint64(utils.OverrideOptimismInterop.Name)
+		config.InteropTime = &v
+	}
+}
+
+// copyShadowForkState copies the account and storage trie nodes of the given
+// state root, as well as the contract codes, into the target database using the
+// hash-based scheme.
+func copyShadowForkState(chaindb ethdb.Database, triedb *trie.Database, db ethdb.Database, root common.Hash) error {
+	t, err := trie.NewStateTrie(trie.StateTrieID(root), triedb)
+	if err != nil {
+		return err
+	}
+	accIter, err := t.NodeIterator(nil)
+	if err != nil {
+		return err
+	}
+	var (
+		nodes      int
+		accounts   int
+		codes      int
+		lastReport time.Time
+		start      = time.Now()
+		batch      = db.NewBatch()
+	)
+	flush := func(force bool) error {
+		if batch.ValueSize() > ethdb.IdealBatchSize || force {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		if time.Since(lastReport) > time.Second*8 {
+			log.Info("Copying state", "nodes", nodes, "accounts", accounts, "codes", codes, "elapsed", common.PrettyDuration(time.Since(start)))
+			lastReport = time.Now()
+		}
+		return nil
+	}
+	for accIter.Next(true) {
+		// Embedded nodes are stored within their parent
+		if hash := accIter.Hash(); hash != (common.Hash{}) {
+			rawdb.WriteLegacyTrieNode(batch, hash, accIter.NodeBlob())
+			nodes++
+		}
+		if accIter.Leaf() {
+			accounts++
+			var acc types.StateAccount
+			if err := rlp.DecodeBytes(accIter.LeafBlob(), &acc); err != nil {
+				return fmt.Errorf("invalid account: %v", err)
+			}
+			if acc.Root != types.EmptyRootHash {
+				id := trie.StorageTrieID(root, common.BytesToHash(accIter.LeafKey()), acc.Root)
+				storageTrie, err := trie.NewStateTrie(id, triedb)
+				if err != nil {
+					return err
+				}
+				storageIter, err := storageTrie.NodeIterator(nil)
+				if err != nil {
+					return err
+				}
+				for storageIter.Next(true) {
+					if hash := storageIter.Hash(); hash != (common.Hash{}) {
+						rawdb.WriteLegacyTrieNode(batch, hash, storageIter.NodeBlob())
+						nodes++
+					}
+					if err := flush(false); err != nil {
+						return err
+					}
+				}
+				if storageIter.Error() != nil {
+					return storageIter.Error()
+				}
+			}
+			if !bytes.Equal(acc.CodeHash, types.EmptyCodeHash.Bytes()) {
+				code := rawdb.ReadCode(chaindb, common.BytesToHash(acc.CodeHash))
+				if len(code) == 0 {
+					return fmt.Errorf("missing code %x", acc.CodeHash)
+				}
+				rawdb.WriteCode(batch, common.BytesToHash(acc.CodeHash), code)
+				codes++
+			}
+		}
+		if err := flush(false); err != nil {
+			return err
+		}
+	}
+	if accIter.Error() != nil {
+		return accIter.Error()
+	}
+	if err := flush(true); err != nil {
+		return err
+	}
+	log.Info("Copied state", "nodes", nodes, "accounts", accounts, "codes", codes, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
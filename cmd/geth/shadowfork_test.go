@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// shadowForkCode is the code of the contract in the test state.
+var shadowForkCode = []byte{0x60, 0x00}
+
+// newShadowForkState creates a state holding a contract with code and storage,
+// returning its root.
+func newShadowForkState(t *testing.T, db ethdb.Database) (*trie.Database, common.Hash) {
+	triedb := trie.NewDatabase(db, trie.HashDefaults)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabaseWithNodeDB(db, triedb), nil)
+	statedb.SetBalance(common.Address{0x01}, big.NewInt(1))
+	statedb.SetCode(common.Address{0x02}, shadowForkCode)
+	for i := byte(0); i < 32; i++ {
+		statedb.SetState(common.Address{0x02}, common.Hash{i}, common.Hash{0xff, i})
+	}
+	root, err := statedb.Commit(0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := triedb.Commit(root, false); err != nil {
+		t.Fatal(err)
+	}
+	return triedb, root
+}
+
+func TestCopyShadowForkState(t *testing.T) {
+	var (
+		srcdb        = rawdb.NewMemoryDatabase()
+		triedb, root = newShadowForkState(t, srcdb)
+		db           = rawdb.NewMemoryDatabase()
+	)
+	if err := copyShadowForkState(srcdb, triedb, db, root); err != nil {
+		t.Fatalf("failed to copy state: %v", err)
+	}
+	statedb, err := state.New(root, state.NewDatabaseWithNodeDB(db, trie.NewDatabase(db, trie.HashDefaults)), nil)
+	if err != nil {
+		t.Fatalf("copied state not found: %v", err)
+	}
+	if balance := statedb.GetBalance(common.Address{0x01}); balance.Cmp(big.NewInt(1)) != 0 {
+		t.Fatalf("balance mismatch: have %v, want 1", balance)
+	}
+	if code := statedb.GetCode(common.Address{0x02}); !bytes.Equal(code, shadowForkCode) {
+		t.Fatalf("code mismatch: have %x", code)
+	}
+	for i := byte(0); i < 32; i++ {
+		if value := statedb.GetState(common.Address{0x02}, common.Hash{i}); value != (common.Hash{0xff, i}) {
+			t.Fatalf("slot %d mismatch: have %x", i, value)
+		}
+	}
+	if err := statedb.Error(); err != nil {
+		t.Fatalf("incomplete state copied: %v", err)
+	}
+}
+
+// Tests that the copy fails on an incomplete source state.
+func TestCopyShadowForkStateMissing(t *testing.T) {
+	srcdb := rawdb.NewMemoryDatabase()
+	triedb, root := newShadowForkState(t, srcdb)
+
+	if err := copyShadowForkState(srcdb, triedb, rawdb.NewMemoryDatabase(), common.Hash{0x01}); err == nil {
+		t.Fatal("unknown state root copied")
+	}
+	rawdb.DeleteCode(srcdb, crypto.Keccak256Hash(shadowForkCode))
+	if err := copyShadowForkState(srcdb, triedb, rawdb.NewMemoryDatabase(), root); err == nil {
+		t.Fatal("state copied without its contract code")
+	}
+}
+
+// TestShadowFork does a basic test of "geth shadowfork", forking the test-genesis.
+func TestShadowFork(t *testing.T) {
+	var (
+		datadir = initGeth(t)
+		target  = filepath.Join(t.TempDir(), "fork")
+	)
+	geth := runGeth(t, "--datadir", datadir, "shadowfork", "--chainid", "1337", target)
+	geth.WaitExit()
+	if have, want := geth.ExitStatus(), 0; have != want {
+		t.Fatalf("exit error, have %d want %d", have, want)
+	}
+	db, err := rawdb.Open(rawdb.OpenOptions{Directory: filepath.Join(target, "geth", "chaindata"), Cache: 16, Handles: 16, ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := rawdb.ReadChainConfig(db, rawdb.ReadCanonicalHash(db, 0))
+	db.Close()
+	if config == nil || config.ChainID.Uint64() != 1337 || !config.IsDevMode {
+		t.Fatalf("forked chain config mismatch: %v", config)
+	}
+	// Forking into an existing chain or from a missing block fails
+	for _, args := range [][]string{
+		{"shadowfork", target},
+		{"shadowfork", "--block", "1", filepath.Join(t.TempDir(), "fork")},
+	} {
+		geth := runGeth(t, append([]string{"--datadir", datadir}, args...)...)
+		geth.WaitExit()
+		if geth.ExitStatus() == 0 {
+			t.Errorf("%v: invalid shadow fork succeeded", args)
+		}
+	}
+}
@@ -20,9 +20,11 @@ package utils
 import (
 	"bufio"
 	"compress/gzip"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"os"
 	"os/signal"
 	"runtime"
@@ -30,6 +32,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -296,6 +299,85 @@ func ExportAppendChain(blockchain *core.BlockChain, fn string, first uint64, las
 	return nil
 }
 
+// exportedPayload is the record exported per block by ExportPayloads, holding the
+// arguments of the engine_newPayload call importing the block.
+type exportedPayload struct {
+	Envelope              *engine.ExecutionPayloadEnvelope `json:"envelope"`
+	VersionedHashes       []common.Hash                    `json:"expectedBlobVersionedHashes"`
+	ParentBeaconBlockRoot *common.Hash                     `json:"parentBeaconBlockRoot,omitempty"`
+}
+
+// ExportPayloads exports a range of the canonical chain into the specified file as
+// a stream of JSON encoded execution payload envelopes, one per line, along with
+// the other engine_newPayload arguments, so it can be replayed through the engine
+// API.
+func ExportPayloads(blockchain *core.BlockChain, fn string, first uint64, last uint64) error {
+	if first > last {
+		return fmt.Errorf("export failed: first (%d) is greater than last (%d)", first, last)
+	}
+	log.Info("Exporting execution payloads", "file", fn, "count", last-first+1)
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	var (
+		enc        = json.NewEncoder(writer)
+		parentHash common.Hash
+		start      = time.Now()
+		reported   = time.Now()
+	)
+	for nr := first; nr <= last; nr++ {
+		block := blockchain.GetBlockByNumber(nr)
+		if block == nil {
+			return fmt.Errorf("export failed on #%d: not found", nr)
+		}
+		if nr > first && block.ParentHash() != parentHash {
+			return errors.New("export failed: chain reorg during export")
+		}
+		parentHash = block.Hash()
+
+		// Sum the priority fees paid to the fee recipient as the block value
+		fees := new(big.Int)
+		receipts := blockchain.GetReceiptsByHash(block.Hash())
+		for i, tx := range block.Transactions() {
+			if i >= len(receipts) || block.BaseFee() == nil {
+				break
+			}
+			tip, err := tx.EffectiveGasTip(block.BaseFee())
+			if err != nil || tx.IsDepositTx() {
+				continue
+			}
+			fees.Add(fees, new(big.Int).Mul(tip, new(big.Int).SetUint64(receipts[i].GasUsed)))
+		}
+		record := &exportedPayload{
+			Envelope:              engine.BlockToExecutableData(block, fees, nil),
+			VersionedHashes:       make([]common.Hash, 0),
+			ParentBeaconBlockRoot: block.BeaconRoot(),
+		}
+		for _, tx := range block.Transactions() {
+			record.VersionedHashes = append(record.VersionedHashes, tx.BlobHashes()...)
+		}
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+		if time.Since(reported) >= 8*time.Second {
+			log.Info("Exporting execution payloads", "exported", nr-first+1, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Exported execution payloads", "file", fn)
+	return nil
+}
+
 // ImportPreimages imports a batch of exported hash preimages into the database.
 // It's a part of the deprecated functionality, should be removed in the future.
 func ImportPreimages(db ethdb.Database, fn string) error {
@@ -17,13 +17,26 @@
 package utils
 
 import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math/big"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -196,3 +209,93 @@ func TestImportFutureFormat(t *testing.T) {
 		t.Fatalf("wrong error: %v", err)
 	}
 }
+
+func TestExportPayloads(t *testing.T) {
+	testExportPayloads(t, filepath.Join(t.TempDir(), "payloads.jsonl"))
+}
+
+func TestExportPayloadsGzip(t *testing.T) {
+	testExportPayloads(t, filepath.Join(t.TempDir(), "payloads.jsonl.gz"))
+}
+
+func testExportPayloads(t *testing.T, f string) {
+	var (
+		key, _  = crypto.GenerateKey()
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &core.Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   core.GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		signer = types.LatestSigner(gspec.Config)
+		tip    = big.NewInt(params.GWei)
+	)
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 4, func(i int, b *core.BlockGen) {
+		tx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   gspec.Config.ChainID,
+			Nonce:     uint64(i),
+			To:        &common.Address{0xaa},
+			Gas:       params.TxGas,
+			GasTipCap: tip,
+			GasFeeCap: new(big.Int).Add(b.BaseFee(), tip),
+		})
+		b.AddTx(tx)
+	})
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+	// Invalid ranges are rejected
+	if err := ExportPayloads(chain, f, 3, 2); err == nil {
+		t.Fatal("reversed range exported")
+	}
+	if err := ExportPayloads(chain, f, 1, 5); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("range beyond the head exported: %v", err)
+	}
+	// Export a part of the chain and check the payloads
+	if err := ExportPayloads(chain, f, 2, 4); err != nil {
+		t.Fatal(err)
+	}
+	fh, err := os.Open(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(f, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var (
+		scanner = bufio.NewScanner(reader)
+		number  = uint64(2)
+	)
+	for ; scanner.Scan(); number++ {
+		var record exportedPayload
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("block %d: invalid record: %v", number, err)
+		}
+		block := blocks[number-1]
+		if payload := record.Envelope.ExecutionPayload; payload.Number != number || payload.BlockHash != block.Hash() {
+			t.Fatalf("block %d: payload mismatch: have #%d %x, want %x", number, payload.Number, payload.BlockHash, block.Hash())
+		}
+		if want := new(big.Int).Mul(tip, big.NewInt(int64(params.TxGas))); record.Envelope.BlockValue.Cmp(want) != 0 {
+			t.Fatalf("block %d: value mismatch: have %v, want %v", number, record.Envelope.BlockValue, want)
+		}
+		if record.VersionedHashes == nil || len(record.VersionedHashes) != 0 {
+			t.Fatalf("block %d: unexpected versioned hashes: %v", number, record.VersionedHashes)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if number != 5 {
+		t.Fatalf("exported payload count mismatch: have %d, want 3", number-2)
+	}
+}
@@ -57,6 +57,7 @@ import (
 	"github.com/ethereum/go-ethereum/graphql"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/internal/tracing"
 	"github.com/ethereum/go-ethereum/les"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
@@ -109,6 +110,11 @@ var (
 		Usage:    "Root directory for ancient data (default = inside chaindata)",
 		Category: flags.EthCategory,
 	}
+	StateDirFlag = &flags.DirectoryFlag{
+		Name:     "datadir.state",
+		Usage:    "Root directory for the state database, e.g. on a faster volume (default = inside chaindata)",
+		Category: flags.EthCategory,
+	}
 	MinFreeDiskSpaceFlag = &flags.DirectoryFlag{
 		Name:     "datadir.minfreedisk",
 		Usage:    "Minimum free disk space in MB, once reached triggers auto shut down (default = --cache.gc converted to MB, 0 = disabled)",
@@ -236,6 +242,11 @@ var (
 		Value:    true,
 		Category: flags.EthCategory,
 	}
+	SnapshotGenRateFlag = &cli.IntFlag{
+		Name:     "snapshot.genrate",
+		Usage:    "Megabytes of state indexed per second at most while (re)generating the snapshot (0 = unlimited)",
+		Category: flags.EthCategory,
+	}
 	LightKDFFlag = &cli.BoolFlag{
 		Name:     "lightkdf",
 		Usage:    "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
@@ -267,6 +278,11 @@ var (
 		Usage:    "Manually specify the Optimsim Canyon fork timestamp, overriding the bundled setting",
 		Category: flags.EthCategory,
 	}
+	OverrideOasysOperatorFee = &cli.Uint64Flag{
+		Name:     "override.oasysoperatorfee",
+		Usage:    "Manually specify the Oasys operator fee fork timestamp, overriding the bundled setting",
+		Category: flags.EthCategory,
+	}
 	OverrideOptimismInterop = &cli.Uint64Flag{
 		Name:     "override.interop",
 		Usage:    "Manually specify the Optimsim Interop feature-set fork timestamp, overriding the bundled setting",
@@ -301,6 +317,11 @@ var (
 		Value:    ethconfig.Defaults.TransactionHistory,
 		Category: flags.StateCategory,
 	}
+	TransactionSenderIndexFlag = &cli.BoolFlag{
+		Name:     "history.transactions.senders",
+		Usage:    "Also index the transactions by sender and nonce, serving eth_getTransactionBySenderAndNonce",
+		Category: flags.StateCategory,
+	}
 	// Light server and client settings
 	LightServeFlag = &cli.IntFlag{
 		Name:     "light.serve",
@@ -406,6 +427,23 @@ var (
 		Value:    ethconfig.Defaults.TxPool.Lifetime,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolPaymasterFlag = &cli.StringFlag{
+		Name:     "txpool.paymaster",
+		Usage:    "Address of the paymaster contract vouching for transactions their senders can't afford",
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolPaymasterGasFlag = &cli.Uint64Flag{
+		Name:     "txpool.paymastergas",
+		Usage:    "Gas budget of a paymaster call",
+		Value:    ethconfig.Defaults.TxPool.PaymasterGas,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolPaymasterTimeoutFlag = &cli.DurationFlag{
+		Name:     "txpool.paymastertimeout",
+		Usage:    "Time budget of a paymaster call",
+		Value:    ethconfig.Defaults.TxPool.PaymasterTimeout,
+		Category: flags.TxPoolCategory,
+	}
 	// Blob transaction pool settings
 	BlobPoolDataDirFlag = &cli.StringFlag{
 		Name:     "blobpool.datadir",
@@ -479,7 +517,7 @@ var (
 	}
 	CryptoKZGFlag = &cli.StringFlag{
 		Name:     "crypto.kzg",
-		Usage:    "KZG library implementation to use; gokzg (recommended) or ckzg",
+		Usage:    "KZG library implementation to use; gokzg (recommended) or ckzg, switchable at runtime with debug_setKzgBackend",
 		Value:    "gokzg",
 		Category: flags.PerfCategory,
 	}
@@ -570,12 +608,50 @@ var (
 		Value:    ethconfig.Defaults.RPCEVMTimeout,
 		Category: flags.APICategory,
 	}
+	RPCGlobalEVMMemoryFlag = &cli.Uint64Flag{
+		Name:     "rpc.evmmemory",
+		Usage:    "Sets a limit on the memory (in MB) used by eth_call across all call frames (0=no limit)",
+		Category: flags.APICategory,
+	}
+	RPCGlobalEVMReadsFlag = &cli.Uint64Flag{
+		Name:     "rpc.evmreads",
+		Usage:    "Sets a limit on the accounts, storage slots and codes eth_call may load from the database (0=no limit)",
+		Category: flags.APICategory,
+	}
 	RPCGlobalTxFeeCapFlag = &cli.Float64Flag{
 		Name:     "rpc.txfeecap",
 		Usage:    "Sets a cap on transaction fee (in ether) that can be sent via the RPC APIs (0 = no cap)",
 		Value:    ethconfig.Defaults.RPCTxFeeCap,
 		Category: flags.APICategory,
 	}
+	RPCLatestBlockTagFlag = &cli.StringFlag{
+		Name:     "rpc.latesttag",
+		Usage:    "Head the \"latest\" block tag resolves to for RPC consumers (unsafe, safe or finalized), subscriptions and filters excepted",
+		Value:    "unsafe",
+		Category: flags.APICategory,
+	}
+	RPCAPIKeysFlag = &cli.StringFlag{
+		Name:     "rpc.apikeys",
+		Usage:    "Path or http(s) URL of a JSON list of API keys and their policies, required on HTTP and WS requests if set",
+		Category: flags.APICategory,
+	}
+	RPCLatestBlockTagNamespacesFlag = &cli.StringFlag{
+		Name:     "rpc.latesttag.namespaces",
+		Usage:    "Comma separated list of per-namespace overrides of --rpc.latesttag (e.g. eth=safe,debug=unsafe)",
+		Category: flags.APICategory,
+	}
+	RPCCacheFlag = &cli.IntFlag{
+		Name:     "rpc.cache",
+		Usage:    "Megabytes of memory allocated to caching RPC responses on immutable data (0 = disabled)",
+		Value:    ethconfig.Defaults.RPCCacheSize,
+		Category: flags.APICategory,
+	}
+	RPCCacheTTLFlag = &cli.DurationFlag{
+		Name:     "rpc.cache.ttl",
+		Usage:    "Maximum time a cached RPC response is served",
+		Value:    ethconfig.Defaults.RPCCacheTTL,
+		Category: flags.APICategory,
+	}
 	// Authenticated RPC HTTP settings
 	AuthListenFlag = &cli.StringFlag{
 		Name:     "authrpc.addr",
@@ -595,9 +671,36 @@ var (
 		Value:    strings.Join(node.DefaultConfig.AuthVirtualHosts, ","),
 		Category: flags.APICategory,
 	}
-	JWTSecretFlag = &flags.DirectoryFlag{
+	AuthTLSCertFlag = &flags.DirectoryFlag{
+		Name:     "authrpc.tls.cert",
+		Usage:    "Path to the PEM certificate to serve the authenticated APIs over TLS with",
+		Category: flags.APICategory,
+	}
+	AuthTLSKeyFlag = &flags.DirectoryFlag{
+		Name:     "authrpc.tls.key",
+		Usage:    "Path to the PEM private key of the authenticated APIs TLS certificate",
+		Category: flags.APICategory,
+	}
+	AuthTLSClientCAFlag = &flags.DirectoryFlag{
+		Name:     "authrpc.tls.clientca",
+		Usage:    "Path to the PEM certificate authorities to require and verify client certificates of the authenticated APIs against",
+		Category: flags.APICategory,
+	}
+	JWTSecretFlag = &cli.StringFlag{
 		Name:     "authrpc.jwtsecret",
-		Usage:    "Path to a JWT secret to use for authenticated RPC endpoints",
+		Usage:    "Path to a JWT secret to use for authenticated RPC endpoints, or an external source (vault:<path>#<field>, exec:<command>)",
+		Category: flags.APICategory,
+	}
+	JWTSecretRefreshFlag = &cli.DurationFlag{
+		Name:     "authrpc.jwtsecret.refresh",
+		Usage:    "Interval at which the JWT secret is reloaded from its source to pick up rotations (0 = disabled)",
+		Value:    node.DefaultConfig.JWTSecretRefresh,
+		Category: flags.APICategory,
+	}
+	JWTSecretGraceFlag = &cli.DurationFlag{
+		Name:     "authrpc.jwtsecret.grace",
+		Usage:    "Duration the previous JWT secret is still accepted after a rotation",
+		Value:    node.DefaultConfig.JWTSecretGrace,
 		Category: flags.APICategory,
 	}
 
@@ -725,6 +828,26 @@ var (
 		Value:    "",
 		Category: flags.APICategory,
 	}
+	WSMaxConnsFlag = &cli.IntFlag{
+		Name:     "ws.maxconns",
+		Usage:    "Maximum number of WS-RPC connections (0 = unlimited)",
+		Category: flags.APICategory,
+	}
+	WSMaxConnsPerIPFlag = &cli.IntFlag{
+		Name:     "ws.maxconns.ip",
+		Usage:    "Maximum number of WS-RPC connections from a single IP address (0 = unlimited)",
+		Category: flags.APICategory,
+	}
+	WSIdleTimeoutFlag = &cli.DurationFlag{
+		Name:     "ws.idletimeout",
+		Usage:    "Time without any message after which a WS-RPC connection is closed (0 = disabled)",
+		Category: flags.APICategory,
+	}
+	WSMaxSubscriptionsFlag = &cli.IntFlag{
+		Name:     "ws.maxsubscriptions",
+		Usage:    "Maximum number of subscriptions per WS-RPC connection (0 = unlimited)",
+		Category: flags.APICategory,
+	}
 	ExecFlag = &cli.StringFlag{
 		Name:     "exec",
 		Usage:    "Execute JavaScript statement",
@@ -752,6 +875,23 @@ var (
 		Value:    node.DefaultConfig.BatchResponseMaxSize,
 		Category: flags.APICategory,
 	}
+	RequestMaxSize = &cli.IntFlag{
+		Name:     "rpc.request-max-size",
+		Usage:    "Maximum number of bytes of a request, or of a WebSocket message",
+		Value:    node.DefaultConfig.RequestMaxSize,
+		Category: flags.APICategory,
+	}
+	InflightRequestLimit = &cli.IntFlag{
+		Name:     "rpc.inflight-request-limit",
+		Usage:    "Maximum number of requests processed concurrently per WebSocket or IPC connection (0 = unlimited)",
+		Value:    node.DefaultConfig.InflightRequestLimit,
+		Category: flags.APICategory,
+	}
+	ExecutionPoolsFlag = &cli.StringFlag{
+		Name:     "rpc.execpools",
+		Usage:    "Worker pools executing the calls per namespace, with their number of workers and queued calls (format: ns+ns=workers:queue,..., e.g. debug+trace=8:32,engine=4:64,eth=64:1024, * matching the other namespaces)",
+		Category: flags.APICategory,
+	}
 	EnablePersonal = &cli.BoolFlag{
 		Name:     "rpc.enabledeprecatedpersonal",
 		Usage:    "Enables the (deprecated) personal namespace",
@@ -905,21 +1045,56 @@ var (
 		Usage:    "Disable transaction pool gossip.",
 		Category: flags.RollupCategory,
 	}
+	RollupBlockGossipFlag = &cli.BoolFlag{
+		Name:     "rollup.blockgossip",
+		Usage:    "Gossip locally built payloads to the eth peers on getPayload, and pre-import the ones gossiped by trusted peers",
+		Category: flags.RollupCategory,
+	}
 	RollupEnableTxPoolAdmissionFlag = &cli.BoolFlag{
 		Name:     "rollup.enabletxpooladmission",
 		Usage:    "Add RPC-submitted transactions to the txpool (on by default if --rollup.sequencerhttp is not set).",
 		Category: flags.RollupCategory,
 	}
+	RollupForwardPrecheckFlag = &cli.BoolFlag{
+		Name:     "rollup.forwardprecheck",
+		Usage:    "Execute transactions against the pending state before forwarding them to the sequencer, returning the errors of invalid transactions immediately",
+		Category: flags.RollupCategory,
+	}
 	RollupComputePendingBlock = &cli.BoolFlag{
 		Name:     "rollup.computependingblock",
 		Usage:    "By default the pending block equals the latest block to save resources and not leak txs from the tx-pool, this flag enables computing of the pending block from the tx-pool instead.",
 		Category: flags.RollupCategory,
 	}
+	RollupPendingViewFlag = &cli.BoolFlag{
+		Name:     "rollup.pendingview",
+		Usage:    "Serve the computed pending block with sequence numbers and the transactions added since a sequence number (oasys_pendingBlock, oasys_pendingTransactionsSince), requires --rollup.computependingblock",
+		Category: flags.RollupCategory,
+	}
+	RollupTxTimeBudgetFlag = &cli.DurationFlag{
+		Name:     "rollup.txtimebudget",
+		Usage:    "Maximum execution time of a tx-pool transaction when building blocks, skipping the transactions exceeding it (0 = unlimited)",
+		Category: flags.RollupCategory,
+	}
+	RollupTxTimeBudgetEvictFlag = &cli.BoolFlag{
+		Name:     "rollup.txtimebudget.evict",
+		Usage:    "Evict the transactions exceeding the execution time budget from the tx-pool",
+		Category: flags.RollupCategory,
+	}
+	RollupOrderingAuditFlag = &cli.BoolFlag{
+		Name:     "rollup.orderingaudit",
+		Usage:    "Record the arrival time and ordering rationale of the transactions of each built block, retrievable through oasys_getOrderingAudit",
+		Category: flags.RollupCategory,
+	}
 	RollupHaltOnIncompatibleProtocolVersionFlag = &cli.StringFlag{
 		Name:     "rollup.halt",
 		Usage:    "Opt-in option to halt on incompatible protocol version requirements of the given level (major/minor/patch/none), as signaled through the Engine API by the rollup node",
 		Category: flags.RollupCategory,
 	}
+	RollupHaltDelayFlag = &cli.DurationFlag{
+		Name:     "rollup.halt.delay",
+		Usage:    "Delay before halting on incompatible protocol version requirements, leaving a maintenance window (0 = halt immediately)",
+		Category: flags.RollupCategory,
+	}
 	RollupSuperchainUpgradesFlag = &cli.BoolFlag{
 		Name:     "rollup.superchain-upgrades",
 		Aliases:  []string{"beta.rollup.superchain-upgrades"},
@@ -927,6 +1102,198 @@ var (
 		Category: flags.RollupCategory,
 		Value:    true,
 	}
+	RollupFeeCheckFlag = &cli.BoolFlag{
+		Name:     "rollup.feecheck",
+		Usage:    "Check every new head's gas limit and L1 fee parameters against the ones set on L1 and report divergences (requires --rollup.feecheck.l1 and --rollup.feecheck.systemconfig)",
+		Category: flags.RollupCategory,
+	}
+	RollupFeeCheckL1Flag = &cli.StringFlag{
+		Name:     "rollup.feecheck.l1",
+		Usage:    "L1 RPC endpoint the expected fee parameters are read from",
+		Category: flags.RollupCategory,
+	}
+	RollupFeeCheckSystemConfigFlag = &cli.StringFlag{
+		Name:     "rollup.feecheck.systemconfig",
+		Usage:    "Address of the SystemConfig contract on L1 holding the expected fee parameters",
+		Category: flags.RollupCategory,
+	}
+	RollupFeeCheckHaltFlag = &cli.BoolFlag{
+		Name:     "rollup.feecheck.halt",
+		Usage:    "Halt the node when the fee parameter checker detects a divergence (requires --rollup.feecheck)",
+		Category: flags.RollupCategory,
+	}
+	RollupDepositCheckFlag = &cli.BoolFlag{
+		Name:     "rollup.depositcheck",
+		Usage:    "Check the ordering, system transaction usage and nonces of deposits in imported blocks, rejecting violating blocks with a descriptive reason",
+		Category: flags.RollupCategory,
+	}
+	RollupTxWALFlag = &cli.StringFlag{
+		Name:     "rollup.txwal",
+		Usage:    "Write-ahead log recording the transactions accepted by the sequencer before pool insertion, replayed on restart (relative to the datadir, disabled if empty)",
+		Category: flags.RollupCategory,
+	}
+	RollupHealthMaxHeadAgeFlag = &cli.DurationFlag{
+		Name:     "rollup.health.maxheadage",
+		Usage:    "Maximum age of the unsafe head before the node reports itself unhealthy on /healthz (0 = disabled)",
+		Value:    ethconfig.Defaults.RollupHealthMaxHeadAge,
+		Category: flags.RollupCategory,
+	}
+	RollupHealthMaxEngineAgeFlag = &cli.DurationFlag{
+		Name:     "rollup.health.maxengineage",
+		Usage:    "Maximum time since the last Engine API update before the node reports itself unhealthy on /healthz (0 = disabled)",
+		Value:    ethconfig.Defaults.RollupHealthMaxEngineAge,
+		Category: flags.RollupCategory,
+	}
+	RollupRuntimeConfigFlag = &cli.StringFlag{
+		Name:     "rollup.runtimeconfig",
+		Usage:    "JSON file with runtime adjustable settings (gas ceiling, gas price, sequencer and historical endpoints), reloaded on change",
+		Category: flags.RollupCategory,
+	}
+	RollupDrainTimeoutFlag = &cli.DurationFlag{
+		Name:     "rollup.drain.timeout",
+		Usage:    "Maximum time to wait on shutdown for the in-flight payload to be delivered and inserted (0 = no draining)",
+		Value:    ethconfig.Defaults.RollupDrainTimeout,
+		Category: flags.RollupCategory,
+	}
+	RollupDrainNotifyFlag = &cli.StringFlag{
+		Name:     "rollup.drain.notify",
+		Usage:    "Comma separated HTTP endpoints of replicas notified when the node drains for shutdown",
+		Category: flags.RollupCategory,
+	}
+	RollupFreezeMarkerFlag = &cli.StringFlag{
+		Name:     "rollup.freeze.marker",
+		Usage:    "File whose presence freezes block production and payload import (emergency stop), lifted when removed",
+		Category: flags.RollupCategory,
+	}
+	RollupReplicaCheckFlag = &cli.BoolFlag{
+		Name:     "rollup.replicacheck",
+		Usage:    "Periodically compare the head, safe and finalized blocks with the sequencer and alert on divergence",
+		Category: flags.RollupCategory,
+	}
+	RollupReplicaCheckIntervalFlag = &cli.DurationFlag{
+		Name:     "rollup.replicacheck.interval",
+		Usage:    "Interval between replica consistency checks",
+		Value:    ethconfig.Defaults.RollupReplicaCheckInterval,
+		Category: flags.RollupCategory,
+	}
+	RollupReplicaModeFlag = &cli.BoolFlag{
+		Name:     "rollup.replicamode",
+		Usage:    "Run as a failover replica of the sequencer, refusing to build blocks from the transaction pool until promoted through engine_promoteReplicaV1",
+		Category: flags.RollupCategory,
+	}
+	RollupInclusionMonitorFlag = &cli.Uint64Flag{
+		Name:     "rollup.inclusionmonitor",
+		Usage:    "Sample one in N submitted transactions to measure their time to inclusion and drop rate (0 = disabled)",
+		Category: flags.RollupCategory,
+	}
+	RollupNonceGapThresholdFlag = &cli.DurationFlag{
+		Name:     "rollup.noncegap.threshold",
+		Usage:    "Report the accounts whose queued transactions are blocked by a nonce gap for longer than this (0 = disabled)",
+		Category: flags.RollupCategory,
+	}
+	RollupNonceGapEvictFlag = &cli.BoolFlag{
+		Name:     "rollup.noncegap.evict",
+		Usage:    "Evict the queued transactions blocked by a nonce gap for longer than the threshold",
+		Category: flags.RollupCategory,
+	}
+	RollupForkRehearsalWindowFlag = &cli.DurationFlag{
+		Name:     "rollup.forkrehearsal",
+		Usage:    "Rehearse the block production under the rules of the forks activating within this duration, reporting the failures (0 = disabled)",
+		Category: flags.RollupCategory,
+	}
+	RollupForkRehearsalIntervalFlag = &cli.DurationFlag{
+		Name:     "rollup.forkrehearsal.interval",
+		Usage:    "Interval between fork rehearsals",
+		Value:    ethconfig.Defaults.RollupForkRehearsalInterval,
+		Category: flags.RollupCategory,
+	}
+	RollupGasGovernorTargetFlag = &cli.DurationFlag{
+		Name:     "rollup.gasgovernor.target",
+		Usage:    "Adjust the miner gas ceiling to keep the 95th percentile of the block processing time below this (0 = disabled)",
+		Category: flags.RollupCategory,
+	}
+	RollupGasGovernorMinFlag = &cli.Uint64Flag{
+		Name:     "rollup.gasgovernor.min",
+		Usage:    "Lowest gas ceiling set by the gas governor (default = a quarter of the maximum)",
+		Category: flags.RollupCategory,
+	}
+	RollupGasGovernorMaxFlag = &cli.Uint64Flag{
+		Name:     "rollup.gasgovernor.max",
+		Usage:    "Highest gas ceiling set by the gas governor (default = miner.gaslimit)",
+		Category: flags.RollupCategory,
+	}
+	RollupFeeRecipientsFlag = &cli.StringFlag{
+		Name:     "rollup.feerecipients",
+		Usage:    "Comma separated fee recipients allowed in the payload attributes, the others being rejected (default = any)",
+		Category: flags.RollupCategory,
+	}
+	RollupFeeRecipientRotationFlag = &cli.Uint64Flag{
+		Name:     "rollup.feerecipients.rotation",
+		Usage:    "Number of blocks each allowed fee recipient is used for in turn, only the scheduled one being accepted (0 = any allowed)",
+		Category: flags.RollupCategory,
+	}
+	RollupGasProfileFlag = &cli.Uint64Flag{
+		Name:     "rollup.gasprofile",
+		Usage:    "Re-execute one in N imported blocks to profile the gas used per contract, served by debug_gasProfile (0 = disabled)",
+		Category: flags.RollupCategory,
+	}
+	RollupWarmCacheFlag = &cli.BoolFlag{
+		Name:     "rollup.warmcache",
+		Usage:    "Share the state read while building payloads with the import of the built blocks, served by debug_warmCacheStats",
+		Category: flags.RollupCategory,
+	}
+	RollupDAServerFlag = &cli.StringFlag{
+		Name:     "rollup.daserver",
+		Usage:    "HTTP endpoint of the alt-DA server serving the inputs committed to by the transactions sent to --rollup.datargets",
+		Category: flags.RollupCategory,
+	}
+	RollupDATargetsFlag = &cli.StringFlag{
+		Name:     "rollup.datargets",
+		Usage:    "Comma separated recipients whose transactions carry alt-DA commitments, included only once their input is available (default = none)",
+		Category: flags.RollupCategory,
+	}
+	RollupDATimeoutFlag = &cli.DurationFlag{
+		Name:     "rollup.datimeout",
+		Usage:    "Timeout of the requests to the alt-DA server",
+		Value:    ethconfig.Defaults.RollupDATimeout,
+		Category: flags.RollupCategory,
+	}
+	RollupDAResolveFlag = &cli.BoolFlag{
+		Name:     "rollup.daresolve",
+		Usage:    "Resolve the alt-DA inputs committed to by the transactions of the payloads to import, served by debug_altDAInput",
+		Category: flags.RollupCategory,
+	}
+	RollupDAFailurePolicyFlag = &cli.StringFlag{
+		Name:     "rollup.dafailurepolicy",
+		Usage:    "Handling of the payloads whose alt-DA inputs cannot be resolved: defer (report syncing), ignore or reject",
+		Value:    ethconfig.Defaults.RollupDAFailurePolicy,
+		Category: flags.RollupCategory,
+	}
+	RollupBlockUsageFlag = &cli.Uint64Flag{
+		Name:     "rollup.blockusage",
+		Usage:    "Number of recent canonical blocks whose import resource usage is tracked, served by oasys_blockUsage (0 = disabled)",
+		Category: flags.RollupCategory,
+	}
+	RollupIndexersFlag = &cli.StringFlag{
+		Name:     "rollup.indexers",
+		Usage:    "Comma separated list of the compiled-in custom chain indexers to run",
+		Category: flags.RollupCategory,
+	}
+	RollupAncientCheckIntervalFlag = &cli.DurationFlag{
+		Name:     "rollup.ancientcheck",
+		Usage:    "Interval between the background verification rounds of the ancient chain segments, repairing the corrupted ones from peers (0 = disabled)",
+		Category: flags.RollupCategory,
+	}
+	RollupAncientCheckEndpointFlag = &cli.StringFlag{
+		Name:     "rollup.ancientcheck.endpoint",
+		Usage:    "RPC endpoint of an archive node the corrupted ancient chain segments are also repaired from",
+		Category: flags.RollupCategory,
+	}
+	RollupMinConsensusVersionsFlag = &cli.StringFlag{
+		Name:     "rollup.minclversion",
+		Usage:    "Minimum consensus client versions per active fork, warned about when not met (format: fork=version,..., e.g. canyon=v1.4.2)",
+		Category: flags.RollupCategory,
+	}
 
 	// Metrics flags
 	MetricsEnabledFlag = &cli.BoolFlag{
@@ -1022,6 +1389,19 @@ Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.
 		Value:    metrics.DefaultConfig.InfluxDBOrganization,
 		Category: flags.MetricsCategory,
 	}
+
+	// Tracing flags
+	TracingEndpointFlag = &cli.StringFlag{
+		Name:     "tracing.endpoint",
+		Usage:    "OpenTelemetry collector OTLP/HTTP endpoint to export Engine API, payload building and txpool spans to (e.g. http://localhost:4318)",
+		Category: flags.MetricsCategory,
+	}
+	TracingServiceFlag = &cli.StringFlag{
+		Name:     "tracing.service",
+		Usage:    "Service name reported with exported tracing spans",
+		Value:    "op-geth",
+		Category: flags.MetricsCategory,
+	}
 )
 
 var (
@@ -1038,6 +1418,7 @@ var (
 	DatabaseFlags = []cli.Flag{
 		DataDirFlag,
 		AncientFlag,
+		StateDirFlag,
 		RemoteDBFlag,
 		DBEngineFlag,
 		StateSchemeFlag,
@@ -1228,6 +1609,16 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 		cfg.AuthVirtualHosts = SplitAndTrim(ctx.String(AuthVirtualHostsFlag.Name))
 	}
 
+	if ctx.IsSet(AuthTLSCertFlag.Name) {
+		cfg.AuthTLSCert = ctx.String(AuthTLSCertFlag.Name)
+	}
+	if ctx.IsSet(AuthTLSKeyFlag.Name) {
+		cfg.AuthTLSKey = ctx.String(AuthTLSKeyFlag.Name)
+	}
+	if ctx.IsSet(AuthTLSClientCAFlag.Name) {
+		cfg.AuthTLSClientCA = ctx.String(AuthTLSClientCAFlag.Name)
+	}
+
 	if ctx.IsSet(HTTPCORSDomainFlag.Name) {
 		cfg.HTTPCors = SplitAndTrim(ctx.String(HTTPCORSDomainFlag.Name))
 	}
@@ -1254,6 +1645,51 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(BatchResponseMaxSize.Name) {
 		cfg.BatchResponseMaxSize = ctx.Int(BatchResponseMaxSize.Name)
 	}
+
+	if ctx.IsSet(RequestMaxSize.Name) {
+		cfg.RequestMaxSize = ctx.Int(RequestMaxSize.Name)
+	}
+
+	if ctx.IsSet(InflightRequestLimit.Name) {
+		cfg.InflightRequestLimit = ctx.Int(InflightRequestLimit.Name)
+	}
+
+	if ctx.IsSet(ExecutionPoolsFlag.Name) {
+		pools, err := parseExecutionPools(ctx.String(ExecutionPoolsFlag.Name))
+		if err != nil {
+			Fatalf("Invalid --%s: %v", ExecutionPoolsFlag.Name, err)
+		}
+		cfg.ExecutionPools = pools
+	}
+}
+
+// parseExecutionPools parses the rpc worker pools in the ns+ns=workers:queue,...
+// format, each pool being named after its namespaces.
+func parseExecutionPools(spec string) ([]rpc.ExecutionPoolConfig, error) {
+	var pools []rpc.ExecutionPoolConfig
+	for _, entry := range SplitAndTrim(spec) {
+		namespaces, limits, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("missing limits in %q", entry)
+		}
+		workers, queue, ok := strings.Cut(limits, ":")
+		if !ok {
+			return nil, fmt.Errorf("missing queue size in %q", entry)
+		}
+		pool := rpc.ExecutionPoolConfig{
+			Name:       strings.ReplaceAll(namespaces, "*", "default"),
+			Namespaces: strings.Split(namespaces, "+"),
+		}
+		var err error
+		if pool.Workers, err = strconv.Atoi(workers); err != nil {
+			return nil, fmt.Errorf("invalid workers in %q: %v", entry, err)
+		}
+		if pool.QueueSize, err = strconv.Atoi(queue); err != nil {
+			return nil, fmt.Errorf("invalid queue size in %q: %v", entry, err)
+		}
+		pools = append(pools, pool)
+	}
+	return pools, nil
 }
 
 // setGraphQL creates the GraphQL listener interface string from the set
@@ -1291,6 +1727,19 @@ func setWS(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(WSPathPrefixFlag.Name) {
 		cfg.WSPathPrefix = ctx.String(WSPathPrefixFlag.Name)
 	}
+
+	if ctx.IsSet(WSMaxConnsFlag.Name) {
+		cfg.WSLimits.MaxConns = ctx.Int(WSMaxConnsFlag.Name)
+	}
+	if ctx.IsSet(WSMaxConnsPerIPFlag.Name) {
+		cfg.WSLimits.MaxConnsPerIP = ctx.Int(WSMaxConnsPerIPFlag.Name)
+	}
+	if ctx.IsSet(WSIdleTimeoutFlag.Name) {
+		cfg.WSLimits.IdleTimeout = ctx.Duration(WSIdleTimeoutFlag.Name)
+	}
+	if ctx.IsSet(WSMaxSubscriptionsFlag.Name) {
+		cfg.WSLimits.MaxSubscriptions = ctx.Int(WSMaxSubscriptionsFlag.Name)
+	}
 }
 
 // setIPC creates an IPC path configuration from the set command line flags,
@@ -1504,6 +1953,18 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 
 	if ctx.IsSet(JWTSecretFlag.Name) {
 		cfg.JWTSecret = ctx.String(JWTSecretFlag.Name)
+		if !node.IsExternalJWTSource(cfg.JWTSecret) {
+			cfg.JWTSecret = flags.ExpandPath(cfg.JWTSecret)
+		}
+	}
+	if ctx.IsSet(JWTSecretRefreshFlag.Name) {
+		cfg.JWTSecretRefresh = ctx.Duration(JWTSecretRefreshFlag.Name)
+	}
+	if ctx.IsSet(JWTSecretGraceFlag.Name) {
+		cfg.JWTSecretGrace = ctx.Duration(JWTSecretGraceFlag.Name)
+	}
+	if ctx.IsSet(RPCAPIKeysFlag.Name) {
+		cfg.APIKeys = ctx.String(RPCAPIKeysFlag.Name)
 	}
 
 	if ctx.IsSet(EnablePersonal.Name) {
@@ -1532,6 +1993,9 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(InsecureUnlockAllowedFlag.Name) {
 		cfg.InsecureUnlockAllowed = ctx.Bool(InsecureUnlockAllowedFlag.Name)
 	}
+	if ctx.IsSet(StateDirFlag.Name) {
+		cfg.StateDir = ctx.String(StateDirFlag.Name)
+	}
 	if ctx.IsSet(DBEngineFlag.Name) {
 		dbEngine := ctx.String(DBEngineFlag.Name)
 		if dbEngine != "leveldb" && dbEngine != "pebble" {
@@ -1646,6 +2110,19 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.Duration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.IsSet(TxPoolPaymasterFlag.Name) {
+		addr := ctx.String(TxPoolPaymasterFlag.Name)
+		if !common.IsHexAddress(addr) {
+			Fatalf("Invalid address in --txpool.paymaster: %s", addr)
+		}
+		cfg.Paymaster = common.HexToAddress(addr)
+	}
+	if ctx.IsSet(TxPoolPaymasterGasFlag.Name) {
+		cfg.PaymasterGas = ctx.Uint64(TxPoolPaymasterGasFlag.Name)
+	}
+	if ctx.IsSet(TxPoolPaymasterTimeoutFlag.Name) {
+		cfg.PaymasterTimeout = ctx.Duration(TxPoolPaymasterTimeoutFlag.Name)
+	}
 }
 
 func setMiner(ctx *cli.Context, cfg *miner.Config) {
@@ -1667,6 +2144,15 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.IsSet(RollupComputePendingBlock.Name) {
 		cfg.RollupComputePendingBlock = ctx.Bool(RollupComputePendingBlock.Name)
 	}
+	if ctx.IsSet(RollupTxTimeBudgetFlag.Name) {
+		cfg.RollupTxTimeBudget = ctx.Duration(RollupTxTimeBudgetFlag.Name)
+	}
+	if ctx.IsSet(RollupTxTimeBudgetEvictFlag.Name) {
+		cfg.RollupTxTimeBudgetEvict = ctx.Bool(RollupTxTimeBudgetEvictFlag.Name)
+	}
+	if ctx.IsSet(RollupOrderingAuditFlag.Name) {
+		cfg.RollupOrderingAudit = ctx.Bool(RollupOrderingAuditFlag.Name)
+	}
 }
 
 func setRequiredBlocks(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1822,6 +2308,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 		log.Warn("The flag --txlookuplimit is deprecated and will be removed, please use --history.transactions")
 		cfg.TransactionHistory = ctx.Uint64(TxLookupLimitFlag.Name)
 	}
+	if ctx.IsSet(TransactionSenderIndexFlag.Name) {
+		cfg.TransactionSenderIndex = ctx.Bool(TransactionSenderIndexFlag.Name)
+	}
 	if ctx.String(GCModeFlag.Name) == "archive" && cfg.TransactionHistory != 0 {
 		cfg.TransactionHistory = 0
 		log.Warn("Disabled transaction unindexing for archive node")
@@ -1850,6 +2339,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 			cfg.SnapshotCache = 0 // Disabled
 		}
 	}
+	if ctx.IsSet(SnapshotGenRateFlag.Name) {
+		cfg.SnapshotGenRate = ctx.Int(SnapshotGenRateFlag.Name)
+	}
 	if ctx.IsSet(DocRootFlag.Name) {
 		cfg.DocRoot = ctx.String(DocRootFlag.Name)
 	}
@@ -1869,9 +2361,34 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(RPCGlobalEVMTimeoutFlag.Name) {
 		cfg.RPCEVMTimeout = ctx.Duration(RPCGlobalEVMTimeoutFlag.Name)
 	}
+	if ctx.IsSet(RPCGlobalEVMMemoryFlag.Name) {
+		cfg.RPCEVMMemoryLimit = ctx.Uint64(RPCGlobalEVMMemoryFlag.Name) * 1024 * 1024
+	}
+	if ctx.IsSet(RPCGlobalEVMReadsFlag.Name) {
+		cfg.RPCEVMReadLimit = ctx.Uint64(RPCGlobalEVMReadsFlag.Name)
+	}
 	if ctx.IsSet(RPCGlobalTxFeeCapFlag.Name) {
 		cfg.RPCTxFeeCap = ctx.Float64(RPCGlobalTxFeeCapFlag.Name)
 	}
+	if ctx.IsSet(RPCLatestBlockTagFlag.Name) {
+		cfg.RPCLatestBlockTag = ctx.String(RPCLatestBlockTagFlag.Name)
+	}
+	if ctx.IsSet(RPCLatestBlockTagNamespacesFlag.Name) {
+		cfg.RPCLatestBlockTagNamespaces = make(map[string]string)
+		for _, entry := range SplitAndTrim(ctx.String(RPCLatestBlockTagNamespacesFlag.Name)) {
+			namespace, tag, ok := strings.Cut(entry, "=")
+			if !ok {
+				Fatalf("Invalid --%s entry %q, want namespace=tag", RPCLatestBlockTagNamespacesFlag.Name, entry)
+			}
+			cfg.RPCLatestBlockTagNamespaces[strings.TrimSpace(namespace)] = strings.TrimSpace(tag)
+		}
+	}
+	if ctx.IsSet(RPCCacheFlag.Name) {
+		cfg.RPCCacheSize = ctx.Int(RPCCacheFlag.Name)
+	}
+	if ctx.IsSet(RPCCacheTTLFlag.Name) {
+		cfg.RPCCacheTTL = ctx.Duration(RPCCacheTTLFlag.Name)
+	}
 	if ctx.IsSet(NoDiscoverFlag.Name) {
 		cfg.EthDiscoveryURLs, cfg.SnapDiscoveryURLs = []string{}, []string{}
 	} else if ctx.IsSet(DNSDiscoveryFlag.Name) {
@@ -1893,9 +2410,111 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 		cfg.RollupHistoricalRPCTimeout = ctx.Duration(RollupHistoricalRPCTimeoutFlag.Name)
 	}
 	cfg.RollupDisableTxPoolGossip = ctx.Bool(RollupDisableTxPoolGossipFlag.Name)
+	cfg.RollupBlockGossip = ctx.Bool(RollupBlockGossipFlag.Name)
+	cfg.RollupPendingView = ctx.Bool(RollupPendingViewFlag.Name)
 	cfg.RollupDisableTxPoolAdmission = cfg.RollupSequencerHTTP != "" && !ctx.Bool(RollupEnableTxPoolAdmissionFlag.Name)
+	cfg.RollupForwardPrecheck = ctx.Bool(RollupForwardPrecheckFlag.Name)
 	cfg.RollupHaltOnIncompatibleProtocolVersion = ctx.String(RollupHaltOnIncompatibleProtocolVersionFlag.Name)
+	cfg.RollupHaltDelay = ctx.Duration(RollupHaltDelayFlag.Name)
 	cfg.ApplySuperchainUpgrades = ctx.Bool(RollupSuperchainUpgradesFlag.Name)
+	cfg.RollupFeeCheck = ctx.Bool(RollupFeeCheckFlag.Name)
+	cfg.RollupFeeCheckHalt = ctx.Bool(RollupFeeCheckHaltFlag.Name)
+	cfg.RollupFeeCheckL1 = ctx.String(RollupFeeCheckL1Flag.Name)
+	if ctx.IsSet(RollupFeeCheckSystemConfigFlag.Name) {
+		addr := ctx.String(RollupFeeCheckSystemConfigFlag.Name)
+		if !common.IsHexAddress(addr) {
+			Fatalf("Invalid address in --%s: %s", RollupFeeCheckSystemConfigFlag.Name, addr)
+		}
+		cfg.RollupFeeCheckSystemConfig = common.HexToAddress(addr)
+	}
+	cfg.RollupDepositCheck = ctx.Bool(RollupDepositCheckFlag.Name)
+	cfg.RollupTxWAL = ctx.String(RollupTxWALFlag.Name)
+	if ctx.IsSet(RollupHealthMaxHeadAgeFlag.Name) {
+		cfg.RollupHealthMaxHeadAge = ctx.Duration(RollupHealthMaxHeadAgeFlag.Name)
+	}
+	if ctx.IsSet(RollupHealthMaxEngineAgeFlag.Name) {
+		cfg.RollupHealthMaxEngineAge = ctx.Duration(RollupHealthMaxEngineAgeFlag.Name)
+	}
+	if ctx.IsSet(RollupRuntimeConfigFlag.Name) {
+		cfg.RollupRuntimeConfig = ctx.String(RollupRuntimeConfigFlag.Name)
+	}
+	if ctx.IsSet(RollupDrainTimeoutFlag.Name) {
+		cfg.RollupDrainTimeout = ctx.Duration(RollupDrainTimeoutFlag.Name)
+	}
+	if ctx.IsSet(RollupDrainNotifyFlag.Name) {
+		cfg.RollupDrainNotify = SplitAndTrim(ctx.String(RollupDrainNotifyFlag.Name))
+	}
+	if ctx.IsSet(RollupFreezeMarkerFlag.Name) {
+		cfg.RollupFreezeMarker = ctx.String(RollupFreezeMarkerFlag.Name)
+	}
+	cfg.RollupReplicaCheck = ctx.Bool(RollupReplicaCheckFlag.Name)
+	if ctx.IsSet(RollupReplicaCheckIntervalFlag.Name) {
+		cfg.RollupReplicaCheckInterval = ctx.Duration(RollupReplicaCheckIntervalFlag.Name)
+	}
+	cfg.RollupReplicaMode = ctx.Bool(RollupReplicaModeFlag.Name)
+	cfg.RollupInclusionMonitor = ctx.Uint64(RollupInclusionMonitorFlag.Name)
+	if ctx.IsSet(RollupNonceGapThresholdFlag.Name) {
+		cfg.RollupNonceGapThreshold = ctx.Duration(RollupNonceGapThresholdFlag.Name)
+	}
+	cfg.RollupNonceGapEvict = ctx.Bool(RollupNonceGapEvictFlag.Name)
+	if ctx.IsSet(RollupForkRehearsalWindowFlag.Name) {
+		cfg.RollupForkRehearsalWindow = ctx.Duration(RollupForkRehearsalWindowFlag.Name)
+	}
+	if ctx.IsSet(RollupForkRehearsalIntervalFlag.Name) {
+		cfg.RollupForkRehearsalInterval = ctx.Duration(RollupForkRehearsalIntervalFlag.Name)
+	}
+	if ctx.IsSet(RollupGasGovernorTargetFlag.Name) {
+		cfg.RollupGasGovernorTarget = ctx.Duration(RollupGasGovernorTargetFlag.Name)
+	}
+	cfg.RollupGasGovernorMin = ctx.Uint64(RollupGasGovernorMinFlag.Name)
+	cfg.RollupGasGovernorMax = ctx.Uint64(RollupGasGovernorMaxFlag.Name)
+	if ctx.IsSet(RollupFeeRecipientsFlag.Name) {
+		for _, addr := range SplitAndTrim(ctx.String(RollupFeeRecipientsFlag.Name)) {
+			if !common.IsHexAddress(addr) {
+				Fatalf("Invalid address in --%s: %s", RollupFeeRecipientsFlag.Name, addr)
+			}
+			cfg.RollupFeeRecipients = append(cfg.RollupFeeRecipients, common.HexToAddress(addr))
+		}
+	}
+	cfg.RollupFeeRecipientRotation = ctx.Uint64(RollupFeeRecipientRotationFlag.Name)
+	cfg.RollupGasProfileRate = ctx.Uint64(RollupGasProfileFlag.Name)
+	cfg.RollupWarmCache = ctx.Bool(RollupWarmCacheFlag.Name)
+	cfg.RollupDAServer = ctx.String(RollupDAServerFlag.Name)
+	if ctx.IsSet(RollupDATargetsFlag.Name) {
+		for _, addr := range SplitAndTrim(ctx.String(RollupDATargetsFlag.Name)) {
+			if !common.IsHexAddress(addr) {
+				Fatalf("Invalid address in --%s: %s", RollupDATargetsFlag.Name, addr)
+			}
+			cfg.RollupDATargets = append(cfg.RollupDATargets, common.HexToAddress(addr))
+		}
+	}
+	if ctx.IsSet(RollupDATimeoutFlag.Name) {
+		cfg.RollupDATimeout = ctx.Duration(RollupDATimeoutFlag.Name)
+	}
+	cfg.RollupDAResolve = ctx.Bool(RollupDAResolveFlag.Name)
+	if ctx.IsSet(RollupDAFailurePolicyFlag.Name) {
+		cfg.RollupDAFailurePolicy = ctx.String(RollupDAFailurePolicyFlag.Name)
+	}
+	cfg.RollupBlockUsageWindow = ctx.Uint64(RollupBlockUsageFlag.Name)
+	if ctx.IsSet(RollupIndexersFlag.Name) {
+		cfg.RollupIndexers = SplitAndTrim(ctx.String(RollupIndexersFlag.Name))
+	}
+	if ctx.IsSet(RollupAncientCheckIntervalFlag.Name) {
+		cfg.RollupAncientCheckInterval = ctx.Duration(RollupAncientCheckIntervalFlag.Name)
+	}
+	if ctx.IsSet(RollupAncientCheckEndpointFlag.Name) {
+		cfg.RollupAncientCheckEndpoint = ctx.String(RollupAncientCheckEndpointFlag.Name)
+	}
+	if ctx.IsSet(RollupMinConsensusVersionsFlag.Name) {
+		cfg.RollupMinConsensusVersions = make(map[string]string)
+		for _, entry := range SplitAndTrim(ctx.String(RollupMinConsensusVersionsFlag.Name)) {
+			fork, version, ok := strings.Cut(entry, "=")
+			if !ok {
+				Fatalf("Invalid --%s entry %q, want fork=version", RollupMinConsensusVersionsFlag.Name, entry)
+			}
+			cfg.RollupMinConsensusVersions[strings.ToLower(strings.TrimSpace(fork))] = strings.TrimSpace(version)
+		}
+	}
 	// Override any default configs for hard coded networks.
 	switch {
 	case ctx.Bool(MainnetFlag.Name):
@@ -1998,7 +2617,7 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 		Fatalf("--%s flag must be 'gokzg' or 'ckzg'", CryptoKZGFlag.Name)
 	}
 	log.Info("Initializing the KZG library", "backend", ctx.String(CryptoKZGFlag.Name))
-	if err := kzg4844.UseCKZG(ctx.String(CryptoKZGFlag.Name) == "ckzg"); err != nil {
+	if err := kzg4844.UseBackend(ctx.String(CryptoKZGFlag.Name)); err != nil {
 		Fatalf("Failed to set KZG library implementation to %s: %v", ctx.String(CryptoKZGFlag.Name), err)
 	}
 }
@@ -2079,6 +2698,14 @@ func RegisterFullSyncTester(stack *node.Node, eth *eth.Ethereum, target common.H
 	log.Info("Registered full-sync tester", "hash", target)
 }
 
+// RegisterTracingExporter starts exporting tracing spans to an OpenTelemetry
+// collector if requested.
+func RegisterTracingExporter(stack *node.Node, ctx *cli.Context) {
+	if endpoint := ctx.String(TracingEndpointFlag.Name); endpoint != "" {
+		stack.RegisterLifecycle(tracing.NewExporter(endpoint, ctx.String(TracingServiceFlag.Name)))
+	}
+}
+
 func SetupMetrics(ctx *cli.Context) {
 	if metrics.Enabled {
 		log.Info("Enabling metrics collection")
@@ -2285,6 +2912,7 @@ func MakeChain(ctx *cli.Context, stack *node.Node, readonly bool) (*core.BlockCh
 		TrieDirtyDisabled:   ctx.String(GCModeFlag.Name) == "archive",
 		TrieTimeLimit:       ethconfig.Defaults.TrieTimeout,
 		SnapshotLimit:       ethconfig.Defaults.SnapshotCache,
+		SnapshotGenRate:     ctx.Int(SnapshotGenRateFlag.Name),
 		Preimages:           ctx.Bool(CachePreimagesFlag.Name),
 		StateScheme:         scheme,
 		StateHistory:        ctx.Uint64(StateHistoryFlag.Name),
@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// LegacyBlock is the export format of a pre-bedrock block, consisting of the
+// block itself and its receipts in storage encoding.
+type LegacyBlock struct {
+	Block    *types.Block
+	Receipts []*types.ReceiptForStorage
+}
+
+// ImportLegacyHistory imports the pre-bedrock blocks and receipts of the given
+// RLP stream of LegacyBlock items into the ancient store. The blocks need to be
+// sequential, starting at most at the first block missing from the ancient store,
+// and chained to the already stored ones. Once the last legacy block is imported
+// and links to the stored bedrock block, the boundary is marked in the database.
+func ImportLegacyHistory(db ethdb.Database, config *params.ChainConfig, fn string) error {
+	// Watch for Ctrl-C while the import is running.
+	// If a signal is received, the import will stop at the next batch.
+	interrupt := make(chan os.Signal, 1)
+	stop := make(chan struct{})
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+	defer close(interrupt)
+	go func() {
+		if _, ok := <-interrupt; ok {
+			log.Info("Interrupted during import, stopping at next batch")
+		}
+		close(stop)
+	}()
+
+	if config == nil || config.BedrockBlock == nil || config.BedrockBlock.Sign() == 0 {
+		return errors.New("chain has no pre-bedrock history")
+	}
+	bedrock := config.BedrockBlock.Uint64()
+	if boundary := rawdb.ReadLegacyHistoryBoundary(db); boundary != nil {
+		log.Info("Legacy history already imported", "boundary", *boundary)
+		return nil
+	}
+	frozen, err := db.Ancients()
+	if err != nil {
+		return err
+	}
+	if frozen >= bedrock {
+		log.Info("Legacy history already in the ancient store", "ancients", frozen, "bedrock", bedrock)
+		return markLegacyHistoryBoundary(db, bedrock)
+	}
+	log.Info("Importing legacy history", "file", fn, "ancients", frozen, "bedrock", bedrock)
+
+	// Open the file handle and potentially unwrap the gzip stream
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return err
+		}
+	}
+	stream := rlp.NewStream(reader, 0)
+
+	// Resume from the last block in the ancient store, if any
+	var (
+		parent common.Hash
+		td     *big.Int
+	)
+	if frozen > 0 {
+		parent = rawdb.ReadCanonicalHash(db, frozen-1)
+		if td = rawdb.ReadTd(db, parent, frozen-1); td == nil {
+			return fmt.Errorf("missing total difficulty of block %d", frozen-1)
+		}
+	}
+	var (
+		blocks   = make([]*types.Block, 0, importBatchSize)
+		receipts = make([]types.Receipts, 0, importBatchSize)
+		batchTd  *big.Int
+		start    = time.Now()
+		logged   time.Time
+		next     = frozen
+	)
+	flush := func() error {
+		if len(blocks) == 0 {
+			return nil
+		}
+		if _, err := rawdb.WriteAncientBlocks(db, blocks, receipts, batchTd); err != nil {
+			return err
+		}
+		if err := db.Sync(); err != nil {
+			return err
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Importing legacy history", "number", next-1, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+		blocks, receipts, batchTd = blocks[:0], receipts[:0], nil
+		return nil
+	}
+	for {
+		select {
+		case <-stop:
+			if err := flush(); err != nil {
+				return err
+			}
+			return errors.New("interrupted")
+		default:
+		}
+		var item LegacyBlock
+		if err := stream.Decode(&item); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("at block %d: %v", next, err)
+		}
+		block := item.Block
+		number := block.NumberU64()
+
+		// Skip the blocks already imported, ensuring they match the stored ones
+		if number < next {
+			if stored := rawdb.ReadCanonicalHash(db, number); stored != block.Hash() {
+				return fmt.Errorf("block %d mismatch: stored %x, importing %x", number, stored, block.Hash())
+			}
+			continue
+		}
+		if number != next {
+			return fmt.Errorf("non-contiguous block %d, expected %d", number, next)
+		}
+		if number >= bedrock {
+			return fmt.Errorf("block %d not below bedrock block %d", number, bedrock)
+		}
+		if err := verifyLegacyBlock(block, item.Receipts); err != nil {
+			return fmt.Errorf("invalid block %d: %v", number, err)
+		}
+		if number > 0 && block.ParentHash() != parent {
+			return fmt.Errorf("block %d parent hash mismatch: have %x, want %x", number, block.ParentHash(), parent)
+		}
+		// Reject blocks conflicting with the canonical ones already known
+		if stored := rawdb.ReadCanonicalHash(db, number); stored != (common.Hash{}) && stored != block.Hash() {
+			return fmt.Errorf("block %d mismatch: stored %x, importing %x", number, stored, block.Hash())
+		}
+		if td == nil {
+			td = new(big.Int).Set(block.Difficulty())
+		} else {
+			td = new(big.Int).Add(td, block.Difficulty())
+		}
+		if batchTd == nil {
+			batchTd = td
+		}
+		blockReceipts := make(types.Receipts, len(item.Receipts))
+		for i, receipt := range item.Receipts {
+			blockReceipts[i] = (*types.Receipt)(receipt)
+		}
+		blocks = append(blocks, block)
+		receipts = append(receipts, blockReceipts)
+		parent, next = block.Hash(), number+1
+
+		if len(blocks) == importBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	log.Info("Imported legacy history", "number", next, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	// Mark the boundary once the history links to the bedrock block
+	if next < bedrock {
+		log.Info("Legacy history incomplete, import the remaining blocks", "next", next, "bedrock", bedrock)
+		return nil
+	}
+	return markLegacyHistoryBoundary(db, bedrock)
+}
+
+// markLegacyHistoryBoundary marks the legacy history as imported, after checking
+// it links to the stored bedrock block.
+func markLegacyHistoryBoundary(db ethdb.Database, bedrock uint64) error {
+	header := rawdb.ReadHeader(db, rawdb.ReadCanonicalHash(db, bedrock), bedrock)
+	if header == nil {
+		return fmt.Errorf("bedrock block %d missing", bedrock)
+	}
+	if parent := rawdb.ReadCanonicalHash(db, bedrock-1); header.ParentHash != parent {
+		return fmt.Errorf("legacy history does not link to bedrock block: have %x, want %x", parent, header.ParentHash)
+	}
+	rawdb.WriteLegacyHistoryBoundary(db, bedrock)
+	log.Info("Marked legacy history boundary", "bedrock", bedrock, "hash", header.Hash())
+	return nil
+}
+
+// verifyLegacyBlock checks the body and receipts of a legacy block against the
+// commitments of its header.
+func verifyLegacyBlock(block *types.Block, receipts []*types.ReceiptForStorage) error {
+	header := block.Header()
+	if hash := types.CalcUncleHash(block.Uncles()); hash != header.UncleHash {
+		return fmt.Errorf("uncle root hash mismatch: have %x, want %x", hash, header.UncleHash)
+	}
+	if hash := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)); hash != header.TxHash {
+		return fmt.Errorf("transaction root hash mismatch: have %x, want %x", hash, header.TxHash)
+	}
+	txs := block.Transactions()
+	if len(receipts) != len(txs) {
+		return fmt.Errorf("receipt count mismatch: have %d, want %d", len(receipts), len(txs))
+	}
+	// The storage encoding omits the receipt type, derive it from the transaction
+	// to compute the receipt root.
+	consensus := make(types.Receipts, len(receipts))
+	for i, receipt := range receipts {
+		r := *(*types.Receipt)(receipt)
+		r.Type = txs[i].Type()
+		consensus[i] = &r
+	}
+	if hash := types.DeriveSha(consensus, trie.NewStackTrie(nil)); hash != header.ReceiptHash {
+		return fmt.Errorf("receipt root hash mismatch: have %x, want %x", hash, header.ReceiptHash)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func makeLegacyChain(t *testing.T, bedrock int) (*params.ChainConfig, []*types.Block, []types.Receipts) {
+	var (
+		key, _ = crypto.GenerateKey()
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		config = *params.TestChainConfig
+	)
+	config.BedrockBlock = big.NewInt(int64(bedrock))
+	gspec := &core.Genesis{
+		Config:  &config,
+		Alloc:   core.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	signer := types.LatestSigner(&config)
+	_, blocks, receipts := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), bedrock, func(i int, gen *core.BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(gen.TxNonce(addr), common.Address{0x01}, big.NewInt(1), params.TxGas, gen.BaseFee(), nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gen.AddTx(tx)
+	})
+	blocks = append([]*types.Block{gspec.ToBlock()}, blocks...)
+	receipts = append([]types.Receipts{{}}, receipts...)
+	return &config, blocks, receipts
+}
+
+func writeLegacyFile(t *testing.T, fn string, blocks []*types.Block, receipts []types.Receipts) {
+	f, err := os.Create(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for i, block := range blocks {
+		item := LegacyBlock{Block: block}
+		for _, receipt := range receipts[i] {
+			item.Receipts = append(item.Receipts, (*types.ReceiptForStorage)(receipt))
+		}
+		if err := rlp.Encode(f, &item); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newLegacyTestDB(t *testing.T, bedrock *types.Block) ethdb.Database {
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), t.TempDir(), "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawdb.WriteBlock(db, bedrock)
+	rawdb.WriteCanonicalHash(db, bedrock.Hash(), bedrock.NumberU64())
+	return db
+}
+
+func TestImportLegacyHistory(t *testing.T) {
+	config, blocks, receipts := makeLegacyChain(t, 8)
+	db := newLegacyTestDB(t, blocks[8])
+	defer db.Close()
+
+	// Import the history in two parts, the second one overlapping the first
+	dir := t.TempDir()
+	writeLegacyFile(t, filepath.Join(dir, "part1"), blocks[:5], receipts[:5])
+	writeLegacyFile(t, filepath.Join(dir, "part2"), blocks[3:8], receipts[3:8])
+
+	if err := ImportLegacyHistory(db, config, filepath.Join(dir, "part1")); err != nil {
+		t.Fatalf("failed to import first part: %v", err)
+	}
+	if boundary := rawdb.ReadLegacyHistoryBoundary(db); boundary != nil {
+		t.Fatalf("boundary marked on partial import: %d", *boundary)
+	}
+	if err := ImportLegacyHistory(db, config, filepath.Join(dir, "part2")); err != nil {
+		t.Fatalf("failed to import second part: %v", err)
+	}
+	if frozen, _ := db.Ancients(); frozen != 8 {
+		t.Fatalf("ancient count mismatch: have %d, want 8", frozen)
+	}
+	if boundary := rawdb.ReadLegacyHistoryBoundary(db); boundary == nil || *boundary != 8 {
+		t.Fatalf("boundary mismatch: have %v, want 8", boundary)
+	}
+	for i := 0; i < 8; i++ {
+		if hash := rawdb.ReadCanonicalHash(db, uint64(i)); hash != blocks[i].Hash() {
+			t.Errorf("block %d hash mismatch: have %x, want %x", i, hash, blocks[i].Hash())
+		}
+		if have := rawdb.ReadRawReceipts(db, blocks[i].Hash(), uint64(i)); len(have) != len(receipts[i]) {
+			t.Errorf("block %d receipt count mismatch: have %d, want %d", i, len(have), len(receipts[i]))
+		}
+	}
+}
+
+func TestImportLegacyHistoryInvalid(t *testing.T) {
+	config, blocks, receipts := makeLegacyChain(t, 8)
+	dir := t.TempDir()
+
+	// Gaps in the history are rejected
+	db := newLegacyTestDB(t, blocks[8])
+	defer db.Close()
+
+	fn := filepath.Join(dir, "gap")
+	writeLegacyFile(t, fn, append(blocks[:3:3], blocks[4:8]...), append(receipts[:3:3], receipts[4:8]...))
+	if err := ImportLegacyHistory(db, config, fn); err == nil {
+		t.Error("history with gap imported")
+	}
+	// Receipts not matching the header are rejected
+	db = newLegacyTestDB(t, blocks[8])
+	defer db.Close()
+
+	fn = filepath.Join(dir, "receipts")
+	missing := append([]types.Receipts{}, receipts[:8]...)
+	missing[3] = nil
+	writeLegacyFile(t, fn, blocks[:8], missing)
+	if err := ImportLegacyHistory(db, config, fn); err == nil {
+		t.Error("history with mismatching receipts imported")
+	}
+}
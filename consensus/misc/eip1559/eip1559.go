@@ -92,7 +92,7 @@ func CalcBaseFee(config *params.ChainConfig, parent *types.Header, time uint64)
 		num.Mul(num, parent.BaseFee)
 		num.Div(num, denom.SetUint64(parentGasTarget))
 		num.Div(num, denom.SetUint64(config.BaseFeeChangeDenominator(time)))
-		baseFeeDelta := math.BigMax(num, common.Big1)
+		baseFeeDelta := math.BigMax(capBaseFeeDelta(config, time, parent.BaseFee, num), common.Big1)
 
 		return num.Add(parent.BaseFee, baseFeeDelta)
 	} else {
@@ -102,8 +102,25 @@ func CalcBaseFee(config *params.ChainConfig, parent *types.Header, time uint64)
 		num.Mul(num, parent.BaseFee)
 		num.Div(num, denom.SetUint64(parentGasTarget))
 		num.Div(num, denom.SetUint64(config.BaseFeeChangeDenominator(time)))
-		baseFee := num.Sub(parent.BaseFee, num)
+		baseFee := num.Sub(parent.BaseFee, capBaseFeeDelta(config, time, parent.BaseFee, num))
 
 		return math.BigMax(baseFee, common.Big0)
 	}
 }
+
+// capBaseFeeDelta limits the base fee change of the block at the given time to
+// the configured maximum fraction of the parent base fee, if any. The delta is
+// modified in place.
+func capBaseFeeDelta(config *params.ChainConfig, time uint64, parentBaseFee, delta *big.Int) *big.Int {
+	bps := config.BaseFeeMaxChangeBps(time)
+	if bps == 0 {
+		return delta
+	}
+	limit := new(big.Int).SetUint64(bps)
+	limit.Mul(limit, parentBaseFee)
+	limit.Div(limit, big.NewInt(10_000))
+	if delta.Cmp(limit) > 0 {
+		delta.Set(limit)
+	}
+	return delta
+}
@@ -0,0 +1,68 @@
+package eip1559
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// HoloceneExtraDataVersion is the version byte of the Holocene extraData format.
+const HoloceneExtraDataVersion = 0
+
+// EncodeHolocene1559Params encodes the EIP-1559 denominator and elasticity into
+// the 8-byte eip1559Params format of the Holocene payload attributes.
+func EncodeHolocene1559Params(denominator, elasticity uint32) []byte {
+	params := make([]byte, 8)
+	binary.BigEndian.PutUint32(params[:4], denominator)
+	binary.BigEndian.PutUint32(params[4:], elasticity)
+	return params
+}
+
+// DecodeHolocene1559Params decodes the EIP-1559 denominator and elasticity from
+// the eip1559Params format. The params are assumed to be valid.
+func DecodeHolocene1559Params(params []byte) (uint64, uint64) {
+	denominator := binary.BigEndian.Uint32(params[:4])
+	elasticity := binary.BigEndian.Uint32(params[4:])
+	return uint64(denominator), uint64(elasticity)
+}
+
+// ValidateHolocene1559Params checks the eip1559Params of the payload attributes.
+// Both values being zero is allowed, signalling the pre-Holocene parameters of
+// the chain config are to be used.
+func ValidateHolocene1559Params(params []byte) error {
+	if len(params) != 8 {
+		return fmt.Errorf("holocene eip-1559 params should be 8 bytes, got %d", len(params))
+	}
+	denominator, elasticity := DecodeHolocene1559Params(params)
+	if denominator == 0 && elasticity != 0 {
+		return errors.New("holocene params cannot have a 0 denominator unless elasticity is also 0")
+	}
+	return nil
+}
+
+// EncodeHoloceneExtraData encodes the EIP-1559 denominator and elasticity into
+// the 9-byte Holocene header extraData format.
+func EncodeHoloceneExtraData(denominator, elasticity uint32) []byte {
+	return append([]byte{HoloceneExtraDataVersion}, EncodeHolocene1559Params(denominator, elasticity)...)
+}
+
+// DecodeHoloceneExtraData decodes the EIP-1559 denominator and elasticity from
+// the Holocene header extraData. The extraData is assumed to be valid.
+func DecodeHoloceneExtraData(extra []byte) (uint64, uint64) {
+	return DecodeHolocene1559Params(extra[1:])
+}
+
+// ValidateHoloceneExtraData checks the header extraData of a Holocene block.
+// Unlike the payload attributes, a header always carries the effective values.
+func ValidateHoloceneExtraData(extra []byte) error {
+	if len(extra) != 9 {
+		return fmt.Errorf("holocene extraData should be 9 bytes, got %d", len(extra))
+	}
+	if extra[0] != HoloceneExtraDataVersion {
+		return fmt.Errorf("holocene extraData version should be %d, got %d", HoloceneExtraDataVersion, extra[0])
+	}
+	if denominator, _ := DecodeHoloceneExtraData(extra); denominator == 0 {
+		return errors.New("holocene extraData must encode a non-zero denominator")
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package eip1559
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHolocene1559Params(t *testing.T) {
+	params := EncodeHolocene1559Params(250, 6)
+	if !bytes.Equal(params, []byte{0, 0, 0, 250, 0, 0, 0, 6}) {
+		t.Fatalf("params encoding mismatch: %x", params)
+	}
+	if d, e := DecodeHolocene1559Params(params); d != 250 || e != 6 {
+		t.Fatalf("params decoding mismatch: have %d/%d, want 250/6", d, e)
+	}
+	tests := []struct {
+		params []byte
+		valid  bool
+	}{
+		{EncodeHolocene1559Params(250, 6), true},
+		{EncodeHolocene1559Params(0, 0), true},
+		{EncodeHolocene1559Params(0, 6), false},
+		{EncodeHolocene1559Params(250, 0), true},
+		{[]byte{0, 0, 0, 250}, false},
+	}
+	for i, tt := range tests {
+		if err := ValidateHolocene1559Params(tt.params); (err == nil) != tt.valid {
+			t.Errorf("test %d: validity mismatch: have %v, want %v", i, err, tt.valid)
+		}
+	}
+}
+
+func TestHoloceneExtraData(t *testing.T) {
+	extra := EncodeHoloceneExtraData(250, 6)
+	if !bytes.Equal(extra, []byte{0, 0, 0, 0, 250, 0, 0, 0, 6}) {
+		t.Fatalf("extraData encoding mismatch: %x", extra)
+	}
+	if d, e := DecodeHoloceneExtraData(extra); d != 250 || e != 6 {
+		t.Fatalf("extraData decoding mismatch: have %d/%d, want 250/6", d, e)
+	}
+	tests := []struct {
+		extra []byte
+		valid bool
+	}{
+		{EncodeHoloceneExtraData(250, 6), true},
+		{EncodeHoloceneExtraData(0, 0), false},
+		{append([]byte{1}, EncodeHolocene1559Params(250, 6)...), false},
+		{EncodeHoloceneExtraData(250, 6)[:8], false},
+	}
+	for i, tt := range tests {
+		if err := ValidateHoloceneExtraData(tt.extra); (err == nil) != tt.valid {
+			t.Errorf("test %d: validity mismatch: have %v, want %v", i, err, tt.valid)
+		}
+	}
+}
@@ -175,3 +175,39 @@ func TestCalcBaseFeeOptimism(t *testing.T) {
 		}
 	}
 }
+
+// TestCalcBaseFeeSmoothing tests that the optional per-block base fee change cap
+// is applied in both directions.
+func TestCalcBaseFeeSmoothing(t *testing.T) {
+	tests := []struct {
+		maxChangeBps    uint64
+		capTime         uint64
+		parentGasUsed   uint64
+		expectedBaseFee int64
+	}{
+		{0, 0, 30_000_000, 1100000000},   // no cap, full block
+		{100, 0, 30_000_000, 1010000000}, // 1% cap, full block
+		{100, 9, 30_000_000, 1100000000}, // 1% cap, before the fork
+		{100, 8, 30_000_000, 1010000000}, // 1% cap, fork activating with the block
+		{100, 0, 10_000_000, 1010000000}, // 1% cap, usage above target
+		{500, 0, 10_000_000, 1020000000}, // cap looser than denominator
+		{100, 0, 0, 990000000},           // 1% cap, empty block
+		{100, 0, 5_000_000, params.InitialBaseFee},
+	}
+	for i, test := range tests {
+		config := opConfig()
+		config.Optimism.EIP1559MaxChangeBps = test.maxChangeBps
+		capTime := test.capTime
+		config.OasysBaseFeeCapTime = &capTime
+		parent := &types.Header{
+			Number:   common.Big32,
+			GasLimit: 30_000_000,
+			GasUsed:  test.parentGasUsed,
+			BaseFee:  big.NewInt(params.InitialBaseFee),
+			Time:     6,
+		}
+		if have, want := CalcBaseFee(config, parent, parent.Time+2), big.NewInt(test.expectedBaseFee); have.Cmp(want) != 0 {
+			t.Errorf("test %d: have %d  want %d, ", i, have, want)
+		}
+	}
+}
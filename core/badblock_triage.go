@@ -0,0 +1,270 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// badBlockTriageTimeout is the time allowed to query the peer about a bad block.
+	badBlockTriageTimeout = time.Minute
+
+	// badBlockTriageAccounts is the maximum number of modified accounts compared
+	// with the peer, in address order.
+	badBlockTriageAccounts = 1024
+)
+
+// BadBlockPeer provides the view of a trusted peer, typically the sequencer, on
+// the blocks failing validation locally.
+type BadBlockPeer interface {
+	// BlockReceipts retrieves the receipts of the block with the given hash.
+	BlockReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error)
+
+	// Account retrieves the account at the state of the block with the given
+	// hash, the empty account if it doesn't exist.
+	Account(ctx context.Context, addr common.Address, hash common.Hash) (*types.StateAccount, error)
+}
+
+// BadBlockReport is the triage report attached to a block failing validation,
+// comparing the local execution with the header and, if available, with the
+// receipts and the post state of the peer.
+type BadBlockReport struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+	Error  string      `json:"error"`
+
+	RemoteGasUsed     uint64      `json:"remoteGasUsed"`
+	LocalGasUsed      uint64      `json:"localGasUsed"`
+	RemoteReceiptRoot common.Hash `json:"remoteReceiptRoot"`
+	LocalReceiptRoot  common.Hash `json:"localReceiptRoot"`
+	RemoteRoot        common.Hash `json:"remoteRoot"`
+	LocalRoot         common.Hash `json:"localRoot"`
+
+	Transactions     []*BadTxReport    `json:"transactions"`
+	FirstDivergentTx *int              `json:"firstDivergentTx,omitempty"` // First transaction whose receipt differs from the peer's
+	DivergentAccount *BadAccountReport `json:"divergentAccount,omitempty"` // First modified account whose state differs from the peer's
+	AccountsChecked  int               `json:"accountsChecked"`            // Number of modified accounts compared with the peer
+	PeerError        string            `json:"peerError,omitempty"`
+}
+
+// BadTxReport compares the receipt of a transaction of a bad block.
+type BadTxReport struct {
+	Index             int         `json:"index"`
+	Hash              common.Hash `json:"hash"`
+	Status            uint64      `json:"status"`
+	GasUsed           uint64      `json:"gasUsed"`
+	CumulativeGasUsed uint64      `json:"cumulativeGasUsed"`
+	Logs              int         `json:"logs"`
+
+	PeerStatus            *uint64 `json:"peerStatus,omitempty"`
+	PeerGasUsed           *uint64 `json:"peerGasUsed,omitempty"`
+	PeerCumulativeGasUsed *uint64 `json:"peerCumulativeGasUsed,omitempty"`
+	PeerLogs              *int    `json:"peerLogs,omitempty"`
+}
+
+// BadAccountReport compares the post state of an account modified by a bad block.
+type BadAccountReport struct {
+	Address common.Address   `json:"address"`
+	Local   *BadAccountState `json:"local"`
+	Peer    *BadAccountState `json:"peer"`
+}
+
+// BadAccountState is the state of an account after a bad block.
+type BadAccountState struct {
+	Nonce    uint64       `json:"nonce"`
+	Balance  *hexutil.Big `json:"balance"`
+	Root     common.Hash  `json:"storageRoot"`
+	CodeHash common.Hash  `json:"codeHash"`
+}
+
+func newBadAccountState(account *types.StateAccount) *BadAccountState {
+	balance := account.Balance
+	if balance == nil {
+		balance = new(big.Int)
+	}
+	return &BadAccountState{
+		Nonce:    account.Nonce,
+		Balance:  (*hexutil.Big)(balance),
+		Root:     account.Root,
+		CodeHash: common.BytesToHash(account.CodeHash),
+	}
+}
+
+func (s *BadAccountState) equal(other *BadAccountState) bool {
+	return s.Nonce == other.Nonce && s.Balance.ToInt().Cmp(other.Balance.ToInt()) == 0 &&
+		s.Root == other.Root && s.CodeHash == other.CodeHash
+}
+
+// SetBadBlockPeer sets the peer the blocks failing validation are compared with
+// in their triage report, nil to only report the local execution.
+func (bc *BlockChain) SetBadBlockPeer(peer BadBlockPeer) {
+	if peer == nil {
+		bc.badBlockPeer.Store(nil)
+		return
+	}
+	bc.badBlockPeer.Store(&peer)
+}
+
+// triageBadBlock attaches a triage report to the bad block failing validation,
+// the comparison with the peer being run in the background.
+func (bc *BlockChain) triageBadBlock(block *types.Block, statedb *state.StateDB, receipts types.Receipts, usedGas uint64, err error) {
+	report := &BadBlockReport{
+		Number:            block.NumberU64(),
+		Hash:              block.Hash(),
+		Error:             err.Error(),
+		RemoteGasUsed:     block.GasUsed(),
+		LocalGasUsed:      usedGas,
+		RemoteReceiptRoot: block.ReceiptHash(),
+		LocalReceiptRoot:  types.DeriveSha(receipts, trie.NewStackTrie(nil)),
+		RemoteRoot:        block.Root(),
+		LocalRoot:         statedb.IntermediateRoot(bc.chainConfig.IsEIP158(block.Number())),
+	}
+	for i, receipt := range receipts {
+		report.Transactions = append(report.Transactions, &BadTxReport{
+			Index:             i,
+			Hash:              receipt.TxHash,
+			Status:            receipt.Status,
+			GasUsed:           receipt.GasUsed,
+			CumulativeGasUsed: receipt.CumulativeGasUsed,
+			Logs:              len(receipt.Logs),
+		})
+	}
+	peer := bc.badBlockPeer.Load()
+	if peer == nil {
+		bc.writeBadBlockReport(report)
+		return
+	}
+	// Snapshot the modified accounts, the peer being queried in the background
+	var (
+		addrs  = statedb.DirtyAccounts()
+		locals = make([]*BadAccountState, 0, len(addrs))
+	)
+	if len(addrs) > badBlockTriageAccounts {
+		addrs = addrs[:badBlockTriageAccounts]
+	}
+	for _, addr := range addrs {
+		account := &types.StateAccount{
+			Nonce:    statedb.GetNonce(addr),
+			Balance:  statedb.GetBalance(addr),
+			Root:     types.EmptyRootHash,
+			CodeHash: types.EmptyCodeHash.Bytes(),
+		}
+		if statedb.Exist(addr) {
+			account.Root = statedb.GetStorageRoot(addr)
+			account.CodeHash = statedb.GetCodeHash(addr).Bytes()
+		}
+		locals = append(locals, newBadAccountState(account))
+	}
+	bc.wg.Add(1)
+	go func() {
+		defer bc.wg.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), badBlockTriageTimeout)
+		defer cancel()
+		go func() {
+			select {
+			case <-bc.quit:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		if err := compareBadBlock(ctx, *peer, report, receipts, addrs, locals); err != nil {
+			report.PeerError = err.Error()
+		}
+		bc.writeBadBlockReport(report)
+	}()
+}
+
+// compareBadBlock fills the report with the first receipt and the first account
+// differing from the peer's.
+func compareBadBlock(ctx context.Context, peer BadBlockPeer, report *BadBlockReport, receipts types.Receipts, addrs []common.Address, locals []*BadAccountState) error {
+	remotes, err := peer.BlockReceipts(ctx, report.Hash)
+	if err != nil {
+		return err
+	}
+	for i, tx := range report.Transactions {
+		if i >= len(remotes) {
+			break
+		}
+		remote := remotes[i]
+		tx.PeerStatus, tx.PeerGasUsed, tx.PeerCumulativeGasUsed = &remote.Status, &remote.GasUsed, &remote.CumulativeGasUsed
+		logs := len(remote.Logs)
+		tx.PeerLogs = &logs
+
+		if report.FirstDivergentTx == nil && !sameReceipt(receipts[i], remote) {
+			index := i
+			report.FirstDivergentTx = &index
+		}
+	}
+	if report.FirstDivergentTx == nil && len(remotes) != len(receipts) {
+		index := len(remotes)
+		if len(receipts) < index {
+			index = len(receipts)
+		}
+		report.FirstDivergentTx = &index
+	}
+	for i, addr := range addrs {
+		account, err := peer.Account(ctx, addr, report.Hash)
+		if err != nil {
+			return err
+		}
+		report.AccountsChecked++
+		if remote := newBadAccountState(account); !locals[i].equal(remote) {
+			report.DivergentAccount = &BadAccountReport{Address: addr, Local: locals[i], Peer: remote}
+			break
+		}
+	}
+	return nil
+}
+
+// sameReceipt reports whether the consensus fields of the receipts match.
+func sameReceipt(local, remote *types.Receipt) bool {
+	if local.Status != remote.Status || local.GasUsed != remote.GasUsed ||
+		local.CumulativeGasUsed != remote.CumulativeGasUsed || len(local.Logs) != len(remote.Logs) {
+		return false
+	}
+	for i, l := range local.Logs {
+		r := remote.Logs[i]
+		if l.Address != r.Address || len(l.Topics) != len(r.Topics) || string(l.Data) != string(r.Data) {
+			return false
+		}
+		for j := range l.Topics {
+			if l.Topics[j] != r.Topics[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// writeBadBlockReport attaches the report to the stored bad block.
+func (bc *BlockChain) writeBadBlockReport(report *BadBlockReport) {
+	blob, err := json.Marshal(report)
+	if err != nil {
+		log.Error("Failed to encode bad block report", "hash", report.Hash, "err", err)
+		return
+	}
+	rawdb.WriteBadBlockReport(bc.db, report.Hash, blob)
+
+	ctx := []interface{}{"number", report.Number, "hash", report.Hash}
+	if report.FirstDivergentTx != nil {
+		ctx = append(ctx, "tx", *report.FirstDivergentTx)
+	}
+	if report.DivergentAccount != nil {
+		ctx = append(ctx, "account", report.DivergentAccount.Address)
+	}
+	if report.PeerError != "" {
+		ctx = append(ctx, "peererr", report.PeerError)
+	}
+	log.Warn("Triaged bad block", ctx...)
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type testBadBlockPeer struct {
+	receipts types.Receipts
+	accounts map[common.Address]*types.StateAccount
+}
+
+func (p *testBadBlockPeer) BlockReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return p.receipts, nil
+}
+
+func (p *testBadBlockPeer) Account(ctx context.Context, addr common.Address, hash common.Hash) (*types.StateAccount, error) {
+	return p.accounts[addr], nil
+}
+
+func TestCompareBadBlock(t *testing.T) {
+	var (
+		local = types.Receipts{
+			{Status: types.ReceiptStatusSuccessful, GasUsed: 21000, CumulativeGasUsed: 21000},
+			{Status: types.ReceiptStatusSuccessful, GasUsed: 30000, CumulativeGasUsed: 51000, Logs: []*types.Log{{Address: common.Address{0x01}}}},
+			{Status: types.ReceiptStatusSuccessful, GasUsed: 21000, CumulativeGasUsed: 72000},
+		}
+		remote = types.Receipts{
+			{Status: types.ReceiptStatusSuccessful, GasUsed: 21000, CumulativeGasUsed: 21000},
+			{Status: types.ReceiptStatusSuccessful, GasUsed: 30000, CumulativeGasUsed: 51000, Logs: []*types.Log{{Address: common.Address{0x02}}}},
+			{Status: types.ReceiptStatusFailed, GasUsed: 21000, CumulativeGasUsed: 72000},
+		}
+		addrs  = []common.Address{{0x01}, {0x02}, {0x03}}
+		locals = []*BadAccountState{
+			newBadAccountState(&types.StateAccount{Nonce: 1, Balance: big.NewInt(1), Root: types.EmptyRootHash, CodeHash: types.EmptyCodeHash.Bytes()}),
+			newBadAccountState(&types.StateAccount{Nonce: 2, Balance: big.NewInt(2), Root: types.EmptyRootHash, CodeHash: types.EmptyCodeHash.Bytes()}),
+			newBadAccountState(&types.StateAccount{Nonce: 3, Balance: big.NewInt(3), Root: types.EmptyRootHash, CodeHash: types.EmptyCodeHash.Bytes()}),
+		}
+		peer = &testBadBlockPeer{
+			receipts: remote,
+			accounts: map[common.Address]*types.StateAccount{
+				addrs[0]: {Nonce: 1, Balance: big.NewInt(1), Root: types.EmptyRootHash, CodeHash: types.EmptyCodeHash.Bytes()},
+				addrs[1]: {Nonce: 2, Balance: big.NewInt(5), Root: types.EmptyRootHash, CodeHash: types.EmptyCodeHash.Bytes()},
+				addrs[2]: {Nonce: 4, Balance: big.NewInt(3), Root: types.EmptyRootHash, CodeHash: types.EmptyCodeHash.Bytes()},
+			},
+		}
+		report = new(BadBlockReport)
+	)
+	for i, receipt := range local {
+		report.Transactions = append(report.Transactions, &BadTxReport{Index: i, Status: receipt.Status, GasUsed: receipt.GasUsed})
+	}
+	if err := compareBadBlock(context.Background(), peer, report, local, addrs, locals); err != nil {
+		t.Fatalf("failed to compare the bad block: %v", err)
+	}
+	if report.FirstDivergentTx == nil || *report.FirstDivergentTx != 1 {
+		t.Fatalf("first divergent transaction mismatch: have %v, want 1", report.FirstDivergentTx)
+	}
+	if status := report.Transactions[2].PeerStatus; status == nil || *status != types.ReceiptStatusFailed {
+		t.Fatalf("peer status not reported: %v", status)
+	}
+	if report.DivergentAccount == nil || report.DivergentAccount.Address != addrs[1] {
+		t.Fatalf("first divergent account mismatch: have %+v, want %x", report.DivergentAccount, addrs[1])
+	}
+	if report.AccountsChecked != 2 {
+		t.Fatalf("checked account count mismatch: have %d, want 2", report.AccountsChecked)
+	}
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// blockUsageCacheLimit is the number of recently imported blocks whose resource
+// usage is kept, to be attached to the chain head events.
+const blockUsageCacheLimit = 256
+
+// BlockUsage is the resources used by the import of a block.
+type BlockUsage struct {
+	Number     uint64      `json:"number"`
+	Hash       common.Hash `json:"hash"`
+	GasUsed    uint64      `json:"gasUsed"`
+	DABytes    uint64      `json:"daBytes"`    // Encoded size of the transactions posted to L1, deposits excluded
+	ExecTimeUs int64       `json:"execTimeUs"` // Time spent executing and validating the block
+	StateBytes uint64      `json:"stateBytes"` // Bytes of trie nodes and contract code written
+}
+
+func newBlockUsage(block *types.Block, gasUsed uint64, execTime time.Duration, stateBytes common.StorageSize) *BlockUsage {
+	usage := &BlockUsage{
+		Number:     block.NumberU64(),
+		Hash:       block.Hash(),
+		GasUsed:    gasUsed,
+		ExecTimeUs: execTime.Microseconds(),
+		StateBytes: uint64(stateBytes),
+	}
+	for _, tx := range block.Transactions() {
+		data := tx.RollupDataGas()
+		usage.DABytes += data.Zeroes + data.Ones
+	}
+	return usage
+}
+
+// BlockUsage returns the resources used by the import of the given block, nil
+// if it was not imported recently.
+func (bc *BlockChain) BlockUsage(hash common.Hash) *BlockUsage {
+	usage, _ := bc.blockUsages.Get(hash)
+	return usage
+}
@@ -55,6 +55,9 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 	if v.bc.HasBlockAndState(block.Hash(), block.NumberU64()) {
 		return ErrKnownBlock
 	}
+	if limit := v.config.MaxBlockSize(block.Time()); limit != 0 && block.Size() > limit {
+		return fmt.Errorf("%w: %d bytes, limit %d", ErrBlockTooLarge, block.Size(), limit)
+	}
 
 	// Header validity is known at this point. Here we verify that uncles, transactions
 	// and withdrawals given in the block body match the header.
@@ -68,6 +71,11 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 	if hash := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)); hash != header.TxHash {
 		return fmt.Errorf("transaction root hash mismatch (header value %x, calculated %x)", header.TxHash, hash)
 	}
+	if v.bc.depositCheck.Load() {
+		if err := ValidateDepositOrder(v.config, block); err != nil {
+			return err
+		}
+	}
 
 	// Withdrawals are present after the Shanghai fork.
 	if header.WithdrawalsHash != nil {
@@ -123,9 +131,18 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 // such as amount of used gas, the receipt roots and the state root itself.
 func (v *BlockValidator) ValidateState(block *types.Block, statedb *state.StateDB, receipts types.Receipts, usedGas uint64) error {
 	header := block.Header()
+	// Check the deposit receipts first, as violations would otherwise surface as
+	// an opaque gas or root mismatch.
+	if v.bc.depositCheck.Load() {
+		if err := ValidateDepositReceipts(v.config, block, receipts); err != nil {
+			return err
+		}
+	}
 	if block.GasUsed() != usedGas {
 		return fmt.Errorf("invalid gas used (remote: %d local: %d)", block.GasUsed(), usedGas)
 	}
+	recordStateGrowth(statedb)
+
 	// Validate the received block's bloom with the one derived from the generated receipts.
 	// For valid blocks this should always validate to true.
 	rbloom := types.CreateBloom(receipts)
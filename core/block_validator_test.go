@@ -17,6 +17,7 @@
 package core
 
 import (
+	"errors"
 	"math/big"
 	"testing"
 	"time"
@@ -239,6 +240,44 @@ func testHeaderVerificationForMerging(t *testing.T, isClique bool) {
 	}
 }
 
+func TestBlockSizeLimit(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		config = *params.TestChainConfig
+		gspec  = &Genesis{
+			Config: &config,
+			Alloc:  GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+	// Enforce the limit from the block size fork, after the first two blocks
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 2, EIP1559Denominator: 8, MaxBlockSize: 4096}
+	config.OasysBlockSizeTime = new(uint64)
+	*config.OasysBlockSizeTime = 25
+
+	// Create a chain whose blocks carry large transactions
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 3, func(i int, gen *BlockGen) {
+		if i > 0 {
+			tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(addr), common.Address{}, new(big.Int), 1000000, gen.header.BaseFee, make([]byte, 4096)), signer, key)
+			gen.AddTx(tx)
+		}
+	})
+	if blocks[1].Time() >= 25 || blocks[2].Time() < 25 {
+		t.Fatalf("unexpected block times: %d, %d", blocks[1].Time(), blocks[2].Time())
+	}
+	chain, _ := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer chain.Stop()
+
+	// Large blocks are accepted before the fork only
+	if _, err := chain.InsertChain(blocks[:2]); err != nil {
+		t.Fatalf("failed to insert blocks before the fork: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks[2:]); !errors.Is(err, ErrBlockTooLarge) {
+		t.Fatalf("block over the limit not rejected: %v", err)
+	}
+}
+
 func TestCalcGasLimit(t *testing.T) {
 	for i, tc := range []struct {
 		pGasLimit uint64
@@ -60,6 +60,8 @@ var (
 	headFinalizedBlockGauge = metrics.NewRegisteredGauge("chain/head/finalized", nil)
 	headSafeBlockGauge      = metrics.NewRegisteredGauge("chain/head/safe", nil)
 
+	rollupZeroFeeGauge = metrics.NewRegisteredGauge("rollup/zerofee", nil)
+
 	chainInfoGauge = metrics.NewRegisteredGaugeInfo("chain/info", nil)
 
 	accountReadTimer   = metrics.NewRegisteredTimer("chain/account/reads", nil)
@@ -105,6 +107,10 @@ const (
 	maxTimeFutureBlocks = 30
 	TriesInMemory       = 128
 
+	// warmCacheStates is the number of recent states whose reads are retained by
+	// the warm cache, covering the payloads built on a few competing parents.
+	warmCacheStates = 4
+
 	// BlockChainVersion ensures that an incompatible database forces a resync from scratch.
 	//
 	// Changelog:
@@ -140,6 +146,8 @@ type CacheConfig struct {
 	TrieDirtyDisabled   bool          // Whether to disable trie write caching and GC altogether (archive node)
 	TrieTimeLimit       time.Duration // Time limit after which to flush the current in-memory trie to disk
 	SnapshotLimit       int           // Memory allowance (MB) to use for caching snapshot entries in memory
+	SnapshotGenRate     int           // Megabytes of state indexed per second at most by the snapshot generation (0 = unlimited)
+	WarmCache           bool          // Whether to share the state read by the block builder with the import of its blocks
 	Preimages           bool          // Whether to store preimage of trie key to the disk
 	StateHistory        uint64        // Number of blocks from head whose state histories are reserved.
 	StateScheme         string        // Scheme used to store ethereum states and merkle tree nodes on top
@@ -211,13 +219,20 @@ type BlockChain struct {
 	flushInterval atomic.Int64                     // Time interval (processing time) after which to flush a state
 	triedb        *trie.Database                   // The database handler for maintaining trie nodes.
 	stateCache    state.Database                   // State database to reuse between imports (contains state cache)
+	warmCache     *state.WarmCache                 // Recent state reads shared by the block builder and import, nil if disabled
 
 	// txLookupLimit is the maximum number of blocks from head whose tx indices
 	// are reserved:
 	//  * 0:   means no limit and regenerate any missing indexes
 	//  * N:   means N block limit [HEAD-N+1, HEAD] and delete extra indexes
 	//  * nil: disable tx reindexer/deleter, but still index new blocks
-	txLookupLimit uint64
+	//
+	// The limit may be changed at runtime if the indexer is enabled.
+	txLookupLimit atomic.Uint64
+	txIndexKick   chan struct{}     // Notifies the indexer of a limit change, nil if disabled
+	txIndexRanges chan txIndexRange // Ranges to reindex, requested at runtime
+	txIndexing    atomic.Bool       // Whether the indexer is processing
+	txSenderIndex atomic.Bool       // Whether transactions are also indexed by sender and nonce
 
 	hc            *HeaderChain
 	rmLogsFeed    event.Feed
@@ -229,6 +244,10 @@ type BlockChain struct {
 	scope         event.SubscriptionScope
 	genesisBlock  *types.Block
 
+	headUpdateFeed event.Feed  // Labelled head transitions, see HeadUpdateEvent
+	blockProcessed event.Feed  // Processing times of the imported blocks, see BlockProcessedEvent
+	headUpdates    headUpdates // Last head posted per label
+
 	// This mutex synchronizes chain write operations.
 	// Readers don't need to take it, they can just read the database.
 	chainmu *syncx.ClosableMutex
@@ -241,6 +260,7 @@ type BlockChain struct {
 	bodyCache     *lru.Cache[common.Hash, *types.Body]
 	bodyRLPCache  *lru.Cache[common.Hash, rlp.RawValue]
 	receiptsCache *lru.Cache[common.Hash, []*types.Receipt]
+	blockUsages   *lru.Cache[common.Hash, *BlockUsage] // Resources used by the recently imported blocks
 	blockCache    *lru.Cache[common.Hash, *types.Block]
 	txLookupCache *lru.Cache[common.Hash, *rawdb.LegacyTxLookupEntry]
 
@@ -251,6 +271,9 @@ type BlockChain struct {
 	quit          chan struct{}  // shutdown signal, closed in Stop.
 	stopping      atomic.Bool    // false if chain is running, true when stopped
 	procInterrupt atomic.Bool    // interrupt signaler for block processing
+	depositCheck  atomic.Bool    // whether deposit rules are checked on import
+
+	badBlockPeer atomic.Pointer[BadBlockPeer] // peer the bad blocks are compared with, if any
 
 	engine     consensus.Engine
 	validator  Validator // Block and state validator interface
@@ -277,6 +300,9 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 	if _, ok := genesisErr.(*params.ConfigCompatError); genesisErr != nil && !ok {
 		return nil, genesisErr
 	}
+	if err := vm.ValidateEVMForks(chainConfig); err != nil {
+		return nil, err
+	}
 	log.Info("")
 	log.Info(strings.Repeat("-", 153))
 	for _, line := range strings.Split(chainConfig.Description(), "\n") {
@@ -300,6 +326,7 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 		bodyCache:     lru.NewCache[common.Hash, *types.Body](bodyCacheLimit),
 		bodyRLPCache:  lru.NewCache[common.Hash, rlp.RawValue](bodyCacheLimit),
 		receiptsCache: lru.NewCache[common.Hash, []*types.Receipt](receiptsCacheLimit),
+		blockUsages:   lru.NewCache[common.Hash, *BlockUsage](blockUsageCacheLimit),
 		blockCache:    lru.NewCache[common.Hash, *types.Block](blockCacheLimit),
 		txLookupCache: lru.NewCache[common.Hash, *rawdb.LegacyTxLookupEntry](txLookupCacheLimit),
 		futureBlocks:  lru.NewCache[common.Hash, *types.Block](maxFutureBlocks),
@@ -309,6 +336,9 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 	bc.flushInterval.Store(int64(cacheConfig.TrieTimeLimit))
 	bc.forker = NewForkChoice(bc, shouldPreserve)
 	bc.stateCache = state.NewDatabaseWithNodeDB(bc.db, bc.triedb)
+	if cacheConfig.WarmCache {
+		bc.warmCache = state.NewWarmCache(warmCacheStates)
+	}
 	bc.validator = NewBlockValidator(chainConfig, bc, engine)
 	bc.prefetcher = newStatePrefetcher(chainConfig, bc, engine)
 	bc.processor = NewStateProcessor(chainConfig, bc, engine)
@@ -450,6 +480,9 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 			NoBuild:    bc.cacheConfig.SnapshotNoBuild,
 			AsyncBuild: !bc.cacheConfig.SnapshotWait,
 		}
+		if bc.cacheConfig.SnapshotGenRate > 0 {
+			snapconfig.GenerationRate = uint64(bc.cacheConfig.SnapshotGenRate) * 1024 * 1024
+		}
 		bc.snaps, _ = snapshot.New(snapconfig, bc.db, bc.triedb, head.Root)
 	}
 
@@ -469,7 +502,9 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 	}
 	// Start tx indexer/unindexer if required.
 	if txLookupLimit != nil {
-		bc.txLookupLimit = *txLookupLimit
+		bc.txLookupLimit.Store(*txLookupLimit)
+		bc.txIndexKick = make(chan struct{}, 1)
+		bc.txIndexRanges = make(chan txIndexRange)
 
 		bc.wg.Add(1)
 		go bc.maintainTxIndex()
@@ -599,7 +634,7 @@ func (bc *BlockChain) SetHead(head uint64) error {
 		log.Error("Current block not found in database", "block", header.Number, "hash", header.Hash())
 		return fmt.Errorf("current block missing: #%d [%x..]", header.Number, header.Hash().Bytes()[:4])
 	}
-	bc.chainHeadFeed.Send(ChainHeadEvent{Block: block})
+	bc.sendHeadEvent(block)
 	return nil
 }
 
@@ -621,7 +656,7 @@ func (bc *BlockChain) SetHeadWithTimestamp(timestamp uint64) error {
 		log.Error("Current block not found in database", "block", header.Number, "hash", header.Hash())
 		return fmt.Errorf("current block missing: #%d [%x..]", header.Number, header.Hash().Bytes()[:4])
 	}
-	bc.chainHeadFeed.Send(ChainHeadEvent{Block: block})
+	bc.sendHeadEvent(block)
 	return nil
 }
 
@@ -631,6 +666,7 @@ func (bc *BlockChain) SetFinalized(header *types.Header) {
 	if header != nil {
 		rawdb.WriteFinalizedBlockHash(bc.db, header.Hash())
 		headFinalizedBlockGauge.Update(int64(header.Number.Uint64()))
+		bc.sendHeadUpdate(HeadFinalized, header, nil)
 	} else {
 		rawdb.WriteFinalizedBlockHash(bc.db, common.Hash{})
 		headFinalizedBlockGauge.Update(0)
@@ -643,6 +679,7 @@ func (bc *BlockChain) SetSafe(header *types.Header) {
 	if header != nil {
 		rawdb.WriteSafeBlockHash(bc.db, header.Hash())
 		headSafeBlockGauge.Update(int64(header.Number.Uint64()))
+		bc.sendHeadUpdate(HeadSafe, header, nil)
 	} else {
 		rawdb.WriteSafeBlockHash(bc.db, common.Hash{})
 		headSafeBlockGauge.Update(0)
@@ -951,6 +988,7 @@ func (bc *BlockChain) writeHeadBlock(block *types.Block) {
 	rawdb.WriteHeadFastBlockHash(batch, block.Hash())
 	rawdb.WriteCanonicalHash(batch, block.Hash(), block.NumberU64())
 	rawdb.WriteTxLookupEntriesByBlock(batch, block)
+	bc.writeTxSenderEntries(batch, block)
 	rawdb.WriteHeadBlockHash(batch, block.Hash())
 
 	// Flush the whole batch into the disk, exit the node if failed
@@ -965,6 +1003,12 @@ func (bc *BlockChain) writeHeadBlock(block *types.Block) {
 
 	bc.currentBlock.Store(block.Header())
 	headBlockGauge.Update(int64(block.NumberU64()))
+
+	if bc.chainConfig.IsFeeZero(block.Time()) {
+		rollupZeroFeeGauge.Update(1)
+	} else {
+		rollupZeroFeeGauge.Update(0)
+	}
 }
 
 // stopWithoutSaving stops the blockchain service. If any imports are currently in progress
@@ -1213,8 +1257,9 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 		// range. In this case, all tx indices of newly imported blocks should be
 		// generated.
 		batch := bc.db.NewBatch()
+		txLookupLimit := bc.txLookupLimit.Load()
 		for i, block := range blockChain {
-			if bc.txLookupLimit == 0 || ancientLimit <= bc.txLookupLimit || block.NumberU64() >= ancientLimit-bc.txLookupLimit {
+			if txLookupLimit == 0 || ancientLimit <= txLookupLimit || block.NumberU64() >= ancientLimit-txLookupLimit {
 				rawdb.WriteTxLookupEntriesByBlock(batch, block)
 			} else if rawdb.ReadTxIndexTail(bc.db) != nil {
 				rawdb.WriteTxLookupEntriesByBlock(batch, block)
@@ -1342,10 +1387,10 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 		// * 0: all ancient blocks have been indexed
 		// * ancient-limit: the indices of blocks before ancient-limit are ignored
 		if tail := rawdb.ReadTxIndexTail(bc.db); tail == nil {
-			if bc.txLookupLimit == 0 || ancientLimit <= bc.txLookupLimit {
+			if txLookupLimit := bc.txLookupLimit.Load(); txLookupLimit == 0 || ancientLimit <= txLookupLimit {
 				rawdb.WriteTxIndexTail(bc.db, 0)
 			} else {
-				rawdb.WriteTxIndexTail(bc.db, ancientLimit-bc.txLookupLimit)
+				rawdb.WriteTxIndexTail(bc.db, ancientLimit-txLookupLimit)
 			}
 		}
 	}
@@ -1540,7 +1585,7 @@ func (bc *BlockChain) writeBlockAndSetHead(block *types.Block, receipts []*types
 		// we will fire an accumulated ChainHeadEvent and disable fire
 		// event here.
 		if emitHeadEvent {
-			bc.chainHeadFeed.Send(ChainHeadEvent{Block: block})
+			bc.sendHeadEvent(block)
 		}
 	} else {
 		bc.chainSideFeed.Send(ChainSideEvent{Block: block})
@@ -1626,7 +1671,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error)
 	// Fire a single chain head event if we've progressed the chain
 	defer func() {
 		if lastCanon != nil && bc.CurrentBlock().Hash() == lastCanon.Hash() {
-			bc.chainHeadFeed.Send(ChainHeadEvent{lastCanon})
+			bc.sendHeadEvent(lastCanon)
 		}
 	}()
 	// Start the parallel header verifier
@@ -1801,6 +1846,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error)
 		if err != nil {
 			return it.index, err
 		}
+		statedb.SetWarmCache(bc.warmCache)
 
 		// Enable prefetching to pull in trie node paths while processing transactions
 		statedb.StartPrefetcher("chain")
@@ -1837,6 +1883,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error)
 		vstart := time.Now()
 		if err := bc.validator.ValidateState(block, statedb, receipts, usedGas); err != nil {
 			bc.reportBlock(block, receipts, err)
+			bc.triageBadBlock(block, statedb, receipts, usedGas, err)
 			followupInterrupt.Store(true)
 			return it.index, err
 		}
@@ -1874,6 +1921,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error)
 		if err != nil {
 			return it.index, err
 		}
+		bc.blockUsages.Add(block.Hash(), newBlockUsage(block, usedGas, proctime, statedb.CommitSize))
 		// Update the metrics touched during block commit
 		accountCommitTimer.Update(statedb.AccountCommits)   // Account commits are complete, we can mark them
 		storageCommitTimer.Update(statedb.StorageCommits)   // Storage commits are complete, we can mark them
@@ -1882,6 +1930,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error)
 
 		blockWriteTimer.Update(time.Since(wstart) - statedb.AccountCommits - statedb.StorageCommits - statedb.SnapshotCommits - statedb.TrieDBCommits)
 		blockInsertTimer.UpdateSince(start)
+		bc.blockProcessed.Send(BlockProcessedEvent{Block: block, ProcTime: proctime})
 
 		// Report the import stats before returning the various results
 		stats.processed++
@@ -2249,6 +2298,10 @@ func (bc *BlockChain) reorg(oldHead *types.Header, newHead *types.Block) error {
 		// rewind the canonical chain to a lower point.
 		log.Error("Impossible reorg, please file an issue", "oldnum", oldBlock.Number(), "oldhash", oldBlock.Hash(), "oldblocks", len(oldChain), "newnum", newBlock.Number(), "newhash", newBlock.Hash(), "newblocks", len(newChain))
 	}
+	// Journal the dropped blocks before rewriting the canonical chain, so that the
+	// removal of their logs is not lost if the node stops mid-reorg.
+	bc.journalReorg(oldChain)
+
 	// Insert the new chain(except the head block(reverse order)),
 	// taking care of the proper incremental order.
 	for i := len(newChain) - 1; i >= 1; i-- {
@@ -2372,7 +2425,7 @@ func (bc *BlockChain) SetCanonical(head *types.Block) (common.Hash, error) {
 	if len(logs) > 0 {
 		bc.logsFeed.Send(logs)
 	}
-	bc.chainHeadFeed.Send(ChainHeadEvent{Block: head})
+	bc.sendHeadEvent(head)
 
 	context := []interface{}{
 		"number", head.Number(),
@@ -2446,6 +2499,11 @@ func (bc *BlockChain) skipBlock(err error, it *insertIterator) bool {
 func (bc *BlockChain) indexBlocks(tail *uint64, head uint64, done chan struct{}) {
 	defer func() { close(done) }()
 
+	bc.txIndexing.Store(true)
+	defer bc.txIndexing.Store(false)
+
+	txLookupLimit := bc.txLookupLimit.Load()
+
 	// If head is 0, it means the chain is just initialized and no blocks are inserted,
 	// so don't need to indexing anything.
 	if head == 0 {
@@ -2456,14 +2514,14 @@ func (bc *BlockChain) indexBlocks(tail *uint64, head uint64, done chan struct{})
 	// and all blocks(may from ancient store) are not indexed yet.
 	if tail == nil {
 		from := uint64(0)
-		if bc.txLookupLimit != 0 && head >= bc.txLookupLimit {
-			from = head - bc.txLookupLimit + 1
+		if txLookupLimit != 0 && head >= txLookupLimit {
+			from = head - txLookupLimit + 1
 		}
 		rawdb.IndexTransactions(bc.db, from, head+1, bc.quit)
 		return
 	}
 	// The tail flag is existent, but the whole chain is required to be indexed.
-	if bc.txLookupLimit == 0 || head < bc.txLookupLimit {
+	if txLookupLimit == 0 || head < txLookupLimit {
 		if *tail > 0 {
 			// It can happen when chain is rewound to a historical point which
 			// is even lower than the indexes tail, recap the indexing target
@@ -2477,15 +2535,35 @@ func (bc *BlockChain) indexBlocks(tail *uint64, head uint64, done chan struct{})
 		return
 	}
 	// Update the transaction index to the new chain state
-	if head-bc.txLookupLimit+1 < *tail {
+	if head-txLookupLimit+1 < *tail {
 		// Reindex a part of missing indices and rewind index tail to HEAD-limit
-		rawdb.IndexTransactions(bc.db, head-bc.txLookupLimit+1, *tail, bc.quit)
+		rawdb.IndexTransactions(bc.db, head-txLookupLimit+1, *tail, bc.quit)
 	} else {
 		// Unindex a part of stale indices and forward index tail to HEAD-limit
-		rawdb.UnindexTransactions(bc.db, *tail, head-bc.txLookupLimit+1, bc.quit)
+		rawdb.UnindexTransactions(bc.db, *tail, head-txLookupLimit+1, bc.quit)
 	}
 }
 
+// reindexRange regenerates the transaction indices of the given range, filling
+// gaps left in the index. If the range extends below the index tail, the tail
+// is moved down to its start.
+func (bc *BlockChain) reindexRange(r txIndexRange, done chan struct{}) {
+	defer func() { close(done) }()
+
+	bc.txIndexing.Store(true)
+	defer bc.txIndexing.Store(false)
+
+	tail := rawdb.ReadTxIndexTail(bc.db)
+	rawdb.IndexTransactions(bc.db, r.from, r.to, bc.quit)
+
+	// Indexing moves the tail to the start of the range, restore it if the range
+	// was above the tail
+	if tail != nil && *tail < r.from {
+		rawdb.WriteTxIndexTail(bc.db, *tail)
+	}
+	log.Info("Reindexed transactions", "from", r.from, "to", r.to)
+}
+
 // maintainTxIndex is responsible for the construction and deletion of the
 // transaction index.
 //
@@ -2501,10 +2579,12 @@ func (bc *BlockChain) maintainTxIndex() {
 
 	// Listening to chain events and manipulate the transaction indexes.
 	var (
-		done   chan struct{}                  // Non-nil if background unindexing or reindexing routine is active.
-		headCh = make(chan ChainHeadEvent, 1) // Buffered to avoid locking up the event feed
+		done    chan struct{}                   // Non-nil if background unindexing or reindexing routine is active.
+		headCh  = make(chan HeadUpdateEvent, 1) // Buffered to avoid locking up the event feed
+		ranges  []txIndexRange                  // Reindexing requests waiting for the active routine
+		pending bool                            // Whether the limit changed while a routine was active
 	)
-	sub := bc.SubscribeChainHeadEvent(headCh)
+	sub := bc.SubscribeHeadUpdateEvent(headCh)
 	if sub == nil {
 		return
 	}
@@ -2522,12 +2602,41 @@ func (bc *BlockChain) maintainTxIndex() {
 	for {
 		select {
 		case head := <-headCh:
+			if head.Label != HeadUnsafe {
+				continue
+			}
 			if done == nil {
 				done = make(chan struct{})
-				go bc.indexBlocks(rawdb.ReadTxIndexTail(bc.db), head.Block.NumberU64(), done)
+				go bc.indexBlocks(rawdb.ReadTxIndexTail(bc.db), head.Header.Number.Uint64(), done)
+			}
+		case <-bc.txIndexKick:
+			if done != nil {
+				pending = true
+				continue
+			}
+			done = make(chan struct{})
+			go bc.indexBlocks(rawdb.ReadTxIndexTail(bc.db), bc.CurrentBlock().Number.Uint64(), done)
+
+		case r := <-bc.txIndexRanges:
+			if done != nil {
+				ranges = append(ranges, r)
+				continue
 			}
+			done = make(chan struct{})
+			go bc.reindexRange(r, done)
+
 		case <-done:
 			done = nil
+			switch {
+			case len(ranges) > 0:
+				done = make(chan struct{})
+				go bc.reindexRange(ranges[0], done)
+				ranges = ranges[1:]
+			case pending:
+				pending = false
+				done = make(chan struct{})
+				go bc.indexBlocks(rawdb.ReadTxIndexTail(bc.db), bc.CurrentBlock().Number.Uint64(), done)
+			}
 		case <-bc.quit:
 			if done != nil {
 				log.Info("Waiting background transaction indexer to exit")
@@ -2604,6 +2713,12 @@ func (bc *BlockChain) SetTrieFlushInterval(interval time.Duration) {
 	bc.flushInterval.Store(int64(interval))
 }
 
+// SetDepositCheck enables or disables checking the deposit rules of imported
+// blocks, rejecting the blocks which violate them with a DepositRuleError.
+func (bc *BlockChain) SetDepositCheck(enabled bool) {
+	bc.depositCheck.Store(enabled)
+}
+
 // GetTrieFlushInterval gets the in-memory tries flush interval
 func (bc *BlockChain) GetTrieFlushInterval() time.Duration {
 	return time.Duration(bc.flushInterval.Load())
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// DeposeBlock removes the given canonical block and its descendants from the
+// chain, rewinding the head to its parent so that a replacement block can be
+// built at the same height. This is how interop drops a block including an
+// invalid cross-chain message. The deposed blocks must not be safe nor
+// finalized. Their transaction indexes are removed, and their logs announced
+// as removed.
+func (bc *BlockChain) DeposeBlock(hash common.Hash) (*types.Header, error) {
+	number := bc.hc.GetBlockNumber(hash)
+	if number == nil || rawdb.ReadCanonicalHash(bc.db, *number) != hash {
+		return nil, fmt.Errorf("block %x is not canonical", hash)
+	}
+	if *number == 0 {
+		return nil, errors.New("cannot depose the genesis block")
+	}
+	if safe := bc.CurrentSafeBlock(); safe != nil && safe.Number.Uint64() >= *number {
+		return nil, fmt.Errorf("block #%d is not above the safe block #%d", *number, safe.Number)
+	}
+	if final := bc.CurrentFinalBlock(); final != nil && final.Number.Uint64() >= *number {
+		return nil, fmt.Errorf("block #%d is not above the finalized block #%d", *number, final.Number)
+	}
+	// Gather the deposed blocks, the head first, along with their logs and
+	// transactions before their receipts are deleted by the rewind.
+	var (
+		deposed     types.Blocks
+		deletedLogs []*types.Log
+		txHashes    []common.Hash
+	)
+	for head := bc.CurrentBlock(); head.Number.Uint64() >= *number; {
+		block := bc.GetBlock(head.Hash(), head.Number.Uint64())
+		if block == nil {
+			return nil, fmt.Errorf("canonical block #%d missing", head.Number)
+		}
+		deposed = append(deposed, block)
+		for _, tx := range block.Transactions() {
+			txHashes = append(txHashes, tx.Hash())
+		}
+		head = bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	}
+	for i := len(deposed) - 1; i >= 0; i-- {
+		deletedLogs = append(deletedLogs, bc.collectLogs(deposed[i], true)...)
+	}
+	bc.journalReorg(deposed)
+
+	if err := bc.SetHead(*number - 1); err != nil {
+		return nil, err
+	}
+	// SetHead leaves the transaction indexes in place, drop the ones of the
+	// deposed blocks so they are not served as included.
+	rawdb.DeleteTxLookupEntries(bc.db, txHashes)
+	bc.txLookupCache.Purge()
+
+	for _, block := range deposed {
+		bc.chainSideFeed.Send(ChainSideEvent{Block: block})
+	}
+	if len(deletedLogs) > 0 {
+		bc.rmLogsFeed.Send(RemovedLogsEvent{deletedLogs})
+	}
+	head := bc.CurrentBlock()
+	log.Warn("Deposed canonical block", "number", *number, "hash", hash, "deposed", len(deposed), "head", head.Number, "headhash", head.Hash())
+	return head, nil
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that deposing a block rewinds the chain to its parent, dropping the
+// transaction indexes and announcing the logs of the deposed blocks as removed.
+func TestDeposeBlock(t *testing.T) {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		gspec  = &Genesis{Config: params.TestChainConfig, Alloc: GenesisAlloc{addr: {Balance: big.NewInt(10000000000000000)}}}
+		signer = types.LatestSigner(gspec.Config)
+	)
+	blockchain, _ := NewBlockChain(rawdb.NewMemoryDatabase(), DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer blockchain.Stop()
+
+	var txs []common.Hash
+	_, chain, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 4, func(i int, gen *BlockGen) {
+		tx, err := types.SignTx(types.NewContractCreation(gen.TxNonce(addr), new(big.Int), 1000000, gen.header.BaseFee, logCode), signer, key)
+		if err != nil {
+			t.Fatalf("failed to create tx: %v", err)
+		}
+		gen.AddTx(tx)
+		txs = append(txs, tx.Hash())
+	})
+	if _, err := blockchain.InsertChain(chain); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	removed := make(chan RemovedLogsEvent, 1)
+	sub := blockchain.SubscribeRemovedLogsEvent(removed)
+	defer sub.Unsubscribe()
+
+	// Blocks at or below the safe block can't be deposed
+	blockchain.SetSafe(chain[1].Header())
+	if _, err := blockchain.DeposeBlock(chain[1].Hash()); err == nil {
+		t.Fatal("safe block deposed")
+	}
+	head, err := blockchain.DeposeBlock(chain[2].Hash())
+	if err != nil {
+		t.Fatalf("failed to depose block: %v", err)
+	}
+	if head.Hash() != chain[1].Hash() || blockchain.CurrentBlock().Hash() != chain[1].Hash() {
+		t.Fatalf("head mismatch: have #%d, want #%d", head.Number, chain[1].Number())
+	}
+	for i, hash := range txs {
+		if lookup := rawdb.ReadTxLookupEntry(blockchain.db, hash); (lookup != nil) != (i < 2) {
+			t.Errorf("tx %d: index presence mismatch: have %v, want %v", i, lookup != nil, i < 2)
+		}
+	}
+	select {
+	case ev := <-removed:
+		if len(ev.Logs) != 2 || ev.Logs[0].BlockHash != chain[2].Hash() || ev.Logs[1].BlockHash != chain[3].Hash() {
+			t.Fatalf("removed logs mismatch: %v", ev.Logs)
+		}
+	default:
+		t.Fatal("no removed logs announced")
+	}
+	if _, err := blockchain.DeposeBlock(chain[3].Hash()); err == nil {
+		t.Fatal("non-canonical block deposed")
+	}
+}
@@ -348,6 +348,12 @@ func (bc *BlockChain) Snapshots() *snapshot.Tree {
 	return bc.snaps
 }
 
+// WarmCache returns the recent state reads shared by the block builder and the
+// block import, nil if disabled.
+func (bc *BlockChain) WarmCache() *state.WarmCache {
+	return bc.warmCache
+}
+
 // Validator returns the current validator.
 func (bc *BlockChain) Validator() Validator {
 	return bc.validator
@@ -381,13 +387,13 @@ func (bc *BlockChain) GetVMConfig() *vm.Config {
 // SetTxLookupLimit is responsible for updating the txlookup limit to the
 // original one stored in db if the new mismatches with the old one.
 func (bc *BlockChain) SetTxLookupLimit(limit uint64) {
-	bc.txLookupLimit = limit
+	bc.txLookupLimit.Store(limit)
 }
 
 // TxLookupLimit retrieves the txlookup limit used by blockchain to prune
 // stale transaction indices.
 func (bc *BlockChain) TxLookupLimit() uint64 {
-	return bc.txLookupLimit
+	return bc.txLookupLimit.Load()
 }
 
 // TrieDB retrieves the low level trie database used for data storage.
@@ -410,6 +416,16 @@ func (bc *BlockChain) SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Su
 	return bc.scope.Track(bc.chainHeadFeed.Subscribe(ch))
 }
 
+// SubscribeHeadUpdateEvent registers a subscription of HeadUpdateEvent.
+func (bc *BlockChain) SubscribeHeadUpdateEvent(ch chan<- HeadUpdateEvent) event.Subscription {
+	return bc.scope.Track(bc.headUpdateFeed.Subscribe(ch))
+}
+
+// SubscribeBlockProcessedEvent registers a subscription of BlockProcessedEvent.
+func (bc *BlockChain) SubscribeBlockProcessedEvent(ch chan<- BlockProcessedEvent) event.Subscription {
+	return bc.scope.Track(bc.blockProcessed.Subscribe(ch))
+}
+
 // SubscribeChainSideEvent registers a subscription of ChainSideEvent.
 func (bc *BlockChain) SubscribeChainSideEvent(ch chan<- ChainSideEvent) event.Subscription {
 	return bc.scope.Track(bc.chainSideFeed.Subscribe(ch))
@@ -38,6 +38,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
 )
@@ -4309,6 +4310,56 @@ func TestTxIndexer(t *testing.T) {
 	}
 }
 
+func TestTxIndexerReindexRange(t *testing.T) {
+	var (
+		testBankKey, _  = crypto.GenerateKey()
+		testBankAddress = crypto.PubkeyToAddress(testBankKey.PublicKey)
+		testBankFunds   = big.NewInt(1000000000000000000)
+
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine = ethash.NewFaker()
+		nonce  = uint64(0)
+		limit  = uint64(64)
+	)
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, engine, 128, func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.HexToAddress("0xdeadbeef"), big.NewInt(1000), params.TxGas, big.NewInt(10*params.InitialBaseFee), nil), types.HomesteadSigner{}, testBankKey)
+		gen.AddTx(tx)
+		nonce += 1
+	})
+	indexed := func(db ethdb.Database, number uint64) bool {
+		return rawdb.ReadTxLookupEntry(db, blocks[number-1].Transactions()[0].Hash()) != nil
+	}
+	db, _ := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), t.TempDir(), "", false)
+	defer db.Close()
+	rawdb.WriteAncientBlocks(db, append([]*types.Block{gspec.ToBlock()}, blocks...), append([]types.Receipts{{}}, receipts...), big.NewInt(0))
+
+	chain, _ := NewBlockChain(db, nil, gspec, nil, engine, vm.Config{}, nil, &limit)
+	defer chain.Stop()
+	chain.indexBlocks(nil, 128, make(chan struct{}))
+
+	// Reindexing a gap above the tail must leave the tail untouched
+	rawdb.DeleteTxLookupEntries(db, []common.Hash{blocks[99].Transactions()[0].Hash()})
+	chain.reindexRange(txIndexRange{from: 100, to: 101}, make(chan struct{}))
+	if !indexed(db, 100) {
+		t.Fatal("gap not reindexed")
+	}
+	if tail := rawdb.ReadTxIndexTail(db); tail == nil || *tail != 65 {
+		t.Fatalf("unexpected tail after reindexing: %v", tail)
+	}
+	// Backfilling below the tail must move the tail down
+	chain.reindexRange(txIndexRange{from: 60, to: 65}, make(chan struct{}))
+	if !indexed(db, 60) {
+		t.Fatal("range below the tail not indexed")
+	}
+	if tail := rawdb.ReadTxIndexTail(db); tail == nil || *tail != 60 {
+		t.Fatalf("unexpected tail after backfilling: %v", tail)
+	}
+}
+
 func TestCreateThenDeletePreByzantium(t *testing.T) {
 	// We use Ropsten chain config instead of Testchain config, this is
 	// deliberate: we want to use pre-byz rules where we have intermediate state roots
@@ -4716,3 +4767,31 @@ func TestEIP3651(t *testing.T) {
 		t.Fatalf("sender balance incorrect: expected %d, got %d", expected, actual)
 	}
 }
+
+func TestZeroFeeGauge(t *testing.T) {
+	enabled, gauge := metrics.Enabled, rollupZeroFeeGauge
+	metrics.Enabled = true
+	rollupZeroFeeGauge = metrics.NewGauge()
+	defer func() { metrics.Enabled, rollupZeroFeeGauge = enabled, gauge }()
+
+	// Blocks are 10 seconds apart, the fees are waived until the third one
+	config := *params.TestChainConfig
+	config.ZeroFeeTimes = []uint64{0, 25}
+	gspec := &Genesis{Config: &config}
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 3, nil)
+
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	for i, want := range []int64{1, 1, 0} {
+		if _, err := chain.InsertChain(blocks[i : i+1]); err != nil {
+			t.Fatalf("block %d: failed to insert into chain: %v", i+1, err)
+		}
+		if have := rollupZeroFeeGauge.Snapshot().Value(); have != want {
+			t.Errorf("block %d: zero fee gauge %d, want %d", i+1, have, want)
+		}
+	}
+}
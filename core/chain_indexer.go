@@ -61,6 +61,12 @@ type ChainIndexerChain interface {
 	SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription
 }
 
+// headUpdateChain is implemented by the chains publishing labelled head updates,
+// which the indexer follows instead of the plain chain head events.
+type headUpdateChain interface {
+	SubscribeHeadUpdateEvent(ch chan<- HeadUpdateEvent) event.Subscription
+}
+
 // ChainIndexer does a post-processing job for equally sized sections of the
 // canonical chain (like BlooomBits and CHT structures). A ChainIndexer is
 // connected to the blockchain through the event system by starting a
@@ -147,9 +153,33 @@ func (c *ChainIndexer) AddCheckpoint(section uint64, shead common.Hash) {
 // cascading background processing. Children do not need to be started, they
 // are notified about new events by their parents.
 func (c *ChainIndexer) Start(chain ChainIndexerChain) {
-	events := make(chan ChainHeadEvent, 10)
-	sub := chain.SubscribeChainHeadEvent(events)
-
+	events := make(chan HeadUpdateEvent, 10)
+
+	var sub event.Subscription
+	if hc, ok := chain.(headUpdateChain); ok {
+		sub = hc.SubscribeHeadUpdateEvent(events)
+	} else {
+		sub = event.NewSubscription(func(quit <-chan struct{}) error {
+			heads := make(chan ChainHeadEvent, 10)
+			headSub := chain.SubscribeChainHeadEvent(heads)
+			defer headSub.Unsubscribe()
+
+			for {
+				select {
+				case head := <-heads:
+					select {
+					case events <- HeadUpdateEvent{Label: HeadUnsafe, Header: head.Block.Header()}:
+					case <-quit:
+						return nil
+					}
+				case err := <-headSub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			}
+		})
+	}
 	go c.eventLoop(chain.CurrentHeader(), events, sub)
 }
 
@@ -195,7 +225,7 @@ func (c *ChainIndexer) Close() error {
 // eventLoop is a secondary - optional - event loop of the indexer which is only
 // started for the outermost indexer to push chain head events into a processing
 // queue.
-func (c *ChainIndexer) eventLoop(currentHeader *types.Header, events chan ChainHeadEvent, sub event.Subscription) {
+func (c *ChainIndexer) eventLoop(currentHeader *types.Header, events chan HeadUpdateEvent, sub event.Subscription) {
 	// Mark the chain indexer as active, requiring an additional teardown
 	c.active.Store(true)
 
@@ -222,7 +252,10 @@ func (c *ChainIndexer) eventLoop(currentHeader *types.Header, events chan ChainH
 				errc <- nil
 				return
 			}
-			header := ev.Block.Header()
+			if ev.Label != HeadUnsafe {
+				continue
+			}
+			header := ev.Header
 			if header.ParentHash != prevHash {
 				// Reorg to the common ancestor if needed (might not exist in light sync mode, skip reorg then)
 				// TODO(karalabe, zsfelfoldi): This seems a bit brittle, can we detect this case explicitly?
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// IndexerModule is a custom chain index compiled into the node, registered by
+// the module from an init function with RegisterIndexer.
+//
+// The index is maintained by a ChainIndexer, which only feeds it sections of
+// confirmed canonical headers and checkpoints each committed section. If a
+// reorg deeper than the confirmations invalidates committed sections, they are
+// rolled back and reprocessed, Reset being called again for each of them, so
+// the backend must key its data by section or block for the reprocessing to
+// overwrite the stale entries.
+type IndexerModule struct {
+	Name        string        // Unique name of the index, namespacing its data
+	SectionSize uint64        // Number of blocks of an indexed section
+	Confirms    uint64        // Number of confirmations before a section is indexed
+	Throttling  time.Duration // Pause after each section, sparing the disk while catching up
+
+	// New creates the indexer backend, reading the chain from chainDb and
+	// writing the index into indexDb, a table dedicated to the module.
+	New func(chainDb ethdb.Database, indexDb ethdb.Database) (ChainIndexerBackend, error)
+}
+
+var (
+	indexerModulesLock sync.Mutex
+	indexerModules     = make(map[string]IndexerModule)
+)
+
+// RegisterIndexer registers a custom chain index. It panics if the module is
+// invalid or if its name is already taken.
+func RegisterIndexer(module IndexerModule) {
+	indexerModulesLock.Lock()
+	defer indexerModulesLock.Unlock()
+
+	if !validIndexerName(module.Name) || module.SectionSize == 0 || module.New == nil {
+		panic(fmt.Sprintf("invalid chain indexer module %q", module.Name))
+	}
+	if _, ok := indexerModules[module.Name]; ok {
+		panic(fmt.Sprintf("chain indexer module %q registered twice", module.Name))
+	}
+	indexerModules[module.Name] = module
+}
+
+// validIndexerName reports whether the name is made of lowercase letters, digits
+// and underscores only, so that the tables of the modules never overlap.
+func validIndexerName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// IndexerModules returns the registered custom chain indexes, sorted by name.
+func IndexerModules() []IndexerModule {
+	indexerModulesLock.Lock()
+	defer indexerModulesLock.Unlock()
+
+	modules := make([]IndexerModule, 0, len(indexerModules))
+	for _, module := range indexerModules {
+		modules = append(modules, module)
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Name < modules[j].Name })
+	return modules
+}
+
+// CustomIndexer is a running custom chain index.
+type CustomIndexer struct {
+	*ChainIndexer
+	Module  IndexerModule
+	Backend ChainIndexerBackend
+}
+
+// NewCustomIndexers creates the indexers of the given registered modules. The
+// indexers need to be started with the chain to index.
+func NewCustomIndexers(chainDb ethdb.Database, names []string) ([]*CustomIndexer, error) {
+	indexerModulesLock.Lock()
+	defer indexerModulesLock.Unlock()
+
+	var (
+		indexers []*CustomIndexer
+		seen     = make(map[string]bool)
+	)
+	closeAll := func() {
+		for _, indexer := range indexers {
+			indexer.Close()
+		}
+	}
+	for _, name := range names {
+		module, ok := indexerModules[name]
+		if !ok {
+			closeAll()
+			return nil, fmt.Errorf("unknown chain indexer module %q", name)
+		}
+		if seen[name] {
+			closeAll()
+			return nil, fmt.Errorf("chain indexer module %q enabled twice", name)
+		}
+		seen[name] = true
+		indexDb := rawdb.NewTable(chainDb, string(rawdb.CustomIndexPrefix)+name+"-")
+		backend, err := module.New(chainDb, indexDb)
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("chain indexer module %q: %w", name, err)
+		}
+		if backend == nil {
+			closeAll()
+			return nil, fmt.Errorf("chain indexer module %q created no backend", name)
+		}
+		indexers = append(indexers, &CustomIndexer{
+			ChainIndexer: NewChainIndexer(chainDb, indexDb, backend, module.SectionSize, module.Confirms, module.Throttling, name),
+			Module:       module,
+			Backend:      backend,
+		})
+	}
+	return indexers, nil
+}
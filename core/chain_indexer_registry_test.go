@@ -0,0 +1,98 @@
+package core
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// testCountIndex counts the headers of each section into its index table.
+type testCountIndex struct {
+	db      ethdb.Database
+	section uint64
+	count   uint64
+}
+
+func (b *testCountIndex) Reset(ctx context.Context, section uint64, prevHead common.Hash) error {
+	b.section, b.count = section, 0
+	return nil
+}
+
+func (b *testCountIndex) Process(ctx context.Context, header *types.Header) error {
+	b.count++
+	return nil
+}
+
+func (b *testCountIndex) Commit() error {
+	var key, val [8]byte
+	binary.BigEndian.PutUint64(key[:], b.section)
+	binary.BigEndian.PutUint64(val[:], b.count)
+	return b.db.Put(key[:], val[:])
+}
+
+func (b *testCountIndex) Prune(threshold uint64) error {
+	return nil
+}
+
+func TestCustomIndexers(t *testing.T) {
+	RegisterIndexer(IndexerModule{
+		Name:        "test_count",
+		SectionSize: 8,
+		Confirms:    2,
+		New: func(chainDb ethdb.Database, indexDb ethdb.Database) (ChainIndexerBackend, error) {
+			return &testCountIndex{db: indexDb}, nil
+		},
+	})
+	db := rawdb.NewMemoryDatabase()
+	defer db.Close()
+
+	if _, err := NewCustomIndexers(db, []string{"test_missing"}); err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Fatalf("unknown module not rejected: %v", err)
+	}
+	if _, err := NewCustomIndexers(db, []string{"test_count", "test_count"}); err == nil || !strings.Contains(err.Error(), "twice") {
+		t.Fatalf("duplicate module not rejected: %v", err)
+	}
+	indexers, err := NewCustomIndexers(db, []string{"test_count"})
+	if err != nil {
+		t.Fatalf("failed to create indexers: %v", err)
+	}
+	indexer := indexers[0]
+	defer indexer.Close()
+
+	var parent common.Hash
+	for i := uint64(0); i < 20; i++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(i), ParentHash: parent}
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteCanonicalHash(db, header.Hash(), i)
+		parent = header.Hash()
+	}
+	// Blocks 0-17 are confirmed, only the first two sections must be indexed
+	indexer.newHead(19, false)
+	for i := 0; i < 300; i++ {
+		if sections, _, _ := indexer.Sections(); sections == 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if sections, _, _ := indexer.Sections(); sections != 2 {
+		t.Fatalf("indexed section count mismatch: have %d, want 2", sections)
+	}
+	// The index must have been written to the table of the module
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], 1)
+	val, err := db.Get(append([]byte(string(rawdb.CustomIndexPrefix)+"test_count-"), key[:]...))
+	if err != nil {
+		t.Fatalf("failed to read the index: %v", err)
+	}
+	if count := binary.BigEndian.Uint64(val); count != 8 {
+		t.Fatalf("indexed header count mismatch: have %d, want 8", count)
+	}
+}
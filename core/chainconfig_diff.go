@@ -0,0 +1,204 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Classes of the differences between a stored chain config and a new one.
+const (
+	ConfigChangeSafe   = "safe"   // Fork scheduled in the future, applicable right away
+	ConfigChangeRewind = "rewind" // Fork already activated, applicable after rewinding the chain
+	ConfigChangeReview = "review" // Parameter other than a fork schedule, to be reviewed
+)
+
+// ChainConfigChange is a difference between a stored chain config and a new one.
+type ChainConfigChange struct {
+	Field  string      `json:"field"` // JSON path of the field
+	Stored interface{} `json:"stored"`
+	New    interface{} `json:"new"`
+	Class  string      `json:"class"`
+}
+
+// ChainConfigDiff is the comparison of a stored chain config with a new one,
+// against the head of the chain.
+type ChainConfigDiff struct {
+	Changes []*ChainConfigChange `json:"changes"`
+
+	// Compat is the incompatibility reported by the config compatibility check,
+	// nil if the new config is compatible with the chain.
+	Compat *params.ConfigCompatError `json:"compat,omitempty"`
+	// CompatError is the compatibility check failure other than a fork conflict.
+	CompatError string `json:"compatError,omitempty"`
+}
+
+// Safe reports whether the new config can be stored without rewinding the chain
+// nor reviewing parameter changes.
+func (d *ChainConfigDiff) Safe() bool {
+	if d.Compat != nil || d.CompatError != "" {
+		return false
+	}
+	for _, change := range d.Changes {
+		if change.Class != ConfigChangeSafe {
+			return false
+		}
+	}
+	return true
+}
+
+// Rewind reports whether a change requires rewinding the chain.
+func (d *ChainConfigDiff) Rewind() bool {
+	if d.Compat != nil {
+		return true
+	}
+	for _, change := range d.Changes {
+		if change.Class == ConfigChangeRewind {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffChainConfig compares the stored chain config with a new one, classifying
+// each difference against the given head. Fork blocks and timestamps, i.e. the
+// numeric fields whose name ends with Block, Time or Times, are safe to change
+// if neither their stored nor their new value is activated at the head.
+func DiffChainConfig(stored, newcfg *params.ChainConfig, head *types.Header) (*ChainConfigDiff, error) {
+	storedFields, err := flattenChainConfig(stored)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flattenChainConfig(newcfg)
+	if err != nil {
+		return nil, err
+	}
+	paths := make(map[string]struct{})
+	for path := range storedFields {
+		paths[path] = struct{}{}
+	}
+	for path := range newFields {
+		paths[path] = struct{}{}
+	}
+	diff := new(ChainConfigDiff)
+	for path := range paths {
+		before, after := storedFields[path], newFields[path]
+		if jsonEqual(before, after) {
+			continue
+		}
+		diff.Changes = append(diff.Changes, &ChainConfigChange{
+			Field:  path,
+			Stored: before,
+			New:    after,
+			Class:  classifyConfigChange(path, before, after, head),
+		})
+	}
+	sort.Slice(diff.Changes, func(i, j int) bool { return diff.Changes[i].Field < diff.Changes[j].Field })
+
+	if err := stored.CheckCompatible(newcfg, head.Number.Uint64(), head.Time); err != nil {
+		if compat, ok := err.(*params.ConfigCompatError); ok {
+			diff.Compat = compat
+		} else {
+			diff.CompatError = err.Error()
+		}
+	}
+	return diff, nil
+}
+
+// classifyConfigChange classifies the change of the field at the given path.
+func classifyConfigChange(path string, before, after interface{}, head *types.Header) string {
+	name := path
+	if i := strings.LastIndexAny(name, ".["); i >= 0 && name[i] == '[' {
+		name = name[:i] // element of a list of timestamps
+	}
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	var current *big.Int
+	switch {
+	case strings.HasSuffix(name, "Block"):
+		current = head.Number
+	case strings.HasSuffix(name, "Time"), strings.HasSuffix(name, "Times"):
+		current = new(big.Int).SetUint64(head.Time)
+	default:
+		return ConfigChangeReview
+	}
+	beforeAt, ok := configActivation(before)
+	if !ok {
+		return ConfigChangeReview
+	}
+	afterAt, ok := configActivation(after)
+	if !ok {
+		return ConfigChangeReview
+	}
+	if (beforeAt != nil && beforeAt.Cmp(current) <= 0) || (afterAt != nil && afterAt.Cmp(current) <= 0) {
+		return ConfigChangeRewind
+	}
+	return ConfigChangeSafe
+}
+
+// configActivation returns the fork activation of a config value, nil if unset.
+// It reports false if the value isn't a fork activation.
+func configActivation(value interface{}) (*big.Int, bool) {
+	if value == nil {
+		return nil, true
+	}
+	number, ok := value.(json.Number)
+	if !ok {
+		return nil, false
+	}
+	at, ok := new(big.Int).SetString(number.String(), 10)
+	return at, ok
+}
+
+// flattenChainConfig returns the leaf values of the JSON encoding of the config
+// by path, list elements being indexed.
+func flattenChainConfig(config *params.ChainConfig) (map[string]interface{}, error) {
+	blob, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(blob))
+	dec.UseNumber()
+
+	var root interface{}
+	if err := dec.Decode(&root); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	var flatten func(prefix string, value interface{})
+	flatten = func(prefix string, value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for key, elem := range v {
+				path := key
+				if prefix != "" {
+					path = prefix + "." + key
+				}
+				flatten(path, elem)
+			}
+		case []interface{}:
+			for i, elem := range v {
+				flatten(fmt.Sprintf("%s[%d]", prefix, i), elem)
+			}
+		default:
+			if v != nil {
+				fields[prefix] = v
+			}
+		}
+	}
+	flatten("", root)
+	return fields, nil
+}
+
+func jsonEqual(a, b interface{}) bool {
+	ablob, _ := json.Marshal(a)
+	bblob, _ := json.Marshal(b)
+	return bytes.Equal(ablob, bblob)
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestDiffChainConfig(t *testing.T) {
+	head := &types.Header{Number: big.NewInt(100), Time: 1000}
+	stored := &params.ChainConfig{
+		ChainID:      big.NewInt(1),
+		LondonBlock:  big.NewInt(0),
+		ShanghaiTime: u64(0),
+		ZeroFeeTimes: []uint64{500},
+	}
+	tests := []struct {
+		name   string
+		modify func(*params.ChainConfig)
+		field  string
+		class  string
+		safe   bool
+		rewind bool
+	}{
+		{"future fork added", func(c *params.ChainConfig) { c.CancunTime = u64(2000) }, "cancunTime", ConfigChangeSafe, true, false},
+		{"future block fork added", func(c *params.ChainConfig) { c.BerlinBlock = big.NewInt(200) }, "berlinBlock", ConfigChangeSafe, true, false},
+		{"future list entry added", func(c *params.ChainConfig) { c.ZeroFeeTimes = []uint64{500, 3000} }, "zeroFeeTimes[1]", ConfigChangeSafe, true, false},
+		{"activated fork moved", func(c *params.ChainConfig) { c.ShanghaiTime = u64(2000) }, "shanghaiTime", ConfigChangeRewind, false, true},
+		{"past fork added", func(c *params.ChainConfig) { c.CancunTime = u64(900) }, "cancunTime", ConfigChangeRewind, false, true},
+		{"parameter changed", func(c *params.ChainConfig) { c.TerminalTotalDifficulty = big.NewInt(1) }, "terminalTotalDifficulty", ConfigChangeReview, false, false},
+	}
+	for _, test := range tests {
+		newcfg := *stored
+		newcfg.ZeroFeeTimes = append([]uint64{}, stored.ZeroFeeTimes...)
+		test.modify(&newcfg)
+
+		diff, err := DiffChainConfig(stored, &newcfg, head)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if len(diff.Changes) != 1 {
+			t.Fatalf("%s: change count mismatch: have %d, want 1", test.name, len(diff.Changes))
+		}
+		if change := diff.Changes[0]; change.Field != test.field || change.Class != test.class {
+			t.Errorf("%s: change mismatch: have %s %s, want %s %s", test.name, change.Class, change.Field, test.class, test.field)
+		}
+		if diff.Safe() != test.safe {
+			t.Errorf("%s: safe mismatch: have %v, want %v", test.name, diff.Safe(), test.safe)
+		}
+		if diff.Rewind() != test.rewind {
+			t.Errorf("%s: rewind mismatch: have %v, want %v", test.name, diff.Rewind(), test.rewind)
+		}
+	}
+	diff, err := DiffChainConfig(stored, stored, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Changes) != 0 || !diff.Safe() {
+		t.Errorf("identical configs differ: %d changes", len(diff.Changes))
+	}
+}
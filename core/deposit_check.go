@@ -0,0 +1,126 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Deposit rules checked on import when the deposit checker is enabled.
+const (
+	DepositRuleL1Info   = "l1-info-first"    // Blocks start with the L1 attributes deposit
+	DepositRuleOrder    = "deposits-first"   // Deposits precede all other transactions
+	DepositRuleSystemTx = "system-tx"        // isSystemTx usage matches the active fork
+	DepositRuleNonce    = "deposit-nonce"    // Deposit nonces are recorded and continuous
+	DepositRuleVersion  = "receipt-version"  // Deposit receipts carry the version of the active fork
+	DepositRuleReceipts = "receipt-coverage" // Every transaction has a receipt
+)
+
+var depositViolationMeter = metrics.NewRegisteredMeter("chain/deposits/violation", nil)
+
+// DepositRuleError is returned when an imported block violates one of the rules
+// deposits are subject to, identifying the offending transaction.
+type DepositRuleError struct {
+	Rule   string      // Rule violated
+	Index  int         // Index of the offending transaction
+	TxHash common.Hash // Hash of the offending transaction
+	Reason string      // Details of the violation
+}
+
+func (e *DepositRuleError) Error() string {
+	return fmt.Sprintf("deposit rule %q violated by tx %d (%x): %s", e.Rule, e.Index, e.TxHash, e.Reason)
+}
+
+func newDepositRuleError(rule string, index int, tx *types.Transaction, format string, args ...interface{}) *DepositRuleError {
+	depositViolationMeter.Mark(1)
+	return &DepositRuleError{Rule: rule, Index: index, TxHash: tx.Hash(), Reason: fmt.Sprintf(format, args...)}
+}
+
+// ValidateDepositOrder checks the placement of deposits in a block and their use
+// of isSystemTx: blocks start with the L1 attributes deposit, followed by the user
+// deposits and then the other transactions. Before Regolith only the L1 attributes
+// deposit is a system transaction, afterwards none is.
+func ValidateDepositOrder(config *params.ChainConfig, block *types.Block) error {
+	if !config.IsOptimismBedrock(block.Number()) {
+		return nil
+	}
+	txs := block.Transactions()
+	if len(txs) == 0 {
+		return &DepositRuleError{Rule: DepositRuleL1Info, Index: 0, Reason: "block has no transactions"}
+	}
+	if !txs[0].IsDepositTx() {
+		return newDepositRuleError(DepositRuleL1Info, 0, txs[0], "first transaction is not a deposit")
+	}
+	regolith := config.IsOptimismRegolith(block.Time())
+	deposits := true
+	for i, tx := range txs {
+		if !tx.IsDepositTx() {
+			deposits = false
+			continue
+		}
+		if !deposits {
+			return newDepositRuleError(DepositRuleOrder, i, tx, "deposit after non-deposit transaction")
+		}
+		switch {
+		case regolith && tx.IsSystemTx():
+			return newDepositRuleError(DepositRuleSystemTx, i, tx, "system transaction after Regolith")
+		case !regolith && i == 0 && !tx.IsSystemTx():
+			return newDepositRuleError(DepositRuleSystemTx, i, tx, "L1 attributes deposit is not a system transaction before Regolith")
+		case !regolith && i > 0 && tx.IsSystemTx():
+			return newDepositRuleError(DepositRuleSystemTx, i, tx, "user deposit is a system transaction")
+		}
+	}
+	return nil
+}
+
+// ValidateDepositReceipts checks the receipts of the deposits in a block: after
+// Regolith they record the nonce used, which needs to be continuous per sender
+// including the transactions following the deposits, and after Canyon they carry
+// the receipt version.
+func ValidateDepositReceipts(config *params.ChainConfig, block *types.Block, receipts types.Receipts) error {
+	if !config.IsOptimismBedrock(block.Number()) {
+		return nil
+	}
+	txs := block.Transactions()
+	if len(receipts) != len(txs) {
+		return &DepositRuleError{Rule: DepositRuleReceipts, Index: len(receipts), Reason: fmt.Sprintf("%d receipts for %d transactions", len(receipts), len(txs))}
+	}
+	var (
+		regolith = config.IsOptimismRegolith(block.Time())
+		canyon   = config.IsOptimismCanyon(block.Time())
+		signer   = types.MakeSigner(config, block.Number(), block.Time())
+		nonces   = make(map[common.Address]uint64) // Next nonce expected per deposit sender
+	)
+	for i, tx := range txs {
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return newDepositRuleError(DepositRuleNonce, i, tx, "invalid sender: %v", err)
+		}
+		if !tx.IsDepositTx() {
+			if next, ok := nonces[from]; ok && tx.Nonce() != next {
+				return newDepositRuleError(DepositRuleNonce, i, tx, "nonce %d does not follow deposits of %x, want %d", tx.Nonce(), from, next)
+			}
+			delete(nonces, from)
+			continue
+		}
+		receipt := receipts[i]
+		if canyon && (receipt.DepositReceiptVersion == nil || *receipt.DepositReceiptVersion != types.CanyonDepositReceiptVersion) {
+			return newDepositRuleError(DepositRuleVersion, i, tx, "missing Canyon deposit receipt version")
+		}
+		if !regolith {
+			continue
+		}
+		if receipt.DepositNonce == nil {
+			return newDepositRuleError(DepositRuleNonce, i, tx, "missing deposit nonce")
+		}
+		nonce := *receipt.DepositNonce
+		if next, ok := nonces[from]; ok && nonce != next {
+			return newDepositRuleError(DepositRuleNonce, i, tx, "nonce %d of %x not continuous, want %d", nonce, from, next)
+		}
+		nonces[from] = nonce + 1
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func depositTestConfig(regolith bool) *params.ChainConfig {
+	config := *params.OptimismTestConfig
+	config.BedrockBlock = big.NewInt(0)
+	if regolith {
+		config.RegolithTime = new(uint64)
+	}
+	return &config
+}
+
+func newDeposit(from common.Address, system bool) *types.Transaction {
+	return types.NewTx(&types.DepositTx{From: from, Value: new(big.Int), Gas: 100000, IsSystemTransaction: system})
+}
+
+func depositTestBlock(txs ...*types.Transaction) *types.Block {
+	return types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), Time: 1}).WithBody(txs, nil)
+}
+
+func TestValidateDepositOrder(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	config := depositTestConfig(true)
+	signer := types.LatestSigner(config)
+	user, _ := types.SignTx(types.NewTransaction(0, common.Address{}, new(big.Int), params.TxGas, big.NewInt(1), nil), signer, key)
+
+	tests := []struct {
+		regolith bool
+		txs      []*types.Transaction
+		rule     string
+	}{
+		{true, []*types.Transaction{newDeposit(common.Address{1}, false), newDeposit(common.Address{2}, false), user}, ""},
+		{false, []*types.Transaction{newDeposit(common.Address{1}, true), newDeposit(common.Address{2}, false), user}, ""},
+		{true, []*types.Transaction{user}, DepositRuleL1Info},
+		{true, []*types.Transaction{newDeposit(common.Address{1}, false), user, newDeposit(common.Address{2}, false)}, DepositRuleOrder},
+		{true, []*types.Transaction{newDeposit(common.Address{1}, true)}, DepositRuleSystemTx},
+		{false, []*types.Transaction{newDeposit(common.Address{1}, false)}, DepositRuleSystemTx},
+		{false, []*types.Transaction{newDeposit(common.Address{1}, true), newDeposit(common.Address{2}, true)}, DepositRuleSystemTx},
+	}
+	for i, tt := range tests {
+		err := ValidateDepositOrder(depositTestConfig(tt.regolith), depositTestBlock(tt.txs...))
+		if tt.rule == "" {
+			if err != nil {
+				t.Errorf("test %d: unexpected error: %v", i, err)
+			}
+			continue
+		}
+		var ruleErr *DepositRuleError
+		if !errors.As(err, &ruleErr) || ruleErr.Rule != tt.rule {
+			t.Errorf("test %d: rule mismatch: have %v, want %s", i, err, tt.rule)
+		}
+	}
+}
+
+func TestValidateDepositReceipts(t *testing.T) {
+	config := depositTestConfig(true)
+	sender := common.Address{1}
+	block := depositTestBlock(newDeposit(common.Address{0xff}, false), newDeposit(sender, false), newDeposit(sender, false))
+
+	receipts := func(nonces ...uint64) types.Receipts {
+		var receipts types.Receipts
+		for _, nonce := range nonces {
+			nonce := nonce
+			receipts = append(receipts, &types.Receipt{DepositNonce: &nonce})
+		}
+		return receipts
+	}
+	if err := ValidateDepositReceipts(config, block, receipts(0, 4, 5)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ruleErr *DepositRuleError
+	if err := ValidateDepositReceipts(config, block, receipts(0, 4, 6)); !errors.As(err, &ruleErr) || ruleErr.Rule != DepositRuleNonce || ruleErr.Index != 2 {
+		t.Errorf("discontinuous nonce not detected: %v", err)
+	}
+	missing := receipts(0, 4, 5)
+	missing[1].DepositNonce = nil
+	if err := ValidateDepositReceipts(config, block, missing); !errors.As(err, &ruleErr) || ruleErr.Rule != DepositRuleNonce || ruleErr.Index != 1 {
+		t.Errorf("missing nonce not detected: %v", err)
+	}
+	if err := ValidateDepositReceipts(config, block, receipts(0, 4)); !errors.As(err, &ruleErr) || ruleErr.Rule != DepositRuleReceipts {
+		t.Errorf("missing receipt not detected: %v", err)
+	}
+}
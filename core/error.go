@@ -32,6 +32,22 @@ var (
 	// ErrNoGenesis is returned when there is no Genesis Block.
 	ErrNoGenesis = errors.New("genesis not found in chain")
 
+	// ErrBlockTooLarge is returned if the RLP-encoded size of a block exceeds the
+	// limit set by the chain config.
+	ErrBlockTooLarge = errors.New("block too large")
+
+	// ErrTxExecutionAborted is returned if the execution of a transaction was
+	// cancelled before its completion, leaving no valid state transition.
+	ErrTxExecutionAborted = errors.New("transaction execution aborted")
+
+	// ErrGasDimensionExceeded is returned if the resources used by a block exceed
+	// the limit of one of the configured gas dimensions.
+	ErrGasDimensionExceeded = errors.New("gas dimension limit exceeded")
+
+	// ErrStateGrowthExceeded is returned if the net number of accounts and storage
+	// slots created by a block exceeds the configured limit.
+	ErrStateGrowthExceeded = errors.New("state growth limit exceeded")
+
 	errSideChainReceipts = errors.New("side blocks can't be accepted as ancient chain data")
 )
 
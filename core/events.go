@@ -17,6 +17,8 @@
 package core
 
 import (
+	"time"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
@@ -40,4 +42,14 @@ type ChainSideEvent struct {
 	Block *types.Block
 }
 
-type ChainHeadEvent struct{ Block *types.Block }
+type ChainHeadEvent struct {
+	Block *types.Block
+	Usage *BlockUsage // Resources used by the import of the block, nil if not imported since startup
+}
+
+// BlockProcessedEvent is posted when an imported block has been executed and
+// validated, along with the time it took.
+type BlockProcessedEvent struct {
+	Block    *types.Block
+	ProcTime time.Duration // Time spent executing and validating the block
+}
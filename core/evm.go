@@ -74,6 +74,8 @@ func NewEVMBlockContext(header *types.Header, chain ChainContext, author *common
 		GasLimit:    header.GasLimit,
 		Random:      random,
 		L1CostFunc:  types.NewL1CostFunc(config, statedb),
+
+		OperatorCostFunc: types.NewOperatorCostFunc(config, statedb),
 	}
 }
 
@@ -0,0 +1,478 @@
+package filtermaps
+
+import (
+	"context"
+	"errors"
+	"math/bits"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// MapBlocks is the number of consecutive blocks covered by a filter map, the
+// unit in which the log index is pruned.
+const MapBlocks = 2048
+
+// valueKeyLength is the length of the keys of the log values in the index. The
+// keys are truncated hashes, collisions only causing false positives.
+const valueKeyLength = 8
+
+// ErrNotIndexed is returned if the requested blocks are not, or no longer,
+// covered by the log index.
+var ErrNotIndexed = errors.New("blocks not covered by the log index")
+
+// LogIndex is an index of the log addresses and topics of a range of blocks,
+// narrowing down the blocks a range filter needs to inspect.
+type LogIndex interface {
+	// IndexedRange returns the first and last blocks covered by the index, ok
+	// being false if no block is indexed yet.
+	IndexedRange() (first, last uint64, ok bool)
+
+	// Candidates returns the blocks of the given range possibly emitting logs
+	// that match the filter, in ascending order. It may return blocks without
+	// matching logs but never omits one that has any. ErrNotIndexed is returned
+	// if the range is not, or no longer, covered by the index.
+	Candidates(ctx context.Context, begin, end uint64, addresses []common.Address, topics [][]common.Hash) ([]uint64, error)
+}
+
+// Chain is the blockchain the log index is built for.
+type Chain interface {
+	CurrentBlock() *types.Header
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// indexRange is the range of blocks covered by the log index.
+type indexRange struct {
+	Tail     uint64      // First indexed block
+	Next     uint64      // First block not yet indexed
+	LastHash common.Hash // Hash of the last indexed block, detecting reorgs
+}
+
+// FilterMaps is an index of the addresses and topics of the logs emitted by a
+// rolling window of recent blocks, aligned with the transaction history. The
+// blocks are grouped into maps of MapBlocks blocks, each map holding a row per
+// log value listing the blocks of the map emitting it, so that a filter only
+// inspects the blocks whose rows match all of its criteria. The maps falling
+// out of the window are dropped as a whole as the chain progresses.
+//
+// The index may report blocks not emitting a matching log, either due to key
+// collisions or to the rows of the blocks dropped by reorgs, which are left
+// behind. It never omits a matching block of the range it covers.
+type FilterMaps struct {
+	db      ethdb.Database
+	chain   Chain
+	history uint64 // Number of recent blocks to index, zero for the whole chain
+
+	lock sync.RWMutex
+	rng  indexRange
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a log index over the given number of recent blocks of the chain,
+// or over the whole chain if zero.
+func New(db ethdb.Database, chain Chain, history uint64) *FilterMaps {
+	f := &FilterMaps{
+		db:      db,
+		chain:   chain,
+		history: history,
+		quit:    make(chan struct{}),
+	}
+	if blob := rawdb.ReadFilterMapsRange(db); len(blob) > 0 {
+		if err := rlp.DecodeBytes(blob, &f.rng); err != nil {
+			log.Error("Invalid log index range, reindexing", "err", err)
+			f.rng = indexRange{}
+		}
+	}
+	return f
+}
+
+// Start starts maintaining the log index in the background.
+func (f *FilterMaps) Start() {
+	f.wg.Add(1)
+	go f.loop()
+}
+
+// Stop stops maintaining the log index.
+func (f *FilterMaps) Stop() {
+	close(f.quit)
+	f.wg.Wait()
+}
+
+// IndexedRange returns the first and last blocks covered by the log index, ok
+// being false if no block is indexed yet.
+func (f *FilterMaps) IndexedRange() (first, last uint64, ok bool) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	if f.rng.Next <= f.rng.Tail {
+		return 0, 0, false
+	}
+	return f.rng.Tail, f.rng.Next - 1, true
+}
+
+// covers reports whether the log index covers the given blocks of the current
+// canonical chain.
+func (f *FilterMaps) covers(begin, end uint64) bool {
+	f.lock.RLock()
+	rng := f.rng
+	f.lock.RUnlock()
+
+	if begin < rng.Tail || end >= rng.Next {
+		return false
+	}
+	// The index is rolled back lazily after reorgs, don't trust it meanwhile
+	return rawdb.ReadCanonicalHash(f.db, rng.Next-1) == rng.LastHash
+}
+
+// Candidates returns the blocks of the given range possibly emitting logs that
+// match the filter, in ascending order. ErrNotIndexed is returned if the range
+// is not covered by the log index.
+func (f *FilterMaps) Candidates(ctx context.Context, begin, end uint64, addresses []common.Address, topics [][]common.Hash) ([]uint64, error) {
+	if !f.covers(begin, end) {
+		return nil, ErrNotIndexed
+	}
+	// Gather the value keys of each criterion, an empty one matching anything
+	var criteria [][][]byte
+	if len(addresses) > 0 {
+		values := make([][]byte, len(addresses))
+		for i, address := range addresses {
+			values[i] = addressValue(address)
+		}
+		criteria = append(criteria, values)
+	}
+	for _, sub := range topics {
+		if len(sub) == 0 {
+			continue
+		}
+		values := make([][]byte, len(sub))
+		for i, topic := range sub {
+			values[i] = topicValue(topic)
+		}
+		criteria = append(criteria, values)
+	}
+	var matches []uint64
+	for mapIndex := begin / MapBlocks; mapIndex <= end/MapBlocks; mapIndex++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var (
+			first = mapIndex * MapBlocks
+			from  = uint16(0)
+			to    = uint16(MapBlocks - 1)
+		)
+		if begin > first {
+			from = uint16(begin - first)
+		}
+		if end < first+MapBlocks-1 {
+			to = uint16(end - first)
+		}
+		blocks := newBlockSet(from, to)
+		for _, values := range criteria {
+			var rows blockSet
+			for _, value := range values {
+				for _, offset := range rawdb.ReadFilterMapRow(f.db, mapIndex, value) {
+					rows.add(offset)
+				}
+			}
+			if !blocks.intersect(&rows) {
+				break
+			}
+		}
+		for _, offset := range blocks.offsets() {
+			matches = append(matches, first+uint64(offset))
+		}
+	}
+	// Rows pruned or rolled back during the search might have been missed
+	if !f.covers(begin, end) {
+		return nil, ErrNotIndexed
+	}
+	return matches, nil
+}
+
+// loop updates the log index as the chain progresses. The updates run in the
+// background not to hold up the head events.
+func (f *FilterMaps) loop() {
+	defer f.wg.Done()
+
+	var (
+		done    chan struct{} // Non-nil if an update is running
+		pending bool          // Whether the head moved during the running update
+		headCh  = make(chan core.ChainHeadEvent, 1)
+	)
+	sub := f.chain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	done = make(chan struct{})
+	go f.update(done)
+	for {
+		select {
+		case <-headCh:
+			if done != nil {
+				pending = true
+				continue
+			}
+			done = make(chan struct{})
+			go f.update(done)
+		case <-done:
+			done = nil
+			if pending {
+				pending = false
+				done = make(chan struct{})
+				go f.update(done)
+			}
+		case <-sub.Err():
+			if done != nil {
+				<-done
+			}
+			return
+		case <-f.quit:
+			if done != nil {
+				<-done
+			}
+			return
+		}
+	}
+}
+
+// update rolls back the blocks dropped by reorgs, indexes the new head blocks,
+// drops the maps past the history window and, if the window grew, indexes the
+// older blocks.
+func (f *FilterMaps) update(done chan struct{}) {
+	defer close(done)
+
+	head := f.chain.CurrentBlock()
+	if head == nil {
+		return
+	}
+	var (
+		number = head.Number.Uint64()
+		target uint64 // First block to index
+		start  = time.Now()
+	)
+	if f.history != 0 && number+1 > f.history {
+		target = number + 1 - f.history
+	}
+	f.lock.RLock()
+	rng := f.rng
+	f.lock.RUnlock()
+
+	rng = f.revert(rng)
+	if rng.Next < target || rng.Next == rng.Tail {
+		// Nothing left worth keeping, start over at the target
+		if rng.Tail < target {
+			f.setRange(indexRange{Tail: target, Next: target})
+			rawdb.DeleteFilterMaps(f.db, rng.Tail/MapBlocks, target/MapBlocks)
+		}
+		rng = indexRange{Tail: target, Next: target}
+	}
+	indexed := rng.Next
+	if rng = f.extendHead(rng, number); rng.Next > indexed {
+		log.Debug("Updated log index head", "blocks", rng.Next-indexed, "head", rng.Next-1, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+	// Drop the maps entirely past the history window. The range is moved
+	// first, so that the searches don't trust the deleted rows.
+	if limit := target / MapBlocks; rng.Tail/MapBlocks < limit && limit*MapBlocks <= rng.Next {
+		first := rng.Tail / MapBlocks
+		rng.Tail = limit * MapBlocks
+		f.setRange(rng)
+		rawdb.DeleteFilterMaps(f.db, first, limit)
+		log.Debug("Pruned log index", "maps", limit-first, "tail", rng.Tail)
+	}
+	if rng.Tail > target {
+		f.extendTail(rng, target)
+	}
+}
+
+// revert rolls the head of the range back to the last indexed block still
+// canonical. The rows of the dropped blocks are left behind.
+func (f *FilterMaps) revert(rng indexRange) indexRange {
+	var (
+		reverted = rng
+		hash     = rng.LastHash
+	)
+	for reverted.Next > reverted.Tail && rawdb.ReadCanonicalHash(f.db, reverted.Next-1) != hash {
+		header := rawdb.ReadHeader(f.db, hash, reverted.Next-1)
+		if header == nil {
+			reverted.Next = reverted.Tail
+			break
+		}
+		reverted.Next, hash = reverted.Next-1, header.ParentHash
+	}
+	if reverted.Next == rng.Next {
+		return rng
+	}
+	reverted.LastHash = hash
+	if reverted.Next == reverted.Tail {
+		reverted.LastHash = common.Hash{}
+	}
+	f.setRange(reverted)
+	log.Debug("Rolled back log index", "blocks", rng.Next-reverted.Next, "head", reverted.Next)
+	return reverted
+}
+
+// extendHead indexes the blocks following the range up to the given head.
+func (f *FilterMaps) extendHead(rng indexRange, head uint64) indexRange {
+	batch := f.db.NewBatch()
+	for ; rng.Next <= head; rng.Next++ {
+		select {
+		case <-f.quit:
+			f.commit(batch, rng)
+			return rng
+		default:
+		}
+		hash := rawdb.ReadCanonicalHash(f.db, rng.Next)
+		if !f.indexBlock(batch, rng.Next, hash) {
+			break
+		}
+		rng.LastHash = hash
+
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			f.commit(batch, indexRange{Tail: rng.Tail, Next: rng.Next + 1, LastHash: rng.LastHash})
+			batch.Reset()
+		}
+	}
+	f.commit(batch, rng)
+	return rng
+}
+
+// extendTail indexes the blocks preceding the range down to the given target.
+func (f *FilterMaps) extendTail(rng indexRange, target uint64) {
+	var (
+		batch = f.db.NewBatch()
+		tail  = rng.Tail
+	)
+	for rng.Tail > target {
+		select {
+		case <-f.quit:
+			f.commit(batch, rng)
+			return
+		default:
+		}
+		number := rng.Tail - 1
+		if !f.indexBlock(batch, number, rawdb.ReadCanonicalHash(f.db, number)) {
+			break
+		}
+		rng.Tail = number
+
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			f.commit(batch, rng)
+			batch.Reset()
+		}
+	}
+	f.commit(batch, rng)
+	if rng.Tail < tail {
+		log.Debug("Extended log index tail", "blocks", tail-rng.Tail, "tail", rng.Tail)
+	}
+}
+
+// indexBlock adds the log values of the given block to its filter map. False
+// is returned if the receipts of the block are not available.
+func (f *FilterMaps) indexBlock(batch ethdb.KeyValueWriter, number uint64, hash common.Hash) bool {
+	if hash == (common.Hash{}) || !rawdb.HasReceipts(f.db, hash, number) {
+		return false
+	}
+	var (
+		mapIndex = number / MapBlocks
+		offset   = uint16(number % MapBlocks)
+		seen     = make(map[string]struct{})
+	)
+	add := func(value []byte) {
+		if _, ok := seen[string(value)]; ok {
+			return
+		}
+		seen[string(value)] = struct{}{}
+		rawdb.WriteFilterMapRow(batch, mapIndex, value, offset)
+	}
+	for _, logs := range rawdb.ReadLogs(f.db, hash, number) {
+		for _, l := range logs {
+			add(addressValue(l.Address))
+			for _, topic := range l.Topics {
+				add(topicValue(topic))
+			}
+		}
+	}
+	return true
+}
+
+// commit writes the indexed rows along with the range covering them.
+func (f *FilterMaps) commit(batch ethdb.Batch, rng indexRange) {
+	blob, err := rlp.EncodeToBytes(&rng)
+	if err != nil {
+		log.Crit("Failed to encode log index range", "err", err)
+	}
+	rawdb.WriteFilterMapsRange(batch, blob)
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write log index", "err", err)
+	}
+	f.lock.Lock()
+	f.rng = rng
+	f.lock.Unlock()
+}
+
+// setRange stores the range covered by the log index.
+func (f *FilterMaps) setRange(rng indexRange) {
+	batch := f.db.NewBatch()
+	f.commit(batch, rng)
+}
+
+// addressValue returns the key of a log address in the index.
+func addressValue(address common.Address) []byte {
+	return crypto.Keccak256(address[:])[:valueKeyLength]
+}
+
+// topicValue returns the key of a log topic in the index.
+func topicValue(topic common.Hash) []byte {
+	return crypto.Keccak256(topic[:])[:valueKeyLength]
+}
+
+// blockSet is a set of block offsets within a filter map.
+type blockSet [MapBlocks / 64]uint64
+
+// newBlockSet returns a set of the offsets within the given inclusive range.
+func newBlockSet(from, to uint16) *blockSet {
+	set := new(blockSet)
+	for offset := from; offset <= to; offset++ {
+		set.add(offset)
+	}
+	return set
+}
+
+// add adds an offset to the set.
+func (s *blockSet) add(offset uint16) {
+	s[offset/64] |= 1 << (offset % 64)
+}
+
+// intersect removes the offsets missing from the other set, returning whether
+// any offset is left.
+func (s *blockSet) intersect(other *blockSet) bool {
+	var left uint64
+	for i := range s {
+		s[i] &= other[i]
+		left |= s[i]
+	}
+	return left != 0
+}
+
+// offsets returns the offsets of the set in ascending order.
+func (s *blockSet) offsets() []uint16 {
+	var offsets []uint16
+	for i, word := range s {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			offsets = append(offsets, uint16(i*64+bit))
+			word &= word - 1
+		}
+	}
+	return offsets
+}
@@ -0,0 +1,127 @@
+package filtermaps
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+type testChain struct {
+	head *types.Header
+	feed event.Feed
+}
+
+func (c *testChain) CurrentBlock() *types.Header { return c.head }
+
+func (c *testChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return c.feed.Subscribe(ch)
+}
+
+var (
+	testAddresses = []common.Address{{0x01}, {0x02}, {0x03}}
+	testTopic     = common.Hash{0xff}
+)
+
+// writeTestBlock stores a canonical block emitting a log from the given address,
+// with the test topic on every other block.
+func writeTestBlock(db ethdb.Database, parent common.Hash, number uint64, address common.Address) *types.Header {
+	header := &types.Header{Number: new(big.Int).SetUint64(number), ParentHash: parent, Extra: address[:1]}
+	l := &types.Log{Address: address}
+	if number%2 == 0 {
+		l.Topics = []common.Hash{testTopic}
+	}
+	rawdb.WriteHeader(db, header)
+	rawdb.WriteCanonicalHash(db, header.Hash(), number)
+	rawdb.WriteReceipts(db, header.Hash(), number, types.Receipts{{Logs: []*types.Log{l}}})
+	return header
+}
+
+func update(f *FilterMaps) {
+	done := make(chan struct{})
+	f.update(done)
+	<-done
+}
+
+func TestFilterMaps(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		chain  = new(testChain)
+		blocks = uint64(3*MapBlocks + 100)
+		parent common.Hash
+	)
+	for i := uint64(0); i < blocks; i++ {
+		chain.head = writeTestBlock(db, parent, i, testAddresses[i%3])
+		parent = chain.head.Hash()
+	}
+	f := New(db, chain, 0)
+	update(f)
+
+	if first, last, ok := f.IndexedRange(); !ok || first != 0 || last != blocks-1 {
+		t.Fatalf("indexed range mismatch: have %d-%d (%v), want 0-%d", first, last, ok, blocks-1)
+	}
+	// check verifies the candidates of the filter against the test blocks
+	check := func(begin, end uint64, addresses []common.Address, topics [][]common.Hash, match func(number uint64) bool) {
+		t.Helper()
+		have, err := f.Candidates(context.Background(), begin, end, addresses, topics)
+		if err != nil {
+			t.Fatalf("failed to search the log index: %v", err)
+		}
+		var want []uint64
+		for i := begin; i <= end; i++ {
+			if match(i) {
+				want = append(want, i)
+			}
+		}
+		if len(have) != len(want) {
+			t.Fatalf("candidate count mismatch: have %d, want %d", len(have), len(want))
+		}
+		for i := range have {
+			if have[i] != want[i] {
+				t.Fatalf("candidate %d mismatch: have %d, want %d", i, have[i], want[i])
+			}
+		}
+	}
+	check(0, blocks-1, testAddresses[:1], nil, func(n uint64) bool { return n%3 == 0 })
+	check(100, 2*MapBlocks+5, testAddresses[1:], nil, func(n uint64) bool { return n%3 != 0 })
+	check(10, MapBlocks+10, testAddresses[:1], [][]common.Hash{{testTopic}}, func(n uint64) bool { return n%6 == 0 })
+	check(0, 100, nil, [][]common.Hash{nil, {testTopic}}, func(n uint64) bool { return n%2 == 0 })
+	check(0, 100, []common.Address{{0x04}}, nil, func(n uint64) bool { return false })
+
+	// Replace the head block, the index mustn't be trusted until rolled back
+	chain.head = writeTestBlock(db, chain.head.ParentHash, blocks-1, common.Address{0x04})
+	if _, err := f.Candidates(context.Background(), 0, blocks-1, nil, nil); !errors.Is(err, ErrNotIndexed) {
+		t.Fatalf("reorged index not rejected: %v", err)
+	}
+	update(f)
+	check(blocks-10, blocks-1, []common.Address{{0x04}}, nil, func(n uint64) bool { return n == blocks-1 })
+
+	// Shrink the history window, the maps past it must be dropped
+	f = New(db, chain, MapBlocks+10)
+	update(f)
+
+	tail := (blocks - MapBlocks - 10) / MapBlocks * MapBlocks
+	if first, _, _ := f.IndexedRange(); first != tail {
+		t.Fatalf("indexed tail mismatch: have %d, want %d", first, tail)
+	}
+	if rows := rawdb.ReadFilterMapRow(db, 0, addressValue(testAddresses[0])); len(rows) != 0 {
+		t.Fatalf("pruned map rows left: %d", len(rows))
+	}
+	if _, err := f.Candidates(context.Background(), 0, blocks-1, nil, nil); !errors.Is(err, ErrNotIndexed) {
+		t.Fatalf("pruned blocks not rejected: %v", err)
+	}
+	// The row left behind by the replaced head block is a false positive
+	check(tail, blocks-1, testAddresses[:1], nil, func(n uint64) bool { return n%3 == 0 })
+
+	// Grow the window back, the older blocks must be indexed again
+	f = New(db, chain, 0)
+	update(f)
+	check(0, blocks-1, testAddresses[2:], nil, func(n uint64) bool { return n%3 == 2 })
+}
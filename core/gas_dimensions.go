@@ -0,0 +1,95 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// GasDimension is a resource used by the transactions which is limited per block
+// independently from the others and from the execution gas.
+type GasDimension interface {
+	// Name returns the identifier of the dimension.
+	Name() string
+
+	// Limit returns the amount of the resource the given block can use, zero if
+	// unlimited.
+	Limit(config *params.ChainConfig, header *types.Header) uint64
+
+	// Usage returns the amount of the resource used by an executed transaction,
+	// the state being the one right after its execution.
+	Usage(tx *types.Transaction, receipt *types.Receipt, statedb *state.StateDB) uint64
+}
+
+// GasDimensions is the list of the gas dimensions accounted by the block builder
+// and the block processing.
+var GasDimensions = []GasDimension{
+	daBytes{},
+	stateGrowth{},
+}
+
+// daBytes is the encoded size of the transactions, posted to L1 for data
+// availability.
+type daBytes struct{}
+
+func (daBytes) Name() string { return "da" }
+
+func (daBytes) Limit(config *params.ChainConfig, header *types.Header) uint64 {
+	return config.GasDimensionLimits(header.Time).MaxDABytes
+}
+
+func (daBytes) Usage(tx *types.Transaction, receipt *types.Receipt, statedb *state.StateDB) uint64 {
+	return tx.Size()
+}
+
+// stateGrowth is the size of the contract code deployed.
+type stateGrowth struct{}
+
+func (stateGrowth) Name() string { return "state" }
+
+func (stateGrowth) Limit(config *params.ChainConfig, header *types.Header) uint64 {
+	return config.GasDimensionLimits(header.Time).MaxStateGrowth
+}
+
+func (stateGrowth) Usage(tx *types.Transaction, receipt *types.Receipt, statedb *state.StateDB) uint64 {
+	if tx.To() != nil || receipt.ContractAddress == (common.Address{}) {
+		return 0
+	}
+	return uint64(statedb.GetCodeSize(receipt.ContractAddress))
+}
+
+// GasDimensionsEnabled returns whether the multi-dimensional gas accounting is
+// enforced on the block at the given time.
+func GasDimensionsEnabled(config *params.ChainConfig, time uint64) bool {
+	return config.GasDimensionLimits(time) != nil
+}
+
+// GasUsage is the amount of resources used per gas dimension, indexed as in
+// GasDimensions.
+type GasUsage []uint64
+
+// Add accounts the resources used by an executed transaction, failing without
+// accounting them if any dimension limit would be exceeded. It must be called
+// before the changes of the transaction are finalised, so that the state is the
+// one right after its execution and the transaction can still be reverted.
+// Deposit transactions are forced into the blocks and are not accounted.
+func (u *GasUsage) Add(config *params.ChainConfig, header *types.Header, tx *types.Transaction, receipt *types.Receipt, statedb *state.StateDB) error {
+	if tx.IsDepositTx() {
+		return nil
+	}
+	if *u == nil {
+		*u = make(GasUsage, len(GasDimensions))
+	}
+	used := make(GasUsage, len(GasDimensions))
+	for i, dim := range GasDimensions {
+		used[i] = (*u)[i] + dim.Usage(tx, receipt, statedb)
+		if limit := dim.Limit(config, header); limit != 0 && used[i] > limit {
+			return fmt.Errorf("%w: %s usage %d, limit %d", ErrGasDimensionExceeded, dim.Name(), used[i], limit)
+		}
+	}
+	copy(*u, used)
+	return nil
+}
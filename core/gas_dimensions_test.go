@@ -0,0 +1,161 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// gasDimensionsConfig returns an optimism config enforcing the given gas
+// dimension limits from timestamp 10.
+func gasDimensionsConfig(limits *params.GasDimensionsConfig) *params.ChainConfig {
+	config := *params.OptimismTestConfig
+	optimism := *config.Optimism
+	optimism.GasDimensions = limits
+	config.Optimism = &optimism
+	config.OasysGasDimensionsTime = new(uint64)
+	*config.OasysGasDimensionsTime = 10
+	return &config
+}
+
+func TestGasUsageLimits(t *testing.T) {
+	var (
+		limits  = new(params.GasDimensionsConfig)
+		config  = gasDimensionsConfig(limits)
+		tx      = types.NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), make([]byte, 100))
+		receipt = &types.Receipt{GasUsed: 21000}
+		header  = &types.Header{Number: big.NewInt(1), Time: 10, GasLimit: 30_000_000}
+	)
+	if GasDimensionsEnabled(config, 9) || !GasDimensionsEnabled(config, 10) {
+		t.Fatalf("gas dimensions not gated by the fork")
+	}
+	// Without limits, all the transactions are accounted
+	var usage GasUsage
+	for i := 0; i < 3; i++ {
+		if err := usage.Add(config, header, tx, receipt, nil); err != nil {
+			t.Fatalf("transaction %d: unexpected error: %v", i, err)
+		}
+	}
+	if usage[0] != 3*tx.Size() {
+		t.Fatalf("DA bytes mismatch: have %d, want %d", usage[0], 3*tx.Size())
+	}
+	// With a DA limit, the transactions beyond are rejected without accounting
+	limits.MaxDABytes = 2*tx.Size() + 1
+	usage = nil
+	for i := 0; i < 2; i++ {
+		if err := usage.Add(config, header, tx, receipt, nil); err != nil {
+			t.Fatalf("transaction %d: unexpected error: %v", i, err)
+		}
+	}
+	if err := usage.Add(config, header, tx, receipt, nil); !errors.Is(err, ErrGasDimensionExceeded) {
+		t.Fatalf("expected %v, got %v", ErrGasDimensionExceeded, err)
+	}
+	if usage[0] != 2*tx.Size() {
+		t.Fatalf("usage mismatch after rejection: %v", usage)
+	}
+}
+
+// Tests that the gas dimensions are checked before the changes of a transaction
+// are finalised, so that a rejected transaction can be reverted.
+func TestGasDimensionsRevert(t *testing.T) {
+	var (
+		limits = &params.GasDimensionsConfig{MaxStateGrowth: 5}
+		config = gasDimensionsConfig(limits)
+		key, _ = crypto.GenerateKey()
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		header = &types.Header{Number: big.NewInt(1), Time: 10, GasLimit: 30_000_000, BaseFee: big.NewInt(1), Difficulty: new(big.Int)}
+		signer = types.LatestSigner(config)
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.AddBalance(sender, big.NewInt(params.Ether))
+	statedb.Finalise(true)
+
+	// Deploy a contract returning 10 bytes of code
+	tx := types.MustSignNewTx(key, signer, &types.LegacyTx{Gas: 100_000, GasPrice: big.NewInt(1), Data: []byte{0x60, 0x0a, 0x60, 0x00, 0xf3}})
+	contract := crypto.CreateAddress(sender, 0)
+
+	apply := func() (*types.Receipt, GasUsage, error) {
+		msg, err := TransactionToMessage(tx, signer, header.BaseFee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var (
+			usage  GasUsage
+			author = common.Address{}
+			evm    = vm.NewEVM(NewEVMBlockContext(header, nil, &author, config, statedb), vm.TxContext{}, statedb, config, vm.Config{})
+			check  = func(receipt *types.Receipt) error {
+				return usage.Add(config, header, tx, receipt, statedb)
+			}
+		)
+		receipt, err := ApplyTransactionWithEVM(msg, config, new(GasPool).AddGas(header.GasLimit), statedb, header.Number, header.Hash(), tx, new(uint64), evm, check)
+		return receipt, usage, err
+	}
+	snap := statedb.Snapshot()
+	if _, _, err := apply(); !errors.Is(err, ErrGasDimensionExceeded) {
+		t.Fatalf("expected %v, got %v", ErrGasDimensionExceeded, err)
+	}
+	statedb.RevertToSnapshot(snap)
+	if nonce := statedb.GetNonce(sender); nonce != 0 {
+		t.Fatalf("sender nonce not reverted: have %d, want 0", nonce)
+	}
+	if size := statedb.GetCodeSize(contract); size != 0 {
+		t.Fatalf("contract code not reverted: have %d bytes", size)
+	}
+	// Within the limit, the deployment is accounted and finalised
+	limits.MaxStateGrowth = 10
+	receipt, usage, err := apply()
+	if err != nil {
+		t.Fatalf("deployment within the limit rejected: %v", err)
+	}
+	if receipt.ContractAddress != contract || usage[1] != 10 {
+		t.Fatalf("deployment mismatch: contract %x, state usage %d", receipt.ContractAddress, usage[1])
+	}
+	if size := statedb.GetCodeSize(contract); size != 10 {
+		t.Fatalf("contract code size mismatch: have %d, want 10", size)
+	}
+}
+
+// Tests that the block processing rejects the transactions exceeding the gas
+// dimension limits.
+func TestProcessGasDimensions(t *testing.T) {
+	var (
+		config = gasDimensionsConfig(&params.GasDimensionsConfig{MaxStateGrowth: 5})
+		key, _ = crypto.GenerateKey()
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		gspec  = &Genesis{Config: config, Alloc: GenesisAlloc{sender: {Balance: big.NewInt(params.Ether)}}, BaseFee: big.NewInt(1)}
+	)
+	config.BedrockBlock = big.NewInt(0)
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, beacon.New(ethash.NewFaker()), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+
+	signer := types.LatestSigner(config)
+	tx := types.MustSignNewTx(key, signer, &types.LegacyTx{Gas: 100_000, GasPrice: big.NewInt(1), Data: []byte{0x60, 0x0a, 0x60, 0x00, 0xf3}})
+
+	genesis := chain.Genesis().Header()
+	for _, time := range []uint64{9, 10} {
+		header := &types.Header{ParentHash: genesis.Hash(), Number: big.NewInt(1), Time: time, GasLimit: genesis.GasLimit, BaseFee: big.NewInt(1), Difficulty: new(big.Int)}
+		block := types.NewBlockWithHeader(header).WithBody([]*types.Transaction{tx}, nil)
+
+		statedb, _ := chain.StateAt(genesis.Root)
+		_, _, _, err := chain.Processor().Process(block, statedb, vm.Config{})
+		if time < 10 && err != nil {
+			t.Fatalf("block before the fork rejected: %v", err)
+		}
+		if time >= 10 && !errors.Is(err, ErrGasDimensionExceeded) {
+			t.Fatalf("expected %v, got %v", ErrGasDimensionExceeded, err)
+		}
+	}
+}
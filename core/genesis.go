@@ -257,9 +257,10 @@ type ChainOverrides struct {
 	OverrideCancun *uint64
 	OverrideVerkle *uint64
 	// optimism
-	OverrideOptimismCanyon  *uint64
-	ApplySuperchainUpgrades bool
-	OverrideOptimismInterop *uint64
+	OverrideOptimismCanyon   *uint64
+	OverrideOasysOperatorFee *uint64
+	ApplySuperchainUpgrades  bool
+	OverrideOptimismInterop  *uint64
 }
 
 // SetupGenesisBlock writes or updates the genesis block in db.
@@ -319,6 +320,9 @@ func SetupGenesisBlockWithOverride(db ethdb.Database, triedb *trie.Database, gen
 					config.Optimism.EIP1559DenominatorCanyon = 250
 				}
 			}
+			if overrides != nil && overrides.OverrideOasysOperatorFee != nil {
+				config.OasysOperatorFeeTime = overrides.OverrideOasysOperatorFee
+			}
 			if overrides != nil && overrides.OverrideOptimismInterop != nil {
 				config.InteropTime = overrides.OverrideOptimismInterop
 			}
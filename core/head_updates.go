@@ -0,0 +1,77 @@
+package core
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Labels of the rollup heads tracked by the chain.
+const (
+	HeadUnsafe    = "unsafe"
+	HeadSafe      = "safe"
+	HeadFinalized = "finalized"
+)
+
+// HeadUpdateEvent is posted when one of the labelled heads moves.
+type HeadUpdateEvent struct {
+	Label      string             // Label of the head that moved
+	Header     *types.Header      // New head
+	L1Origin   *types.L1BlockInfo // L1 origin of the new head, nil if unknown
+	ReorgDepth uint64             // Number of blocks of the previous head dropped from the canonical chain
+}
+
+// headUpdates tracks the last head posted per label, to derive the reorg depth
+// of the following update.
+type headUpdates struct {
+	lock  sync.Mutex
+	heads map[string]*types.Header
+}
+
+// sendHeadEvent notifies the subscribers of a new chain head, also posting the
+// labelled update of the unsafe head.
+func (bc *BlockChain) sendHeadEvent(block *types.Block) {
+	bc.chainHeadFeed.Send(ChainHeadEvent{Block: block, Usage: bc.BlockUsage(block.Hash())})
+	bc.sendHeadUpdate(HeadUnsafe, block.Header(), block)
+}
+
+// sendHeadUpdate posts the update of the given labelled head, if it moved. The
+// block is used to decode the L1 origin, retrieved if not given.
+func (bc *BlockChain) sendHeadUpdate(label string, header *types.Header, block *types.Block) {
+	bc.headUpdates.lock.Lock()
+	defer bc.headUpdates.lock.Unlock()
+
+	prev := bc.headUpdates.heads[label]
+	if prev != nil && prev.Hash() == header.Hash() {
+		return
+	}
+	if bc.headUpdates.heads == nil {
+		bc.headUpdates.heads = make(map[string]*types.Header)
+	}
+	bc.headUpdates.heads[label] = header
+
+	ev := HeadUpdateEvent{Label: label, Header: header, ReorgDepth: bc.reorgDepth(prev)}
+	if block == nil {
+		block = bc.GetBlock(header.Hash(), header.Number.Uint64())
+	}
+	if block != nil && bc.chainConfig.IsOptimismBedrock(header.Number) {
+		if txs := block.Transactions(); len(txs) > 0 && txs[0].IsDepositTx() {
+			ev.L1Origin, _ = types.ParseL1BlockInfo(txs[0].Data())
+		}
+	}
+	bc.headUpdateFeed.Send(ev)
+}
+
+// reorgDepth returns the number of blocks between the given header and its most
+// recent ancestor still in the canonical chain.
+func (bc *BlockChain) reorgDepth(header *types.Header) uint64 {
+	var depth uint64
+	for header != nil && bc.GetCanonicalHash(header.Number.Uint64()) != header.Hash() {
+		depth++
+		if header.Number.Sign() == 0 {
+			break
+		}
+		header = bc.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	}
+	return depth
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestHeadUpdateEvents(t *testing.T) {
+	var (
+		gspec  = &Genesis{Config: params.TestChainConfig}
+		engine = ethash.NewFaker()
+	)
+	_, canon, _ := GenerateChainWithGenesis(gspec, engine, 3, func(i int, gen *BlockGen) {})
+	_, side, _ := GenerateChainWithGenesis(gspec, engine, 4, func(i int, gen *BlockGen) {
+		gen.SetCoinbase(common.Address{1})
+	})
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	updates := make(chan HeadUpdateEvent, 16)
+	sub := chain.SubscribeHeadUpdateEvent(updates)
+	defer sub.Unsubscribe()
+
+	// drain returns the last update posted, along with the deepest reorg reported
+	drain := func() (last HeadUpdateEvent, depth uint64) {
+		for {
+			select {
+			case ev := <-updates:
+				if ev.ReorgDepth > depth {
+					depth = ev.ReorgDepth
+				}
+				last = ev
+			default:
+				return last, depth
+			}
+		}
+	}
+	if _, err := chain.InsertChain(canon); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	if last, depth := drain(); last.Label != HeadUnsafe || last.Header.Hash() != canon[2].Hash() || depth != 0 {
+		t.Fatalf("unexpected update: label %s, number %v, depth %d", last.Label, last.Header.Number, depth)
+	}
+	chain.SetSafe(canon[1].Header())
+	if last, _ := drain(); last.Label != HeadSafe || last.Header.Hash() != canon[1].Hash() {
+		t.Fatalf("unexpected safe update: label %s, number %v", last.Label, last.Header.Number)
+	}
+	// Reorging the whole chain must report the depth of the dropped blocks
+	if _, err := chain.InsertChain(side); err != nil {
+		t.Fatalf("failed to insert side chain: %v", err)
+	}
+	if last, depth := drain(); last.Label != HeadUnsafe || last.Header.Hash() != side[3].Hash() || depth != 3 {
+		t.Fatalf("unexpected reorg update: label %s, number %v, depth %d", last.Label, last.Header.Number, depth)
+	}
+}
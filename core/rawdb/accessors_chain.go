@@ -294,6 +294,40 @@ func WriteTxIndexTail(db ethdb.KeyValueWriter, number uint64) {
 	}
 }
 
+// ReadTxSenderIndexTail retrieves the number of the oldest block whose
+// transactions have been indexed by sender and nonce.
+func ReadTxSenderIndexTail(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(txSenderIndexTailKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteTxSenderIndexTail stores the number of the oldest block whose
+// transactions have been indexed by sender and nonce.
+func WriteTxSenderIndexTail(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(txSenderIndexTailKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the transaction sender index tail", "err", err)
+	}
+}
+
+// ReadFilterMapsRange retrieves the serialized range of blocks covered by the
+// log index.
+func ReadFilterMapsRange(db ethdb.KeyValueReader) []byte {
+	data, _ := db.Get(filterMapsRangeKey)
+	return data
+}
+
+// WriteFilterMapsRange stores the serialized range of blocks covered by the
+// log index.
+func WriteFilterMapsRange(db ethdb.KeyValueWriter, blob []byte) {
+	if err := db.Put(filterMapsRangeKey, blob); err != nil {
+		log.Crit("Failed to store the log index range", "err", err)
+	}
+}
+
 // ReadFastTxLookupLimit retrieves the tx lookup limit used in fast sync.
 func ReadFastTxLookupLimit(db ethdb.KeyValueReader) *uint64 {
 	data, _ := db.Get(fastTxLookupLimitKey)
@@ -701,6 +735,29 @@ func DeleteReceipts(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
 	}
 }
 
+// ReadDepositRecordRLP retrieves the encoded deposits and withdrawals of a block
+// synced by a deposit-only node.
+func ReadDepositRecordRLP(db ethdb.KeyValueReader, hash common.Hash, number uint64) rlp.RawValue {
+	data, _ := db.Get(depositRecordKey(number, hash))
+	return data
+}
+
+// WriteDepositRecordRLP stores the encoded deposits and withdrawals of a block
+// synced by a deposit-only node.
+func WriteDepositRecordRLP(db ethdb.KeyValueWriter, hash common.Hash, number uint64, record rlp.RawValue) {
+	if err := db.Put(depositRecordKey(number, hash), record); err != nil {
+		log.Crit("Failed to store deposit record", "err", err)
+	}
+}
+
+// DeleteDepositRecord removes the deposits and withdrawals of a block synced by
+// a deposit-only node.
+func DeleteDepositRecord(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(depositRecordKey(number, hash)); err != nil {
+		log.Crit("Failed to delete deposit record", "err", err)
+	}
+}
+
 // storedReceiptRLP is the storage encoding of a receipt.
 // Re-definition in core/types/receipt.go.
 // TODO: Re-use the existing definition.
@@ -870,6 +927,7 @@ const badBlockToKeep = 10
 type badBlock struct {
 	Header *types.Header
 	Body   *types.Body
+	Report []byte `rlp:"optional"` // Triage report of the bad block, if any
 }
 
 // ReadBadBlock retrieves the bad block with the corresponding block hash.
@@ -947,6 +1005,54 @@ func WriteBadBlock(db ethdb.KeyValueStore, block *types.Block) {
 	}
 }
 
+// ReadBadBlockReport retrieves the triage report attached to the bad block with
+// the given hash, nil if none.
+func ReadBadBlockReport(db ethdb.Reader, hash common.Hash) []byte {
+	blob, err := db.Get(badBlockKey)
+	if err != nil {
+		return nil
+	}
+	var badBlocks []*badBlock
+	if err := rlp.DecodeBytes(blob, &badBlocks); err != nil {
+		return nil
+	}
+	for _, bad := range badBlocks {
+		if bad.Header.Hash() == hash {
+			return bad.Report
+		}
+	}
+	return nil
+}
+
+// WriteBadBlockReport attaches the triage report to the stored bad block with
+// the given hash. The report is dropped if the block is not stored anymore.
+func WriteBadBlockReport(db ethdb.KeyValueStore, hash common.Hash, report []byte) {
+	blob, err := db.Get(badBlockKey)
+	if err != nil {
+		return
+	}
+	var badBlocks []*badBlock
+	if err := rlp.DecodeBytes(blob, &badBlocks); err != nil {
+		log.Crit("Failed to decode old bad blocks", "error", err)
+	}
+	found := false
+	for _, bad := range badBlocks {
+		if bad.Header.Hash() == hash {
+			bad.Report, found = report, true
+		}
+	}
+	if !found {
+		return
+	}
+	data, err := rlp.EncodeToBytes(badBlocks)
+	if err != nil {
+		log.Crit("Failed to encode bad blocks", "err", err)
+	}
+	if err := db.Put(badBlockKey, data); err != nil {
+		log.Crit("Failed to write bad blocks", "err", err)
+	}
+}
+
 // DeleteBadBlocks deletes all the bad blocks from the database
 func DeleteBadBlocks(db ethdb.KeyValueWriter) {
 	if err := db.Delete(badBlockKey); err != nil {
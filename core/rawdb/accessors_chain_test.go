@@ -252,6 +252,16 @@ func TestBadBlockStorage(t *testing.T) {
 		}
 	}
 
+	// Attach a report to a stored bad block
+	hash := badBlocks[0].Hash()
+	WriteBadBlockReport(db, hash, []byte(`{"number":99}`))
+	if report := ReadBadBlockReport(db, hash); string(report) != `{"number":99}` {
+		t.Fatalf("Bad block report mismatch: %s", report)
+	}
+	if len(ReadAllBadBlocks(db)) != badBlockToKeep {
+		t.Fatalf("Bad blocks lost while attaching a report")
+	}
+
 	// Delete all bad blocks
 	DeleteBadBlocks(db)
 	badBlocks = ReadAllBadBlocks(db)
@@ -988,3 +998,18 @@ func TestHeadersRLPStorage(t *testing.T) {
 	checkSequence(1, 1)    // Only block 1
 	checkSequence(1, 2)    // Genesis + block 1
 }
+
+func TestReplicaPromotionStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+	if promoted := ReadReplicaPromotion(db); promoted != nil {
+		t.Fatalf("non existent promotion returned: %d", *promoted)
+	}
+	WriteReplicaPromotion(db, 1700000000)
+	if promoted := ReadReplicaPromotion(db); promoted == nil || *promoted != 1700000000 {
+		t.Fatalf("promotion mismatch: have %v, want %d", promoted, 1700000000)
+	}
+	DeleteReplicaPromotion(db)
+	if promoted := ReadReplicaPromotion(db); promoted != nil {
+		t.Fatalf("deleted promotion returned: %d", *promoted)
+	}
+}
@@ -18,6 +18,7 @@ package rawdb
 
 import (
 	"bytes"
+	"encoding/binary"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -93,6 +94,51 @@ func DeleteTxLookupEntries(db ethdb.KeyValueWriter, hashes []common.Hash) {
 	}
 }
 
+// ReadTxSenderEntry retrieves the hash of the transaction sent by the account
+// with the given nonce from the sender index.
+func ReadTxSenderEntry(db ethdb.KeyValueReader, sender common.Address, nonce uint64) *common.Hash {
+	data, _ := db.Get(txSenderKey(sender, nonce))
+	if len(data) != common.HashLength {
+		return nil
+	}
+	hash := common.BytesToHash(data)
+	return &hash
+}
+
+// WriteTxSenderEntries indexes the given transactions by sender and nonce.
+// Deposit transactions, which carry no nonce, are not indexed.
+func WriteTxSenderEntries(db ethdb.KeyValueWriter, signer types.Signer, txs types.Transactions) {
+	for _, tx := range txs {
+		if tx.IsDepositTx() {
+			continue
+		}
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			log.Warn("Failed to derive transaction sender", "hash", tx.Hash(), "err", err)
+			continue
+		}
+		if err := db.Put(txSenderKey(sender, tx.Nonce()), tx.Hash().Bytes()); err != nil {
+			log.Crit("Failed to store transaction sender entry", "err", err)
+		}
+	}
+}
+
+// DeleteTxSenderEntries removes the sender index entries of the given transactions.
+func DeleteTxSenderEntries(db ethdb.KeyValueWriter, signer types.Signer, txs types.Transactions) {
+	for _, tx := range txs {
+		if tx.IsDepositTx() {
+			continue
+		}
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			continue
+		}
+		if err := db.Delete(txSenderKey(sender, tx.Nonce())); err != nil {
+			log.Crit("Failed to delete transaction sender entry", "err", err)
+		}
+	}
+}
+
 // ReadTransaction retrieves a specific transaction from the database, along with
 // its added positional metadata.
 func ReadTransaction(db ethdb.Reader, hash common.Hash) (*types.Transaction, common.Hash, uint64, uint64) {
@@ -179,3 +225,57 @@ func DeleteBloombits(db ethdb.Database, bit uint, from uint64, to uint64) {
 		log.Crit("Failed to delete bloom bits", "err", it.Error())
 	}
 }
+
+// ReadFilterMapRow retrieves the offsets of the blocks of the given filter map
+// emitting logs with the given value key.
+func ReadFilterMapRow(db ethdb.Iteratee, mapIndex uint64, value []byte) []uint16 {
+	prefix := append(filterMapKey(mapIndex), value...)
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	var offsets []uint16
+	for it.Next() {
+		if key := it.Key(); len(key) == len(prefix)+2 {
+			offsets = append(offsets, binary.BigEndian.Uint16(key[len(prefix):]))
+		}
+	}
+	return offsets
+}
+
+// WriteFilterMapRow marks the block at the given offset of the filter map as
+// emitting logs with the given value key.
+func WriteFilterMapRow(db ethdb.KeyValueWriter, mapIndex uint64, value []byte, offset uint16) {
+	if err := db.Put(filterMapRowKey(mapIndex, value, offset), nil); err != nil {
+		log.Crit("Failed to store filter map row", "err", err)
+	}
+}
+
+// DeleteFilterMaps removes all the rows of the filter maps in the given range.
+func DeleteFilterMaps(db ethdb.KeyValueStore, from uint64, to uint64) {
+	var (
+		batch = db.NewBatch()
+		start = filterMapKey(from)
+		end   = filterMapKey(to)
+		it    = db.NewIterator(filterMapRowPrefix, start[len(filterMapRowPrefix):])
+	)
+	defer it.Release()
+
+	for it.Next() {
+		if bytes.Compare(it.Key(), end) >= 0 {
+			break
+		}
+		batch.Delete(it.Key())
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed to delete filter maps", "err", err)
+			}
+			batch.Reset()
+		}
+	}
+	if it.Error() != nil {
+		log.Crit("Failed to iterate filter maps", "err", it.Error())
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to delete filter maps", "err", err)
+	}
+}
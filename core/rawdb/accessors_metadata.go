@@ -17,6 +17,7 @@
 package rawdb
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"time"
 
@@ -190,3 +191,77 @@ func WriteTransitionStatus(db ethdb.KeyValueWriter, data []byte) {
 		log.Crit("Failed to store the eth2 transition status", "err", err)
 	}
 }
+
+// ReadLegacyHistoryBoundary retrieves the number of the first block following
+// the imported pre-bedrock history, nil if no history was imported.
+func ReadLegacyHistoryBoundary(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(legacyHistoryBoundaryKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteLegacyHistoryBoundary stores the number of the first block following the
+// imported pre-bedrock history.
+func WriteLegacyHistoryBoundary(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(legacyHistoryBoundaryKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the legacy history boundary", "err", err)
+	}
+}
+
+// ReadReorgJournal retrieves the encoded journal of the blocks recently dropped
+// from the canonical chain by reorgs.
+func ReadReorgJournal(db ethdb.KeyValueReader) []byte {
+	data, _ := db.Get(reorgJournalKey)
+	return data
+}
+
+// WriteReorgJournal stores the encoded journal of the blocks recently dropped from
+// the canonical chain by reorgs.
+func WriteReorgJournal(db ethdb.KeyValueWriter, journal []byte) {
+	if err := db.Put(reorgJournalKey, journal); err != nil {
+		log.Crit("Failed to store the reorg journal", "err", err)
+	}
+}
+
+// ReadOrderingAudit retrieves the encoded ordering audit of a locally built block.
+func ReadOrderingAudit(db ethdb.KeyValueReader, hash common.Hash) []byte {
+	data, _ := db.Get(orderingAuditKey(hash))
+	return data
+}
+
+// WriteOrderingAudit stores the encoded ordering audit of a locally built block.
+func WriteOrderingAudit(db ethdb.KeyValueWriter, hash common.Hash, audit []byte) {
+	if err := db.Put(orderingAuditKey(hash), audit); err != nil {
+		log.Crit("Failed to store the ordering audit", "err", err)
+	}
+}
+
+// ReadReplicaPromotion retrieves the unix time the node was promoted from failover
+// replica to sequencer, nil if it was not.
+func ReadReplicaPromotion(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(replicaPromotionKey)
+	if len(data) != 8 {
+		return nil
+	}
+	promoted := binary.BigEndian.Uint64(data)
+	return &promoted
+}
+
+// WriteReplicaPromotion stores the unix time the node was promoted from failover
+// replica to sequencer.
+func WriteReplicaPromotion(db ethdb.KeyValueWriter, promoted uint64) {
+	if err := db.Put(replicaPromotionKey, encodeBlockNumber(promoted)); err != nil {
+		log.Crit("Failed to store the replica promotion", "err", err)
+	}
+}
+
+// DeleteReplicaPromotion removes the record of the promotion of the node from
+// failover replica to sequencer.
+func DeleteReplicaPromotion(db ethdb.KeyValueWriter) {
+	if err := db.Delete(replicaPromotionKey); err != nil {
+		log.Crit("Failed to delete the replica promotion", "err", err)
+	}
+}
@@ -19,6 +19,7 @@ package rawdb
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -51,6 +52,20 @@ func (info *freezerInfo) size() common.StorageSize {
 	return total
 }
 
+// stats returns the size of each table of the freezer.
+func (info *freezerInfo) stats() []DatabaseStat {
+	stats := make([]DatabaseStat, 0, len(info.sizes))
+	for _, table := range info.sizes {
+		stats = append(stats, DatabaseStat{
+			Database: fmt.Sprintf("Ancient store (%s)", strings.Title(info.name)),
+			Category: strings.Title(table.name),
+			Size:     uint64(table.size),
+			Items:    info.count(),
+		})
+	}
+	return stats
+}
+
 func inspect(name string, order map[string]bool, reader ethdb.AncientReader) (freezerInfo, error) {
 	info := freezerInfo{name: name}
 	for t := range order {
@@ -123,6 +123,11 @@ func (db *nofreezedb) ModifyAncients(func(ethdb.AncientWriteOp) error) (int64, e
 	return 0, errNotSupported
 }
 
+// RewriteAncient returns an error as we don't have a backing chain freezer.
+func (db *nofreezedb) RewriteAncient(kind string, number uint64, item []byte) error {
+	return errNotSupported
+}
+
 // TruncateHead returns an error as we don't have a backing chain freezer.
 func (db *nofreezedb) TruncateHead(items uint64) (uint64, error) {
 	return 0, errNotSupported
@@ -359,6 +364,7 @@ type OpenOptions struct {
 	Type              string // "leveldb" | "pebble"
 	Directory         string // the datadir
 	AncientsDirectory string // the ancients-dir
+	StateDirectory    string // the dir of the separate state database, empty to keep the state in the datadir
 	Namespace         string // the namespace for database relevant metrics
 	Cache             int    // the capacity(in megabytes) of the data caching
 	Handles           int    // number of files to be open simultaneously
@@ -398,13 +404,52 @@ func openKeyValueDatabase(o OpenOptions) (ethdb.Database, error) {
 	return NewPebbleDBDatabase(o.Directory, o.Cache, o.Handles, o.Namespace, o.ReadOnly, o.Ephemeral)
 }
 
+// openStateSplitDatabase opens the chain and the state key-value databases of a
+// database keeping the state in a directory of its own. The state, read far more
+// often, is given three quarters of the cache and file handles.
+func openStateSplitDatabase(o OpenOptions) (ethdb.Database, error) {
+	chainOpts, stateOpts := o, o
+	chainOpts.Cache, stateOpts.Cache = o.Cache/4, o.Cache-o.Cache/4
+	chainOpts.Handles, stateOpts.Handles = o.Handles/4, o.Handles-o.Handles/4
+	stateOpts.Directory = o.StateDirectory
+	if o.Namespace != "" {
+		stateOpts.Namespace = o.Namespace + "state/"
+	}
+	chain, err := openKeyValueDatabase(chainOpts)
+	if err != nil {
+		return nil, err
+	}
+	state, err := openKeyValueDatabase(stateOpts)
+	if err != nil {
+		chain.Close()
+		return nil, err
+	}
+	store, err := newStateStore(chain, state, o.ReadOnly)
+	if err != nil {
+		state.Close()
+		chain.Close()
+		return nil, err
+	}
+	log.Info("Keeping the state in a separate database", "chain", o.Directory, "state", o.StateDirectory)
+	return NewDatabase(store), nil
+}
+
 // Open opens both a disk-based key-value database such as leveldb or pebble, but also
 // integrates it with a freezer database -- if the AncientDir option has been
 // set on the provided OpenOptions.
 // The passed o.AncientDir indicates the path of root ancient directory where
-// the chain freezer can be opened.
+// the chain freezer can be opened. If o.StateDirectory is set, the state is kept
+// in a key-value database of its own in that directory.
 func Open(o OpenOptions) (ethdb.Database, error) {
-	kvdb, err := openKeyValueDatabase(o)
+	var (
+		kvdb ethdb.Database
+		err  error
+	)
+	if len(o.StateDirectory) == 0 {
+		kvdb, err = openKeyValueDatabase(o)
+	} else {
+		kvdb, err = openStateSplitDatabase(o)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -449,9 +494,25 @@ func (s *stat) Count() string {
 	return s.count.String()
 }
 
-// InspectDatabase traverses the entire database and checks the size
-// of all different categories of data.
-func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
+// DatabaseStat is the size and the number of items of a category of data.
+type DatabaseStat struct {
+	Database string `json:"database"`
+	Category string `json:"category"`
+	Size     uint64 `json:"size"`
+	Items    uint64 `json:"items"`
+}
+
+func newDatabaseStat(database, category string, s stat) DatabaseStat {
+	return DatabaseStat{Database: database, Category: category, Size: uint64(s.size), Items: uint64(s.count)}
+}
+
+// errInspectionAborted is returned if a database inspection is aborted.
+var errInspectionAborted = errors.New("inspection aborted")
+
+// InspectKeyValueStore traverses the key-value store of the database and sums up
+// the size of each category of data, the unaccounted data coming last. The
+// traversal stops early with an error if abort is closed.
+func InspectKeyValueStore(db ethdb.Iteratee, keyPrefix, keyStart []byte, abort <-chan struct{}) ([]DatabaseStat, error) {
 	it := db.NewIterator(keyPrefix, keyStart)
 	defer it.Release()
 
@@ -477,6 +538,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		storageSnaps    stat
 		preimages       stat
 		bloomBits       stat
+		filterMaps      stat
+		customIndexes   stat
 		beaconHeaders   stat
 		cliqueSnaps     stat
 
@@ -487,17 +550,12 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		// Meta- and unaccounted data
 		metadata    stat
 		unaccounted stat
-
-		// Totals
-		total common.StorageSize
 	)
-	// Inspect key-value database first.
 	for it.Next() {
 		var (
 			key  = it.Key()
 			size = common.StorageSize(len(key) + len(it.Value()))
 		)
-		total += size
 		switch {
 		case bytes.HasPrefix(key, headerPrefix) && len(key) == (len(headerPrefix)+8+common.HashLength):
 			headers.Add(size)
@@ -537,6 +595,10 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			bloomBits.Add(size)
 		case bytes.HasPrefix(key, BloomBitsIndexPrefix):
 			bloomBits.Add(size)
+		case bytes.HasPrefix(key, filterMapRowPrefix) && len(key) == (len(filterMapRowPrefix)+8+8+2):
+			filterMaps.Add(size)
+		case bytes.HasPrefix(key, CustomIndexPrefix):
+			customIndexes.Add(size)
 		case bytes.HasPrefix(key, skeletonHeaderPrefix) && len(key) == (len(skeletonHeaderPrefix)+8):
 			beaconHeaders.Add(size)
 		case bytes.HasPrefix(key, CliqueSnapshotPrefix) && len(key) == 7+common.HashLength:
@@ -557,6 +619,7 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 				snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, fastTxLookupLimitKey,
 				uncleanShutdownKey, badBlockKey, transitionStatusKey, skeletonSyncStatusKey,
 				persistentStateIDKey, trieJournalKey, snapshotSyncStatusKey, snapSyncStatusFlagKey,
+				legacyHistoryBoundaryKey, reorgJournalKey, filterMapsRangeKey, stateDatabaseIDKey,
 			} {
 				if bytes.Equal(key, meta) {
 					metadata.Add(size)
@@ -569,48 +632,81 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			}
 		}
 		count++
+		if count%1000 == 0 {
+			select {
+			case <-abort:
+				return nil, errInspectionAborted
+			default:
+			}
+		}
 		if count%1000 == 0 && time.Since(logged) > 8*time.Second {
 			log.Info("Inspecting database", "count", count, "elapsed", common.PrettyDuration(time.Since(start)))
 			logged = time.Now()
 		}
 	}
-	// Display the database statistic of key-value store.
-	stats := [][]string{
-		{"Key-Value store", "Headers", headers.Size(), headers.Count()},
-		{"Key-Value store", "Bodies", bodies.Size(), bodies.Count()},
-		{"Key-Value store", "Receipt lists", receipts.Size(), receipts.Count()},
-		{"Key-Value store", "Difficulties", tds.Size(), tds.Count()},
-		{"Key-Value store", "Block number->hash", numHashPairings.Size(), numHashPairings.Count()},
-		{"Key-Value store", "Block hash->number", hashNumPairings.Size(), hashNumPairings.Count()},
-		{"Key-Value store", "Transaction index", txLookups.Size(), txLookups.Count()},
-		{"Key-Value store", "Bloombit index", bloomBits.Size(), bloomBits.Count()},
-		{"Key-Value store", "Contract codes", codes.Size(), codes.Count()},
-		{"Key-Value store", "Hash trie nodes", legacyTries.Size(), legacyTries.Count()},
-		{"Key-Value store", "Path trie state lookups", stateLookups.Size(), stateLookups.Count()},
-		{"Key-Value store", "Path trie account nodes", accountTries.Size(), accountTries.Count()},
-		{"Key-Value store", "Path trie storage nodes", storageTries.Size(), storageTries.Count()},
-		{"Key-Value store", "Trie preimages", preimages.Size(), preimages.Count()},
-		{"Key-Value store", "Account snapshot", accountSnaps.Size(), accountSnaps.Count()},
-		{"Key-Value store", "Storage snapshot", storageSnaps.Size(), storageSnaps.Count()},
-		{"Key-Value store", "Beacon sync headers", beaconHeaders.Size(), beaconHeaders.Count()},
-		{"Key-Value store", "Clique snapshots", cliqueSnaps.Size(), cliqueSnaps.Count()},
-		{"Key-Value store", "Singleton metadata", metadata.Size(), metadata.Count()},
-		{"Light client", "CHT trie nodes", chtTrieNodes.Size(), chtTrieNodes.Count()},
-		{"Light client", "Bloom trie nodes", bloomTrieNodes.Size(), bloomTrieNodes.Count()},
+	return []DatabaseStat{
+		newDatabaseStat("Key-Value store", "Headers", headers),
+		newDatabaseStat("Key-Value store", "Bodies", bodies),
+		newDatabaseStat("Key-Value store", "Receipt lists", receipts),
+		newDatabaseStat("Key-Value store", "Difficulties", tds),
+		newDatabaseStat("Key-Value store", "Block number->hash", numHashPairings),
+		newDatabaseStat("Key-Value store", "Block hash->number", hashNumPairings),
+		newDatabaseStat("Key-Value store", "Transaction index", txLookups),
+		newDatabaseStat("Key-Value store", "Bloombit index", bloomBits),
+		newDatabaseStat("Key-Value store", "Log index", filterMaps),
+		newDatabaseStat("Key-Value store", "Custom indexes", customIndexes),
+		newDatabaseStat("Key-Value store", "Contract codes", codes),
+		newDatabaseStat("Key-Value store", "Hash trie nodes", legacyTries),
+		newDatabaseStat("Key-Value store", "Path trie state lookups", stateLookups),
+		newDatabaseStat("Key-Value store", "Path trie account nodes", accountTries),
+		newDatabaseStat("Key-Value store", "Path trie storage nodes", storageTries),
+		newDatabaseStat("Key-Value store", "Trie preimages", preimages),
+		newDatabaseStat("Key-Value store", "Account snapshot", accountSnaps),
+		newDatabaseStat("Key-Value store", "Storage snapshot", storageSnaps),
+		newDatabaseStat("Key-Value store", "Beacon sync headers", beaconHeaders),
+		newDatabaseStat("Key-Value store", "Clique snapshots", cliqueSnaps),
+		newDatabaseStat("Key-Value store", "Singleton metadata", metadata),
+		newDatabaseStat("Light client", "CHT trie nodes", chtTrieNodes),
+		newDatabaseStat("Light client", "Bloom trie nodes", bloomTrieNodes),
+		newDatabaseStat("Key-Value store", "Unaccounted", unaccounted),
+	}, nil
+}
+
+// InspectChainFreezer returns the size of each table of the chain freezer.
+func InspectChainFreezer(db ethdb.AncientReader) ([]DatabaseStat, error) {
+	info, err := inspect(chainFreezerName, chainFreezerNoSnappy, db)
+	if err != nil {
+		return nil, err
+	}
+	return info.stats(), nil
+}
+
+// InspectDatabase traverses the entire database and checks the size
+// of all different categories of data.
+func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
+	kvstats, err := InspectKeyValueStore(db, keyPrefix, keyStart, nil)
+	if err != nil {
+		return err
+	}
+	var (
+		total common.StorageSize
+		stats [][]string
+	)
+	for _, s := range kvstats[:len(kvstats)-1] {
+		stats = append(stats, []string{s.Database, s.Category, common.StorageSize(s.Size).String(), counter(s.Items).String()})
+		total += common.StorageSize(s.Size)
 	}
+	unaccounted := kvstats[len(kvstats)-1]
+	total += common.StorageSize(unaccounted.Size)
+
 	// Inspect all registered append-only file store then.
 	ancients, err := inspectFreezers(db)
 	if err != nil {
 		return err
 	}
 	for _, ancient := range ancients {
-		for _, table := range ancient.sizes {
-			stats = append(stats, []string{
-				fmt.Sprintf("Ancient store (%s)", strings.Title(ancient.name)),
-				strings.Title(table.name),
-				table.size.String(),
-				fmt.Sprintf("%d", ancient.count()),
-			})
+		for _, s := range ancient.stats() {
+			stats = append(stats, []string{s.Database, s.Category, common.StorageSize(s.Size).String(), counter(s.Items).String()})
 		}
 		total += ancient.size()
 	}
@@ -620,8 +716,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 	table.AppendBulk(stats)
 	table.Render()
 
-	if unaccounted.size > 0 {
-		log.Error("Database contains unaccounted data", "size", unaccounted.size, "count", unaccounted.count)
+	if unaccounted.Size > 0 {
+		log.Error("Database contains unaccounted data", "size", common.StorageSize(unaccounted.Size), "count", unaccounted.Items)
 	}
 	return nil
 }
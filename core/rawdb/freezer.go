@@ -300,6 +300,22 @@ func (f *Freezer) TruncateHead(items uint64) (uint64, error) {
 	return oitems, nil
 }
 
+// RewriteAncient overwrites an ancient item in place, repairing corrupted data.
+// The encoded item must have the same size as the stored one.
+func (f *Freezer) RewriteAncient(kind string, number uint64, item []byte) error {
+	if f.readonly {
+		return errReadOnly
+	}
+	f.writeLock.Lock()
+	defer f.writeLock.Unlock()
+
+	table := f.tables[kind]
+	if table == nil {
+		return errUnknownTable
+	}
+	return table.rewriteItem(number, item)
+}
+
 // TruncateTail discards any recent data below the provided threshold number.
 func (f *Freezer) TruncateTail(tail uint64) (uint64, error) {
 	if f.readonly {
@@ -180,6 +180,15 @@ func (f *ResettableFreezer) TruncateHead(items uint64) (uint64, error) {
 	return f.freezer.TruncateHead(items)
 }
 
+// RewriteAncient overwrites an ancient item in place, the encoded item having
+// the same size as the stored one.
+func (f *ResettableFreezer) RewriteAncient(kind string, number uint64, item []byte) error {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.freezer.RewriteAncient(kind, number, item)
+}
+
 // TruncateTail discards any recent data below the provided threshold number.
 // It returns the previous value
 func (f *ResettableFreezer) TruncateTail(tail uint64) (uint64, error) {
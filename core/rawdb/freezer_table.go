@@ -856,6 +856,49 @@ func (t *freezerTable) retrieveItems(start, count, maxBytes uint64) ([]byte, []i
 	return output, sizes, nil
 }
 
+// rewriteItem overwrites the stored item with the given data in place. The item
+// is only rewritten if its encoding has the same size as the stored one, as the
+// following items can't be moved.
+func (t *freezerTable) rewriteItem(item uint64, blob []byte) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil || t.head == nil || t.meta == nil {
+		return errClosed
+	}
+	if item >= t.items.Load() || item < t.itemHidden.Load() {
+		return errOutOfBounds
+	}
+	indices, err := t.getIndices(item, 1)
+	if err != nil {
+		return err
+	}
+	start, end, filenum := indices[0].bounds(indices[1])
+	if !t.noCompression {
+		blob = snappy.Encode(nil, blob)
+	}
+	if len(blob) != int(end-start) {
+		return fmt.Errorf("item size mismatch: have %d, want %d", len(blob), end-start)
+	}
+	// The data files below the head are opened read only, write the item
+	// through a dedicated descriptor
+	name := fmt.Sprintf("%s.%04d.cdat", t.name, filenum)
+	if t.noCompression {
+		name = fmt.Sprintf("%s.%04d.rdat", t.name, filenum)
+	}
+	f, err := os.OpenFile(filepath.Join(t.path, name), os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteAt(blob, int64(start)); err != nil {
+		return err
+	}
+	t.logger.Warn("Rewrote freezer table item", "item", item, "size", len(blob))
+	return f.Sync()
+}
+
 // has returns an indicator whether the specified number data is still accessible
 // in the freezer table.
 func (t *freezerTable) has(number uint64) bool {
@@ -1334,3 +1334,44 @@ func TestRandom(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestFreezerRewriteItem tests rewriting items in place, which is only allowed
+// with data of the same size.
+func TestFreezerRewriteItem(t *testing.T) {
+	t.Parallel()
+	f, err := newTable(os.TempDir(),
+		fmt.Sprintf("unittest-%d", rand.Uint64()),
+		metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 50, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// Write 15 bytes 10 times, spanning multiple files
+	writeChunks(t, f, 10, 15)
+
+	for _, item := range []uint64{0, 4, 9} {
+		if err := f.rewriteItem(item, getChunk(15, 0xaa)); err != nil {
+			t.Fatalf("rewriting item %d: %v", item, err)
+		}
+	}
+	for y := 0; y < 10; y++ {
+		exp := getChunk(15, y)
+		if y == 0 || y == 4 || y == 9 {
+			exp = getChunk(15, 0xaa)
+		}
+		got, err := f.Retrieve(uint64(y))
+		if err != nil {
+			t.Fatalf("reading item %d: %v", y, err)
+		}
+		if !bytes.Equal(got, exp) {
+			t.Fatalf("test %d, got \n%x != \n%x", y, got, exp)
+		}
+	}
+	if err := f.rewriteItem(5, getChunk(16, 0xaa)); err == nil {
+		t.Fatal("rewrote item with a different size")
+	}
+	if err := f.rewriteItem(10, getChunk(15, 0xaa)); err != errOutOfBounds {
+		t.Fatalf("wrong error rewriting missing item: %v", err)
+	}
+}
@@ -82,6 +82,13 @@ var (
 	// txIndexTailKey tracks the oldest block whose transactions have been indexed.
 	txIndexTailKey = []byte("TransactionIndexTail")
 
+	// txSenderIndexTailKey tracks the oldest block whose transactions have been
+	// indexed by sender and nonce.
+	txSenderIndexTailKey = []byte("TransactionSenderIndexTail")
+
+	// filterMapsRangeKey tracks the range of blocks covered by the log index.
+	filterMapsRangeKey = []byte("FilterMapsRange")
+
 	// fastTxLookupLimitKey tracks the transaction lookup limit during fast sync.
 	fastTxLookupLimitKey = []byte("FastTransactionLookupLimit")
 
@@ -97,6 +104,21 @@ var (
 	// snapSyncStatusFlagKey flags that status of snap sync.
 	snapSyncStatusFlagKey = []byte("SnapSyncStatus")
 
+	// legacyHistoryBoundaryKey tracks the first block following the imported
+	// pre-bedrock history.
+	legacyHistoryBoundaryKey = []byte("LegacyHistoryBoundary")
+
+	// stateDatabaseIDKey pairs the chain and the state key-value stores of a
+	// database keeping the state in a directory of its own.
+	stateDatabaseIDKey = []byte("StateDatabaseID")
+
+	// reorgJournalKey tracks the blocks recently dropped by reorgs across restarts.
+	reorgJournalKey = []byte("ReorgJournal")
+
+	// replicaPromotionKey tracks the time a failover replica was promoted to
+	// sequencer, so that it keeps sequencing across restarts.
+	replicaPromotionKey = []byte("ReplicaPromotion")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -125,6 +147,10 @@ var (
 	// BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
 	BloomBitsIndexPrefix = []byte("iB")
 
+	// CustomIndexPrefix is the prefix of the data tables of the custom chain
+	// indexers, followed by the name of the indexer and a dash.
+	CustomIndexPrefix = []byte("oasys-idx-")
+
 	ChtPrefix           = []byte("chtRootV2-") // ChtPrefix + chtNum (uint64 big endian) -> trie root hash
 	ChtTablePrefix      = []byte("cht-")
 	ChtIndexTablePrefix = []byte("chtIndexV2-")
@@ -135,6 +161,11 @@ var (
 
 	CliqueSnapshotPrefix = []byte("clique-")
 
+	orderingAuditPrefix = []byte("oasys-ordering-audit-") // orderingAuditPrefix + hash -> ordering audit of a built block
+	txSenderPrefix      = []byte("oasys-tx-sender-")      // txSenderPrefix + sender + nonce (uint64 big endian) -> transaction hash
+	filterMapRowPrefix  = []byte("oasys-fm-")             // filterMapRowPrefix + map (uint64 big endian) + log value key + block offset (uint16 big endian) -> nil
+	depositRecordPrefix = []byte("oasys-deposits-")       // depositRecordPrefix + num (uint64 big endian) + hash -> deposits and withdrawals of a block
+
 	preimageCounter    = metrics.NewRegisteredCounter("db/preimage/total", nil)
 	preimageHitCounter = metrics.NewRegisteredCounter("db/preimage/hits", nil)
 )
@@ -147,6 +178,32 @@ type LegacyTxLookupEntry struct {
 	Index      uint64
 }
 
+// orderingAuditKey = orderingAuditPrefix + hash
+func orderingAuditKey(hash common.Hash) []byte {
+	return append(orderingAuditPrefix, hash.Bytes()...)
+}
+
+// txSenderKey = txSenderPrefix + sender + nonce (uint64 big endian)
+func txSenderKey(sender common.Address, nonce uint64) []byte {
+	key := append(append([]byte{}, txSenderPrefix...), sender.Bytes()...)
+	return binary.BigEndian.AppendUint64(key, nonce)
+}
+
+// filterMapKey = filterMapRowPrefix + map (uint64 big endian)
+func filterMapKey(mapIndex uint64) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte{}, filterMapRowPrefix...), mapIndex)
+}
+
+// filterMapRowKey = filterMapRowPrefix + map (uint64 big endian) + log value key + block offset (uint16 big endian)
+func filterMapRowKey(mapIndex uint64, value []byte, offset uint16) []byte {
+	return binary.BigEndian.AppendUint16(append(filterMapKey(mapIndex), value...), offset)
+}
+
+// depositRecordKey = depositRecordPrefix + num (uint64 big endian) + hash
+func depositRecordKey(number uint64, hash common.Hash) []byte {
+	return append(append(append([]byte{}, depositRecordPrefix...), encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
 // encodeBlockNumber encodes a block number as big endian uint64
 func encodeBlockNumber(number uint64) []byte {
 	enc := make([]byte, 8)
@@ -0,0 +1,346 @@
+package rawdb
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// stateMetadataKeys are the singleton keys tracking the persisted state, kept
+// along with it in the state key-value store of a split database.
+var stateMetadataKeys = [][]byte{
+	persistentStateIDKey, fastTrieProgressKey, snapshotDisabledKey, SnapshotRootKey,
+	snapshotJournalKey, snapshotGeneratorKey, snapshotRecoveryKey, snapshotSyncStatusKey,
+	trieJournalKey,
+}
+
+// isStateKey reports whether the given key belongs to the state: trie nodes of
+// both schemes, snapshot entries, contract codes and preimages, along with the
+// metadata tracking them. The routing only depends on the key, so a key of the
+// chain data misclassified as state is still stored and found consistently.
+func isStateKey(key []byte) bool {
+	switch {
+	case len(key) == common.HashLength: // Hash-based trie node
+		return true
+	case bytes.HasPrefix(key, stateIDPrefix) && len(key) == len(stateIDPrefix)+common.HashLength:
+		return true
+	case IsAccountTrieNode(key) || IsStorageTrieNode(key):
+		return true
+	case bytes.HasPrefix(key, CodePrefix) && len(key) == len(CodePrefix)+common.HashLength:
+		return true
+	case bytes.HasPrefix(key, SnapshotAccountPrefix) && len(key) == len(SnapshotAccountPrefix)+common.HashLength:
+		return true
+	case bytes.HasPrefix(key, SnapshotStoragePrefix) && len(key) == len(SnapshotStoragePrefix)+2*common.HashLength:
+		return true
+	case bytes.HasPrefix(key, PreimagePrefix) && len(key) == len(PreimagePrefix)+common.HashLength:
+		return true
+	}
+	for _, meta := range stateMetadataKeys {
+		if bytes.Equal(key, meta) {
+			return true
+		}
+	}
+	return false
+}
+
+// stateStore is a key-value store keeping the state in a key-value store of its
+// own, e.g. on a faster volume, and the rest of the data in the chain one. Both
+// stores are paired on creation, refusing to be opened with another one.
+//
+// The writes of a batch spanning both stores are not atomic: the state is
+// written first, the chain data tracking it, e.g. the head markers, last. After
+// a crash, the chain data may thus miss the latest state, never the other way
+// around, which is recovered from as if the state was not flushed.
+type stateStore struct {
+	chain ethdb.KeyValueStore
+	state ethdb.KeyValueStore
+}
+
+// newStateStore pairs the given chain and state key-value stores, failing if
+// they were not created together.
+func newStateStore(chain, state ethdb.KeyValueStore, readonly bool) (*stateStore, error) {
+	chainID, _ := chain.Get(stateDatabaseIDKey)
+	stateID, _ := state.Get(stateDatabaseIDKey)
+
+	switch {
+	case chainID != nil && bytes.Equal(chainID, stateID):
+		// Stores created together, nothing to do
+	case chainID != nil:
+		return nil, errors.New("state database does not belong to the chain database")
+	default:
+		// A chain store initialized without the state store would lose its state
+		if ReadHeadHeaderHash(chain) != (common.Hash{}) {
+			return nil, errors.New("chain database already initialized without a separate state database")
+		}
+		// A state store holding more than its pairing marker belongs to another chain
+		it := state.NewIterator(nil, nil)
+		used := it.Next() && (!bytes.Equal(it.Key(), stateDatabaseIDKey) || it.Next())
+		it.Release()
+		if used {
+			return nil, errors.New("state database already in use")
+		}
+		if readonly {
+			break
+		}
+		// Pair the stores, the state one first to resume after a crash
+		if stateID == nil {
+			stateID = make([]byte, common.HashLength)
+			if _, err := rand.Read(stateID); err != nil {
+				return nil, err
+			}
+			if err := state.Put(stateDatabaseIDKey, stateID); err != nil {
+				return nil, err
+			}
+		}
+		if err := chain.Put(stateDatabaseIDKey, stateID); err != nil {
+			return nil, err
+		}
+	}
+	return &stateStore{chain: chain, state: state}, nil
+}
+
+// store returns the key-value store the given key belongs to.
+func (s *stateStore) store(key []byte) ethdb.KeyValueStore {
+	if isStateKey(key) {
+		return s.state
+	}
+	return s.chain
+}
+
+// Has retrieves if a key is present in the key-value store it belongs to.
+func (s *stateStore) Has(key []byte) (bool, error) {
+	return s.store(key).Has(key)
+}
+
+// Get retrieves the given key from the key-value store it belongs to.
+func (s *stateStore) Get(key []byte) ([]byte, error) {
+	return s.store(key).Get(key)
+}
+
+// Put inserts the given value into the key-value store the key belongs to.
+func (s *stateStore) Put(key []byte, value []byte) error {
+	return s.store(key).Put(key, value)
+}
+
+// Delete removes the key from the key-value store it belongs to.
+func (s *stateStore) Delete(key []byte) error {
+	return s.store(key).Delete(key)
+}
+
+// Stat returns a particular internal stat of both key-value stores.
+func (s *stateStore) Stat(property string) (string, error) {
+	chain, err := s.chain.Stat(property)
+	if err != nil {
+		return "", err
+	}
+	state, err := s.state.Stat(property)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Chain database:\n%s\nState database:\n%s", chain, state), nil
+}
+
+// Compact flattens the underlying data stores for the given key range.
+func (s *stateStore) Compact(start []byte, limit []byte) error {
+	if err := s.state.Compact(start, limit); err != nil {
+		return err
+	}
+	return s.chain.Compact(start, limit)
+}
+
+// NewBatch creates a write-only batch spanning both key-value stores.
+func (s *stateStore) NewBatch() ethdb.Batch {
+	return &stateBatch{chain: s.chain.NewBatch(), state: s.state.NewBatch()}
+}
+
+// NewBatchWithSize creates a write-only batch spanning both key-value stores
+// with pre-allocated buffers.
+func (s *stateStore) NewBatchWithSize(size int) ethdb.Batch {
+	return &stateBatch{chain: s.chain.NewBatchWithSize(size), state: s.state.NewBatchWithSize(size)}
+}
+
+// NewIterator creates an iterator over both key-value stores, merging their
+// keys in lexicographic order.
+func (s *stateStore) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
+	return newStateIterator(s.chain.NewIterator(prefix, start), s.state.NewIterator(prefix, start))
+}
+
+// NewSnapshot creates a snapshot of both key-value stores, the state one first.
+// The snapshots are not taken atomically.
+func (s *stateStore) NewSnapshot() (ethdb.Snapshot, error) {
+	state, err := s.state.NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	chain, err := s.chain.NewSnapshot()
+	if err != nil {
+		state.Release()
+		return nil, err
+	}
+	return &stateSnapshot{chain: chain, state: state}, nil
+}
+
+// Close closes both key-value stores.
+func (s *stateStore) Close() error {
+	stateErr := s.state.Close()
+	if err := s.chain.Close(); err != nil {
+		return err
+	}
+	return stateErr
+}
+
+// stateBatch is a batch spanning the chain and the state key-value stores.
+type stateBatch struct {
+	chain ethdb.Batch
+	state ethdb.Batch
+}
+
+// Put inserts the given value into the batch of the store the key belongs to.
+func (b *stateBatch) Put(key []byte, value []byte) error {
+	if isStateKey(key) {
+		return b.state.Put(key, value)
+	}
+	return b.chain.Put(key, value)
+}
+
+// Delete inserts a key removal into the batch of the store the key belongs to.
+func (b *stateBatch) Delete(key []byte) error {
+	if isStateKey(key) {
+		return b.state.Delete(key)
+	}
+	return b.chain.Delete(key)
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *stateBatch) ValueSize() int {
+	return b.chain.ValueSize() + b.state.ValueSize()
+}
+
+// Write flushes the state changes first, then the chain ones.
+func (b *stateBatch) Write() error {
+	if err := b.state.Write(); err != nil {
+		return err
+	}
+	return b.chain.Write()
+}
+
+// Reset resets the batch for reuse.
+func (b *stateBatch) Reset() {
+	b.chain.Reset()
+	b.state.Reset()
+}
+
+// Replay replays the state changes first, then the chain ones.
+func (b *stateBatch) Replay(w ethdb.KeyValueWriter) error {
+	if err := b.state.Replay(w); err != nil {
+		return err
+	}
+	return b.chain.Replay(w)
+}
+
+// stateIterator merges the iterators over the chain and the state key-value
+// stores. The entries of the state store not belonging to the state, i.e. its
+// pairing marker, are skipped so that no key is iterated twice.
+type stateIterator struct {
+	chain, state     ethdb.Iterator
+	chainOk, stateOk bool           // Whether the iterators are positioned on an entry
+	cur              ethdb.Iterator // Iterator positioned on the current entry, nil if none
+}
+
+func newStateIterator(chain, state ethdb.Iterator) *stateIterator {
+	it := &stateIterator{
+		chain:   chain,
+		state:   state,
+		chainOk: chain.Next(),
+	}
+	it.nextState()
+	return it
+}
+
+// nextState moves the state iterator to its next state entry.
+func (it *stateIterator) nextState() {
+	for it.stateOk = it.state.Next(); it.stateOk && !isStateKey(it.state.Key()); it.stateOk = it.state.Next() {
+	}
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted.
+func (it *stateIterator) Next() bool {
+	switch it.cur {
+	case it.chain:
+		it.chainOk = it.chain.Next()
+	case it.state:
+		it.nextState()
+	}
+	switch {
+	case !it.chainOk && !it.stateOk:
+		it.cur = nil
+	case !it.stateOk || (it.chainOk && bytes.Compare(it.chain.Key(), it.state.Key()) < 0):
+		it.cur = it.chain
+	default:
+		it.cur = it.state
+	}
+	return it.cur != nil
+}
+
+// Error returns any accumulated error of either iterator.
+func (it *stateIterator) Error() error {
+	if err := it.chain.Error(); err != nil {
+		return err
+	}
+	return it.state.Error()
+}
+
+// Key returns the key of the current key/value pair, or nil if done.
+func (it *stateIterator) Key() []byte {
+	if it.cur == nil {
+		return nil
+	}
+	return it.cur.Key()
+}
+
+// Value returns the value of the current key/value pair, or nil if done.
+func (it *stateIterator) Value() []byte {
+	if it.cur == nil {
+		return nil
+	}
+	return it.cur.Value()
+}
+
+// Release releases both iterators.
+func (it *stateIterator) Release() {
+	it.chain.Release()
+	it.state.Release()
+}
+
+// stateSnapshot is a snapshot of the chain and the state key-value stores.
+type stateSnapshot struct {
+	chain ethdb.Snapshot
+	state ethdb.Snapshot
+}
+
+// Has retrieves if a key is present in the snapshot of the store it belongs to.
+func (s *stateSnapshot) Has(key []byte) (bool, error) {
+	if isStateKey(key) {
+		return s.state.Has(key)
+	}
+	return s.chain.Has(key)
+}
+
+// Get retrieves the given key from the snapshot of the store it belongs to.
+func (s *stateSnapshot) Get(key []byte) ([]byte, error) {
+	if isStateKey(key) {
+		return s.state.Get(key)
+	}
+	return s.chain.Get(key)
+}
+
+// Release releases both snapshots.
+func (s *stateSnapshot) Release() {
+	s.chain.Release()
+	s.state.Release()
+}
@@ -0,0 +1,75 @@
+package rawdb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+)
+
+func TestStateStore(t *testing.T) {
+	chain, state := memorydb.New(), memorydb.New()
+	store, err := newStateStore(chain, state, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := NewDatabase(store)
+
+	// The state goes to the state store, the chain data to the chain one
+	WriteCode(db, common.Hash{0x01}, []byte{0x01})
+	WriteHeadHeaderHash(db, common.Hash{0x02})
+	batch := db.NewBatch()
+	WriteLegacyTrieNode(batch, common.Hash{0x03}, []byte{0x03})
+	WriteCanonicalHash(batch, common.Hash{0x04}, 4)
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := state.Has(codeKey(common.Hash{0x01})); !ok {
+		t.Fatalf("code not in the state store")
+	}
+	if ok, _ := state.Has(common.Hash{0x03}.Bytes()); !ok {
+		t.Fatalf("trie node not in the state store")
+	}
+	if ok, _ := chain.Has(headHeaderKey); !ok {
+		t.Fatalf("head header not in the chain store")
+	}
+	if ok, _ := chain.Has(headerHashKey(4)); !ok {
+		t.Fatalf("canonical hash not in the chain store")
+	}
+	if ReadHeadHeaderHash(db) != (common.Hash{0x02}) || len(ReadCode(db, common.Hash{0x01})) != 1 {
+		t.Fatalf("entries not found")
+	}
+	// Iterating merges both stores in order
+	var (
+		it   = db.NewIterator(nil, nil)
+		prev []byte
+		n    int
+	)
+	for it.Next() {
+		if prev != nil && bytes.Compare(prev, it.Key()) >= 0 {
+			t.Fatalf("keys out of order: %x after %x", it.Key(), prev)
+		}
+		prev = common.CopyBytes(it.Key())
+		n++
+	}
+	it.Release()
+	if n != 5 { // 4 entries and the pairing marker
+		t.Fatalf("iterated entry count mismatch: have %d, want 5", n)
+	}
+	// The stores only open with each other
+	if _, err := newStateStore(chain, state, false); err != nil {
+		t.Fatalf("paired stores rejected: %v", err)
+	}
+	if _, err := newStateStore(chain, memorydb.New(), false); err == nil {
+		t.Fatalf("unpaired state store accepted")
+	}
+	if _, err := newStateStore(memorydb.New(), state, false); err == nil {
+		t.Fatalf("used state store accepted")
+	}
+	unsplit := memorydb.New()
+	WriteHeadHeaderHash(unsplit, common.Hash{0x02})
+	if _, err := newStateStore(unsplit, memorydb.New(), false); err == nil {
+		t.Fatalf("chain store initialized without state store accepted")
+	}
+}
@@ -101,6 +101,12 @@ func (t *table) TruncateHead(items uint64) (uint64, error) {
 	return t.db.TruncateHead(items)
 }
 
+// RewriteAncient is a noop passthrough that just forwards the request to the underlying
+// database.
+func (t *table) RewriteAncient(kind string, number uint64, item []byte) error {
+	return t.db.RewriteAncient(kind, number, item)
+}
+
 // TruncateTail is a noop passthrough that just forwards the request to the underlying
 // database.
 func (t *table) TruncateTail(items uint64) (uint64, error) {
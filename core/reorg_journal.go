@@ -0,0 +1,97 @@
+package core
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// reorgJournalLimit is the maximum number of reorgs kept in the journal.
+	reorgJournalLimit = 128
+
+	// reorgJournalRetention is the time reorgs are kept in the journal.
+	reorgJournalRetention = time.Hour
+)
+
+// ReorgJournalEntry records the blocks dropped from the canonical chain by a reorg.
+type ReorgJournalEntry struct {
+	Time    uint64              // Unix time of the reorg
+	Dropped []ReorgDroppedBlock // Blocks dropped, in ascending order
+}
+
+// ReorgDroppedBlock identifies a block dropped from the canonical chain.
+type ReorgDroppedBlock struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+// readReorgJournal returns the reorgs journaled within the retention period.
+func (bc *BlockChain) readReorgJournal() []*ReorgJournalEntry {
+	data := rawdb.ReadReorgJournal(bc.db)
+	if len(data) == 0 {
+		return nil
+	}
+	var entries []*ReorgJournalEntry
+	if err := rlp.DecodeBytes(data, &entries); err != nil {
+		log.Warn("Failed to decode reorg journal", "err", err)
+		return nil
+	}
+	cutoff := uint64(time.Now().Add(-reorgJournalRetention).Unix())
+	for len(entries) > 0 && entries[0].Time < cutoff {
+		entries = entries[1:]
+	}
+	return entries
+}
+
+// journalReorg records the blocks dropped by a reorg before the canonical chain is
+// rewritten, so the removal of their logs can be delivered even if the node stops
+// before the reorg events are sent.
+func (bc *BlockChain) journalReorg(oldChain types.Blocks) {
+	if len(oldChain) == 0 {
+		return
+	}
+	entry := &ReorgJournalEntry{Time: uint64(time.Now().Unix())}
+	for i := len(oldChain) - 1; i >= 0; i-- {
+		entry.Dropped = append(entry.Dropped, ReorgDroppedBlock{Number: oldChain[i].NumberU64(), Hash: oldChain[i].Hash()})
+	}
+	entries := append(bc.readReorgJournal(), entry)
+	if len(entries) > reorgJournalLimit {
+		entries = entries[len(entries)-reorgJournalLimit:]
+	}
+	data, err := rlp.EncodeToBytes(entries)
+	if err != nil {
+		log.Error("Failed to encode reorg journal", "err", err)
+		return
+	}
+	rawdb.WriteReorgJournal(bc.db, data)
+}
+
+// JournaledRemovedLogs returns the logs of the journaled blocks at or above the
+// given number which are no longer canonical, flagged as removed, in the order
+// the reorgs happened.
+func (bc *BlockChain) JournaledRemovedLogs(from uint64) []*types.Log {
+	var (
+		logs []*types.Log
+		seen = make(map[common.Hash]bool)
+	)
+	for _, entry := range bc.readReorgJournal() {
+		for _, dropped := range entry.Dropped {
+			if dropped.Number < from || seen[dropped.Hash] {
+				continue
+			}
+			seen[dropped.Hash] = true
+			if bc.GetCanonicalHash(dropped.Number) == dropped.Hash {
+				continue // Reorged back into the canonical chain
+			}
+			if block := bc.GetBlock(dropped.Hash, dropped.Number); block != nil {
+				logs = append(logs, bc.collectLogs(block, true)...)
+			}
+		}
+	}
+	return logs
+}
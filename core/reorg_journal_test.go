@@ -0,0 +1,75 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the logs dropped by a reorg are still retrievable as removed after
+// the chain is reopened, until reorged back into the canonical chain.
+func TestReorgJournal(t *testing.T) {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		gspec  = &Genesis{Config: params.TestChainConfig, Alloc: GenesisAlloc{addr: {Balance: big.NewInt(10000000000000000)}}}
+		signer = types.LatestSigner(gspec.Config)
+		db     = rawdb.NewMemoryDatabase()
+	)
+	blockchain, _ := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+
+	_, logChain, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 2, func(i int, gen *BlockGen) {
+		if i == 1 {
+			tx, err := types.SignTx(types.NewContractCreation(gen.TxNonce(addr), new(big.Int), 1000000, gen.header.BaseFee, logCode), signer, key)
+			if err != nil {
+				t.Fatalf("failed to create tx: %v", err)
+			}
+			gen.AddTx(tx)
+		}
+	})
+	if _, err := blockchain.InsertChain(logChain); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	_, forkChain, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 3, func(i int, gen *BlockGen) {})
+	if _, err := blockchain.InsertChain(forkChain); err != nil {
+		t.Fatalf("failed to insert forked chain: %v", err)
+	}
+	blockchain.Stop()
+
+	// Reopen the chain, the removed logs must be served from the journal
+	blockchain, _ = NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer blockchain.Stop()
+
+	logs := blockchain.JournaledRemovedLogs(0)
+	if len(logs) != 1 {
+		t.Fatalf("removed log count mismatch: have %d, want 1", len(logs))
+	}
+	if !logs[0].Removed || logs[0].BlockHash != logChain[1].Hash() {
+		t.Fatalf("removed log mismatch: removed %v, block %x, want %x", logs[0].Removed, logs[0].BlockHash, logChain[1].Hash())
+	}
+	if logs := blockchain.JournaledRemovedLogs(3); len(logs) != 0 {
+		t.Fatalf("removed logs returned above the dropped blocks: %d", len(logs))
+	}
+	// Reorg back to the chain with the log, which must not be reported removed anymore
+	_, backChain, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 4, func(i int, gen *BlockGen) {
+		if i == 1 {
+			tx, _ := types.SignTx(types.NewContractCreation(gen.TxNonce(addr), new(big.Int), 1000000, gen.header.BaseFee, logCode), signer, key)
+			gen.AddTx(tx)
+		}
+	})
+	if backChain[1].Hash() != logChain[1].Hash() {
+		t.Fatal("regenerated chain mismatch")
+	}
+	if _, err := blockchain.InsertChain(backChain); err != nil {
+		t.Fatalf("failed to insert chain back: %v", err)
+	}
+	if logs := blockchain.JournaledRemovedLogs(0); len(logs) != 0 {
+		t.Fatalf("removed logs returned after reorging back: %d", len(logs))
+	}
+}
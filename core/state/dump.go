@@ -17,8 +17,10 @@
 package state
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -37,6 +39,8 @@ type DumpConfig struct {
 	OnlyWithAddresses bool
 	Start             []byte
 	Max               uint64
+	AddressPrefix     []byte       // Only dump the accounts whose address starts with the prefix
+	Interrupt         *atomic.Bool // Stops the iteration once set, if non-nil
 }
 
 // DumpCollector interface which the state trie calls during iteration
@@ -146,6 +150,10 @@ func (s *StateDB) DumpToCollector(c DumpCollector, conf *DumpConfig) (nextKey []
 	}
 	it := trie.NewIterator(trieIt)
 	for it.Next() {
+		if conf.Interrupt != nil && conf.Interrupt.Load() {
+			log.Info("Trie dumping interrupted", "at", it.Key, "accounts", accounts)
+			break
+		}
 		var data types.StateAccount
 		if err := rlp.DecodeBytes(it.Value, &data); err != nil {
 			panic(err)
@@ -171,6 +179,9 @@ func (s *StateDB) DumpToCollector(c DumpCollector, conf *DumpConfig) (nextKey []
 		} else {
 			address = &addr
 		}
+		if len(conf.AddressPrefix) > 0 && (address == nil || !bytes.HasPrefix(addrBytes, conf.AddressPrefix)) {
+			continue
+		}
 		obj := newObject(s, addr, &data)
 		if !conf.SkipCode {
 			account.Code = obj.Code()
@@ -33,6 +33,10 @@ import (
 const (
 	snapAccount = "account" // Identifier of account snapshot generation
 	snapStorage = "storage" // Identifier of storage snapshot generation
+
+	// throttleMinWait is the shortest pause of a throttled generation, avoiding
+	// a sleep for each account or slot indexed.
+	throttleMinWait = 100 * time.Millisecond
 )
 
 // generatorStats is a collection of statistics gathered by the snapshot generator
@@ -72,19 +76,63 @@ func (gs *generatorStats) Log(msg string, root common.Hash, marker []byte) {
 		"elapsed", common.PrettyDuration(time.Since(gs.start)),
 	}...)
 	// Calculate the estimated indexing time based on current stats
-	if len(marker) > 0 {
-		if done := binary.BigEndian.Uint64(marker[:8]) - gs.origin; done > 0 {
-			left := math.MaxUint64 - binary.BigEndian.Uint64(marker[:8])
-
-			speed := done/uint64(time.Since(gs.start)/time.Millisecond+1) + 1 // +1s to avoid division by zero
-			ctx = append(ctx, []interface{}{
-				"eta", common.PrettyDuration(time.Duration(left/speed) * time.Millisecond),
-			}...)
-		}
+	if eta, ok := gs.eta(marker); ok {
+		ctx = append(ctx, []interface{}{
+			"eta", common.PrettyDuration(eta),
+		}...)
 	}
 	log.Info(msg, ctx...)
 }
 
+// eta estimates the time left to index the state after the given marker, from
+// the speed of the generation since it started.
+func (gs *generatorStats) eta(marker []byte) (time.Duration, bool) {
+	if len(marker) == 0 {
+		return 0, false
+	}
+	done := binary.BigEndian.Uint64(marker[:8]) - gs.origin
+	if done == 0 {
+		return 0, false
+	}
+	left := math.MaxUint64 - binary.BigEndian.Uint64(marker[:8])
+
+	speed := done/uint64(time.Since(gs.start)/time.Millisecond+1) + 1 // +1s to avoid division by zero
+	return time.Duration(left/speed) * time.Millisecond, true
+}
+
+// GenerationProgress is the progress of the background generation of a state
+// snapshot.
+type GenerationProgress struct {
+	Root     common.Hash        // Root of the state being indexed
+	Marker   []byte             // Account (and slot) hash indexed last
+	Accounts uint64             // Number of accounts indexed (generated or recovered)
+	Slots    uint64             // Number of storage slots indexed (generated or recovered)
+	Dangling uint64             // Number of dangling storage slots deleted
+	Storage  common.StorageSize // Total account and storage slot size indexed
+	Done     float64            // Fraction of the account hash space indexed
+	Started  time.Time          // Start of the current generation run
+	ETA      time.Duration      // Estimated time left, zero if not yet known
+}
+
+// progress assembles the progress of the generation of the given state, indexed
+// up to the given marker.
+func (gs *generatorStats) progress(root common.Hash, marker []byte) *GenerationProgress {
+	progress := &GenerationProgress{
+		Root:     root,
+		Marker:   common.CopyBytes(marker),
+		Accounts: gs.accounts,
+		Slots:    gs.slots,
+		Dangling: gs.dangling,
+		Storage:  gs.storage,
+		Started:  gs.start,
+	}
+	if len(marker) > 0 {
+		progress.Done = float64(binary.BigEndian.Uint64(marker[:8])) / float64(math.MaxUint64)
+	}
+	progress.ETA, _ = gs.eta(marker)
+	return progress
+}
+
 // generatorContext carries a few global values to be shared by all generation functions.
 type generatorContext struct {
 	stats   *generatorStats     // Generation statistic collection
@@ -93,6 +141,9 @@ type generatorContext struct {
 	storage *holdableIterator   // Iterator of storage snapshot data
 	batch   ethdb.Batch         // Database batch for writing batch data atomically
 	logged  time.Time           // The timestamp when last generation progress was displayed
+
+	paced     time.Time          // Start of the current throttling window
+	pacedSize common.StorageSize // Size of the state indexed before the throttling window
 }
 
 // newGeneratorContext initializes the context for generation.
@@ -102,7 +153,9 @@ func newGeneratorContext(stats *generatorStats, db ethdb.KeyValueStore, accMarke
 		db:     db,
 		batch:  db.NewBatch(),
 		logged: time.Now(),
+		paced:  time.Now(),
 	}
+	ctx.pacedSize = stats.storage
 	ctx.openIterator(snapAccount, accMarker)
 	ctx.openIterator(snapStorage, storageMarker)
 	return ctx
@@ -146,6 +199,36 @@ func (ctx *generatorContext) reopenIterator(kind string) {
 	ctx.openIterator(kind, next[1:])
 }
 
+// throttle pauses the generation for as long as the state indexed within the
+// current window exceeds the given rate in bytes per second, returning early
+// with the interruption signal if one is received on the given channel. The
+// window restarts after each pause and at least every second, so that the
+// generator does not accumulate any allowance while it is slowed down by the
+// database.
+func (ctx *generatorContext) throttle(rate uint64, abort chan chan *generatorStats) chan *generatorStats {
+	if rate > 0 {
+		wait := time.Duration(float64(ctx.stats.storage-ctx.pacedSize)/float64(rate)*float64(time.Second)) - time.Since(ctx.paced)
+		if wait > throttleMinWait {
+			snapThrottleCounter.Inc(wait.Nanoseconds())
+
+			timer := time.NewTimer(wait)
+			defer timer.Stop()
+
+			select {
+			case <-timer.C:
+			case signal := <-abort:
+				return signal
+			}
+			ctx.paced, ctx.pacedSize = time.Now(), ctx.stats.storage
+			return nil
+		}
+	}
+	if time.Since(ctx.paced) >= time.Second {
+		ctx.paced, ctx.pacedSize = time.Now(), ctx.stats.storage
+	}
+	return nil
+}
+
 // close releases all the held resources.
 func (ctx *generatorContext) close() {
 	ctx.account.Release()
@@ -19,6 +19,7 @@ package snapshot
 import (
 	"bytes"
 	"sync"
+	"sync/atomic"
 
 	"github.com/VictoriaMetrics/fastcache"
 	"github.com/ethereum/go-ethereum/common"
@@ -42,6 +43,9 @@ type diskLayer struct {
 	genPending chan struct{}             // Notification channel when generation is done (test synchronicity)
 	genAbort   chan chan *generatorStats // Notification channel to abort generating the snapshot in this layer
 
+	genRate     *atomic.Uint64      // Maximum bytes of state indexed per second by the generation, nil if unlimited
+	genProgress *GenerationProgress // Progress of the generation, updated on each flush and log
+
 	lock sync.RWMutex
 }
 
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/VictoriaMetrics/fastcache"
@@ -54,8 +55,9 @@ var (
 
 // generateSnapshot regenerates a brand new snapshot based on an existing state
 // database and head block asynchronously. The snapshot is returned immediately
-// and generation is continued in the background until done.
-func generateSnapshot(diskdb ethdb.KeyValueStore, triedb *trie.Database, cache int, root common.Hash) *diskLayer {
+// and generation is continued in the background until done, throttled to the
+// given rate if any.
+func generateSnapshot(diskdb ethdb.KeyValueStore, triedb *trie.Database, cache int, root common.Hash, rate *atomic.Uint64) *diskLayer {
 	// Create a new disk layer with an initialized state marker at zero
 	var (
 		stats     = &generatorStats{start: time.Now()}
@@ -75,6 +77,7 @@ func generateSnapshot(diskdb ethdb.KeyValueStore, triedb *trie.Database, cache i
 		genMarker:  genMarker,
 		genPending: make(chan struct{}),
 		genAbort:   make(chan chan *generatorStats),
+		genRate:    rate,
 	}
 	go base.generate(stats)
 	log.Debug("Start snapshot generation", "root", root)
@@ -481,6 +484,9 @@ func (dl *diskLayer) checkAndFlush(ctx *generatorContext, current []byte) error
 	case abort = <-dl.genAbort:
 	default:
 	}
+	if abort == nil && dl.genRate != nil {
+		abort = ctx.throttle(dl.genRate.Load(), dl.genAbort)
+	}
 	if ctx.batch.ValueSize() > ethdb.IdealBatchSize || abort != nil {
 		if bytes.Compare(current, dl.genMarker) < 0 {
 			log.Error("Snapshot generator went backwards", "current", fmt.Sprintf("%x", current), "genMarker", fmt.Sprintf("%x", dl.genMarker))
@@ -498,6 +504,7 @@ func (dl *diskLayer) checkAndFlush(ctx *generatorContext, current []byte) error
 		dl.lock.Lock()
 		dl.genMarker = current
 		dl.lock.Unlock()
+		dl.reportProgress(ctx.stats, current)
 
 		if abort != nil {
 			ctx.stats.Log("Aborting state snapshot generation", dl.root, current)
@@ -510,10 +517,22 @@ func (dl *diskLayer) checkAndFlush(ctx *generatorContext, current []byte) error
 	if time.Since(ctx.logged) > 8*time.Second {
 		ctx.stats.Log("Generating state snapshot", dl.root, current)
 		ctx.logged = time.Now()
+		dl.reportProgress(ctx.stats, current)
 	}
 	return nil
 }
 
+// reportProgress publishes the progress of the generation up to the given marker.
+func (dl *diskLayer) reportProgress(stats *generatorStats, marker []byte) {
+	progress := stats.progress(dl.root, marker)
+	snapGenerationDoneGauge.Update(progress.Done)
+	snapGenerationEtaGauge.Update(int64(progress.ETA / time.Second))
+
+	dl.lock.Lock()
+	dl.genProgress = progress
+	dl.lock.Unlock()
+}
+
 // generateStorages generates the missing storage slots of the specific contract.
 // It's supposed to restart the generation from the given origin position.
 func generateStorages(ctx *generatorContext, dl *diskLayer, stateRoot common.Hash, account common.Hash, storageRoot common.Hash, storeMarker []byte) error {
@@ -672,6 +691,7 @@ func (dl *diskLayer) generate(stats *generatorStats) {
 		accMarker = dl.genMarker[:common.HashLength]
 	}
 	stats.Log("Resuming state snapshot generation", dl.root, dl.genMarker)
+	dl.reportProgress(stats, dl.genMarker)
 
 	// Initialize the global generator context. The snapshot iterators are
 	// opened at the interrupted position because the assumption is held
@@ -717,6 +737,9 @@ func (dl *diskLayer) generate(stats *generatorStats) {
 	close(dl.genPending)
 	dl.lock.Unlock()
 
+	snapGenerationDoneGauge.Update(1)
+	snapGenerationEtaGauge.Update(0)
+
 	// Someone will be looking for us, wait it out
 	abort = <-dl.genAbort
 	abort <- nil
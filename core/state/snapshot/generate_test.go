@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -228,7 +229,7 @@ func (t *testHelper) Commit() common.Hash {
 
 func (t *testHelper) CommitAndGenerate() (common.Hash, *diskLayer) {
 	root := t.Commit()
-	snap := generateSnapshot(t.diskdb, t.triedb, 16, root)
+	snap := generateSnapshot(t.diskdb, t.triedb, 16, root, nil)
 	return root, snap
 }
 
@@ -430,7 +431,7 @@ func testGenerateCorruptAccountTrie(t *testing.T, scheme string) {
 
 	rawdb.DeleteTrieNode(helper.diskdb, common.Hash{}, targetPath, targetHash, scheme)
 
-	snap := generateSnapshot(helper.diskdb, helper.triedb, 16, root)
+	snap := generateSnapshot(helper.diskdb, helper.triedb, 16, root, nil)
 	select {
 	case <-snap.genPending:
 		// Snapshot generation succeeded
@@ -445,6 +446,42 @@ func testGenerateCorruptAccountTrie(t *testing.T, scheme string) {
 	<-stop
 }
 
+// Tests that a throttled snapshot generation pauses, reporting its progress, and
+// that it can still be aborted while paused.
+func TestGenerateThrottled(t *testing.T) {
+	helper := newHelper(rawdb.HashScheme)
+	for i := 0; i < 3; i++ {
+		helper.addTrieAccount(fmt.Sprintf("acc-%d", i), &types.StateAccount{Balance: big.NewInt(int64(i)), Root: types.EmptyRootHash, CodeHash: types.EmptyCodeHash.Bytes()})
+	}
+	root := helper.Commit()
+
+	rate := new(atomic.Uint64)
+	rate.Store(1) // Pauses for tens of seconds after the first account
+	snap := generateSnapshot(helper.diskdb, helper.triedb, 16, root, rate)
+	select {
+	case <-snap.genPending:
+		t.Fatalf("Throttled snapshot generated")
+	case <-time.After(500 * time.Millisecond):
+	}
+	snap.lock.RLock()
+	progress := snap.genProgress
+	snap.lock.RUnlock()
+	if progress == nil || progress.Root != root {
+		t.Fatalf("Generation progress not reported: %+v", progress)
+	}
+	// Signal abortion to the paused generator and wait for it to tear down
+	stop := make(chan *generatorStats)
+	snap.genAbort <- stop
+	select {
+	case stats := <-stop:
+		if stats == nil || stats.accounts != 1 {
+			t.Fatalf("Unexpected generation stats: %+v", stats)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Throttled generation not aborted")
+	}
+}
+
 // Tests that snapshot generation errors out correctly in case of a missing root
 // trie node for a storage trie. It's similar to internal corruption but it is
 // handled differently inside the generator.
@@ -474,7 +511,7 @@ func testGenerateMissingStorageTrie(t *testing.T, scheme string) {
 	rawdb.DeleteTrieNode(helper.diskdb, acc1, nil, stRoot, scheme)
 	rawdb.DeleteTrieNode(helper.diskdb, acc3, nil, stRoot, scheme)
 
-	snap := generateSnapshot(helper.diskdb, helper.triedb, 16, root)
+	snap := generateSnapshot(helper.diskdb, helper.triedb, 16, root, nil)
 	select {
 	case <-snap.genPending:
 		// Snapshot generation succeeded
@@ -516,7 +553,7 @@ func testGenerateCorruptStorageTrie(t *testing.T, scheme string) {
 	rawdb.DeleteTrieNode(helper.diskdb, hashData([]byte("acc-1")), targetPath, targetHash, scheme)
 	rawdb.DeleteTrieNode(helper.diskdb, hashData([]byte("acc-3")), targetPath, targetHash, scheme)
 
-	snap := generateSnapshot(helper.diskdb, helper.triedb, 16, root)
+	snap := generateSnapshot(helper.diskdb, helper.triedb, 16, root, nil)
 	select {
 	case <-snap.genPending:
 		// Snapshot generation succeeded
@@ -580,7 +617,7 @@ func testGenerateWithExtraAccounts(t *testing.T, scheme string) {
 	if data := rawdb.ReadStorageSnapshot(helper.diskdb, hashData([]byte("acc-2")), hashData([]byte("b-key-1"))); data == nil {
 		t.Fatalf("expected snap storage to exist")
 	}
-	snap := generateSnapshot(helper.diskdb, helper.triedb, 16, root)
+	snap := generateSnapshot(helper.diskdb, helper.triedb, 16, root, nil)
 	select {
 	case <-snap.genPending:
 		// Snapshot generation succeeded
@@ -50,4 +50,11 @@ var (
 	snapStorageWriteCounter = metrics.NewRegisteredCounter("state/snapshot/generation/duration/storage/write", nil)
 	// snapStorageCleanCounter measures time spent on deleting storages
 	snapStorageCleanCounter = metrics.NewRegisteredCounter("state/snapshot/generation/duration/storage/clean", nil)
+	// snapThrottleCounter measures time spent paused by the generation throttling
+	snapThrottleCounter = metrics.NewRegisteredCounter("state/snapshot/generation/duration/throttle", nil)
+
+	// snapGenerationDoneGauge is the fraction of the account hash space indexed
+	snapGenerationDoneGauge = metrics.NewRegisteredGaugeFloat64("state/snapshot/generation/done", nil)
+	// snapGenerationEtaGauge is the estimated number of seconds left to the generation
+	snapGenerationEtaGauge = metrics.NewRegisteredGauge("state/snapshot/generation/eta", nil)
 )
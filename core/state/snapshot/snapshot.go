@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -154,6 +155,8 @@ type Config struct {
 	Recovery   bool // Indicator that the snapshots is in the recovery mode
 	NoBuild    bool // Indicator that the snapshots generation is disallowed
 	AsyncBuild bool // The snapshot generation is allowed to be constructed asynchronously
+
+	GenerationRate uint64 // Maximum bytes of state indexed per second by the generation (0 = unlimited)
 }
 
 // Tree is an Ethereum state snapshot tree. It consists of one persistent base
@@ -172,6 +175,8 @@ type Tree struct {
 	layers map[common.Hash]snapshot // Collection of all known layers
 	lock   sync.RWMutex
 
+	genRate atomic.Uint64 // Maximum bytes of state indexed per second by the generation (0 = unlimited)
+
 	// Test hooks
 	onFlatten func() // Hook invoked when the bottom most diff layers are flattened
 }
@@ -200,8 +205,10 @@ func New(config Config, diskdb ethdb.KeyValueStore, triedb *trie.Database, root
 		triedb: triedb,
 		layers: make(map[common.Hash]snapshot),
 	}
+	snap.genRate.Store(config.GenerationRate)
+
 	// Attempt to load a previously persisted snapshot and rebuild one if failed
-	head, disabled, err := loadSnapshot(diskdb, triedb, root, config.CacheSize, config.Recovery, config.NoBuild)
+	head, disabled, err := loadSnapshot(diskdb, triedb, root, config.CacheSize, config.Recovery, config.NoBuild, &snap.genRate)
 	if disabled {
 		log.Warn("Snapshot maintenance disabled (syncing)")
 		return snap, nil
@@ -642,6 +649,7 @@ func diffToDisk(bottom *diffLayer) *diskLayer {
 		triedb:     base.triedb,
 		genMarker:  base.genMarker,
 		genPending: base.genPending,
+		genRate:    base.genRate,
 	}
 	// If snapshot generation hasn't finished yet, port over all the starts and
 	// continue where the previous round left off.
@@ -650,6 +658,7 @@ func diffToDisk(bottom *diffLayer) *diskLayer {
 	// to allow the tests to play with the marker without triggering this path.
 	if base.genMarker != nil && base.genAbort != nil {
 		res.genMarker = base.genMarker
+		res.genProgress = base.genProgress
 		res.genAbort = make(chan chan *generatorStats)
 		go res.generate(stats)
 	}
@@ -744,7 +753,7 @@ func (t *Tree) Rebuild(root common.Hash) {
 	// generator will run a wiper first if there's not one running right now.
 	log.Info("Rebuilding state snapshot")
 	t.layers = map[common.Hash]snapshot{
-		root: generateSnapshot(t.diskdb, t.triedb, t.config.CacheSize, root),
+		root: generateSnapshot(t.diskdb, t.triedb, t.config.CacheSize, root, &t.genRate),
 	}
 }
 
@@ -852,6 +861,45 @@ func (t *Tree) generating() (bool, error) {
 	return layer.genMarker != nil, nil
 }
 
+// Generating reports whether the snapshot is still being constructed, or is
+// missing its disk layer altogether.
+func (t *Tree) Generating() bool {
+	generating, err := t.generating()
+	return err != nil || generating
+}
+
+// GenerationProgress returns the progress of the snapshot generation, nil if
+// the snapshot is not being generated.
+func (t *Tree) GenerationProgress() *GenerationProgress {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	layer := t.disklayer()
+	if layer == nil {
+		return nil
+	}
+	layer.lock.RLock()
+	defer layer.lock.RUnlock()
+
+	if layer.genMarker == nil || layer.genProgress == nil {
+		return nil
+	}
+	progress := *layer.genProgress
+	return &progress
+}
+
+// GenerationRate returns the maximum bytes of state indexed per second by the
+// snapshot generation, 0 if unlimited.
+func (t *Tree) GenerationRate() uint64 {
+	return t.genRate.Load()
+}
+
+// SetGenerationRate changes the maximum bytes of state indexed per second by
+// the snapshot generation, 0 for unlimited. It applies to a running generation.
+func (t *Tree) SetGenerationRate(rate uint64) {
+	t.genRate.Store(rate)
+}
+
 // DiskRoot is a external helper function to return the disk layer root.
 func (t *Tree) DiskRoot() common.Hash {
 	t.lock.Lock()
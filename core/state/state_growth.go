@@ -0,0 +1,54 @@
+package state
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TrackStateGrowth enables the accounting of the accounts and storage slots
+// created, off by default as it walks the changes of every transaction.
+func (s *StateDB) TrackStateGrowth() {
+	s.trackGrowth = true
+}
+
+// StateGrowth returns the net number of accounts and storage slots created since
+// the tracking was enabled, including the changes of the transaction in progress.
+// The accounts left empty are considered deleted if deleteEmptyObjects is set, as
+// done on finalisation since EIP-158.
+func (s *StateDB) StateGrowth(deleteEmptyObjects bool) (accounts int64, slots int64) {
+	accounts, slots = s.txStateGrowth(deleteEmptyObjects)
+	return s.accountsCreated + accounts, s.slotsCreated + slots
+}
+
+// txStateGrowth returns the net number of accounts and storage slots created by
+// the changes not finalised yet.
+func (s *StateDB) txStateGrowth(deleteEmptyObjects bool) (accounts int64, slots int64) {
+	if !s.trackGrowth {
+		return 0, 0
+	}
+	for _, entry := range s.journal.entries {
+		if ch, ok := entry.(createObjectChange); ok {
+			if obj := s.stateObjects[*ch.account]; obj != nil && !obj.selfDestructed && !(deleteEmptyObjects && obj.empty()) {
+				accounts++
+			}
+		}
+	}
+	for addr := range s.journal.dirties {
+		obj, exist := s.stateObjects[addr]
+		if !exist || obj.selfDestructed || (deleteEmptyObjects && obj.empty()) {
+			continue
+		}
+		for key, value := range obj.dirtyStorage {
+			prev, ok := obj.pendingStorage[key]
+			if !ok {
+				prev = obj.originStorage[key]
+			}
+			switch {
+			case prev == (common.Hash{}) && value != (common.Hash{}):
+				slots++
+			case prev != (common.Hash{}) && value == (common.Hash{}):
+				slots--
+			}
+		}
+	}
+	return accounts, slots
+}
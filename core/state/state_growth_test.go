@@ -0,0 +1,58 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestStateGrowth(t *testing.T) {
+	state, _ := New(types.EmptyRootHash, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	state.TrackStateGrowth()
+
+	check := func(wantAccounts, wantSlots int64) {
+		t.Helper()
+		if accounts, slots := state.StateGrowth(true); accounts != wantAccounts || slots != wantSlots {
+			t.Fatalf("state growth mismatch: have %d accounts %d slots, want %d accounts %d slots", accounts, slots, wantAccounts, wantSlots)
+		}
+	}
+	a, b := common.Address{0x01}, common.Address{0x02}
+
+	// Account and slots created in the transaction in progress are accounted
+	state.AddBalance(a, big.NewInt(1))
+	state.SetState(a, common.Hash{0x01}, common.Hash{0x01})
+	state.SetState(a, common.Hash{0x02}, common.Hash{0x02})
+	check(1, 2)
+
+	// Finalising keeps the growth, and empty accounts are not accounted
+	state.Finalise(true)
+	state.AddBalance(b, new(big.Int))
+	check(1, 2)
+
+	// Overwriting a slot doesn't grow the state, clearing one shrinks it
+	state.SetState(a, common.Hash{0x01}, common.Hash{0x03})
+	state.SetState(a, common.Hash{0x02}, common.Hash{})
+	check(1, 1)
+
+	// Reverted changes are not accounted
+	snap := state.Snapshot()
+	state.AddBalance(b, big.NewInt(1))
+	state.SetState(a, common.Hash{0x04}, common.Hash{0x04})
+	check(2, 2)
+	state.RevertToSnapshot(snap)
+	check(1, 1)
+
+	state.Finalise(true)
+	check(1, 1)
+
+	// Nothing is accounted unless tracked
+	untracked, _ := New(types.EmptyRootHash, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	untracked.AddBalance(a, big.NewInt(1))
+	untracked.Finalise(true)
+	if accounts, slots := untracked.StateGrowth(true); accounts != 0 || slots != 0 {
+		t.Fatalf("untracked state growth accounted: %d accounts %d slots", accounts, slots)
+	}
+}
@@ -184,7 +184,14 @@ func (s *stateObject) GetCommittedState(key common.Hash) common.Hash {
 	if _, destructed := s.db.stateObjectsDestruct[s.address]; destructed {
 		return common.Hash{}
 	}
-	// If no live objects are available, attempt to use snapshots
+	s.db.countRead()
+
+	// If no live objects are available, attempt to use the warm cache
+	if value, ok := s.db.warm.slot(s.address, key); ok {
+		s.originStorage[key] = value
+		return value
+	}
+	// If not cached, attempt to use snapshots
 	var (
 		enc   []byte
 		err   error
@@ -222,6 +229,7 @@ func (s *stateObject) GetCommittedState(key common.Hash) common.Hash {
 		}
 		value.SetBytes(val)
 	}
+	s.db.warm.setSlot(s.address, key, value)
 	s.originStorage[key] = value
 	return value
 }
@@ -472,6 +480,7 @@ func (s *stateObject) Code() []byte {
 	if bytes.Equal(s.CodeHash(), types.EmptyCodeHash.Bytes()) {
 		return nil
 	}
+	s.db.countRead()
 	code, err := s.db.db.ContractCode(s.address, common.BytesToHash(s.CodeHash()))
 	if err != nil {
 		s.db.setError(fmt.Errorf("can't load code hash %x: %v", s.CodeHash(), err))
@@ -490,6 +499,7 @@ func (s *stateObject) CodeSize() int {
 	if bytes.Equal(s.CodeHash(), types.EmptyCodeHash.Bytes()) {
 		return 0
 	}
+	s.db.countRead()
 	size, err := s.db.db.ContractCodeSize(s.address, common.BytesToHash(s.CodeHash()))
 	if err != nil {
 		s.db.setError(fmt.Errorf("can't load code size %x: %v", s.CodeHash(), err))
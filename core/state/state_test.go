@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"math/big"
+	"sync/atomic"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -132,6 +133,45 @@ func TestIterativeDump(t *testing.T) {
 	}
 }
 
+// interruptingDump is a dump collector interrupting the dump after the first
+// account.
+type interruptingDump struct {
+	Dump
+	interrupt *atomic.Bool
+}
+
+func (d *interruptingDump) OnAccount(addr *common.Address, account DumpAccount) {
+	d.Dump.OnAccount(addr, account)
+	d.interrupt.Store(true)
+}
+
+func TestFilteredDump(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	tdb := NewDatabaseWithConfig(db, &trie.Config{Preimages: true})
+	sdb, _ := New(types.EmptyRootHash, tdb, nil)
+
+	for _, addr := range []string{"0xaa00000000000000000000000000000000000001", "0xaa00000000000000000000000000000000000002", "0xbb00000000000000000000000000000000000001"} {
+		sdb.SetBalance(common.HexToAddress(addr), big.NewInt(1))
+	}
+	root, _ := sdb.Commit(0, false)
+	sdb, _ = New(root, tdb, nil)
+
+	dump := sdb.RawDump(&DumpConfig{SkipCode: true, SkipStorage: true, AddressPrefix: []byte{0xaa}})
+	if len(dump.Accounts) != 2 {
+		t.Fatalf("filtered account count mismatch: have %d, want 2", len(dump.Accounts))
+	}
+	for addr := range dump.Accounts {
+		if addr[0] != 0xaa {
+			t.Errorf("account %x not matching the prefix", addr)
+		}
+	}
+	interrupted := &interruptingDump{Dump: Dump{Accounts: make(map[common.Address]DumpAccount)}, interrupt: new(atomic.Bool)}
+	sdb.DumpToCollector(interrupted, &DumpConfig{Interrupt: interrupted.interrupt})
+	if len(interrupted.Accounts) != 1 {
+		t.Fatalf("interrupted account count mismatch: have %d, want 1", len(interrupted.Accounts))
+	}
+}
+
 func TestNull(t *testing.T) {
 	s := newStateEnv()
 	address := common.HexToAddress("0x823140710bf13990e4500136726d8b55")
@@ -65,6 +65,7 @@ type StateDB struct {
 	hasher     crypto.KeccakState
 	snaps      *snapshot.Tree    // Nil if snapshot is not available
 	snap       snapshot.Snapshot // Nil if snapshot is not available
+	warm       *warmState        // Nil if no warm cache is used
 
 	// originalRoot is the pre-state root, before any changes were made.
 	// It will be updated when the Commit is called.
@@ -136,6 +137,20 @@ type StateDB struct {
 	AccountDeleted int
 	StorageDeleted int
 
+	// Bytes of trie nodes and contract code written by the last commit
+	CommitSize common.StorageSize
+
+	// Database read accounting, used to bound the work of RPC calls
+	dbReads     int
+	dbReadLimit int
+	onReadLimit func()
+
+	// Net number of accounts and storage slots created by the finalised
+	// transactions, used to rate limit the state growth
+	trackGrowth     bool
+	accountsCreated int64
+	slotsCreated    int64
+
 	// Testing hooks
 	onCommit func(states *triestate.Set) // Hook invoked when commit is performed
 }
@@ -206,6 +221,33 @@ func (s *StateDB) Error() error {
 	return s.dbErr
 }
 
+// SetReadLimit sets the number of accounts, storage slots and codes which may
+// be loaded from the database from now on before onExceed is invoked. The
+// callback is only invoked once; it is up to the caller to abort the execution.
+// A zero limit removes the limit.
+func (s *StateDB) SetReadLimit(limit int, onExceed func()) {
+	if limit <= 0 {
+		s.dbReadLimit, s.onReadLimit = 0, nil
+		return
+	}
+	s.dbReadLimit, s.onReadLimit = s.dbReads+limit, onExceed
+}
+
+// DatabaseReads returns the number of accounts, storage slots and codes loaded
+// from the database so far.
+func (s *StateDB) DatabaseReads() int {
+	return s.dbReads
+}
+
+// countRead accounts for a database read, invoking the read limit callback the
+// first time the limit is exceeded.
+func (s *StateDB) countRead() {
+	s.dbReads++
+	if s.dbReadLimit > 0 && s.dbReads == s.dbReadLimit+1 && s.onReadLimit != nil {
+		s.onReadLimit()
+	}
+}
+
 func (s *StateDB) AddLog(log *types.Log) {
 	s.journal.append(addLogChange{txhash: s.thash})
 
@@ -562,9 +604,15 @@ func (s *StateDB) getDeletedStateObject(addr common.Address) *stateObject {
 	if obj := s.stateObjects[addr]; obj != nil {
 		return obj
 	}
-	// If no live objects are available, attempt to use snapshots
-	var data *types.StateAccount
-	if s.snap != nil {
+	s.countRead()
+
+	// If no live objects are available, attempt to use the warm cache
+	data, warm := s.warm.account(addr)
+	if warm && data == nil {
+		return nil
+	}
+	// If not cached, attempt to use snapshots
+	if !warm && s.snap != nil {
 		start := time.Now()
 		acc, err := s.snap.Account(crypto.HashData(s.hasher, addr.Bytes()))
 		if metrics.EnabledExpensive {
@@ -572,6 +620,7 @@ func (s *StateDB) getDeletedStateObject(addr common.Address) *stateObject {
 		}
 		if err == nil {
 			if acc == nil {
+				s.warm.setAccount(addr, nil)
 				return nil
 			}
 			data = &types.StateAccount{
@@ -601,9 +650,13 @@ func (s *StateDB) getDeletedStateObject(addr common.Address) *stateObject {
 			return nil
 		}
 		if data == nil {
+			s.warm.setAccount(addr, nil)
 			return nil
 		}
 	}
+	if !warm {
+		s.warm.setAccount(addr, data)
+	}
 	// Insert into the live set
 	obj := newObject(s, addr, data)
 	s.setStateObject(obj)
@@ -685,6 +738,17 @@ func (s *StateDB) CreateAccount(addr common.Address) {
 	}
 }
 
+// DirtyAccounts returns the addresses of the accounts modified since the last
+// commit, sorted.
+func (s *StateDB) DirtyAccounts() []common.Address {
+	addrs := make([]common.Address, 0, len(s.stateObjectsDirty))
+	for addr := range s.stateObjectsDirty {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i].Cmp(addrs[j]) < 0 })
+	return addrs
+}
+
 // Copy creates a deep, independent copy of the state.
 // Snapshots of the copied state cannot be applied to the copy.
 func (s *StateDB) Copy() *StateDB {
@@ -693,6 +757,7 @@ func (s *StateDB) Copy() *StateDB {
 		db:                   s.db,
 		trie:                 s.db.CopyTrie(s.trie),
 		originalRoot:         s.originalRoot,
+		warm:                 s.warm,
 		accounts:             make(map[common.Hash][]byte),
 		storages:             make(map[common.Hash]map[common.Hash][]byte),
 		accountsOrigin:       make(map[common.Address][]byte),
@@ -704,6 +769,9 @@ func (s *StateDB) Copy() *StateDB {
 		refund:               s.refund,
 		logs:                 make(map[common.Hash][]*types.Log, len(s.logs)),
 		logSize:              s.logSize,
+		trackGrowth:          s.trackGrowth,
+		accountsCreated:      s.accountsCreated,
+		slotsCreated:         s.slotsCreated,
 		preimages:            make(map[common.Hash][]byte, len(s.preimages)),
 		journal:              newJournal(),
 		hasher:               crypto.NewKeccakState(),
@@ -822,6 +890,10 @@ func (s *StateDB) GetRefund() uint64 {
 // the journal as well as the refunds. Finalise, however, will not push any updates
 // into the tries just yet. Only IntermediateRoot or Commit will do that.
 func (s *StateDB) Finalise(deleteEmptyObjects bool) {
+	accounts, slots := s.txStateGrowth(deleteEmptyObjects)
+	s.accountsCreated += accounts
+	s.slotsCreated += slots
+
 	addressesToPrefetch := make([][]byte, 0, len(s.journal.dirties))
 	for addr := range s.journal.dirties {
 		obj, exist := s.stateObjects[addr]
@@ -1214,7 +1286,8 @@ func (s *StateDB) Commit(block uint64, deleteEmptyObjects bool) (common.Hash, er
 			storageTrieNodesDeleted += deleted
 		}
 	}
-	if codeWriter.ValueSize() > 0 {
+	codeSize := codeWriter.ValueSize()
+	if codeSize > 0 {
 		if err := codeWriter.Write(); err != nil {
 			log.Crit("Failed to commit dirty codes", "error", err)
 		}
@@ -1235,6 +1308,14 @@ func (s *StateDB) Commit(block uint64, deleteEmptyObjects bool) (common.Hash, er
 		}
 		accountTrieNodesUpdated, accountTrieNodesDeleted = set.Size()
 	}
+	s.CommitSize = common.StorageSize(codeSize)
+	for _, set := range nodes.Sets {
+		for path, n := range set.Nodes {
+			if !n.IsDeleted() {
+				s.CommitSize += common.StorageSize(len(path) + len(n.Blob))
+			}
+		}
+	}
 	if metrics.EnabledExpensive {
 		s.AccountCommits += time.Since(start)
 
@@ -1284,6 +1365,7 @@ func (s *StateDB) Commit(block uint64, deleteEmptyObjects bool) (common.Hash, er
 			return common.Hash{}, err
 		}
 		s.originalRoot = root
+		s.warm = nil // Cached entries are the ones of the previous root
 		if metrics.EnabledExpensive {
 			s.TrieDBCommits += time.Since(start)
 		}
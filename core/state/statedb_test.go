@@ -1191,3 +1191,43 @@ func TestDeleteStorage(t *testing.T) {
 		t.Fatalf("difference found:\nfast: %v\nslow: %v\n", fastRes, slowRes)
 	}
 }
+
+func TestReadLimit(t *testing.T) {
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	state, _ := New(types.EmptyRootHash, db, nil)
+	for i := byte(0); i < 4; i++ {
+		state.SetBalance(common.Address{i}, big.NewInt(1))
+	}
+	root, _ := state.Commit(0, false)
+
+	state, _ = New(root, db, nil)
+	var exceeded int
+	state.SetReadLimit(2, func() { exceeded++ })
+
+	state.GetBalance(common.Address{0})
+	state.GetBalance(common.Address{0}) // cached, not loaded again
+	state.GetBalance(common.Address{1})
+	if exceeded != 0 {
+		t.Fatalf("limit exceeded prematurely")
+	}
+	state.GetBalance(common.Address{2})
+	state.GetBalance(common.Address{3})
+	if exceeded != 1 {
+		t.Fatalf("limit callback invoked %d times, want 1", exceeded)
+	}
+	if reads := state.DatabaseReads(); reads != 4 {
+		t.Fatalf("read count mismatch: have %d, want 4", reads)
+	}
+	// Limits are relative to the reads done so far
+	state.SetReadLimit(1, func() { exceeded++ })
+	state.GetBalance(common.Address{4})
+	if exceeded != 1 {
+		t.Fatalf("limit exceeded prematurely")
+	}
+	state.SetReadLimit(0, nil)
+	state.GetBalance(common.Address{5})
+	state.GetBalance(common.Address{6})
+	if exceeded != 1 {
+		t.Fatalf("lifted limit enforced")
+	}
+}
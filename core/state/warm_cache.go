@@ -0,0 +1,190 @@
+package state
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	warmAccountHitMeter  = metrics.NewRegisteredMeter("state/warm/account/hit", nil)
+	warmAccountMissMeter = metrics.NewRegisteredMeter("state/warm/account/miss", nil)
+	warmStorageHitMeter  = metrics.NewRegisteredMeter("state/warm/storage/hit", nil)
+	warmStorageMissMeter = metrics.NewRegisteredMeter("state/warm/storage/miss", nil)
+)
+
+// WarmCache retains the accounts and storage slots read from the most recent
+// states by the block builder and the block import. A block built locally is
+// imported on top of the same parent state, finding the state touched while
+// building it without going through the snapshot or the tries again.
+//
+// The values are the ones of the state roots they were read from, so they stay
+// valid regardless of the blocks built or imported on top of them.
+type WarmCache struct {
+	states int                        // Number of state roots retained
+	roots  []common.Hash              // Retained state roots, oldest first
+	cached map[common.Hash]*warmState // Entries read from each retained state root
+	lock   sync.Mutex                 // Lock protecting the retained state roots
+
+	accountHits   atomic.Uint64
+	accountMisses atomic.Uint64
+	storageHits   atomic.Uint64
+	storageMisses atomic.Uint64
+}
+
+// WarmCacheStats are the lookups served by a warm cache since its creation.
+type WarmCacheStats struct {
+	AccountHits    uint64  `json:"accountHits"`
+	AccountMisses  uint64  `json:"accountMisses"`
+	AccountHitRate float64 `json:"accountHitRate"`
+	StorageHits    uint64  `json:"storageHits"`
+	StorageMisses  uint64  `json:"storageMisses"`
+	StorageHitRate float64 `json:"storageHitRate"`
+}
+
+// NewWarmCache creates a warm cache retaining the entries read from the given
+// number of most recent state roots.
+func NewWarmCache(states int) *WarmCache {
+	if states < 1 {
+		states = 1
+	}
+	return &WarmCache{
+		states: states,
+		cached: make(map[common.Hash]*warmState),
+	}
+}
+
+// Stats returns the lookups served by the cache since its creation.
+func (c *WarmCache) Stats() WarmCacheStats {
+	stats := WarmCacheStats{
+		AccountHits:   c.accountHits.Load(),
+		AccountMisses: c.accountMisses.Load(),
+		StorageHits:   c.storageHits.Load(),
+		StorageMisses: c.storageMisses.Load(),
+	}
+	if lookups := stats.AccountHits + stats.AccountMisses; lookups > 0 {
+		stats.AccountHitRate = float64(stats.AccountHits) / float64(lookups)
+	}
+	if lookups := stats.StorageHits + stats.StorageMisses; lookups > 0 {
+		stats.StorageHitRate = float64(stats.StorageHits) / float64(lookups)
+	}
+	return stats
+}
+
+// state returns the entries read from the given state root, dropping the ones
+// of the oldest retained root if it wasn't retained yet.
+func (c *WarmCache) state(root common.Hash) *warmState {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if state := c.cached[root]; state != nil {
+		return state
+	}
+	if len(c.roots) == c.states {
+		delete(c.cached, c.roots[0])
+		c.roots = append(c.roots[:0], c.roots[1:]...)
+	}
+	state := &warmState{
+		cache:    c,
+		accounts: make(map[common.Address]*types.StateAccount),
+		storage:  make(map[common.Address]map[common.Hash]common.Hash),
+	}
+	c.roots = append(c.roots, root)
+	c.cached[root] = state
+	return state
+}
+
+// SetWarmCache makes the state look up the accounts and storage slots read from
+// its original root in the given cache before the snapshot and the tries, and
+// cache the ones read from the latter. A nil cache disables the lookups.
+func (s *StateDB) SetWarmCache(cache *WarmCache) {
+	if cache == nil {
+		s.warm = nil
+		return
+	}
+	s.warm = cache.state(s.originalRoot)
+}
+
+// warmState holds the accounts and storage slots read from a state root. All
+// its methods are safe to call on a nil state, which caches nothing.
+type warmState struct {
+	cache    *WarmCache
+	accounts map[common.Address]*types.StateAccount         // Accounts read, nil if missing
+	storage  map[common.Address]map[common.Hash]common.Hash // Storage slots read
+	lock     sync.RWMutex
+}
+
+// account returns a copy of the cached account with the given address, which is
+// nil if the account is missing, and whether it was cached at all.
+func (s *warmState) account(addr common.Address) (*types.StateAccount, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.lock.RLock()
+	acc, ok := s.accounts[addr]
+	s.lock.RUnlock()
+
+	if !ok {
+		s.cache.accountMisses.Add(1)
+		warmAccountMissMeter.Mark(1)
+		return nil, false
+	}
+	s.cache.accountHits.Add(1)
+	warmAccountHitMeter.Mark(1)
+	if acc == nil {
+		return nil, true
+	}
+	return acc.Copy(), true
+}
+
+// setAccount caches the account with the given address, nil if missing.
+func (s *warmState) setAccount(addr common.Address, acc *types.StateAccount) {
+	if s == nil {
+		return
+	}
+	if acc != nil {
+		acc = acc.Copy()
+	}
+	s.lock.Lock()
+	s.accounts[addr] = acc
+	s.lock.Unlock()
+}
+
+// slot returns the cached value of the given storage slot of the given account,
+// and whether it was cached at all.
+func (s *warmState) slot(addr common.Address, key common.Hash) (common.Hash, bool) {
+	if s == nil {
+		return common.Hash{}, false
+	}
+	s.lock.RLock()
+	value, ok := s.storage[addr][key]
+	s.lock.RUnlock()
+
+	if !ok {
+		s.cache.storageMisses.Add(1)
+		warmStorageMissMeter.Mark(1)
+		return common.Hash{}, false
+	}
+	s.cache.storageHits.Add(1)
+	warmStorageHitMeter.Mark(1)
+	return value, true
+}
+
+// setSlot caches the value of the given storage slot of the given account.
+func (s *warmState) setSlot(addr common.Address, key common.Hash, value common.Hash) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	slots := s.storage[addr]
+	if slots == nil {
+		slots = make(map[common.Hash]common.Hash)
+		s.storage[addr] = slots
+	}
+	slots[key] = value
+}
@@ -0,0 +1,55 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestWarmCache(t *testing.T) {
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	state, _ := New(types.EmptyRootHash, db, nil)
+
+	a, b := common.Address{0x01}, common.Address{0x02}
+	state.AddBalance(a, big.NewInt(1))
+	state.SetState(a, common.Hash{0x01}, common.Hash{0x01})
+	root, _ := state.Commit(0, false)
+
+	// The builder caches the entries read from the parent state, but not its changes
+	cache := NewWarmCache(1)
+	builder, _ := New(root, db, nil)
+	builder.SetWarmCache(cache)
+	builder.GetBalance(a)
+	builder.GetState(a, common.Hash{0x01})
+	builder.Exist(b)
+	builder.AddBalance(a, big.NewInt(1))
+	builder.SetState(a, common.Hash{0x01}, common.Hash{0x02})
+	builder.Finalise(true)
+
+	// The import finds them, missing accounts included
+	importer, _ := New(root, db, nil)
+	importer.SetWarmCache(cache)
+	if balance := importer.GetBalance(a); balance.Cmp(big.NewInt(1)) != 0 {
+		t.Fatalf("balance mismatch: have %v, want 1", balance)
+	}
+	if value := importer.GetState(a, common.Hash{0x01}); value != (common.Hash{0x01}) {
+		t.Fatalf("slot mismatch: have %x, want %x", value, common.Hash{0x01})
+	}
+	if importer.Exist(b) {
+		t.Fatalf("missing account found")
+	}
+	if stats := cache.Stats(); stats.AccountHits != 2 || stats.AccountMisses != 2 || stats.StorageHits != 1 || stats.StorageMisses != 1 {
+		t.Fatalf("stats mismatch: %+v", stats)
+	}
+	// Opening another root evicts the entries of the oldest one
+	cache.state(common.Hash{0x01})
+	other, _ := New(root, db, nil)
+	other.SetWarmCache(cache)
+	other.GetBalance(a)
+	if stats := cache.Stats(); stats.AccountHits != 2 {
+		t.Fatalf("evicted account served: %+v", stats)
+	}
+}
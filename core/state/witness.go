@@ -0,0 +1,46 @@
+package state
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RecordWitness makes the state read the accounts and storage slots through the
+// tries rather than through the snapshot or the warm cache, so that the trie
+// nodes proving them are retained for the witness. It must be called before the
+// state is accessed.
+func (s *StateDB) RecordWitness() {
+	s.snap = nil
+	s.warm = nil
+}
+
+// Witness returns the trie nodes and the contract codes of the original state
+// needed to apply the state transitions done so far, keyed by their content:
+// the nodes resolved from the database by the account and storage tries, and
+// the codes of the pre-existing accounts accessed. The nodes resolved while
+// hashing the changes are only included once IntermediateRoot was called, and
+// Commit drops them all.
+func (s *StateDB) Witness() (nodes map[string]struct{}, codes map[string]struct{}, err error) {
+	nodes, codes = make(map[string]struct{}), make(map[string]struct{})
+	for node := range s.trie.Witness() {
+		nodes[node] = struct{}{}
+	}
+	for _, obj := range s.stateObjects {
+		if obj.trie != nil {
+			for node := range obj.trie.Witness() {
+				nodes[node] = struct{}{}
+			}
+		}
+		if obj.origin == nil || bytes.Equal(obj.origin.CodeHash, types.EmptyCodeHash.Bytes()) {
+			continue
+		}
+		code, err := s.db.ContractCode(obj.address, common.BytesToHash(obj.origin.CodeHash))
+		if err != nil {
+			return nil, nil, err
+		}
+		codes[string(code)] = struct{}{}
+	}
+	return nodes, codes, nil
+}
@@ -0,0 +1,62 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestWitness(t *testing.T) {
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	state, _ := New(types.EmptyRootHash, db, nil)
+	for i := byte(0); i < 64; i++ {
+		addr := common.Address{i}
+		state.AddBalance(addr, big.NewInt(int64(i)+1))
+		state.SetState(addr, common.Hash{i}, common.Hash{i})
+	}
+	state.SetCode(common.Address{0x01}, []byte{0x60, 0x00})
+	root, _ := state.Commit(0, false)
+
+	// Read and change a few accounts and slots while recording the witness
+	apply := func(state *StateDB) common.Hash {
+		state.GetCode(common.Address{0x01})
+		state.GetState(common.Address{0x02}, common.Hash{0x02})
+		state.SetState(common.Address{0x03}, common.Hash{0x03}, common.Hash{})
+		state.AddBalance(common.Address{0x04}, big.NewInt(1))
+		state.AddBalance(common.Address{0xff}, big.NewInt(1))
+		return state.IntermediateRoot(true)
+	}
+	recorded, _ := New(root, db, nil)
+	recorded.RecordWitness()
+	want := apply(recorded)
+
+	nodes, codes, err := recorded.Witness()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != 1 {
+		t.Fatalf("code count mismatch: have %d, want 1", len(codes))
+	}
+	// The same transitions apply on top of the witness alone
+	witness := rawdb.NewMemoryDatabase()
+	for node := range nodes {
+		rawdb.WriteLegacyTrieNode(witness, crypto.Keccak256Hash([]byte(node)), []byte(node))
+	}
+	for code := range codes {
+		rawdb.WriteCode(witness, crypto.Keccak256Hash([]byte(code)), []byte(code))
+	}
+	stateless, err := New(root, NewDatabase(witness), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := apply(stateless); have != want {
+		t.Fatalf("root mismatch: have %x, want %x", have, want)
+	}
+	if err := stateless.Error(); err != nil {
+		t.Fatalf("witness incomplete: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	stateGrowthAccountsHist = metrics.NewRegisteredHistogram("chain/stategrowth/accounts", nil, metrics.NewExpDecaySample(1028, 0.015))
+	stateGrowthSlotsHist    = metrics.NewRegisteredHistogram("chain/stategrowth/slots", nil, metrics.NewExpDecaySample(1028, 0.015))
+)
+
+// CheckStateGrowth fails a transaction other than a deposit if the net number of
+// accounts and storage slots created in the block, its changes included, exceeds
+// the limit. The same check is done on block building and import, so that the
+// blocks built are accepted.
+func CheckStateGrowth(config *params.ChainConfig, header *types.Header, tx *types.Transaction, statedb *state.StateDB) error {
+	limit := config.MaxStateGrowth(header.Time)
+	if limit == 0 || tx.IsDepositTx() {
+		return nil
+	}
+	accounts, slots := statedb.StateGrowth(config.IsEIP158(header.Number))
+	if growth := accounts + slots; growth > 0 && uint64(growth) > limit {
+		return fmt.Errorf("%w: %d accounts and %d slots created, limit %d", ErrStateGrowthExceeded, accounts, slots, limit)
+	}
+	return nil
+}
+
+// recordStateGrowth records the state created by a processed block.
+func recordStateGrowth(statedb *state.StateDB) {
+	if !metrics.Enabled {
+		return
+	}
+	accounts, slots := statedb.StateGrowth(true)
+	stateGrowthAccountsHist.Update(accounts)
+	stateGrowthSlotsHist.Update(slots)
+}
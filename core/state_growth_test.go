@@ -0,0 +1,51 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestCheckStateGrowth(t *testing.T) {
+	config := *params.OptimismTestConfig
+	optimism := *config.Optimism
+	optimism.StateGrowthLimit = 2
+	config.Optimism = &optimism
+	config.OasysStateGrowthTime = new(uint64)
+	*config.OasysStateGrowthTime = 10
+
+	var (
+		header  = &types.Header{Number: big.NewInt(1), Time: 10}
+		tx      = types.NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil)
+		deposit = types.NewTx(&types.DepositTx{})
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.TrackStateGrowth()
+
+	// Transactions within the limit pass, empty accounts not being accounted
+	statedb.AddBalance(common.Address{0x01}, big.NewInt(1))
+	statedb.SetState(common.Address{0x01}, common.Hash{0x01}, common.Hash{0x01})
+	statedb.AddBalance(common.Address{0x02}, new(big.Int))
+	if err := CheckStateGrowth(&config, header, tx, statedb); err != nil {
+		t.Fatalf("transaction within the limit rejected: %v", err)
+	}
+	statedb.Finalise(true)
+
+	// Transactions beyond are rejected, unless deposits or before the fork
+	statedb.AddBalance(common.Address{0x03}, big.NewInt(1))
+	if err := CheckStateGrowth(&config, header, tx, statedb); !errors.Is(err, ErrStateGrowthExceeded) {
+		t.Fatalf("expected %v, got %v", ErrStateGrowthExceeded, err)
+	}
+	if err := CheckStateGrowth(&config, header, deposit, statedb); err != nil {
+		t.Fatalf("deposit rejected: %v", err)
+	}
+	if err := CheckStateGrowth(&config, &types.Header{Number: big.NewInt(1), Time: 9}, tx, statedb); err != nil {
+		t.Fatalf("transaction before the fork rejected: %v", err)
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -72,22 +73,45 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		misc.ApplyDAOHardFork(statedb)
 	}
 	misc.EnsureCreate2Deployer(p.config, block.Time(), statedb)
+	var (
+		growth     = p.config.MaxStateGrowth(header.Time) != 0
+		dimensions = GasDimensionsEnabled(p.config, header.Time)
+		usage      GasUsage
+	)
+	if metrics.Enabled || growth {
+		statedb.TrackStateGrowth()
+	}
 	var (
 		context = NewEVMBlockContext(header, p.bc, nil, p.config, statedb)
 		vmenv   = vm.NewEVM(context, vm.TxContext{}, statedb, p.config, cfg)
 		signer  = types.MakeSigner(p.config, header.Number, header.Time)
+		msg     = new(Message) // Reused across the transactions
 	)
+	// The EVM never escapes the processing, recycle it for the next block
+	defer vmenv.Release()
+
 	if beaconRoot := block.BeaconRoot(); beaconRoot != nil {
 		ProcessBeaconBlockRoot(*beaconRoot, vmenv, statedb)
 	}
 	// Iterate over and process the individual transactions
 	for i, tx := range block.Transactions() {
-		msg, err := TransactionToMessage(tx, signer, header.BaseFee)
-		if err != nil {
+		if err := transactionToMessageInto(msg, tx, signer, header.BaseFee); err != nil {
 			return nil, nil, 0, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
 		}
 		statedb.SetTxContext(tx.Hash(), i)
-		receipt, err := applyTransaction(msg, p.config, gp, statedb, blockNumber, blockHash, tx, usedGas, vmenv)
+		var check func(*types.Receipt) error
+		if growth || dimensions {
+			check = func(receipt *types.Receipt) error {
+				if err := CheckStateGrowth(p.config, header, tx, statedb); err != nil {
+					return err
+				}
+				if dimensions {
+					return usage.Add(p.config, header, tx, receipt, statedb)
+				}
+				return nil
+			}
+		}
+		receipt, err := applyTransaction(msg, p.config, gp, statedb, blockNumber, blockHash, tx, usedGas, vmenv, check)
 		if err != nil {
 			return nil, nil, 0, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
 		}
@@ -105,7 +129,7 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 	return receipts, allLogs, *usedGas, nil
 }
 
-func applyTransaction(msg *Message, config *params.ChainConfig, gp *GasPool, statedb *state.StateDB, blockNumber *big.Int, blockHash common.Hash, tx *types.Transaction, usedGas *uint64, evm *vm.EVM) (*types.Receipt, error) {
+func applyTransaction(msg *Message, config *params.ChainConfig, gp *GasPool, statedb *state.StateDB, blockNumber *big.Int, blockHash common.Hash, tx *types.Transaction, usedGas *uint64, evm *vm.EVM, check func(*types.Receipt) error) (*types.Receipt, error) {
 	// Create a new context to be used in the EVM environment.
 	txContext := NewEVMTxContext(msg)
 	evm.Reset(txContext, statedb)
@@ -120,19 +144,12 @@ func applyTransaction(msg *Message, config *params.ChainConfig, gp *GasPool, sta
 	if err != nil {
 		return nil, err
 	}
-
-	// Update the state with pending changes.
-	var root []byte
-	if config.IsByzantium(blockNumber) {
-		statedb.Finalise(true)
-	} else {
-		root = statedb.IntermediateRoot(config.IsEIP158(blockNumber)).Bytes()
+	if evm.Cancelled() {
+		return nil, ErrTxExecutionAborted
 	}
-	*usedGas += result.UsedGas
 
-	// Create a new receipt for the transaction, storing the intermediate root and gas used
-	// by the tx.
-	receipt := &types.Receipt{Type: tx.Type(), PostState: root, CumulativeGasUsed: *usedGas}
+	// Create a new receipt for the transaction, storing the gas used by the tx.
+	receipt := &types.Receipt{Type: tx.Type()}
 	if result.Failed() {
 		receipt.Status = types.ReceiptStatusFailed
 	} else {
@@ -168,6 +185,23 @@ func applyTransaction(msg *Message, config *params.ChainConfig, gp *GasPool, sta
 	receipt.BlockHash = blockHash
 	receipt.BlockNumber = blockNumber
 	receipt.TransactionIndex = uint(statedb.TxIndex())
+
+	// Give the caller a chance to refuse the transaction before its changes
+	// can't be reverted anymore.
+	if check != nil {
+		if err := check(receipt); err != nil {
+			return nil, err
+		}
+	}
+	// Update the state with pending changes, storing the intermediate root in
+	// the receipt.
+	if config.IsByzantium(blockNumber) {
+		statedb.Finalise(true)
+	} else {
+		receipt.PostState = statedb.IntermediateRoot(config.IsEIP158(blockNumber)).Bytes()
+	}
+	*usedGas += result.UsedGas
+	receipt.CumulativeGasUsed = *usedGas
 	return receipt, err
 }
 
@@ -184,7 +218,16 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 	blockContext := NewEVMBlockContext(header, bc, author, config, statedb)
 	txContext := NewEVMTxContext(msg)
 	vmenv := vm.NewEVM(blockContext, txContext, statedb, config, cfg)
-	return applyTransaction(msg, config, gp, statedb, header.Number, header.Hash(), tx, usedGas, vmenv)
+	return applyTransaction(msg, config, gp, statedb, header.Number, header.Hash(), tx, usedGas, vmenv, nil)
+}
+
+// ApplyTransactionWithEVM attempts to apply a transaction to the given state
+// database using the given EVM, which the caller may cancel to abort the execution.
+// The optional check is run on the receipt before the state changes are finalised,
+// failing the transaction if it returns an error. The state changes need to be
+// reverted by the caller if an error is returned.
+func ApplyTransactionWithEVM(msg *Message, config *params.ChainConfig, gp *GasPool, statedb *state.StateDB, blockNumber *big.Int, blockHash common.Hash, tx *types.Transaction, usedGas *uint64, evm *vm.EVM, check func(*types.Receipt) error) (*types.Receipt, error) {
+	return applyTransaction(msg, config, gp, statedb, blockNumber, blockHash, tx, usedGas, evm, check)
 }
 
 // ProcessBeaconBlockRoot applies the EIP-4788 system call to the beacon block root
@@ -152,12 +152,29 @@ type Message struct {
 
 // TransactionToMessage converts a transaction into a Message.
 func TransactionToMessage(tx *types.Transaction, s types.Signer, baseFee *big.Int) (*Message, error) {
-	msg := &Message{
+	msg := new(Message)
+	err := transactionToMessageInto(msg, tx, s, baseFee)
+	return msg, err
+}
+
+// transactionToMessageInto converts a transaction into the given Message. All
+// its fields are overwritten, but its gas price integers are reused, so that
+// the transactions of a block can be converted in turn into the same Message.
+func transactionToMessageInto(msg *Message, tx *types.Transaction, s types.Signer, baseFee *big.Int) error {
+	reuse := func(b *big.Int) *big.Int {
+		if b == nil {
+			return new(big.Int)
+		}
+		return b
+	}
+	gasPrice, gasFeeCap, gasTipCap := reuse(msg.GasPrice), reuse(msg.GasFeeCap), reuse(msg.GasTipCap)
+
+	*msg = Message{
 		Nonce:         tx.Nonce(),
 		GasLimit:      tx.Gas(),
-		GasPrice:      new(big.Int).Set(tx.GasPrice()),
-		GasFeeCap:     new(big.Int).Set(tx.GasFeeCap()),
-		GasTipCap:     new(big.Int).Set(tx.GasTipCap()),
+		GasPrice:      gasPrice.Set(tx.GasPrice()),
+		GasFeeCap:     gasFeeCap.Set(tx.GasFeeCap()),
+		GasTipCap:     gasTipCap.Set(tx.GasTipCap()),
 		To:            tx.To(),
 		Value:         tx.Value(),
 		Data:          tx.Data(),
@@ -171,13 +188,16 @@ func TransactionToMessage(tx *types.Transaction, s types.Signer, baseFee *big.In
 		BlobHashes:        tx.BlobHashes(),
 		BlobGasFeeCap:     tx.BlobGasFeeCap(),
 	}
-	// If baseFee provided, set gasPrice to effectiveGasPrice.
+	// If baseFee provided, set gasPrice to effectiveGasPrice. The fee cap is
+	// copied rather than aliased, the integers being reused.
 	if baseFee != nil {
-		msg.GasPrice = cmath.BigMin(msg.GasPrice.Add(msg.GasTipCap, baseFee), msg.GasFeeCap)
+		if msg.GasPrice.Add(msg.GasTipCap, baseFee).Cmp(msg.GasFeeCap) > 0 {
+			msg.GasPrice.Set(msg.GasFeeCap)
+		}
 	}
 	var err error
 	msg.From, err = types.Sender(s, tx)
-	return msg, err
+	return err
 }
 
 // ApplyMessage computes the new state by applying the given message
@@ -250,6 +270,12 @@ func (st *StateTransition) buyGas() error {
 	if l1Cost != nil {
 		mgval = mgval.Add(mgval, l1Cost)
 	}
+	// The operator fee of the whole gas limit is charged upfront, the part of
+	// the unused gas is refunded after execution.
+	operatorCost := st.operatorCost(st.msg.GasLimit)
+	if operatorCost != nil {
+		mgval = mgval.Add(mgval, operatorCost)
+	}
 	balanceCheck := new(big.Int).Set(mgval)
 	if st.msg.GasFeeCap != nil {
 		balanceCheck.SetUint64(st.msg.GasLimit)
@@ -258,6 +284,9 @@ func (st *StateTransition) buyGas() error {
 		if l1Cost != nil {
 			balanceCheck.Add(balanceCheck, l1Cost)
 		}
+		if operatorCost != nil {
+			balanceCheck.Add(balanceCheck, operatorCost)
+		}
 	}
 	if st.evm.ChainConfig().IsCancun(st.evm.Context.BlockNumber, st.evm.Context.Time) {
 		if blobGas := st.blobGasUsed(); blobGas > 0 {
@@ -528,9 +557,12 @@ func (st *StateTransition) innerTransitionDb() (*ExecutionResult, error) {
 		// are 0. This avoids a negative effectiveTip being applied to
 		// the coinbase when simulating calls.
 	} else {
-		fee := new(big.Int).SetUint64(st.gasUsed())
-		fee.Mul(fee, effectiveTip)
-		st.state.AddBalance(st.evm.Context.Coinbase, fee)
+		// The priority fee is redirected or burnt if scheduled by the chain config
+		if recipient, ok := st.evm.ChainConfig().PriorityFeeRecipient(st.evm.Context.Coinbase, st.evm.Context.Time); ok {
+			fee := new(big.Int).SetUint64(st.gasUsed())
+			fee.Mul(fee, effectiveTip)
+			st.state.AddBalance(recipient, fee)
+		}
 	}
 
 	// Check that we are post bedrock to enable op-geth to be able to create pseudo pre-bedrock blocks (these are pre-bedrock, but don't follow l2 geth rules)
@@ -540,6 +572,12 @@ func (st *StateTransition) innerTransitionDb() (*ExecutionResult, error) {
 		if cost := st.evm.Context.L1CostFunc(st.evm.Context.BlockNumber.Uint64(), st.evm.Context.Time, st.msg.RollupDataGas, st.msg.IsDepositTx); cost != nil {
 			st.state.AddBalance(params.OptimismL1FeeRecipient, cost)
 		}
+		if rules.IsOasysOperatorFee {
+			st.refundOperatorCost()
+			if cost := st.operatorCost(st.gasUsed()); cost != nil {
+				st.state.AddBalance(params.OptimismOperatorFeeRecipient, cost)
+			}
+		}
 	}
 
 	return &ExecutionResult{
@@ -566,6 +604,26 @@ func (st *StateTransition) refundGas(refundQuotient uint64) {
 	st.gp.AddGas(st.gasRemaining)
 }
 
+// operatorCost returns the operator fee of the given amount of gas, nil if the
+// message pays none.
+func (st *StateTransition) operatorCost(gas uint64) *big.Int {
+	if st.evm.Context.OperatorCostFunc == nil || st.msg.IsDepositTx || st.msg.SkipAccountChecks {
+		return nil
+	}
+	return st.evm.Context.OperatorCostFunc(gas, st.evm.Context.Time)
+}
+
+// refundOperatorCost returns the operator fee of the unused gas, charged upfront
+// with the gas limit, to the sender.
+func (st *StateTransition) refundOperatorCost() {
+	charged := st.operatorCost(st.msg.GasLimit)
+	if charged == nil {
+		return
+	}
+	refund := new(big.Int).Sub(charged, st.operatorCost(st.gasUsed()))
+	st.state.AddBalance(st.msg.From, refund)
+}
+
 // gasUsed returns the amount of gas used up by the state transition.
 func (st *StateTransition) gasUsed() uint64 {
 	return st.initialGas - st.gasRemaining
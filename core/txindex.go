@@ -0,0 +1,115 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// errTxIndexerDisabled is returned when managing the transaction indexer while
+// it is not running.
+var errTxIndexerDisabled = errors.New("transaction indexer disabled")
+
+// txIndexRange is a range of blocks [from, to) whose transactions are to be
+// reindexed.
+type txIndexRange struct {
+	from, to uint64
+}
+
+// TxIndexProgress describes the state of the transaction index.
+type TxIndexProgress struct {
+	Limit     uint64  `json:"limit"`          // Number of recent blocks to index, 0 for the entire chain
+	Head      uint64  `json:"head"`           // Current head block
+	Tail      *uint64 `json:"tail,omitempty"` // Oldest indexed block, nil if the indexer never ran
+	Target    uint64  `json:"target"`         // Oldest block to index according to the limit
+	Remaining uint64  `json:"remaining"`      // Blocks left to index to reach the target
+	Indexing  bool    `json:"indexing"`       // Whether the indexer is processing
+}
+
+// TxIndexProgress returns the state of the transaction index.
+func (bc *BlockChain) TxIndexProgress() (*TxIndexProgress, error) {
+	if bc.txIndexKick == nil {
+		return nil, errTxIndexerDisabled
+	}
+	var (
+		limit = bc.txLookupLimit.Load()
+		head  = bc.CurrentBlock().Number.Uint64()
+	)
+	progress := &TxIndexProgress{
+		Limit:    limit,
+		Head:     head,
+		Tail:     rawdb.ReadTxIndexTail(bc.db),
+		Indexing: bc.txIndexing.Load(),
+	}
+	if limit != 0 && head+1 > limit {
+		progress.Target = head - limit + 1
+	}
+	switch {
+	case progress.Tail == nil:
+		progress.Remaining = head + 1 - progress.Target
+	case *progress.Tail > progress.Target:
+		progress.Remaining = *progress.Tail - progress.Target
+	}
+	return progress, nil
+}
+
+// UpdateTxLookupLimit changes the number of recent blocks whose transactions are
+// indexed, 0 indexing the entire chain. Missing indices are generated and stale
+// ones removed in the background.
+func (bc *BlockChain) UpdateTxLookupLimit(limit uint64) error {
+	if bc.txIndexKick == nil {
+		return errTxIndexerDisabled
+	}
+	old := bc.txLookupLimit.Swap(limit)
+	if old == limit {
+		return nil
+	}
+	// Persist the limit used by snap sync to determine the indexed range
+	if rawdb.ReadFastTxLookupLimit(bc.db) != nil {
+		rawdb.WriteFastTxLookupLimit(bc.db, limit)
+	}
+	log.Info("Updated transaction index limit", "old", old, "new", limit)
+
+	select {
+	case bc.txIndexKick <- struct{}{}:
+	default: // already notified
+	}
+	return nil
+}
+
+// ReindexTransactions regenerates the transaction indices of the blocks [from, to)
+// in the background, filling gaps in the index. Blocks below the index tail are
+// backfilled, extending the index down to from; they need to be within the
+// configured limit, otherwise they would be pruned again.
+func (bc *BlockChain) ReindexTransactions(from, to uint64) error {
+	if bc.txIndexKick == nil {
+		return errTxIndexerDisabled
+	}
+	head := bc.CurrentBlock().Number.Uint64()
+	if to > head+1 {
+		to = head + 1
+	}
+	if from >= to {
+		return fmt.Errorf("invalid range [%d, %d), head %d", from, to, head)
+	}
+	if limit := bc.txLookupLimit.Load(); limit != 0 && head+1 > limit && from < head-limit+1 {
+		return fmt.Errorf("block %d below index limit (oldest %d), raise the limit instead", from, head-limit+1)
+	}
+	tail := rawdb.ReadTxIndexTail(bc.db)
+	if tail == nil {
+		return errors.New("transaction index not initialized yet")
+	}
+	// A backfill must connect to the tail, otherwise the tail would hide a gap
+	if from < *tail && to < *tail {
+		to = *tail
+	}
+	select {
+	case bc.txIndexRanges <- txIndexRange{from: from, to: to}:
+		log.Info("Scheduled transaction reindexing", "from", from, "to", to)
+		return nil
+	case <-bc.quit:
+		return errChainStopped
+	}
+}
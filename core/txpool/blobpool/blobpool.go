@@ -1036,7 +1036,8 @@ func (p *BlobPool) SetGasTip(tip *big.Int) {
 
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
-func (p *BlobPool) validateTx(tx *types.Transaction) error {
+// The blob proofs are not verified again if already verified.
+func (p *BlobPool) validateTx(tx *types.Transaction, verified bool) error {
 	// Ensure the transaction adheres to basic pool filters (type, size, tip) and
 	// consensus rules
 	baseOpts := &txpool.ValidationOptions{
@@ -1044,6 +1045,8 @@ func (p *BlobPool) validateTx(tx *types.Transaction) error {
 		Accept:  1 << types.BlobTxType,
 		MaxSize: txMaxSize,
 		MinTip:  p.gasTip.ToBig(),
+
+		BlobsVerified: verified,
 	}
 	if err := txpool.ValidateTransaction(tx, p.head, p.signer, baseOpts); err != nil {
 		return err
@@ -1167,8 +1170,14 @@ func (p *BlobPool) Add(txs []*types.Transaction, local bool, sync bool) []error
 		adds = make([]*types.Transaction, 0, len(txs))
 		errs = make([]error, len(txs))
 	)
+	// Verify the blob proofs of the whole batch at once, before contending for
+	// the pool lock
+	verified := p.verifyBlobs(txs, errs)
 	for i, tx := range txs {
-		errs[i] = p.add(tx)
+		if errs[i] != nil {
+			continue
+		}
+		errs[i] = p.add(tx, verified[i])
 		if errs[i] == nil {
 			adds = append(adds, tx.WithoutBlobTxSidecar())
 		}
@@ -1180,9 +1189,46 @@ func (p *BlobPool) Add(txs []*types.Transaction, local bool, sync bool) []error
 	return errs
 }
 
-// Add inserts a new blob transaction into the pool if it passes validation (both
-// consensus validity and pool restictions).
-func (p *BlobPool) add(tx *types.Transaction) (err error) {
+// verifyBlobs verifies the blob proofs of the given transactions in a single
+// batch, setting the errors of the ones with invalid blobs and reporting the ones
+// with valid blobs. Transactions failing the cheaper sidecar checks are left to
+// the regular validation.
+func (p *BlobPool) verifyBlobs(txs []*types.Transaction, errs []error) []bool {
+	var (
+		verified = make([]bool, len(txs))
+		sidecars []*types.BlobTxSidecar
+		indices  []int
+	)
+	for i, tx := range txs {
+		sidecar := tx.BlobTxSidecar()
+		if tx.Type() != types.BlobTxType || sidecar == nil || uint64(len(sidecar.Blobs)) > params.MaxBlobGasPerBlock/params.BlobTxBlobGasPerBlob {
+			continue
+		}
+		if txpool.ValidateBlobSidecar(tx.BlobHashes(), sidecar) != nil {
+			continue
+		}
+		sidecars = append(sidecars, sidecar)
+		indices = append(indices, i)
+	}
+	if len(sidecars) == 0 {
+		return verified
+	}
+	start := time.Now()
+	for j, err := range txpool.VerifyBlobSidecars(sidecars) {
+		if err != nil {
+			errs[indices[j]] = err
+		} else {
+			verified[indices[j]] = true
+		}
+	}
+	verifytimeHist.Update(time.Since(start).Nanoseconds())
+	return verified
+}
+
+// add inserts a new blob transaction into the pool if it passes validation (both
+// consensus validity and pool restictions). The blob proofs are not verified
+// again if already verified.
+func (p *BlobPool) add(tx *types.Transaction, verified bool) (err error) {
 	// The blob pool blocks on adding a transaction. This is because blob txs are
 	// only even pulled form the network, so this method will act as the overload
 	// protection for fetches.
@@ -1196,7 +1242,7 @@ func (p *BlobPool) add(tx *types.Transaction) (err error) {
 	}(time.Now())
 
 	// Ensure the transaction is valid from all perspectives
-	if err := p.validateTx(tx); err != nil {
+	if err := p.validateTx(tx, verified); err != nil {
 		log.Trace("Transaction validation failed", "hash", tx.Hash(), "err", err)
 		return err
 	}
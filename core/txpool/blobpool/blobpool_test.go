@@ -1239,7 +1239,7 @@ func TestAdd(t *testing.T) {
 		// Add each transaction one by one, verifying the pool internals in between
 		for j, add := range tt.adds {
 			signed, _ := types.SignNewTx(keys[add.from], types.LatestSigner(testChainConfig), add.tx)
-			if err := pool.add(signed); !errors.Is(err, add.err) {
+			if err := pool.add(signed, false); !errors.Is(err, add.err) {
 				t.Errorf("test %d, tx %d: adding transaction error mismatch: have %v, want %v", i, j, err, add.err)
 			}
 			verifyPoolInternals(t, pool)
@@ -1249,3 +1249,36 @@ func TestAdd(t *testing.T) {
 		pool.Close()
 	}
 }
+
+// Tests that the blob proofs of a batch of added transactions are verified at
+// once, singling out the invalid ones and leaving the malformed ones to the
+// regular validation.
+func TestVerifyBlobs(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := types.LatestSigner(testChainConfig)
+
+	invalid := makeUnsignedTx(1, 1, 1, 1)
+	invalid.Sidecar.Proofs[0] = kzg4844.Proof{}
+	malformed := makeUnsignedTx(3, 1, 1, 1)
+	malformed.BlobHashes = []common.Hash{{0x01}}
+
+	txs := []*types.Transaction{
+		makeTx(0, 1, 1, 1, key),
+		types.MustSignNewTx(key, signer, invalid),
+		makeTx(2, 1, 1, 1, key),
+		types.MustSignNewTx(key, signer, malformed),
+	}
+	errs := make([]error, len(txs))
+	verified := new(BlobPool).verifyBlobs(txs, errs)
+
+	for i, want := range []bool{true, false, true, false} {
+		if verified[i] != want {
+			t.Errorf("transaction %d: verified mismatch: have %v, want %v", i, verified[i], want)
+		}
+	}
+	for i, want := range []bool{false, true, false, false} {
+		if (errs[i] != nil) != want {
+			t.Errorf("transaction %d: error mismatch: have %v, want error %v", i, errs[i], want)
+		}
+	}
+}
@@ -75,4 +75,8 @@ var (
 	pendtimeHist  = metrics.NewRegisteredHistogram("blobpool/pendtime", nil, metrics.NewExpDecaySample(1028, 0.015))
 	resetwaitHist = metrics.NewRegisteredHistogram("blobpool/resetwait", nil, metrics.NewExpDecaySample(1028, 0.015))
 	resettimeHist = metrics.NewRegisteredHistogram("blobpool/resettime", nil, metrics.NewExpDecaySample(1028, 0.015))
+
+	// verifytime tracks the time spent verifying the blob proofs of a batch of
+	// added transactions, outside of the pool lock.
+	verifytimeHist = metrics.NewRegisteredHistogram("blobpool/verifytime", nil, metrics.NewExpDecaySample(1028, 0.015))
 )
@@ -138,6 +138,10 @@ type Config struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	Paymaster        common.Address // Paymaster contract vouching for unaffordable transactions (zero = disabled)
+	PaymasterGas     uint64         // Gas budget of a paymaster call
+	PaymasterTimeout time.Duration  // Time budget of a paymaster call
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
@@ -154,6 +158,9 @@ var DefaultConfig = Config{
 	GlobalQueue:  1024,
 
 	Lifetime: 3 * time.Hour,
+
+	PaymasterGas:     100_000,
+	PaymasterTimeout: 50 * time.Millisecond,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -192,6 +199,16 @@ func (config *Config) sanitize() Config {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultConfig.Lifetime)
 		conf.Lifetime = DefaultConfig.Lifetime
 	}
+	if conf.Paymaster != (common.Address{}) {
+		if conf.PaymasterGas < 1 {
+			log.Warn("Sanitizing invalid txpool paymaster gas", "provided", conf.PaymasterGas, "updated", DefaultConfig.PaymasterGas)
+			conf.PaymasterGas = DefaultConfig.PaymasterGas
+		}
+		if conf.PaymasterTimeout <= 0 {
+			log.Warn("Sanitizing invalid txpool paymaster timeout", "provided", conf.PaymasterTimeout, "updated", DefaultConfig.PaymasterTimeout)
+			conf.PaymasterTimeout = DefaultConfig.PaymasterTimeout
+		}
+	}
 	return conf
 }
 
@@ -207,6 +224,7 @@ type LegacyPool struct {
 	chainconfig *params.ChainConfig
 	chain       BlockChain
 	gasTip      atomic.Pointer[big.Int]
+	governedTip atomic.Pointer[big.Int] // On-chain governed tip floor, nil if not configured
 	txFeed      event.Feed
 	signer      types.Signer
 	mu          sync.RWMutex
@@ -222,6 +240,7 @@ type LegacyPool struct {
 	pending map[common.Address]*list     // All currently processable transactions
 	queue   map[common.Address]*list     // Queued but non-processable transactions
 	beats   map[common.Address]time.Time // Last heartbeat from each known account
+	vouched map[common.Address]*big.Int  // Shortfalls vouched for by the paymaster
 	all     *lookup                      // All transactions to allow lookups
 	priced  *pricedList                  // All transactions sorted by price
 
@@ -257,6 +276,7 @@ func New(config Config, chain BlockChain) *LegacyPool {
 		pending:         make(map[common.Address]*list),
 		queue:           make(map[common.Address]*list),
 		beats:           make(map[common.Address]time.Time),
+		vouched:         make(map[common.Address]*big.Int),
 		all:             newLookup(),
 		reqResetCh:      make(chan *txpoolResetRequest),
 		reqPromoteCh:    make(chan *accountSet),
@@ -313,6 +333,7 @@ func (pool *LegacyPool) Init(gasTip *big.Int, head *types.Header, reserve txpool
 	pool.currentHead.Store(head)
 	pool.currentState = statedb
 	pool.pendingNonces = newNoncer(statedb)
+	pool.governedTip.Store(types.GovernedMinTip(pool.chainconfig, statedb, head.Time))
 
 	// Start the reorg loop early, so it can handle requests generated during
 	// journal loading.
@@ -420,6 +441,18 @@ func (pool *LegacyPool) Close() error {
 	return nil
 }
 
+// Rejournal regenerates the local transaction journal from the current pool
+// contents, e.g. to persist an up to date journal before shutting down.
+func (pool *LegacyPool) Rejournal() error {
+	if pool.journal == nil {
+		return nil
+	}
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return pool.journal.rotate(pool.toJournal())
+}
+
 // Reset implements txpool.SubPool, allowing the legacy pool's internal state to be
 // kept in sync with the main transaction pool's internal state.
 func (pool *LegacyPool) Reset(oldHead, newHead *types.Header) {
@@ -448,16 +481,32 @@ func (pool *LegacyPool) SetGasTip(tip *big.Int) {
 
 	// If the min miner fee increased, remove transactions below the new threshold
 	if tip.Cmp(old) > 0 {
-		// pool.priced is sorted by GasFeeCap, so we have to iterate through pool.all instead
-		drop := pool.all.RemotesBelowTip(tip)
-		for _, tx := range drop {
-			pool.removeTx(tx.Hash(), false, true)
-		}
-		pool.priced.Removed(len(drop))
+		pool.dropRemotesBelowTip(pool.minTip())
 	}
 	log.Info("Legacy pool tip threshold updated", "tip", tip)
 }
 
+// minTip returns the minimum gas tip currently required for remote transactions,
+// which is the larger of the locally configured and the on-chain governed tip.
+func (pool *LegacyPool) minTip() *big.Int {
+	tip := pool.gasTip.Load()
+	if governed := pool.governedTip.Load(); governed != nil && governed.Cmp(tip) > 0 {
+		return governed
+	}
+	return tip
+}
+
+// dropRemotesBelowTip removes all remote transactions whose gas tip cap is below
+// the given threshold. The caller must hold the pool lock.
+func (pool *LegacyPool) dropRemotesBelowTip(tip *big.Int) {
+	// pool.priced is sorted by GasFeeCap, so we have to iterate through pool.all instead
+	drop := pool.all.RemotesBelowTip(tip)
+	for _, tx := range drop {
+		pool.removeTx(tx.Hash(), false, true)
+	}
+	pool.priced.Removed(len(drop))
+}
+
 // Nonce returns the next nonce of an account, with all transactions executable
 // by the pool already applied on top.
 func (pool *LegacyPool) Nonce(addr common.Address) uint64 {
@@ -542,7 +591,7 @@ func (pool *LegacyPool) Pending(enforceTips bool) map[common.Address][]*txpool.L
 		// If the miner requests tip enforcement, cap the lists now
 		if enforceTips && !pool.locals.contains(addr) {
 			for i, tx := range txs {
-				if tx.EffectiveGasTipIntCmp(pool.gasTip.Load(), pool.priced.urgent.baseFee) < 0 {
+				if tx.EffectiveGasTipIntCmp(pool.minTip(), pool.priced.urgent.baseFee) < 0 {
 					txs = txs[:i]
 					break
 				}
@@ -621,7 +670,7 @@ func (pool *LegacyPool) validateTxBasics(tx *types.Transaction, local bool) erro
 			1<<types.AccessListTxType |
 			1<<types.DynamicFeeTxType,
 		MaxSize: txMaxSize,
-		MinTip:  pool.gasTip.Load(),
+		MinTip:  pool.minTip(),
 	}
 	if local {
 		opts.MinTip = new(big.Int)
@@ -660,7 +709,7 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
 				if tx := list.txs.Get(nonce); tx != nil {
 					cost := tx.Cost()
 					if pool.l1CostFn != nil {
-						if l1Cost := pool.l1CostFn(tx.RollupDataGas()); l1Cost != nil { // add rollup cost
+						if l1Cost := pool.l1CostFn(tx); l1Cost != nil { // add rollup cost
 							cost = cost.Add(cost, l1Cost)
 						}
 					}
@@ -672,6 +721,9 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
 		L1CostFn:  pool.l1CostFn,
 		IsFeeZero: pool.chainconfig.IsFeeZero(pool.currentHead.Load().Time),
 	}
+	if pool.config.Paymaster != (common.Address{}) {
+		opts.Sponsor = pool.vouch
+	}
 	if err := txpool.ValidateTransactionWithState(tx, pool.signer, opts); err != nil {
 		return err
 	}
@@ -1098,6 +1150,20 @@ func (pool *LegacyPool) Has(hash common.Hash) bool {
 	return pool.all.Get(hash) != nil
 }
 
+// Remove evicts a transaction from the pool, moving the subsequent transactions
+// of its sender back to the future queue. It returns whether the transaction was
+// contained in the pool.
+func (pool *LegacyPool) Remove(hash common.Hash) bool {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if pool.all.Get(hash) == nil {
+		return false
+	}
+	pool.removeTx(hash, true, true)
+	return true
+}
+
 // removeTx removes a single transaction from the queue, moving all subsequent
 // transactions back to the future queue.
 //
@@ -1445,10 +1511,31 @@ func (pool *LegacyPool) reset(oldHead, newHead *types.Header) {
 	pool.currentHead.Store(newHead)
 	pool.currentState = statedb
 	pool.pendingNonces = newNoncer(statedb)
+	pool.dropVouches()
 
-	costFn := types.NewL1CostFunc(pool.chainconfig, statedb)
-	pool.l1CostFn = func(dataGas types.RollupGasData) *big.Int {
-		return costFn(newHead.Number.Uint64(), newHead.Time, dataGas, false)
+	var (
+		costFn         = types.NewL1CostFunc(pool.chainconfig, statedb)
+		operatorCostFn = types.NewOperatorCostFunc(pool.chainconfig, statedb)
+	)
+	pool.l1CostFn = func(tx *types.Transaction) *big.Int {
+		cost := costFn(newHead.Number.Uint64(), newHead.Time, tx.RollupDataGas(), false)
+		if operatorCost := operatorCostFn(tx.Gas(), newHead.Time); operatorCost != nil {
+			if cost == nil {
+				return operatorCost
+			}
+			cost = new(big.Int).Add(cost, operatorCost)
+		}
+		return cost
+	}
+
+	// Pick up any change of the on-chain governed tip floor at the block boundary
+	oldTip := pool.minTip()
+	pool.governedTip.Store(types.GovernedMinTip(pool.chainconfig, statedb, newHead.Time))
+	if newTip := pool.minTip(); newTip.Cmp(oldTip) != 0 {
+		if newTip.Cmp(oldTip) > 0 {
+			pool.dropRemotesBelowTip(newTip)
+		}
+		log.Info("Legacy pool governed tip threshold updated", "tip", newTip)
 	}
 
 	// Inject any transactions discarded due to reorgs
@@ -1478,11 +1565,11 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 			pool.all.Remove(hash)
 		}
 		log.Trace("Removed old queued transactions", "count", len(forwards))
-		balance := pool.currentState.GetBalance(addr)
+		balance := pool.vouchedBalance(addr)
 		if !list.Empty() && pool.l1CostFn != nil {
 			// Reduce the cost-cap by L1 rollup cost of the first tx if necessary. Other txs will get filtered out afterwards.
 			el := list.txs.FirstElement()
-			if l1Cost := pool.l1CostFn(el.RollupDataGas()); l1Cost != nil {
+			if l1Cost := pool.l1CostFn(el); l1Cost != nil {
 				balance = new(big.Int).Sub(balance, l1Cost) // negative big int is fine
 			}
 		}
@@ -1687,11 +1774,11 @@ func (pool *LegacyPool) demoteUnexecutables() {
 			pool.all.Remove(hash)
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
-		balance := pool.currentState.GetBalance(addr)
+		balance := pool.vouchedBalance(addr)
 		if !list.Empty() && pool.l1CostFn != nil {
 			// Reduce the cost-cap by L1 rollup cost of the first tx if necessary. Other txs will get filtered out afterwards.
 			el := list.txs.FirstElement()
-			if l1Cost := pool.l1CostFn(el.RollupDataGas()); l1Cost != nil {
+			if l1Cost := pool.l1CostFn(el); l1Cost != nil {
 				balance = new(big.Int).Sub(balance, l1Cost) // negative big int is fine
 			}
 		}
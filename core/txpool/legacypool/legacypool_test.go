@@ -357,6 +357,61 @@ func TestInvalidTransactions(t *testing.T) {
 	}
 }
 
+// Tests that a configured paymaster can vouch for transactions their senders
+// can't afford, and that the vouched shortfall keeps them executable.
+func TestPaymasterVouching(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(params.TestChainConfig, 10000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.Paymaster = common.HexToAddress("0xfee")
+	pool := New(config, blockchain)
+	pool.Init(new(big.Int).SetUint64(testTxPoolConfig.PriceLimit), blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	// Without paymaster code, the call returns nothing and the transaction is refused
+	if err, want := pool.addRemote(transaction(0, 100000, key)), core.ErrInsufficientFunds; !errors.Is(err, want) {
+		t.Fatalf("want %v have %v", want, err)
+	}
+	// A paymaster returning false refuses the transaction
+	pool.mu.Lock()
+	pool.currentState.SetCode(config.Paymaster, common.FromHex("0x600060005260206000f3"))
+	pool.mu.Unlock()
+	if err, want := pool.addRemote(transaction(0, 100000, key)), core.ErrInsufficientFunds; !errors.Is(err, want) {
+		t.Fatalf("want %v have %v", want, err)
+	}
+	// A paymaster returning true vouches for the transaction
+	pool.mu.Lock()
+	pool.currentState.SetCode(config.Paymaster, common.FromHex("0x600160005260206000f3"))
+	pool.mu.Unlock()
+	if err := pool.addRemoteSync(transaction(0, 100000, key)); err != nil {
+		t.Fatalf("vouched transaction rejected: %v", err)
+	}
+	if pending, queued := pool.Stats(); pending != 1 || queued != 0 {
+		t.Fatalf("pool stats mismatch: have %d pending %d queued, want 1 pending", pending, queued)
+	}
+	if pool.vouched[from] == nil {
+		t.Fatalf("vouched shortfall not recorded")
+	}
+	// The credit is dropped once the sender has no transactions left
+	<-pool.requestReset(nil, nil)
+	if pending, _ := pool.Stats(); pending != 1 {
+		t.Fatalf("vouched transaction dropped on reset")
+	}
+	pool.mu.Lock()
+	pool.removeTx(pool.pending[from].Flatten()[0].Hash(), true, true)
+	pool.dropVouches()
+	pool.mu.Unlock()
+	if pool.vouched[from] != nil {
+		t.Fatalf("vouched shortfall not dropped")
+	}
+}
+
 func TestQueue(t *testing.T) {
 	t.Parallel()
 
@@ -691,6 +746,40 @@ func TestDropping(t *testing.T) {
 	}
 }
 
+// Tests that removing a pending transaction evicts it and demotes the subsequent
+// transactions of its sender.
+func TestRemove(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, account, big.NewInt(1000000))
+
+	txs := []*types.Transaction{transaction(0, 100000, key), transaction(1, 100000, key), transaction(2, 100000, key)}
+	for i, err := range pool.addRemotesSync(txs) {
+		if err != nil {
+			t.Fatalf("tx %d: failed to add transaction: %v", i, err)
+		}
+	}
+	if !pool.Remove(txs[1].Hash()) {
+		t.Fatal("pooled transaction not removed")
+	}
+	if pool.Remove(txs[1].Hash()) {
+		t.Fatal("removed transaction removed again")
+	}
+	if pool.Has(txs[1].Hash()) {
+		t.Error("removed transaction still pooled")
+	}
+	if pending, queued := pool.stats(); pending != 1 || queued != 1 {
+		t.Errorf("pool stats mismatch: have %d pending, %d queued, want 1 pending, 1 queued", pending, queued)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Tests that if a transaction is dropped from the current pending pool (e.g. out
 // of fund), all consecutive (still valid, but not executable) transactions are
 // postponed back into the future queue to prevent broadcasting them.
@@ -926,7 +1015,7 @@ func testQueueGlobalLimiting(t *testing.T, nolocals bool) {
 	keys := make([]*ecdsa.PrivateKey, 5)
 	for i := 0; i < len(keys); i++ {
 		keys[i], _ = crypto.GenerateKey()
-		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(10000000))
 	}
 	local := keys[len(keys)-1]
 
@@ -1204,7 +1293,7 @@ func TestPendingGlobalLimiting(t *testing.T) {
 	keys := make([]*ecdsa.PrivateKey, 5)
 	for i := 0; i < len(keys); i++ {
 		keys[i], _ = crypto.GenerateKey()
-		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(10000000))
 	}
 	// Generate and queue a batch of transactions
 	nonces := make(map[common.Address]uint64)
@@ -1341,7 +1430,7 @@ func TestPendingMinimumAllowance(t *testing.T) {
 	keys := make([]*ecdsa.PrivateKey, 5)
 	for i := 0; i < len(keys); i++ {
 		keys[i], _ = crypto.GenerateKey()
-		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(10000000))
 	}
 	// Generate and queue a batch of transactions
 	nonces := make(map[common.Address]uint64)
@@ -1367,6 +1456,64 @@ func TestPendingMinimumAllowance(t *testing.T) {
 	}
 }
 
+// Tests that the on-chain governed tip floor rejects underpriced remote
+// transactions, and that it is re-read from the state on every pool reset.
+func TestGovernedTipFloor(t *testing.T) {
+	t.Parallel()
+
+	var (
+		contract = common.HexToAddress("0x4200000000000000000000000000000000000100")
+		config   = *params.TestChainConfig
+	)
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 2, EIP1559Denominator: 8, MinTipContract: &contract}
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetState(contract, types.MinTipSlot, common.BigToHash(big.NewInt(10)))
+	blockchain := newTestBlockChain(&config, 1000000, statedb, new(event.Feed))
+
+	pool := New(testTxPoolConfig, blockchain)
+	pool.Init(new(big.Int).SetUint64(testTxPoolConfig.PriceLimit), blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	setFloor := func(tip int64) {
+		pool.mu.Lock()
+		pool.currentState.SetState(contract, types.MinTipSlot, common.BigToHash(big.NewInt(tip)))
+		pool.mu.Unlock()
+		<-pool.requestReset(nil, nil)
+	}
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(10000000))
+	}
+	// Remote transactions below the governed floor are rejected, the ones at it accepted
+	if err := pool.addRemoteSync(pricedTransaction(0, 100000, big.NewInt(5), keys[0])); !errors.Is(err, txpool.ErrUnderpriced) {
+		t.Fatalf("adding underpriced remote transaction error mismatch: have %v, want %v", err, txpool.ErrUnderpriced)
+	}
+	if err := pool.addRemoteSync(pricedTransaction(0, 100000, big.NewInt(10), keys[0])); err != nil {
+		t.Fatalf("failed to add remote transaction at the floor: %v", err)
+	}
+	// Lowering the floor on chain is picked up on reset
+	setFloor(2)
+	if err := pool.addRemoteSync(pricedTransaction(0, 100000, big.NewInt(5), keys[1])); err != nil {
+		t.Fatalf("failed to add remote transaction above the lowered floor: %v", err)
+	}
+	if pending, _ := pool.Stats(); pending != 2 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 2)
+	}
+	// Raising it drops the remote transactions below the new floor
+	setFloor(8)
+	if pending, _ := pool.Stats(); pending != 1 {
+		t.Fatalf("pending transactions mismatched after raising the floor: have %d, want %d", pending, 1)
+	}
+	if err := pool.addRemoteSync(pricedTransaction(0, 100000, big.NewInt(5), keys[2])); !errors.Is(err, txpool.ErrUnderpriced) {
+		t.Fatalf("adding underpriced remote transaction error mismatch: have %v, want %v", err, txpool.ErrUnderpriced)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Tests that setting the transaction pool gas price to a higher value correctly
 // discards everything cheaper than that and moves any gapped transactions back
 // from the pending pool to the queue.
@@ -1392,7 +1539,7 @@ func TestRepricing(t *testing.T) {
 	keys := make([]*ecdsa.PrivateKey, 4)
 	for i := 0; i < len(keys); i++ {
 		keys[i], _ = crypto.GenerateKey()
-		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(10000000))
 	}
 	// Generate and queue a batch of transactions, both pending and queued
 	txs := types.Transactions{}
@@ -1513,7 +1660,7 @@ func TestRepricingDynamicFee(t *testing.T) {
 	keys := make([]*ecdsa.PrivateKey, 4)
 	for i := 0; i < len(keys); i++ {
 		keys[i], _ = crypto.GenerateKey()
-		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(10000000))
 	}
 	// Generate and queue a batch of transactions, both pending and queued
 	txs := types.Transactions{}
@@ -1719,7 +1866,7 @@ func TestUnderpricing(t *testing.T) {
 	keys := make([]*ecdsa.PrivateKey, 5)
 	for i := 0; i < len(keys); i++ {
 		keys[i], _ = crypto.GenerateKey()
-		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(10000000))
 	}
 	// Generate and queue a batch of transactions, both pending and queued
 	txs := types.Transactions{}
@@ -1834,7 +1981,7 @@ func TestStableUnderpricing(t *testing.T) {
 	keys := make([]*ecdsa.PrivateKey, 2)
 	for i := 0; i < len(keys); i++ {
 		keys[i], _ = crypto.GenerateKey()
-		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(10000000))
 	}
 	// Fill up the entire queue with the same transaction price points
 	txs := types.Transactions{}
@@ -1898,7 +2045,7 @@ func TestUnderpricingDynamicFee(t *testing.T) {
 	keys := make([]*ecdsa.PrivateKey, 4)
 	for i := 0; i < len(keys); i++ {
 		keys[i], _ = crypto.GenerateKey()
-		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(10000000))
 	}
 
 	// Generate and queue a batch of transactions, both pending and queued
@@ -2446,7 +2593,7 @@ func TestStatusCheck(t *testing.T) {
 	keys := make([]*ecdsa.PrivateKey, 3)
 	for i := 0; i < len(keys); i++ {
 		keys[i], _ = crypto.GenerateKey()
-		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(10000000))
 	}
 	// Generate and queue a batch of transactions, both pending and queued
 	txs := types.Transactions{}
@@ -0,0 +1,106 @@
+package legacypool
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// paymasterVouchSelector is the selector of the paymaster method consulted about
+// an unaffordable transaction: vouch(address sender, bytes32 txHash, uint256 shortfall)
+// returns (bool).
+var paymasterVouchSelector = crypto.Keccak256([]byte("vouch(address,bytes32,uint256)"))[:4]
+
+var (
+	paymasterVouchedMeter = metrics.NewRegisteredMeter("txpool/paymaster/vouched", nil)
+	paymasterRefusedMeter = metrics.NewRegisteredMeter("txpool/paymaster/refused", nil)
+	paymasterFailedMeter  = metrics.NewRegisteredMeter("txpool/paymaster/failed", nil) // Reverted, out of gas or timed out
+)
+
+// vouch consults the configured paymaster about a transaction its sender can't
+// afford, via a static call against the pool state bounded by the configured gas
+// and time budgets. If the paymaster vouches for the shortfall, the sender is
+// credited with it until it has no more transactions in the pool.
+//
+// The method assumes the pool lock is held.
+func (pool *LegacyPool) vouch(tx *types.Transaction, from common.Address, shortfall *big.Int) bool {
+	if pool.config.Paymaster == (common.Address{}) {
+		return false
+	}
+	var (
+		head     = pool.currentHead.Load()
+		blockCtx = vm.BlockContext{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			GetHash:     func(uint64) common.Hash { return common.Hash{} },
+			Coinbase:    head.Coinbase,
+			GasLimit:    head.GasLimit,
+			BlockNumber: new(big.Int).Set(head.Number),
+			Time:        head.Time,
+			Difficulty:  new(big.Int),
+			BaseFee:     head.BaseFee,
+		}
+	)
+	if head.Difficulty != nil {
+		blockCtx.Difficulty.Set(head.Difficulty)
+	}
+	if head.Difficulty == nil || head.Difficulty.Sign() == 0 {
+		random := head.MixDigest
+		blockCtx.Random = &random
+	}
+	evm := vm.NewEVM(blockCtx, vm.TxContext{Origin: from, GasPrice: new(big.Int)}, pool.currentState.Copy(), pool.chainconfig, vm.Config{NoBaseFee: true})
+	timer := time.AfterFunc(pool.config.PaymasterTimeout, evm.Cancel)
+	defer timer.Stop()
+
+	input := make([]byte, 0, len(paymasterVouchSelector)+3*32)
+	input = append(input, paymasterVouchSelector...)
+	input = append(input, common.LeftPadBytes(from.Bytes(), 32)...)
+	input = append(input, tx.Hash().Bytes()...)
+	input = append(input, common.LeftPadBytes(shortfall.Bytes(), 32)...)
+
+	ret, _, err := evm.StaticCall(vm.AccountRef(from), pool.config.Paymaster, input, pool.config.PaymasterGas)
+	if err != nil || evm.Cancelled() {
+		paymasterFailedMeter.Mark(1)
+		log.Trace("Paymaster call failed", "hash", tx.Hash(), "from", from, "err", err, "cancelled", evm.Cancelled())
+		return false
+	}
+	if len(ret) != 32 || new(big.Int).SetBytes(ret).Cmp(common.Big1) != 0 {
+		paymasterRefusedMeter.Mark(1)
+		log.Trace("Paymaster refused transaction", "hash", tx.Hash(), "from", from, "shortfall", shortfall)
+		return false
+	}
+	paymasterVouchedMeter.Mark(1)
+	log.Trace("Paymaster vouched for transaction", "hash", tx.Hash(), "from", from, "shortfall", shortfall)
+
+	if credit := pool.vouched[from]; credit == nil || credit.Cmp(shortfall) < 0 {
+		pool.vouched[from] = new(big.Int).Set(shortfall)
+	}
+	return true
+}
+
+// vouchedBalance returns the balance of the account for the funding checks,
+// including the shortfall vouched for by the paymaster.
+func (pool *LegacyPool) vouchedBalance(addr common.Address) *big.Int {
+	balance := pool.currentState.GetBalance(addr)
+	if credit := pool.vouched[addr]; credit != nil {
+		balance = new(big.Int).Add(balance, credit)
+	}
+	return balance
+}
+
+// dropVouches forgets the paymaster credits of the accounts without transactions
+// left in the pool.
+func (pool *LegacyPool) dropVouches() {
+	for addr := range pool.vouched {
+		if pool.pending[addr] == nil && pool.queue[addr] == nil {
+			delete(pool.vouched, addr)
+		}
+	}
+}
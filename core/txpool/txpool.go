@@ -17,6 +17,7 @@
 package txpool
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
@@ -26,11 +27,14 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/tracing"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 )
 
-type L1CostFunc func(dataGas types.RollupGasData) *big.Int
+// L1CostFunc returns the rollup costs of a transaction on top of its gas and
+// value: the L1 data fee and, from the operator fee fork, the operator fee of its gas limit.
+type L1CostFunc func(tx *types.Transaction) *big.Int
 
 // TxStatus is the current status of a transaction as seen by the pool.
 type TxStatus uint
@@ -79,6 +83,12 @@ type TxPool struct {
 // New creates a new transaction pool to gather, sort and filter inbound
 // transactions from the network.
 func New(gasTip *big.Int, chain BlockChain, subpools []SubPool) (*TxPool, error) {
+	// Subscribe to chain head events to trigger subpool resets. It is done before
+	// starting the event loop, not to miss the heads imported in the meantime.
+	var (
+		newHeadCh  = make(chan core.ChainHeadEvent)
+		newHeadSub = chain.SubscribeChainHeadEvent(newHeadCh)
+	)
 	// Retrieve the current head so that all subpools and this main coordinator
 	// pool will have the same starting state, even if the chain moves forward
 	// during initialization.
@@ -94,10 +104,11 @@ func New(gasTip *big.Int, chain BlockChain, subpools []SubPool) (*TxPool, error)
 			for j := i - 1; j >= 0; j-- {
 				subpools[j].Close()
 			}
+			newHeadSub.Unsubscribe()
 			return nil, err
 		}
 	}
-	go pool.loop(head, chain)
+	go pool.loop(head, newHeadCh, newHeadSub)
 	return pool, nil
 }
 
@@ -175,12 +186,7 @@ func (p *TxPool) Close() error {
 // loop is the transaction pool's main event loop, waiting for and reacting to
 // outside blockchain events as well as for various reporting and transaction
 // eviction events.
-func (p *TxPool) loop(head *types.Header, chain BlockChain) {
-	// Subscribe to chain head events to trigger subpool resets
-	var (
-		newHeadCh  = make(chan core.ChainHeadEvent)
-		newHeadSub = chain.SubscribeChainHeadEvent(newHeadCh)
-	)
+func (p *TxPool) loop(head *types.Header, newHeadCh <-chan core.ChainHeadEvent, newHeadSub event.Subscription) {
 	defer newHeadSub.Unsubscribe()
 
 	// Track the previous and current head to feed to an idle reset
@@ -261,10 +267,36 @@ func (p *TxPool) Get(hash common.Hash) *types.Transaction {
 	return nil
 }
 
+// remover is implemented by the subpools supporting the eviction of single
+// transactions.
+type remover interface {
+	Remove(hash common.Hash) bool
+}
+
+// Remove evicts a transaction from the subpool containing it, if the subpool
+// supports it. It returns whether the transaction was removed.
+func (p *TxPool) Remove(hash common.Hash) bool {
+	for _, subpool := range p.subpools {
+		if r, ok := subpool.(remover); ok && r.Remove(hash) {
+			return true
+		}
+	}
+	return false
+}
+
 // Add enqueues a batch of transactions into the pool if they are valid. Due
 // to the large transaction churn, add may postpone fully integrating the tx
 // to a later point to batch multiple ones together.
 func (p *TxPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
+	return p.AddContext(context.Background(), txs, local, sync)
+}
+
+// AddContext is like Add, recording the admission span as a child of the span
+// in ctx, if any.
+func (p *TxPool) AddContext(ctx context.Context, txs []*types.Transaction, local bool, sync bool) []error {
+	_, span := tracing.Start(ctx, "txpool.add", "txs", len(txs), "local", local)
+	defer span.End()
+
 	// Split the input transactions between the subpools. It shouldn't really
 	// happen that we receive merged batches, but better graceful than strange
 	// errors.
@@ -304,6 +336,15 @@ func (p *TxPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
 		errs[i] = errsets[split][0]
 		errsets[split] = errsets[split][1:]
 	}
+	if span != nil {
+		var rejected int
+		for _, err := range errs {
+			if err != nil {
+				rejected++
+			}
+		}
+		span.SetAttributes("rejected", rejected)
+	}
 	return errs
 }
 
@@ -406,6 +447,19 @@ func (p *TxPool) Locals() []common.Address {
 	return flat
 }
 
+// Rejournal regenerates the disk journals of the subpools keeping one, so that
+// they reflect the current pool contents.
+func (p *TxPool) Rejournal() error {
+	for _, subpool := range p.subpools {
+		if journaled, ok := subpool.(interface{ Rejournal() error }); ok {
+			if err := journaled.Rejournal(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // Status returns the known status (unknown/pending/queued) of a transaction
 // identified by its hash.
 func (p *TxPool) Status(hash common.Hash) TxStatus {
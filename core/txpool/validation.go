@@ -54,6 +54,8 @@ type ValidationOptions struct {
 	Accept  uint8    // Bitmap of transaction types that should be accepted for the calling pool
 	MaxSize uint64   // Maximum size of a transaction that the caller can meaningfully handle
 	MinTip  *big.Int // Minimum gas tip needed to allow a transaction into the caller pool
+
+	BlobsVerified bool // Whether the blob proofs were verified beforehand, e.g. in batch by VerifyBlobSidecars
 }
 
 // ValidateTransaction is a helper method to check whether a transaction is valid
@@ -145,14 +147,20 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 		if len(hashes) > params.MaxBlobGasPerBlock/params.BlobTxBlobGasPerBlob {
 			return fmt.Errorf("too many blobs in transaction: have %d, permitted %d", len(hashes), params.MaxBlobGasPerBlock/params.BlobTxBlobGasPerBlob)
 		}
-		if err := validateBlobSidecar(hashes, sidecar); err != nil {
+		if err := validateBlobSidecar(hashes, sidecar, !opts.BlobsVerified); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func validateBlobSidecar(hashes []common.Hash, sidecar *types.BlobTxSidecar) error {
+// ValidateBlobSidecar checks that the items of the sidecar match the given blob
+// hashes of a transaction, without verifying the blob proofs.
+func ValidateBlobSidecar(hashes []common.Hash, sidecar *types.BlobTxSidecar) error {
+	return validateBlobSidecar(hashes, sidecar, false)
+}
+
+func validateBlobSidecar(hashes []common.Hash, sidecar *types.BlobTxSidecar, verifyProofs bool) error {
 	if len(sidecar.Blobs) != len(hashes) {
 		return fmt.Errorf("invalid number of %d blobs compared to %d blob hashes", len(sidecar.Blobs), len(hashes))
 	}
@@ -180,6 +188,9 @@ func validateBlobSidecar(hashes []common.Hash, sidecar *types.BlobTxSidecar) err
 	}
 	// Blob commitments match with the hashes in the transaction, verify the
 	// blobs themselves via KZG
+	if !verifyProofs {
+		return nil
+	}
 	for i := range sidecar.Blobs {
 		if err := kzg4844.VerifyBlobProof(sidecar.Blobs[i], sidecar.Commitments[i], sidecar.Proofs[i]); err != nil {
 			return fmt.Errorf("invalid blob %d: %v", i, err)
@@ -188,6 +199,51 @@ func validateBlobSidecar(hashes []common.Hash, sidecar *types.BlobTxSidecar) err
 	return nil
 }
 
+// VerifyBlobSidecars verifies the blob proofs of the given sidecars in a single
+// batch, returning the error of each invalid sidecar. A failing batch is split
+// in halves until the invalid sidecars are singled out, so that a burst of valid
+// blobs costs a fraction of their individual verifications. The sidecars must
+// have been checked against their transaction by ValidateBlobSidecar.
+func VerifyBlobSidecars(sidecars []*types.BlobTxSidecar) []error {
+	errs := make([]error, len(sidecars))
+	if len(sidecars) > 0 {
+		verifyBlobSidecars(sidecars, errs)
+	}
+	return errs
+}
+
+func verifyBlobSidecars(sidecars []*types.BlobTxSidecar, errs []error) {
+	var (
+		blobs       []kzg4844.Blob
+		commitments []kzg4844.Commitment
+		proofs      []kzg4844.Proof
+	)
+	for _, sidecar := range sidecars {
+		blobs = append(blobs, sidecar.Blobs...)
+		commitments = append(commitments, sidecar.Commitments...)
+		proofs = append(proofs, sidecar.Proofs...)
+	}
+	err := kzg4844.VerifyBlobProofBatch(blobs, commitments, proofs)
+	if err == nil {
+		return
+	}
+	if len(sidecars) > 1 {
+		half := len(sidecars) / 2
+		verifyBlobSidecars(sidecars[:half], errs[:half])
+		verifyBlobSidecars(sidecars[half:], errs[half:])
+		return
+	}
+	// Single out the invalid blob of the sidecar
+	sidecar := sidecars[0]
+	for i := range sidecar.Blobs {
+		if err := kzg4844.VerifyBlobProof(sidecar.Blobs[i], sidecar.Commitments[i], sidecar.Proofs[i]); err != nil {
+			errs[0] = fmt.Errorf("invalid blob %d: %v", i, err)
+			return
+		}
+	}
+	errs[0] = fmt.Errorf("invalid blobs: %v", err)
+}
+
 // ValidationOptionsWithState define certain differences between stateful transaction
 // validation across the different pools without having to duplicate those checks.
 type ValidationOptionsWithState struct {
@@ -217,6 +273,11 @@ type ValidationOptionsWithState struct {
 
 	// Flag to indicate that the L2 fee is zero
 	IsFeeZero bool
+
+	// Sponsor is an optional extension, consulted about a transaction whose
+	// sender can't afford it. It reports whether a third party vouches for the
+	// given shortfall, the transaction being accepted in that case.
+	Sponsor func(tx *types.Transaction, from common.Address, shortfall *big.Int) bool
 }
 
 // ValidateTransactionWithState is a helper method to check whether a transaction
@@ -252,27 +313,38 @@ func ValidateTransactionWithState(tx *types.Transaction, signer types.Signer, op
 		cost    = tx.Cost()
 	)
 	if opts.L1CostFn != nil {
-		if l1Cost := opts.L1CostFn(tx.RollupDataGas()); l1Cost != nil { // add rollup cost
+		if l1Cost := opts.L1CostFn(tx); l1Cost != nil { // add rollup cost
 			cost = cost.Add(cost, l1Cost)
 		}
 	}
-	if balance.Cmp(cost) < 0 {
-		return fmt.Errorf("%w: balance %v, tx cost %v, overshot %v", core.ErrInsufficientFunds, balance, cost, new(big.Int).Sub(cost, balance))
-	}
 	// Ensure the transactor has enough funds to cover for replacements or nonce
 	// expansions without overdrafts
-	spent := opts.ExistingExpenditure(from)
-	if prev := opts.ExistingCost(from, tx.Nonce()); prev != nil {
-		bump := new(big.Int).Sub(cost, prev)
-		need := new(big.Int).Add(spent, bump)
-		if balance.Cmp(need) < 0 {
-			return fmt.Errorf("%w: balance %v, queued cost %v, tx bumped %v, overshot %v", core.ErrInsufficientFunds, balance, spent, bump, new(big.Int).Sub(need, balance))
-		}
+	var (
+		spent = opts.ExistingExpenditure(from)
+		prev  = opts.ExistingCost(from, tx.Nonce())
+		need  *big.Int
+	)
+	if prev != nil {
+		need = new(big.Int).Add(spent, new(big.Int).Sub(cost, prev))
 	} else {
-		need := new(big.Int).Add(spent, cost)
+		need = new(big.Int).Add(spent, cost)
+	}
+	// Let the sponsor vouch for the transaction if the transactor falls short,
+	// the overdraft being covered by a third party
+	sponsored := balance.Cmp(need) < 0 && opts.Sponsor != nil && opts.Sponsor(tx, from, new(big.Int).Sub(need, balance))
+	if !sponsored {
+		if balance.Cmp(cost) < 0 {
+			return fmt.Errorf("%w: balance %v, tx cost %v, overshot %v", core.ErrInsufficientFunds, balance, cost, new(big.Int).Sub(cost, balance))
+		}
 		if balance.Cmp(need) < 0 {
+			if prev != nil {
+				bump := new(big.Int).Sub(cost, prev)
+				return fmt.Errorf("%w: balance %v, queued cost %v, tx bumped %v, overshot %v", core.ErrInsufficientFunds, balance, spent, bump, new(big.Int).Sub(need, balance))
+			}
 			return fmt.Errorf("%w: balance %v, queued cost %v, tx cost %v, overshot %v", core.ErrInsufficientFunds, balance, spent, cost, new(big.Int).Sub(need, balance))
 		}
+	}
+	if prev == nil {
 		// Transaction takes a new nonce value out of the pool. Ensure it doesn't
 		// overflow the number of permitted transactions from a single account
 		// (i.e. max cancellable via out-of-bound transaction).
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// EnableTxSenderIndex starts indexing the canonical transactions by sender and
+// nonce, over the same block window as the transaction index. The blocks written
+// from then on are indexed on insertion, the older ones in the background.
+func (bc *BlockChain) EnableTxSenderIndex() {
+	if !bc.txSenderIndex.CompareAndSwap(false, true) {
+		return
+	}
+	bc.wg.Add(1)
+	go bc.maintainTxSenderIndex()
+}
+
+// TxSenderIndexEnabled reports whether transactions are indexed by sender.
+func (bc *BlockChain) TxSenderIndexEnabled() bool {
+	return bc.txSenderIndex.Load()
+}
+
+// GetTxHashBySenderAndNonce returns the hash of the canonical transaction sent by
+// the account with the given nonce, nil if not indexed.
+func (bc *BlockChain) GetTxHashBySenderAndNonce(sender common.Address, nonce uint64) *common.Hash {
+	hash := rawdb.ReadTxSenderEntry(bc.db, sender, nonce)
+	if hash == nil {
+		return nil
+	}
+	// Entries of the transactions dropped by reorgs are left behind, only
+	// return the ones still indexed as canonical
+	if bc.GetTransactionLookup(*hash) == nil {
+		return nil
+	}
+	return hash
+}
+
+// writeTxSenderEntries indexes the transactions of the block by sender if the
+// index is enabled.
+func (bc *BlockChain) writeTxSenderEntries(db ethdb.KeyValueWriter, block *types.Block) {
+	if bc.txSenderIndex.Load() {
+		rawdb.WriteTxSenderEntries(db, types.MakeSigner(bc.chainConfig, block.Number(), block.Time()), block.Transactions())
+	}
+}
+
+// maintainTxSenderIndex moves the tail of the sender index along with the tail
+// of the transaction index as the chain progresses.
+func (bc *BlockChain) maintainTxSenderIndex() {
+	defer bc.wg.Done()
+
+	var (
+		done   chan struct{} // Non-nil if background indexing routine is active
+		headCh = make(chan HeadUpdateEvent, 1)
+	)
+	sub := bc.SubscribeHeadUpdateEvent(headCh)
+	if sub == nil {
+		return
+	}
+	defer sub.Unsubscribe()
+
+	// The blocks written from now on are indexed on insertion, start indexing
+	// the history below the current head.
+	if rawdb.ReadTxSenderIndexTail(bc.db) == nil {
+		rawdb.WriteTxSenderIndexTail(bc.db, bc.CurrentBlock().Number.Uint64()+1)
+	}
+	log.Info("Initialized transaction sender indexer")
+
+	done = make(chan struct{})
+	go bc.syncTxSenderIndex(done)
+	for {
+		select {
+		case head := <-headCh:
+			if head.Label == HeadUnsafe && done == nil {
+				done = make(chan struct{})
+				go bc.syncTxSenderIndex(done)
+			}
+		case <-done:
+			done = nil
+		case <-bc.quit:
+			if done != nil {
+				<-done
+			}
+			return
+		}
+	}
+}
+
+// syncTxSenderIndex indexes or unindexes the blocks between the tails of the
+// sender and transaction indexes, moving the former to the latter.
+func (bc *BlockChain) syncTxSenderIndex(done chan struct{}) {
+	defer close(done)
+
+	txTail, tail := rawdb.ReadTxIndexTail(bc.db), rawdb.ReadTxSenderIndexTail(bc.db)
+	if txTail == nil || tail == nil || *txTail == *tail {
+		return
+	}
+	var (
+		from, to = *tail, *txTail
+		index    = *txTail < *tail
+	)
+	if index {
+		from, to = *txTail, *tail
+		if head := bc.CurrentBlock().Number.Uint64(); to > head+1 {
+			to = head + 1
+		}
+	}
+	var (
+		batch   = bc.db.NewBatch()
+		start   = time.Now()
+		logged  = start
+		blocks  int
+		newTail = *tail
+	)
+loop:
+	for i := from; i < to; i++ {
+		// Index from the top down and unindex from the bottom up, so that the
+		// tail is contiguous if interrupted
+		number := i
+		if index {
+			number = to - 1 - (i - from)
+		}
+		select {
+		case <-bc.quit:
+			break loop
+		default:
+		}
+		if block := bc.GetBlockByNumber(number); block != nil {
+			signer := types.MakeSigner(bc.chainConfig, block.Number(), block.Time())
+			if index {
+				rawdb.WriteTxSenderEntries(batch, signer, block.Transactions())
+			} else {
+				rawdb.DeleteTxSenderEntries(batch, signer, block.Transactions())
+			}
+		}
+		newTail = number
+		if !index {
+			newTail = number + 1
+		}
+		blocks++
+
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			rawdb.WriteTxSenderIndexTail(batch, newTail)
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed writing batch to db", "error", err)
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Indexing transaction senders", "blocks", blocks, "tail", newTail, "target", *txTail, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	rawdb.WriteTxSenderIndexTail(batch, newTail)
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed writing batch to db", "error", err)
+	}
+	log.Debug("Synced transaction sender index", "blocks", blocks, "tail", newTail, "elapsed", common.PrettyDuration(time.Since(start)))
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestTxSenderIndex(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   GenesisAlloc{address: {Balance: big.NewInt(100000000000000000)}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 64, func(i int, block *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(address), common.Address{0x00}, big.NewInt(1000), params.TxGas, block.header.BaseFee, nil), signer, key)
+		if err != nil {
+			panic(err)
+		}
+		block.AddTx(tx)
+	})
+	limit := uint64(0)
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, &limit)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks[:48]); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	// waitTail waits for the given index tail to reach zero
+	waitTail := func(read func() *uint64) {
+		for deadline := time.Now().Add(5 * time.Second); ; {
+			if tail := read(); tail != nil && *tail == 0 {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("index not completed in time")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitTail(func() *uint64 { return rawdb.ReadTxIndexTail(chain.db) })
+
+	if hash := chain.GetTxHashBySenderAndNonce(address, 0); hash != nil {
+		t.Fatalf("transaction indexed by sender before enabling the index: %x", *hash)
+	}
+	// Index the existing history, then the blocks inserted afterwards
+	chain.EnableTxSenderIndex()
+	waitTail(func() *uint64 { return rawdb.ReadTxSenderIndexTail(chain.db) })
+
+	if _, err := chain.InsertChain(blocks[48:]); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	for i, block := range blocks {
+		tx := block.Transactions()[0]
+		hash := chain.GetTxHashBySenderAndNonce(address, uint64(i))
+		if hash == nil || *hash != tx.Hash() {
+			t.Fatalf("nonce %d: transaction hash mismatch: have %v, want %x", i, hash, tx.Hash())
+		}
+	}
+	if hash := chain.GetTxHashBySenderAndNonce(address, uint64(len(blocks))); hash != nil {
+		t.Fatalf("unexpected transaction for unused nonce: %x", *hash)
+	}
+}
@@ -47,6 +47,7 @@ type L1CostFunc func(blockNum uint64, blockTime uint64, dataGas RollupGasData, i
 
 var (
 	L1BaseFeeSlot = common.BigToHash(big.NewInt(1))
+	L1HashSlot    = common.BigToHash(big.NewInt(2))
 	OverheadSlot  = common.BigToHash(big.NewInt(5))
 	ScalarSlot    = common.BigToHash(big.NewInt(6))
 )
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// L1InfoArgsLen is the length of the calldata of the L1 attributes deposit:
+// the function selector followed by the 8 arguments of setL1BlockValues.
+const L1InfoArgsLen = 4 + 32*8
+
+// L1BlockInfo is the L1 origin information carried by the L1 attributes deposit
+// at the start of every L2 block, as passed to L1Block.setL1BlockValues.
+type L1BlockInfo struct {
+	Number         uint64
+	Time           uint64
+	BaseFee        *big.Int
+	BlockHash      common.Hash
+	SequenceNumber uint64
+	BatcherHash    common.Hash
+	L1FeeOverhead  *big.Int
+	L1FeeScalar    *big.Int
+}
+
+// ParseL1BlockInfo decodes the calldata of an L1 attributes deposit.
+func ParseL1BlockInfo(data []byte) (*L1BlockInfo, error) {
+	if len(data) < L1InfoArgsLen {
+		return nil, fmt.Errorf("L1 info tx only has %d bytes, cannot read gas price parameters", len(data))
+	}
+	arg := func(i int) []byte { return data[4+32*i : 4+32*(i+1)] }
+	return &L1BlockInfo{
+		Number:         binary.BigEndian.Uint64(arg(0)[24:]),
+		Time:           binary.BigEndian.Uint64(arg(1)[24:]),
+		BaseFee:        new(big.Int).SetBytes(arg(2)),
+		BlockHash:      common.BytesToHash(arg(3)),
+		SequenceNumber: binary.BigEndian.Uint64(arg(4)[24:]),
+		BatcherHash:    common.BytesToHash(arg(5)),
+		L1FeeOverhead:  new(big.Int).SetBytes(arg(6)),
+		L1FeeScalar:    new(big.Int).SetBytes(arg(7)),
+	}, nil
+}
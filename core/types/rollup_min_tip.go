@@ -0,0 +1,25 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// MinTipSlot is the storage slot of the configured min tip contract holding the
+// governed minimum priority fee, in wei.
+var MinTipSlot = common.BigToHash(big.NewInt(0))
+
+// GovernedMinTip reads the on-chain governed minimum priority fee from the given
+// state. It returns nil if no min tip contract is configured or if transaction
+// fees are disabled at the given time.
+func GovernedMinTip(config *params.ChainConfig, statedb StateGetter, time uint64) *big.Int {
+	if config.Optimism == nil || config.Optimism.MinTipContract == nil {
+		return nil
+	}
+	if config.IsFeeZero(time) {
+		return nil
+	}
+	return statedb.GetState(*config.Optimism.MinTipContract, MinTipSlot).Big()
+}
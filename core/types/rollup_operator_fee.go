@@ -0,0 +1,60 @@
+package types
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// OperatorFeeParamsSlot is the storage slot of the L1Block predeploy packing the
+// operator fee scalar and constant set by the SystemConfig, from the operator fee
+// fork.
+var OperatorFeeParamsSlot = common.BigToHash(big.NewInt(8))
+
+// OperatorCostFunc is used in the state transition to determine the operator fee
+// of a rollup message given the gas it uses. Returns nil if there is no cost.
+type OperatorCostFunc func(gasUsed uint64, blockTime uint64) *big.Int
+
+// NewOperatorCostFunc returns a function used for calculating the operator fee
+// from the parameters held by the L1Block predeploy in the given state.
+func NewOperatorCostFunc(config *params.ChainConfig, statedb StateGetter) OperatorCostFunc {
+	return func(gasUsed uint64, blockTime uint64) *big.Int {
+		if !config.IsOasysOperatorFee(blockTime) {
+			return nil
+		}
+		if config.IsFeeZero(blockTime) {
+			// `nil` means that there is no cost, so it explicitly returns zero.
+			return big.NewInt(0)
+		}
+		scalar, constant := ExtractOperatorFeeParams(statedb.GetState(L1BlockAddr, OperatorFeeParamsSlot))
+		return OperatorCost(gasUsed, scalar, constant)
+	}
+}
+
+// ExtractOperatorFeeParams unpacks the operator fee scalar and constant from the
+// L1Block storage slot holding them.
+func ExtractOperatorFeeParams(slot common.Hash) (scalar uint64, constant uint64) {
+	scalar = uint64(binary.BigEndian.Uint32(slot[20:24]))
+	constant = binary.BigEndian.Uint64(slot[24:32])
+	return scalar, constant
+}
+
+// OperatorFeeParamsHash packs the operator fee scalar and constant as stored in
+// the L1Block storage slot holding them.
+func OperatorFeeParamsHash(scalar uint64, constant uint64) common.Hash {
+	var slot common.Hash
+	binary.BigEndian.PutUint32(slot[20:24], uint32(scalar))
+	binary.BigEndian.PutUint64(slot[24:32], constant)
+	return slot
+}
+
+// OperatorCost returns the operator fee of the given amount of gas: the gas
+// scaled by the scalar, which has 6 decimals, plus the constant.
+func OperatorCost(gasUsed uint64, scalar, constant uint64) *big.Int {
+	cost := new(big.Int).SetUint64(gasUsed)
+	cost.Mul(cost, new(big.Int).SetUint64(scalar))
+	cost.Div(cost, big.NewInt(1_000_000))
+	return cost.Add(cost, new(big.Int).SetUint64(constant))
+}
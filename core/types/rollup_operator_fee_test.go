@@ -0,0 +1,44 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+type operatorFeeState map[common.Hash]common.Hash
+
+func (s operatorFeeState) GetState(addr common.Address, slot common.Hash) common.Hash {
+	if addr != L1BlockAddr {
+		return common.Hash{}
+	}
+	return s[slot]
+}
+
+func TestOperatorFeeParams(t *testing.T) {
+	slot := OperatorFeeParamsHash(1_500_000, 42)
+	scalar, constant := ExtractOperatorFeeParams(slot)
+	require.Equal(t, uint64(1_500_000), scalar)
+	require.Equal(t, uint64(42), constant)
+
+	require.Zero(t, big.NewInt(21000*3/2+42).Cmp(OperatorCost(21000, scalar, constant)))
+	require.Zero(t, OperatorCost(21000, 0, 0).Sign())
+}
+
+func TestOperatorCostFunc(t *testing.T) {
+	operatorFee := uint64(100)
+	config := &params.ChainConfig{
+		OasysOperatorFeeTime: &operatorFee,
+		ZeroFeeTimes:         []uint64{200, 300},
+		Optimism:             &params.OptimismConfig{},
+	}
+	costFn := NewOperatorCostFunc(config, operatorFeeState{OperatorFeeParamsSlot: OperatorFeeParamsHash(2_000_000, 7)})
+
+	require.Nil(t, costFn(1000, 99), "operator fee charged before its fork")
+	require.Zero(t, big.NewInt(2007).Cmp(costFn(1000, 100)))
+	require.Zero(t, costFn(1000, 250).Sign(), "operator fee charged in zero-fee window")
+	require.Zero(t, big.NewInt(2007).Cmp(costFn(1000, 300)))
+}
@@ -149,6 +149,14 @@ func init() {
 
 // ActivePrecompiles returns the precompiles enabled with the current configuration.
 func ActivePrecompiles(rules params.Rules) []common.Address {
+	precompiles := activePrecompiles(rules)
+	if rules.IsOasysL1Verifier {
+		precompiles = append(precompiles[:len(precompiles):len(precompiles)], L1VerifierAddress)
+	}
+	return precompiles
+}
+
+func activePrecompiles(rules params.Rules) []common.Address {
 	switch {
 	case rules.IsCancun:
 		return PrecompiledAddressesCancun
@@ -0,0 +1,104 @@
+package vm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// L1VerifierAddress is the address of the precompiled contract verifying the L1
+// headers and receipts, enabled by the OasysL1Verifier fork.
+var L1VerifierAddress = common.HexToAddress("0x0000000000000000000000000000000000000200")
+
+var (
+	errL1VerifierInput    = errors.New("invalid input")
+	errL1VerifierHeaders  = errors.New("invalid header chain")
+	errL1VerifierReceipt  = errors.New("invalid receipt proof")
+	errL1VerifierNoOrigin = errors.New("unknown L1 origin")
+)
+
+// l1VerifierInput is the RLP-encoded input of the L1 verifier precompile.
+type l1VerifierInput struct {
+	// Headers is the chain of RLP-encoded L1 headers to verify, starting with the
+	// L1 origin of the current block, each header being the parent of the previous
+	// one. The last header is the one the receipt is verified against.
+	Headers []rlp.RawValue
+
+	// ReceiptKey is the key of the receipt in the receipt trie, that is the RLP
+	// encoding of its transaction index. Empty to verify the headers only.
+	ReceiptKey []byte
+
+	// ReceiptProof is the list of the receipt trie nodes proving the receipt.
+	ReceiptProof [][]byte
+}
+
+// l1Verifier implements the precompiled contract verifying a chain of L1 headers
+// ending at the L1 origin known to the L1Block predeploy, and optionally the
+// inclusion of a receipt in the oldest of them.
+//
+// The output is the 32-byte hash of the oldest header, followed by its 32-byte
+// number, followed by the consensus encoding of the receipt if one was proven.
+type l1Verifier struct {
+	state StateDB
+}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract. It
+// only depends on the input length, every byte of which is hashed and decoded at
+// most once, and includes the read of the L1 origin from the L1Block storage.
+func (c *l1Verifier) RequiredGas(input []byte) uint64 {
+	words := toWordSize(uint64(len(input)))
+	return params.L1VerifierBaseGas + params.L1VerifierStorageReadGas + words*(params.Keccak256WordGas+params.L1VerifierPerWordGas)
+}
+
+func (c *l1Verifier) Run(input []byte) ([]byte, error) {
+	var args l1VerifierInput
+	if err := rlp.DecodeBytes(input, &args); err != nil {
+		return nil, fmt.Errorf("%w: %v", errL1VerifierInput, err)
+	}
+	if len(args.Headers) == 0 || len(args.Headers) > params.L1VerifierMaxHeaders {
+		return nil, fmt.Errorf("%w: %d headers", errL1VerifierInput, len(args.Headers))
+	}
+	// Walk the header chain back from the L1 origin
+	want := c.state.GetState(types.L1BlockAddr, types.L1HashSlot)
+	if want == (common.Hash{}) {
+		return nil, errL1VerifierNoOrigin
+	}
+	var header *types.Header
+	for i, enc := range args.Headers {
+		if hash := crypto.Keccak256Hash(enc); hash != want {
+			return nil, fmt.Errorf("%w: header %d hash %x, want %x", errL1VerifierHeaders, i, hash, want)
+		}
+		header = new(types.Header)
+		if err := rlp.DecodeBytes(enc, header); err != nil {
+			return nil, fmt.Errorf("%w: header %d: %v", errL1VerifierHeaders, i, err)
+		}
+		want = header.ParentHash
+	}
+	output := make([]byte, 64, 64+1024)
+	copy(output, crypto.Keccak256(args.Headers[len(args.Headers)-1]))
+	header.Number.FillBytes(output[32:64])
+
+	// Verify the receipt against the oldest header, if requested
+	if len(args.ReceiptKey) == 0 {
+		return output, nil
+	}
+	proof := memorydb.New()
+	for _, node := range args.ReceiptProof {
+		proof.Put(crypto.Keccak256(node), node)
+	}
+	receipt, err := trie.VerifyProof(header.ReceiptHash, args.ReceiptKey, proof)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errL1VerifierReceipt, err)
+	}
+	if len(receipt) == 0 {
+		return nil, fmt.Errorf("%w: receipt not found", errL1VerifierReceipt)
+	}
+	return append(output, receipt...), nil
+}
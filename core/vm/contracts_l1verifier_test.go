@@ -0,0 +1,96 @@
+package vm
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+func TestL1Verifier(t *testing.T) {
+	// Build a receipt trie and prove its second receipt
+	receipts := types.Receipts{
+		{Type: types.LegacyTxType, Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*types.Log{}},
+		{Type: types.DynamicFeeTxType, Status: types.ReceiptStatusFailed, CumulativeGasUsed: 42000, Logs: []*types.Log{}},
+	}
+	receiptTrie := trie.NewEmpty(trie.NewDatabase(rawdb.NewMemoryDatabase(), nil))
+	for i, receipt := range receipts {
+		key, _ := rlp.EncodeToBytes(uint(i))
+		enc, _ := receipt.MarshalBinary()
+		receiptTrie.MustUpdate(key, enc)
+	}
+	key, _ := rlp.EncodeToBytes(uint(1))
+	proofDb := memorydb.New()
+	if err := receiptTrie.Prove(key, proofDb); err != nil {
+		t.Fatalf("failed to prove receipt: %v", err)
+	}
+	var proof [][]byte
+	it := proofDb.NewIterator(nil, nil)
+	for it.Next() {
+		proof = append(proof, common.CopyBytes(it.Value()))
+	}
+	it.Release()
+
+	// Build a header chain on top of the block holding the receipts
+	target := &types.Header{Number: big.NewInt(100), ReceiptHash: receiptTrie.Hash(), Difficulty: new(big.Int)}
+	origin := &types.Header{Number: big.NewInt(101), ParentHash: target.Hash(), Difficulty: new(big.Int)}
+	headers := make([]rlp.RawValue, 2)
+	headers[0], _ = rlp.EncodeToBytes(origin)
+	headers[1], _ = rlp.EncodeToBytes(target)
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	verifier := &l1Verifier{state: statedb}
+
+	input, _ := rlp.EncodeToBytes(&l1VerifierInput{Headers: headers, ReceiptKey: key, ReceiptProof: proof})
+	if _, err := verifier.Run(input); !errors.Is(err, errL1VerifierNoOrigin) {
+		t.Fatalf("expected %v without L1 origin, got %v", errL1VerifierNoOrigin, err)
+	}
+	statedb.SetState(types.L1BlockAddr, types.L1HashSlot, origin.Hash())
+
+	// Verify the receipt along with the header chain
+	output, err := verifier.Run(input)
+	if err != nil {
+		t.Fatalf("failed to verify receipt: %v", err)
+	}
+	want, _ := receipts[1].MarshalBinary()
+	if hash := common.BytesToHash(output[:32]); hash != target.Hash() {
+		t.Errorf("header hash mismatch: have %x, want %x", hash, target.Hash())
+	}
+	if number := new(big.Int).SetBytes(output[32:64]); number.Cmp(target.Number) != 0 {
+		t.Errorf("header number mismatch: have %v, want %v", number, target.Number)
+	}
+	if !bytes.Equal(output[64:], want) {
+		t.Errorf("receipt mismatch: have %x, want %x", output[64:], want)
+	}
+	// Verify the header chain only
+	input, _ = rlp.EncodeToBytes(&l1VerifierInput{Headers: headers})
+	if output, err = verifier.Run(input); err != nil || len(output) != 64 {
+		t.Fatalf("failed to verify headers: %x, %v", output, err)
+	}
+	// Reject broken header chains and proofs
+	input, _ = rlp.EncodeToBytes(&l1VerifierInput{Headers: headers[1:]})
+	if _, err := verifier.Run(input); !errors.Is(err, errL1VerifierHeaders) {
+		t.Fatalf("expected %v for unanchored chain, got %v", errL1VerifierHeaders, err)
+	}
+	input, _ = rlp.EncodeToBytes(&l1VerifierInput{Headers: headers, ReceiptKey: key, ReceiptProof: proof[:1]})
+	if _, err := verifier.Run(input); !errors.Is(err, errL1VerifierReceipt) {
+		t.Fatalf("expected %v for incomplete proof, got %v", errL1VerifierReceipt, err)
+	}
+	// The gas only depends on the input length, the L1 origin read included
+	gas := verifier.RequiredGas(input)
+	if want := params.L1VerifierBaseGas + params.L1VerifierStorageReadGas + toWordSize(uint64(len(input)))*(params.Keccak256WordGas+params.L1VerifierPerWordGas); gas != want {
+		t.Errorf("gas mismatch: have %d, want %d", gas, want)
+	}
+	if garbage := verifier.RequiredGas(make([]byte, len(input))); garbage != gas {
+		t.Errorf("gas mismatch for undecodable input: have %d, want %d", garbage, gas)
+	}
+}
@@ -18,6 +18,7 @@ package vm
 
 import (
 	"math/big"
+	"sync"
 	"sync/atomic"
 
 	"github.com/holiman/uint256"
@@ -39,6 +40,9 @@ type (
 )
 
 func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
+	if evm.chainRules.IsOasysL1Verifier && addr == L1VerifierAddress {
+		return &l1Verifier{state: evm.StateDB}, true
+	}
 	var precompiles map[common.Address]PrecompiledContract
 	switch {
 	case evm.chainRules.IsCancun:
@@ -68,6 +72,8 @@ type BlockContext struct {
 	GetHash GetHashFunc
 	// L1CostFunc returns the L1 cost of the rollup message, the function may be nil, or return nil
 	L1CostFunc types.L1CostFunc
+	// OperatorCostFunc returns the operator fee of the rollup message, the function may be nil, or return nil
+	OperatorCostFunc types.OperatorCostFunc
 
 	// Block information
 	Coinbase    common.Address // Provides information for COINBASE
@@ -120,14 +126,27 @@ type EVM struct {
 	interpreter *EVMInterpreter
 	// abort is used to abort the EVM calling operations
 	abort atomic.Bool
+	// memoryUsed is the memory allocated by the active call frames, tracked
+	// if a memory limit is configured
+	memoryUsed uint64
+	// memoryExceeded is set if the execution was aborted due to the memory limit
+	memoryExceeded bool
 	// callGasTemp holds the gas available for the current call. This is needed because the
 	// available gas is calculated in gasCall* according to the 63/64 rule and later
 	// applied in opCall*.
 	callGasTemp uint64
 }
 
-// NewEVM returns a new EVM. The returned EVM is not thread safe and should
-// only ever be used *once*.
+// evmPool recycles the EVMs released after use, along with the hasher of their
+// interpreter.
+var evmPool = sync.Pool{
+	New: func() any {
+		return new(EVM)
+	},
+}
+
+// NewEVM returns a new EVM, recycled from the pool if available. The returned
+// EVM is not thread safe and should only ever be used *once*.
 func NewEVM(blockCtx BlockContext, txCtx TxContext, statedb StateDB, chainConfig *params.ChainConfig, config Config) *EVM {
 	// If basefee tracking is disabled (eth_call, eth_estimateGas, etc), and no
 	// gas prices were specified, lower the basefee to 0 to avoid breaking EVM
@@ -140,18 +159,48 @@ func NewEVM(blockCtx BlockContext, txCtx TxContext, statedb StateDB, chainConfig
 			blockCtx.BlobBaseFee = new(big.Int)
 		}
 	}
-	evm := &EVM{
-		Context:     blockCtx,
-		TxContext:   txCtx,
-		StateDB:     statedb,
-		Config:      config,
-		chainConfig: chainConfig,
-		chainRules:  chainConfig.Rules(blockCtx.BlockNumber, blockCtx.Random != nil, blockCtx.Time),
+	// Every field of a recycled EVM is overwritten, the abort flag included.
+	// The struct is not assigned as a whole as it holds an atomic.
+	evm := evmPool.Get().(*EVM)
+	evm.Context = blockCtx
+	evm.TxContext = txCtx
+	evm.StateDB = statedb
+	evm.depth = 0
+	evm.chainConfig = chainConfig
+	evm.chainRules = chainConfig.Rules(blockCtx.BlockNumber, blockCtx.Random != nil, blockCtx.Time)
+	evm.Config = config
+	evm.abort.Store(false)
+	evm.memoryUsed = 0
+	evm.memoryExceeded = false
+	evm.callGasTemp = 0
+
+	var hasher crypto.KeccakState
+	if evm.interpreter != nil {
+		hasher = evm.interpreter.hasher
 	}
 	evm.interpreter = NewEVMInterpreter(evm)
+	evm.interpreter.hasher = hasher
 	return evm
 }
 
+// Release returns the EVM to the pool once its owner is done with it, dropping
+// its references to the state and the contexts. Neither the EVM nor its
+// interpreter may be used afterwards, so it must only be released by the code
+// having created it, with no other reference escaping. An EVM traced is left to
+// the garbage collector instead, as the tracer may keep a reference to it.
+func (evm *EVM) Release() {
+	if evm.Config.Tracer != nil {
+		return
+	}
+	evm.Context = BlockContext{}
+	evm.TxContext = TxContext{}
+	evm.StateDB = nil
+	evm.Config = Config{}
+	evm.interpreter.evm = nil
+	evm.interpreter.returnData = nil
+	evmPool.Put(evm)
+}
+
 // Reset resets the EVM with a new transaction context.Reset
 // This is not threadsafe and should only be done very cautiously.
 func (evm *EVM) Reset(txCtx TxContext, statedb StateDB) {
@@ -170,6 +219,12 @@ func (evm *EVM) Cancelled() bool {
 	return evm.abort.Load()
 }
 
+// MemoryExceeded returns true if the execution was aborted due to exceeding the
+// configured memory limit.
+func (evm *EVM) MemoryExceeded() bool {
+	return evm.memoryExceeded
+}
+
 // Interpreter returns the current interpreter
 func (evm *EVM) Interpreter() *EVMInterpreter {
 	return evm.interpreter
@@ -0,0 +1,82 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// deactivators lists the opcodes introduced by the EIPs which can be disabled by
+// the custom EVM forks. The EIPs changing the semantics of existing opcodes can
+// only be enabled.
+var deactivators = map[int][]OpCode{
+	1153: {TLOAD, TSTORE},
+	1344: {CHAINID},
+	3198: {BASEFEE},
+	3855: {PUSH0},
+	4844: {BLOBHASH},
+	5656: {MCOPY},
+	7516: {BLOBBASEFEE},
+}
+
+// stateTransitionEIPs lists the EIPs whose changes are not confined to the jump
+// table, also altering the state transition in a way bound to the standard forks:
+// the access list warming (2929), the refund cap (3529) and the initcode size
+// limit (3860). They can't be toggled by the custom EVM forks.
+var stateTransitionEIPs = map[int]bool{
+	2929: true,
+	3529: true,
+	3860: true,
+}
+
+// DisableEIP removes the opcodes introduced by the given EIP from the jump table.
+// This operation writes in-place, and callers need to ensure that the globally
+// defined jump tables are not polluted.
+func DisableEIP(eipNum int, jt *JumpTable) error {
+	ops, ok := deactivators[eipNum]
+	if !ok {
+		return fmt.Errorf("eip %d cannot be disabled", eipNum)
+	}
+	for _, op := range ops {
+		jt[op] = &operation{execute: opUndefined, maxStack: maxStack(0, 0)}
+	}
+	return nil
+}
+
+// ValidateEVMForks checks that the custom EVM forks of the chain configuration
+// are sorted by time and only toggle the supported EIPs, those implemented in the
+// jump table alone.
+func ValidateEVMForks(config *params.ChainConfig) error {
+	for i, fork := range config.EVMForks {
+		if i > 0 && fork.Time < config.EVMForks[i-1].Time {
+			return fmt.Errorf("evm fork %q at %d scheduled before the previous fork at %d", fork.Name, fork.Time, config.EVMForks[i-1].Time)
+		}
+		for _, eip := range fork.EnableEIPs {
+			if !ValidEip(eip) || stateTransitionEIPs[eip] {
+				return fmt.Errorf("evm fork %q enables unsupported eip %d", fork.Name, eip)
+			}
+		}
+		for _, eip := range fork.DisableEIPs {
+			if _, ok := deactivators[eip]; !ok {
+				return fmt.Errorf("evm fork %q disables unsupported eip %d", fork.Name, eip)
+			}
+		}
+	}
+	return nil
+}
+
+// applyEVMForks returns the jump table with the EIPs toggled by the custom EVM
+// forks applied, copied if modified.
+func applyEVMForks(rules params.Rules, table *JumpTable) *JumpTable {
+	if len(rules.EnabledEIPs) == 0 && len(rules.DisabledEIPs) == 0 {
+		return table
+	}
+	table = copyJumpTable(table)
+	for _, eip := range rules.EnabledEIPs {
+		EnableEIP(eip, table) // Validated on startup
+	}
+	for _, eip := range rules.DisabledEIPs {
+		DisableEIP(eip, table)
+	}
+	return table
+}
@@ -0,0 +1,64 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestEVMForks(t *testing.T) {
+	config := *params.AllDevChainProtocolChanges // Shanghai enabled from genesis
+	config.EVMForks = []params.EVMForkConfig{
+		{Name: "nopush0", Time: 10, DisableEIPs: []int{3855}},
+		{Name: "mcopy", Time: 20, EnableEIPs: []int{3855, 5656}},
+	}
+	if err := ValidateEVMForks(&config); err != nil {
+		t.Fatalf("failed to validate forks: %v", err)
+	}
+	tests := []struct {
+		time  uint64
+		push0 bool
+		mcopy bool
+	}{
+		{0, true, false},
+		{10, false, false},
+		{19, false, false},
+		{20, true, true},
+	}
+	for _, tt := range tests {
+		table, err := LookupInstructionSet(config.Rules(big.NewInt(0), true, tt.time))
+		if err != nil {
+			t.Fatalf("time %d: failed to lookup instruction set: %v", tt.time, err)
+		}
+		if have := table[PUSH0].HasCost(); have != tt.push0 {
+			t.Errorf("time %d: PUSH0 availability mismatch: have %v, want %v", tt.time, have, tt.push0)
+		}
+		if have := table[MCOPY].HasCost(); have != tt.mcopy {
+			t.Errorf("time %d: MCOPY availability mismatch: have %v, want %v", tt.time, have, tt.mcopy)
+		}
+	}
+	// The standard instruction sets must not be modified
+	if !shanghaiInstructionSet[PUSH0].HasCost() || shanghaiInstructionSet[MCOPY].HasCost() {
+		t.Fatal("shanghai instruction set modified")
+	}
+	// Invalid fork schedules must be rejected
+	invalid := []params.EVMForkConfig{
+		{Name: "unsorted", Time: 10},
+		{Name: "before", Time: 5},
+	}
+	if config.EVMForks = invalid; ValidateEVMForks(&config) == nil {
+		t.Error("expected unsorted forks to be rejected")
+	}
+	if config.EVMForks = []params.EVMForkConfig{{Name: "unknown", EnableEIPs: []int{1}}}; ValidateEVMForks(&config) == nil {
+		t.Error("expected unknown enabled eip to be rejected")
+	}
+	if config.EVMForks = []params.EVMForkConfig{{Name: "sstore", DisableEIPs: []int{2200}}}; ValidateEVMForks(&config) == nil {
+		t.Error("expected non-disableable eip to be rejected")
+	}
+	for _, eip := range []int{2929, 3529, 3860} {
+		if config.EVMForks = []params.EVMForkConfig{{Name: "transition", EnableEIPs: []int{eip}}}; ValidateEVMForks(&config) == nil {
+			t.Errorf("expected state transition eip %d to be rejected", eip)
+		}
+	}
+}
@@ -29,6 +29,7 @@ type Config struct {
 	NoBaseFee               bool      // Forces the EIP-1559 baseFee to 0 (needed for 0 price calls)
 	EnablePreimageRecording bool      // Enables recording of SHA3/keccak preimages
 	ExtraEips               []int     // Additional EIPS that are to be enabled
+	MemoryLimit             uint64    // Memory allowed across all call frames, aborting the execution beyond (0 = unlimited)
 }
 
 // ScopeContext contains the things that are per-call, such as stack and memory,
@@ -81,6 +82,8 @@ func NewEVMInterpreter(evm *EVM) *EVMInterpreter {
 	default:
 		table = &frontierInstructionSet
 	}
+	table = applyEVMForks(evm.chainRules, table)
+
 	var extraEips []int
 	if len(evm.Config.ExtraEips) > 0 {
 		// Deep-copy jumptable to prevent modification of opcodes in other tables
@@ -151,7 +154,11 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 	// they are returned to the pools
 	defer func() {
 		returnStack(stack)
+		mem.Free()
 	}()
+	if in.evm.Config.MemoryLimit > 0 {
+		defer func() { in.evm.memoryUsed -= uint64(mem.Len()) }()
+	}
 	contract.Input = input
 
 	if debug {
@@ -220,6 +227,15 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 				logged = true
 			}
 			if memorySize > 0 {
+				if limit := in.evm.Config.MemoryLimit; limit > 0 && memorySize > uint64(mem.Len()) {
+					used := in.evm.memoryUsed + memorySize - uint64(mem.Len())
+					if used > limit {
+						in.evm.memoryExceeded = true
+						in.evm.Cancel()
+						return nil, ErrMemoryLimitExceeded
+					}
+					in.evm.memoryUsed = used
+				}
 				mem.Resize(memorySize)
 			}
 		} else if debug {
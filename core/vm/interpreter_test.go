@@ -17,6 +17,7 @@
 package vm
 
 import (
+	"bytes"
 	"math/big"
 	"testing"
 	"time"
@@ -75,3 +76,131 @@ func TestLoopInterrupt(t *testing.T) {
 		}
 	}
 }
+
+func TestMemoryLimit(t *testing.T) {
+	address := common.BytesToAddress([]byte("contract"))
+	vmctx := BlockContext{
+		Transfer: func(StateDB, common.Address, common.Address, *big.Int) {},
+	}
+	// Expand the memory to 1MB: push3(0x100000) mload stop
+	code := common.Hex2Bytes("621000005100")
+
+	for _, tt := range []struct {
+		limit    uint64
+		exceeded bool
+	}{
+		{0, false},
+		{512 * 1024, true},
+		{2 * 1024 * 1024, false},
+	} {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.CreateAccount(address)
+		statedb.SetCode(address, code)
+		statedb.Finalise(true)
+
+		evm := NewEVM(vmctx, TxContext{}, statedb, params.AllEthashProtocolChanges, Config{MemoryLimit: tt.limit})
+		_, _, err := evm.Call(AccountRef(common.Address{}), address, nil, math.MaxUint64, new(big.Int))
+		if tt.exceeded {
+			if err != ErrMemoryLimitExceeded {
+				t.Errorf("limit %d: error mismatch: have %v, want %v", tt.limit, err, ErrMemoryLimitExceeded)
+			}
+			if !evm.MemoryExceeded() || !evm.Cancelled() {
+				t.Errorf("limit %d: execution not aborted", tt.limit)
+			}
+		} else if err != nil {
+			t.Errorf("limit %d: unexpected error: %v", tt.limit, err)
+		}
+		if evm.memoryUsed != 0 {
+			t.Errorf("limit %d: memory not released: %d", tt.limit, evm.memoryUsed)
+		}
+	}
+}
+
+func TestEVMRecycling(t *testing.T) {
+	address := common.BytesToAddress([]byte("contract"))
+	vmctx := BlockContext{
+		Transfer: func(StateDB, common.Address, common.Address, *big.Int) {},
+	}
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.CreateAccount(address)
+	// Return the calldata through the memory: calldatacopy(0, 0, 32) return(0, 32)
+	statedb.SetCode(address, common.Hex2Bytes("6020600060003760206000f3"))
+	statedb.Finalise(true)
+
+	first := bytes.Repeat([]byte{0x11}, 32)
+	evm := NewEVM(vmctx, TxContext{}, statedb, params.AllEthashProtocolChanges, Config{})
+	ret, _, err := evm.Call(AccountRef(common.Address{}), address, first, math.MaxUint64, new(big.Int))
+	if err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+	evm.Cancel()
+	evm.Release()
+
+	// The recycled EVM and memories must carry nothing over
+	for i := 0; i < 4; i++ {
+		evm = NewEVM(vmctx, TxContext{}, statedb, params.AllEthashProtocolChanges, Config{})
+		if evm.Cancelled() || evm.depth != 0 || evm.StateDB != statedb {
+			t.Fatalf("recycled EVM not reset")
+		}
+		second := bytes.Repeat([]byte{byte(0x22 + i)}, 32)
+		have, _, err := evm.Call(AccountRef(common.Address{}), address, second, math.MaxUint64, new(big.Int))
+		if err != nil {
+			t.Fatalf("call %d failed: %v", i, err)
+		}
+		if !bytes.Equal(have, second) {
+			t.Fatalf("call %d output mismatch: have %x, want %x", i, have, second)
+		}
+		evm.Release()
+	}
+	if !bytes.Equal(ret, first) {
+		t.Fatalf("returned data overwritten through the recycled memory: have %x, want %x", ret, first)
+	}
+}
+
+// Tests that the traced EVMs are not recycled, as the tracer may keep using them.
+func TestEVMRecyclingTraced(t *testing.T) {
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	traced := NewEVM(BlockContext{}, TxContext{}, statedb, params.AllEthashProtocolChanges, Config{Tracer: struct{ EVMLogger }{}})
+	traced.Release()
+	if traced.StateDB != statedb || traced.interpreter.evm != traced {
+		t.Fatalf("traced EVM reset on release")
+	}
+	for i := 0; i < 4; i++ {
+		evm := NewEVM(BlockContext{}, TxContext{}, statedb, params.AllEthashProtocolChanges, Config{})
+		if evm == traced {
+			t.Fatalf("traced EVM recycled")
+		}
+		evm.Release()
+	}
+}
+
+// Benchmarks the execution of a call in a new EVM, with and without recycling
+// the EVMs and their memories.
+func BenchmarkEVMRecycling(b *testing.B) {
+	address := common.BytesToAddress([]byte("contract"))
+	vmctx := BlockContext{
+		Transfer: func(StateDB, common.Address, common.Address, *big.Int) {},
+	}
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.CreateAccount(address)
+	// Hash the calldata through the memory: calldatacopy(0, 0, 32) keccak256(0, 32) return(0, 32)
+	statedb.SetCode(address, common.Hex2Bytes("60206000600037602060002060205260206020f3"))
+	statedb.Finalise(true)
+
+	input := bytes.Repeat([]byte{0x11}, 32)
+	run := func(b *testing.B, release bool) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			evm := NewEVM(vmctx, TxContext{}, statedb, params.AllEthashProtocolChanges, Config{})
+			if _, _, err := evm.Call(AccountRef(common.Address{}), address, input, math.MaxUint64, new(big.Int)); err != nil {
+				b.Fatal(err)
+			}
+			if release {
+				evm.Release()
+			}
+		}
+	}
+	b.Run("pooled", func(b *testing.B) { run(b, true) })
+	b.Run("unpooled", func(b *testing.B) { run(b, false) })
+}
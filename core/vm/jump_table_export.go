@@ -23,8 +23,13 @@ import (
 )
 
 // LookupInstructionSet returns the instruction set for the fork configured by
-// the rules.
+// the rules, including the EIPs toggled by the custom EVM forks.
 func LookupInstructionSet(rules params.Rules) (JumpTable, error) {
+	table, err := lookupInstructionSet(rules)
+	return *applyEVMForks(rules, &table), err
+}
+
+func lookupInstructionSet(rules params.Rules) (JumpTable, error) {
 	switch {
 	case rules.IsVerkle:
 		return newCancunInstructionSet(), errors.New("verkle-fork not defined yet")
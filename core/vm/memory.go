@@ -17,18 +17,41 @@
 package vm
 
 import (
+	"sync"
+
 	"github.com/holiman/uint256"
 )
 
+// maxPooledMemory is the maximum capacity of the memories returned to the pool,
+// larger ones being left to the garbage collector to bound the pool footprint.
+const maxPooledMemory = 16 * 1024
+
+var memoryPool = sync.Pool{
+	New: func() any {
+		return &Memory{}
+	},
+}
+
 // Memory implements a simple memory model for the ethereum virtual machine.
 type Memory struct {
 	store       []byte
 	lastGasCost uint64
 }
 
-// NewMemory returns a new memory model.
+// NewMemory returns a new memory model, recycled from the pool if available.
 func NewMemory() *Memory {
-	return &Memory{}
+	return memoryPool.Get().(*Memory)
+}
+
+// Free returns the memory to the pool. Neither the memory nor any slice of its
+// backing store may be used afterwards.
+func (m *Memory) Free() {
+	if cap(m.store) > maxPooledMemory {
+		return
+	}
+	m.store = m.store[:0]
+	m.lastGasCost = 0
+	memoryPool.Put(m)
 }
 
 // Set sets offset + size to value
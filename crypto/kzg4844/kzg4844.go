@@ -20,6 +20,7 @@ package kzg4844
 import (
 	"embed"
 	"errors"
+	"fmt"
 	"sync/atomic"
 )
 
@@ -64,6 +65,27 @@ func UseCKZG(use bool) error {
 	return nil
 }
 
+// UseBackend switches the KZG library implementation to the one with the given
+// name, gokzg or ckzg. It is safe to call while crypto operations are running.
+func UseBackend(name string) error {
+	switch name {
+	case "gokzg":
+		return UseCKZG(false)
+	case "ckzg":
+		return UseCKZG(true)
+	default:
+		return fmt.Errorf("unknown KZG library implementation %q, must be gokzg or ckzg", name)
+	}
+}
+
+// Backend returns the name of the KZG library implementation in use.
+func Backend() string {
+	if useCKZG.Load() {
+		return "ckzg"
+	}
+	return "gokzg"
+}
+
 // BlobToCommitment creates a small commitment out of a data blob.
 func BlobToCommitment(blob Blob) (Commitment, error) {
 	if useCKZG.Load() {
@@ -108,3 +130,16 @@ func VerifyBlobProof(blob Blob, commitment Commitment, proof Proof) error {
 	}
 	return gokzgVerifyBlobProof(blob, commitment, proof)
 }
+
+// VerifyBlobProofBatch verifies that the blobs correspond to the provided
+// commitments all at once, which is cheaper than verifying them one by one but
+// doesn't tell which blob is invalid.
+func VerifyBlobProofBatch(blobs []Blob, commitments []Commitment, proofs []Proof) error {
+	if len(commitments) != len(blobs) || len(proofs) != len(blobs) {
+		return errors.New("mismatching number of blobs, commitments and proofs")
+	}
+	if useCKZG.Load() {
+		return ckzgVerifyBlobProofBatch(blobs, commitments, proofs)
+	}
+	return gokzgVerifyBlobProofBatch(blobs, commitments, proofs)
+}
@@ -125,3 +125,28 @@ func ckzgVerifyBlobProof(blob Blob, commitment Commitment, proof Proof) error {
 	}
 	return nil
 }
+
+// ckzgVerifyBlobProofBatch verifies that the blobs correspond to the provided
+// commitments all at once.
+func ckzgVerifyBlobProofBatch(blobs []Blob, commitments []Commitment, proofs []Proof) error {
+	ckzgIniter.Do(ckzgInit)
+
+	var (
+		cblobs       = make([]ckzg4844.Blob, len(blobs))
+		ccommitments = make([]ckzg4844.Bytes48, len(commitments))
+		cproofs      = make([]ckzg4844.Bytes48, len(proofs))
+	)
+	for i := range blobs {
+		cblobs[i] = (ckzg4844.Blob)(blobs[i])
+		ccommitments[i] = (ckzg4844.Bytes48)(commitments[i])
+		cproofs[i] = (ckzg4844.Bytes48)(proofs[i])
+	}
+	valid, err := ckzg4844.VerifyBlobKZGProofBatch(cblobs, ccommitments, cproofs)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.New("invalid proof")
+	}
+	return nil
+}
@@ -60,3 +60,9 @@ func ckzgComputeBlobProof(blob Blob, commitment Commitment) (Proof, error) {
 func ckzgVerifyBlobProof(blob Blob, commitment Commitment, proof Proof) error {
 	panic("unsupported platform")
 }
+
+// ckzgVerifyBlobProofBatch verifies that the blobs correspond to the provided
+// commitments all at once.
+func ckzgVerifyBlobProofBatch(blobs []Blob, commitments []Commitment, proofs []Proof) error {
+	panic("unsupported platform")
+}
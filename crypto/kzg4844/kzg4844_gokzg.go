@@ -96,3 +96,21 @@ func gokzgVerifyBlobProof(blob Blob, commitment Commitment, proof Proof) error {
 
 	return context.VerifyBlobKZGProof((gokzg4844.Blob)(blob), (gokzg4844.KZGCommitment)(commitment), (gokzg4844.KZGProof)(proof))
 }
+
+// gokzgVerifyBlobProofBatch verifies that the blobs correspond to the provided
+// commitments all at once.
+func gokzgVerifyBlobProofBatch(blobs []Blob, commitments []Commitment, proofs []Proof) error {
+	gokzgIniter.Do(gokzgInit)
+
+	var (
+		gblobs       = make([]gokzg4844.Blob, len(blobs))
+		gcommitments = make([]gokzg4844.KZGCommitment, len(commitments))
+		gproofs      = make([]gokzg4844.KZGProof, len(proofs))
+	)
+	for i := range blobs {
+		gblobs[i] = (gokzg4844.Blob)(blobs[i])
+		gcommitments[i] = (gokzg4844.KZGCommitment)(commitments[i])
+		gproofs[i] = (gokzg4844.KZGProof)(proofs[i])
+	}
+	return context.VerifyBlobKZGProofBatch(gblobs, gcommitments, gproofs)
+}
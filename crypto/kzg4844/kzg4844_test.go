@@ -94,6 +94,36 @@ func testKZGWithBlob(t *testing.T, ckzg bool) {
 	}
 }
 
+func TestCKZGWithBlobBatch(t *testing.T)  { testKZGWithBlobBatch(t, true) }
+func TestGoKZGWithBlobBatch(t *testing.T) { testKZGWithBlobBatch(t, false) }
+func testKZGWithBlobBatch(t *testing.T, ckzg bool) {
+	if ckzg && !ckzgAvailable {
+		t.Skip("CKZG unavailable in this test build")
+	}
+	defer func(old bool) { useCKZG.Store(old) }(useCKZG.Load())
+	useCKZG.Store(ckzg)
+
+	var (
+		blobs       = []Blob{randBlob(), randBlob(), randBlob()}
+		commitments = make([]Commitment, len(blobs))
+		proofs      = make([]Proof, len(blobs))
+	)
+	for i, blob := range blobs {
+		commitments[i], _ = BlobToCommitment(blob)
+		proofs[i], _ = ComputeBlobProof(blob, commitments[i])
+	}
+	if err := VerifyBlobProofBatch(blobs, commitments, proofs); err != nil {
+		t.Fatalf("failed to verify KZG proofs for blobs: %v", err)
+	}
+	proofs[1], proofs[2] = proofs[2], proofs[1]
+	if err := VerifyBlobProofBatch(blobs, commitments, proofs); err == nil {
+		t.Fatalf("verified mismatching KZG proofs for blobs")
+	}
+	if err := VerifyBlobProofBatch(blobs, commitments, proofs[:2]); err == nil {
+		t.Fatalf("verified KZG proofs for blobs with missing proof")
+	}
+}
+
 func BenchmarkCKZGBlobToCommitment(b *testing.B)  { benchmarkBlobToCommitment(b, true) }
 func BenchmarkGoKZGBlobToCommitment(b *testing.B) { benchmarkBlobToCommitment(b, false) }
 func benchmarkBlobToCommitment(b *testing.B, ckzg bool) {
@@ -0,0 +1,147 @@
+package altda
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// newTestServer serves the given inputs keyed by their hex encoded commitment,
+// counting the requests received.
+func newTestServer(inputs map[string][]byte, requests *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		input, ok := inputs[strings.TrimPrefix(r.URL.Path, "/get/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(input)
+	}))
+}
+
+func TestDecodeTxData(t *testing.T) {
+	comm := Keccak256Commitment([]byte("input"))
+	if have, err := DecodeTxData(append([]byte{TxDataVersion1}, comm...)); err != nil || string(have) != string(comm) {
+		t.Fatalf("keccak256 commitment not decoded: %x %v", have, err)
+	}
+	if _, err := DecodeTxData(append([]byte{TxDataVersion1, byte(GenericCommitmentType), 0x0c}, 0x01)); err != nil {
+		t.Fatalf("generic commitment not decoded: %v", err)
+	}
+	for _, data := range [][]byte{nil, {0x00}, comm, {TxDataVersion1}, {TxDataVersion1, 0x00, 0x01}, {TxDataVersion1, 0x02}} {
+		if _, err := DecodeTxData(data); err == nil {
+			t.Errorf("invalid transaction data %x decoded", data)
+		}
+	}
+	if err := comm.Verify([]byte("input")); err != nil {
+		t.Fatalf("input rejected: %v", err)
+	}
+	if err := comm.Verify([]byte("other")); !errors.Is(err, errInputMismatch) {
+		t.Fatalf("mismatching input accepted: %v", err)
+	}
+}
+
+func TestChecker(t *testing.T) {
+	var (
+		inbox     = common.Address{0x01}
+		available = Keccak256Commitment([]byte("available"))
+		corrupted = Keccak256Commitment([]byte("corrupted"))
+		missing   = Keccak256Commitment([]byte("missing"))
+		requests  atomic.Int32
+	)
+	server := newTestServer(map[string][]byte{
+		hexutil.Encode(available): []byte("available"),
+		hexutil.Encode(corrupted): []byte("tampered"),
+	}, &requests)
+	defer server.Close()
+
+	checker := NewChecker(NewClient(server.URL, time.Second), []common.Address{inbox})
+	tx := func(to common.Address, comm Commitment) *types.Transaction {
+		return types.NewTransaction(0, to, new(big.Int), 100000, big.NewInt(1), append([]byte{TxDataVersion1}, comm...))
+	}
+	if err := checker.CheckAvailability(tx(inbox, available)); err != nil {
+		t.Fatalf("available input rejected: %v", err)
+	}
+	if err := checker.CheckAvailability(tx(inbox, corrupted)); err == nil {
+		t.Fatalf("corrupted input accepted")
+	}
+	if err := checker.CheckAvailability(tx(inbox, missing)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("missing input accepted: %v", err)
+	}
+	// Other recipients are not checked, and the known inputs not checked again
+	if err := checker.CheckAvailability(tx(common.Address{0x02}, missing)); err != nil {
+		t.Fatalf("transaction to another recipient rejected: %v", err)
+	}
+	if err := checker.CheckAvailability(tx(inbox, available)); err != nil {
+		t.Fatalf("available input rejected: %v", err)
+	}
+	if err := checker.CheckAvailability(tx(inbox, missing)); err == nil {
+		t.Fatalf("missing input accepted")
+	}
+	if n := requests.Load(); n != 3 {
+		t.Fatalf("request count mismatch: have %d, want 3", n)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	var (
+		inbox     = common.Address{0x01}
+		available = Keccak256Commitment([]byte("available"))
+		corrupted = Keccak256Commitment([]byte("corrupted"))
+		missing   = Keccak256Commitment([]byte("missing"))
+		requests  atomic.Int32
+	)
+	server := newTestServer(map[string][]byte{
+		hexutil.Encode(available): []byte("available"),
+		hexutil.Encode(corrupted): []byte("tampered"),
+	}, &requests)
+	defer server.Close()
+
+	block := func(comms ...Commitment) *types.Block {
+		txs := make([]*types.Transaction, len(comms))
+		for i, comm := range comms {
+			txs[i] = types.NewTransaction(uint64(i), inbox, new(big.Int), 100000, big.NewInt(1), append([]byte{TxDataVersion1}, comm...))
+		}
+		return types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}).WithBody(txs, nil)
+	}
+	resolver := NewResolver(NewClient(server.URL, time.Second), []common.Address{inbox}, FailureDefer)
+	resolver.backoff = time.Millisecond
+
+	// Resolved inputs are cached, corrupted ones not requested again
+	for i := 0; i < 2; i++ {
+		if err := resolver.ResolveBlock(block(available)); err != nil {
+			t.Fatalf("available input not resolved: %v", err)
+		}
+	}
+	if err := resolver.ResolveBlock(block(available, corrupted)); !errors.Is(err, errInputMismatch) {
+		t.Fatalf("corrupted input resolved: %v", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Fatalf("request count mismatch: have %d, want 2", n)
+	}
+	// Missing inputs are requested again before giving up
+	if err := resolver.ResolveBlock(block(missing)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("missing input resolved: %v", err)
+	}
+	if n := requests.Load(); n != 2+resolveAttempts {
+		t.Fatalf("request count mismatch: have %d, want %d", n, 2+resolveAttempts)
+	}
+	// Failures are ignored if requested
+	resolver.policy = FailureIgnore
+	if err := resolver.ResolveBlock(block(missing)); err != nil {
+		t.Fatalf("failure not ignored: %v", err)
+	}
+	if input, err := resolver.Resolve(context.Background(), available); err != nil || string(input) != "available" {
+		t.Fatalf("input mismatch: have %q, %v", input, err)
+	}
+}
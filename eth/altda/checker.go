@@ -0,0 +1,111 @@
+package altda
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// availableCacheSize is the number of commitments found available whose
+	// check is skipped.
+	availableCacheSize = 4096
+
+	// unavailableRecheck is the minimum interval between the checks of a
+	// commitment found unavailable, sparing the block building from waiting
+	// on the DA server again and again.
+	unavailableRecheck = 2 * time.Second
+)
+
+var (
+	availableMeter   = metrics.NewRegisteredMeter("altda/check/available", nil)
+	unavailableMeter = metrics.NewRegisteredMeter("altda/check/unavailable", nil)
+	checkTimer       = metrics.NewRegisteredTimer("altda/check/duration", nil)
+)
+
+// Checker verifies the availability of the alt-DA inputs committed to by the
+// transactions sent to a set of targets, e.g. the inboxes of applications
+// posting their data to an alt-DA layer, so that the sequencer only includes
+// them once their input can be retrieved.
+type Checker struct {
+	client  *Client
+	targets map[common.Address]struct{}
+
+	available   *lru.Cache[string, struct{}] // Commitments found available
+	unavailable map[string]time.Time         // Last check of the commitments found unavailable
+	lock        sync.Mutex                   // Lock protecting the unavailable commitments
+}
+
+// NewChecker creates a checker of the inputs committed to by the transactions
+// sent to the given targets, fetching them from the given DA server.
+func NewChecker(client *Client, targets []common.Address) *Checker {
+	c := &Checker{
+		client:      client,
+		targets:     make(map[common.Address]struct{}, len(targets)),
+		available:   lru.NewCache[string, struct{}](availableCacheSize),
+		unavailable: make(map[string]time.Time),
+	}
+	for _, target := range targets {
+		c.targets[target] = struct{}{}
+	}
+	return c
+}
+
+// CheckAvailability returns an error if the given transaction commits to an
+// alt-DA input which cannot be retrieved from the DA server or does not match
+// its commitment. The transactions sent to other recipients, or carrying no
+// commitment, always pass.
+func (c *Checker) CheckAvailability(tx *types.Transaction) error {
+	if tx.To() == nil {
+		return nil
+	}
+	if _, ok := c.targets[*tx.To()]; !ok {
+		return nil
+	}
+	comm, err := DecodeTxData(tx.Data())
+	if err != nil {
+		return nil
+	}
+	key := string(comm)
+	if c.available.Contains(key) {
+		return nil
+	}
+	c.lock.Lock()
+	last, ok := c.unavailable[key]
+	c.lock.Unlock()
+	if ok && time.Since(last) < unavailableRecheck {
+		return fmt.Errorf("input of commitment %x unavailable", []byte(comm))
+	}
+	start := time.Now()
+	input, err := c.client.GetInput(context.Background(), comm)
+	if err == nil {
+		err = comm.Verify(input)
+	}
+	checkTimer.UpdateSince(start)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err != nil {
+		unavailableMeter.Mark(1)
+		for stale, last := range c.unavailable {
+			if time.Since(last) >= unavailableRecheck {
+				delete(c.unavailable, stale)
+			}
+		}
+		c.unavailable[key] = time.Now()
+		log.Debug("Alt-DA input unavailable", "tx", tx.Hash(), "commitment", common.Bytes2Hex(comm), "err", err)
+		return fmt.Errorf("input of commitment %x unavailable: %w", []byte(comm), err)
+	}
+	availableMeter.Mark(1)
+	delete(c.unavailable, key)
+	c.available.Add(key, struct{}{})
+	return nil
+}
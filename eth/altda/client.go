@@ -0,0 +1,64 @@
+package altda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxInputSize is the maximum size of an input served by a DA server, well
+// above the size of the batcher frames.
+const maxInputSize = 4 * 1024 * 1024
+
+// ErrNotFound is returned if the DA server does not serve the input of a
+// commitment.
+var ErrNotFound = errors.New("alt-DA input not found")
+
+// Client fetches the inputs of alt-DA commitments from a DA server, following
+// the API of the OP Stack DA servers: GET <url>/get/0x<commitment>.
+type Client struct {
+	url  string
+	http *http.Client
+}
+
+// NewClient creates a client of the DA server at the given URL, each request
+// being aborted after the given timeout.
+func NewClient(url string, timeout time.Duration) *Client {
+	return &Client{
+		url:  strings.TrimSuffix(url, "/"),
+		http: &http.Client{Timeout: timeout},
+	}
+}
+
+// GetInput fetches the input of the given commitment, without verifying it.
+func (c *Client) GetInput(ctx context.Context, comm Commitment) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/get/0x%x", c.url, []byte(comm)), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("DA server returned %s", resp.Status)
+	}
+	input, err := io.ReadAll(io.LimitReader(resp.Body, maxInputSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(input) > maxInputSize {
+		return nil, fmt.Errorf("input larger than %d bytes", maxInputSize)
+	}
+	return input, nil
+}
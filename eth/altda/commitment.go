@@ -0,0 +1,88 @@
+// Package altda implements the support of the alternative data availability
+// (alt-DA) layers of the OP Stack, whose transactions carry a commitment to
+// their input instead of the input itself, the input being served by a DA
+// server.
+package altda
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TxDataVersion1 is the version byte of the transaction data carrying an alt-DA
+// commitment.
+const TxDataVersion1 = 0x01
+
+// CommitmentType is the type of an alt-DA commitment, its first byte.
+type CommitmentType byte
+
+const (
+	// Keccak256CommitmentType commits to an input with its keccak256 hash.
+	Keccak256CommitmentType CommitmentType = 0x00
+
+	// GenericCommitmentType commits to an input in a way specific to the DA
+	// layer, identified by the byte following the type.
+	GenericCommitmentType CommitmentType = 0x01
+)
+
+var (
+	errNoCommitment    = errors.New("transaction data carries no alt-DA commitment")
+	errInvalidLength   = errors.New("invalid commitment length")
+	errUnknownType     = errors.New("unknown commitment type")
+	errInputMismatch   = errors.New("input does not match its commitment")
+	errEmptyCommitment = errors.New("empty commitment")
+)
+
+// Commitment is an encoded alt-DA commitment, its type byte first.
+type Commitment []byte
+
+// DecodeTxData decodes the alt-DA commitment carried by the given transaction
+// data.
+func DecodeTxData(data []byte) (Commitment, error) {
+	if len(data) == 0 || data[0] != TxDataVersion1 {
+		return nil, errNoCommitment
+	}
+	return DecodeCommitment(data[1:])
+}
+
+// DecodeCommitment validates the given encoded alt-DA commitment.
+func DecodeCommitment(data []byte) (Commitment, error) {
+	if len(data) == 0 {
+		return nil, errEmptyCommitment
+	}
+	switch CommitmentType(data[0]) {
+	case Keccak256CommitmentType:
+		if len(data) != 1+32 {
+			return nil, errInvalidLength
+		}
+	case GenericCommitmentType:
+		if len(data) < 2 {
+			return nil, errInvalidLength
+		}
+	default:
+		return nil, fmt.Errorf("%w: %#x", errUnknownType, data[0])
+	}
+	return Commitment(data), nil
+}
+
+// Keccak256Commitment returns the keccak256 commitment to the given input.
+func Keccak256Commitment(input []byte) Commitment {
+	return append(Commitment{byte(Keccak256CommitmentType)}, crypto.Keccak256(input)...)
+}
+
+// Type returns the type of the commitment.
+func (c Commitment) Type() CommitmentType {
+	return CommitmentType(c[0])
+}
+
+// Verify checks the given input against the commitment. Only keccak256
+// commitments are verifiable, the generic ones being left to the DA server.
+func (c Commitment) Verify(input []byte) error {
+	if c.Type() == Keccak256CommitmentType && !bytes.Equal(c[1:], crypto.Keccak256(input)) {
+		return errInputMismatch
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package altda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// inputCacheSize is the maximum bytes of resolved inputs kept in memory.
+	inputCacheSize = 64 * 1024 * 1024
+
+	// resolveAttempts is the number of times an input is requested from the DA
+	// server before giving up, the delay between the attempts doubling from
+	// resolveBackoff.
+	resolveAttempts = 3
+	resolveBackoff  = 250 * time.Millisecond
+)
+
+var (
+	resolveHitMeter     = metrics.NewRegisteredMeter("altda/resolve/hit", nil)
+	resolveMissMeter    = metrics.NewRegisteredMeter("altda/resolve/miss", nil)
+	resolveFailureMeter = metrics.NewRegisteredMeter("altda/resolve/failure", nil)
+	resolveTimer        = metrics.NewRegisteredTimer("altda/resolve/duration", nil)
+)
+
+// FailurePolicy is the handling of the payloads whose alt-DA inputs cannot be
+// resolved.
+type FailurePolicy int
+
+const (
+	// FailureDefer postpones the import of the payload, answering the consensus
+	// client that the node is syncing so that it delivers the payload again.
+	FailureDefer FailurePolicy = iota
+
+	// FailureIgnore imports the payload anyway, only logging the failure.
+	FailureIgnore
+
+	// FailureReject rejects the payload as invalid.
+	FailureReject
+)
+
+// ParseFailurePolicy parses the name of a failure policy.
+func ParseFailurePolicy(name string) (FailurePolicy, error) {
+	switch name {
+	case "defer":
+		return FailureDefer, nil
+	case "ignore":
+		return FailureIgnore, nil
+	case "reject":
+		return FailureReject, nil
+	default:
+		return 0, fmt.Errorf("unknown alt-DA failure policy %q, want defer, ignore or reject", name)
+	}
+}
+
+// String implements fmt.Stringer.
+func (p FailurePolicy) String() string {
+	switch p {
+	case FailureDefer:
+		return "defer"
+	case FailureIgnore:
+		return "ignore"
+	case FailureReject:
+		return "reject"
+	default:
+		return fmt.Sprintf("FailurePolicy(%d)", int(p))
+	}
+}
+
+// Resolver fetches and verifies the alt-DA inputs committed to by the
+// transactions sent to a set of targets, so that the payloads derived from
+// them are only imported once their data is retrievable. The resolved inputs
+// are cached, sparing the DA server the requests for payloads delivered again.
+type Resolver struct {
+	client  *Client
+	targets map[common.Address]struct{}
+	policy  FailurePolicy
+	inputs  *lru.SizeConstrainedCache[string, []byte]
+	backoff time.Duration
+}
+
+// NewResolver creates a resolver of the inputs committed to by the transactions
+// sent to the given targets, fetching them from the given DA server.
+func NewResolver(client *Client, targets []common.Address, policy FailurePolicy) *Resolver {
+	r := &Resolver{
+		client:  client,
+		targets: make(map[common.Address]struct{}, len(targets)),
+		policy:  policy,
+		inputs:  lru.NewSizeConstrainedCache[string, []byte](inputCacheSize),
+		backoff: resolveBackoff,
+	}
+	for _, target := range targets {
+		r.targets[target] = struct{}{}
+	}
+	return r
+}
+
+// Policy returns the handling of the payloads whose inputs cannot be resolved.
+func (r *Resolver) Policy() FailurePolicy {
+	return r.policy
+}
+
+// Resolve returns the verified input of the given commitment, fetching it from
+// the DA server if not cached.
+func (r *Resolver) Resolve(ctx context.Context, comm Commitment) ([]byte, error) {
+	if input, ok := r.inputs.Get(string(comm)); ok {
+		resolveHitMeter.Mark(1)
+		return input, nil
+	}
+	resolveMissMeter.Mark(1)
+	defer resolveTimer.UpdateSince(time.Now())
+
+	var (
+		input   []byte
+		err     error
+		backoff = r.backoff
+	)
+	for attempt := 0; attempt < resolveAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+				backoff *= 2
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if input, err = r.client.GetInput(ctx, comm); err == nil {
+			if err = comm.Verify(input); err == nil {
+				r.inputs.Add(string(comm), input)
+				return input, nil
+			}
+		}
+		// A corrupted input is not going to be fixed by asking again
+		if errors.Is(err, errInputMismatch) {
+			break
+		}
+	}
+	resolveFailureMeter.Mark(1)
+	return nil, err
+}
+
+// ResolveBlock resolves the inputs committed to by the transactions of the
+// given block. An error is returned if any cannot be resolved, unless the
+// failures are ignored.
+func (r *Resolver) ResolveBlock(block *types.Block) error {
+	for _, tx := range block.Transactions() {
+		if tx.To() == nil {
+			continue
+		}
+		if _, ok := r.targets[*tx.To()]; !ok {
+			continue
+		}
+		comm, err := DecodeTxData(tx.Data())
+		if err != nil {
+			continue
+		}
+		if _, err := r.Resolve(context.Background(), comm); err != nil {
+			if r.policy == FailureIgnore {
+				log.Warn("Ignoring unresolved alt-DA input", "number", block.Number(), "hash", block.Hash(), "tx", tx.Hash(), "err", err)
+				continue
+			}
+			return fmt.Errorf("unresolved alt-DA input of transaction %v: %w", tx.Hash(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+)
+
+// ancientPeerAttempts is the number of peers asked for an ancient block before
+// giving up, as many peers don't serve the full history.
+const ancientPeerAttempts = 3
+
+// peerAncientSource retrieves ancient blocks from the connected eth peers.
+type peerAncientSource struct {
+	peers *peerSet
+}
+
+func (s *peerAncientSource) Name() string {
+	return "peers"
+}
+
+func (s *peerAncientSource) Block(ctx context.Context, number uint64, hash common.Hash) (*types.Header, *types.Body, types.Receipts, error) {
+	peers := s.peers.allPeers()
+	if len(peers) == 0 {
+		return nil, nil, nil, errors.New("no peers")
+	}
+	if len(peers) > ancientPeerAttempts {
+		peers = peers[:ancientPeerAttempts]
+	}
+	var err error
+	for _, peer := range peers {
+		var (
+			header   *types.Header
+			body     *types.Body
+			receipts types.Receipts
+		)
+		if header, body, receipts, err = s.fetch(ctx, peer.Peer, hash); err == nil {
+			return header, body, receipts, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, nil, nil, err
+}
+
+// fetch retrieves the block with the given hash from a single peer.
+func (s *peerAncientSource) fetch(ctx context.Context, peer *eth.Peer, hash common.Hash) (*types.Header, *types.Body, types.Receipts, error) {
+	res, err := requestPeer(ctx, func(sink chan *eth.Response) (*eth.Request, error) {
+		return peer.RequestHeadersByHash(hash, 1, 0, false, sink)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	headers := *res.(*eth.BlockHeadersRequest)
+	if len(headers) != 1 {
+		return nil, nil, nil, fmt.Errorf("peer %s: header not found", peer.ID())
+	}
+	res, err = requestPeer(ctx, func(sink chan *eth.Response) (*eth.Request, error) {
+		return peer.RequestBodies([]common.Hash{hash}, sink)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	bodies := *res.(*eth.BlockBodiesResponse)
+	if len(bodies) != 1 {
+		return nil, nil, nil, fmt.Errorf("peer %s: body not found", peer.ID())
+	}
+	res, err = requestPeer(ctx, func(sink chan *eth.Response) (*eth.Request, error) {
+		return peer.RequestReceipts([]common.Hash{hash}, sink)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	receipts := *res.(*eth.ReceiptsResponse)
+	if len(receipts) != 1 {
+		return nil, nil, nil, fmt.Errorf("peer %s: receipts not found", peer.ID())
+	}
+	body := &types.Body{
+		Transactions: bodies[0].Transactions,
+		Uncles:       bodies[0].Uncles,
+		Withdrawals:  bodies[0].Withdrawals,
+	}
+	return headers[0], body, receipts[0], nil
+}
+
+// requestPeer sends a request to a peer and waits for its response, the response
+// being accepted regardless of its content as the caller validates it.
+func requestPeer(ctx context.Context, send func(chan *eth.Response) (*eth.Request, error)) (interface{}, error) {
+	sink := make(chan *eth.Response)
+	req, err := send(sink)
+	if err != nil {
+		return nil, err
+	}
+	defer req.Close()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-sink:
+		res.Done <- nil
+		return res.Res, nil
+	}
+}
@@ -0,0 +1,636 @@
+// Package ancientcheck implements a background checker which verifies the chain
+// segments stored in the freezer, repairing the corrupted items with data
+// retrieved from peers or from an archive endpoint.
+package ancientcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// batchSize is the number of blocks verified in each background round.
+	batchSize = 256
+
+	// requestTimeout is the time allowed to retrieve a block from a source.
+	requestTimeout = 10 * time.Second
+
+	// maxCorruptions is the number of recent corruptions kept in the status.
+	maxCorruptions = 64
+)
+
+var (
+	verifiedMeter   = metrics.NewRegisteredMeter("ancientcheck/verified", nil)
+	corruptionMeter = metrics.NewRegisteredMeter("ancientcheck/corruption", nil)
+	repairedMeter   = metrics.NewRegisteredMeter("ancientcheck/repaired", nil)
+	unrepairedMeter = metrics.NewRegisteredMeter("ancientcheck/unrepaired", nil)
+)
+
+// Source provides trusted copies of ancient blocks, validated by the checker
+// against the canonical hash before being used for repairs.
+type Source interface {
+	// Name identifies the source in logs and errors.
+	Name() string
+
+	// Block retrieves the header, body and receipts of the block with the given
+	// number and hash.
+	Block(ctx context.Context, number uint64, hash common.Hash) (*types.Header, *types.Body, types.Receipts, error)
+}
+
+// Corruption describes a corrupted ancient item.
+type Corruption struct {
+	Number      uint64    `json:"number"`
+	Kind        string    `json:"kind"` // Freezer table of the item
+	Error       string    `json:"error"`
+	Repaired    bool      `json:"repaired"`
+	RepairError string    `json:"repairError,omitempty"`
+	Detected    time.Time `json:"detected"`
+}
+
+// Result is the outcome of the verification of a range of ancient blocks.
+type Result struct {
+	From        uint64        `json:"from"`
+	To          uint64        `json:"to"`
+	Corruptions []*Corruption `json:"corruptions"`
+}
+
+// Status is the progress of the background verification.
+type Status struct {
+	Tail        uint64        `json:"tail"`   // First block stored in the freezer
+	Frozen      uint64        `json:"frozen"` // Number of blocks stored in the freezer
+	Cursor      uint64        `json:"cursor"` // Next block verified in the background
+	Verified    uint64        `json:"verified"`
+	Passes      uint64        `json:"passes"` // Number of complete passes over the freezer
+	Corruptions []*Corruption `json:"corruptions"`
+}
+
+// Checker cyclically verifies the ancient chain segments in the background,
+// repairing the corrupted items if sources are available.
+type Checker struct {
+	db       ethdb.Database
+	sources  []Source
+	interval time.Duration
+
+	verifyLock sync.Mutex // Serializes the verifications and repairs
+
+	lock        sync.Mutex
+	cursor      uint64
+	verified    uint64
+	passes      uint64
+	corruptions []*Corruption
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates an ancient data checker verifying a batch of blocks every interval
+// and repairing the corrupted items from the given sources.
+func New(db ethdb.Database, sources []Source, interval time.Duration) *Checker {
+	return &Checker{
+		db:       db,
+		sources:  sources,
+		interval: interval,
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start launches the background verification loop.
+func (c *Checker) Start() {
+	c.wg.Add(1)
+	go c.loop()
+}
+
+// Stop terminates the background verification loop.
+func (c *Checker) Stop() {
+	close(c.quit)
+	c.wg.Wait()
+}
+
+// Status returns the progress of the background verification.
+func (c *Checker) Status() *Status {
+	frozen, _ := c.db.Ancients()
+	tail, _ := c.db.Tail()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return &Status{
+		Tail:        tail,
+		Frozen:      frozen,
+		Cursor:      c.cursor,
+		Verified:    c.verified,
+		Passes:      c.passes,
+		Corruptions: append([]*Corruption{}, c.corruptions...),
+	}
+}
+
+func (c *Checker) loop() {
+	defer c.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-c.quit
+		cancel()
+	}()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.step(ctx); err != nil {
+				log.Debug("Ancient data verification failed", "err", err)
+			}
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// step verifies the next batch of blocks, starting over from the freezer tail
+// once the whole freezer is verified.
+func (c *Checker) step(ctx context.Context) error {
+	frozen, err := c.db.Ancients()
+	if err != nil {
+		return err
+	}
+	tail, err := c.db.Tail()
+	if err != nil {
+		return err
+	}
+	c.lock.Lock()
+	from := c.cursor
+	if from < tail {
+		from = tail
+	}
+	if from >= frozen {
+		if frozen > tail {
+			c.passes++
+		}
+		c.cursor = tail
+		c.lock.Unlock()
+		return nil
+	}
+	c.lock.Unlock()
+
+	to := from + batchSize
+	if to > frozen {
+		to = frozen
+	}
+	if _, err := c.verify(ctx, from, to, len(c.sources) > 0); err != nil {
+		return err
+	}
+	c.lock.Lock()
+	c.cursor = to
+	c.lock.Unlock()
+	return nil
+}
+
+// Verify checks the ancient blocks in [from, to), repairing the corrupted items
+// from the sources if requested. The range is clipped to the freezer content.
+func (c *Checker) Verify(ctx context.Context, from, to uint64, repair bool) (*Result, error) {
+	if to <= from {
+		return nil, fmt.Errorf("invalid range [%d, %d)", from, to)
+	}
+	if repair && len(c.sources) == 0 {
+		return nil, errors.New("no repair source available")
+	}
+	frozen, err := c.db.Ancients()
+	if err != nil {
+		return nil, err
+	}
+	tail, err := c.db.Tail()
+	if err != nil {
+		return nil, err
+	}
+	if from < tail {
+		from = tail
+	}
+	if to > frozen {
+		to = frozen
+	}
+	if from >= to {
+		return nil, fmt.Errorf("range not in freezer [%d, %d)", tail, frozen)
+	}
+	corruptions, err := c.verify(ctx, from, to, repair)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{From: from, To: to, Corruptions: corruptions}, nil
+}
+
+// verify checks the ancient blocks in [from, to), which must be in the freezer.
+func (c *Checker) verify(ctx context.Context, from, to uint64, repair bool) ([]*Corruption, error) {
+	c.verifyLock.Lock()
+	defer c.verifyLock.Unlock()
+
+	// The total difficulty of the first block is checked against the one stored
+	// for its parent, the next ones against the verified one of their parent.
+	// Without a parent, e.g. at the freezer tail, the stored one is the base.
+	var (
+		td          *big.Int
+		corruptions []*Corruption
+	)
+	if tail, _ := c.db.Tail(); from > tail {
+		td = c.ancientTd(from - 1)
+	}
+	for number := from; number < to; number++ {
+		if err := ctx.Err(); err != nil {
+			return corruptions, err
+		}
+		found, verifiedTd := c.verifyBlock(ctx, number, td, repair)
+		td = verifiedTd
+		corruptions = append(corruptions, found...)
+		verifiedMeter.Mark(1)
+	}
+	c.lock.Lock()
+	c.verified += to - from
+	c.corruptions = append(c.corruptions, corruptions...)
+	if len(c.corruptions) > maxCorruptions {
+		c.corruptions = c.corruptions[len(c.corruptions)-maxCorruptions:]
+	}
+	c.lock.Unlock()
+	return corruptions, nil
+}
+
+// verifyBlock checks the ancient items of the given block, parentTd being the
+// total difficulty of its parent if known. It returns the corruptions found and
+// the total difficulty of the block, nil if it can't be verified.
+func (c *Checker) verifyBlock(ctx context.Context, number uint64, parentTd *big.Int, repair bool) ([]*Corruption, *big.Int) {
+	var (
+		faults = make(map[string]error)
+		kinds  []string
+	)
+	fault := func(kind string, err error) {
+		faults[kind] = err
+		kinds = append(kinds, kind)
+	}
+	hash, err := c.canonicalHash(number)
+	if err != nil {
+		corruptionMeter.Mark(1)
+		log.Error("Ancient block unverifiable", "number", number, "err", err)
+		return []*Corruption{{Number: number, Kind: rawdb.ChainFreezerHeaderTable, Error: err.Error(), RepairError: "unknown canonical hash", Detected: time.Now()}}, nil
+	}
+	if blob, err := c.db.Ancient(rawdb.ChainFreezerHashTable, number); err != nil {
+		fault(rawdb.ChainFreezerHashTable, err)
+	} else if have := common.BytesToHash(blob); have != hash {
+		fault(rawdb.ChainFreezerHashTable, fmt.Errorf("hash mismatch: have %x, want %x", have, hash))
+	}
+	header, err := c.ancientHeader(number)
+	if err == nil && header.Hash() != hash {
+		err = fmt.Errorf("header hash mismatch: have %x, want %x", header.Hash(), hash)
+	}
+	if err != nil {
+		fault(rawdb.ChainFreezerHeaderTable, err)
+		header = nil
+	}
+	// Retrieve the block from the sources straight away if the local header is
+	// corrupted, the other items being only verifiable against a valid header
+	var (
+		remote    *block
+		remoteErr error
+	)
+	fetch := func() (*block, error) {
+		if remote == nil && remoteErr == nil {
+			remote, remoteErr = c.fetch(ctx, number, hash)
+		}
+		return remote, remoteErr
+	}
+	if header == nil && repair {
+		if b, err := fetch(); err == nil {
+			header = b.header
+		}
+	}
+	var td *big.Int
+	if header != nil {
+		body, err := c.ancientBody(number)
+		if err == nil {
+			err = verifyBody(header, body)
+		}
+		if err != nil {
+			fault(rawdb.ChainFreezerBodiesTable, err)
+			body = nil
+		}
+		receipts, err := c.ancientReceipts(number)
+		if err == nil {
+			if body != nil {
+				err = verifyReceipts(header, body.Transactions, receipts)
+			} else if repair {
+				// Verify the receipts against the transactions of the source
+				if b, ferr := fetch(); ferr == nil {
+					err = verifyReceipts(header, b.body.Transactions, receipts)
+				}
+			}
+		}
+		if err != nil {
+			fault(rawdb.ChainFreezerReceiptTable, err)
+		}
+		if parentTd == nil && number == 0 {
+			parentTd = new(big.Int)
+		}
+		have := c.ancientTd(number)
+		switch {
+		case parentTd != nil:
+			td = new(big.Int).Add(parentTd, header.Difficulty)
+			if have == nil {
+				fault(rawdb.ChainFreezerDifficultyTable, errors.New("invalid total difficulty"))
+			} else if have.Cmp(td) != 0 {
+				fault(rawdb.ChainFreezerDifficultyTable, fmt.Errorf("total difficulty mismatch: have %v, want %v", have, td))
+			}
+		case have == nil:
+			fault(rawdb.ChainFreezerDifficultyTable, errors.New("invalid total difficulty"))
+		default:
+			td = have
+		}
+	}
+	if len(kinds) == 0 {
+		return nil, td
+	}
+	corruptions := make([]*Corruption, 0, len(kinds))
+	for _, kind := range kinds {
+		corruption := &Corruption{Number: number, Kind: kind, Error: faults[kind].Error(), Detected: time.Now()}
+		corruptionMeter.Mark(1)
+
+		if repair {
+			err := c.repair(kind, number, hash, td, fetch)
+			if err != nil {
+				corruption.RepairError = err.Error()
+				unrepairedMeter.Mark(1)
+			} else {
+				corruption.Repaired = true
+				repairedMeter.Mark(1)
+			}
+		}
+		log.Error("Corrupted ancient item", "number", number, "hash", hash, "kind", kind,
+			"err", corruption.Error, "repaired", corruption.Repaired, "repairerr", corruption.RepairError)
+		corruptions = append(corruptions, corruption)
+	}
+	return corruptions, td
+}
+
+// repair rewrites the ancient item of the given kind with the valid data.
+func (c *Checker) repair(kind string, number uint64, hash common.Hash, td *big.Int, fetch func() (*block, error)) error {
+	var (
+		blob []byte
+		err  error
+	)
+	switch kind {
+	case rawdb.ChainFreezerHashTable:
+		blob = hash.Bytes()
+	case rawdb.ChainFreezerDifficultyTable:
+		if td == nil {
+			return errors.New("total difficulty unverifiable")
+		}
+		blob, err = rlp.EncodeToBytes(td)
+	default:
+		b, ferr := fetch()
+		if ferr != nil {
+			return ferr
+		}
+		switch kind {
+		case rawdb.ChainFreezerHeaderTable:
+			blob, err = rlp.EncodeToBytes(b.header)
+		case rawdb.ChainFreezerBodiesTable:
+			blob, err = rlp.EncodeToBytes(b.body)
+		case rawdb.ChainFreezerReceiptTable:
+			stored := make([]*types.ReceiptForStorage, len(b.receipts))
+			for i, receipt := range b.receipts {
+				stored[i] = (*types.ReceiptForStorage)(receipt)
+			}
+			blob, err = rlp.EncodeToBytes(stored)
+		default:
+			return fmt.Errorf("unknown ancient kind %q", kind)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return c.db.RewriteAncient(kind, number, blob)
+}
+
+// canonicalHash determines the canonical hash of the ancient block, from the
+// parent hash of its child if the child is intact, or from the block itself if
+// its stored header and hash agree.
+func (c *Checker) canonicalHash(number uint64) (common.Hash, error) {
+	frozen, err := c.db.Ancients()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if number+1 < frozen {
+		if child, err := c.ancientHeader(number + 1); err == nil {
+			blob, err := c.db.Ancient(rawdb.ChainFreezerHashTable, number+1)
+			if err == nil && common.BytesToHash(blob) == child.Hash() {
+				return child.ParentHash, nil
+			}
+		}
+	} else if hash := rawdb.ReadCanonicalHash(c.db, number+1); hash != (common.Hash{}) {
+		if child := rawdb.ReadHeader(c.db, hash, number+1); child != nil {
+			return child.ParentHash, nil
+		}
+	}
+	header, err := c.ancientHeader(number)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("child and header unverifiable: %w", err)
+	}
+	blob, err := c.db.Ancient(rawdb.ChainFreezerHashTable, number)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("child and hash unverifiable: %w", err)
+	}
+	if hash := header.Hash(); common.BytesToHash(blob) == hash {
+		return hash, nil
+	}
+	return common.Hash{}, errors.New("child unverifiable and header mismatching hash")
+}
+
+// block is a block retrieved from a source.
+type block struct {
+	header   *types.Header
+	body     *types.Body
+	receipts types.Receipts
+}
+
+// fetch retrieves the block from the first source serving it validly.
+func (c *Checker) fetch(ctx context.Context, number uint64, hash common.Hash) (*block, error) {
+	if len(c.sources) == 0 {
+		return nil, errors.New("no repair source available")
+	}
+	var errs []string
+	for _, source := range c.sources {
+		ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+		header, body, receipts, err := source.Block(ctx, number, hash)
+		cancel()
+		if err == nil {
+			err = verifyBlock(hash, header, body, receipts)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", source.Name(), err))
+			continue
+		}
+		return &block{header: header, body: body, receipts: receipts}, nil
+	}
+	return nil, errors.New(strings.Join(errs, "; "))
+}
+
+// verifyBlock checks that the source block matches the canonical hash.
+func verifyBlock(hash common.Hash, header *types.Header, body *types.Body, receipts types.Receipts) error {
+	if header == nil || body == nil {
+		return errors.New("block not found")
+	}
+	if header.Hash() != hash {
+		return fmt.Errorf("header hash mismatch: have %x, want %x", header.Hash(), hash)
+	}
+	if err := verifyBody(header, body); err != nil {
+		return err
+	}
+	return verifyReceipts(header, body.Transactions, receipts)
+}
+
+// verifyBody checks the body against the roots of the header.
+func verifyBody(header *types.Header, body *types.Body) error {
+	if root := types.DeriveSha(types.Transactions(body.Transactions), trie.NewStackTrie(nil)); root != header.TxHash {
+		return fmt.Errorf("transaction root mismatch: have %x, want %x", root, header.TxHash)
+	}
+	if hash := types.CalcUncleHash(body.Uncles); hash != header.UncleHash {
+		return fmt.Errorf("uncle hash mismatch: have %x, want %x", hash, header.UncleHash)
+	}
+	if header.WithdrawalsHash != nil {
+		if body.Withdrawals == nil {
+			return errors.New("missing withdrawals")
+		}
+		if root := types.DeriveSha(types.Withdrawals(body.Withdrawals), trie.NewStackTrie(nil)); root != *header.WithdrawalsHash {
+			return fmt.Errorf("withdrawals root mismatch: have %x, want %x", root, *header.WithdrawalsHash)
+		}
+	}
+	return nil
+}
+
+// verifyReceipts checks the receipts against the receipt root of the header,
+// filling in the consensus fields not kept in the storage encoding.
+func verifyReceipts(header *types.Header, txs []*types.Transaction, receipts types.Receipts) error {
+	if len(receipts) != len(txs) {
+		return fmt.Errorf("receipt count mismatch: have %d, want %d", len(receipts), len(txs))
+	}
+	for i, receipt := range receipts {
+		receipt.Type = txs[i].Type()
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	}
+	if root := types.DeriveSha(receipts, trie.NewStackTrie(nil)); root != header.ReceiptHash {
+		return fmt.Errorf("receipt root mismatch: have %x, want %x", root, header.ReceiptHash)
+	}
+	return nil
+}
+
+func (c *Checker) ancientHeader(number uint64) (*types.Header, error) {
+	blob, err := c.db.Ancient(rawdb.ChainFreezerHeaderTable, number)
+	if err != nil {
+		return nil, err
+	}
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(blob, header); err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	return header, nil
+}
+
+func (c *Checker) ancientBody(number uint64) (*types.Body, error) {
+	blob, err := c.db.Ancient(rawdb.ChainFreezerBodiesTable, number)
+	if err != nil {
+		return nil, err
+	}
+	body := new(types.Body)
+	if err := rlp.DecodeBytes(blob, body); err != nil {
+		return nil, fmt.Errorf("invalid body: %w", err)
+	}
+	return body, nil
+}
+
+func (c *Checker) ancientReceipts(number uint64) (types.Receipts, error) {
+	blob, err := c.db.Ancient(rawdb.ChainFreezerReceiptTable, number)
+	if err != nil {
+		return nil, err
+	}
+	var stored []*types.ReceiptForStorage
+	if err := rlp.DecodeBytes(blob, &stored); err != nil {
+		return nil, fmt.Errorf("invalid receipts: %w", err)
+	}
+	receipts := make(types.Receipts, len(stored))
+	for i, receipt := range stored {
+		receipts[i] = (*types.Receipt)(receipt)
+	}
+	return receipts, nil
+}
+
+func (c *Checker) ancientTd(number uint64) *big.Int {
+	blob, err := c.db.Ancient(rawdb.ChainFreezerDifficultyTable, number)
+	if err != nil {
+		return nil
+	}
+	td := new(big.Int)
+	if err := rlp.DecodeBytes(blob, td); err != nil {
+		return nil
+	}
+	return td
+}
+
+// rpcSource retrieves ancient blocks from an archive node over RPC.
+type rpcSource struct {
+	client *rpc.Client
+	name   string
+}
+
+// NewRPCSource creates a source retrieving ancient blocks from the node behind
+// the RPC client, using its debug raw block APIs.
+func NewRPCSource(client *rpc.Client, name string) Source {
+	return &rpcSource{client: client, name: name}
+}
+
+func (s *rpcSource) Name() string {
+	return s.name
+}
+
+func (s *rpcSource) Block(ctx context.Context, number uint64, hash common.Hash) (*types.Header, *types.Body, types.Receipts, error) {
+	var (
+		ref      = rpc.BlockNumberOrHashWithHash(hash, true)
+		rawBlock hexutil.Bytes
+		raws     []hexutil.Bytes
+	)
+	if err := s.client.CallContext(ctx, &rawBlock, "debug_getRawBlock", ref); err != nil {
+		return nil, nil, nil, err
+	}
+	if len(rawBlock) == 0 {
+		return nil, nil, nil, errors.New("block not found")
+	}
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(rawBlock, block); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid block: %w", err)
+	}
+	if err := s.client.CallContext(ctx, &raws, "debug_getRawReceipts", ref); err != nil {
+		return nil, nil, nil, err
+	}
+	receipts := make(types.Receipts, len(raws))
+	for i, raw := range raws {
+		receipts[i] = new(types.Receipt)
+		if err := receipts[i].UnmarshalBinary(raw); err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid receipt %d: %w", i, err)
+		}
+	}
+	return block.Header(), block.Body(), receipts, nil
+}
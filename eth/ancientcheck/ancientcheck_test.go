@@ -0,0 +1,121 @@
+package ancientcheck
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// testSource serves the blocks of a test chain.
+type testSource struct {
+	blocks   []*types.Block
+	receipts []types.Receipts
+}
+
+func (s *testSource) Name() string { return "test" }
+
+func (s *testSource) Block(ctx context.Context, number uint64, hash common.Hash) (*types.Header, *types.Body, types.Receipts, error) {
+	block := s.blocks[number]
+	return block.Header(), block.Body(), s.receipts[number], nil
+}
+
+func newTestSource(n int) *testSource {
+	source := new(testSource)
+	parent := common.Hash{}
+	for i := 0; i < n; i++ {
+		tx := types.NewTransaction(uint64(i), common.Address{0x01}, big.NewInt(int64(i)), 21000, big.NewInt(1), nil)
+		receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*types.Log{}}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		header := &types.Header{ParentHash: parent, Number: big.NewInt(int64(i)), Difficulty: big.NewInt(1), GasLimit: 30_000_000}
+		block := types.NewBlock(header, []*types.Transaction{tx}, nil, []*types.Receipt{receipt}, trie.NewStackTrie(nil))
+
+		source.blocks = append(source.blocks, block)
+		source.receipts = append(source.receipts, types.Receipts{receipt})
+		parent = block.Hash()
+	}
+	return source
+}
+
+// corrupt flips the last byte of an ancient item.
+func corrupt(t *testing.T, c *Checker, kind string, number uint64) {
+	blob, err := c.db.Ancient(kind, number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob = common.CopyBytes(blob)
+	blob[len(blob)-1] ^= 0x01
+	if err := c.db.RewriteAncient(kind, number, blob); err != nil {
+		t.Fatalf("corrupting %s #%d: %v", kind, number, err)
+	}
+}
+
+func TestVerifyAndRepair(t *testing.T) {
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), t.TempDir(), "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	source := newTestSource(8)
+	if _, err := rawdb.WriteAncientBlocks(db, source.blocks, source.receipts, big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	checker := New(db, []Source{source}, 0)
+
+	result, err := checker.Verify(context.Background(), 0, 8, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Corruptions) != 0 {
+		t.Fatalf("corruptions found in intact freezer: %v", result.Corruptions[0].Error)
+	}
+	corrupt(t, checker, rawdb.ChainFreezerBodiesTable, 2)
+	corrupt(t, checker, rawdb.ChainFreezerHashTable, 4)
+	corrupt(t, checker, rawdb.ChainFreezerReceiptTable, 5)
+	corrupt(t, checker, rawdb.ChainFreezerDifficultyTable, 6)
+
+	want := []struct {
+		number uint64
+		kind   string
+	}{
+		{2, rawdb.ChainFreezerBodiesTable},
+		{4, rawdb.ChainFreezerHashTable},
+		{5, rawdb.ChainFreezerReceiptTable},
+		{6, rawdb.ChainFreezerDifficultyTable},
+	}
+	for _, repair := range []bool{false, true} {
+		result, err := checker.Verify(context.Background(), 0, 100, repair)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.To != 8 {
+			t.Errorf("range not clipped: have %d, want %d", result.To, 8)
+		}
+		if len(result.Corruptions) != len(want) {
+			t.Fatalf("corruption count mismatch: have %d, want %d", len(result.Corruptions), len(want))
+		}
+		for i, corruption := range result.Corruptions {
+			if corruption.Number != want[i].number || corruption.Kind != want[i].kind {
+				t.Errorf("corruption %d: have %s #%d, want %s #%d", i, corruption.Kind, corruption.Number, want[i].kind, want[i].number)
+			}
+			if corruption.Repaired != repair {
+				t.Errorf("corruption %d: repaired %v, want %v (%s)", i, corruption.Repaired, repair, corruption.RepairError)
+			}
+		}
+	}
+	result, err = checker.Verify(context.Background(), 0, 8, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Corruptions) != 0 {
+		t.Fatalf("corruptions left after repair: %v", result.Corruptions[0].Error)
+	}
+	if status := checker.Status(); status.Verified != 8*4 || len(status.Corruptions) != 2*len(want) {
+		t.Errorf("status mismatch: verified %d, corruptions %d", status.Verified, len(status.Corruptions))
+	}
+}
@@ -18,14 +18,18 @@ package eth
 
 import (
 	"compress/gzip"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/ancientcheck"
+	"github.com/ethereum/go-ethereum/internal/shutdowncheck"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -141,3 +145,87 @@ func (api *AdminAPI) ImportChain(file string) (bool, error) {
 	}
 	return true, nil
 }
+
+// RuntimeConfig returns the currently effective runtime adjustable settings.
+func (api *AdminAPI) RuntimeConfig() RuntimeConfig {
+	return api.eth.RuntimeConfig()
+}
+
+// SetRuntimeConfig changes runtime adjustable settings without restarting the
+// node. Omitted fields are left unchanged.
+func (api *AdminAPI) SetRuntimeConfig(cfg RuntimeConfig) error {
+	return api.eth.ApplyRuntimeConfig(&cfg, "rpc")
+}
+
+// RecoveryReport returns how the node is recovering from an unclean shutdown of
+// its previous run, or nil if it was shut down cleanly.
+func (api *AdminAPI) RecoveryReport() *shutdowncheck.RecoveryReport {
+	return api.eth.shutdownTracker.RecoveryReport()
+}
+
+// Freeze activates the emergency stop, halting block production and payload
+// import while read requests keep being served. The node is unfrozen after the
+// given number of seconds, if non-zero.
+func (api *AdminAPI) Freeze(reason string, seconds *uint64) error {
+	var duration time.Duration
+	if seconds != nil {
+		duration = time.Duration(*seconds) * time.Second
+	}
+	return api.eth.Freeze(reason, FreezeSourceRPC, duration)
+}
+
+// Unfreeze lifts the emergency stop.
+func (api *AdminAPI) Unfreeze() {
+	api.eth.Unfreeze(FreezeSourceRPC)
+}
+
+// FreezeStatus returns the emergency stop state of the node.
+func (api *AdminAPI) FreezeStatus() FreezeStatus {
+	return api.eth.FreezeStatus()
+}
+
+// ScheduleHalt schedules the node to shut down at the given unix timestamp,
+// giving operators a controlled maintenance window. A zero timestamp cancels
+// the scheduled halt.
+func (api *AdminAPI) ScheduleHalt(timestamp uint64, reason string) error {
+	if timestamp == 0 {
+		api.eth.ScheduleHalt(time.Time{}, "")
+		return nil
+	}
+	at := time.Unix(int64(timestamp), 0)
+	if at.Before(time.Now()) {
+		return fmt.Errorf("halt time %v in the past", at)
+	}
+	api.eth.ScheduleHalt(at, reason)
+	return nil
+}
+
+// TxIndexProgress returns the state of the transaction index.
+func (api *AdminAPI) TxIndexProgress() (*core.TxIndexProgress, error) {
+	return api.eth.blockchain.TxIndexProgress()
+}
+
+// SetTxIndexLimit changes the number of recent blocks whose transactions are
+// indexed, 0 indexing the entire chain. The index is extended or pruned in the
+// background.
+func (api *AdminAPI) SetTxIndexLimit(limit uint64) error {
+	return api.eth.blockchain.UpdateTxLookupLimit(limit)
+}
+
+// ReindexTransactions regenerates the transaction indices of the blocks in
+// [from, to) in the background, backfilling blocks below the index tail.
+func (api *AdminAPI) ReindexTransactions(from uint64, to uint64) error {
+	return api.eth.blockchain.ReindexTransactions(from, to)
+}
+
+// VerifyAncients verifies the ancient blocks in [from, to), repairing the
+// corrupted items from the peers and the archive endpoint if requested.
+func (api *AdminAPI) VerifyAncients(ctx context.Context, from uint64, to uint64, repair bool) (*ancientcheck.Result, error) {
+	return api.eth.ancientChecker.Verify(ctx, from, to, repair)
+}
+
+// AncientCheckStatus returns the progress of the background verification of the
+// ancient blocks and the corruptions recently found.
+func (api *AdminAPI) AncientCheckStatus() *ancientcheck.Status {
+	return api.eth.ancientChecker.Status()
+}
@@ -29,7 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
-	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
@@ -40,11 +40,18 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/miner"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
+var (
+	sequencerForwardSuccessMeter = metrics.NewRegisteredMeter("rollup/sequencer/forward/success", nil)
+	sequencerForwardFailureMeter = metrics.NewRegisteredMeter("rollup/sequencer/forward/failure", nil)
+	sequencerForwardTimer        = metrics.NewRegisteredTimer("rollup/sequencer/forward/duration", nil)
+)
+
 // EthAPIBackend implements ethapi.Backend and tracers.Backend for full nodes
 type EthAPIBackend struct {
 	extRPCEnabled       bool
@@ -52,6 +59,9 @@ type EthAPIBackend struct {
 	disableTxPool       bool
 	eth                 *Ethereum
 	gpo                 *gasprice.Oracle
+
+	latestTag  rpc.BlockNumber            // Head the "latest" tag resolves to
+	latestTags map[string]rpc.BlockNumber // Per-namespace overrides of latestTag
 }
 
 // ChainConfig returns the active chain configuration.
@@ -68,7 +78,34 @@ func (b *EthAPIBackend) SetHead(number uint64) {
 	b.eth.blockchain.SetHead(number)
 }
 
+// resolveLatest maps the "latest" block tag to the head configured for the
+// request, so that RPC consumers can be pinned to the safe or finalized chain.
+// A tag set explicitly in the request context takes precedence over the
+// per-namespace and global configuration.
+//
+// The transaction and receipt lookups by hash hide the blocks beyond the mapped
+// head too. Subscriptions and filters (newHeads, logs) are not request scoped and
+// keep following the unsafe head, as do blocks and headers queried by hash.
+func (b *EthAPIBackend) resolveLatest(ctx context.Context, number rpc.BlockNumber) rpc.BlockNumber {
+	if number != rpc.LatestBlockNumber {
+		return number
+	}
+	if tag, ok := rpc.LatestBlockTagFromContext(ctx); ok {
+		return tag
+	}
+	if namespace, ok := rpc.NamespaceFromContext(ctx); ok {
+		if tag, ok := b.latestTags[namespace]; ok {
+			return tag
+		}
+	}
+	if b.latestTag == 0 {
+		return rpc.LatestBlockNumber
+	}
+	return b.latestTag
+}
+
 func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	number = b.resolveLatest(ctx, number)
 	// Pending block is only known by the miner
 	if number == rpc.PendingBlockNumber {
 		block := b.eth.miner.PendingBlock()
@@ -120,6 +157,7 @@ func (b *EthAPIBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*ty
 }
 
 func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	number = b.resolveLatest(ctx, number)
 	// Pending block is only known by the miner
 	if number == rpc.PendingBlockNumber {
 		block := b.eth.miner.PendingBlock()
@@ -288,32 +326,68 @@ func (b *EthAPIBackend) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) e
 	return b.eth.BlockChain().SubscribeChainSideEvent(ch)
 }
 
+func (b *EthAPIBackend) SubscribeHeadUpdateEvent(ch chan<- core.HeadUpdateEvent) event.Subscription {
+	return b.eth.BlockChain().SubscribeHeadUpdateEvent(ch)
+}
+
+func (b *EthAPIBackend) JournaledRemovedLogs(from uint64) []*types.Log {
+	return b.eth.BlockChain().JournaledRemovedLogs(from)
+}
+
 func (b *EthAPIBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription {
 	return b.eth.BlockChain().SubscribeLogsEvent(ch)
 }
 
 func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
-	if b.eth.seqRPCService != nil {
+	if sequencer := b.eth.sequencerClient(); sequencer != nil {
+		if b.eth.config.RollupForwardPrecheck {
+			if err := b.precheckForward(ctx, signedTx); err != nil {
+				return err
+			}
+		}
 		data, err := signedTx.MarshalBinary()
 		if err != nil {
 			return err
 		}
-		if err := b.eth.seqRPCService.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(data)); err != nil {
+		b.eth.forwards.forwarded(signedTx.Hash())
+		start := time.Now()
+		err = sequencer.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(data))
+		sequencerForwardTimer.UpdateSince(start)
+		b.eth.forwards.answered(signedTx.Hash(), err)
+		if err != nil {
+			sequencerForwardFailureMeter.Mark(1)
 			return err
 		}
+		sequencerForwardSuccessMeter.Mark(1)
+		if b.eth.inclusion != nil {
+			b.eth.inclusion.Submitted(signedTx.Hash())
+		}
 		if b.disableTxPool {
 			return nil
 		}
 		// Retain tx in local tx pool after forwarding, for local RPC usage.
-		if err := b.eth.txPool.Add([]*types.Transaction{signedTx}, true, false)[0]; err != nil {
+		if err := b.eth.txPool.AddContext(ctx, []*types.Transaction{signedTx}, true, false)[0]; err != nil {
 			log.Warn("successfully sent tx to sequencer, but failed to persist in local tx pool", "err", err, "tx", signedTx.Hash())
 		}
 		return nil
 	}
-	if b.disableTxPool {
+	// A promoted replica admits transactions into its pool, as it now sequences
+	// them, even if admission was disabled while forwarding.
+	if b.disableTxPool && !b.eth.promoted() {
 		return nil
 	}
-	return b.eth.txPool.Add([]*types.Transaction{signedTx}, true, false)[0]
+	if b.eth.txWAL != nil {
+		if err := b.eth.txWAL.Append(signedTx); err != nil {
+			return err
+		}
+	}
+	if err := b.eth.txPool.AddContext(ctx, []*types.Transaction{signedTx}, true, false)[0]; err != nil {
+		return err
+	}
+	if b.eth.inclusion != nil {
+		b.eth.inclusion.Submitted(signedTx.Hash())
+	}
+	return nil
 }
 
 func (b *EthAPIBackend) GetPoolTransactions() (types.Transactions, error) {
@@ -338,6 +412,13 @@ func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash)
 	return tx, blockHash, blockNumber, index, nil
 }
 
+func (b *EthAPIBackend) GetTransactionHashBySenderAndNonce(ctx context.Context, sender common.Address, nonce uint64) (*common.Hash, error) {
+	if !b.eth.blockchain.TxSenderIndexEnabled() {
+		return nil, errors.New("transaction sender index disabled")
+	}
+	return b.eth.blockchain.GetTxHashBySenderAndNonce(sender, nonce), nil
+}
+
 func (b *EthAPIBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error) {
 	return b.eth.txPool.Nonce(addr), nil
 }
@@ -402,19 +483,20 @@ func (b *EthAPIBackend) RPCEVMTimeout() time.Duration {
 	return b.eth.config.RPCEVMTimeout
 }
 
-func (b *EthAPIBackend) RPCTxFeeCap() float64 {
-	return b.eth.config.RPCTxFeeCap
+func (b *EthAPIBackend) RPCEVMMemoryLimit() uint64 {
+	return b.eth.config.RPCEVMMemoryLimit
 }
 
-func (b *EthAPIBackend) BloomStatus() (uint64, uint64) {
-	sections, _, _ := b.eth.bloomIndexer.Sections()
-	return params.BloomBitsBlocks, sections
+func (b *EthAPIBackend) RPCEVMReadLimit() uint64 {
+	return b.eth.config.RPCEVMReadLimit
 }
 
-func (b *EthAPIBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
-	for i := 0; i < bloomFilterThreads; i++ {
-		go session.Multiplex(bloomRetrievalBatch, bloomRetrievalWait, b.eth.bloomRequests)
-	}
+func (b *EthAPIBackend) RPCTxFeeCap() float64 {
+	return b.eth.config.RPCTxFeeCap
+}
+
+func (b *EthAPIBackend) LogIndex() filtermaps.LogIndex {
+	return b.eth.filterMaps
 }
 
 func (b *EthAPIBackend) Engine() consensus.Engine {
@@ -442,7 +524,7 @@ func (b *EthAPIBackend) StateAtTransaction(ctx context.Context, block *types.Blo
 }
 
 func (b *EthAPIBackend) HistoricalRPCService() *rpc.Client {
-	return b.eth.historicalRPCService
+	return b.eth.historicalClient()
 }
 
 func (b *EthAPIBackend) Genesis() *types.Block {
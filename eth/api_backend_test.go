@@ -0,0 +1,169 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testLatestService resolves the "latest" tag of the requests it serves.
+type testLatestService struct {
+	backend *EthAPIBackend
+}
+
+func (s *testLatestService) Resolve(ctx context.Context) rpc.BlockNumber {
+	return s.backend.resolveLatest(ctx, rpc.LatestBlockNumber)
+}
+
+func TestResolveLatest(t *testing.T) {
+	backend := &EthAPIBackend{
+		latestTag:  rpc.SafeBlockNumber,
+		latestTags: map[string]rpc.BlockNumber{"pinned": rpc.FinalizedBlockNumber},
+	}
+	ctx := context.Background()
+
+	if got := backend.resolveLatest(ctx, rpc.BlockNumber(5)); got != rpc.BlockNumber(5) {
+		t.Errorf("explicit number: got %d, want 5", got)
+	}
+	if got := backend.resolveLatest(ctx, rpc.PendingBlockNumber); got != rpc.PendingBlockNumber {
+		t.Errorf("pending tag: got %d, want %d", got, rpc.PendingBlockNumber)
+	}
+	if got := backend.resolveLatest(ctx, rpc.LatestBlockNumber); got != rpc.SafeBlockNumber {
+		t.Errorf("global tag: got %d, want %d", got, rpc.SafeBlockNumber)
+	}
+	pinned := rpc.WithLatestBlockTag(ctx, rpc.LatestBlockNumber)
+	if got := backend.resolveLatest(pinned, rpc.LatestBlockNumber); got != rpc.LatestBlockNumber {
+		t.Errorf("context tag: got %d, want %d", got, rpc.LatestBlockNumber)
+	}
+	if got := (&EthAPIBackend{}).resolveLatest(ctx, rpc.LatestBlockNumber); got != rpc.LatestBlockNumber {
+		t.Errorf("unconfigured: got %d, want %d", got, rpc.LatestBlockNumber)
+	}
+
+	// The per-namespace overrides apply to the requests served over RPC
+	server := rpc.NewServer()
+	defer server.Stop()
+	for _, namespace := range []string{"pinned", "other"} {
+		if err := server.RegisterName(namespace, &testLatestService{backend}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	for namespace, want := range map[string]rpc.BlockNumber{
+		"pinned": rpc.FinalizedBlockNumber,
+		"other":  rpc.SafeBlockNumber,
+	} {
+		var got rpc.BlockNumber
+		if err := client.Call(&got, namespace+"_resolve"); err != nil {
+			t.Fatalf("namespace %s: %v", namespace, err)
+		}
+		if got != want {
+			t.Errorf("namespace %s: got %d, want %d", namespace, got, want)
+		}
+	}
+}
+
+func TestHeaderByNumberLatestTag(t *testing.T) {
+	handler := newTestHandlerWithBlocks(4)
+	defer handler.close()
+
+	backend := &EthAPIBackend{
+		eth:       &Ethereum{blockchain: handler.chain},
+		latestTag: rpc.SafeBlockNumber,
+	}
+	ctx := context.Background()
+
+	// Without a safe head, "latest" must fail rather than fall back to unsafe
+	if _, err := backend.HeaderByNumber(ctx, rpc.LatestBlockNumber); err == nil {
+		t.Fatal("expected error without a safe head")
+	}
+	handler.chain.SetSafe(handler.chain.GetHeaderByNumber(2))
+
+	header, err := backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Number.Uint64() != 2 {
+		t.Errorf("latest: got block %d, want 2", header.Number)
+	}
+	header, err = backend.HeaderByNumber(rpc.WithLatestBlockTag(ctx, rpc.LatestBlockNumber), rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Number.Uint64() != 4 {
+		t.Errorf("unsafe latest: got block %d, want 4", header.Number)
+	}
+	block, err := backend.BlockByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.NumberU64() != 2 {
+		t.Errorf("latest block: got %d, want 2", block.NumberU64())
+	}
+}
+
+// testForwardSequencer is a sequencer endpoint accepting the transactions unless
+// configured to reject them.
+type testForwardSequencer struct {
+	reject bool
+}
+
+func (s *testForwardSequencer) SendRawTransaction(data hexutil.Bytes) (common.Hash, error) {
+	if s.reject {
+		return common.Hash{}, errors.New("rejected")
+	}
+	return common.Hash{}, nil
+}
+
+// enableTestMeter replaces a metered package variable with a functional meter
+// for the duration of the test.
+func enableTestMeter(t *testing.T, meter *metrics.Meter) {
+	enabled, orig := metrics.Enabled, *meter
+	metrics.Enabled = true
+	*meter = metrics.NewMeter()
+	t.Cleanup(func() {
+		(*meter).Stop()
+		metrics.Enabled, *meter = enabled, orig
+	})
+}
+
+func TestSendTxForwardMetrics(t *testing.T) {
+	enableTestMeter(t, &sequencerForwardSuccessMeter)
+	enableTestMeter(t, &sequencerForwardFailureMeter)
+
+	sequencer := new(testForwardSequencer)
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", sequencer); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	backend := &EthAPIBackend{
+		eth:           &Ethereum{config: &ethconfig.Config{}, seqRPCService: client},
+		disableTxPool: true,
+	}
+	tx := types.NewTx(&types.LegacyTx{Gas: 21000})
+	if err := backend.SendTx(context.Background(), tx); err != nil {
+		t.Fatalf("forwarding failed: %v", err)
+	}
+	sequencer.reject = true
+	if err := backend.SendTx(context.Background(), tx); err == nil {
+		t.Fatal("rejected forward succeeded")
+	}
+	if n := sequencerForwardSuccessMeter.Snapshot().Count(); n != 1 {
+		t.Errorf("forward successes: have %d, want 1", n)
+	}
+	if n := sequencerForwardFailureMeter.Snapshot().Count(); n != 1 {
+		t.Errorf("forward failures: have %d, want 1", n)
+	}
+}
@@ -18,6 +18,7 @@ package eth
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -28,6 +29,10 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/eth/altda"
+	"github.com/ethereum/go-ethereum/eth/dbinspect"
+	"github.com/ethereum/go-ethereum/eth/gasprofile"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -97,9 +102,10 @@ func (api *DebugAPI) Preimage(ctx context.Context, hash common.Hash) (hexutil.By
 
 // BadBlockArgs represents the entries in the list returned when bad blocks are queried.
 type BadBlockArgs struct {
-	Hash  common.Hash            `json:"hash"`
-	Block map[string]interface{} `json:"block"`
-	RLP   string                 `json:"rlp"`
+	Hash   common.Hash            `json:"hash"`
+	Block  map[string]interface{} `json:"block"`
+	RLP    string                 `json:"rlp"`
+	Report json.RawMessage        `json:"report,omitempty"`
 }
 
 // GetBadBlocks returns a list of the last 'bad blocks' that the client has seen on the network
@@ -124,9 +130,10 @@ func (api *DebugAPI) GetBadBlocks(ctx context.Context) ([]*BadBlockArgs, error)
 			blockJSON = map[string]interface{}{"error": err.Error()}
 		}
 		results = append(results, &BadBlockArgs{
-			Hash:  block.Hash(),
-			RLP:   blockRlp,
-			Block: blockJSON,
+			Hash:   block.Hash(),
+			RLP:    blockRlp,
+			Block:  blockJSON,
+			Report: rawdb.ReadBadBlockReport(api.eth.chainDb, block.Hash()),
 		})
 	}
 	return results, nil
@@ -137,53 +144,9 @@ const AccountRangeMaxResults = 256
 
 // AccountRange enumerates all accounts in the given block and start point in paging request
 func (api *DebugAPI) AccountRange(blockNrOrHash rpc.BlockNumberOrHash, start hexutil.Bytes, maxResults int, nocode, nostorage, incompletes bool) (state.IteratorDump, error) {
-	var stateDb *state.StateDB
-	var err error
-
-	if number, ok := blockNrOrHash.Number(); ok {
-		if number == rpc.PendingBlockNumber {
-			// If we're dumping the pending state, we need to request
-			// both the pending block as well as the pending state from
-			// the miner and operate on those
-			_, stateDb = api.eth.miner.Pending()
-			if stateDb == nil {
-				return state.IteratorDump{}, errors.New("pending state is not available")
-			}
-		} else {
-			var header *types.Header
-			switch number {
-			case rpc.LatestBlockNumber:
-				header = api.eth.blockchain.CurrentBlock()
-			case rpc.FinalizedBlockNumber:
-				header = api.eth.blockchain.CurrentFinalBlock()
-			case rpc.SafeBlockNumber:
-				header = api.eth.blockchain.CurrentSafeBlock()
-			default:
-				block := api.eth.blockchain.GetBlockByNumber(uint64(number))
-				if block == nil {
-					return state.IteratorDump{}, fmt.Errorf("block #%d not found", number)
-				}
-				header = block.Header()
-			}
-			if header == nil {
-				return state.IteratorDump{}, fmt.Errorf("block #%d not found", number)
-			}
-			stateDb, err = api.eth.BlockChain().StateAt(header.Root)
-			if err != nil {
-				return state.IteratorDump{}, err
-			}
-		}
-	} else if hash, ok := blockNrOrHash.Hash(); ok {
-		block := api.eth.blockchain.GetBlockByHash(hash)
-		if block == nil {
-			return state.IteratorDump{}, fmt.Errorf("block %s not found", hash.Hex())
-		}
-		stateDb, err = api.eth.BlockChain().StateAt(block.Root())
-		if err != nil {
-			return state.IteratorDump{}, err
-		}
-	} else {
-		return state.IteratorDump{}, errors.New("either block number or block hash must be specified")
+	stateDb, err := api.stateAtBlock(blockNrOrHash)
+	if err != nil {
+		return state.IteratorDump{}, err
 	}
 
 	opts := &state.DumpConfig{
@@ -268,6 +231,21 @@ func storageRangeAt(statedb *state.StateDB, root common.Hash, address common.Add
 	return result, nil
 }
 
+// ExecutionWitness regenerates the witness needed to execute the given block
+// statelessly, by re-executing it on top of its parent state. The parent state
+// must be available or regenerable from a recent enough one, e.g. to backfill
+// the witnesses of the blocks built before they were generated.
+func (api *DebugAPI) ExecutionWitness(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*ExecutionWitness, error) {
+	block, err := api.eth.APIBackend.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %v not found", blockNrOrHash)
+	}
+	return api.eth.executionWitness(ctx, block)
+}
+
 // GetModifiedAccountsByNumber returns all accounts that have changed between the
 // two blocks specified. A change is defined as a difference in nonce, balance,
 // code hash, or storage hash.
@@ -375,6 +353,20 @@ func (api *DebugAPI) GetAccessibleState(from, to rpc.BlockNumber) (uint64, error
 		log.Info("Found fast-sync pivot marker", "number", pivot)
 	}
 	var resolveNum = func(num rpc.BlockNumber) (uint64, error) {
+		switch num {
+		case rpc.SafeBlockNumber:
+			block := api.eth.blockchain.CurrentSafeBlock()
+			if block == nil {
+				return 0, errors.New("safe block missing")
+			}
+			return block.Number.Uint64(), nil
+		case rpc.FinalizedBlockNumber:
+			block := api.eth.blockchain.CurrentFinalBlock()
+			if block == nil {
+				return 0, errors.New("finalized block missing")
+			}
+			return block.Number.Uint64(), nil
+		}
 		// We don't have state for pending (-2), so treat it as latest
 		if num.Int64() < 0 {
 			block := api.eth.blockchain.CurrentBlock()
@@ -446,3 +438,124 @@ func (api *DebugAPI) GetTrieFlushInterval() (string, error) {
 	}
 	return api.eth.blockchain.GetTrieFlushInterval().String(), nil
 }
+
+// GasProfile returns the gas used and the calls received by the given number of
+// contracts using the most gas over the recently sampled blocks, all of them if
+// omitted.
+func (api *DebugAPI) GasProfile(count *int) (*gasprofile.Report, error) {
+	if api.eth.gasProfiler == nil {
+		return nil, errors.New("gas profiler disabled")
+	}
+	var n int
+	if count != nil {
+		n = *count
+	}
+	return api.eth.gasProfiler.Report(n), nil
+}
+
+// KzgBackend returns the name of the KZG library implementation in use.
+func (api *DebugAPI) KzgBackend() string {
+	return kzg4844.Backend()
+}
+
+// SetKzgBackend switches the KZG library implementation to the one with the
+// given name, gokzg or ckzg, e.g. to work around a bug in one of them. The
+// change does not survive a restart.
+func (api *DebugAPI) SetKzgBackend(name string) error {
+	if err := kzg4844.UseBackend(name); err != nil {
+		return err
+	}
+	log.Info("Switched the KZG library", "backend", name)
+	return nil
+}
+
+// WarmCacheStats returns the lookups of the state reads shared by the block
+// builder and the block import served since the node started.
+func (api *DebugAPI) WarmCacheStats() (*state.WarmCacheStats, error) {
+	cache := api.eth.blockchain.WarmCache()
+	if cache == nil {
+		return nil, errors.New("warm cache disabled")
+	}
+	stats := cache.Stats()
+	return &stats, nil
+}
+
+// SnapshotGeneration is the progress of the state snapshot (re)generation.
+type SnapshotGeneration struct {
+	Generating bool        `json:"generating"`
+	Rate       uint64      `json:"rate"` // Maximum bytes of state indexed per second, 0 if unlimited
+	Root       common.Hash `json:"root"`
+	Accounts   uint64      `json:"accounts"`
+	Slots      uint64      `json:"slots"`
+	Dangling   uint64      `json:"dangling"`
+	Storage    uint64      `json:"storage"` // Bytes of accounts and slots indexed
+	Done       float64     `json:"done"`    // Fraction of the account hash space indexed
+	ElapsedSec int64       `json:"elapsedSec"`
+	EtaSec     int64       `json:"etaSec"`
+}
+
+// SnapshotGeneration returns the progress of the state snapshot generation, as
+// of its last flush or progress log.
+func (api *DebugAPI) SnapshotGeneration() (*SnapshotGeneration, error) {
+	snaps := api.eth.blockchain.Snapshots()
+	if snaps == nil {
+		return nil, errors.New("snapshot disabled")
+	}
+	status := &SnapshotGeneration{Rate: snaps.GenerationRate()}
+	if progress := snaps.GenerationProgress(); progress != nil {
+		status.Generating = true
+		status.Root = progress.Root
+		status.Accounts = progress.Accounts
+		status.Slots = progress.Slots
+		status.Dangling = progress.Dangling
+		status.Storage = uint64(progress.Storage)
+		status.Done = progress.Done
+		status.ElapsedSec = int64(time.Since(progress.Started) / time.Second)
+		status.EtaSec = int64(progress.ETA / time.Second)
+	}
+	return status, nil
+}
+
+// SetSnapshotGenerationRate changes the maximum megabytes of state indexed per
+// second by the snapshot generation, 0 for unlimited. It applies to a running
+// generation, but not across restarts.
+func (api *DebugAPI) SetSnapshotGenerationRate(mb uint64) error {
+	snaps := api.eth.blockchain.Snapshots()
+	if snaps == nil {
+		return errors.New("snapshot disabled")
+	}
+	snaps.SetGenerationRate(mb * 1024 * 1024)
+	return nil
+}
+
+// InspectDatabase starts measuring the size of each category of data stored in
+// the database in the background, reported by DatabaseStats once completed. The
+// node keeps running, but traversing the whole database may take hours.
+func (api *DebugAPI) InspectDatabase() error {
+	return api.eth.dbInspector.Start()
+}
+
+// DatabaseStats returns the size of each category of data stored in the database
+// as of the last inspection completed, and its growth per hour since the
+// previous one.
+func (api *DebugAPI) DatabaseStats() *dbinspect.Report {
+	return api.eth.dbInspector.Report()
+}
+
+// AltDAInput returns the input of the given alt-DA commitment, fetched from the
+// DA server and verified if not resolved already.
+func (api *DebugAPI) AltDAInput(ctx context.Context, commitment hexutil.Bytes) (hexutil.Bytes, error) {
+	resolver := api.eth.daResolver
+	if resolver == nil {
+		return nil, errors.New("alt-DA input resolution disabled")
+	}
+	comm, err := altda.DecodeCommitment(commitment)
+	if err != nil {
+		return nil, err
+	}
+	input, err := resolver.Resolve(ctx, comm)
+	if err != nil {
+		return nil, err
+	}
+	return input, nil
+}
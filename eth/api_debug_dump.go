@@ -0,0 +1,168 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DumpOptions selects the accounts and the data dumped by the filtered state
+// dumps.
+type DumpOptions struct {
+	AddressPrefix  hexutil.Bytes   `json:"addressPrefix"`  // Only dump the accounts whose address starts with the prefix
+	IncludeCode    bool            `json:"includeCode"`    // Dump the code of the accounts
+	IncludeStorage bool            `json:"includeStorage"` // Dump the storage of the accounts
+	Start          hexutil.Bytes   `json:"start"`          // Account hash to start at, the next key of the previous page
+	Max            *hexutil.Uint64 `json:"max"`            // Maximum number of accounts of a page
+}
+
+// config converts the options into a dump configuration.
+func (opts *DumpOptions) config() *state.DumpConfig {
+	if opts == nil {
+		opts = new(DumpOptions)
+	}
+	return &state.DumpConfig{
+		SkipCode:          !opts.IncludeCode,
+		SkipStorage:       !opts.IncludeStorage,
+		OnlyWithAddresses: true,
+		Start:             opts.Start,
+		AddressPrefix:     opts.AddressPrefix,
+	}
+}
+
+// DumpStreamEnd is the last notification of a state dump stream.
+type DumpStreamEnd struct {
+	Root     common.Hash    `json:"root"`
+	Accounts hexutil.Uint64 `json:"accounts"`
+	Complete bool           `json:"complete"`
+}
+
+// DumpBlockFiltered retrieves a page of the accounts matching the options at the
+// given block. The next key of the result is the start of the following page.
+func (api *DebugAPI) DumpBlockFiltered(blockNrOrHash rpc.BlockNumberOrHash, opts *DumpOptions) (state.IteratorDump, error) {
+	stateDb, err := api.stateAtBlock(blockNrOrHash)
+	if err != nil {
+		return state.IteratorDump{}, err
+	}
+	conf := opts.config()
+	conf.Max = AccountRangeMaxResults
+	if opts != nil && opts.Max != nil && *opts.Max > 0 && *opts.Max < AccountRangeMaxResults {
+		conf.Max = uint64(*opts.Max)
+	}
+	return stateDb.IteratorDump(conf), nil
+}
+
+// DumpBlockStream streams the accounts matching the options at the given block,
+// one notification per account, followed by a DumpStreamEnd notification. The
+// dump stops once the subscription is cancelled.
+func (api *DebugAPI) DumpBlockStream(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, opts *DumpOptions) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	stateDb, err := api.stateAtBlock(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	conf := opts.config()
+	if opts != nil && opts.Max != nil {
+		conf.Max = uint64(*opts.Max)
+	}
+	conf.Interrupt = new(atomic.Bool)
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-rpcSub.Err():
+			case <-notifier.Closed():
+			case <-done:
+			}
+			conf.Interrupt.Store(true)
+		}()
+		stream := &dumpStream{notifier: notifier, id: rpcSub.ID, interrupt: conf.Interrupt}
+		stateDb.DumpToCollector(stream, conf)
+		if conf.Interrupt.Load() {
+			return
+		}
+		notifier.Notify(rpcSub.ID, &DumpStreamEnd{Root: stream.root, Accounts: hexutil.Uint64(stream.accounts), Complete: true})
+	}()
+	return rpcSub, nil
+}
+
+// dumpStream is a state dump collector sending each account as a notification.
+type dumpStream struct {
+	notifier  *rpc.Notifier
+	id        rpc.ID
+	interrupt *atomic.Bool
+	root      common.Hash
+	accounts  uint64
+}
+
+// OnRoot implements state.DumpCollector.
+func (s *dumpStream) OnRoot(root common.Hash) {
+	s.root = root
+}
+
+// OnAccount implements state.DumpCollector.
+func (s *dumpStream) OnAccount(addr *common.Address, account state.DumpAccount) {
+	account.Address = addr
+	if err := s.notifier.Notify(s.id, &account); err != nil {
+		log.Debug("Failed to stream dumped account", "err", err)
+		s.interrupt.Store(true)
+		return
+	}
+	s.accounts++
+}
+
+// stateAtBlock returns the state at the given block, the pending one included.
+func (api *DebugAPI) stateAtBlock(blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, error) {
+	if number, ok := blockNrOrHash.Number(); ok {
+		if number == rpc.PendingBlockNumber {
+			// If we're dumping the pending state, we need to request
+			// both the pending block as well as the pending state from
+			// the miner and operate on those
+			_, stateDb := api.eth.miner.Pending()
+			if stateDb == nil {
+				return nil, errors.New("pending state is not available")
+			}
+			return stateDb, nil
+		}
+		var header *types.Header
+		switch number {
+		case rpc.LatestBlockNumber:
+			header = api.eth.blockchain.CurrentBlock()
+		case rpc.FinalizedBlockNumber:
+			header = api.eth.blockchain.CurrentFinalBlock()
+		case rpc.SafeBlockNumber:
+			header = api.eth.blockchain.CurrentSafeBlock()
+		default:
+			block := api.eth.blockchain.GetBlockByNumber(uint64(number))
+			if block == nil {
+				return nil, fmt.Errorf("block #%d not found", number)
+			}
+			header = block.Header()
+		}
+		if header == nil {
+			return nil, fmt.Errorf("block #%d not found", number)
+		}
+		return api.eth.BlockChain().StateAt(header.Root)
+	} else if hash, ok := blockNrOrHash.Hash(); ok {
+		block := api.eth.blockchain.GetBlockByHash(hash)
+		if block == nil {
+			return nil, fmt.Errorf("block %s not found", hash.Hex())
+		}
+		return api.eth.BlockChain().StateAt(block.Root())
+	}
+	return nil, errors.New("either block number or block hash must be specified")
+}
@@ -69,6 +69,10 @@ func (api *MinerAPI) SetGasPrice(gasPrice hexutil.Big) bool {
 
 // SetGasLimit sets the gaslimit to target towards during mining.
 func (api *MinerAPI) SetGasLimit(gasLimit hexutil.Uint64) bool {
+	api.e.lock.Lock()
+	api.e.gasCeil = uint64(gasLimit)
+	api.e.lock.Unlock()
+
 	api.e.Miner().SetGasCeil(uint64(gasLimit))
 	return true
 }
@@ -0,0 +1,253 @@
+package eth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/eth/blockusage"
+	"github.com/ethereum/go-ethereum/eth/forkrehearsal"
+	"github.com/ethereum/go-ethereum/eth/gasgovernor"
+	"github.com/ethereum/go-ethereum/eth/inclusion"
+	"github.com/ethereum/go-ethereum/eth/noncegap"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// OasysAPI provides Oasys rollup specific information about the node.
+type OasysAPI struct {
+	e *Ethereum
+}
+
+// NewOasysAPI creates a new OasysAPI instance.
+func NewOasysAPI(e *Ethereum) *OasysAPI {
+	return &OasysAPI{e}
+}
+
+// Health returns the rollup-aware liveness and readiness state of the node, as
+// also served on the /healthz and /readyz HTTP endpoints.
+func (api *OasysAPI) Health(ctx context.Context) *HealthReport {
+	return api.e.Health(ctx)
+}
+
+// ClientIdentity returns the build information of the node along with the
+// fingerprint of its fork schedule and the supported OP Stack protocol version.
+func (api *OasysAPI) ClientIdentity() (*ClientIdentity, error) {
+	return api.e.ClientIdentity()
+}
+
+// ProtocolVersions returns the protocol version supported by the node and the
+// ones signaled by the rollup node, along with the halt scheduled if any.
+func (api *OasysAPI) ProtocolVersions() *ProtocolVersionStatus {
+	return api.e.ProtocolVersions()
+}
+
+// ReplicaStatus returns the failover replica state of the node.
+func (api *OasysAPI) ReplicaStatus() ReplicaStatus {
+	return api.e.ReplicaStatus()
+}
+
+// InclusionReport returns the time to inclusion and drop rate of the sampled
+// transactions submitted to the node.
+func (api *OasysAPI) InclusionReport() (*inclusion.Report, error) {
+	if api.e.inclusion == nil {
+		return nil, errors.New("inclusion monitor disabled")
+	}
+	return api.e.inclusion.Report(), nil
+}
+
+// BlockUsage returns the resources used by the import of the given number of
+// most recent canonical blocks, all those tracked if zero.
+func (api *OasysAPI) BlockUsage(count int) (*blockusage.Report, error) {
+	if api.e.blockUsage == nil {
+		return nil, errors.New("block usage tracker disabled")
+	}
+	return api.e.blockUsage.Report(count), nil
+}
+
+// GetOrderingAudit returns the arrival time and ordering rationale of the
+// transactions of a block built by the node, nil if none was recorded.
+func (api *OasysAPI) GetOrderingAudit(hash common.Hash) (*miner.OrderingAudit, error) {
+	return miner.ReadOrderingAudit(api.e.ChainDb(), hash)
+}
+
+// NonceGaps returns the accounts whose queued transactions are blocked by a nonce
+// gap, the oldest first.
+func (api *OasysAPI) NonceGaps() ([]*noncegap.Gap, error) {
+	if api.e.nonceGaps == nil {
+		return nil, errors.New("nonce gap monitor disabled")
+	}
+	return api.e.nonceGaps.Gaps(), nil
+}
+
+// GasGovernor returns the state of the controller adjusting the gas ceiling to
+// the block processing times.
+func (api *OasysAPI) GasGovernor() (*gasgovernor.Status, error) {
+	if api.e.gasGovernor == nil {
+		return nil, errors.New("gas governor disabled")
+	}
+	return api.e.gasGovernor.Status(), nil
+}
+
+// ForkRehearsals returns the outcome of the last rehearsal of the block
+// production of each fork activating within the rehearsal window.
+func (api *OasysAPI) ForkRehearsals() ([]*forkrehearsal.Result, error) {
+	if api.e.rehearsal == nil {
+		return nil, errors.New("fork rehearsal disabled")
+	}
+	return api.e.rehearsal.Results(), nil
+}
+
+// IndexerStatus is the progress of a custom chain index.
+type IndexerStatus struct {
+	Name        string         `json:"name"`
+	SectionSize hexutil.Uint64 `json:"sectionSize"`
+	Sections    hexutil.Uint64 `json:"sections"`
+	Head        *common.Hash   `json:"head"`
+}
+
+// Indexers returns the progress of the custom chain indexes enabled on the node.
+func (api *OasysAPI) Indexers() []*IndexerStatus {
+	statuses := make([]*IndexerStatus, 0, len(api.e.customIndexers))
+	for _, indexer := range api.e.customIndexers {
+		status := &IndexerStatus{
+			Name:        indexer.Module.Name,
+			SectionSize: hexutil.Uint64(indexer.Module.SectionSize),
+		}
+		if sections, _, head := indexer.Sections(); sections > 0 {
+			status.Sections, status.Head = hexutil.Uint64(sections), &head
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// GetTransactionStatus returns the status of a transaction forwarded to the
+// sequencer by this node or included in the local chain, null if unknown.
+func (api *OasysAPI) GetTransactionStatus(hash common.Hash) *TransactionStatus {
+	return api.e.TransactionStatus(hash)
+}
+
+// RPCL1Origin identifies the L1 block an L2 block was derived from.
+type RPCL1Origin struct {
+	Number hexutil.Uint64 `json:"number"`
+	Hash   common.Hash    `json:"hash"`
+}
+
+// RPCHeadUpdate is the notification sent on the move of a labelled head.
+type RPCHeadUpdate struct {
+	Label      string         `json:"label"`
+	Number     hexutil.Uint64 `json:"number"`
+	Hash       common.Hash    `json:"hash"`
+	ParentHash common.Hash    `json:"parentHash"`
+	Time       hexutil.Uint64 `json:"timestamp"`
+	L1Origin   *RPCL1Origin   `json:"l1Origin"`
+	ReorgDepth hexutil.Uint64 `json:"reorgDepth"`
+}
+
+func newRPCHeadUpdate(ev core.HeadUpdateEvent) *RPCHeadUpdate {
+	update := &RPCHeadUpdate{
+		Label:      ev.Label,
+		Number:     hexutil.Uint64(ev.Header.Number.Uint64()),
+		Hash:       ev.Header.Hash(),
+		ParentHash: ev.Header.ParentHash,
+		Time:       hexutil.Uint64(ev.Header.Time),
+		ReorgDepth: hexutil.Uint64(ev.ReorgDepth),
+	}
+	if ev.L1Origin != nil {
+		update.L1Origin = &RPCL1Origin{Number: hexutil.Uint64(ev.L1Origin.Number), Hash: ev.L1Origin.BlockHash}
+	}
+	return update
+}
+
+// PendingDelta is the RPC representation of the change of the pending block
+// since a sequence number.
+type PendingDelta struct {
+	Seq          hexutil.Uint64           `json:"seq"`
+	Number       hexutil.Uint64           `json:"number"`
+	ParentHash   common.Hash              `json:"parentHash"`
+	Reset        bool                     `json:"reset"`
+	Transactions []*ethapi.RPCTransaction `json:"transactions"`
+}
+
+// errPendingViewDisabled is returned by the pending block view if not enabled
+// by the operator.
+var errPendingViewDisabled = errors.New("pending block view disabled")
+
+// PendingBlock returns the pending block along with its sequence number, which
+// is incremented on every update of the pending block.
+func (api *OasysAPI) PendingBlock(ctx context.Context, fullTx bool) (map[string]interface{}, error) {
+	if !api.e.config.RollupPendingView || !api.e.config.Miner.RollupComputePendingBlock {
+		return nil, errPendingViewDisabled
+	}
+	seq, block := api.e.Miner().PendingSequenced()
+	if block == nil {
+		return nil, nil
+	}
+	fields, err := ethapi.RPCMarshalBlock(ctx, block, true, fullTx, api.e.APIBackend.ChainConfig(), api.e.APIBackend)
+	if err != nil {
+		return nil, err
+	}
+	fields["seq"] = hexutil.Uint64(seq)
+	return fields, nil
+}
+
+// PendingTransactionsSince returns the transactions added to the pending block
+// since the given sequence number. If the pending block was replaced in the
+// meantime, reset is set and all the transactions of the pending block are
+// returned.
+func (api *OasysAPI) PendingTransactionsSince(seq hexutil.Uint64) (*PendingDelta, error) {
+	if !api.e.config.RollupPendingView || !api.e.config.Miner.RollupComputePendingBlock {
+		return nil, errPendingViewDisabled
+	}
+	delta := api.e.Miner().PendingSince(uint64(seq))
+	if delta == nil {
+		return nil, nil
+	}
+	var (
+		config  = api.e.APIBackend.ChainConfig()
+		current = api.e.BlockChain().CurrentHeader()
+		result  = &PendingDelta{
+			Seq:          hexutil.Uint64(delta.Seq),
+			Number:       hexutil.Uint64(delta.Number),
+			ParentHash:   delta.ParentHash,
+			Reset:        delta.Reset,
+			Transactions: make([]*ethapi.RPCTransaction, len(delta.Transactions)),
+		}
+	)
+	for i, tx := range delta.Transactions {
+		result.Transactions[i] = ethapi.NewRPCPendingTransaction(tx, current, config)
+	}
+	return result, nil
+}
+
+// HeadUpdates sends a notification each time the unsafe, safe or finalized head
+// moves, along with its L1 origin and the depth of the reorg it caused.
+func (api *OasysAPI) HeadUpdates(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		updates := make(chan core.HeadUpdateEvent, 16)
+		updatesSub := api.e.BlockChain().SubscribeHeadUpdateEvent(updates)
+		defer updatesSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-updates:
+				notifier.Notify(rpcSub.ID, newRPCHeadUpdate(ev))
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
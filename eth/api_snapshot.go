@@ -0,0 +1,230 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+)
+
+const (
+	// defaultSnapshotChunk is the number of entries of a snapshot chunk when
+	// the request doesn't specify it.
+	defaultSnapshotChunk = 1024
+
+	// maxSnapshotChunk is the maximum number of entries of a snapshot chunk,
+	// bounding the memory used to serve a request.
+	maxSnapshotChunk = 16384
+)
+
+// SnapshotAPI exports the state at finalized blocks in chunks of consecutive
+// trie entries, each verifiable against the state root with its range proof.
+// It is only served on the authenticated endpoint.
+type SnapshotAPI struct {
+	eth *Ethereum
+}
+
+// NewSnapshotAPI creates a new state snapshot export API.
+func NewSnapshotAPI(eth *Ethereum) *SnapshotAPI {
+	return &SnapshotAPI{eth: eth}
+}
+
+// SnapshotAccount is an account of a snapshot chunk. The RLP encoding of the
+// account fields is the trie value proven by the chunk.
+type SnapshotAccount struct {
+	Hash        common.Hash     `json:"hash"`
+	Address     *common.Address `json:"address,omitempty"` // Preimage of the hash, if known
+	Nonce       hexutil.Uint64  `json:"nonce"`
+	Balance     *hexutil.Big    `json:"balance"`
+	StorageRoot common.Hash     `json:"storageRoot"`
+	CodeHash    common.Hash     `json:"codeHash"`
+}
+
+// SnapshotSlot is a storage slot of a snapshot chunk. The RLP encoding of the
+// value is the trie value proven by the chunk.
+type SnapshotSlot struct {
+	Hash  common.Hash   `json:"hash"`
+	Key   *common.Hash  `json:"key,omitempty"` // Preimage of the hash, if known
+	Value hexutil.Bytes `json:"value"`
+}
+
+// SnapshotChunk is a range of consecutive entries of the account trie or of a
+// storage trie, along with the Merkle proofs of the first requested and last
+// returned keys against the root of the trie.
+type SnapshotChunk struct {
+	Number   hexutil.Uint64     `json:"number"`
+	Hash     common.Hash        `json:"hash"`
+	Root     common.Hash        `json:"root"` // Root of the trie the chunk belongs to
+	Accounts []*SnapshotAccount `json:"accounts,omitempty"`
+	Storage  []*SnapshotSlot    `json:"storage,omitempty"`
+	Proof    []hexutil.Bytes    `json:"proof"`
+	Next     *common.Hash       `json:"next"` // Origin of the next chunk, null once the trie is exhausted
+}
+
+// GetSnapshot returns a chunk of the accounts at the given finalized block,
+// defaulting to the latest finalized one, starting at the given account hash.
+func (api *SnapshotAPI) GetSnapshot(ctx context.Context, blockNrOrHash *rpc.BlockNumberOrHash, origin common.Hash, limit *hexutil.Uint64) (*SnapshotChunk, error) {
+	header, err := api.finalizedHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	tr, err := trie.NewStateTrie(trie.StateTrieID(header.Root), api.eth.blockchain.TrieDB())
+	if err != nil {
+		return nil, fmt.Errorf("state of block #%d not available: %w", header.Number, err)
+	}
+	chunk := &SnapshotChunk{Number: hexutil.Uint64(header.Number.Uint64()), Hash: header.Hash(), Root: header.Root, Accounts: []*SnapshotAccount{}}
+	chunk.Proof, chunk.Next, err = snapshotRange(tr, origin, snapshotLimit(limit), func(key, value []byte) error {
+		account := new(types.StateAccount)
+		if err := rlp.DecodeBytes(value, account); err != nil {
+			return err
+		}
+		entry := &SnapshotAccount{
+			Hash:        common.BytesToHash(key),
+			Nonce:       hexutil.Uint64(account.Nonce),
+			Balance:     (*hexutil.Big)(account.Balance),
+			StorageRoot: account.Root,
+			CodeHash:    common.BytesToHash(account.CodeHash),
+		}
+		if preimage := tr.GetKey(key); preimage != nil {
+			address := common.BytesToAddress(preimage)
+			entry.Address = &address
+		}
+		chunk.Accounts = append(chunk.Accounts, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return chunk, nil
+}
+
+// GetSnapshotStorage returns a chunk of the storage of the account with the
+// given hash at the given finalized block, defaulting to the latest finalized
+// one, starting at the given slot hash.
+func (api *SnapshotAPI) GetSnapshotStorage(ctx context.Context, blockNrOrHash *rpc.BlockNumberOrHash, account common.Hash, origin common.Hash, limit *hexutil.Uint64) (*SnapshotChunk, error) {
+	header, err := api.finalizedHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	triedb := api.eth.blockchain.TrieDB()
+	accTrie, err := trie.NewStateTrie(trie.StateTrieID(header.Root), triedb)
+	if err != nil {
+		return nil, fmt.Errorf("state of block #%d not available: %w", header.Number, err)
+	}
+	acc, err := accTrie.GetAccountByHash(account)
+	if err != nil {
+		return nil, err
+	}
+	if acc == nil {
+		return nil, fmt.Errorf("account %x not found", account)
+	}
+	tr, err := trie.NewStateTrie(trie.StorageTrieID(header.Root, account, acc.Root), triedb)
+	if err != nil {
+		return nil, err
+	}
+	chunk := &SnapshotChunk{Number: hexutil.Uint64(header.Number.Uint64()), Hash: header.Hash(), Root: acc.Root, Storage: []*SnapshotSlot{}}
+	chunk.Proof, chunk.Next, err = snapshotRange(tr, origin, snapshotLimit(limit), func(key, value []byte) error {
+		_, content, _, err := rlp.Split(value)
+		if err != nil {
+			return err
+		}
+		entry := &SnapshotSlot{Hash: common.BytesToHash(key), Value: common.CopyBytes(content)}
+		if preimage := tr.GetKey(key); preimage != nil {
+			slot := common.BytesToHash(preimage)
+			entry.Key = &slot
+		}
+		chunk.Storage = append(chunk.Storage, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return chunk, nil
+}
+
+// finalizedHeader resolves the requested block, which must be a canonical block
+// not newer than the finalized one.
+func (api *SnapshotAPI) finalizedHeader(ctx context.Context, blockNrOrHash *rpc.BlockNumberOrHash) (*types.Header, error) {
+	final := api.eth.blockchain.CurrentFinalBlock()
+	if final == nil {
+		return nil, errors.New("no finalized block")
+	}
+	if blockNrOrHash == nil {
+		return final, nil
+	}
+	header, err := api.eth.APIBackend.HeaderByNumberOrHash(ctx, *blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("block %v not found", blockNrOrHash)
+	}
+	if header.Number.Cmp(final.Number) > 0 {
+		return nil, fmt.Errorf("block #%d is not finalized, finalized block is #%d", header.Number, final.Number)
+	}
+	if api.eth.blockchain.GetCanonicalHash(header.Number.Uint64()) != header.Hash() {
+		return nil, fmt.Errorf("block %x is not canonical", header.Hash())
+	}
+	return header, nil
+}
+
+// snapshotLimit returns the number of entries of a chunk for the requested limit.
+func snapshotLimit(limit *hexutil.Uint64) int {
+	if limit == nil || *limit == 0 {
+		return defaultSnapshotChunk
+	}
+	if *limit > maxSnapshotChunk {
+		return maxSnapshotChunk
+	}
+	return int(*limit)
+}
+
+// snapshotRange visits up to limit leaves of the trie starting at the origin,
+// returning the Merkle proofs of the origin and of the last visited key, along
+// with the key of the next leaf if the trie isn't exhausted.
+func snapshotRange(tr *trie.StateTrie, origin common.Hash, limit int, visit func(key, value []byte) error) ([]hexutil.Bytes, *common.Hash, error) {
+	nodeIt, err := tr.NodeIterator(origin[:])
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		it   = trie.NewIterator(nodeIt)
+		last []byte
+		next *common.Hash
+	)
+	for count := 0; it.Next(); count++ {
+		if count == limit {
+			key := common.BytesToHash(it.Key)
+			next = &key
+			break
+		}
+		if err := visit(it.Key, it.Value); err != nil {
+			return nil, nil, err
+		}
+		last = common.CopyBytes(it.Key)
+	}
+	if it.Err != nil {
+		return nil, nil, it.Err
+	}
+	proof := trienode.NewProofSet()
+	if err := tr.Prove(origin[:], proof); err != nil {
+		return nil, nil, err
+	}
+	if last != nil {
+		if err := tr.Prove(last, proof); err != nil {
+			return nil, nil, err
+		}
+	}
+	var proofs []hexutil.Bytes
+	for _, blob := range proof.List() {
+		proofs = append(proofs, hexutil.Bytes(blob))
+	}
+	return proofs, next, nil
+}
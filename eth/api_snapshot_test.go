@@ -0,0 +1,77 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+)
+
+// Tests that the snapshot chunks cover the whole account trie and that each one
+// is verifiable against the state root.
+func TestSnapshotRange(t *testing.T) {
+	var (
+		db     = state.NewDatabaseWithConfig(rawdb.NewMemoryDatabase(), &trie.Config{Preimages: true})
+		sdb, _ = state.New(types.EmptyRootHash, db, nil)
+	)
+	for i := 0; i < 100; i++ {
+		sdb.SetBalance(common.BigToAddress(big.NewInt(int64(i+1))), big.NewInt(int64(i+1)))
+	}
+	root, _ := sdb.Commit(0, true)
+
+	tr, err := trie.NewStateTrie(trie.StateTrieID(root), db.TrieDB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		origin common.Hash
+		total  int
+	)
+	for chunks := 0; ; chunks++ {
+		var keys, values [][]byte
+		proof, next, err := snapshotRange(tr, origin, 30, func(key, value []byte) error {
+			account := new(types.StateAccount)
+			if err := rlp.DecodeBytes(value, account); err != nil {
+				return err
+			}
+			if tr.GetKey(key) == nil {
+				t.Errorf("missing preimage of %x", key)
+			}
+			// Rebuild the trie value from the account fields, as a client would
+			blob, err := rlp.EncodeToBytes(&types.StateAccount{Nonce: account.Nonce, Balance: account.Balance, Root: account.Root, CodeHash: account.CodeHash})
+			if err != nil {
+				return err
+			}
+			keys, values = append(keys, common.CopyBytes(key)), append(values, blob)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("chunk %d: %v", chunks, err)
+		}
+		proofs := make(trienode.ProofList, 0, len(proof))
+		for _, blob := range proof {
+			proofs = append(proofs, rlp.RawValue(blob))
+		}
+		more, err := trie.VerifyRangeProof(root, origin[:], keys, values, proofs.Set())
+		if err != nil {
+			t.Fatalf("chunk %d: invalid range proof: %v", chunks, err)
+		}
+		if more != (next != nil) {
+			t.Fatalf("chunk %d: continuation mismatch: proof %v, next %v", chunks, more, next)
+		}
+		total += len(keys)
+		if next == nil {
+			break
+		}
+		origin = *next
+	}
+	if total != 100 {
+		t.Fatalf("account count mismatch: have %d, want 100", total)
+	}
+}
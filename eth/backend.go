@@ -24,6 +24,7 @@ import (
 	"math/big"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
@@ -33,7 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/core"
-	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/txpool"
@@ -41,11 +42,24 @@ import (
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/altda"
+	"github.com/ethereum/go-ethereum/eth/ancientcheck"
+	"github.com/ethereum/go-ethereum/eth/blockusage"
+	"github.com/ethereum/go-ethereum/eth/dbinspect"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/feecheck"
+	"github.com/ethereum/go-ethereum/eth/forkrehearsal"
+	"github.com/ethereum/go-ethereum/eth/gasgovernor"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
+	"github.com/ethereum/go-ethereum/eth/gasprofile"
+	"github.com/ethereum/go-ethereum/eth/inclusion"
+	"github.com/ethereum/go-ethereum/eth/noncegap"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
+	"github.com/ethereum/go-ethereum/eth/replicacheck"
+	"github.com/ethereum/go-ethereum/eth/rpccache"
+	"github.com/ethereum/go-ethereum/eth/txwal"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
@@ -80,6 +94,8 @@ type Ethereum struct {
 
 	seqRPCService        *rpc.Client
 	historicalRPCService *rpc.Client
+	ancientRPCService    *rpc.Client
+	feeCheckRPCService   *rpc.Client
 
 	// DB interfaces
 	chainDb ethdb.Database // Block chain database
@@ -88,9 +104,8 @@ type Ethereum struct {
 	engine         consensus.Engine
 	accountManager *accounts.Manager
 
-	bloomRequests     chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
-	bloomIndexer      *core.ChainIndexer             // Bloom indexer operating during block imports
-	closeBloomHandler chan struct{}
+	filterMaps     *filtermaps.FilterMaps // Log index serving the log filters
+	customIndexers []*core.CustomIndexer  // Compiled-in custom chain indexes enabled by the operator
 
 	APIBackend *EthAPIBackend
 
@@ -108,6 +123,35 @@ type Ethereum struct {
 	shutdownTracker *shutdowncheck.ShutdownTracker // Tracks if and when the node has shutdown ungracefully
 
 	nodeCloser func() error
+
+	feeChecker     *feecheck.Checker        // Optional fee parameter divergence checker
+	replicaChecker *replicacheck.Checker    // Optional consistency checker against the sequencer
+	responseCache  *rpccache.Cache          // Optional cache of RPC responses on immutable data
+	txWAL          *txwal.WAL               // Optional write-ahead log of the transactions accepted
+	inclusion      *inclusion.Monitor       // Optional monitor of the inclusion of the submitted transactions
+	nonceGaps      *noncegap.Monitor        // Optional monitor of the pooled transactions blocked by nonce gaps
+	rehearsal      *forkrehearsal.Rehearsal // Optional rehearsal of the block production ahead of forks
+	gasGovernor    *gasgovernor.Governor    // Optional controller of the gas ceiling on the block processing times
+	gasProfiler    *gasprofile.Profiler     // Optional sampling profiler of the gas used per contract
+	ancientChecker *ancientcheck.Checker    // Verifier and repairer of the ancient chain segments
+	dbInspector    *dbinspect.Inspector     // Background inspector of the database size
+	daResolver     *altda.Resolver          // Optional resolver of the alt-DA inputs of the payloads to import
+	blockUsage     *blockusage.Tracker      // Optional tracker of the resources used by the recent blocks
+
+	lastEngineUpdate atomic.Int64   // Unix nanoseconds of the last Engine API update, for health checks
+	sequencerHealth  sequencerProbe // Outcome of the last sequencer reachability probe, for readiness checks
+
+	gasCeil     uint64        // Gas ceiling currently targeted by the miner, protected by lock
+	runtimeLock sync.Mutex    // Serializes runtime configuration changes
+	runtimeQuit chan struct{} // Terminates the runtime configuration watcher
+	runtimeWg   sync.WaitGroup
+
+	freeze          chainFreeze     // Emergency stop switch halting block production
+	consensusClient consensusClient // Consensus client reported through the Engine API
+	forwards        forwardTracker  // Transactions forwarded to the sequencer
+
+	protocolVersions protocolVersions // Protocol versions signaled through the Engine API
+	replica          failoverReplica  // Failover replica mode, following the primary sequencer
 }
 
 // New creates a new Ethereum object (including the
@@ -164,21 +208,20 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		networkID = chainConfig.ChainID.Uint64()
 	}
 	eth := &Ethereum{
-		config:            config,
-		merger:            consensus.NewMerger(chainDb),
-		chainDb:           chainDb,
-		eventMux:          stack.EventMux(),
-		accountManager:    stack.AccountManager(),
-		engine:            engine,
-		closeBloomHandler: make(chan struct{}),
-		networkID:         networkID,
-		gasPrice:          config.Miner.GasPrice,
-		etherbase:         config.Miner.Etherbase,
-		bloomRequests:     make(chan chan *bloombits.Retrieval),
-		bloomIndexer:      core.NewBloomIndexer(chainDb, params.BloomBitsBlocks, params.BloomConfirms),
-		p2pServer:         stack.Server(),
-		shutdownTracker:   shutdowncheck.NewShutdownTracker(chainDb),
-		nodeCloser:        stack.Close,
+		config:          config,
+		merger:          consensus.NewMerger(chainDb),
+		chainDb:         chainDb,
+		eventMux:        stack.EventMux(),
+		accountManager:  stack.AccountManager(),
+		engine:          engine,
+		networkID:       networkID,
+		gasPrice:        config.Miner.GasPrice,
+		gasCeil:         config.Miner.GasCeil,
+		runtimeQuit:     make(chan struct{}),
+		etherbase:       config.Miner.Etherbase,
+		p2pServer:       stack.Server(),
+		shutdownTracker: shutdowncheck.NewShutdownTracker(chainDb),
+		nodeCloser:      stack.Close,
 	}
 	bcVersion := rawdb.ReadDatabaseVersion(chainDb)
 	var dbVer = "<nil>"
@@ -207,6 +250,8 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			TrieDirtyDisabled:   config.NoPruning,
 			TrieTimeLimit:       config.TrieTimeout,
 			SnapshotLimit:       config.SnapshotCache,
+			SnapshotGenRate:     config.SnapshotGenRate,
+			WarmCache:           config.RollupWarmCache,
 			Preimages:           config.Preimages,
 			StateHistory:        config.StateHistory,
 			StateScheme:         scheme,
@@ -223,6 +268,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if config.OverrideOptimismCanyon != nil {
 		overrides.OverrideOptimismCanyon = config.OverrideOptimismCanyon
 	}
+	if config.OverrideOasysOperatorFee != nil {
+		overrides.OverrideOasysOperatorFee = config.OverrideOasysOperatorFee
+	}
 	if config.OverrideOptimismInterop != nil {
 		overrides.OverrideOptimismInterop = config.OverrideOptimismInterop
 	}
@@ -231,6 +279,10 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if err != nil {
 		return nil, err
 	}
+	eth.blockchain.SetDepositCheck(config.RollupDepositCheck)
+	if config.TransactionSenderIndex {
+		eth.blockchain.EnableTxSenderIndex()
+	}
 	if chainConfig := eth.blockchain.Config(); chainConfig.Optimism != nil { // config.Genesis.Config.ChainID cannot be used because it's based on CLI flags only, thus default to mainnet L1
 		config.NetworkId = chainConfig.ChainID.Uint64() // optimism defaults eth network ID to chain ID
 		eth.networkID = config.NetworkId
@@ -241,7 +293,10 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		eth.merger.FinalizePoS()
 	}
 
-	eth.bloomIndexer.Start(eth.blockchain)
+	eth.filterMaps = filtermaps.New(chainDb, eth.blockchain, config.TransactionHistory)
+	if eth.customIndexers, err = core.NewCustomIndexers(chainDb, config.RollupIndexers); err != nil {
+		return nil, err
+	}
 
 	if config.BlobPool.Datadir != "" {
 		config.BlobPool.Datadir = stack.ResolvePath(config.BlobPool.Datadir)
@@ -251,12 +306,18 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
+	txJournal := shutdowncheck.ReadJournalStatus(config.TxPool.Journal)
 	legacyPool := legacypool.New(config.TxPool, eth.blockchain)
 
 	eth.txPool, err = txpool.New(new(big.Int).SetUint64(config.TxPool.PriceLimit), eth.blockchain, []txpool.SubPool{legacyPool, blobPool})
 	if err != nil {
 		return nil, err
 	}
+	if config.RollupTxWAL != "" {
+		if eth.txWAL, err = txwal.New(stack.ResolvePath(config.RollupTxWAL), eth.txPool); err != nil {
+			return nil, err
+		}
+	}
 	// Permit the downloader to use the trie cache allowance during fast sync
 	cacheLimit := cacheConfig.TrieCleanLimit + cacheConfig.TrieDirtyLimit + cacheConfig.SnapshotLimit
 	if eth.handler, err = newHandler(&handlerConfig{
@@ -270,14 +331,56 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		EventMux:       eth.eventMux,
 		RequiredBlocks: config.RequiredBlocks,
 		NoTxGossip:     config.RollupDisableTxPoolGossip,
+		BlockGossip:    config.RollupBlockGossip,
+		ConsensusClient: func() interface{} {
+			if info := eth.ConsensusClient(); info != nil {
+				return info
+			}
+			return nil
+		},
 	}); err != nil {
 		return nil, err
 	}
 
 	eth.miner = miner.New(eth, &config.Miner, eth.blockchain.Config(), eth.EventMux(), eth.engine, eth.isLocalBlock)
 	eth.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
+	if config.RollupDAServer != "" && len(config.RollupDATargets) > 0 {
+		client := altda.NewClient(config.RollupDAServer, config.RollupDATimeout)
+		eth.miner.SetDAChecker(altda.NewChecker(client, config.RollupDATargets))
+
+		if config.RollupDAResolve {
+			policy, err := altda.ParseFailurePolicy(config.RollupDAFailurePolicy)
+			if err != nil {
+				return nil, err
+			}
+			eth.daResolver = altda.NewResolver(client, config.RollupDATargets, policy)
+		}
+	}
 
-	eth.APIBackend = &EthAPIBackend{stack.Config().ExtRPCEnabled(), stack.Config().AllowUnprotectedTxs, config.RollupDisableTxPoolAdmission, eth, nil}
+	latestTag, err := rpc.ParseLatestBlockTag(config.RPCLatestBlockTag)
+	if err != nil {
+		return nil, err
+	}
+	var latestTags map[string]rpc.BlockNumber
+	if len(config.RPCLatestBlockTagNamespaces) > 0 {
+		latestTags = make(map[string]rpc.BlockNumber)
+		for namespace, tag := range config.RPCLatestBlockTagNamespaces {
+			if latestTags[namespace], err = rpc.ParseLatestBlockTag(tag); err != nil {
+				return nil, fmt.Errorf("namespace %q: %w", namespace, err)
+			}
+		}
+	}
+	eth.APIBackend = &EthAPIBackend{
+		extRPCEnabled:       stack.Config().ExtRPCEnabled(),
+		allowUnprotectedTxs: stack.Config().AllowUnprotectedTxs,
+		disableTxPool:       config.RollupDisableTxPoolAdmission,
+		eth:                 eth,
+		latestTag:           latestTag,
+		latestTags:          latestTags,
+	}
+	if eth.APIBackend.latestTag != rpc.LatestBlockNumber || len(eth.APIBackend.latestTags) > 0 {
+		log.Info("Remapped RPC latest block tag", "default", eth.APIBackend.latestTag, "namespaces", config.RPCLatestBlockTagNamespaces)
+	}
 	if eth.APIBackend.allowUnprotectedTxs {
 		log.Info("Unprotected transactions allowed")
 	}
@@ -298,6 +401,19 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		return nil, err
 	}
 
+	// A replica promoted before a restart keeps sequencing, instead of following
+	// its former primary again. Starting without replica mode drops the record.
+	if config.RollupReplicaMode {
+		if promoted := rawdb.ReadReplicaPromotion(chainDb); promoted != nil {
+			eth.replica.promoted = time.Unix(int64(*promoted), 0)
+			log.Warn("Replica promoted before restart, running as sequencer", "promoted", eth.replica.promoted, "primary", redactURL(config.RollupSequencerHTTP))
+
+			config.RollupReplicaMode, config.RollupReplicaCheck = false, false
+			config.RollupSequencerHTTP = ""
+		}
+	} else {
+		rawdb.DeleteReplicaPromotion(chainDb)
+	}
 	if config.RollupSequencerHTTP != "" {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		client, err := rpc.DialContext(ctx, config.RollupSequencerHTTP)
@@ -306,6 +422,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			return nil, err
 		}
 		eth.seqRPCService = client
+		eth.blockchain.SetBadBlockPeer(sequencerBadBlockPeer(eth.sequencerClient))
 	}
 
 	if config.RollupHistoricalRPC != "" {
@@ -317,17 +434,94 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		}
 		eth.historicalRPCService = client
 	}
+	if boundary := rawdb.ReadLegacyHistoryBoundary(chainDb); boundary != nil {
+		log.Info("Serving pre-bedrock history locally", "boundary", *boundary)
+	}
+
+	if config.RollupFeeCheck {
+		if config.RollupFeeCheckL1 == "" || config.RollupFeeCheckSystemConfig == (common.Address{}) {
+			return nil, errors.New("fee parameter check requires an L1 endpoint and the SystemConfig address")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		client, err := rpc.DialContext(ctx, config.RollupFeeCheckL1)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		eth.feeCheckRPCService = client
+
+		var halt func() error
+		if config.RollupFeeCheckHalt {
+			halt = eth.nodeCloser
+		}
+		eth.feeChecker = feecheck.New(eth.blockchain, feecheck.NewRPCSource(client, config.RollupFeeCheckSystemConfig), halt)
+	}
+	if config.RollupReplicaCheck {
+		if config.RollupSequencerHTTP == "" {
+			return nil, errors.New("replica consistency check requires a sequencer endpoint")
+		}
+		eth.replicaChecker = replicacheck.New(eth.blockchain, replicacheck.NewRPCRemote(eth.sequencerClient), config.RollupReplicaCheckInterval)
+	}
+	if config.RollupReplicaMode {
+		if config.RollupSequencerHTTP == "" {
+			return nil, errors.New("replica mode requires a primary sequencer endpoint")
+		}
+		eth.replica.enabled = true
+	}
+	if config.RollupInclusionMonitor > 0 {
+		eth.inclusion = inclusion.New(eth.blockchain, config.RollupInclusionMonitor)
+	}
+	if config.RollupBlockUsageWindow > 0 {
+		eth.blockUsage = blockusage.New(eth.blockchain, int(config.RollupBlockUsageWindow))
+	}
+	if config.RollupNonceGapThreshold > 0 {
+		eth.nonceGaps = noncegap.New(eth.txPool, config.RollupNonceGapThreshold, config.RollupNonceGapEvict)
+	}
+	if config.RollupForkRehearsalWindow > 0 {
+		eth.rehearsal = forkrehearsal.New(eth.blockchain, eth.miner, config.RollupForkRehearsalWindow, config.RollupForkRehearsalInterval)
+	}
+	if config.RollupGasGovernorTarget > 0 {
+		eth.gasGovernor = gasgovernor.New(eth.blockchain, governedGasCeil{eth}, gasgovernor.Config{
+			Target: config.RollupGasGovernorTarget,
+			Min:    config.RollupGasGovernorMin,
+			Max:    config.RollupGasGovernorMax,
+			Window: gasgovernor.DefaultConfig.Window,
+		}, config.Miner.GasCeil)
+	}
+	if config.RollupGasProfileRate > 0 {
+		eth.gasProfiler = gasprofile.New(eth.blockchain, config.RollupGasProfileRate)
+	}
+	ancientSources := []ancientcheck.Source{&peerAncientSource{peers: eth.handler.peers}}
+	if config.RollupAncientCheckEndpoint != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		client, err := rpc.DialContext(ctx, config.RollupAncientCheckEndpoint)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		eth.ancientRPCService = client
+		ancientSources = append(ancientSources, ancientcheck.NewRPCSource(client, "archive"))
+	}
+	eth.ancientChecker = ancientcheck.New(chainDb, ancientSources, config.RollupAncientCheckInterval)
+	eth.dbInspector = dbinspect.New(chainDb)
+	if config.RPCCacheSize > 0 {
+		eth.responseCache = rpccache.New(eth.blockchain, config.RPCCacheSize*1024*1024, config.RPCCacheTTL)
+		stack.RegisterResponseCache(eth.responseCache)
+	}
 
 	// Start the RPC service
 	eth.netRPCService = ethapi.NewNetAPI(eth.p2pServer, networkID)
 
 	// Register the backend on the node
 	stack.RegisterAPIs(eth.APIs())
+	stack.RegisterHandler("Health check", "/healthz", &healthHandler{eth: eth})
+	stack.RegisterHandler("Readiness check", "/readyz", &healthHandler{eth: eth, readiness: true})
 	stack.RegisterProtocols(eth.Protocols())
 	stack.RegisterLifecycle(eth)
 
 	// Successful startup; push a marker and check previous unclean shutdowns.
 	eth.shutdownTracker.MarkStartup()
+	eth.shutdownTracker.InspectRecovery(eth.blockchain, txJournal)
 
 	return eth, nil
 }
@@ -357,6 +551,12 @@ func (s *Ethereum) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
+	// Append any APIs exposed by the custom chain indexes
+	for _, indexer := range s.customIndexers {
+		if backend, ok := indexer.Backend.(interface{ APIs() []rpc.API }); ok {
+			apis = append(apis, backend.APIs()...)
+		}
+	}
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -377,6 +577,13 @@ func (s *Ethereum) APIs() []rpc.API {
 		}, {
 			Namespace: "net",
 			Service:   s.netRPCService,
+		}, {
+			Namespace: "oasys",
+			Service:   NewOasysAPI(s),
+		}, {
+			Namespace:     "eth",
+			Service:       NewSnapshotAPI(s),
+			Authenticated: true,
 		},
 	}...)
 }
@@ -530,13 +737,19 @@ func (s *Ethereum) Downloader() *downloader.Downloader { return s.handler.downlo
 func (s *Ethereum) Synced() bool                       { return s.handler.synced.Load() }
 func (s *Ethereum) SetSynced()                         { s.handler.enableSyncedFeatures() }
 func (s *Ethereum) ArchiveMode() bool                  { return s.config.NoPruning }
-func (s *Ethereum) BloomIndexer() *core.ChainIndexer   { return s.bloomIndexer }
 func (s *Ethereum) Merger() *consensus.Merger          { return s.merger }
+func (s *Ethereum) DAResolver() *altda.Resolver        { return s.daResolver }
 func (s *Ethereum) SyncMode() downloader.SyncMode {
 	mode, _ := s.handler.chainSync.modeAndLocalHead()
 	return mode
 }
 
+// GossipPayloadBlock propagates a locally built payload to the eth peers, if
+// block gossip is enabled.
+func (s *Ethereum) GossipPayloadBlock(block *types.Block) {
+	s.handler.GossipPayloadBlock(block)
+}
+
 // Protocols returns all the currently configured
 // network protocols to start.
 func (s *Ethereum) Protocols() []p2p.Protocol {
@@ -552,12 +765,61 @@ func (s *Ethereum) Protocols() []p2p.Protocol {
 func (s *Ethereum) Start() error {
 	eth.StartENRUpdater(s.blockchain, s.p2pServer.LocalNode())
 
-	// Start the bloom bits servicing goroutines
-	s.startBloomHandlers(params.BloomBitsBlocks)
+	// Start maintaining the log index
+	s.filterMaps.Start()
+	for _, indexer := range s.customIndexers {
+		indexer.Start(s.blockchain)
+	}
 
 	// Regularly update shutdown marker
 	s.shutdownTracker.Start()
 
+	if s.feeChecker != nil {
+		s.feeChecker.Start()
+	}
+	if s.replicaChecker != nil {
+		s.replicaChecker.Start()
+	}
+	if s.txWAL != nil {
+		s.txWAL.Start()
+	}
+	if s.inclusion != nil {
+		s.inclusion.Start()
+	}
+	if s.blockUsage != nil {
+		s.blockUsage.Start()
+	}
+	if s.nonceGaps != nil {
+		s.nonceGaps.Start()
+	}
+	if s.rehearsal != nil {
+		s.rehearsal.Start()
+	}
+	if s.gasGovernor != nil {
+		s.gasGovernor.Start()
+	}
+	if s.gasProfiler != nil {
+		s.gasProfiler.Start()
+	}
+	if s.config.RollupAncientCheckInterval > 0 {
+		s.ancientChecker.Start()
+	}
+	if s.responseCache != nil {
+		s.responseCache.Start()
+	}
+	if s.config.RollupRuntimeConfig != "" {
+		s.runtimeWg.Add(1)
+		go s.runtimeConfigLoop(s.config.RollupRuntimeConfig)
+	}
+	if s.config.RollupFreezeMarker != "" {
+		s.runtimeWg.Add(1)
+		go s.freezeMarkerLoop(s.config.RollupFreezeMarker)
+	}
+	if s.Replica() {
+		s.runtimeWg.Add(1)
+		go s.replicaLoop()
+	}
+
 	// Figure out a max peers count based on the server limits
 	maxPeers := s.p2pServer.MaxPeers
 	if s.config.LightServ > 0 {
@@ -580,10 +842,49 @@ func (s *Ethereum) Stop() error {
 	s.handler.Stop()
 
 	// Then stop everything else.
-	s.bloomIndexer.Close()
-	close(s.closeBloomHandler)
+	s.stopHalt()
+	close(s.runtimeQuit)
+	s.runtimeWg.Wait()
+	s.filterMaps.Stop()
+	for _, indexer := range s.customIndexers {
+		indexer.Close()
+	}
+	if s.txWAL != nil {
+		s.txWAL.Stop()
+	}
 	s.txPool.Close()
 	s.miner.Close()
+	if s.feeChecker != nil {
+		s.feeChecker.Stop()
+	}
+	if s.replicaChecker != nil {
+		s.replicaChecker.Stop()
+	}
+	if s.inclusion != nil {
+		s.inclusion.Stop()
+	}
+	if s.blockUsage != nil {
+		s.blockUsage.Stop()
+	}
+	if s.nonceGaps != nil {
+		s.nonceGaps.Stop()
+	}
+	if s.rehearsal != nil {
+		s.rehearsal.Stop()
+	}
+	if s.gasGovernor != nil {
+		s.gasGovernor.Stop()
+	}
+	if s.gasProfiler != nil {
+		s.gasProfiler.Stop()
+	}
+	if s.config.RollupAncientCheckInterval > 0 {
+		s.ancientChecker.Stop()
+	}
+	if s.responseCache != nil {
+		s.responseCache.Stop()
+	}
+	s.dbInspector.Stop()
 	s.blockchain.Stop()
 	s.engine.Close()
 	if s.seqRPCService != nil {
@@ -592,6 +893,12 @@ func (s *Ethereum) Stop() error {
 	if s.historicalRPCService != nil {
 		s.historicalRPCService.Close()
 	}
+	if s.ancientRPCService != nil {
+		s.ancientRPCService.Close()
+	}
+	if s.feeCheckRPCService != nil {
+		s.feeCheckRPCService.Close()
+	}
 
 	// Clean shutdown marker as the last thing before closing db
 	s.shutdownTracker.Stop()
@@ -627,7 +934,7 @@ func (s *Ethereum) HandleRequiredProtocolVersion(required params.ProtocolVersion
 	}
 	if haveLevel >= needLevel { // halt if we opted in to do so at this granularity
 		log.Error("Opted to halt, unprepared for protocol change", "required", required, "local", params.OPStackSupport)
-		return s.nodeCloser()
+		return s.haltOnProtocolVersion(required)
 	}
 	return nil
 }
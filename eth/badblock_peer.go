@@ -0,0 +1,54 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// sequencerBadBlockPeer compares the bad blocks with the sequencer, querying its
+// receipts and its post state proofs.
+type sequencerBadBlockPeer func() *rpc.Client
+
+func (p sequencerBadBlockPeer) BlockReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	client := p()
+	if client == nil {
+		return nil, errors.New("no sequencer endpoint")
+	}
+	var receipts types.Receipts
+	if err := client.CallContext(ctx, &receipts, "eth_getBlockReceipts", rpc.BlockNumberOrHashWithHash(hash, true)); err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+func (p sequencerBadBlockPeer) Account(ctx context.Context, addr common.Address, hash common.Hash) (*types.StateAccount, error) {
+	client := p()
+	if client == nil {
+		return nil, errors.New("no sequencer endpoint")
+	}
+	var proof struct {
+		Balance     *hexutil.Big   `json:"balance"`
+		Nonce       hexutil.Uint64 `json:"nonce"`
+		StorageHash common.Hash    `json:"storageHash"`
+		CodeHash    common.Hash    `json:"codeHash"`
+	}
+	if err := client.CallContext(ctx, &proof, "eth_getProof", addr, []string{}, rpc.BlockNumberOrHashWithHash(hash, true)); err != nil {
+		return nil, err
+	}
+	balance := new(big.Int)
+	if proof.Balance != nil {
+		balance = proof.Balance.ToInt()
+	}
+	return &types.StateAccount{
+		Nonce:    uint64(proof.Nonce),
+		Balance:  balance,
+		Root:     proof.StorageHash,
+		CodeHash: proof.CodeHash.Bytes(),
+	}, nil
+}
@@ -0,0 +1,161 @@
+// Package blockusage implements a tracker of the resources used by the import
+// of the recent canonical blocks, reported over a rolling window to power the
+// operator dashboards without external tracing.
+package blockusage
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// chainHeadChanSize is the size of channel listening to ChainHeadEvent.
+const chainHeadChanSize = 64
+
+// BlockChain defines the minimal set of methods needed to back the tracker.
+type BlockChain interface {
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+	BlockUsage(hash common.Hash) *core.BlockUsage
+	GetHeader(hash common.Hash, number uint64) *types.Header
+}
+
+// Report summarizes the resources used by the most recent canonical blocks, the
+// oldest first. The blocks not imported by the node since it started, e.g.
+// snap synced, are missing.
+type Report struct {
+	Blocks        []*core.BlockUsage `json:"blocks"`
+	GasUsed       uint64             `json:"gasUsed"`
+	DABytes       uint64             `json:"daBytes"`
+	ExecTimeUs    int64              `json:"execTimeUs"`
+	MaxExecTimeUs int64              `json:"maxExecTimeUs"`
+	StateBytes    uint64             `json:"stateBytes"`
+}
+
+// Tracker keeps the resource usage of the last window canonical blocks.
+type Tracker struct {
+	chain  BlockChain
+	window int
+
+	lock   sync.RWMutex
+	blocks []*core.BlockUsage // Usage of the recent canonical blocks, the oldest first
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a tracker of the resource usage of the last window canonical blocks.
+func New(chain BlockChain, window int) *Tracker {
+	return &Tracker{
+		chain:  chain,
+		window: window,
+		quit:   make(chan struct{}),
+	}
+}
+
+// Start launches the background loop following the chain head.
+func (t *Tracker) Start() {
+	t.wg.Add(1)
+	go t.loop()
+}
+
+// Stop terminates the background loop.
+func (t *Tracker) Stop() {
+	close(t.quit)
+	t.wg.Wait()
+}
+
+// Report returns the resource usage of the given number of most recent blocks,
+// all those tracked if zero.
+func (t *Tracker) Report(count int) *Report {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	blocks := t.blocks
+	if count > 0 && count < len(blocks) {
+		blocks = blocks[len(blocks)-count:]
+	}
+	report := &Report{Blocks: make([]*core.BlockUsage, len(blocks))}
+	for i, usage := range blocks {
+		report.Blocks[i] = usage
+		report.GasUsed += usage.GasUsed
+		report.DABytes += usage.DABytes
+		report.ExecTimeUs += usage.ExecTimeUs
+		report.StateBytes += usage.StateBytes
+		if usage.ExecTimeUs > report.MaxExecTimeUs {
+			report.MaxExecTimeUs = usage.ExecTimeUs
+		}
+	}
+	return report
+}
+
+func (t *Tracker) loop() {
+	defer t.wg.Done()
+
+	heads := make(chan core.ChainHeadEvent, chainHeadChanSize)
+	sub := t.chain.SubscribeChainHeadEvent(heads)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-heads:
+			t.update(ev)
+		case <-sub.Err():
+			return
+		case <-t.quit:
+			return
+		}
+	}
+}
+
+// update records the usage of a new head, along with the one of its ancestors
+// imported with it, e.g. in a batch or a reorg.
+func (t *Tracker) update(ev core.ChainHeadEvent) {
+	usage := ev.Usage
+	if usage == nil {
+		usage = t.chain.BlockUsage(ev.Block.Hash())
+	}
+	if usage == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	// Walk back to the last tracked block still canonical, at most a window away
+	added := []*core.BlockUsage{usage}
+	for header := ev.Block.Header(); len(added) < t.window && header.Number.Sign() > 0; {
+		if t.tracked(header.ParentHash, header.Number.Uint64()-1) {
+			break
+		}
+		parent := t.chain.BlockUsage(header.ParentHash)
+		if parent == nil {
+			break
+		}
+		added = append(added, parent)
+		if header = t.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1); header == nil {
+			break
+		}
+	}
+	// Drop the tracked blocks replaced, then append the new ones in order
+	first := added[len(added)-1].Number
+	for len(t.blocks) > 0 && t.blocks[len(t.blocks)-1].Number >= first {
+		t.blocks = t.blocks[:len(t.blocks)-1]
+	}
+	for i := len(added) - 1; i >= 0; i-- {
+		t.blocks = append(t.blocks, added[i])
+	}
+	if len(t.blocks) > t.window {
+		t.blocks = append(t.blocks[:0:0], t.blocks[len(t.blocks)-t.window:]...)
+	}
+}
+
+// tracked reports whether the given block is the last one tracked.
+func (t *Tracker) tracked(hash common.Hash, number uint64) bool {
+	if len(t.blocks) == 0 {
+		return false
+	}
+	last := t.blocks[len(t.blocks)-1]
+	return last.Number == number && last.Hash == hash
+}
@@ -0,0 +1,91 @@
+package blockusage
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+type testChain struct {
+	headers map[common.Hash]*types.Header
+	usages  map[common.Hash]*core.BlockUsage
+}
+
+func (c *testChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return nil
+}
+
+func (c *testChain) BlockUsage(hash common.Hash) *core.BlockUsage {
+	return c.usages[hash]
+}
+
+func (c *testChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	return c.headers[hash]
+}
+
+// importBlock adds a block imported on top of the given parent, using the given gas.
+func (c *testChain) importBlock(parent *types.Block, gas uint64) *types.Block {
+	header := &types.Header{Number: big.NewInt(1), GasUsed: gas}
+	if parent != nil {
+		header.ParentHash = parent.Hash()
+		header.Number = new(big.Int).Add(parent.Number(), common.Big1)
+	}
+	block := types.NewBlockWithHeader(header)
+	c.headers[block.Hash()] = block.Header()
+	c.usages[block.Hash()] = &core.BlockUsage{Number: block.NumberU64(), Hash: block.Hash(), GasUsed: gas, ExecTimeUs: int64(gas)}
+	return block
+}
+
+func TestTracker(t *testing.T) {
+	chain := &testChain{
+		headers: make(map[common.Hash]*types.Header),
+		usages:  make(map[common.Hash]*core.BlockUsage),
+	}
+	tracker := New(chain, 3)
+
+	check := func(want ...uint64) {
+		t.Helper()
+		report := tracker.Report(0)
+		if len(report.Blocks) != len(want) {
+			t.Fatalf("tracked block count mismatch: have %d, want %d", len(report.Blocks), len(want))
+		}
+		var total uint64
+		for i, usage := range report.Blocks {
+			if usage.GasUsed != want[i] {
+				t.Fatalf("block %d gas mismatch: have %d, want %d", i, usage.GasUsed, want[i])
+			}
+			total += want[i]
+		}
+		if report.GasUsed != total {
+			t.Fatalf("total gas mismatch: have %d, want %d", report.GasUsed, total)
+		}
+	}
+	// Heads imported one by one
+	b1 := chain.importBlock(nil, 1)
+	b2 := chain.importBlock(b1, 2)
+	tracker.update(core.ChainHeadEvent{Block: b1, Usage: chain.usages[b1.Hash()]})
+	tracker.update(core.ChainHeadEvent{Block: b2, Usage: chain.usages[b2.Hash()]})
+	check(1, 2)
+
+	// Heads imported in a batch, the oldest falling out of the window
+	b3 := chain.importBlock(b2, 3)
+	b4 := chain.importBlock(b3, 4)
+	tracker.update(core.ChainHeadEvent{Block: b4, Usage: chain.usages[b4.Hash()]})
+	check(2, 3, 4)
+
+	// Reorg replacing the last head
+	b4b := chain.importBlock(b3, 40)
+	tracker.update(core.ChainHeadEvent{Block: b4b})
+	check(2, 3, 40)
+
+	if report := tracker.Report(2); len(report.Blocks) != 2 || report.MaxExecTimeUs != 40 || report.GasUsed != 43 {
+		t.Fatalf("partial report mismatch: have %d blocks, %d gas, %dus max", len(report.Blocks), report.GasUsed, report.MaxExecTimeUs)
+	}
+	// Heads not imported locally are skipped
+	tracker.update(core.ChainHeadEvent{Block: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(5), ParentHash: b4b.Hash()})})
+	check(2, 3, 40)
+}
@@ -18,6 +18,7 @@
 package catalyst
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
@@ -30,10 +31,12 @@ import (
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/altda"
 	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/internal/version"
 	"github.com/ethereum/go-ethereum/log"
-	"github.com/ethereum/go-ethereum/miner"
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -88,6 +91,8 @@ var caps = []string{
 	"engine_newPayloadV3",
 	"engine_getPayloadBodiesByHashV1",
 	"engine_getPayloadBodiesByRangeV1",
+	"engine_getClientVersionV1",
+	"engine_deposeBlockV1",
 }
 
 type ConsensusAPI struct {
@@ -171,7 +176,7 @@ func newConsensusAPIWithoutHeartbeat(eth *eth.Ethereum) *ConsensusAPI {
 //
 // If there are payloadAttributes: we try to assemble a block with the payloadAttributes
 // and return its payloadID.
-func (api *ConsensusAPI) ForkchoiceUpdatedV1(update engine.ForkchoiceStateV1, payloadAttributes *engine.PayloadAttributes) (engine.ForkChoiceResponse, error) {
+func (api *ConsensusAPI) ForkchoiceUpdatedV1(ctx context.Context, update engine.ForkchoiceStateV1, payloadAttributes *engine.PayloadAttributes) (engine.ForkChoiceResponse, error) {
 	if payloadAttributes != nil {
 		if payloadAttributes.Withdrawals != nil {
 			return engine.STATUS_INVALID, engine.InvalidParams.With(errors.New("withdrawals not supported in V1"))
@@ -179,28 +184,31 @@ func (api *ConsensusAPI) ForkchoiceUpdatedV1(update engine.ForkchoiceStateV1, pa
 		if api.eth.BlockChain().Config().IsShanghai(api.eth.BlockChain().Config().LondonBlock, payloadAttributes.Timestamp) {
 			return engine.STATUS_INVALID, engine.InvalidParams.With(errors.New("forkChoiceUpdateV1 called post-shanghai"))
 		}
+		if err := checkJovianVersion(api.eth.BlockChain().Config(), "forkChoiceUpdate", 1, payloadAttributes.Timestamp); err != nil {
+			return engine.STATUS_INVALID, err
+		}
 	}
-	return api.forkchoiceUpdated(update, payloadAttributes)
+	return api.forkchoiceUpdated(ctx, update, payloadAttributes)
 }
 
 // ForkchoiceUpdatedV2 is equivalent to V1 with the addition of withdrawals in the payload attributes.
-func (api *ConsensusAPI) ForkchoiceUpdatedV2(update engine.ForkchoiceStateV1, payloadAttributes *engine.PayloadAttributes) (engine.ForkChoiceResponse, error) {
+func (api *ConsensusAPI) ForkchoiceUpdatedV2(ctx context.Context, update engine.ForkchoiceStateV1, payloadAttributes *engine.PayloadAttributes) (engine.ForkChoiceResponse, error) {
 	if payloadAttributes != nil {
 		if err := api.verifyPayloadAttributes(payloadAttributes); err != nil {
 			return engine.STATUS_INVALID, engine.InvalidParams.With(err)
 		}
 	}
-	return api.forkchoiceUpdated(update, payloadAttributes)
+	return api.forkchoiceUpdated(ctx, update, payloadAttributes)
 }
 
 // ForkchoiceUpdatedV3 is equivalent to V2 with the addition of parent beacon block root in the payload attributes.
-func (api *ConsensusAPI) ForkchoiceUpdatedV3(update engine.ForkchoiceStateV1, payloadAttributes *engine.PayloadAttributes) (engine.ForkChoiceResponse, error) {
+func (api *ConsensusAPI) ForkchoiceUpdatedV3(ctx context.Context, update engine.ForkchoiceStateV1, payloadAttributes *engine.PayloadAttributes) (engine.ForkChoiceResponse, error) {
 	if payloadAttributes != nil {
 		if err := api.verifyPayloadAttributes(payloadAttributes); err != nil {
 			return engine.STATUS_INVALID, engine.InvalidParams.With(err)
 		}
 	}
-	return api.forkchoiceUpdated(update, payloadAttributes)
+	return api.forkchoiceUpdated(ctx, update, payloadAttributes)
 }
 
 func (api *ConsensusAPI) verifyPayloadAttributes(attr *engine.PayloadAttributes) error {
@@ -214,7 +222,7 @@ func (api *ConsensusAPI) verifyPayloadAttributes(attr *engine.PayloadAttributes)
 	if err := checkAttribute(c.IsCancun, attr.BeaconRoot != nil, c.LondonBlock, attr.Timestamp); err != nil {
 		return fmt.Errorf("invalid parent beacon block root: %w", err)
 	}
-	return nil
+	return verifyJovianAttributes(c, attr)
 }
 
 func checkAttribute(active func(*big.Int, uint64) bool, exists bool, block *big.Int, time uint64) error {
@@ -227,7 +235,7 @@ func checkAttribute(active func(*big.Int, uint64) bool, exists bool, block *big.
 	return nil
 }
 
-func (api *ConsensusAPI) forkchoiceUpdated(update engine.ForkchoiceStateV1, payloadAttributes *engine.PayloadAttributes) (engine.ForkChoiceResponse, error) {
+func (api *ConsensusAPI) forkchoiceUpdated(ctx context.Context, update engine.ForkchoiceStateV1, payloadAttributes *engine.PayloadAttributes) (engine.ForkChoiceResponse, error) {
 	api.forkchoiceLock.Lock()
 	defer api.forkchoiceLock.Unlock()
 
@@ -239,8 +247,24 @@ func (api *ConsensusAPI) forkchoiceUpdated(update engine.ForkchoiceStateV1, payl
 	// Stash away the last update to warn the user if the beacon client goes offline
 	api.lastForkchoiceLock.Lock()
 	api.lastForkchoiceUpdate = time.Now()
+	api.eth.MarkEngineUpdate()
 	api.lastForkchoiceLock.Unlock()
 
+	// Refuse any chain progression while the emergency stop is active
+	if api.eth.Frozen() {
+		log.Warn("Refusing forkchoice update, chain is frozen", "head", update.HeadBlockHash)
+		if payloadAttributes != nil {
+			return engine.STATUS_SYNCING, engine.ChainFrozen
+		}
+		return engine.STATUS_SYNCING, nil
+	}
+	// Replicas derive blocks from L1 like any verifier, but must not sequence
+	// transactions from their pool until promoted
+	if payloadAttributes != nil && !payloadAttributes.NoTxPool && api.eth.Replica() {
+		log.Warn("Refusing payload build from transaction pool, node is a replica", "head", update.HeadBlockHash)
+		return engine.STATUS_SYNCING, engine.ReplicaMode
+	}
+
 	// Check whether we have the block yet in our database or not. If not, we'll
 	// need to either trigger a sync, or to reject this forkchoice update for a
 	// reason.
@@ -367,24 +391,14 @@ func (api *ConsensusAPI) forkchoiceUpdated(update engine.ForkchoiceStateV1, payl
 		if api.eth.BlockChain().Config().Optimism != nil && payloadAttributes.GasLimit == nil {
 			return engine.STATUS_INVALID, engine.InvalidPayloadAttributes.With(errors.New("gasLimit parameter is required"))
 		}
-		transactions := make(types.Transactions, 0, len(payloadAttributes.Transactions))
-		for i, otx := range payloadAttributes.Transactions {
-			var tx types.Transaction
-			if err := tx.UnmarshalBinary(otx); err != nil {
-				return engine.STATUS_INVALID, fmt.Errorf("transaction %d is not valid: %v", i, err)
-			}
-			transactions = append(transactions, &tx)
-		}
-		args := &miner.BuildPayloadArgs{
-			Parent:       update.HeadBlockHash,
-			Timestamp:    payloadAttributes.Timestamp,
-			FeeRecipient: payloadAttributes.SuggestedFeeRecipient,
-			Random:       payloadAttributes.Random,
-			Withdrawals:  payloadAttributes.Withdrawals,
-			BeaconRoot:   payloadAttributes.BeaconRoot,
-			NoTxPool:     payloadAttributes.NoTxPool,
-			Transactions: transactions,
-			GasLimit:     payloadAttributes.GasLimit,
+		recipient, err := api.eth.FeeRecipient(block.NumberU64()+1, payloadAttributes.SuggestedFeeRecipient)
+		if err != nil {
+			return engine.STATUS_INVALID, engine.InvalidPayloadAttributes.With(err)
+		}
+		payloadAttributes.SuggestedFeeRecipient = recipient
+		args, err := buildPayloadArgs(update.HeadBlockHash, payloadAttributes)
+		if err != nil {
+			return engine.STATUS_INVALID, err
 		}
 		id := args.Id()
 		// If we already are busy generating this work, then we do not need
@@ -392,7 +406,7 @@ func (api *ConsensusAPI) forkchoiceUpdated(update engine.ForkchoiceStateV1, payl
 		if api.localBlocks.has(id) {
 			return valid(&id), nil
 		}
-		payload, err := api.eth.Miner().BuildPayload(args)
+		payload, err := api.eth.Miner().BuildPayload(ctx, args)
 		if err != nil {
 			log.Error("Failed to build payload", "err", err)
 			return valid(nil), engine.InvalidPayloadAttributes.With(err)
@@ -458,6 +472,9 @@ func (api *ConsensusAPI) getPayload(payloadID engine.PayloadID, full bool) (*eng
 	if data == nil {
 		return nil, engine.UnknownPayload
 	}
+	if block := api.localBlocks.getBlock(payloadID); block != nil && block.Hash() == data.ExecutionPayload.BlockHash {
+		api.eth.GossipPayloadBlock(block)
+	}
 	return data, nil
 }
 
@@ -466,6 +483,9 @@ func (api *ConsensusAPI) NewPayloadV1(params engine.ExecutableData) (engine.Payl
 	if params.Withdrawals != nil {
 		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(errors.New("withdrawals not supported in V1"))
 	}
+	if err := checkJovianVersion(api.eth.BlockChain().Config(), "newPayload", 1, params.Timestamp); err != nil {
+		return engine.PayloadStatusV1{Status: engine.INVALID}, err
+	}
 	return api.newPayload(params, nil, nil)
 }
 
@@ -532,8 +552,14 @@ func (api *ConsensusAPI) newPayload(params engine.ExecutableData, versionedHashe
 	// Stash away the last update to warn the user if the beacon client goes offline
 	api.lastNewPayloadLock.Lock()
 	api.lastNewPayloadUpdate = time.Now()
+	api.eth.MarkEngineUpdate()
 	api.lastNewPayloadLock.Unlock()
 
+	if api.eth.Frozen() {
+		log.Warn("Refusing new payload, chain is frozen", "number", params.Number, "hash", params.BlockHash)
+		return engine.PayloadStatusV1{Status: engine.SYNCING}, nil
+	}
+
 	// If we already have the block locally, ignore the entire execution and just
 	// return a fake success.
 	if block := api.eth.BlockChain().GetBlockByHash(params.BlockHash); block != nil {
@@ -555,6 +581,10 @@ func (api *ConsensusAPI) newPayload(params engine.ExecutableData, versionedHashe
 	if parent == nil {
 		return api.delayPayloadImport(block)
 	}
+	if err := verifyJovianPayload(api.eth.BlockChain().Config(), block); err != nil {
+		log.Warn("Invalid NewPayload for Jovian", "number", params.Number, "hash", params.BlockHash, "error", err)
+		return api.invalid(err, parent.Header()), nil
+	}
 	// We have an existing parent, do some sanity checks to avoid the beacon client
 	// triggering too early
 	var (
@@ -586,6 +616,17 @@ func (api *ConsensusAPI) newPayload(params engine.ExecutableData, versionedHashe
 		log.Warn("State not available, ignoring new payload")
 		return engine.PayloadStatusV1{Status: engine.ACCEPTED}, nil
 	}
+	// Resolve the alt-DA inputs the payload commits to before executing it
+	if resolver := api.eth.DAResolver(); resolver != nil {
+		if err := resolver.ResolveBlock(block); err != nil {
+			if resolver.Policy() == altda.FailureReject {
+				log.Warn("Rejecting payload with unresolved alt-DA input", "number", params.Number, "hash", params.BlockHash, "error", err)
+				return api.invalid(err, parent.Header()), nil
+			}
+			log.Warn("Deferring payload with unresolved alt-DA input", "number", params.Number, "hash", params.BlockHash, "error", err)
+			return engine.PayloadStatusV1{Status: engine.SYNCING}, nil
+		}
+	}
 	log.Trace("Inserting block without sethead", "hash", block.Hash(), "number", block.Number)
 	if err := api.eth.BlockChain().InsertBlockWithoutSetHead(block); err != nil {
 		log.Warn("NewPayloadV1: inserting block failed", "error", err)
@@ -787,10 +828,31 @@ func (api *ConsensusAPI) heartbeat() {
 }
 
 // ExchangeCapabilities returns the current methods provided by this node.
-func (api *ConsensusAPI) ExchangeCapabilities([]string) []string {
+func (api *ConsensusAPI) ExchangeCapabilities(remote []string) []string {
+	api.eth.SetConsensusClientCapabilities(remote)
 	return caps
 }
 
+// GetClientVersionV1 exchanges client version data of this node, recording the
+// version of the consensus client driving it.
+func (api *ConsensusAPI) GetClientVersionV1(info engine.ClientVersionV1) []engine.ClientVersionV1 {
+	log.Trace("Engine API request received", "method", "GetClientVersionV1", "info", info.String())
+	api.eth.SetConsensusClientVersion(info)
+
+	commit := "00000000"
+	if vcs, ok := version.VCS(); ok && len(vcs.Commit) >= 8 {
+		commit = vcs.Commit[:8]
+	}
+	return []engine.ClientVersionV1{
+		{
+			Code:    engine.ClientCode,
+			Name:    engine.ClientName,
+			Version: params.VersionWithMeta,
+			Commit:  commit,
+		},
+	}
+}
+
 // GetPayloadBodiesByHashV1 implements engine_getPayloadBodiesByHashV1 which allows for retrieval of a list
 // of block bodies by the engine api.
 func (api *ConsensusAPI) GetPayloadBodiesByHashV1(hashes []common.Hash) []*engine.ExecutionPayloadBodyV1 {
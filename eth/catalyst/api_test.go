@@ -171,7 +171,7 @@ func TestSetHeadBeforeTotalDifficulty(t *testing.T) {
 		SafeBlockHash:      common.Hash{},
 		FinalizedBlockHash: common.Hash{},
 	}
-	if resp, err := api.ForkchoiceUpdatedV1(fcState, nil); err != nil {
+	if resp, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, nil); err != nil {
 		t.Errorf("fork choice updated should not error: %v", err)
 	} else if resp.PayloadStatus.Status != engine.INVALID_TERMINAL_BLOCK.Status {
 		t.Errorf("fork choice updated before total terminal difficulty should be INVALID")
@@ -198,7 +198,7 @@ func TestEth2PrepareAndGetPayload(t *testing.T) {
 		SafeBlockHash:      common.Hash{},
 		FinalizedBlockHash: common.Hash{},
 	}
-	_, err := api.ForkchoiceUpdatedV1(fcState, &blockParams)
+	_, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, &blockParams)
 	if err != nil {
 		t.Fatalf("error preparing payload, err=%v", err)
 	}
@@ -228,6 +228,77 @@ func TestEth2PrepareAndGetPayload(t *testing.T) {
 	}
 }
 
+func TestListPayloads(t *testing.T) {
+	genesis, blocks := generateMergeChain(10, false)
+	n, ethservice := startEthService(t, genesis, blocks)
+	defer n.Close()
+
+	api := NewConsensusAPI(ethservice)
+
+	parent := ethservice.BlockChain().CurrentBlock()
+	blockParams := engine.PayloadAttributes{
+		Timestamp: parent.Time + 5,
+		NoTxPool:  true,
+	}
+	expected, err := api.ComputePayloadIdV1(parent.Hash(), blockParams)
+	if err != nil {
+		t.Fatalf("error computing payload id: %v", err)
+	}
+	fcState := engine.ForkchoiceStateV1{HeadBlockHash: parent.Hash()}
+	resp, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, &blockParams)
+	if err != nil {
+		t.Fatalf("error preparing payload, err=%v", err)
+	}
+	if resp.PayloadID == nil || *resp.PayloadID != expected {
+		t.Fatalf("payload id mismatch: have %v, want %v", resp.PayloadID, expected)
+	}
+	payloads := api.ListPayloadsV1()
+	if len(payloads) != 1 {
+		t.Fatalf("payload count mismatch: have %d, want 1", len(payloads))
+	}
+	if info := payloads[0]; info.ID != expected || info.Parent != parent.Hash() || uint64(info.Timestamp) != blockParams.Timestamp || !info.NoTxPool {
+		t.Fatalf("payload info mismatch: %+v", info)
+	}
+}
+
+func TestClearCaches(t *testing.T) {
+	genesis, blocks := generateMergeChain(10, false)
+	n, ethservice := startEthService(t, genesis, blocks)
+	defer n.Close()
+
+	api := NewConsensusAPI(ethservice)
+
+	// Mark a block invalid along with a descendant chain
+	bad, tip := blocks[8].Header(), blocks[9].Header()
+	api.setInvalidAncestor(bad, tip)
+	if status := api.checkInvalidAncestor(tip.Hash(), tip.Hash()); status == nil {
+		t.Fatal("expected tipset to be invalid")
+	}
+	if !api.ForgetInvalidBlockV1(bad.Hash()) {
+		t.Fatal("expected invalid block to be forgotten")
+	}
+	if status := api.checkInvalidAncestor(tip.Hash(), tip.Hash()); status != nil {
+		t.Fatalf("expected tipset to be reprocessed, got %v", status.Status)
+	}
+	if api.ForgetInvalidBlockV1(bad.Hash()) {
+		t.Fatal("expected invalid block to be unknown")
+	}
+	// Cache a payload and clear everything
+	api.setInvalidAncestor(bad, tip)
+	parent := ethservice.BlockChain().CurrentBlock()
+	fcState := engine.ForkchoiceStateV1{HeadBlockHash: parent.Hash()}
+	if _, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, &engine.PayloadAttributes{Timestamp: parent.Time + 5, NoTxPool: true}); err != nil {
+		t.Fatalf("error preparing payload, err=%v", err)
+	}
+	cleared := api.ClearCachesV1()
+	if cleared.LocalPayloads != 1 || cleared.InvalidBlocks != 1 || cleared.InvalidTipsets != 1 {
+		t.Fatalf("cleared caches mismatch: %+v", cleared)
+	}
+	if payloads := api.ListPayloadsV1(); len(payloads) != 0 {
+		t.Fatalf("payload count mismatch: have %d, want 0", len(payloads))
+	}
+}
+
 func checkLogEvents(t *testing.T, logsCh <-chan []*types.Log, rmLogsCh <-chan core.RemovedLogsEvent, wantNew, wantRemoved int) {
 	t.Helper()
 
@@ -246,6 +317,37 @@ func checkLogEvents(t *testing.T, logsCh <-chan []*types.Log, rmLogsCh <-chan co
 	}
 }
 
+func TestFrozenChain(t *testing.T) {
+	genesis, blocks := generateMergeChain(10, false)
+	genesis.Config.TerminalTotalDifficulty.Sub(genesis.Config.TerminalTotalDifficulty, blocks[9].Difficulty())
+	n, ethservice := startEthService(t, genesis, blocks[:9])
+	defer n.Close()
+
+	api := NewConsensusAPI(ethservice)
+	if err := ethservice.Freeze("test", eth.FreezeSourceRPC, 0); err != nil {
+		t.Fatalf("failed to freeze chain: %v", err)
+	}
+	var (
+		fcState     = engine.ForkchoiceStateV1{HeadBlockHash: blocks[8].Hash()}
+		blockParams = engine.PayloadAttributes{Timestamp: blocks[8].Time() + 5}
+	)
+	resp, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, &blockParams)
+	if err != engine.ChainFrozen {
+		t.Fatalf("payload build error mismatch: have %v, want %v", err, engine.ChainFrozen)
+	}
+	if resp.PayloadStatus.Status != engine.SYNCING || resp.PayloadID != nil {
+		t.Fatalf("unexpected forkchoice response while frozen: %v", resp)
+	}
+	// Unfreezing resumes block production
+	ethservice.Unfreeze(eth.FreezeSourceRPC)
+	if resp, err = api.ForkchoiceUpdatedV1(context.Background(), fcState, &blockParams); err != nil {
+		t.Fatalf("error preparing payload after unfreeze: %v", err)
+	}
+	if resp.PayloadID == nil {
+		t.Fatal("no payload built after unfreeze")
+	}
+}
+
 func TestInvalidPayloadTimestamp(t *testing.T) {
 	genesis, preMergeBlocks := generateMergeChain(10, false)
 	n, ethservice := startEthService(t, genesis, preMergeBlocks)
@@ -280,7 +382,7 @@ func TestInvalidPayloadTimestamp(t *testing.T) {
 				SafeBlockHash:      common.Hash{},
 				FinalizedBlockHash: common.Hash{},
 			}
-			_, err := api.ForkchoiceUpdatedV1(fcState, &params)
+			_, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, &params)
 			if test.shouldErr && err == nil {
 				t.Fatalf("expected error preparing payload with invalid timestamp, err=%v", err)
 			} else if !test.shouldErr && err != nil {
@@ -339,7 +441,7 @@ func TestEth2NewBlock(t *testing.T) {
 			SafeBlockHash:      block.Hash(),
 			FinalizedBlockHash: block.Hash(),
 		}
-		if _, err := api.ForkchoiceUpdatedV1(fcState, nil); err != nil {
+		if _, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, nil); err != nil {
 			t.Fatalf("Failed to insert block: %v", err)
 		}
 		if have, want := ethservice.BlockChain().CurrentBlock().Number.Uint64(), block.NumberU64(); have != want {
@@ -379,7 +481,7 @@ func TestEth2NewBlock(t *testing.T) {
 			SafeBlockHash:      block.Hash(),
 			FinalizedBlockHash: block.Hash(),
 		}
-		if _, err := api.ForkchoiceUpdatedV1(fcState, nil); err != nil {
+		if _, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, nil); err != nil {
 			t.Fatalf("Failed to insert block: %v", err)
 		}
 		if ethservice.BlockChain().CurrentBlock().Number.Uint64() != block.NumberU64() {
@@ -515,7 +617,7 @@ func setupBlocks(t *testing.T, ethservice *eth.Ethereum, n int, parent *types.He
 			SafeBlockHash:      payload.ParentHash,
 			FinalizedBlockHash: payload.ParentHash,
 		}
-		if _, err := api.ForkchoiceUpdatedV1(fcState, nil); err != nil {
+		if _, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, nil); err != nil {
 			t.Fatalf("Failed to insert block: %v", err)
 		}
 		if ethservice.BlockChain().CurrentBlock().Number.Uint64() != payload.Number {
@@ -629,7 +731,7 @@ func TestNewPayloadOnInvalidChain(t *testing.T) {
 			err     error
 		)
 		for i := 0; ; i++ {
-			if resp, err = api.ForkchoiceUpdatedV1(fcState, &params); err != nil {
+			if resp, err = api.ForkchoiceUpdatedV1(context.Background(), fcState, &params); err != nil {
 				t.Fatalf("error preparing payload, err=%v", err)
 			}
 			if resp.PayloadStatus.Status != engine.VALID {
@@ -661,7 +763,7 @@ func TestNewPayloadOnInvalidChain(t *testing.T) {
 			SafeBlockHash:      payload.ParentHash,
 			FinalizedBlockHash: payload.ParentHash,
 		}
-		if _, err := api.ForkchoiceUpdatedV1(fcState, nil); err != nil {
+		if _, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, nil); err != nil {
 			t.Fatalf("Failed to insert block: %v", err)
 		}
 		if ethservice.BlockChain().CurrentBlock().Number.Uint64() != payload.Number {
@@ -680,7 +782,7 @@ func assembleBlock(api *ConsensusAPI, parentHash common.Hash, params *engine.Pay
 		Withdrawals:  params.Withdrawals,
 		BeaconRoot:   params.BeaconRoot,
 	}
-	payload, err := api.eth.Miner().BuildPayload(args)
+	payload, err := api.eth.Miner().BuildPayload(context.Background(), args)
 	if err != nil {
 		return nil, err
 	}
@@ -855,7 +957,7 @@ func TestTrickRemoteBlockCache(t *testing.T) {
 			t.Error("invalid status: VALID on an invalid chain")
 		}
 		// Now reorg to the head of the invalid chain
-		resp, err := apiB.ForkchoiceUpdatedV1(engine.ForkchoiceStateV1{HeadBlockHash: payload.BlockHash, SafeBlockHash: payload.BlockHash, FinalizedBlockHash: payload.ParentHash}, nil)
+		resp, err := apiB.ForkchoiceUpdatedV1(context.Background(), engine.ForkchoiceStateV1{HeadBlockHash: payload.BlockHash, SafeBlockHash: payload.BlockHash, FinalizedBlockHash: payload.ParentHash}, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -903,7 +1005,7 @@ func TestNewPayloadOnInvalidTerminalBlock(t *testing.T) {
 		SafeBlockHash:      common.Hash{},
 		FinalizedBlockHash: common.Hash{},
 	}
-	resp, err := api.ForkchoiceUpdatedV1(fcState, nil)
+	resp, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, nil)
 	if err != nil {
 		t.Fatalf("error sending forkchoice, err=%v", err)
 	}
@@ -918,7 +1020,7 @@ func TestNewPayloadOnInvalidTerminalBlock(t *testing.T) {
 		Random:       crypto.Keccak256Hash([]byte{byte(1)}),
 		FeeRecipient: parent.Coinbase(),
 	}
-	payload, err := api.eth.Miner().BuildPayload(args)
+	payload, err := api.eth.Miner().BuildPayload(context.Background(), args)
 	if err != nil {
 		t.Fatalf("error preparing payload, err=%v", err)
 	}
@@ -1023,7 +1125,7 @@ func TestSimultaneousNewBlock(t *testing.T) {
 			for ii := 0; ii < 10; ii++ {
 				go func() {
 					defer wg.Done()
-					if _, err := api.ForkchoiceUpdatedV1(fcState, nil); err != nil {
+					if _, err := api.ForkchoiceUpdatedV1(context.Background(), fcState, nil); err != nil {
 						errMu.Lock()
 						testErr = fmt.Errorf("Failed to insert block: %w", err)
 						errMu.Unlock()
@@ -1065,7 +1167,7 @@ func TestWithdrawals(t *testing.T) {
 	fcState := engine.ForkchoiceStateV1{
 		HeadBlockHash: parent.Hash(),
 	}
-	resp, err := api.ForkchoiceUpdatedV2(fcState, &blockParams)
+	resp, err := api.ForkchoiceUpdatedV2(context.Background(), fcState, &blockParams)
 	if err != nil {
 		t.Fatalf("error preparing payload, err=%v", err)
 	}
@@ -1116,7 +1218,7 @@ func TestWithdrawals(t *testing.T) {
 		},
 	}
 	fcState.HeadBlockHash = execData.ExecutionPayload.BlockHash
-	_, err = api.ForkchoiceUpdatedV2(fcState, &blockParams)
+	_, err = api.ForkchoiceUpdatedV2(context.Background(), fcState, &blockParams)
 	if err != nil {
 		t.Fatalf("error preparing payload, err=%v", err)
 	}
@@ -1142,7 +1244,7 @@ func TestWithdrawals(t *testing.T) {
 
 	// 11: set block as head.
 	fcState.HeadBlockHash = execData.ExecutionPayload.BlockHash
-	_, err = api.ForkchoiceUpdatedV2(fcState, nil)
+	_, err = api.ForkchoiceUpdatedV2(context.Background(), fcState, nil)
 	if err != nil {
 		t.Fatalf("error preparing payload, err=%v", err)
 	}
@@ -1242,7 +1344,7 @@ func TestNilWithdrawals(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		_, err := api.ForkchoiceUpdatedV2(fcState, &test.blockParams)
+		_, err := api.ForkchoiceUpdatedV2(context.Background(), fcState, &test.blockParams)
 		if test.wantErr {
 			if err == nil {
 				t.Fatal("wanted error on fcuv2 with invalid withdrawals")
@@ -1592,7 +1694,7 @@ func TestParentBeaconBlockRoot(t *testing.T) {
 	fcState := engine.ForkchoiceStateV1{
 		HeadBlockHash: parent.Hash(),
 	}
-	resp, err := api.ForkchoiceUpdatedV2(fcState, &blockParams)
+	resp, err := api.ForkchoiceUpdatedV2(context.Background(), fcState, &blockParams)
 	if err != nil {
 		t.Fatalf("error preparing payload, err=%v", err.(*engine.EngineAPIError).ErrorData())
 	}
@@ -1622,7 +1724,7 @@ func TestParentBeaconBlockRoot(t *testing.T) {
 	}
 
 	fcState.HeadBlockHash = execData.ExecutionPayload.BlockHash
-	resp, err = api.ForkchoiceUpdatedV3(fcState, nil)
+	resp, err = api.ForkchoiceUpdatedV3(context.Background(), fcState, nil)
 	if err != nil {
 		t.Fatalf("error preparing payload, err=%v", err.(*engine.EngineAPIError).ErrorData())
 	}
@@ -0,0 +1,79 @@
+package catalyst
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// DeposedBlock reports the outcome of engine_deposeBlockV1.
+type DeposedBlock struct {
+	Head      common.Hash       `json:"headBlockHash"`   // New head, the parent of the deposed block
+	Number    hexutil.Uint64    `json:"headBlockNumber"` // Number of the new head
+	PayloadID *engine.PayloadID `json:"payloadId"`       // Replacement payload being built, if requested
+}
+
+// DeposeBlockV1 removes an unsafe block, along with its descendants, from the
+// canonical chain once the supervisor marked a cross-chain message it includes
+// invalid. The chain is rewound to the parent of the block, which is tracked as
+// invalid from then on. If payload attributes are given, the building of the
+// replacement block on top of the parent is started, to be retrieved with
+// engine_getPayload like any payload.
+func (api *ConsensusAPI) DeposeBlockV1(hash common.Hash, payloadAttributes *engine.PayloadAttributes) (*DeposedBlock, error) {
+	if payloadAttributes != nil {
+		if err := api.verifyPayloadAttributes(payloadAttributes); err != nil {
+			return nil, engine.InvalidParams.With(err)
+		}
+	}
+	head, err := api.deposeBlock(hash)
+	if err != nil {
+		return nil, err
+	}
+	result := &DeposedBlock{Head: head.Hash(), Number: hexutil.Uint64(head.Number.Uint64())}
+	if payloadAttributes == nil {
+		return result, nil
+	}
+	resp, err := api.forkchoiceUpdated(context.Background(), engine.ForkchoiceStateV1{HeadBlockHash: head.Hash()}, payloadAttributes)
+	if err != nil {
+		return nil, err
+	}
+	if resp.PayloadStatus.Status != engine.VALID {
+		return nil, fmt.Errorf("replacement payload not started: %s", resp.PayloadStatus.Status)
+	}
+	result.PayloadID = resp.PayloadID
+	return result, nil
+}
+
+// deposeBlock rewinds the chain to the parent of the given block and tracks it
+// as invalid, returning the new head.
+func (api *ConsensusAPI) deposeBlock(hash common.Hash) (*types.Header, error) {
+	// Hold the update locks so no block gets imported on top while rewinding
+	api.forkchoiceLock.Lock()
+	defer api.forkchoiceLock.Unlock()
+	api.newPayloadLock.Lock()
+	defer api.newPayloadLock.Unlock()
+
+	chain := api.eth.BlockChain()
+	header := chain.GetHeaderByHash(hash)
+	if header == nil {
+		return nil, engine.InvalidParams.With(errors.New("unknown block"))
+	}
+	if !chain.Config().IsInterop(header.Time) {
+		return nil, engine.UnsupportedFork.With(errors.New("block replacement requires interop"))
+	}
+	head, err := chain.DeposeBlock(hash)
+	if err != nil {
+		return nil, engine.InvalidParams.With(err)
+	}
+	// Keep rejecting the deposed block if it is sent again
+	api.setInvalidAncestor(header, header)
+
+	log.Warn("Deposed block on request", "number", header.Number, "hash", hash, "head", head.Number)
+	return head, nil
+}
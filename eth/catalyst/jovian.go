@@ -0,0 +1,69 @@
+package catalyst
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// jovianEngineVersion is the lowest version of the engine API methods accepted
+// for Jovian blocks. Their payloads carry the withdrawals list required since
+// Canyon, which the V1 methods lack.
+const jovianEngineVersion = 2
+
+// jovianFeature is a feature activated by the Jovian fork, with the checks of
+// the payload attributes and payloads of the blocks it is active for. Either
+// check may be nil.
+type jovianFeature struct {
+	name             string
+	verifyAttributes func(config *params.ChainConfig, attr *engine.PayloadAttributes) error
+	verifyPayload    func(config *params.ChainConfig, block *types.Block) error
+}
+
+// jovianFeatures lists the features activated by Jovian. The features slot their
+// checks in here as they get specified.
+var jovianFeatures []jovianFeature
+
+// checkJovianVersion rejects the engine API calls for Jovian blocks made with a
+// method version not supporting them.
+func checkJovianVersion(config *params.ChainConfig, method string, version int, time uint64) error {
+	if config.IsOptimismJovian(time) && version < jovianEngineVersion {
+		return engine.UnsupportedFork.With(fmt.Errorf("%sV%d called post-jovian", method, version))
+	}
+	return nil
+}
+
+// verifyJovianAttributes checks the payload attributes of a block against the
+// Jovian features, if active.
+func verifyJovianAttributes(config *params.ChainConfig, attr *engine.PayloadAttributes) error {
+	if !config.IsOptimismJovian(attr.Timestamp) {
+		return nil
+	}
+	for _, feature := range jovianFeatures {
+		if feature.verifyAttributes == nil {
+			continue
+		}
+		if err := feature.verifyAttributes(config, attr); err != nil {
+			return fmt.Errorf("jovian %s: %w", feature.name, err)
+		}
+	}
+	return nil
+}
+
+// verifyJovianPayload checks a payload against the Jovian features, if active.
+func verifyJovianPayload(config *params.ChainConfig, block *types.Block) error {
+	if !config.IsOptimismJovian(block.Time()) {
+		return nil
+	}
+	for _, feature := range jovianFeatures {
+		if feature.verifyPayload == nil {
+			continue
+		}
+		if err := feature.verifyPayload(config, block); err != nil {
+			return fmt.Errorf("jovian %s: %w", feature.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package catalyst
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestJovianScaffolding(t *testing.T) {
+	var (
+		operatorFee = uint64(0)
+		jovian      = uint64(100)
+		config      = &params.ChainConfig{OasysOperatorFeeTime: &operatorFee, JovianTime: &jovian, Optimism: &params.OptimismConfig{}}
+		errTest     = errors.New("test feature rejection")
+	)
+	if err := checkJovianVersion(config, "newPayload", 1, 99); err != nil {
+		t.Errorf("V1 rejected pre-jovian: %v", err)
+	}
+	if err := checkJovianVersion(config, "newPayload", 1, 100); err == nil {
+		t.Error("V1 accepted post-jovian")
+	}
+	if err := checkJovianVersion(config, "newPayload", 2, 100); err != nil {
+		t.Errorf("V2 rejected post-jovian: %v", err)
+	}
+
+	defer func(features []jovianFeature) { jovianFeatures = features }(jovianFeatures)
+	jovianFeatures = []jovianFeature{{
+		name: "test",
+		verifyAttributes: func(config *params.ChainConfig, attr *engine.PayloadAttributes) error {
+			return errTest
+		},
+		verifyPayload: func(config *params.ChainConfig, block *types.Block) error {
+			return errTest
+		},
+	}}
+	if err := verifyJovianAttributes(config, &engine.PayloadAttributes{Timestamp: 99}); err != nil {
+		t.Errorf("attributes checked pre-jovian: %v", err)
+	}
+	if err := verifyJovianAttributes(config, &engine.PayloadAttributes{Timestamp: 100}); !errors.Is(err, errTest) {
+		t.Errorf("attributes check mismatch: have %v, want %v", err, errTest)
+	}
+	if err := verifyJovianPayload(config, types.NewBlockWithHeader(&types.Header{Time: 99})); err != nil {
+		t.Errorf("payload checked pre-jovian: %v", err)
+	}
+	if err := verifyJovianPayload(config, types.NewBlockWithHeader(&types.Header{Time: 100})); !errors.Is(err, errTest) {
+		t.Errorf("payload check mismatch: have %v, want %v", err, errTest)
+	}
+}
@@ -0,0 +1,83 @@
+package catalyst
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/miner"
+)
+
+// PayloadInfo describes a payload cached by the execution engine, as returned by
+// engine_listPayloadsV1.
+type PayloadInfo struct {
+	ID           engine.PayloadID `json:"payloadId"`
+	Parent       common.Hash      `json:"parentHash"`
+	Number       hexutil.Uint64   `json:"blockNumber"`
+	Timestamp    hexutil.Uint64   `json:"timestamp"`
+	FeeRecipient common.Address   `json:"feeRecipient"`
+	GasLimit     *hexutil.Uint64  `json:"gasLimit,omitempty"`
+	NoTxPool     bool             `json:"noTxPool"`
+	ForcedTxs    hexutil.Uint     `json:"forcedTransactions"` // Transactions provided by the payload attributes
+	Txs          hexutil.Uint     `json:"transactions"`       // Transactions included in the payload built so far
+}
+
+// ListPayloadsV1 returns the payloads currently cached by the execution engine,
+// the most recent first. It is meant to debug "Unknown payload" errors.
+func (api *ConsensusAPI) ListPayloadsV1() []*PayloadInfo {
+	items := api.localBlocks.list()
+
+	infos := make([]*PayloadInfo, 0, len(items))
+	for _, item := range items {
+		args, block := item.payload.Args(), item.payload.Block()
+		info := &PayloadInfo{
+			ID:           item.id,
+			Parent:       args.Parent,
+			Number:       hexutil.Uint64(block.NumberU64()),
+			Timestamp:    hexutil.Uint64(args.Timestamp),
+			FeeRecipient: args.FeeRecipient,
+			GasLimit:     (*hexutil.Uint64)(args.GasLimit),
+			NoTxPool:     args.NoTxPool,
+			ForcedTxs:    hexutil.Uint(len(args.Transactions)),
+			Txs:          hexutil.Uint(len(block.Transactions())),
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// ComputePayloadIdV1 returns the identifier of the payload a forkchoice update
+// building on top of the given parent with the given attributes would start.
+func (api *ConsensusAPI) ComputePayloadIdV1(parent common.Hash, payloadAttributes engine.PayloadAttributes) (engine.PayloadID, error) {
+	args, err := buildPayloadArgs(parent, &payloadAttributes)
+	if err != nil {
+		return engine.PayloadID{}, engine.InvalidPayloadAttributes.With(err)
+	}
+	return args.Id(), nil
+}
+
+// buildPayloadArgs converts the payload attributes of a forkchoice update into
+// the parameters to build the payload with.
+func buildPayloadArgs(parent common.Hash, attr *engine.PayloadAttributes) (*miner.BuildPayloadArgs, error) {
+	transactions := make(types.Transactions, 0, len(attr.Transactions))
+	for i, otx := range attr.Transactions {
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(otx); err != nil {
+			return nil, fmt.Errorf("transaction %d is not valid: %v", i, err)
+		}
+		transactions = append(transactions, &tx)
+	}
+	return &miner.BuildPayloadArgs{
+		Parent:       parent,
+		Timestamp:    attr.Timestamp,
+		FeeRecipient: attr.SuggestedFeeRecipient,
+		Random:       attr.Random,
+		Withdrawals:  attr.Withdrawals,
+		BeaconRoot:   attr.BeaconRoot,
+		NoTxPool:     attr.NoTxPool,
+		Transactions: transactions,
+		GasLimit:     attr.GasLimit,
+	}, nil
+}
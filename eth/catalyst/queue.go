@@ -91,6 +91,55 @@ func (q *payloadQueue) get(id engine.PayloadID, full bool) *engine.ExecutionPayl
 	return nil
 }
 
+// getBlock retrieves the latest built block of a previously stored payload.
+func (q *payloadQueue) getBlock(id engine.PayloadID) *types.Block {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	for _, item := range q.payloads {
+		if item == nil {
+			return nil // no more items
+		}
+		if item.id == id {
+			return item.payload.Block()
+		}
+	}
+	return nil
+}
+
+// list retrieves all the tracked payloads, the most recent first.
+func (q *payloadQueue) list() []*payloadQueueItem {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	var items []*payloadQueueItem
+	for _, item := range q.payloads {
+		if item == nil {
+			break // no more items
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// reset drops all the tracked payloads, terminating their background building,
+// and returns the number of payloads dropped.
+func (q *payloadQueue) reset() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	var dropped int
+	for i, item := range q.payloads {
+		if item == nil {
+			break // no more items
+		}
+		item.payload.Resolve()
+		q.payloads[i] = nil
+		dropped++
+	}
+	return dropped
+}
+
 // has checks if a particular payload is already tracked.
 func (q *payloadQueue) has(id engine.PayloadID) bool {
 	q.lock.RLock()
@@ -156,3 +205,19 @@ func (q *headerQueue) get(hash common.Hash) *types.Header {
 	}
 	return nil
 }
+
+// reset drops all the tracked headers and returns the number of headers dropped.
+func (q *headerQueue) reset() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	var dropped int
+	for i, item := range q.headers {
+		if item == nil {
+			break // no more items
+		}
+		q.headers[i] = nil
+		dropped++
+	}
+	return dropped
+}
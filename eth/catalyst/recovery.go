@@ -0,0 +1,65 @@
+package catalyst
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ClearedCaches reports the number of entries dropped by engine_clearCachesV1.
+type ClearedCaches struct {
+	LocalPayloads  hexutil.Uint `json:"localPayloads"`
+	RemotePayloads hexutil.Uint `json:"remotePayloads"`
+	InvalidBlocks  hexutil.Uint `json:"invalidBlocks"`
+	InvalidTipsets hexutil.Uint `json:"invalidTipsets"`
+}
+
+// ClearCachesV1 drops the cached local and remote payloads along with the
+// tracked bad blocks, so operators can recover from transient failures without
+// restarting the node. The payloads being built are abandoned.
+func (api *ConsensusAPI) ClearCachesV1() *ClearedCaches {
+	// Hold the update locks so no payload gets cached while clearing
+	api.forkchoiceLock.Lock()
+	defer api.forkchoiceLock.Unlock()
+	api.newPayloadLock.Lock()
+	defer api.newPayloadLock.Unlock()
+
+	api.invalidLock.Lock()
+	cleared := &ClearedCaches{
+		InvalidBlocks:  hexutil.Uint(len(api.invalidBlocksHits)),
+		InvalidTipsets: hexutil.Uint(len(api.invalidTipsets)),
+	}
+	api.invalidBlocksHits = make(map[common.Hash]int)
+	api.invalidTipsets = make(map[common.Hash]*types.Header)
+	api.invalidLock.Unlock()
+
+	cleared.LocalPayloads = hexutil.Uint(api.localBlocks.reset())
+	cleared.RemotePayloads = hexutil.Uint(api.remoteBlocks.reset())
+
+	log.Warn("Cleared engine API caches", "local", cleared.LocalPayloads, "remote", cleared.RemotePayloads,
+		"invalid", cleared.InvalidBlocks, "tipsets", cleared.InvalidTipsets)
+	return cleared
+}
+
+// ForgetInvalidBlockV1 drops a block previously marked invalid along with the
+// chains built on top of it, so that it is reprocessed the next time it is
+// referenced. It returns whether the block was tracked as invalid.
+func (api *ConsensusAPI) ForgetInvalidBlockV1(hash common.Hash) bool {
+	api.invalidLock.Lock()
+	defer api.invalidLock.Unlock()
+
+	_, known := api.invalidBlocksHits[hash]
+	delete(api.invalidBlocksHits, hash)
+
+	for descendant, badHeader := range api.invalidTipsets {
+		if badHeader.Hash() == hash {
+			delete(api.invalidTipsets, descendant)
+			known = true
+		}
+	}
+	if known {
+		log.Warn("Forgot invalid block on request, reprocessing", "hash", hash)
+	}
+	return known
+}
@@ -0,0 +1,36 @@
+package catalyst
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/eth"
+)
+
+// PromoteReplicaV1 promotes a failover replica to sequencer, provided its unsafe
+// head is the given block. Transaction forwarding is disabled and block building
+// from the transaction pool allowed at once. Being served on the authenticated
+// endpoint only, it is restricted to the operators holding the JWT secret.
+func (api *ConsensusAPI) PromoteReplicaV1(ctx context.Context, head common.Hash) (*eth.ReplicaStatus, error) {
+	// Query the primary before taking the forkchoice lock, not to stall the
+	// forkchoice updates on network I/O
+	if err := api.eth.CheckReplicaPromotion(ctx, head); err != nil {
+		return nil, engine.GenericServerError.With(err)
+	}
+	// Hold the forkchoice lock so no update moves the head while promoting
+	api.forkchoiceLock.Lock()
+	defer api.forkchoiceLock.Unlock()
+
+	if err := api.eth.PromoteReplica(head); err != nil {
+		return nil, engine.GenericServerError.With(err)
+	}
+	status := api.eth.ReplicaStatus()
+	return &status, nil
+}
+
+// ReplicaStatusV1 returns the failover replica state of the node.
+func (api *ConsensusAPI) ReplicaStatusV1() *eth.ReplicaStatus {
+	status := api.eth.ReplicaStatus()
+	return &status
+}
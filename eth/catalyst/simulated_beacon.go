@@ -17,6 +17,7 @@
 package catalyst
 
 import (
+	"context"
 	"crypto/rand"
 	"errors"
 	"sync"
@@ -72,10 +73,13 @@ type SimulatedBeacon struct {
 	eth         *eth.Ethereum
 	period      uint64
 	withdrawals withdrawalQueue
+	deposits    *depositQueue // Optimism addition: deposits forced into the next block
 
 	feeRecipient     common.Address
 	feeRecipientLock sync.Mutex // lock gates concurrent access to the feeRecipient
 
+	opAttributes optimismAttributes // Optimism addition: rollup fields of the payload attributes
+
 	engineAPI          *ConsensusAPI
 	curForkchoiceState engine.ForkchoiceStateV1
 	lastBlockTime      uint64
@@ -96,7 +100,7 @@ func NewSimulatedBeacon(period uint64, eth *eth.Ethereum) (*SimulatedBeacon, err
 
 	// if genesis block, send forkchoiceUpdated to trigger transition to PoS
 	if block.Number.Sign() == 0 {
-		if _, err := engineAPI.ForkchoiceUpdatedV2(current, nil); err != nil {
+		if _, err := engineAPI.ForkchoiceUpdatedV2(context.Background(), current, nil); err != nil {
 			return nil, err
 		}
 	}
@@ -108,6 +112,7 @@ func NewSimulatedBeacon(period uint64, eth *eth.Ethereum) (*SimulatedBeacon, err
 		lastBlockTime:      block.Time,
 		curForkchoiceState: current,
 		withdrawals:        withdrawalQueue{make(chan *types.Withdrawal, 20)},
+		deposits:           newDepositQueue(),
 	}, nil
 }
 
@@ -152,12 +157,22 @@ func (c *SimulatedBeacon) sealBlock(withdrawals []*types.Withdrawal) error {
 
 	var random [32]byte
 	rand.Read(random[:])
-	fcResponse, err := c.engineAPI.ForkchoiceUpdatedV2(c.curForkchoiceState, &engine.PayloadAttributes{
+	attrs := &engine.PayloadAttributes{
 		Timestamp:             tstamp,
 		SuggestedFeeRecipient: feeRecipient,
 		Withdrawals:           withdrawals,
 		Random:                random,
-	})
+	}
+	if c.eth.BlockChain().Config().IsOptimism() {
+		parent := c.eth.BlockChain().GetHeaderByHash(c.curForkchoiceState.HeadBlockHash)
+		if parent == nil {
+			return errors.New("chain rewind interrupted retrieval of parent block")
+		}
+		if err := c.setOptimismAttributes(attrs, parent); err != nil {
+			return err
+		}
+	}
+	fcResponse, err := c.engineAPI.ForkchoiceUpdatedV2(context.Background(), c.curForkchoiceState, attrs)
 	if err != nil {
 		return err
 	}
@@ -188,7 +203,7 @@ func (c *SimulatedBeacon) sealBlock(withdrawals []*types.Withdrawal) error {
 	}
 	c.setCurrentState(payload.BlockHash, finalizedHash)
 	// Mark the block containing the payload as canonical
-	if _, err = c.engineAPI.ForkchoiceUpdatedV2(c.curForkchoiceState, nil); err != nil {
+	if _, err = c.engineAPI.ForkchoiceUpdatedV2(context.Background(), c.curForkchoiceState, nil); err != nil {
 		return err
 	}
 	c.lastBlockTime = payload.Timestamp
@@ -217,6 +232,11 @@ func (c *SimulatedBeacon) loopOnDemand() {
 			if err := c.sealBlock(withdrawals); err != nil {
 				log.Warn("Error performing sealing work", "err", err)
 			}
+		case <-c.deposits.notify:
+			withdrawals := c.withdrawals.gatherPending(10)
+			if err := c.sealBlock(withdrawals); err != nil {
+				log.Warn("Error performing sealing work", "err", err)
+			}
 		}
 	}
 }
@@ -18,6 +18,7 @@ package catalyst
 
 import (
 	"context"
+	"errors"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -28,6 +29,9 @@ type api struct {
 }
 
 func (a *api) AddWithdrawal(ctx context.Context, withdrawal *types.Withdrawal) error {
+	if a.simBeacon.eth.BlockChain().Config().IsOptimism() {
+		return errors.New("withdrawals are not supported on optimism chains")
+	}
 	return a.simBeacon.withdrawals.add(withdrawal)
 }
 
@@ -0,0 +1,163 @@
+package catalyst
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// maxPendingDeposits is the maximum number of deposits queued for inclusion.
+const maxPendingDeposits = 256
+
+// depositQueue holds the deposit transactions pending inclusion, which are
+// forced into the next block like the deposits derived from L1 by a rollup node.
+type depositQueue struct {
+	pending []*types.Transaction
+	notify  chan struct{}
+	lock    sync.Mutex
+}
+
+func newDepositQueue() *depositQueue {
+	return &depositQueue{notify: make(chan struct{}, 1)}
+}
+
+// add queues a deposit for inclusion in the next block.
+func (q *depositQueue) add(tx *types.Transaction) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if len(q.pending) >= maxPendingDeposits {
+		return errors.New("deposit queue full")
+	}
+	q.pending = append(q.pending, tx)
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// gatherPending returns all the queued deposits, emptying the queue.
+func (q *depositQueue) gatherPending() []*types.Transaction {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	deposits := q.pending
+	q.pending = nil
+	return deposits
+}
+
+// optimismAttributes holds the rollup specific payload attributes used by the
+// simulated beacon to build the blocks of an OP Stack chain.
+type optimismAttributes struct {
+	gasLimit *uint64 // Gas limit of the blocks, the one of the parent if nil
+	noTxPool bool    // Whether the blocks only include the queued deposits
+	lock     sync.Mutex
+}
+
+func (c *SimulatedBeacon) setGasLimit(gasLimit *uint64) {
+	c.opAttributes.lock.Lock()
+	c.opAttributes.gasLimit = gasLimit
+	c.opAttributes.lock.Unlock()
+}
+
+func (c *SimulatedBeacon) setNoTxPool(noTxPool bool) {
+	c.opAttributes.lock.Lock()
+	c.opAttributes.noTxPool = noTxPool
+	c.opAttributes.lock.Unlock()
+}
+
+// setOptimismAttributes fills the rollup fields of the payload attributes of the
+// block built on top of the given parent, forcing the queued deposits into it.
+func (c *SimulatedBeacon) setOptimismAttributes(attrs *engine.PayloadAttributes, parent *types.Header) error {
+	c.opAttributes.lock.Lock()
+	gasLimit, noTxPool := c.opAttributes.gasLimit, c.opAttributes.noTxPool
+	c.opAttributes.lock.Unlock()
+
+	if gasLimit == nil {
+		gasLimit = &parent.GasLimit
+	}
+	attrs.GasLimit = gasLimit
+	attrs.NoTxPool = noTxPool
+
+	// Withdrawals are not supported by OP Stack chains, the list must be empty
+	// once activated by Canyon.
+	if c.eth.BlockChain().Config().IsOptimismCanyon(attrs.Timestamp) {
+		attrs.Withdrawals = []*types.Withdrawal{}
+	} else {
+		attrs.Withdrawals = nil
+	}
+	for _, tx := range c.deposits.gatherPending() {
+		blob, err := tx.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		attrs.Transactions = append(attrs.Transactions, blob)
+	}
+	return nil
+}
+
+// DepositArgs represents the arguments of a deposit transaction injected in the
+// development chain, as if it was derived from L1.
+type DepositArgs struct {
+	SourceHash *common.Hash    `json:"sourceHash"`
+	From       common.Address  `json:"from"`
+	To         *common.Address `json:"to"`
+	Mint       *hexutil.Big    `json:"mint"`
+	Value      *hexutil.Big    `json:"value"`
+	Gas        hexutil.Uint64  `json:"gas"`
+	Data       hexutil.Bytes   `json:"data"`
+}
+
+// AddDeposit queues a deposit transaction for inclusion in the next block and
+// returns its hash. A random source hash is used if none is given.
+func (a *api) AddDeposit(ctx context.Context, args DepositArgs) (common.Hash, error) {
+	if !a.simBeacon.eth.BlockChain().Config().IsOptimism() {
+		return common.Hash{}, errors.New("deposits are only supported on optimism chains")
+	}
+	if args.Gas == 0 {
+		return common.Hash{}, errors.New("deposit gas limit is required")
+	}
+	var sourceHash common.Hash
+	if args.SourceHash != nil {
+		sourceHash = *args.SourceHash
+	} else {
+		rand.Read(sourceHash[:])
+	}
+	value := new(big.Int)
+	if args.Value != nil {
+		value = args.Value.ToInt()
+	}
+	tx := types.NewTx(&types.DepositTx{
+		SourceHash: sourceHash,
+		From:       args.From,
+		To:         args.To,
+		Mint:       (*big.Int)(args.Mint),
+		Value:      value,
+		Gas:        uint64(args.Gas),
+		Data:       args.Data,
+	})
+	if err := a.simBeacon.deposits.add(tx); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// SetGasLimit sets the gas limit of the blocks built on optimism chains. The gas
+// limit of the parent block is kept if nil.
+func (a *api) SetGasLimit(ctx context.Context, gasLimit *hexutil.Uint64) {
+	a.simBeacon.setGasLimit((*uint64)(gasLimit))
+}
+
+// SetNoTxPool sets whether the blocks built on optimism chains only include the
+// queued deposits, leaving the transactions of the pool out.
+func (a *api) SetNoTxPool(ctx context.Context, noTxPool bool) {
+	a.simBeacon.setNoTxPool(noTxPool)
+}
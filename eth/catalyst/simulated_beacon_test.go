@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -139,3 +140,76 @@ func TestSimulatedBeaconSendWithdrawals(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the simulated beacon builds the blocks of optimism chains with the
+// rollup payload attributes, forcing the queued deposits into the blocks.
+func TestSimulatedBeaconOptimism(t *testing.T) {
+	var (
+		testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		testAddr   = crypto.PubkeyToAddress(testKey.PublicKey)
+		recipient  = common.Address{0xaa}
+		zero       uint64
+		gasLimit   uint64 = 20_000_000
+	)
+	genesis := core.DeveloperGenesisBlock(10_000_000, testAddr)
+	genesis.Config.BedrockBlock = new(big.Int)
+	genesis.Config.RegolithTime = &zero
+	genesis.Config.CanyonTime = &zero
+	genesis.Config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50, EIP1559DenominatorCanyon: 250}
+
+	node, ethService, mock := startSimulatedBeaconEthService(t, genesis)
+	defer node.Close()
+
+	chainHeadCh := make(chan core.ChainHeadEvent, 10)
+	subscription := ethService.BlockChain().SubscribeChainHeadEvent(chainHeadCh)
+	defer subscription.Unsubscribe()
+
+	devAPI := &api{mock}
+	devAPI.SetGasLimit(context.Background(), (*hexutil.Uint64)(&gasLimit))
+	devAPI.SetNoTxPool(context.Background(), true)
+
+	// Send a transaction to the pool, which must be left out of the blocks
+	signer := types.LatestSigner(ethService.BlockChain().Config())
+	tx, err := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(1000), params.TxGas, big.NewInt(params.InitialBaseFee), nil), signer, testKey)
+	if err != nil {
+		t.Fatalf("error signing transaction, err=%v", err)
+	}
+	if err := ethService.APIBackend.SendTx(context.Background(), tx); err != nil {
+		t.Fatal("SendTx failed", err)
+	}
+	hash, err := devAPI.AddDeposit(context.Background(), DepositArgs{
+		From:  testAddr,
+		To:    &recipient,
+		Mint:  (*hexutil.Big)(big.NewInt(1000)),
+		Value: (*hexutil.Big)(big.NewInt(1000)),
+		Gas:   hexutil.Uint64(params.TxGas),
+	})
+	if err != nil {
+		t.Fatal("AddDeposit failed", err)
+	}
+	timer := time.NewTimer(12 * time.Second)
+	for {
+		select {
+		case evt := <-chainHeadCh:
+			if evt.Block.GasLimit() != gasLimit {
+				t.Fatalf("gas limit mismatch: have %d, want %d", evt.Block.GasLimit(), gasLimit)
+			}
+			if evt.Block.Transaction(tx.Hash()) != nil {
+				t.Fatal("pool transaction included with no tx pool")
+			}
+			if evt.Block.Transaction(hash) == nil {
+				continue
+			}
+			state, err := ethService.BlockChain().StateAt(evt.Block.Root())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if balance := state.GetBalance(recipient); balance.Cmp(big.NewInt(1000)) != 0 {
+				t.Fatalf("minted balance mismatch: have %v, want 1000", balance)
+			}
+			return
+		case <-timer.C:
+			t.Fatal("timed out without including the deposit")
+		}
+	}
+}
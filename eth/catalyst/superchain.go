@@ -29,6 +29,7 @@ func (api *ConsensusAPI) SignalSuperchainV1(signal *SuperchainSignal) (params.Pr
 	logger := log.New("local", params.OPStackSupport, "required", signal.Required, "recommended", signal.Recommended)
 	LogProtocolVersionSupport(logger, params.OPStackSupport, signal.Recommended, "recommended")
 	LogProtocolVersionSupport(logger, params.OPStackSupport, signal.Required, "required")
+	api.eth.SetProtocolVersionSignal(signal.Recommended, signal.Required)
 
 	if err := api.eth.HandleRequiredProtocolVersion(signal.Required); err != nil {
 		log.Error("Failed to handle required protocol version", "err", err, "required", signal.Required)
@@ -0,0 +1,72 @@
+package eth
+
+import (
+	"encoding/json"
+	"runtime"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/forkid"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/internal/version"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ClientIdentity describes the build and the chain configuration of the node, so
+// fleet tooling can verify all the nodes run compatible configurations.
+type ClientIdentity struct {
+	Version         string                 `json:"version"`
+	Commit          string                 `json:"commit,omitempty"`
+	CommitDate      string                 `json:"commitDate,omitempty"`
+	Dirty           bool                   `json:"dirty,omitempty"`
+	GoVersion       string                 `json:"goVersion"`
+	ProtocolVersion params.ProtocolVersion `json:"protocolVersion"` // Supported OP Stack protocol version
+	ChainID         *hexutil.Big           `json:"chainId"`
+	Genesis         common.Hash            `json:"genesis"`
+
+	// ForkFingerprint is the hash of the JSON-encoded chain configuration, thus
+	// covering the whole fork schedule including the rollup and custom forks.
+	ForkFingerprint common.Hash `json:"forkFingerprint"`
+
+	// ForkID is the EIP-2124 fork identifier at the current head.
+	ForkID     hexutil.Bytes  `json:"forkId"`
+	ForkIDNext hexutil.Uint64 `json:"forkIdNext"`
+}
+
+// forkFingerprint returns the hash of the JSON encoding of the chain config.
+func forkFingerprint(config *params.ChainConfig) (common.Hash, error) {
+	blob, err := json.Marshal(config)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(blob), nil
+}
+
+// ClientIdentity returns the build information and the fork configuration of
+// the node.
+func (s *Ethereum) ClientIdentity() (*ClientIdentity, error) {
+	var (
+		config  = s.blockchain.Config()
+		genesis = s.blockchain.Genesis()
+		head    = s.blockchain.CurrentHeader()
+	)
+	fingerprint, err := forkFingerprint(config)
+	if err != nil {
+		return nil, err
+	}
+	id := forkid.NewID(config, genesis, head.Number.Uint64(), head.Time)
+	identity := &ClientIdentity{
+		Version:         params.VersionWithMeta,
+		GoVersion:       runtime.Version(),
+		ProtocolVersion: params.OPStackSupport,
+		ChainID:         (*hexutil.Big)(config.ChainID),
+		Genesis:         genesis.Hash(),
+		ForkFingerprint: fingerprint,
+		ForkID:          id.Hash[:],
+		ForkIDNext:      hexutil.Uint64(id.Next),
+	}
+	if vcs, ok := version.VCS(); ok {
+		identity.Commit, identity.CommitDate, identity.Dirty = vcs.Commit, vcs.Date, vcs.Dirty
+	}
+	return identity, nil
+}
@@ -0,0 +1,23 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestForkFingerprint(t *testing.T) {
+	config := *params.OptimismTestConfig
+	base, err := forkFingerprint(&config)
+	if err != nil {
+		t.Fatalf("failed to fingerprint config: %v", err)
+	}
+	if again, _ := forkFingerprint(&config); again != base {
+		t.Fatalf("fingerprint not deterministic: %x != %x", again, base)
+	}
+	canyon := uint64(1000)
+	config.CanyonTime = &canyon
+	if changed, _ := forkFingerprint(&config); changed == base {
+		t.Fatal("fingerprint unchanged by fork schedule change")
+	}
+}
@@ -0,0 +1,189 @@
+package eth
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	consensusClientGauge         = metrics.NewRegisteredGaugeInfo("engine/client/version", nil)
+	consensusClientOutdatedGauge = metrics.NewRegisteredGauge("engine/client/outdated", nil)
+)
+
+// ConsensusClientInfo describes the consensus client driving the node, as
+// reported by it through the Engine API.
+type ConsensusClientInfo struct {
+	Version      *engine.ClientVersionV1 `json:"version,omitempty"`
+	Capabilities []string                `json:"capabilities,omitempty"`
+	Minimum      string                  `json:"minimum,omitempty"` // Minimum version required by the active forks
+	Outdated     bool                    `json:"outdated"`
+	Updated      time.Time               `json:"updated"`
+}
+
+// consensusClient tracks the consensus client reported through the Engine API.
+type consensusClient struct {
+	lock sync.Mutex
+	info *ConsensusClientInfo
+}
+
+// SetConsensusClientVersion records the version reported by the consensus client
+// and warns if it is below the minimum configured for the active forks.
+func (s *Ethereum) SetConsensusClientVersion(version engine.ClientVersionV1) {
+	s.consensusClient.lock.Lock()
+	defer s.consensusClient.lock.Unlock()
+
+	info := s.consensusClientInfo()
+	if info.Version == nil || *info.Version != version {
+		log.Info("Consensus client version reported", "client", version.String())
+	}
+	info.Version = &version
+	info.Updated = time.Now()
+	info.Minimum = "" // force reevaluation against the new version
+
+	consensusClientGauge.Update(metrics.GaugeInfoValue{
+		"code":    version.Code,
+		"name":    version.Name,
+		"version": version.Version,
+		"commit":  version.Commit,
+	})
+	s.checkConsensusClient(info)
+}
+
+// SetConsensusClientCapabilities records the Engine API methods the consensus
+// client reported to support.
+func (s *Ethereum) SetConsensusClientCapabilities(capabilities []string) {
+	s.consensusClient.lock.Lock()
+	defer s.consensusClient.lock.Unlock()
+
+	info := s.consensusClientInfo()
+	info.Capabilities = capabilities
+	info.Updated = time.Now()
+}
+
+// ConsensusClient returns what the consensus client reported about itself, or
+// nil if it did not report anything yet.
+func (s *Ethereum) ConsensusClient() *ConsensusClientInfo {
+	s.consensusClient.lock.Lock()
+	defer s.consensusClient.lock.Unlock()
+
+	if s.consensusClient.info == nil {
+		return nil
+	}
+	s.checkConsensusClient(s.consensusClient.info)
+	info := *s.consensusClient.info
+	return &info
+}
+
+// consensusClientInfo returns the tracked info, creating it if needed. The lock
+// is assumed to be held.
+func (s *Ethereum) consensusClientInfo() *ConsensusClientInfo {
+	if s.consensusClient.info == nil {
+		s.consensusClient.info = new(ConsensusClientInfo)
+	}
+	return s.consensusClient.info
+}
+
+// checkConsensusClient compares the reported version with the highest minimum
+// configured for the forks active at the chain head. The lock is assumed to be
+// held.
+func (s *Ethereum) checkConsensusClient(info *ConsensusClientInfo) {
+	if info.Version == nil || len(s.config.RollupMinConsensusVersions) == 0 {
+		return
+	}
+	var (
+		config  = s.blockchain.Config()
+		head    = s.blockchain.CurrentBlock()
+		minimum string
+	)
+	for _, fork := range []struct {
+		name   string
+		active bool
+	}{
+		{"bedrock", config.IsOptimismBedrock(head.Number)},
+		{"regolith", config.IsOptimismRegolith(head.Time)},
+		{"canyon", config.IsOptimismCanyon(head.Time)},
+		{"oasysOperatorFee", config.IsOasysOperatorFee(head.Time)},
+		{"jovian", config.IsOptimismJovian(head.Time)},
+		{"interop", config.IsInterop(head.Time)},
+	} {
+		required, ok := s.config.RollupMinConsensusVersions[fork.name]
+		if !ok || !fork.active {
+			continue
+		}
+		if minimum == "" {
+			minimum = required
+		} else if cmp, err := compareVersions(required, minimum); err == nil && cmp > 0 {
+			minimum = required
+		}
+	}
+	if minimum == info.Minimum && info.Minimum != "" {
+		return // already evaluated against this minimum
+	}
+	info.Minimum, info.Outdated = minimum, false
+	if minimum == "" {
+		consensusClientOutdatedGauge.Update(0)
+		return
+	}
+	cmp, err := compareVersions(info.Version.Version, minimum)
+	if err != nil {
+		log.Warn("Unable to compare consensus client version", "version", info.Version.Version, "minimum", minimum, "err", err)
+		return
+	}
+	if cmp < 0 {
+		info.Outdated = true
+		consensusClientOutdatedGauge.Update(1)
+		log.Warn("Consensus client version below required minimum for active forks", "client", info.Version.String(), "minimum", minimum)
+		return
+	}
+	consensusClientOutdatedGauge.Update(0)
+}
+
+// compareVersions compares two semantic versions, optionally prefixed with "v"
+// and ignoring pre-release and build metadata.
+func compareVersions(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := range va {
+		switch {
+		case va[i] < vb[i]:
+			return -1, nil
+		case va[i] > vb[i]:
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func parseVersion(version string) ([3]uint64, error) {
+	var parsed [3]uint64
+
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) > 3 {
+		return parsed, fmt.Errorf("invalid version %q", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return parsed, fmt.Errorf("invalid version %q", version)
+		}
+		parsed[i] = n
+	}
+	return parsed, nil
+}
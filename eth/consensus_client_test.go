@@ -0,0 +1,31 @@
+package eth
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.4.2", "v1.4.2", 0},
+		{"v1.4.2", "1.4.2", 0},
+		{"v1.4.2-rc.1", "v1.4.2", 0},
+		{"v1.4.1", "v1.4.2", -1},
+		{"v1.10.0", "v1.9.9", 1},
+		{"v2", "v1.9.9", 1},
+		{"v1.4.2+abcdef", "v1.5.0", -1},
+	}
+	for _, tt := range tests {
+		have, err := compareVersions(tt.a, tt.b)
+		if err != nil {
+			t.Errorf("%s vs %s: unexpected error: %v", tt.a, tt.b, err)
+			continue
+		}
+		if have != tt.want {
+			t.Errorf("%s vs %s: comparison mismatch: have %d, want %d", tt.a, tt.b, have, tt.want)
+		}
+	}
+	if _, err := compareVersions("v1.x", "v1.0.0"); err == nil {
+		t.Error("invalid version accepted")
+	}
+}
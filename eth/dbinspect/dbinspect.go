@@ -0,0 +1,189 @@
+// Package dbinspect implements the inspection of the node database in the
+// background, measuring the size of each category of data while the node keeps
+// running, as well as its growth between successive inspections.
+package dbinspect
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	inspectionTimer = metrics.NewRegisteredTimer("dbinspect/duration", nil)
+	totalSizeGauge  = metrics.NewRegisteredGauge("dbinspect/size", nil)
+)
+
+var (
+	errRunning = errors.New("database inspection already in progress")
+	errStopped = errors.New("database inspector stopped")
+)
+
+// Database defines the minimal set of methods needed to inspect the database.
+type Database interface {
+	ethdb.Iteratee
+	ethdb.AncientReader
+}
+
+// Table is the size of a category of data, and its growth since the previous
+// inspection.
+type Table struct {
+	rawdb.DatabaseStat
+	Growth float64 `json:"growth"` // Bytes added per hour, 0 if not inspected before
+}
+
+// Report is the outcome of the last inspection completed.
+type Report struct {
+	Running    bool      `json:"running"`    // Whether an inspection is in progress
+	Inspected  time.Time `json:"inspected"`  // Completion of the last inspection, zero if none
+	ElapsedSec int64     `json:"elapsedSec"` // Duration of the last inspection
+	Size       uint64    `json:"size"`       // Bytes stored in total
+	Growth     float64   `json:"growth"`     // Bytes added per hour in total
+	Tables     []*Table  `json:"tables"`
+}
+
+// inspection is the outcome of a completed inspection.
+type inspection struct {
+	done    time.Time
+	elapsed time.Duration
+	stats   []rawdb.DatabaseStat
+}
+
+// size returns the bytes stored in total.
+func (in *inspection) size() uint64 {
+	var size uint64
+	for _, stat := range in.stats {
+		size += stat.Size
+	}
+	return size
+}
+
+// Inspector traverses the database in the background on request, one inspection
+// at a time, keeping the outcome of the last two to measure the growth.
+type Inspector struct {
+	db Database
+
+	lock    sync.Mutex
+	running bool
+	last    *inspection // Last inspection completed
+	prev    *inspection // Inspection completed before the last one
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates an inspector of the given database.
+func New(db Database) *Inspector {
+	return &Inspector{
+		db:   db,
+		quit: make(chan struct{}),
+	}
+}
+
+// Start begins an inspection in the background, failing if one is already in
+// progress.
+func (in *Inspector) Start() error {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+
+	if in.running {
+		return errRunning
+	}
+	select {
+	case <-in.quit:
+		return errStopped
+	default:
+	}
+	in.running = true
+	in.wg.Add(1)
+	go in.inspect()
+	return nil
+}
+
+// Stop aborts the inspection in progress, if any.
+func (in *Inspector) Stop() {
+	in.lock.Lock()
+	close(in.quit)
+	in.lock.Unlock()
+	in.wg.Wait()
+}
+
+// inspect traverses the key-value store and measures the chain freezer.
+func (in *Inspector) inspect() {
+	defer in.wg.Done()
+
+	log.Info("Inspecting database")
+	start := time.Now()
+	stats, err := rawdb.InspectKeyValueStore(in.db, nil, nil, in.quit)
+	if err == nil {
+		var ancients []rawdb.DatabaseStat
+		if ancients, err = rawdb.InspectChainFreezer(in.db); err == nil {
+			stats = append(stats, ancients...)
+		}
+	}
+	in.lock.Lock()
+	defer in.lock.Unlock()
+
+	in.running = false
+	if err != nil {
+		log.Warn("Database inspection failed", "err", err)
+		return
+	}
+	in.prev, in.last = in.last, &inspection{
+		done:    time.Now(),
+		elapsed: time.Since(start),
+		stats:   stats,
+	}
+	inspectionTimer.Update(in.last.elapsed)
+	totalSizeGauge.Update(int64(in.last.size()))
+	log.Info("Inspected database", "size", common.StorageSize(in.last.size()), "elapsed", common.PrettyDuration(in.last.elapsed))
+}
+
+// Report returns the outcome of the last inspection completed, with the growth
+// since the previous one.
+func (in *Inspector) Report() *Report {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+
+	report := &Report{Running: in.running, Tables: []*Table{}}
+	if in.last == nil {
+		return report
+	}
+	report.Inspected = in.last.done
+	report.ElapsedSec = int64(in.last.elapsed / time.Second)
+	report.Size = in.last.size()
+
+	var (
+		hours    float64
+		previous = make(map[[2]string]uint64)
+	)
+	if in.prev != nil {
+		hours = in.last.done.Sub(in.prev.done).Hours()
+		for _, stat := range in.prev.stats {
+			previous[[2]string{stat.Database, stat.Category}] = stat.Size
+		}
+		report.Growth = growth(report.Size, in.prev.size(), hours)
+	}
+	for _, stat := range in.last.stats {
+		table := &Table{DatabaseStat: stat}
+		if size, ok := previous[[2]string{stat.Database, stat.Category}]; ok {
+			table.Growth = growth(stat.Size, size, hours)
+		}
+		report.Tables = append(report.Tables, table)
+	}
+	return report
+}
+
+// growth returns the bytes added per hour, negative if shrinking.
+func growth(size, prev uint64, hours float64) float64 {
+	if hours <= 0 {
+		return 0
+	}
+	return (float64(size) - float64(prev)) / hours
+}
@@ -0,0 +1,65 @@
+package dbinspect
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+)
+
+// waitInspection starts an inspection and waits for it to complete.
+func waitInspection(t *testing.T, in *Inspector) *Report {
+	if err := in.Start(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if report := in.Report(); !report.Running {
+			return report
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("inspection not completed")
+	return nil
+}
+
+func TestInspector(t *testing.T) {
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), t.TempDir(), "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	in := New(db)
+	defer in.Stop()
+
+	if report := in.Report(); report.Running || !report.Inspected.IsZero() || len(report.Tables) != 0 {
+		t.Fatalf("report before inspection: %+v", report)
+	}
+	rawdb.WriteCode(db, common.Hash{0x01}, make([]byte, 100))
+	first := waitInspection(t, in)
+	if first.Inspected.IsZero() || first.Size == 0 || first.Growth != 0 {
+		t.Fatalf("first report mismatch: %+v", first)
+	}
+	codes := func(report *Report) *Table {
+		for _, table := range report.Tables {
+			if table.Category == "Contract codes" {
+				return table
+			}
+		}
+		t.Fatalf("contract codes not reported")
+		return nil
+	}
+	if table := codes(first); table.Items != 1 || table.Size != 100+1+common.HashLength {
+		t.Fatalf("contract codes mismatch: %+v", table)
+	}
+	// The growth is measured against the previous inspection
+	rawdb.WriteCode(db, common.Hash{0x02}, make([]byte, 100))
+	second := waitInspection(t, in)
+	if table := codes(second); table.Items != 2 || table.Growth <= 0 {
+		t.Fatalf("contract codes growth mismatch: %+v", table)
+	}
+	if second.Growth <= 0 {
+		t.Fatalf("total growth mismatch: %+v", second)
+	}
+}
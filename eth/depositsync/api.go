@@ -0,0 +1,209 @@
+package depositsync
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxWithdrawalRange is the maximum number of blocks scanned by a withdrawal query.
+const maxWithdrawalRange = 10000
+
+// API is the RPC API of the deposit-only node.
+type API struct {
+	s *Syncer
+}
+
+// SyncStatus is the progress of the deposit-only sync.
+type SyncStatus struct {
+	StartingBlock hexutil.Uint64 `json:"startingBlock"`
+	CurrentBlock  hexutil.Uint64 `json:"currentBlock"`
+	CurrentHash   common.Hash    `json:"currentHash"`
+	HighestBlock  hexutil.Uint64 `json:"highestBlock"`
+}
+
+// Deposit is a deposit transaction along with the outcome of its execution.
+type Deposit struct {
+	BlockHash        common.Hash        `json:"blockHash"`
+	BlockNumber      hexutil.Uint64     `json:"blockNumber"`
+	TransactionIndex hexutil.Uint64     `json:"transactionIndex"`
+	Transaction      *types.Transaction `json:"transaction"`
+	Status           hexutil.Uint64     `json:"status"`
+	DepositNonce     *hexutil.Uint64    `json:"depositNonce,omitempty"`
+	Logs             []*types.Log       `json:"logs"`
+}
+
+// Withdrawal is a withdrawal initiated on L2, to be proven and finalized on L1.
+type Withdrawal struct {
+	BlockHash        common.Hash                   `json:"blockHash"`
+	BlockNumber      hexutil.Uint64                `json:"blockNumber"`
+	TransactionHash  common.Hash                   `json:"transactionHash"`
+	TransactionIndex hexutil.Uint64                `json:"transactionIndex"`
+	LogIndex         hexutil.Uint64                `json:"logIndex"`
+	WithdrawalHash   common.Hash                   `json:"withdrawalHash"`
+	Withdrawal       *ethapi.WithdrawalTransaction `json:"withdrawal"`
+}
+
+// SyncStatus returns the progress of the deposit-only sync.
+func (api *API) SyncStatus() *SyncStatus {
+	status := &SyncStatus{
+		StartingBlock: hexutil.Uint64(api.s.config.From),
+		HighestBlock:  hexutil.Uint64(api.s.remoteHead.Load()),
+	}
+	if head := api.s.Head(); head != nil {
+		status.CurrentBlock = hexutil.Uint64(head.Number.Uint64())
+		status.CurrentHash = head.Hash()
+	}
+	return status
+}
+
+// GetDeposits returns the deposit transactions of the given block.
+func (api *API) GetDeposits(number rpc.BlockNumber) ([]*Deposit, error) {
+	api.s.lock.RLock()
+	defer api.s.lock.RUnlock()
+
+	header, err := api.header(number)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := api.s.readRecord(header.Hash(), header.Number.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	return newDeposits(header, rec), nil
+}
+
+// GetDeposit returns the deposit transaction with the given hash.
+func (api *API) GetDeposit(hash common.Hash) (*Deposit, error) {
+	api.s.lock.RLock()
+	defer api.s.lock.RUnlock()
+
+	number := rawdb.ReadTxLookupEntry(api.s.db, hash)
+	if number == nil {
+		return nil, nil
+	}
+	header, err := api.header(rpc.BlockNumber(*number))
+	if err != nil {
+		return nil, err
+	}
+	rec, err := api.s.readRecord(header.Hash(), header.Number.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	for _, deposit := range newDeposits(header, rec) {
+		if deposit.Transaction.Hash() == hash {
+			return deposit, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetWithdrawals returns the withdrawals initiated in the given range of blocks,
+// both ends included.
+func (api *API) GetWithdrawals(from, to rpc.BlockNumber) ([]*Withdrawal, error) {
+	api.s.lock.RLock()
+	defer api.s.lock.RUnlock()
+
+	first, err := api.header(from)
+	if err != nil {
+		return nil, err
+	}
+	last, err := api.header(to)
+	if err != nil {
+		return nil, err
+	}
+	start, end := first.Number.Uint64(), last.Number.Uint64()
+	if start > end {
+		return nil, errors.New("invalid block range")
+	}
+	if end-start >= maxWithdrawalRange {
+		return nil, fmt.Errorf("block range too large, maximum %d blocks", maxWithdrawalRange)
+	}
+	withdrawals := []*Withdrawal{}
+	for number := start; number <= end; number++ {
+		hash := rawdb.ReadCanonicalHash(api.s.db, number)
+		rec, err := api.s.readRecord(hash, number)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range rec.Withdrawals {
+			withdrawal, err := ethapi.ParseMessagePassed(w.Log)
+			if err != nil {
+				return nil, fmt.Errorf("block #%d, transaction %x: %w", number, w.TxHash, err)
+			}
+			withdrawals = append(withdrawals, &Withdrawal{
+				BlockHash:        hash,
+				BlockNumber:      hexutil.Uint64(number),
+				TransactionHash:  w.TxHash,
+				TransactionIndex: hexutil.Uint64(w.TxIndex),
+				LogIndex:         hexutil.Uint64(w.LogIndex),
+				WithdrawalHash:   withdrawal.Hash(),
+				Withdrawal:       withdrawal,
+			})
+		}
+	}
+	return withdrawals, nil
+}
+
+// header resolves the given block number to a synced header.
+func (api *API) header(number rpc.BlockNumber) (*types.Header, error) {
+	switch {
+	case number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber:
+		if head := api.s.Head(); head != nil {
+			return head, nil
+		}
+		return nil, errors.New("no block synced")
+	case number < 0:
+		return nil, fmt.Errorf("block tag %d not supported", number)
+	}
+	hash := rawdb.ReadCanonicalHash(api.s.db, uint64(number))
+	if hash == (common.Hash{}) {
+		return nil, fmt.Errorf("block #%d not synced", number)
+	}
+	header := rawdb.ReadHeader(api.s.db, hash, uint64(number))
+	if header == nil {
+		return nil, fmt.Errorf("block #%d not synced", number)
+	}
+	return header, nil
+}
+
+// newDeposits assembles the deposits of a synced block. Deposit transactions
+// come first in a block, so their position in the record is their index.
+func newDeposits(header *types.Header, rec *record) []*Deposit {
+	var (
+		hash     = header.Hash()
+		number   = header.Number.Uint64()
+		deposits = make([]*Deposit, 0, len(rec.Deposits))
+		logIndex uint
+	)
+	for i, tx := range rec.Deposits {
+		receipt := rec.Receipts[i]
+		deposit := &Deposit{
+			BlockHash:        hash,
+			BlockNumber:      hexutil.Uint64(number),
+			TransactionIndex: hexutil.Uint64(i),
+			Transaction:      tx,
+			Status:           hexutil.Uint64(receipt.Status),
+			DepositNonce:     (*hexutil.Uint64)(receipt.DepositNonce),
+			Logs:             make([]*types.Log, len(receipt.Logs)),
+		}
+		for j, l := range receipt.Logs {
+			l := *l
+			l.BlockNumber = number
+			l.BlockHash = hash
+			l.TxHash = tx.Hash()
+			l.TxIndex = uint(i)
+			l.Index = logIndex
+			deposit.Logs[j] = &l
+			logIndex++
+		}
+		deposits = append(deposits, deposit)
+	}
+	return deposits
+}
@@ -0,0 +1,358 @@
+// Package depositsync implements a deposit-only node following an L2 node over
+// RPC. It only stores the headers of the chain, the deposit transactions with
+// their receipts and the withdrawals initiated on L2, for the lightweight
+// monitoring of the Oasys bridge.
+package depositsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// requestTimeout is the time allowed to retrieve a block from the endpoint.
+	requestTimeout = 10 * time.Second
+
+	// retryInterval is the time waited after a failed sync round.
+	retryInterval = 5 * time.Second
+)
+
+var headGauge = metrics.NewRegisteredGauge("depositsync/head", nil)
+
+// Config are the configuration parameters of the deposit-only sync.
+type Config struct {
+	From         uint64        // Number of the first block synced
+	PollInterval time.Duration // Interval between the checks of the endpoint head
+}
+
+// DefaultConfig contains the default configuration of the deposit-only sync.
+var DefaultConfig = Config{
+	PollInterval: 2 * time.Second,
+}
+
+// record is the storage encoding of the deposits and withdrawals of a block.
+type record struct {
+	Deposits    []*types.Transaction
+	Receipts    []*types.ReceiptForStorage
+	Withdrawals []*withdrawalRecord
+}
+
+// withdrawalRecord is a MessagePassed event emitted by a transaction of a block.
+type withdrawalRecord struct {
+	TxHash   common.Hash
+	TxIndex  uint64
+	LogIndex uint64
+	Log      *types.Log
+}
+
+// Syncer follows the chain of an L2 node, downloading the headers and the
+// receipts of its blocks but only storing the deposits and withdrawals. The
+// receipts are verified against the receipt root of their header. Like the
+// headers, the deposit transactions are trusted as served by the endpoint: the
+// receipt root does not commit to the transaction hashes, so matching them with
+// their receipts only catches an inconsistent endpoint, not a forged deposit.
+type Syncer struct {
+	db     ethdb.Database
+	client *rpc.Client
+	config Config
+
+	remoteHead atomic.Uint64 // Last head number reported by the endpoint
+	lock       sync.RWMutex  // Lock protecting the stored chain against concurrent reads during rewinds
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a deposit-only syncer storing into the given database the chain
+// of the L2 node behind the client.
+func New(db ethdb.Database, client *rpc.Client, config Config) *Syncer {
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultConfig.PollInterval
+	}
+	return &Syncer{
+		db:     db,
+		client: client,
+		config: config,
+		quit:   make(chan struct{}),
+	}
+}
+
+// Start implements node.Lifecycle, starting the background sync.
+func (s *Syncer) Start() error {
+	s.wg.Add(1)
+	go s.loop()
+	return nil
+}
+
+// Stop implements node.Lifecycle, terminating the background sync.
+func (s *Syncer) Stop() error {
+	close(s.quit)
+	s.wg.Wait()
+	return nil
+}
+
+// APIs returns the RPC APIs of the deposit-only node.
+func (s *Syncer) APIs() []rpc.API {
+	return []rpc.API{{
+		Namespace: "deposits",
+		Service:   &API{s},
+	}}
+}
+
+// Head returns the last block synced, nil if none.
+func (s *Syncer) Head() *types.Header {
+	return rawdb.ReadHeadHeader(s.db)
+}
+
+func (s *Syncer) loop() {
+	defer s.wg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			wait := s.config.PollInterval
+			if err := s.sync(); err != nil {
+				log.Warn("Deposit sync failed", "err", err)
+				wait = retryInterval
+			}
+			timer.Reset(wait)
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// sync downloads the blocks up to the head of the endpoint.
+func (s *Syncer) sync() error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	var number hexutil.Uint64
+	err := s.client.CallContext(ctx, &number, "eth_blockNumber")
+	cancel()
+	if err != nil {
+		return err
+	}
+	s.remoteHead.Store(uint64(number))
+
+	logged := time.Now()
+	for {
+		next := s.config.From
+		if head := s.Head(); head != nil {
+			next = head.Number.Uint64() + 1
+		}
+		if next > uint64(number) {
+			return nil
+		}
+		if err := s.syncBlock(next); err != nil {
+			return fmt.Errorf("block #%d: %w", next, err)
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Syncing deposits", "number", next, "head", uint64(number))
+			logged = time.Now()
+		}
+		select {
+		case <-s.quit:
+			return nil
+		default:
+		}
+	}
+}
+
+// syncBlock downloads the block with the given number, storing it as the new
+// head if it extends the stored chain. Otherwise the stored head is rewound.
+func (s *Syncer) syncBlock(number uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	header, err := s.fetchHeader(ctx, number)
+	if err != nil {
+		return err
+	}
+	if head := s.Head(); head != nil && header.ParentHash != head.Hash() {
+		return s.rewind(head)
+	}
+	receipts, err := s.fetchReceipts(ctx, header)
+	if err != nil {
+		return err
+	}
+	rec, err := s.fetchDeposits(ctx, receipts)
+	if err != nil {
+		return err
+	}
+	blob, err := rlp.EncodeToBytes(rec)
+	if err != nil {
+		return err
+	}
+	var (
+		hash   = header.Hash()
+		hashes = make([]common.Hash, len(rec.Deposits))
+	)
+	for i, tx := range rec.Deposits {
+		hashes[i] = tx.Hash()
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	batch := s.db.NewBatch()
+	rawdb.WriteHeader(batch, header)
+	rawdb.WriteCanonicalHash(batch, hash, number)
+	rawdb.WriteDepositRecordRLP(batch, hash, number, blob)
+	rawdb.WriteTxLookupEntries(batch, number, hashes)
+	rawdb.WriteHeadHeaderHash(batch, hash)
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	headGauge.Update(int64(number))
+	return nil
+}
+
+// rewind drops the given stored head, replaced on the endpoint by a reorg.
+func (s *Syncer) rewind(head *types.Header) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var (
+		hash   = head.Hash()
+		number = head.Number.Uint64()
+		batch  = s.db.NewBatch()
+	)
+	if rec, err := s.readRecord(hash, number); err == nil {
+		hashes := make([]common.Hash, len(rec.Deposits))
+		for i, tx := range rec.Deposits {
+			hashes[i] = tx.Hash()
+		}
+		rawdb.DeleteTxLookupEntries(batch, hashes)
+	}
+	rawdb.DeleteDepositRecord(batch, hash, number)
+	rawdb.DeleteCanonicalHash(batch, number)
+	if number > s.config.From {
+		rawdb.WriteHeadHeaderHash(batch, head.ParentHash)
+	} else {
+		rawdb.WriteHeadHeaderHash(batch, common.Hash{})
+	}
+	log.Warn("Rewinding reorged deposit block", "number", number, "hash", hash)
+	return batch.Write()
+}
+
+// fetchHeader retrieves the header with the given number, checking the hash
+// reported by the endpoint.
+func (s *Syncer) fetchHeader(ctx context.Context, number uint64) (*types.Header, error) {
+	var raw json.RawMessage
+	if err := s.client.CallContext(ctx, &raw, "eth_getHeaderByNumber", hexutil.Uint64(number)); err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, errors.New("header not found")
+	}
+	header := new(types.Header)
+	if err := json.Unmarshal(raw, header); err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	var reported struct {
+		Hash common.Hash `json:"hash"`
+	}
+	if err := json.Unmarshal(raw, &reported); err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	if header.Number == nil || header.Number.Uint64() != number {
+		return nil, fmt.Errorf("header number mismatch: have %v, want %d", header.Number, number)
+	}
+	if hash := header.Hash(); hash != reported.Hash {
+		return nil, fmt.Errorf("header hash mismatch: reported %x, computed %x", reported.Hash, hash)
+	}
+	return header, nil
+}
+
+// fetchReceipts retrieves the receipts of the block, verified against the
+// receipt root of the header.
+func (s *Syncer) fetchReceipts(ctx context.Context, header *types.Header) (types.Receipts, error) {
+	var receipts types.Receipts
+	if err := s.client.CallContext(ctx, &receipts, "eth_getBlockReceipts", header.Hash()); err != nil {
+		return nil, err
+	}
+	if root := types.DeriveSha(receipts, trie.NewStackTrie(nil)); root != header.ReceiptHash {
+		return nil, fmt.Errorf("receipt root mismatch: header %x, computed %x", header.ReceiptHash, root)
+	}
+	return receipts, nil
+}
+
+// fetchDeposits retrieves the deposit transactions of the given receipts and
+// collects the withdrawals initiated in the block. The transactions are only
+// checked for consistency with the hashes reported in the receipts.
+func (s *Syncer) fetchDeposits(ctx context.Context, receipts types.Receipts) (*record, error) {
+	var (
+		rec  = new(record)
+		reqs []rpc.BatchElem
+		logs uint64
+	)
+	for i, receipt := range receipts {
+		if receipt.Type == types.DepositTxType {
+			rec.Receipts = append(rec.Receipts, (*types.ReceiptForStorage)(receipt))
+			reqs = append(reqs, rpc.BatchElem{
+				Method: "eth_getTransactionByHash",
+				Args:   []interface{}{receipt.TxHash},
+				Result: new(types.Transaction),
+			})
+		}
+		for _, l := range receipt.Logs {
+			if l.Address == params.OptimismL2ToL1MessagePasser && len(l.Topics) == 4 && l.Topics[0] == ethapi.MessagePassedTopic {
+				rec.Withdrawals = append(rec.Withdrawals, &withdrawalRecord{
+					TxHash:   receipt.TxHash,
+					TxIndex:  uint64(i),
+					LogIndex: logs,
+					Log:      l,
+				})
+			}
+			logs++
+		}
+	}
+	if len(reqs) == 0 {
+		return rec, nil
+	}
+	if err := s.client.BatchCallContext(ctx, reqs); err != nil {
+		return nil, err
+	}
+	for i, req := range reqs {
+		if req.Error != nil {
+			return nil, fmt.Errorf("deposit %x: %w", rec.Receipts[i].TxHash, req.Error)
+		}
+		tx := req.Result.(*types.Transaction)
+		if !tx.IsDepositTx() || tx.Hash() != rec.Receipts[i].TxHash {
+			return nil, fmt.Errorf("deposit %x: transaction inconsistent with its receipt", rec.Receipts[i].TxHash)
+		}
+		rec.Deposits = append(rec.Deposits, tx)
+	}
+	return rec, nil
+}
+
+// readRecord retrieves the deposits and withdrawals of a stored block.
+func (s *Syncer) readRecord(hash common.Hash, number uint64) (*record, error) {
+	blob := rawdb.ReadDepositRecordRLP(s.db, hash, number)
+	if len(blob) == 0 {
+		return nil, fmt.Errorf("block #%d not synced", number)
+	}
+	rec := new(record)
+	if err := rlp.DecodeBytes(blob, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
@@ -0,0 +1,189 @@
+package depositsync
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// testBackend serves the eth methods used by the syncer from a test chain.
+type testBackend struct {
+	lock     sync.Mutex
+	headers  []*types.Header
+	receipts map[common.Hash]types.Receipts
+	txs      map[common.Hash]*types.Transaction
+}
+
+func (b *testBackend) BlockNumber() hexutil.Uint64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return hexutil.Uint64(len(b.headers) - 1)
+}
+
+func (b *testBackend) GetHeaderByNumber(number hexutil.Uint64) *types.Header {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if int(number) >= len(b.headers) {
+		return nil
+	}
+	return b.headers[number]
+}
+
+func (b *testBackend) GetBlockReceipts(hash common.Hash) types.Receipts {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.receipts[hash]
+}
+
+func (b *testBackend) GetTransactionByHash(hash common.Hash) *types.Transaction {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.txs[hash]
+}
+
+// setChain replaces the chain served by the backend with n blocks, forking
+// from the given number of blocks of the current chain. Each block contains a
+// deposit and a transaction initiating a withdrawal.
+func (b *testBackend) setChain(keep, n int, seed byte) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.headers = b.headers[:keep]
+	parent := common.Hash{}
+	if keep > 0 {
+		parent = b.headers[keep-1].Hash()
+	}
+	for i := keep; i < n; i++ {
+		nonce := uint64(i)
+		deposit := types.NewTx(&types.DepositTx{
+			SourceHash: common.Hash{seed, byte(i)},
+			From:       common.Address{0x01},
+			To:         &common.Address{0x02},
+			Value:      big.NewInt(int64(i)),
+			Gas:        21000,
+		})
+		tx := types.NewTransaction(uint64(i), common.Address{0x03}, big.NewInt(1), 100000, big.NewInt(1), nil)
+		receipts := types.Receipts{
+			{Type: types.DepositTxType, Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*types.Log{}, TxHash: deposit.Hash(), DepositNonce: &nonce},
+			{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 121000, Logs: []*types.Log{messagePassedLog(uint64(i))}, TxHash: tx.Hash()},
+		}
+		for _, receipt := range receipts {
+			receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		}
+		header := &types.Header{
+			ParentHash:  parent,
+			Number:      big.NewInt(int64(i)),
+			Difficulty:  new(big.Int),
+			GasLimit:    30_000_000,
+			Extra:       []byte{seed},
+			ReceiptHash: types.DeriveSha(receipts, trie.NewStackTrie(nil)),
+		}
+		b.headers = append(b.headers, header)
+		b.receipts[header.Hash()] = receipts
+		b.txs[deposit.Hash()] = deposit
+		parent = header.Hash()
+	}
+}
+
+func messagePassedLog(nonce uint64) *types.Log {
+	withdrawal := &ethapi.WithdrawalTransaction{
+		Nonce:    (*hexutil.Big)(new(big.Int).SetUint64(nonce)),
+		Sender:   common.Address{0x03},
+		Target:   common.Address{0x04},
+		Value:    (*hexutil.Big)(big.NewInt(1)),
+		GasLimit: (*hexutil.Big)(big.NewInt(100000)),
+		Data:     []byte{0xca, 0xfe},
+	}
+	data := make([]byte, 0, 7*32)
+	data = append(data, common.BigToHash(withdrawal.Value.ToInt()).Bytes()...)
+	data = append(data, common.BigToHash(withdrawal.GasLimit.ToInt()).Bytes()...)
+	data = append(data, common.BigToHash(big.NewInt(4*32)).Bytes()...)
+	data = append(data, withdrawal.Hash().Bytes()...)
+	data = append(data, common.BigToHash(big.NewInt(int64(len(withdrawal.Data)))).Bytes()...)
+	data = append(data, common.RightPadBytes(withdrawal.Data, 32)...)
+	return &types.Log{
+		Address: params.OptimismL2ToL1MessagePasser,
+		Topics: []common.Hash{
+			ethapi.MessagePassedTopic,
+			common.BigToHash(withdrawal.Nonce.ToInt()),
+			common.BytesToHash(withdrawal.Sender.Bytes()),
+			common.BytesToHash(withdrawal.Target.Bytes()),
+		},
+		Data: data,
+	}
+}
+
+func TestDepositSync(t *testing.T) {
+	backend := &testBackend{
+		receipts: make(map[common.Hash]types.Receipts),
+		txs:      make(map[common.Hash]*types.Transaction),
+	}
+	backend.setChain(0, 4, 0)
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", backend); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	syncer := New(rawdb.NewMemoryDatabase(), client, Config{From: 1})
+	if err := syncer.sync(); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	api := &API{syncer}
+	if status := api.SyncStatus(); status.CurrentBlock != 3 || status.CurrentHash != backend.headers[3].Hash() {
+		t.Fatalf("sync status mismatch: have #%d %x", status.CurrentBlock, status.CurrentHash)
+	}
+	if _, err := api.GetDeposits(0); err == nil {
+		t.Fatalf("block before the starting one served")
+	}
+	deposits, err := api.GetDeposits(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deposits) != 1 || deposits[0].Transaction.SourceHash() != (common.Hash{0, 2}) || deposits[0].DepositNonce == nil || *deposits[0].DepositNonce != 2 {
+		t.Fatalf("deposits mismatch: %+v", deposits)
+	}
+	stale := deposits[0].Transaction.Hash()
+	if deposit, err := api.GetDeposit(stale); err != nil || deposit == nil || deposit.BlockNumber != 2 {
+		t.Fatalf("deposit lookup failed: %v %+v", err, deposit)
+	}
+	withdrawals, err := api.GetWithdrawals(1, rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(withdrawals) != 3 || withdrawals[0].TransactionIndex != 1 || withdrawals[2].Withdrawal.Nonce.ToInt().Uint64() != 3 {
+		t.Fatalf("withdrawals mismatch: %+v", withdrawals)
+	}
+	// Reorg the endpoint chain from block 2, the syncer follows it
+	backend.setChain(2, 5, 1)
+	if err := syncer.sync(); err != nil {
+		t.Fatalf("sync after reorg failed: %v", err)
+	}
+	if head := syncer.Head(); head.Hash() != backend.headers[4].Hash() {
+		t.Fatalf("head mismatch after reorg: have #%d %x", head.Number, head.Hash())
+	}
+	if deposit, _ := api.GetDeposit(stale); deposit != nil {
+		t.Fatalf("reorged deposit still served")
+	}
+	if deposits, err := api.GetDeposits(2); err != nil || deposits[0].Transaction.SourceHash() != (common.Hash{1, 2}) {
+		t.Fatalf("reorged deposits mismatch: %v", err)
+	}
+	// Receipts not matching their header are rejected
+	backend.setChain(5, 6, 2)
+	backend.receipts[backend.headers[5].Hash()][0].Status = types.ReceiptStatusFailed
+	if err := syncer.sync(); err == nil {
+		t.Fatalf("invalid receipts accepted")
+	}
+}
@@ -0,0 +1,117 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// drainHeadPollInterval is how often the chain head is checked while waiting
+	// for the rollup node to insert the last built block.
+	drainHeadPollInterval = 100 * time.Millisecond
+
+	// drainNotifyTimeout is the time allowed for each replica to acknowledge the
+	// drain notification.
+	drainNotifyTimeout = 2 * time.Second
+)
+
+// drainNotification is posted to the configured replicas when the node drains
+// for shutdown.
+type drainNotification struct {
+	Event  string      `json:"event"`
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+}
+
+// Drain implements node.Drainer. It winds down block production before the RPC
+// endpoints are closed on shutdown: new payload builds are refused, the payload
+// in flight is given time to be delivered to and inserted by the rollup node,
+// the transaction journal is regenerated and the configured replicas are
+// notified, so that stopping a sequencer mid-slot does not miss a block.
+func (s *Ethereum) Drain() {
+	timeout := s.config.RollupDrainTimeout
+	if timeout == 0 {
+		return
+	}
+	var (
+		start    = time.Now()
+		deadline = start.Add(timeout)
+	)
+	log.Info("Draining block production for shutdown", "timeout", common.PrettyDuration(timeout))
+
+	number, delivered := s.miner.Drain(timeout)
+	if !delivered {
+		log.Warn("In-flight payload not delivered before drain timeout")
+	}
+	// Give the rollup node time to insert the last block we built
+	if delivered && number > 0 {
+		for s.blockchain.CurrentBlock().Number.Uint64() < number && time.Now().Before(deadline) {
+			time.Sleep(drainHeadPollInterval)
+		}
+		if head := s.blockchain.CurrentBlock().Number.Uint64(); head < number {
+			log.Warn("Last built block not inserted before drain timeout", "number", number, "head", head)
+		}
+	}
+	if err := s.txPool.Rejournal(); err != nil {
+		log.Warn("Failed to flush transaction journal", "err", err)
+	}
+	s.notifyDrain()
+
+	log.Info("Drained block production", "elapsed", common.PrettyDuration(time.Since(start)))
+}
+
+// notifyDrain posts the drain notification to all configured replicas.
+func (s *Ethereum) notifyDrain() {
+	if len(s.config.RollupDrainNotify) == 0 {
+		return
+	}
+	head := s.blockchain.CurrentBlock()
+	body, err := json.Marshal(&drainNotification{
+		Event:  "drain",
+		Number: head.Number.Uint64(),
+		Hash:   head.Hash(),
+	})
+	if err != nil {
+		log.Warn("Failed to encode drain notification", "err", err)
+		return
+	}
+	var wg sync.WaitGroup
+	for _, endpoint := range s.config.RollupDrainNotify {
+		wg.Add(1)
+		go func(endpoint string) {
+			defer wg.Done()
+			if err := postDrainNotification(endpoint, body); err != nil {
+				log.Warn("Failed to notify replica of drain", "endpoint", redactURL(endpoint), "err", err)
+			}
+		}(endpoint)
+	}
+	wg.Wait()
+}
+
+func postDrainNotification(endpoint string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), drainNotifyTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("replica returned %s", res.Status)
+	}
+	return nil
+}
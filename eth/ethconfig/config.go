@@ -78,6 +78,18 @@ var Defaults = Config{
 	RPCEVMTimeout:      5 * time.Second,
 	GPO:                FullNodeGPO,
 	RPCTxFeeCap:        1, // 1 ether
+	RPCCacheTTL:        10 * time.Minute,
+
+	RollupHealthMaxHeadAge:   time.Minute,
+	RollupHealthMaxEngineAge: 2 * time.Minute,
+	RollupDrainTimeout:       15 * time.Second,
+
+	RollupReplicaCheckInterval: 2 * time.Second,
+
+	RollupForkRehearsalInterval: 10 * time.Minute,
+
+	RollupDATimeout:       time.Second,
+	RollupDAFailurePolicy: "defer",
 }
 
 //go:generate go run github.com/fjl/gencodec -type Config -formats toml -out gen_config.go
@@ -102,9 +114,10 @@ type Config struct {
 	NoPrefetch bool // Whether to disable prefetching and only load state on demand
 
 	// Deprecated, use 'TransactionHistory' instead.
-	TxLookupLimit      uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
-	TransactionHistory uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
-	StateHistory       uint64 `toml:",omitempty"` // The maximum number of blocks from head whose state histories are reserved.
+	TxLookupLimit          uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
+	TransactionHistory     uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
+	TransactionSenderIndex bool   `toml:",omitempty"` // Whether to also index the transactions by sender and nonce.
+	StateHistory           uint64 `toml:",omitempty"` // The maximum number of blocks from head whose state histories are reserved.
 
 	// State scheme represents the scheme used to store ethereum states and trie
 	// nodes on top. It can be 'hash', 'path', or none which means use the scheme
@@ -130,11 +143,12 @@ type Config struct {
 	DatabaseCache      int
 	DatabaseFreezer    string
 
-	TrieCleanCache int
-	TrieDirtyCache int
-	TrieTimeout    time.Duration
-	SnapshotCache  int
-	Preimages      bool
+	TrieCleanCache  int
+	TrieDirtyCache  int
+	TrieTimeout     time.Duration
+	SnapshotCache   int
+	SnapshotGenRate int
+	Preimages       bool
 
 	// This is the number of blocks for which logs will be cached in the filter system.
 	FilterLogCacheSize int
@@ -161,17 +175,41 @@ type Config struct {
 	// RPCEVMTimeout is the global timeout for eth-call.
 	RPCEVMTimeout time.Duration
 
+	// RPCEVMMemoryLimit is the global memory limit (in bytes) for eth-call, across all
+	// call frames of the execution.
+	RPCEVMMemoryLimit uint64
+
+	// RPCEVMReadLimit is the global limit on the number of accounts, storage slots
+	// and codes eth-call may load from the database.
+	RPCEVMReadLimit uint64
+
 	// RPCTxFeeCap is the global transaction fee(price * gaslimit) cap for
 	// send-transaction variants. The unit is ether.
 	RPCTxFeeCap float64
 
+	// RPCLatestBlockTag is the head ("unsafe", "safe" or "finalized") that the "latest"
+	// block tag resolves to for RPC consumers. Defaults to the unsafe head.
+	// Subscriptions and filters always follow the unsafe head.
+	RPCLatestBlockTag string `toml:",omitempty"`
+
+	// RPCLatestBlockTagNamespaces overrides RPCLatestBlockTag per RPC namespace.
+	RPCLatestBlockTagNamespaces map[string]string `toml:",omitempty"`
+
+	// RPCCacheSize is the memory allowance (MB) of the cache for RPC responses on
+	// immutable data. Zero disables the cache.
+	RPCCacheSize int
+
+	// RPCCacheTTL is the maximum time a cached RPC response is served.
+	RPCCacheTTL time.Duration
+
 	// OverrideCancun (TODO: remove after the fork)
 	OverrideCancun *uint64 `toml:",omitempty"`
 
 	// OverrideVerkle (TODO: remove after the fork)
 	OverrideVerkle *uint64 `toml:",omitempty"`
 
-	OverrideOptimismCanyon *uint64 `toml:",omitempty"`
+	OverrideOptimismCanyon   *uint64 `toml:",omitempty"`
+	OverrideOasysOperatorFee *uint64 `toml:",omitempty"`
 
 	OverrideOptimismInterop *uint64 `toml:",omitempty"`
 
@@ -182,8 +220,49 @@ type Config struct {
 	RollupHistoricalRPC                     string
 	RollupHistoricalRPCTimeout              time.Duration
 	RollupDisableTxPoolGossip               bool
+	RollupBlockGossip                       bool
+	RollupPendingView                       bool
 	RollupDisableTxPoolAdmission            bool
+	RollupForwardPrecheck                   bool
 	RollupHaltOnIncompatibleProtocolVersion string
+	RollupHaltDelay                         time.Duration
+	RollupFeeCheck                          bool
+	RollupFeeCheckHalt                      bool
+	RollupFeeCheckL1                        string
+	RollupFeeCheckSystemConfig              common.Address
+	RollupDepositCheck                      bool
+	RollupTxWAL                             string
+	RollupHealthMaxHeadAge                  time.Duration
+	RollupHealthMaxEngineAge                time.Duration
+	RollupRuntimeConfig                     string
+	RollupDrainTimeout                      time.Duration
+	RollupDrainNotify                       []string `toml:",omitempty"`
+	RollupFreezeMarker                      string
+	RollupReplicaCheck                      bool
+	RollupReplicaCheckInterval              time.Duration
+	RollupReplicaMode                       bool
+	RollupInclusionMonitor                  uint64
+	RollupNonceGapThreshold                 time.Duration
+	RollupNonceGapEvict                     bool
+	RollupForkRehearsalWindow               time.Duration
+	RollupForkRehearsalInterval             time.Duration
+	RollupGasGovernorTarget                 time.Duration
+	RollupGasGovernorMin                    uint64
+	RollupGasGovernorMax                    uint64
+	RollupFeeRecipients                     []common.Address `toml:",omitempty"`
+	RollupFeeRecipientRotation              uint64
+	RollupGasProfileRate                    uint64
+	RollupWarmCache                         bool
+	RollupDAServer                          string
+	RollupDATargets                         []common.Address `toml:",omitempty"`
+	RollupDATimeout                         time.Duration
+	RollupDAResolve                         bool
+	RollupDAFailurePolicy                   string
+	RollupBlockUsageWindow                  uint64
+	RollupIndexers                          []string `toml:",omitempty"`
+	RollupAncientCheckInterval              time.Duration
+	RollupAncientCheckEndpoint              string
+	RollupMinConsensusVersions              map[string]string `toml:",omitempty"`
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain config.
@@ -26,6 +26,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		NoPrefetch                              bool
 		TxLookupLimit                           uint64                 `toml:",omitempty"`
 		TransactionHistory                      uint64                 `toml:",omitempty"`
+		TransactionSenderIndex                  bool                   `toml:",omitempty"`
 		StateHistory                            uint64                 `toml:",omitempty"`
 		StateScheme                             string                 `toml:",omitempty"`
 		RequiredBlocks                          map[uint64]common.Hash `toml:"-"`
@@ -43,6 +44,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		TrieDirtyCache                          int
 		TrieTimeout                             time.Duration
 		SnapshotCache                           int
+		SnapshotGenRate                         int
 		Preimages                               bool
 		FilterLogCacheSize                      int
 		Miner                                   miner.Config
@@ -53,17 +55,65 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		DocRoot                                 string `toml:"-"`
 		RPCGasCap                               uint64
 		RPCEVMTimeout                           time.Duration
+		RPCEVMMemoryLimit                       uint64
+		RPCEVMReadLimit                         uint64
 		RPCTxFeeCap                             float64
+		RPCLatestBlockTag                       string            `toml:",omitempty"`
+		RPCLatestBlockTagNamespaces             map[string]string `toml:",omitempty"`
+		RPCCacheSize                            int
+		RPCCacheTTL                             time.Duration
 		OverrideCancun                          *uint64 `toml:",omitempty"`
 		OverrideVerkle                          *uint64 `toml:",omitempty"`
 		OverrideOptimismCanyon                  *uint64 `toml:",omitempty"`
+		OverrideOasysOperatorFee                *uint64 `toml:",omitempty"`
 		ApplySuperchainUpgrades                 bool    `toml:",omitempty"`
 		RollupSequencerHTTP                     string
 		RollupHistoricalRPC                     string
 		RollupHistoricalRPCTimeout              time.Duration
 		RollupDisableTxPoolGossip               bool
+		RollupBlockGossip                       bool
+		RollupPendingView                       bool
 		RollupDisableTxPoolAdmission            bool
+		RollupForwardPrecheck                   bool
 		RollupHaltOnIncompatibleProtocolVersion string
+		RollupHaltDelay                         time.Duration
+		RollupFeeCheck                          bool
+		RollupFeeCheckHalt                      bool
+		RollupFeeCheckL1                        string
+		RollupFeeCheckSystemConfig              common.Address
+		RollupDepositCheck                      bool
+		RollupTxWAL                             string
+		RollupHealthMaxHeadAge                  time.Duration
+		RollupHealthMaxEngineAge                time.Duration
+		RollupRuntimeConfig                     string
+		RollupDrainTimeout                      time.Duration
+		RollupDrainNotify                       []string `toml:",omitempty"`
+		RollupFreezeMarker                      string
+		RollupReplicaCheck                      bool
+		RollupReplicaCheckInterval              time.Duration
+		RollupReplicaMode                       bool
+		RollupInclusionMonitor                  uint64
+		RollupNonceGapThreshold                 time.Duration
+		RollupNonceGapEvict                     bool
+		RollupForkRehearsalWindow               time.Duration
+		RollupForkRehearsalInterval             time.Duration
+		RollupGasGovernorTarget                 time.Duration
+		RollupGasGovernorMin                    uint64
+		RollupGasGovernorMax                    uint64
+		RollupFeeRecipients                     []common.Address `toml:",omitempty"`
+		RollupFeeRecipientRotation              uint64
+		RollupGasProfileRate                    uint64
+		RollupWarmCache                         bool
+		RollupDAServer                          string
+		RollupDATargets                         []common.Address `toml:",omitempty"`
+		RollupDATimeout                         time.Duration
+		RollupDAResolve                         bool
+		RollupDAFailurePolicy                   string
+		RollupBlockUsageWindow                  uint64
+		RollupIndexers                          []string `toml:",omitempty"`
+		RollupAncientCheckInterval              time.Duration
+		RollupAncientCheckEndpoint              string
+		RollupMinConsensusVersions              map[string]string `toml:",omitempty"`
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -75,6 +125,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.NoPrefetch = c.NoPrefetch
 	enc.TxLookupLimit = c.TxLookupLimit
 	enc.TransactionHistory = c.TransactionHistory
+	enc.TransactionSenderIndex = c.TransactionSenderIndex
 	enc.StateHistory = c.StateHistory
 	enc.StateScheme = c.StateScheme
 	enc.RequiredBlocks = c.RequiredBlocks
@@ -92,6 +143,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.TrieDirtyCache = c.TrieDirtyCache
 	enc.TrieTimeout = c.TrieTimeout
 	enc.SnapshotCache = c.SnapshotCache
+	enc.SnapshotGenRate = c.SnapshotGenRate
 	enc.Preimages = c.Preimages
 	enc.FilterLogCacheSize = c.FilterLogCacheSize
 	enc.Miner = c.Miner
@@ -102,17 +154,65 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.DocRoot = c.DocRoot
 	enc.RPCGasCap = c.RPCGasCap
 	enc.RPCEVMTimeout = c.RPCEVMTimeout
+	enc.RPCEVMMemoryLimit = c.RPCEVMMemoryLimit
+	enc.RPCEVMReadLimit = c.RPCEVMReadLimit
 	enc.RPCTxFeeCap = c.RPCTxFeeCap
+	enc.RPCLatestBlockTag = c.RPCLatestBlockTag
+	enc.RPCLatestBlockTagNamespaces = c.RPCLatestBlockTagNamespaces
+	enc.RPCCacheSize = c.RPCCacheSize
+	enc.RPCCacheTTL = c.RPCCacheTTL
 	enc.OverrideCancun = c.OverrideCancun
 	enc.OverrideVerkle = c.OverrideVerkle
 	enc.OverrideOptimismCanyon = c.OverrideOptimismCanyon
+	enc.OverrideOasysOperatorFee = c.OverrideOasysOperatorFee
 	enc.ApplySuperchainUpgrades = c.ApplySuperchainUpgrades
 	enc.RollupSequencerHTTP = c.RollupSequencerHTTP
 	enc.RollupHistoricalRPC = c.RollupHistoricalRPC
 	enc.RollupHistoricalRPCTimeout = c.RollupHistoricalRPCTimeout
 	enc.RollupDisableTxPoolGossip = c.RollupDisableTxPoolGossip
+	enc.RollupBlockGossip = c.RollupBlockGossip
+	enc.RollupPendingView = c.RollupPendingView
 	enc.RollupDisableTxPoolAdmission = c.RollupDisableTxPoolAdmission
+	enc.RollupForwardPrecheck = c.RollupForwardPrecheck
 	enc.RollupHaltOnIncompatibleProtocolVersion = c.RollupHaltOnIncompatibleProtocolVersion
+	enc.RollupHaltDelay = c.RollupHaltDelay
+	enc.RollupFeeCheck = c.RollupFeeCheck
+	enc.RollupFeeCheckHalt = c.RollupFeeCheckHalt
+	enc.RollupFeeCheckL1 = c.RollupFeeCheckL1
+	enc.RollupFeeCheckSystemConfig = c.RollupFeeCheckSystemConfig
+	enc.RollupDepositCheck = c.RollupDepositCheck
+	enc.RollupTxWAL = c.RollupTxWAL
+	enc.RollupHealthMaxHeadAge = c.RollupHealthMaxHeadAge
+	enc.RollupHealthMaxEngineAge = c.RollupHealthMaxEngineAge
+	enc.RollupRuntimeConfig = c.RollupRuntimeConfig
+	enc.RollupDrainTimeout = c.RollupDrainTimeout
+	enc.RollupDrainNotify = c.RollupDrainNotify
+	enc.RollupFreezeMarker = c.RollupFreezeMarker
+	enc.RollupReplicaCheck = c.RollupReplicaCheck
+	enc.RollupReplicaCheckInterval = c.RollupReplicaCheckInterval
+	enc.RollupReplicaMode = c.RollupReplicaMode
+	enc.RollupInclusionMonitor = c.RollupInclusionMonitor
+	enc.RollupNonceGapThreshold = c.RollupNonceGapThreshold
+	enc.RollupNonceGapEvict = c.RollupNonceGapEvict
+	enc.RollupForkRehearsalWindow = c.RollupForkRehearsalWindow
+	enc.RollupForkRehearsalInterval = c.RollupForkRehearsalInterval
+	enc.RollupGasGovernorTarget = c.RollupGasGovernorTarget
+	enc.RollupGasGovernorMin = c.RollupGasGovernorMin
+	enc.RollupGasGovernorMax = c.RollupGasGovernorMax
+	enc.RollupFeeRecipients = c.RollupFeeRecipients
+	enc.RollupFeeRecipientRotation = c.RollupFeeRecipientRotation
+	enc.RollupGasProfileRate = c.RollupGasProfileRate
+	enc.RollupWarmCache = c.RollupWarmCache
+	enc.RollupDAServer = c.RollupDAServer
+	enc.RollupDATargets = c.RollupDATargets
+	enc.RollupDATimeout = c.RollupDATimeout
+	enc.RollupDAResolve = c.RollupDAResolve
+	enc.RollupDAFailurePolicy = c.RollupDAFailurePolicy
+	enc.RollupBlockUsageWindow = c.RollupBlockUsageWindow
+	enc.RollupIndexers = c.RollupIndexers
+	enc.RollupAncientCheckInterval = c.RollupAncientCheckInterval
+	enc.RollupAncientCheckEndpoint = c.RollupAncientCheckEndpoint
+	enc.RollupMinConsensusVersions = c.RollupMinConsensusVersions
 	return &enc, nil
 }
 
@@ -128,6 +228,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		NoPrefetch                              *bool
 		TxLookupLimit                           *uint64                `toml:",omitempty"`
 		TransactionHistory                      *uint64                `toml:",omitempty"`
+		TransactionSenderIndex                  *bool                  `toml:",omitempty"`
 		StateHistory                            *uint64                `toml:",omitempty"`
 		StateScheme                             *string                `toml:",omitempty"`
 		RequiredBlocks                          map[uint64]common.Hash `toml:"-"`
@@ -145,6 +246,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		TrieDirtyCache                          *int
 		TrieTimeout                             *time.Duration
 		SnapshotCache                           *int
+		SnapshotGenRate                         *int
 		Preimages                               *bool
 		FilterLogCacheSize                      *int
 		Miner                                   *miner.Config
@@ -155,17 +257,65 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		DocRoot                                 *string `toml:"-"`
 		RPCGasCap                               *uint64
 		RPCEVMTimeout                           *time.Duration
+		RPCEVMMemoryLimit                       *uint64
+		RPCEVMReadLimit                         *uint64
 		RPCTxFeeCap                             *float64
+		RPCLatestBlockTag                       *string           `toml:",omitempty"`
+		RPCLatestBlockTagNamespaces             map[string]string `toml:",omitempty"`
+		RPCCacheSize                            *int
+		RPCCacheTTL                             *time.Duration
 		OverrideCancun                          *uint64 `toml:",omitempty"`
 		OverrideVerkle                          *uint64 `toml:",omitempty"`
 		OverrideOptimismCanyon                  *uint64 `toml:",omitempty"`
+		OverrideOasysOperatorFee                *uint64 `toml:",omitempty"`
 		ApplySuperchainUpgrades                 *bool   `toml:",omitempty"`
 		RollupSequencerHTTP                     *string
 		RollupHistoricalRPC                     *string
 		RollupHistoricalRPCTimeout              *time.Duration
 		RollupDisableTxPoolGossip               *bool
+		RollupBlockGossip                       *bool
+		RollupPendingView                       *bool
 		RollupDisableTxPoolAdmission            *bool
+		RollupForwardPrecheck                   *bool
 		RollupHaltOnIncompatibleProtocolVersion *string
+		RollupHaltDelay                         *time.Duration
+		RollupFeeCheck                          *bool
+		RollupFeeCheckHalt                      *bool
+		RollupFeeCheckL1                        *string
+		RollupFeeCheckSystemConfig              *common.Address
+		RollupDepositCheck                      *bool
+		RollupTxWAL                             *string
+		RollupHealthMaxHeadAge                  *time.Duration
+		RollupHealthMaxEngineAge                *time.Duration
+		RollupRuntimeConfig                     *string
+		RollupDrainTimeout                      *time.Duration
+		RollupDrainNotify                       []string `toml:",omitempty"`
+		RollupFreezeMarker                      *string
+		RollupReplicaCheck                      *bool
+		RollupReplicaCheckInterval              *time.Duration
+		RollupReplicaMode                       *bool
+		RollupInclusionMonitor                  *uint64
+		RollupNonceGapThreshold                 *time.Duration
+		RollupNonceGapEvict                     *bool
+		RollupForkRehearsalWindow               *time.Duration
+		RollupForkRehearsalInterval             *time.Duration
+		RollupGasGovernorTarget                 *time.Duration
+		RollupGasGovernorMin                    *uint64
+		RollupGasGovernorMax                    *uint64
+		RollupFeeRecipients                     []common.Address `toml:",omitempty"`
+		RollupFeeRecipientRotation              *uint64
+		RollupGasProfileRate                    *uint64
+		RollupWarmCache                         *bool
+		RollupDAServer                          *string
+		RollupDATargets                         []common.Address `toml:",omitempty"`
+		RollupDATimeout                         *time.Duration
+		RollupDAResolve                         *bool
+		RollupDAFailurePolicy                   *string
+		RollupBlockUsageWindow                  *uint64
+		RollupIndexers                          []string `toml:",omitempty"`
+		RollupAncientCheckInterval              *time.Duration
+		RollupAncientCheckEndpoint              *string
+		RollupMinConsensusVersions              map[string]string `toml:",omitempty"`
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -198,6 +348,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.TransactionHistory != nil {
 		c.TransactionHistory = *dec.TransactionHistory
 	}
+	if dec.TransactionSenderIndex != nil {
+		c.TransactionSenderIndex = *dec.TransactionSenderIndex
+	}
 	if dec.StateHistory != nil {
 		c.StateHistory = *dec.StateHistory
 	}
@@ -249,6 +402,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.SnapshotCache != nil {
 		c.SnapshotCache = *dec.SnapshotCache
 	}
+	if dec.SnapshotGenRate != nil {
+		c.SnapshotGenRate = *dec.SnapshotGenRate
+	}
 	if dec.Preimages != nil {
 		c.Preimages = *dec.Preimages
 	}
@@ -279,9 +435,27 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.RPCEVMTimeout != nil {
 		c.RPCEVMTimeout = *dec.RPCEVMTimeout
 	}
+	if dec.RPCEVMMemoryLimit != nil {
+		c.RPCEVMMemoryLimit = *dec.RPCEVMMemoryLimit
+	}
+	if dec.RPCEVMReadLimit != nil {
+		c.RPCEVMReadLimit = *dec.RPCEVMReadLimit
+	}
 	if dec.RPCTxFeeCap != nil {
 		c.RPCTxFeeCap = *dec.RPCTxFeeCap
 	}
+	if dec.RPCLatestBlockTag != nil {
+		c.RPCLatestBlockTag = *dec.RPCLatestBlockTag
+	}
+	if dec.RPCLatestBlockTagNamespaces != nil {
+		c.RPCLatestBlockTagNamespaces = dec.RPCLatestBlockTagNamespaces
+	}
+	if dec.RPCCacheSize != nil {
+		c.RPCCacheSize = *dec.RPCCacheSize
+	}
+	if dec.RPCCacheTTL != nil {
+		c.RPCCacheTTL = *dec.RPCCacheTTL
+	}
 	if dec.OverrideCancun != nil {
 		c.OverrideCancun = dec.OverrideCancun
 	}
@@ -291,6 +465,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.OverrideOptimismCanyon != nil {
 		c.OverrideOptimismCanyon = dec.OverrideOptimismCanyon
 	}
+	if dec.OverrideOasysOperatorFee != nil {
+		c.OverrideOasysOperatorFee = dec.OverrideOasysOperatorFee
+	}
 	if dec.ApplySuperchainUpgrades != nil {
 		c.ApplySuperchainUpgrades = *dec.ApplySuperchainUpgrades
 	}
@@ -306,11 +483,134 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.RollupDisableTxPoolGossip != nil {
 		c.RollupDisableTxPoolGossip = *dec.RollupDisableTxPoolGossip
 	}
+	if dec.RollupBlockGossip != nil {
+		c.RollupBlockGossip = *dec.RollupBlockGossip
+	}
+	if dec.RollupPendingView != nil {
+		c.RollupPendingView = *dec.RollupPendingView
+	}
 	if dec.RollupDisableTxPoolAdmission != nil {
 		c.RollupDisableTxPoolAdmission = *dec.RollupDisableTxPoolAdmission
 	}
+	if dec.RollupForwardPrecheck != nil {
+		c.RollupForwardPrecheck = *dec.RollupForwardPrecheck
+	}
 	if dec.RollupHaltOnIncompatibleProtocolVersion != nil {
 		c.RollupHaltOnIncompatibleProtocolVersion = *dec.RollupHaltOnIncompatibleProtocolVersion
 	}
+	if dec.RollupHaltDelay != nil {
+		c.RollupHaltDelay = *dec.RollupHaltDelay
+	}
+	if dec.RollupFeeCheck != nil {
+		c.RollupFeeCheck = *dec.RollupFeeCheck
+	}
+	if dec.RollupFeeCheckHalt != nil {
+		c.RollupFeeCheckHalt = *dec.RollupFeeCheckHalt
+	}
+	if dec.RollupFeeCheckL1 != nil {
+		c.RollupFeeCheckL1 = *dec.RollupFeeCheckL1
+	}
+	if dec.RollupFeeCheckSystemConfig != nil {
+		c.RollupFeeCheckSystemConfig = *dec.RollupFeeCheckSystemConfig
+	}
+	if dec.RollupDepositCheck != nil {
+		c.RollupDepositCheck = *dec.RollupDepositCheck
+	}
+	if dec.RollupTxWAL != nil {
+		c.RollupTxWAL = *dec.RollupTxWAL
+	}
+	if dec.RollupHealthMaxHeadAge != nil {
+		c.RollupHealthMaxHeadAge = *dec.RollupHealthMaxHeadAge
+	}
+	if dec.RollupHealthMaxEngineAge != nil {
+		c.RollupHealthMaxEngineAge = *dec.RollupHealthMaxEngineAge
+	}
+	if dec.RollupRuntimeConfig != nil {
+		c.RollupRuntimeConfig = *dec.RollupRuntimeConfig
+	}
+	if dec.RollupDrainTimeout != nil {
+		c.RollupDrainTimeout = *dec.RollupDrainTimeout
+	}
+	if dec.RollupDrainNotify != nil {
+		c.RollupDrainNotify = dec.RollupDrainNotify
+	}
+	if dec.RollupFreezeMarker != nil {
+		c.RollupFreezeMarker = *dec.RollupFreezeMarker
+	}
+	if dec.RollupReplicaCheck != nil {
+		c.RollupReplicaCheck = *dec.RollupReplicaCheck
+	}
+	if dec.RollupReplicaCheckInterval != nil {
+		c.RollupReplicaCheckInterval = *dec.RollupReplicaCheckInterval
+	}
+	if dec.RollupReplicaMode != nil {
+		c.RollupReplicaMode = *dec.RollupReplicaMode
+	}
+	if dec.RollupInclusionMonitor != nil {
+		c.RollupInclusionMonitor = *dec.RollupInclusionMonitor
+	}
+	if dec.RollupNonceGapThreshold != nil {
+		c.RollupNonceGapThreshold = *dec.RollupNonceGapThreshold
+	}
+	if dec.RollupNonceGapEvict != nil {
+		c.RollupNonceGapEvict = *dec.RollupNonceGapEvict
+	}
+	if dec.RollupForkRehearsalWindow != nil {
+		c.RollupForkRehearsalWindow = *dec.RollupForkRehearsalWindow
+	}
+	if dec.RollupForkRehearsalInterval != nil {
+		c.RollupForkRehearsalInterval = *dec.RollupForkRehearsalInterval
+	}
+	if dec.RollupGasGovernorTarget != nil {
+		c.RollupGasGovernorTarget = *dec.RollupGasGovernorTarget
+	}
+	if dec.RollupGasGovernorMin != nil {
+		c.RollupGasGovernorMin = *dec.RollupGasGovernorMin
+	}
+	if dec.RollupGasGovernorMax != nil {
+		c.RollupGasGovernorMax = *dec.RollupGasGovernorMax
+	}
+	if dec.RollupFeeRecipients != nil {
+		c.RollupFeeRecipients = dec.RollupFeeRecipients
+	}
+	if dec.RollupFeeRecipientRotation != nil {
+		c.RollupFeeRecipientRotation = *dec.RollupFeeRecipientRotation
+	}
+	if dec.RollupGasProfileRate != nil {
+		c.RollupGasProfileRate = *dec.RollupGasProfileRate
+	}
+	if dec.RollupWarmCache != nil {
+		c.RollupWarmCache = *dec.RollupWarmCache
+	}
+	if dec.RollupDAServer != nil {
+		c.RollupDAServer = *dec.RollupDAServer
+	}
+	if dec.RollupDATargets != nil {
+		c.RollupDATargets = dec.RollupDATargets
+	}
+	if dec.RollupDATimeout != nil {
+		c.RollupDATimeout = *dec.RollupDATimeout
+	}
+	if dec.RollupDAResolve != nil {
+		c.RollupDAResolve = *dec.RollupDAResolve
+	}
+	if dec.RollupDAFailurePolicy != nil {
+		c.RollupDAFailurePolicy = *dec.RollupDAFailurePolicy
+	}
+	if dec.RollupBlockUsageWindow != nil {
+		c.RollupBlockUsageWindow = *dec.RollupBlockUsageWindow
+	}
+	if dec.RollupIndexers != nil {
+		c.RollupIndexers = dec.RollupIndexers
+	}
+	if dec.RollupAncientCheckInterval != nil {
+		c.RollupAncientCheckInterval = *dec.RollupAncientCheckInterval
+	}
+	if dec.RollupAncientCheckEndpoint != nil {
+		c.RollupAncientCheckEndpoint = *dec.RollupAncientCheckEndpoint
+	}
+	if dec.RollupMinConsensusVersions != nil {
+		c.RollupMinConsensusVersions = dec.RollupMinConsensusVersions
+	}
 	return nil
 }
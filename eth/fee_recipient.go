@@ -0,0 +1,52 @@
+package eth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var feeRecipientRejectedMeter = metrics.NewRegisteredMeter("rollup/feerecipient/rejected", nil)
+
+// FeeRecipient checks the fee recipient suggested by the consensus client for
+// the block with the given number against the recipients allowed by the node,
+// returning the recipient to build the block with. A zero suggestion leaves the
+// choice to the node, which picks the recipient scheduled for the block.
+func (s *Ethereum) FeeRecipient(number uint64, suggested common.Address) (common.Address, error) {
+	recipient, err := scheduleFeeRecipient(s.config.RollupFeeRecipients, s.config.RollupFeeRecipientRotation, number, suggested)
+	if err != nil {
+		feeRecipientRejectedMeter.Mark(1)
+		log.Error("Rejecting payload attributes with unexpected fee recipient", "number", number, "suggested", suggested, "err", err)
+	}
+	return recipient, err
+}
+
+// scheduleFeeRecipient resolves the fee recipient of a block. Without allowed
+// recipients, any suggestion is accepted. With a rotation, each recipient of the
+// list is used for that many blocks in turn and only the scheduled one accepted;
+// otherwise any recipient of the list is accepted, the first one being picked
+// by default.
+func scheduleFeeRecipient(allowed []common.Address, rotation uint64, number uint64, suggested common.Address) (common.Address, error) {
+	if len(allowed) == 0 {
+		return suggested, nil
+	}
+	if rotation > 0 {
+		scheduled := allowed[(number/rotation)%uint64(len(allowed))]
+		if suggested != (common.Address{}) && suggested != scheduled {
+			return common.Address{}, fmt.Errorf("fee recipient %v not scheduled, want %v", suggested, scheduled)
+		}
+		return scheduled, nil
+	}
+	if suggested == (common.Address{}) {
+		return allowed[0], nil
+	}
+	for _, addr := range allowed {
+		if addr == suggested {
+			return suggested, nil
+		}
+	}
+	return common.Address{}, errors.New("fee recipient not allowed")
+}
@@ -0,0 +1,47 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestScheduleFeeRecipient(t *testing.T) {
+	var (
+		a = common.Address{0xa}
+		b = common.Address{0xb}
+		c = common.Address{0xc}
+	)
+	tests := []struct {
+		allowed   []common.Address
+		rotation  uint64
+		number    uint64
+		suggested common.Address
+		want      common.Address
+		fail      bool
+	}{
+		// No allowed list, anything goes
+		{nil, 0, 1, c, c, false},
+		{nil, 10, 1, common.Address{}, common.Address{}, false},
+
+		// Allowed list without rotation
+		{[]common.Address{a, b}, 0, 1, b, b, false},
+		{[]common.Address{a, b}, 0, 1, common.Address{}, a, false},
+		{[]common.Address{a, b}, 0, 1, c, common.Address{}, true},
+
+		// Rotation every 10 blocks
+		{[]common.Address{a, b}, 10, 9, a, a, false},
+		{[]common.Address{a, b}, 10, 10, a, common.Address{}, true},
+		{[]common.Address{a, b}, 10, 10, common.Address{}, b, false},
+		{[]common.Address{a, b}, 10, 25, a, a, false},
+	}
+	for i, tt := range tests {
+		have, err := scheduleFeeRecipient(tt.allowed, tt.rotation, tt.number, tt.suggested)
+		if (err != nil) != tt.fail {
+			t.Errorf("test %d: failure mismatch: have %v, want fail %v", i, err, tt.fail)
+		}
+		if have != tt.want {
+			t.Errorf("test %d: recipient mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
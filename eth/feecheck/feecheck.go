@@ -0,0 +1,167 @@
+// Package feecheck implements a background checker which compares the fee
+// parameters of every new chain head with the ones set on L1, catching sequencer
+// misconfiguration early.
+package feecheck
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// requestTimeout is the time allowed to retrieve the expected parameters of a block.
+const requestTimeout = 10 * time.Second
+
+var (
+	checkedMeter            = metrics.NewRegisteredMeter("feecheck/checked", nil)
+	gasLimitDivergenceMeter = metrics.NewRegisteredMeter("feecheck/divergence/gaslimit", nil)
+	l1FeeDivergenceMeter    = metrics.NewRegisteredMeter("feecheck/divergence/l1fee", nil)
+	failureMeter            = metrics.NewRegisteredMeter("feecheck/failure", nil)
+)
+
+// BlockChain defines the minimal set of methods needed to back the checker.
+type BlockChain interface {
+	Config() *params.ChainConfig
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// Expected is the fee parameters of the blocks derived from an L1 origin, as
+// set on L1 at that origin.
+type Expected struct {
+	L1Hash      common.Hash // Hash of the L1 origin block
+	L1BaseFee   *big.Int    // Base fee of the L1 origin block
+	BatcherHash common.Hash // SystemConfig batcher hash
+	Overhead    *big.Int    // SystemConfig L1 fee overhead
+	Scalar      *big.Int    // SystemConfig L1 fee scalar
+	GasLimit    uint64      // SystemConfig L2 block gas limit
+}
+
+// Source provides the expected fee parameters of the blocks.
+type Source interface {
+	// Expected retrieves the fee parameters of the blocks derived from the L1
+	// block with the given number.
+	Expected(ctx context.Context, origin uint64) (*Expected, error)
+}
+
+// Checker compares the gas limit and the L1 fee parameters of every new chain
+// head with the ones expected from its L1 origin, and alerts when the block
+// diverges from them. The base fee is derived from the gas limit and the chain
+// config, and already verified on import.
+type Checker struct {
+	chain  BlockChain
+	source Source
+	halt   func() error // Optional callback to halt the node on divergence
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a fee parameter checker. If halt is non-nil, it is invoked on the
+// first detected divergence.
+func New(chain BlockChain, source Source, halt func() error) *Checker {
+	return &Checker{
+		chain:  chain,
+		source: source,
+		halt:   halt,
+		quit:   make(chan struct{}),
+	}
+}
+
+// Start launches the background loop checking new chain heads.
+func (c *Checker) Start() {
+	c.wg.Add(1)
+	go c.loop()
+}
+
+// Stop terminates the background loop.
+func (c *Checker) Stop() {
+	close(c.quit)
+	c.wg.Wait()
+}
+
+func (c *Checker) loop() {
+	defer c.wg.Done()
+
+	heads := make(chan core.ChainHeadEvent, 16)
+	sub := c.chain.SubscribeChainHeadEvent(heads)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-heads:
+			ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+			err := c.Check(ctx, ev.Block)
+			cancel()
+			if err == nil {
+				continue
+			}
+			log.Error("Fee parameter divergence detected", "number", ev.Block.NumberU64(), "hash", ev.Block.Hash(), "err", err)
+			if c.halt != nil {
+				log.Error("Halting on fee parameter divergence")
+				go c.halt()
+				return
+			}
+		case <-sub.Err():
+			return
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// Check verifies the fee parameters of the given block, returning a non-nil
+// error describing the first divergence found. Blocks whose expected parameters
+// cannot be retrieved are skipped.
+func (c *Checker) Check(ctx context.Context, block *types.Block) error {
+	if block.NumberU64() == 0 || !c.chain.Config().IsOptimismBedrock(block.Number()) {
+		return nil
+	}
+	checkedMeter.Mark(1)
+
+	txs := block.Transactions()
+	if len(txs) == 0 || !txs[0].IsDepositTx() {
+		l1FeeDivergenceMeter.Mark(1)
+		return fmt.Errorf("block does not start with an L1 attributes deposit")
+	}
+	info, err := types.ParseL1BlockInfo(txs[0].Data())
+	if err != nil {
+		l1FeeDivergenceMeter.Mark(1)
+		return err
+	}
+	want, err := c.source.Expected(ctx, info.Number)
+	if err != nil {
+		failureMeter.Mark(1)
+		log.Debug("Skipping fee check, expected parameters unavailable", "number", block.Number(), "hash", block.Hash(), "origin", info.Number, "err", err)
+		return nil
+	}
+	if block.GasLimit() != want.GasLimit {
+		gasLimitDivergenceMeter.Mark(1)
+		return fmt.Errorf("gas limit mismatch: have %d, want %d", block.GasLimit(), want.GasLimit)
+	}
+	for _, param := range []struct {
+		name       string
+		have, want common.Hash
+	}{
+		{"l1Hash", info.BlockHash, want.L1Hash},
+		{"l1BaseFee", common.BigToHash(info.BaseFee), common.BigToHash(want.L1BaseFee)},
+		{"batcherHash", info.BatcherHash, want.BatcherHash},
+		{"overhead", common.BigToHash(info.L1FeeOverhead), common.BigToHash(want.Overhead)},
+		{"scalar", common.BigToHash(info.L1FeeScalar), common.BigToHash(want.Scalar)},
+	} {
+		if param.have != param.want {
+			l1FeeDivergenceMeter.Mark(1)
+			return fmt.Errorf("%s mismatch: have %x, want %x", param.name, param.have, param.want)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package feecheck
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type testChain struct {
+	config *params.ChainConfig
+}
+
+func (c *testChain) Config() *params.ChainConfig { return c.config }
+
+func (c *testChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return nil
+}
+
+type testSource map[uint64]*Expected
+
+func (s testSource) Expected(ctx context.Context, origin uint64) (*Expected, error) {
+	if want, ok := s[origin]; ok {
+		return want, nil
+	}
+	return nil, errors.New("unknown origin")
+}
+
+// testExpected are the fee parameters set on L1 at the origin 100.
+var testExpected = &Expected{
+	L1Hash:      common.Hash{0x01},
+	L1BaseFee:   big.NewInt(params.GWei),
+	BatcherHash: common.BytesToHash(common.Address{0xba}.Bytes()),
+	Overhead:    big.NewInt(188),
+	Scalar:      big.NewInt(684_000),
+	GasLimit:    30_000_000,
+}
+
+// newTestBlock creates a block whose L1 attributes deposit carries the given
+// parameters.
+func newTestBlock(origin uint64, gasLimit uint64, want *Expected) *types.Block {
+	data := []byte{0x01, 0x5d, 0x8e, 0xb9}
+	for _, arg := range []*big.Int{
+		new(big.Int).SetUint64(origin), big.NewInt(1_700_000_000), want.L1BaseFee, want.L1Hash.Big(),
+		new(big.Int), want.BatcherHash.Big(), want.Overhead, want.Scalar,
+	} {
+		data = append(data, common.LeftPadBytes(arg.Bytes(), 32)...)
+	}
+	deposit := types.NewTx(&types.DepositTx{To: &types.L1BlockAddr, Gas: 1_000_000, Data: data})
+	header := &types.Header{Number: big.NewInt(1), GasLimit: gasLimit}
+	return types.NewBlockWithHeader(header).WithBody([]*types.Transaction{deposit}, nil)
+}
+
+func TestCheck(t *testing.T) {
+	config := *params.TestChainConfig
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 2, EIP1559Denominator: 8}
+	config.BedrockBlock = new(big.Int)
+
+	checker := New(&testChain{config: &config}, testSource{100: testExpected}, nil)
+
+	// A block matching the L1 parameters passes
+	if err := checker.Check(context.Background(), newTestBlock(100, testExpected.GasLimit, testExpected)); err != nil {
+		t.Fatalf("matching block reported: %v", err)
+	}
+	// Blocks whose expected parameters are unknown are skipped
+	if err := checker.Check(context.Background(), newTestBlock(101, testExpected.GasLimit, testExpected)); err != nil {
+		t.Fatalf("unchecked block reported: %v", err)
+	}
+	// Diverging blocks are reported
+	scalar := *testExpected
+	scalar.Scalar = big.NewInt(1_000_000)
+	origin := *testExpected
+	origin.L1Hash = common.Hash{0x02}
+
+	tests := []struct {
+		block *types.Block
+		param string
+	}{
+		{newTestBlock(100, 20_000_000, testExpected), "gas limit"},
+		{newTestBlock(100, testExpected.GasLimit, &scalar), "scalar"},
+		{newTestBlock(100, testExpected.GasLimit, &origin), "l1Hash"},
+		{types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), GasLimit: testExpected.GasLimit}), "deposit"},
+	}
+	for i, tt := range tests {
+		err := checker.Check(context.Background(), tt.block)
+		if err == nil || !strings.Contains(err.Error(), tt.param) {
+			t.Errorf("test %d: expected %s divergence, got %v", i, tt.param, err)
+		}
+	}
+}
+
+// testL1 serves the L1 origin blocks and their SystemConfig over RPC.
+type testL1 struct {
+	systemConfig common.Address
+	origin       uint64
+	want         *Expected
+}
+
+func (l *testL1) GetBlockByNumber(number hexutil.Uint64, full bool) (map[string]interface{}, error) {
+	if uint64(number) != l.origin {
+		return nil, nil
+	}
+	return map[string]interface{}{"hash": l.want.L1Hash, "baseFeePerGas": (*hexutil.Big)(l.want.L1BaseFee)}, nil
+}
+
+func (l *testL1) Call(args struct {
+	To   common.Address `json:"to"`
+	Data hexutil.Bytes  `json:"data"`
+}, at rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	if hash, ok := at.Hash(); !ok || hash != l.want.L1Hash || args.To != l.systemConfig {
+		return nil, errors.New("execution reverted")
+	}
+	values := map[string]*big.Int{
+		"batcherHash()": l.want.BatcherHash.Big(),
+		"overhead()":    l.want.Overhead,
+		"scalar()":      l.want.Scalar,
+		"gasLimit()":    new(big.Int).SetUint64(l.want.GasLimit),
+	}
+	for getter, value := range values {
+		if string(crypto.Keccak256([]byte(getter))[:4]) == string(args.Data) {
+			return common.LeftPadBytes(value.Bytes(), 32), nil
+		}
+	}
+	return nil, errors.New("execution reverted")
+}
+
+func TestRPCSource(t *testing.T) {
+	systemConfig := common.Address{0x5c}
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("eth", &testL1{systemConfig: systemConfig, origin: 100, want: testExpected}); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	have, err := NewRPCSource(client, systemConfig).Expected(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("failed to read expected parameters: %v", err)
+	}
+	if have.L1Hash != testExpected.L1Hash || have.L1BaseFee.Cmp(testExpected.L1BaseFee) != 0 || have.BatcherHash != testExpected.BatcherHash ||
+		have.Overhead.Cmp(testExpected.Overhead) != 0 || have.Scalar.Cmp(testExpected.Scalar) != 0 || have.GasLimit != testExpected.GasLimit {
+		t.Fatalf("expected parameters mismatch: have %+v, want %+v", have, testExpected)
+	}
+	// Unknown origins and wrong contracts fail
+	if _, err := NewRPCSource(client, systemConfig).Expected(context.Background(), 101); err == nil {
+		t.Fatal("parameters read for an unknown origin")
+	}
+	if _, err := NewRPCSource(client, common.Address{0x01}).Expected(context.Background(), 100); err == nil {
+		t.Fatal("parameters read from a wrong contract")
+	}
+}
@@ -0,0 +1,74 @@
+package feecheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// systemConfigGetters are the SystemConfig getters of the expected parameters.
+var systemConfigGetters = []string{"batcherHash()", "overhead()", "scalar()", "gasLimit()"}
+
+// rpcSource reads the expected fee parameters from an L1 node, at the L1 origin
+// of the blocks.
+type rpcSource struct {
+	client       *rpc.Client
+	systemConfig common.Address
+}
+
+// NewRPCSource creates a source reading the expected fee parameters from the
+// SystemConfig contract at the given address, through the given L1 endpoint.
+func NewRPCSource(client *rpc.Client, systemConfig common.Address) Source {
+	return &rpcSource{client: client, systemConfig: systemConfig}
+}
+
+func (s *rpcSource) Expected(ctx context.Context, origin uint64) (*Expected, error) {
+	var header *struct {
+		Hash    common.Hash  `json:"hash"`
+		BaseFee *hexutil.Big `json:"baseFeePerGas"`
+	}
+	if err := s.client.CallContext(ctx, &header, "eth_getBlockByNumber", hexutil.Uint64(origin), false); err != nil {
+		return nil, err
+	}
+	if header == nil || header.BaseFee == nil {
+		return nil, fmt.Errorf("L1 block #%d not found", origin)
+	}
+	// Read the SystemConfig at the origin, by hash not to race with L1 reorgs
+	var (
+		at      = rpc.BlockNumberOrHashWithHash(header.Hash, false)
+		results = make([]hexutil.Bytes, len(systemConfigGetters))
+		batch   = make([]rpc.BatchElem, len(systemConfigGetters))
+	)
+	for i, getter := range systemConfigGetters {
+		call := map[string]interface{}{
+			"to":   s.systemConfig,
+			"data": hexutil.Bytes(crypto.Keccak256([]byte(getter))[:4]),
+		}
+		batch[i] = rpc.BatchElem{Method: "eth_call", Args: []interface{}{call, at}, Result: &results[i]}
+	}
+	if err := s.client.BatchCallContext(ctx, batch); err != nil {
+		return nil, err
+	}
+	for i, elem := range batch {
+		if elem.Error != nil {
+			return nil, fmt.Errorf("SystemConfig.%s failed: %v", systemConfigGetters[i], elem.Error)
+		}
+		if len(results[i]) != 32 {
+			return nil, errors.New("invalid SystemConfig")
+		}
+	}
+	return &Expected{
+		L1Hash:      header.Hash,
+		L1BaseFee:   header.BaseFee.ToInt(),
+		BatcherHash: common.BytesToHash(results[0]),
+		Overhead:    new(big.Int).SetBytes(results[1]),
+		Scalar:      new(big.Int).SetBytes(results[2]),
+		GasLimit:    new(big.Int).SetBytes(results[3]).Uint64(),
+	}, nil
+}
@@ -28,7 +28,9 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -222,11 +224,16 @@ func (api *FilterAPI) NewBlockFilter() rpc.ID {
 }
 
 // NewHeads send a notification each time a new (header) block is appended to the chain.
-func (api *FilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+// If the "safe" or "finalized" tag is given, a notification is sent each time the
+// respective head moves instead.
+func (api *FilterAPI) NewHeads(ctx context.Context, tag *rpc.BlockNumber) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
 	}
+	if tag != nil && *tag != rpc.LatestBlockNumber {
+		return api.newLabelledHeads(notifier, *tag)
+	}
 
 	rpcSub := notifier.CreateSubscription()
 
@@ -251,7 +258,59 @@ func (api *FilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
 	return rpcSub, nil
 }
 
+// headUpdateBackend is implemented by the backends tracking the safe and finalized
+// heads set by the consensus client.
+type headUpdateBackend interface {
+	SubscribeHeadUpdateEvent(ch chan<- core.HeadUpdateEvent) event.Subscription
+}
+
+// newLabelledHeads sends a notification each time the safe or finalized head moves.
+func (api *FilterAPI) newLabelledHeads(notifier *rpc.Notifier, tag rpc.BlockNumber) (*rpc.Subscription, error) {
+	var label string
+	switch tag {
+	case rpc.SafeBlockNumber:
+		label = core.HeadSafe
+	case rpc.FinalizedBlockNumber:
+		label = core.HeadFinalized
+	default:
+		return &rpc.Subscription{}, fmt.Errorf("unsupported head tag %v", tag)
+	}
+	backend, ok := api.sys.backend.(headUpdateBackend)
+	if !ok {
+		return &rpc.Subscription{}, fmt.Errorf("%s head notifications not supported", label)
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		updates := make(chan core.HeadUpdateEvent, 16)
+		updatesSub := backend.SubscribeHeadUpdateEvent(updates)
+		defer updatesSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-updates:
+				if ev.Label == label {
+					notifier.Notify(rpcSub.ID, ev.Header)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// reorgJournalBackend is implemented by the backends journaling the blocks dropped
+// by reorgs across restarts.
+type reorgJournalBackend interface {
+	JournaledRemovedLogs(from uint64) []*types.Log
+}
+
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
+// If a from block is given, the removal of the logs dropped by recent reorgs at or above it
+// is delivered first, with the removed flag set.
 func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
@@ -267,8 +326,17 @@ func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subsc
 	if err != nil {
 		return nil, err
 	}
+	// Replay the removal of the logs dropped by recent reorgs from the requested
+	// block, which the subscriber may have missed, e.g. across a node restart.
+	var removed []*types.Log
+	if backend, ok := api.sys.backend.(reorgJournalBackend); ok && crit.FromBlock != nil && crit.FromBlock.Sign() >= 0 {
+		removed = filterLogs(backend.JournaledRemovedLogs(crit.FromBlock.Uint64()), nil, nil, crit.Addresses, crit.Topics)
+	}
 
 	go func() {
+		for _, log := range removed {
+			notifier.Notify(rpcSub.ID, log)
+		}
 		for {
 			select {
 			case logs := <-matchedLogs:
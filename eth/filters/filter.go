@@ -22,11 +22,15 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
+// indexedChunkSize is the number of blocks searched at once in the log index,
+// bounding the candidates held in memory.
+const indexedChunkSize = 32768
+
 // Filter can be used to retrieve and filter logs.
 type Filter struct {
 	sys *FilterSystem
@@ -36,37 +40,15 @@ type Filter struct {
 
 	block      *common.Hash // Block hash if filtering a single block
 	begin, end int64        // Range interval if filtering multiple blocks
-
-	matcher *bloombits.Matcher
 }
 
-// NewRangeFilter creates a new filter which uses a bloom filter on blocks to
-// figure out whether a particular block is interesting or not.
+// NewRangeFilter creates a new filter which uses the log index, or the bloom
+// filter of the blocks not indexed, to figure out whether a particular block
+// is interesting or not.
 func (sys *FilterSystem) NewRangeFilter(begin, end int64, addresses []common.Address, topics [][]common.Hash) *Filter {
-	// Flatten the address and topic filter clauses into a single bloombits filter
-	// system. Since the bloombits are not positional, nil topics are permitted,
-	// which get flattened into a nil byte slice.
-	var filters [][][]byte
-	if len(addresses) > 0 {
-		filter := make([][]byte, len(addresses))
-		for i, address := range addresses {
-			filter[i] = address.Bytes()
-		}
-		filters = append(filters, filter)
-	}
-	for _, topicList := range topics {
-		filter := make([][]byte, len(topicList))
-		for i, topic := range topicList {
-			filter[i] = topic.Bytes()
-		}
-		filters = append(filters, filter)
-	}
-	size, _ := sys.backend.BloomStatus()
-
 	// Create a generic filter and convert it into a range filter
 	filter := newFilter(sys, addresses, topics)
 
-	filter.matcher = bloombits.NewMatcher(size, filters)
 	filter.begin = begin
 	filter.end = end
 
@@ -192,19 +174,24 @@ func (f *Filter) rangeLogsAsync(ctx context.Context) (chan *types.Log, chan erro
 			close(logChan)
 		}()
 
-		// Gather all indexed logs, and finish with non indexed ones
-		var (
-			end            = uint64(f.end)
-			size, sections = f.sys.backend.BloomStatus()
-			err            error
-		)
-		if indexed := sections * size; indexed > uint64(f.begin) {
-			if indexed > end {
-				indexed = end + 1
-			}
-			if err = f.indexedLogs(ctx, indexed-1, logChan); err != nil {
-				errChan <- err
-				return
+		// Gather the logs below the tail of the index, the indexed ones, and
+		// finish with the ones not indexed yet
+		end := uint64(f.end)
+		if index := f.sys.backend.LogIndex(); index != nil {
+			if first, last, ok := index.IndexedRange(); ok && first <= end && last >= uint64(f.begin) {
+				if uint64(f.begin) < first {
+					if err := f.unindexedLogs(ctx, first-1, logChan); err != nil {
+						errChan <- err
+						return
+					}
+				}
+				if last > end {
+					last = end
+				}
+				if err := f.indexedLogs(ctx, index, last, logChan); err != nil {
+					errChan <- err
+					return
+				}
 			}
 		}
 
@@ -219,33 +206,25 @@ func (f *Filter) rangeLogsAsync(ctx context.Context) (chan *types.Log, chan erro
 	return logChan, errChan
 }
 
-// indexedLogs returns the logs matching the filter criteria based on the bloom
-// bits indexed available locally or via the network.
-func (f *Filter) indexedLogs(ctx context.Context, end uint64, logChan chan *types.Log) error {
-	// Create a matcher session and request servicing from the backend
-	matches := make(chan uint64, 64)
-
-	session, err := f.matcher.Start(ctx, uint64(f.begin), end, matches)
-	if err != nil {
-		return err
-	}
-	defer session.Close()
-
-	f.sys.backend.ServiceFilter(ctx, session)
-
-	for {
-		select {
-		case number, ok := <-matches:
-			// Abort if all matches have been fulfilled
-			if !ok {
-				err := session.Error()
-				if err == nil {
-					f.begin = int64(end) + 1
-				}
+// indexedLogs returns the logs matching the filter criteria based on the log
+// index, falling back to raw block iteration for the chunks no longer indexed.
+func (f *Filter) indexedLogs(ctx context.Context, index filtermaps.LogIndex, end uint64, logChan chan *types.Log) error {
+	for f.begin <= int64(end) {
+		chunkEnd := uint64(f.begin) + indexedChunkSize - 1
+		if chunkEnd > end {
+			chunkEnd = end
+		}
+		matches, err := index.Candidates(ctx, uint64(f.begin), chunkEnd, f.addresses, f.topics)
+		if errors.Is(err, filtermaps.ErrNotIndexed) {
+			if err := f.unindexedLogs(ctx, chunkEnd, logChan); err != nil {
 				return err
 			}
-			f.begin = int64(number) + 1
-
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, number := range matches {
 			// Retrieve the suggested block and pull any truly matching logs
 			header, err := f.sys.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
 			if header == nil || err != nil {
@@ -256,13 +235,17 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64, logChan chan *type
 				return err
 			}
 			for _, log := range found {
-				logChan <- log
+				select {
+				case logChan <- log:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
 			}
-
-		case <-ctx.Done():
-			return ctx.Err()
+			f.begin = int64(number) + 1
 		}
+		f.begin = int64(chunkEnd) + 1
 	}
+	return nil
 }
 
 // unindexedLogs returns the logs matching the filter criteria based on raw block
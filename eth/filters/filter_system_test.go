@@ -21,7 +21,6 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"math/rand"
 	"reflect"
 	"runtime"
 	"testing"
@@ -31,7 +30,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
-	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -44,12 +43,13 @@ import (
 
 type testBackend struct {
 	db              ethdb.Database
-	sections        uint64
+	logIndex        filtermaps.LogIndex
 	txFeed          event.Feed
 	logsFeed        event.Feed
 	rmLogsFeed      event.Feed
 	pendingLogsFeed event.Feed
 	chainFeed       event.Feed
+	headUpdateFeed  event.Feed
 	pendingBlock    *types.Block
 	pendingReceipts types.Receipts
 }
@@ -149,35 +149,12 @@ func (b *testBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subsc
 	return b.chainFeed.Subscribe(ch)
 }
 
-func (b *testBackend) BloomStatus() (uint64, uint64) {
-	return params.BloomBitsBlocks, b.sections
+func (b *testBackend) SubscribeHeadUpdateEvent(ch chan<- core.HeadUpdateEvent) event.Subscription {
+	return b.headUpdateFeed.Subscribe(ch)
 }
 
-func (b *testBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
-	requests := make(chan chan *bloombits.Retrieval)
-
-	go session.Multiplex(16, 0, requests)
-	go func() {
-		for {
-			// Wait for a service request or a shutdown
-			select {
-			case <-ctx.Done():
-				return
-
-			case request := <-requests:
-				task := <-request
-
-				task.Bitsets = make([][]byte, len(task.Sections))
-				for i, section := range task.Sections {
-					if rand.Int()%4 != 0 { // Handle occasional missing deliveries
-						head := rawdb.ReadCanonicalHash(b.db, (section+1)*params.BloomBitsBlocks-1)
-						task.Bitsets[i], _ = rawdb.ReadBloomBits(b.db, task.Bit, section, head)
-					}
-				}
-				request <- task
-			}
-		}
-	}()
+func (b *testBackend) LogIndex() filtermaps.LogIndex {
+	return b.logIndex
 }
 
 func newTestFilterSystem(t testing.TB, db ethdb.Database, cfg Config) (*testBackend, *FilterSystem) {
@@ -245,6 +222,57 @@ func TestBlockSubscription(t *testing.T) {
 	<-sub1.Err()
 }
 
+// TestLabelledHeadSubscription tests that newHeads subscriptions for the safe and
+// finalized tags are only notified of the moves of the respective head.
+func TestLabelledHeadSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(t, db, Config{})
+		server       = rpc.NewServer()
+	)
+	if err := server.RegisterName("eth", NewFilterAPI(sys, false)); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	heads := make(chan *types.Header, 4)
+	sub, err := client.EthSubscribe(context.Background(), heads, "newHeads", "safe")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	if _, err := client.EthSubscribe(context.Background(), make(chan *types.Header), "newHeads", "pending"); err == nil {
+		t.Error("subscription to pending heads accepted")
+	}
+	unsafe := &types.Header{Number: big.NewInt(10), Difficulty: new(big.Int)}
+	safe := &types.Header{Number: big.NewInt(5), Difficulty: new(big.Int)}
+
+	// Wait for the subscription to be registered with the backend
+	for deadline := time.Now().Add(5 * time.Second); backend.headUpdateFeed.Send(core.HeadUpdateEvent{Label: core.HeadUnsafe, Header: unsafe}) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("subscription not registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	backend.headUpdateFeed.Send(core.HeadUpdateEvent{Label: core.HeadSafe, Header: safe})
+
+	select {
+	case head := <-heads:
+		if head.Hash() != safe.Hash() {
+			t.Fatalf("notified head mismatch: have #%d, want #%d", head.Number, safe.Number)
+		}
+	case err := <-sub.Err():
+		t.Fatalf("subscription failed: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("safe head not notified")
+	}
+}
+
 // TestPendingTxFilter tests whether pending tx filters retrieve all pending transactions that are posted to the event mux.
 func TestPendingTxFilter(t *testing.T) {
 	t.Parallel()
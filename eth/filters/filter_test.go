@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -261,6 +262,22 @@ func TestFilters(t *testing.T) {
 	// Set block 998 as Finalized (-3)
 	bc.SetFinalized(chain[998].Header())
 
+	// Index the logs of the recent half of the chain only, the searches cover
+	// both the indexed and the unindexed blocks
+	index := filtermaps.New(db, bc, 500)
+	index.Start()
+	defer index.Stop()
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		if _, last, ok := index.IndexedRange(); ok && last == 1000 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("log index not built in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	sys.backend.(*testBackend).logIndex = index
+
 	// Generate pending block
 	pchain, preceipts := core.GenerateChain(gspec.Config, chain[len(chain)-1], ethash.NewFaker(), db, 1, func(i int, gen *core.BlockGen) {
 		data, err := contractABI.Pack("log1", hash5.Big())
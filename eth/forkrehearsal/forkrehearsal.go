@@ -0,0 +1,200 @@
+// Package forkrehearsal implements the rehearsal of the block production ahead
+// of the activation of forks: in the window preceding an activation, blocks are
+// periodically built under the rules of the fork, without being published, so
+// that misconfigurations are reported before the fork activates.
+package forkrehearsal
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	rehearsalMeter = metrics.NewRegisteredMeter("forkrehearsal/rehearsals", nil)
+	failureMeter   = metrics.NewRegisteredMeter("forkrehearsal/failures", nil)
+	failingGauge   = metrics.NewRegisteredGauge("forkrehearsal/failing", nil)
+)
+
+// Chain defines the minimal set of methods needed to back the rehearsal.
+type Chain interface {
+	Config() *params.ChainConfig
+	CurrentBlock() *types.Header
+}
+
+// Builder builds blocks on top of the head without publishing them.
+type Builder interface {
+	BuildDryRun(timestamp uint64) (*types.Block, error)
+}
+
+// Result is the outcome of the last rehearsal of a fork.
+type Result struct {
+	Fork       string      `json:"fork"`
+	Activation uint64      `json:"activation"` // Activation timestamp of the fork
+	Time       time.Time   `json:"time"`       // Time of the rehearsal
+	Parent     uint64      `json:"parent"`     // Number of the head the block was built on
+	Hash       common.Hash `json:"hash,omitempty"`
+	Txs        int         `json:"txs"`
+	GasUsed    uint64      `json:"gasUsed"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// fork is a timestamp based change of the block production rules.
+type fork struct {
+	name string
+	time uint64
+}
+
+// forks returns the timestamp based forks of the chain, including the starts and
+// ends of the zero-fee windows, in activation order.
+func forks(config *params.ChainConfig) []fork {
+	var forks []fork
+	for _, f := range []struct {
+		name string
+		time *uint64
+	}{
+		{"shanghai", config.ShanghaiTime},
+		{"cancun", config.CancunTime},
+		{"prague", config.PragueTime},
+		{"regolith", config.RegolithTime},
+		{"canyon", config.CanyonTime},
+		{"oasysOperatorFee", config.OasysOperatorFeeTime},
+		{"jovian", config.JovianTime},
+		{"interop", config.InteropTime},
+	} {
+		if f.time != nil {
+			forks = append(forks, fork{f.name, *f.time})
+		}
+	}
+	for i, time := range config.ZeroFeeTimes {
+		name := "zerofee-start"
+		if i%2 == 1 {
+			name = "zerofee-end"
+		}
+		forks = append(forks, fork{name, time})
+	}
+	sort.SliceStable(forks, func(i, j int) bool { return forks[i].time < forks[j].time })
+	return forks
+}
+
+// Rehearsal periodically builds blocks under the rules of the upcoming forks.
+type Rehearsal struct {
+	chain    Chain
+	builder  Builder
+	window   time.Duration
+	interval time.Duration
+
+	lock    sync.Mutex
+	results map[string]*Result
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a rehearsal of the forks activating within the window, repeated
+// every interval.
+func New(chain Chain, builder Builder, window, interval time.Duration) *Rehearsal {
+	return &Rehearsal{
+		chain:    chain,
+		builder:  builder,
+		window:   window,
+		interval: interval,
+		results:  make(map[string]*Result),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start launches the background loop rehearsing the forks.
+func (r *Rehearsal) Start() {
+	r.wg.Add(1)
+	go r.loop()
+}
+
+// Stop terminates the background loop.
+func (r *Rehearsal) Stop() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+// Results returns the outcome of the last rehearsal of each upcoming fork, in
+// activation order.
+func (r *Rehearsal) Results() []*Result {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	results := make([]*Result, 0, len(r.results))
+	for _, result := range r.results {
+		res := *result
+		results = append(results, &res)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Activation < results[j].Activation })
+	return results
+}
+
+func (r *Rehearsal) loop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.rehearse(time.Now())
+	for {
+		select {
+		case now := <-ticker.C:
+			r.rehearse(now)
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// rehearse builds a block at the activation of each fork due within the window,
+// on top of the current head.
+func (r *Rehearsal) rehearse(now time.Time) {
+	var (
+		head    = r.chain.CurrentBlock()
+		results = make(map[string]*Result)
+		failing int
+	)
+	for _, fork := range forks(r.chain.Config()) {
+		if fork.time <= head.Time || fork.time > uint64(now.Add(r.window).Unix()) {
+			continue // Activated or not due yet
+		}
+		result := &Result{
+			Fork:       fork.name,
+			Activation: fork.time,
+			Time:       now,
+			Parent:     head.Number.Uint64(),
+		}
+		rehearsalMeter.Mark(1)
+
+		block, err := r.builder.BuildDryRun(fork.time)
+		if err != nil {
+			result.Error = err.Error()
+			failureMeter.Mark(1)
+			failing++
+			log.Error("Fork rehearsal failed", "fork", fork.name, "activation", fork.time, "in", common.PrettyDuration(activationDelay(now, fork.time)), "err", err)
+		} else {
+			result.Hash, result.Txs, result.GasUsed = block.Hash(), len(block.Transactions()), block.GasUsed()
+			log.Info("Fork rehearsal succeeded", "fork", fork.name, "activation", fork.time, "in", common.PrettyDuration(activationDelay(now, fork.time)), "txs", result.Txs, "gas", result.GasUsed)
+		}
+		results[fmt.Sprintf("%s-%d", fork.name, fork.time)] = result
+	}
+	failingGauge.Update(int64(failing))
+
+	r.lock.Lock()
+	r.results = results
+	r.lock.Unlock()
+}
+
+// activationDelay returns the time left until the activation timestamp.
+func activationDelay(now time.Time, activation uint64) time.Duration {
+	return time.Unix(int64(activation), 0).Sub(now).Round(time.Second)
+}
@@ -0,0 +1,74 @@
+package forkrehearsal
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+type testChain struct {
+	config *params.ChainConfig
+	head   *types.Header
+}
+
+func (c *testChain) Config() *params.ChainConfig { return c.config }
+func (c *testChain) CurrentBlock() *types.Header { return c.head }
+
+type testBuilder struct {
+	failing map[uint64]bool
+	built   []uint64
+}
+
+func (b *testBuilder) BuildDryRun(timestamp uint64) (*types.Block, error) {
+	b.built = append(b.built, timestamp)
+	if b.failing[timestamp] {
+		return nil, errors.New("test failure")
+	}
+	return types.NewBlockWithHeader(&types.Header{Number: big.NewInt(11), Time: timestamp}), nil
+}
+
+func TestRehearse(t *testing.T) {
+	var (
+		canyon      = uint64(900)
+		operatorFee = uint64(2000)
+		jovian      = uint64(5000)
+		config      = &params.ChainConfig{CanyonTime: &canyon, OasysOperatorFeeTime: &operatorFee, JovianTime: &jovian, ZeroFeeTimes: []uint64{1500, 3000}}
+		chain       = &testChain{config: config, head: &types.Header{Number: big.NewInt(10), Time: 1000}}
+		builder     = &testBuilder{failing: map[uint64]bool{2000: true}}
+	)
+	r := New(chain, builder, time.Hour, time.Minute)
+	r.rehearse(time.Unix(1000, 0))
+
+	// Canyon is active at the head, Jovian not due within the window
+	if want := []uint64{1500, 2000, 3000}; len(builder.built) != len(want) || builder.built[0] != want[0] || builder.built[1] != want[1] || builder.built[2] != want[2] {
+		t.Fatalf("rehearsed timestamps mismatch: have %v, want %v", builder.built, want)
+	}
+	results := r.Results()
+	if len(results) != 3 {
+		t.Fatalf("result count mismatch: have %d, want 3", len(results))
+	}
+	for i, want := range []struct {
+		fork   string
+		failed bool
+	}{{"zerofee-start", false}, {"oasysOperatorFee", true}, {"zerofee-end", false}} {
+		if results[i].Fork != want.fork || (results[i].Error != "") != want.failed {
+			t.Errorf("result %d mismatch: have %s (err %q), want %s (failed %v)", i, results[i].Fork, results[i].Error, want.fork, want.failed)
+		}
+	}
+	// Forks activated since are no longer reported, Jovian is now due
+	chain.head = &types.Header{Number: big.NewInt(20), Time: 2500}
+	r.rehearse(time.Unix(2500, 0))
+
+	results = r.Results()
+	if len(results) != 2 || results[0].Fork != "zerofee-end" || results[1].Fork != "jovian" {
+		forks := make([]string, len(results))
+		for i, result := range results {
+			forks[i] = result.Fork
+		}
+		t.Fatalf("results mismatch after activation: have %v, want [zerofee-end jovian]", forks)
+	}
+}
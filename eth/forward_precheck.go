@@ -0,0 +1,60 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var forwardPrecheckRejectMeter = metrics.NewRegisteredMeter("sequencer/forward/precheck/reject", nil)
+
+// precheckForward executes a transaction about to be forwarded to the sequencer
+// against the pending state, returning the error the sequencer would reject it
+// with. Future nonces are allowed, as the transactions preceding them may still
+// be on their way to the sequencer, and reverting transactions are valid.
+func (b *EthAPIBackend) precheckForward(ctx context.Context, tx *types.Transaction) error {
+	if err := b.simulateForward(ctx, tx); err != nil {
+		forwardPrecheckRejectMeter.Mark(1)
+		return fmt.Errorf("rejected by local pre-execution: %w", err)
+	}
+	return nil
+}
+
+func (b *EthAPIBackend) simulateForward(ctx context.Context, tx *types.Transaction) error {
+	if tx.IsDepositTx() {
+		return core.ErrTxTypeNotSupported
+	}
+	state, header, err := b.StateAndHeaderByNumber(ctx, rpc.PendingBlockNumber)
+	if err != nil {
+		return err
+	}
+	signer := types.MakeSigner(b.ChainConfig(), header.Number, header.Time)
+	msg, err := core.TransactionToMessage(tx, signer, header.BaseFee)
+	if err != nil {
+		return err
+	}
+	if nonce := state.GetNonce(msg.From); nonce > msg.Nonce {
+		return fmt.Errorf("%w: address %v, tx: %d state: %d", core.ErrNonceTooLow, msg.From.Hex(), msg.Nonce, nonce)
+	}
+	msg.SkipAccountChecks = true
+
+	// Bound the execution as done for calls, the transaction is still forwarded
+	// if it doesn't complete in time
+	timeout := b.RPCEVMTimeout()
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	evm, vmError := b.GetEVM(ctx, msg, state, header, nil, nil)
+	timer := time.AfterFunc(timeout, evm.Cancel)
+	defer timer.Stop()
+
+	if _, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(header.GasLimit)); err != nil && !evm.Cancelled() {
+		return err
+	}
+	return vmError()
+}
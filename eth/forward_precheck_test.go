@@ -0,0 +1,108 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	precheckReverter = common.HexToAddress("0xdead01") // Reverts unconditionally
+	precheckLooper   = common.HexToAddress("0xdead02") // Loops until out of gas
+)
+
+// newTestPrecheckBackend creates a full node, not started, whose genesis funds
+// testAddr with a nonce of 5 and holds a reverting and a looping contract.
+func newTestPrecheckBackend(t *testing.T, timeout time.Duration) *EthAPIBackend {
+	stack, err := node.New(&node.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { stack.Close() })
+
+	config := ethconfig.Defaults
+	config.Genesis = core.DeveloperGenesisBlock(10_000_000, testAddr)
+	config.Genesis.Alloc[testAddr] = core.GenesisAccount{Balance: big.NewInt(params.Ether), Nonce: 5}
+	config.Genesis.Alloc[precheckReverter] = core.GenesisAccount{Balance: new(big.Int), Code: common.FromHex("0x60006000fd")}
+	config.Genesis.Alloc[precheckLooper] = core.GenesisAccount{Balance: new(big.Int), Code: common.FromHex("0x5b600056")}
+	config.SyncMode = downloader.FullSync
+	config.RPCEVMTimeout = timeout
+
+	eth, err := New(stack, &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return eth.APIBackend
+}
+
+func newPrecheckTx(t *testing.T, backend *EthAPIBackend, key []byte, nonce uint64, to common.Address, gas uint64) *types.Transaction {
+	signer := types.LatestSigner(backend.ChainConfig())
+	privkey, err := crypto.ToECDSA(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := types.SignNewTx(privkey, signer, &types.LegacyTx{
+		Nonce:    nonce,
+		To:       &to,
+		Gas:      gas,
+		GasPrice: big.NewInt(10 * params.GWei),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestPrecheckForward(t *testing.T) {
+	var (
+		backend = newTestPrecheckBackend(t, 0)
+		ctx     = context.Background()
+		funded  = crypto.FromECDSA(testKey)
+		empty   = common.FromHex("0x8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+	)
+	tests := []struct {
+		name string
+		tx   *types.Transaction
+		err  error
+	}{
+		{"nonce too low", newPrecheckTx(t, backend, funded, 4, common.Address{1}, 21000), core.ErrNonceTooLow},
+		{"insufficient funds", newPrecheckTx(t, backend, empty, 0, common.Address{1}, 21000), core.ErrInsufficientFunds},
+		{"current nonce", newPrecheckTx(t, backend, funded, 5, common.Address{1}, 21000), nil},
+		{"future nonce", newPrecheckTx(t, backend, funded, 10, common.Address{1}, 21000), nil},
+		{"reverting", newPrecheckTx(t, backend, funded, 5, precheckReverter, 100000), nil},
+		{"deposit", types.NewTx(&types.DepositTx{To: &common.Address{1}, Gas: 21000}), core.ErrTxTypeNotSupported},
+	}
+	for _, test := range tests {
+		err := backend.precheckForward(ctx, test.tx)
+		if test.err == nil {
+			if err != nil {
+				t.Errorf("%s: unexpected rejection: %v", test.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: have error %v, want %v", test.name, err, test.err)
+		}
+	}
+}
+
+func TestPrecheckForwardTimeout(t *testing.T) {
+	backend := newTestPrecheckBackend(t, time.Nanosecond)
+
+	// A transaction whose execution doesn't complete in time is still forwarded
+	tx := newPrecheckTx(t, backend, crypto.FromECDSA(testKey), 5, precheckLooper, 10_000_000)
+	if err := backend.precheckForward(context.Background(), tx); err != nil {
+		t.Fatalf("timed out transaction rejected: %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package eth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+)
+
+const (
+	// forwardTrackerSize is the number of forwarded transactions tracked, the
+	// oldest ones being forgotten beyond.
+	forwardTrackerSize = 16384
+
+	// forwardDropTimeout is the time allowed for a transaction accepted by the
+	// sequencer to be included before it is reported dropped.
+	forwardDropTimeout = 10 * time.Minute
+)
+
+// Statuses of the transactions reported by oasys_getTransactionStatus.
+const (
+	TxStatusForwarded = "forwarded" // Sent to the sequencer, awaiting its answer
+	TxStatusAccepted  = "accepted"  // Accepted by the sequencer, not included yet
+	TxStatusIncluded  = "included"  // Included in the local canonical chain
+	TxStatusDropped   = "dropped"   // Rejected by the sequencer or not included in time
+)
+
+// forwardedTx records the progress of a transaction forwarded to the sequencer.
+type forwardedTx struct {
+	forwarded time.Time
+	accepted  time.Time // Zero until the sequencer accepted the transaction
+	rejected  time.Time // Zero unless the sequencer rejected the transaction
+	err       string    // Rejection reason of the sequencer
+}
+
+// forwardTracker tracks the transactions forwarded to the sequencer, so their
+// status can be reported by replicas which don't include them.
+type forwardTracker struct {
+	lock sync.Mutex
+	txs  *lru.BasicLRU[common.Hash, *forwardedTx]
+}
+
+func (t *forwardTracker) get(hash common.Hash) (forwardedTx, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.txs == nil {
+		return forwardedTx{}, false
+	}
+	tx, ok := t.txs.Peek(hash)
+	if !ok {
+		return forwardedTx{}, false
+	}
+	return *tx, true
+}
+
+func (t *forwardTracker) update(hash common.Hash, fn func(tx *forwardedTx)) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.txs == nil {
+		txs := lru.NewBasicLRU[common.Hash, *forwardedTx](forwardTrackerSize)
+		t.txs = &txs
+	}
+	tx, ok := t.txs.Get(hash)
+	if !ok {
+		tx = new(forwardedTx)
+		t.txs.Add(hash, tx)
+	}
+	fn(tx)
+}
+
+// forwarded records that a transaction is being sent to the sequencer.
+func (t *forwardTracker) forwarded(hash common.Hash) {
+	t.update(hash, func(tx *forwardedTx) {
+		*tx = forwardedTx{forwarded: time.Now()}
+	})
+}
+
+// answered records the answer of the sequencer to a forwarded transaction.
+func (t *forwardTracker) answered(hash common.Hash, err error) {
+	t.update(hash, func(tx *forwardedTx) {
+		if err != nil {
+			tx.rejected, tx.err = time.Now(), err.Error()
+		} else {
+			tx.accepted = time.Now()
+		}
+	})
+}
+
+// TransactionStatus is the status of a transaction as reported by
+// oasys_getTransactionStatus. The timestamps are in unix seconds.
+type TransactionStatus struct {
+	Status      string          `json:"status"`
+	ForwardedAt *hexutil.Uint64 `json:"forwardedAt,omitempty"`
+	AcceptedAt  *hexutil.Uint64 `json:"acceptedAt,omitempty"`
+	IncludedAt  *hexutil.Uint64 `json:"includedAt,omitempty"` // Timestamp of the including block
+	DroppedAt   *hexutil.Uint64 `json:"droppedAt,omitempty"`
+	BlockHash   *common.Hash    `json:"blockHash,omitempty"`
+	BlockNumber *hexutil.Uint64 `json:"blockNumber,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+func unixTime(t time.Time) *hexutil.Uint64 {
+	if t.IsZero() {
+		return nil
+	}
+	ts := hexutil.Uint64(t.Unix())
+	return &ts
+}
+
+// TransactionStatus returns the status of a transaction forwarded to the
+// sequencer or included in the local chain, nil if neither is known.
+func (s *Ethereum) TransactionStatus(hash common.Hash) *TransactionStatus {
+	fwd, tracked := s.forwards.get(hash)
+
+	status := &TransactionStatus{
+		ForwardedAt: unixTime(fwd.forwarded),
+		AcceptedAt:  unixTime(fwd.accepted),
+	}
+	if tx, blockHash, blockNumber, _ := rawdb.ReadTransaction(s.chainDb, hash); tx != nil {
+		if header := s.blockchain.GetHeader(blockHash, blockNumber); header != nil {
+			number, included := hexutil.Uint64(blockNumber), hexutil.Uint64(header.Time)
+			status.Status = TxStatusIncluded
+			status.BlockHash, status.BlockNumber, status.IncludedAt = &blockHash, &number, &included
+			return status
+		}
+	}
+	switch {
+	case !tracked:
+		return nil
+	case !fwd.rejected.IsZero():
+		status.Status, status.DroppedAt, status.Error = TxStatusDropped, unixTime(fwd.rejected), fwd.err
+	case fwd.accepted.IsZero():
+		status.Status = TxStatusForwarded
+	case time.Since(fwd.accepted) > forwardDropTimeout:
+		status.Status, status.DroppedAt = TxStatusDropped, unixTime(fwd.accepted.Add(forwardDropTimeout))
+	default:
+		status.Status = TxStatusAccepted
+	}
+	return status
+}
@@ -0,0 +1,39 @@
+package eth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestForwardTracker(t *testing.T) {
+	var (
+		tracker  forwardTracker
+		accepted = common.Hash{1}
+		rejected = common.Hash{2}
+	)
+	if _, ok := tracker.get(accepted); ok {
+		t.Fatal("untracked transaction reported")
+	}
+	tracker.forwarded(accepted)
+	tracker.forwarded(rejected)
+	if tx, ok := tracker.get(accepted); !ok || tx.forwarded.IsZero() || !tx.accepted.IsZero() {
+		t.Fatalf("forwarded transaction mismatch: %+v", tx)
+	}
+	tracker.answered(accepted, nil)
+	tracker.answered(rejected, errors.New("nonce too low"))
+
+	if tx, _ := tracker.get(accepted); tx.accepted.IsZero() || !tx.rejected.IsZero() {
+		t.Errorf("accepted transaction mismatch: %+v", tx)
+	}
+	if tx, _ := tracker.get(rejected); tx.rejected.IsZero() || tx.err != "nonce too low" {
+		t.Errorf("rejected transaction mismatch: %+v", tx)
+	}
+	// Forwarding a transaction again resets its progress
+	tracker.forwarded(rejected)
+	if tx, _ := tracker.get(rejected); !tx.rejected.IsZero() || tx.err != "" || time.Since(tx.forwarded) > time.Minute {
+		t.Errorf("reforwarded transaction mismatch: %+v", tx)
+	}
+}
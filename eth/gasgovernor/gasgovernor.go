@@ -0,0 +1,210 @@
+// Package gasgovernor implements a controller adjusting the gas ceiling of the
+// built blocks to the observed block processing times, so that the replicas of
+// the chain running on slower hardware than the sequencer are not outrun.
+package gasgovernor
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// stepDivisor bounds the change of the gas ceiling per adjustment to
+	// 1/stepDivisor of its value.
+	stepDivisor = 16
+
+	// minFill is the minimum gas usage, in percent of the gas limit of the
+	// observed blocks, for their processing times to justify a ceiling raise.
+	minFill = 50
+
+	// processedChanSize is the size of channel listening to BlockProcessedEvent.
+	processedChanSize = 64
+)
+
+var (
+	ceilGauge = metrics.NewRegisteredGauge("gasgovernor/ceil", nil)
+	p95Gauge  = metrics.NewRegisteredGauge("gasgovernor/p95", nil)
+)
+
+// BlockChain defines the minimal set of methods needed to back the governor.
+type BlockChain interface {
+	SubscribeBlockProcessedEvent(ch chan<- core.BlockProcessedEvent) event.Subscription
+}
+
+// Miner defines the minimal set of methods needed to back the governor.
+type Miner interface {
+	SetGasCeil(ceil uint64)
+}
+
+// Config are the configuration parameters of the gas ceiling governor.
+type Config struct {
+	Target time.Duration // Aimed 95th percentile of the block processing time
+	Min    uint64        // Lowest gas ceiling set
+	Max    uint64        // Highest gas ceiling set
+	Window int           // Number of blocks observed between two adjustments
+}
+
+// DefaultConfig contains the default configuration of the governor.
+var DefaultConfig = Config{
+	Window: 64,
+}
+
+// Status is the state of the governor.
+type Status struct {
+	Ceil     uint64  `json:"ceil"`
+	Min      uint64  `json:"min"`
+	Max      uint64  `json:"max"`
+	TargetMs int64   `json:"targetMs"`
+	P95Ms    int64   `json:"p95Ms"`   // Processing time percentile of the last complete window
+	Fill     float64 `json:"fill"`    // Gas usage over gas limit of the last complete window
+	Samples  int     `json:"samples"` // Blocks observed in the current window
+}
+
+// Governor lowers the gas ceiling of the miner while the 95th percentile of the
+// processing time of the imported blocks exceeds the target, and raises it back
+// while the blocks are comfortably processed in time despite being well filled.
+type Governor struct {
+	chain  BlockChain
+	miner  Miner
+	config Config
+
+	lock   sync.Mutex
+	ceil   uint64          // Gas ceiling currently set
+	times  []time.Duration // Processing times of the current window
+	used   uint64          // Gas used by the blocks of the current window
+	limits uint64          // Gas limit of the blocks of the current window
+	p95    time.Duration   // Processing time percentile of the last complete window
+	fill   float64         // Gas usage of the last complete window
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a governor starting from the given gas ceiling, clamped within
+// the configured bounds.
+func New(chain BlockChain, miner Miner, config Config, ceil uint64) *Governor {
+	if config.Window <= 0 {
+		log.Warn("Sanitizing invalid gas governor window", "provided", config.Window, "updated", DefaultConfig.Window)
+		config.Window = DefaultConfig.Window
+	}
+	if config.Max == 0 {
+		config.Max = ceil
+	}
+	if config.Min == 0 || config.Min > config.Max {
+		log.Warn("Sanitizing invalid gas governor minimum", "provided", config.Min, "updated", config.Max/4)
+		config.Min = config.Max / 4
+	}
+	return &Governor{
+		chain:  chain,
+		miner:  miner,
+		config: config,
+		ceil:   clamp(ceil, config.Min, config.Max),
+		quit:   make(chan struct{}),
+	}
+}
+
+// Start applies the initial gas ceiling and launches the background loop
+// observing the imported blocks.
+func (g *Governor) Start() {
+	g.miner.SetGasCeil(g.ceil)
+	ceilGauge.Update(int64(g.ceil))
+
+	g.wg.Add(1)
+	go g.loop()
+}
+
+// Stop terminates the background loop.
+func (g *Governor) Stop() {
+	close(g.quit)
+	g.wg.Wait()
+}
+
+// Status returns the state of the governor.
+func (g *Governor) Status() *Status {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	return &Status{
+		Ceil:     g.ceil,
+		Min:      g.config.Min,
+		Max:      g.config.Max,
+		TargetMs: g.config.Target.Milliseconds(),
+		P95Ms:    g.p95.Milliseconds(),
+		Fill:     g.fill,
+		Samples:  len(g.times),
+	}
+}
+
+func (g *Governor) loop() {
+	defer g.wg.Done()
+
+	events := make(chan core.BlockProcessedEvent, processedChanSize)
+	sub := g.chain.SubscribeBlockProcessedEvent(events)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-events:
+			g.observe(ev)
+		case <-sub.Err():
+			return
+		case <-g.quit:
+			return
+		}
+	}
+}
+
+// observe records the processing time of an imported block, adjusting the gas
+// ceiling once the window is complete.
+func (g *Governor) observe(ev core.BlockProcessedEvent) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	g.times = append(g.times, ev.ProcTime)
+	g.used += ev.Block.GasUsed()
+	g.limits += ev.Block.GasLimit()
+	if len(g.times) < g.config.Window {
+		return
+	}
+	sort.Slice(g.times, func(i, j int) bool { return g.times[i] < g.times[j] })
+	g.p95 = g.times[(len(g.times)-1)*95/100]
+	g.fill = 0
+	if g.limits > 0 {
+		g.fill = float64(g.used) / float64(g.limits)
+	}
+	full := g.used*100 >= g.limits*minFill
+	g.times, g.used, g.limits = g.times[:0], 0, 0
+	p95Gauge.Update(g.p95.Milliseconds())
+
+	ceil := g.ceil
+	switch {
+	case g.p95 > g.config.Target:
+		ceil = clamp(ceil-ceil/stepDivisor, g.config.Min, g.config.Max)
+	case g.p95 < g.config.Target*3/4 && full:
+		ceil = clamp(ceil+ceil/stepDivisor, g.config.Min, g.config.Max)
+	}
+	if ceil == g.ceil {
+		return
+	}
+	log.Info("Adjusting gas ceiling", "old", g.ceil, "new", ceil, "p95", g.p95, "target", g.config.Target, "fill", g.fill)
+	g.ceil = ceil
+	g.miner.SetGasCeil(ceil)
+	ceilGauge.Update(int64(ceil))
+}
+
+// clamp bounds the given gas ceiling within [min, max].
+func clamp(ceil, min, max uint64) uint64 {
+	if ceil < min {
+		return min
+	}
+	if ceil > max {
+		return max
+	}
+	return ceil
+}
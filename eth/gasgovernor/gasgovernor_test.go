@@ -0,0 +1,67 @@
+package gasgovernor
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type testMiner struct{ ceil uint64 }
+
+func (m *testMiner) SetGasCeil(ceil uint64) { m.ceil = ceil }
+
+// feed observes a full window of blocks processed in the given time.
+func feed(g *Governor, proc time.Duration, used, limit uint64) {
+	for i := 0; i < g.config.Window; i++ {
+		block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(int64(i)), GasUsed: used, GasLimit: limit})
+		g.observe(core.BlockProcessedEvent{Block: block, ProcTime: proc})
+	}
+}
+
+func TestGovernor(t *testing.T) {
+	miner := new(testMiner)
+	g := New(nil, miner, Config{Target: 100 * time.Millisecond, Min: 10_000_000, Max: 32_000_000, Window: 20}, 40_000_000)
+	if g.ceil != 32_000_000 {
+		t.Fatalf("initial ceiling not clamped: have %d", g.ceil)
+	}
+	// Slow blocks lower the ceiling by a step per window, down to the minimum
+	feed(g, 200*time.Millisecond, 30_000_000, 32_000_000)
+	if want := uint64(30_000_000); miner.ceil != want {
+		t.Fatalf("ceiling mismatch after slow window: have %d, want %d", miner.ceil, want)
+	}
+	for i := 0; i < 32; i++ {
+		feed(g, 200*time.Millisecond, 30_000_000, 32_000_000)
+	}
+	if miner.ceil != 10_000_000 {
+		t.Fatalf("ceiling not bounded by the minimum: have %d", miner.ceil)
+	}
+	// Fast but mostly empty blocks leave the ceiling untouched
+	feed(g, time.Millisecond, 1_000_000, 10_000_000)
+	if miner.ceil != 10_000_000 {
+		t.Fatalf("ceiling raised on empty blocks: have %d", miner.ceil)
+	}
+	// Fast and full blocks raise it back
+	feed(g, time.Millisecond, 9_000_000, 10_000_000)
+	if want := uint64(10_625_000); miner.ceil != want {
+		t.Fatalf("ceiling mismatch after fast window: have %d, want %d", miner.ceil, want)
+	}
+	// A few slow outliers within the percentile are tolerated
+	before := miner.ceil
+	for i := 0; i < g.config.Window; i++ {
+		proc := 80 * time.Millisecond
+		if i == 0 {
+			proc = time.Second
+		}
+		block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(int64(i)), GasUsed: 9_000_000, GasLimit: 10_000_000})
+		g.observe(core.BlockProcessedEvent{Block: block, ProcTime: proc})
+	}
+	if miner.ceil != before {
+		t.Fatalf("ceiling changed within tolerance: have %d, want %d", miner.ceil, before)
+	}
+	if status := g.Status(); status.P95Ms != 80 || status.Samples != 0 {
+		t.Fatalf("status mismatch: %+v", status)
+	}
+}
@@ -0,0 +1,335 @@
+// Package gasprofile implements a sampling profiler re-executing one in every N
+// imported blocks to aggregate the gas used and the calls received by each
+// contract over the recent blocks, identifying the contracts dominating the
+// block space.
+package gasprofile
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// profileWindow is the number of sampled blocks the statistics are
+	// aggregated over.
+	profileWindow = 256
+
+	// chainEventChanSize is the size of channel listening to ChainEvent.
+	chainEventChanSize = 64
+)
+
+var (
+	sampledMeter   = metrics.NewRegisteredMeter("gasprofile/sampled", nil)
+	skippedMeter   = metrics.NewRegisteredMeter("gasprofile/skipped", nil)
+	gasMeter       = metrics.NewRegisteredMeter("gasprofile/gas", nil)
+	contractsGauge = metrics.NewRegisteredGauge("gasprofile/contracts", nil)
+	topShareGauge  = metrics.NewRegisteredGauge("gasprofile/topshare", nil)
+)
+
+// BlockChain defines the minimal set of methods needed to back the profiler.
+type BlockChain interface {
+	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
+	GetHeader(hash common.Hash, number uint64) *types.Header
+	StateAt(root common.Hash) (*state.StateDB, error)
+	Processor() core.Processor
+}
+
+// ContractStats is the gas used and the calls received by a contract. The gas
+// is the execution gas spent in the code of the contract itself, excluding its
+// subcalls as well as the intrinsic gas of the transactions.
+type ContractStats struct {
+	Address common.Address `json:"address"`
+	Calls   uint64         `json:"calls"`
+	Gas     uint64         `json:"gas"`
+}
+
+// Report aggregates the statistics of the contracts over the sampled blocks.
+type Report struct {
+	SampleRate uint64           `json:"sampleRate"` // One in SampleRate imported blocks is profiled
+	Blocks     int              `json:"blocks"`     // Number of blocks profiled
+	FirstBlock uint64           `json:"firstBlock"`
+	LastBlock  uint64           `json:"lastBlock"`
+	Gas        uint64           `json:"gas"`       // Execution gas of the transactions profiled, attributed or not
+	Contracts  []*ContractStats `json:"contracts"` // Contracts by decreasing gas used
+}
+
+// blockProfile is the statistics of the contracts of a sampled block.
+type blockProfile struct {
+	number    uint64
+	gas       uint64 // Execution gas of the transactions
+	contracts map[common.Address]*ContractStats
+}
+
+// Profiler samples one in every rate imported blocks and re-executes it in the
+// background to profile the gas used per contract.
+type Profiler struct {
+	chain BlockChain
+	rate  uint64
+
+	lock     sync.Mutex
+	imported uint64          // Number of blocks imported, for sampling
+	profiles []*blockProfile // Ring of the most recent block profiles
+	next     int             // Next profile slot to overwrite
+
+	blocks chan *types.Block // Sampled blocks awaiting profiling
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// New creates a gas profiler sampling one in every rate imported blocks.
+func New(chain BlockChain, rate uint64) *Profiler {
+	if rate == 0 {
+		rate = 1
+	}
+	return &Profiler{
+		chain:  chain,
+		rate:   rate,
+		blocks: make(chan *types.Block, 1),
+		quit:   make(chan struct{}),
+	}
+}
+
+// Start launches the background loops sampling and profiling the blocks.
+func (p *Profiler) Start() {
+	p.wg.Add(2)
+	go p.loop()
+	go p.profileLoop()
+}
+
+// Stop terminates the background loops.
+func (p *Profiler) Stop() {
+	close(p.quit)
+	p.wg.Wait()
+}
+
+// Report returns the statistics of the given number of contracts using the most
+// gas over the sampled blocks, all of them if zero.
+func (p *Profiler) Report(count int) *Report {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	report := &Report{
+		SampleRate: p.rate,
+		Blocks:     len(p.profiles),
+		Contracts:  []*ContractStats{},
+	}
+	totals := make(map[common.Address]*ContractStats)
+	for _, profile := range p.profiles {
+		if report.FirstBlock == 0 || profile.number < report.FirstBlock {
+			report.FirstBlock = profile.number
+		}
+		if profile.number > report.LastBlock {
+			report.LastBlock = profile.number
+		}
+		report.Gas += profile.gas
+		for addr, stats := range profile.contracts {
+			total, ok := totals[addr]
+			if !ok {
+				total = &ContractStats{Address: addr}
+				totals[addr] = total
+				report.Contracts = append(report.Contracts, total)
+			}
+			total.Calls += stats.Calls
+			total.Gas += stats.Gas
+		}
+	}
+	sort.Slice(report.Contracts, func(i, j int) bool {
+		if report.Contracts[i].Gas != report.Contracts[j].Gas {
+			return report.Contracts[i].Gas > report.Contracts[j].Gas
+		}
+		return report.Contracts[i].Calls > report.Contracts[j].Calls
+	})
+	if count > 0 && len(report.Contracts) > count {
+		report.Contracts = report.Contracts[:count]
+	}
+	return report
+}
+
+// loop samples the imported blocks, handing them to the profiling loop unless
+// it is still busy, so that the import is never held up.
+func (p *Profiler) loop() {
+	defer p.wg.Done()
+
+	events := make(chan core.ChainEvent, chainEventChanSize)
+	sub := p.chain.SubscribeChainEvent(events)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-events:
+			p.imported++
+			if p.imported%p.rate != 0 {
+				continue
+			}
+			select {
+			case p.blocks <- ev.Block:
+			default:
+				skippedMeter.Mark(1)
+			}
+		case <-sub.Err():
+			return
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+func (p *Profiler) profileLoop() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case block := <-p.blocks:
+			if err := p.profile(block); err != nil {
+				log.Debug("Failed to profile block gas", "number", block.NumberU64(), "hash", block.Hash(), "err", err)
+				skippedMeter.Mark(1)
+			}
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// profile re-executes the given block on top of its parent state, recording
+// the gas used per contract.
+func (p *Profiler) profile(block *types.Block) error {
+	parent := p.chain.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	statedb, err := p.chain.StateAt(parent.Root)
+	if err != nil {
+		return err
+	}
+	tracer := newGasTracer()
+	if _, _, _, err := p.chain.Processor().Process(block, statedb, vm.Config{Tracer: tracer}); err != nil {
+		return err
+	}
+	p.record(block.NumberU64(), tracer.gas, tracer.contracts)
+	return nil
+}
+
+// record stores the profile of a block, evicting the oldest one if the window
+// is full.
+func (p *Profiler) record(number uint64, gas uint64, contracts map[common.Address]*ContractStats) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	profile := &blockProfile{number: number, gas: gas, contracts: contracts}
+	if len(p.profiles) < profileWindow {
+		p.profiles = append(p.profiles, profile)
+	} else {
+		p.profiles[p.next] = profile
+		p.next = (p.next + 1) % profileWindow
+	}
+	sampledMeter.Mark(1)
+	gasMeter.Mark(int64(gas))
+
+	// Refresh the gauges over the whole window
+	var (
+		total, top uint64
+		distinct   = make(map[common.Address]uint64)
+	)
+	for _, profile := range p.profiles {
+		total += profile.gas
+		for addr, stats := range profile.contracts {
+			distinct[addr] += stats.Gas
+		}
+	}
+	for _, gas := range distinct {
+		if gas > top {
+			top = gas
+		}
+	}
+	contractsGauge.Update(int64(len(distinct)))
+	if total > 0 {
+		topShareGauge.Update(int64(top * 100 / total))
+	}
+}
+
+// gasFrame is a call being executed by the gas tracer.
+type gasFrame struct {
+	addr     common.Address
+	contract bool   // Whether code is executed, transfers to accounts being ignored
+	children uint64 // Gas used by the subcalls
+}
+
+// gasTracer attributes the execution gas of the calls to the contracts whose
+// code is executed.
+type gasTracer struct {
+	env       *vm.EVM
+	frames    []gasFrame
+	gas       uint64 // Execution gas of the transactions traced
+	contracts map[common.Address]*ContractStats
+}
+
+func newGasTracer() *gasTracer {
+	return &gasTracer{contracts: make(map[common.Address]*ContractStats)}
+}
+
+func (t *gasTracer) enter(to common.Address, create bool) {
+	contract := create || t.env.StateDB.GetCodeSize(to) > 0
+	t.frames = append(t.frames, gasFrame{addr: to, contract: contract})
+}
+
+func (t *gasTracer) exit(gasUsed uint64) {
+	if len(t.frames) == 0 {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+	t.frames = t.frames[:len(t.frames)-1]
+	if len(t.frames) > 0 {
+		t.frames[len(t.frames)-1].children += gasUsed
+	}
+	if !frame.contract {
+		return
+	}
+	stats, ok := t.contracts[frame.addr]
+	if !ok {
+		stats = &ContractStats{Address: frame.addr}
+		t.contracts[frame.addr] = stats
+	}
+	stats.Calls++
+	if gasUsed > frame.children {
+		stats.Gas += gasUsed - frame.children
+	}
+}
+
+func (t *gasTracer) CaptureTxStart(gasLimit uint64) {}
+
+func (t *gasTracer) CaptureTxEnd(restGas uint64) {}
+
+func (t *gasTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env, t.frames = env, t.frames[:0]
+	t.enter(to, create)
+}
+
+func (t *gasTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	t.gas += gasUsed
+	t.exit(gasUsed)
+}
+
+func (t *gasTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.enter(to, typ == vm.CREATE || typ == vm.CREATE2)
+}
+
+func (t *gasTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	t.exit(gasUsed)
+}
+
+func (t *gasTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *gasTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
@@ -0,0 +1,78 @@
+package gasprofile
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestGasProfile(t *testing.T) {
+	var (
+		account  = common.Address{0xee}
+		contract = common.Address{0xaa}
+		callee   = common.Address{0xbb}
+		created  = common.Address{0xcc}
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetCode(contract, []byte{0x00})
+	statedb.SetCode(callee, []byte{0x00})
+	env := vm.NewEVM(vm.BlockContext{}, vm.TxContext{}, statedb, params.TestChainConfig, vm.Config{})
+
+	// A contract calling another one, transferring to an account and creating
+	// a third contract
+	trace := func() (uint64, map[common.Address]*ContractStats) {
+		tracer := newGasTracer()
+		tracer.CaptureStart(env, account, contract, false, nil, 100000, nil)
+		tracer.CaptureEnter(vm.CALL, contract, callee, nil, 50000, nil)
+		tracer.CaptureExit(nil, 3000, nil)
+		tracer.CaptureEnter(vm.CALL, contract, account, nil, 2300, nil)
+		tracer.CaptureExit(nil, 100, nil)
+		tracer.CaptureEnter(vm.CREATE, contract, created, nil, 50000, nil)
+		tracer.CaptureExit(nil, 5000, nil)
+		tracer.CaptureEnd(nil, 10000, nil)
+		return tracer.gas, tracer.contracts
+	}
+	gas, contracts := trace()
+	if gas != 10000 {
+		t.Fatalf("traced gas mismatch: have %d, want 10000", gas)
+	}
+	if len(contracts) != 3 || contracts[account] != nil {
+		t.Fatalf("profiled contracts mismatch: %v", contracts)
+	}
+	if stats := contracts[contract]; stats.Calls != 1 || stats.Gas != 1900 {
+		t.Fatalf("caller stats mismatch: have %d calls, %d gas, want 1, 1900", stats.Calls, stats.Gas)
+	}
+	profiler := New(nil, 10)
+	profiler.record(10, gas, contracts)
+	gas, contracts = trace()
+	profiler.record(20, gas, contracts)
+
+	report := profiler.Report(2)
+	if report.Blocks != 2 || report.FirstBlock != 10 || report.LastBlock != 20 || report.Gas != 20000 {
+		t.Fatalf("report mismatch: %+v", report)
+	}
+	if len(report.Contracts) != 2 || report.Contracts[0].Address != created || report.Contracts[0].Gas != 10000 || report.Contracts[1].Address != callee || report.Contracts[1].Calls != 2 {
+		t.Fatalf("top contracts mismatch: %+v %+v", report.Contracts[0], report.Contracts[1])
+	}
+	// The gas of the transfer to an account is profiled without being attributed
+	var attributed uint64
+	for _, stats := range profiler.Report(0).Contracts {
+		attributed += stats.Gas
+	}
+	if want := report.Gas - 2*100; attributed != want {
+		t.Fatalf("attributed gas mismatch: have %d, want %d", attributed, want)
+	}
+	// Only the most recent sampled blocks are aggregated
+	for i := 0; i < profileWindow; i++ {
+		gas, contracts := trace()
+		profiler.record(uint64(30+i), gas, contracts)
+	}
+	if report := profiler.Report(0); report.Blocks != profileWindow || report.FirstBlock != 30 || len(report.Contracts) != 3 {
+		t.Fatalf("windowed report mismatch: %d blocks from #%d, %d contracts", report.Blocks, report.FirstBlock, len(report.Contracts))
+	}
+}
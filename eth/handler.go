@@ -53,6 +53,10 @@ const (
 	// All transactions with a higher size will be announced and need to be fetched
 	// by the peer.
 	txMaxBroadcastSize = 4096
+
+	// gossipChanSize is the number of gossiped blocks waiting for pre-import,
+	// further blocks being dropped until the queue drains.
+	gossipChanSize = 16
 )
 
 var syncChallengeTimeout = 15 * time.Second // Time allowance for a node to reply to the sync progress challenge
@@ -94,6 +98,9 @@ type handlerConfig struct {
 	EventMux       *event.TypeMux         // Legacy event mux, deprecate for `feed`
 	RequiredBlocks map[uint64]common.Hash // Hard coded map of required block hashes for sync challenges
 	NoTxGossip     bool                   // Disable P2P transaction gossip
+	BlockGossip    bool                   // Gossip locally built payloads and pre-import the ones of trusted peers
+
+	ConsensusClient func() interface{} // Optional consensus client info reported in the node info
 }
 
 type handler struct {
@@ -108,7 +115,10 @@ type handler struct {
 	chain    *core.BlockChain
 	maxPeers int
 
-	noTxGossip bool
+	noTxGossip      bool
+	blockGossip     bool
+	gossipCh        chan *types.Block // Blocks gossiped by trusted peers, waiting for pre-import
+	consensusClient func() interface{}
 
 	downloader   *downloader.Downloader
 	blockFetcher *fetcher.BlockFetcher
@@ -140,19 +150,22 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		config.EventMux = new(event.TypeMux) // Nicety initialization for tests
 	}
 	h := &handler{
-		networkID:      config.Network,
-		forkFilter:     forkid.NewFilter(config.Chain),
-		eventMux:       config.EventMux,
-		database:       config.Database,
-		txpool:         config.TxPool,
-		noTxGossip:     config.NoTxGossip,
-		chain:          config.Chain,
-		peers:          newPeerSet(),
-		merger:         config.Merger,
-		requiredBlocks: config.RequiredBlocks,
-		quitSync:       make(chan struct{}),
-		handlerDoneCh:  make(chan struct{}),
-		handlerStartCh: make(chan struct{}),
+		networkID:       config.Network,
+		forkFilter:      forkid.NewFilter(config.Chain),
+		eventMux:        config.EventMux,
+		database:        config.Database,
+		txpool:          config.TxPool,
+		noTxGossip:      config.NoTxGossip,
+		blockGossip:     config.BlockGossip,
+		gossipCh:        make(chan *types.Block, gossipChanSize),
+		consensusClient: config.ConsensusClient,
+		chain:           config.Chain,
+		peers:           newPeerSet(),
+		merger:          config.Merger,
+		requiredBlocks:  config.RequiredBlocks,
+		quitSync:        make(chan struct{}),
+		handlerDoneCh:   make(chan struct{}),
+		handlerStartCh:  make(chan struct{}),
 	}
 	if config.Sync == downloader.FullSync {
 		// The database seems empty as the current block is the genesis. Yet the snap
@@ -533,6 +546,12 @@ func (h *handler) Start(maxPeers int) {
 	// start peer handler tracker
 	h.wg.Add(1)
 	go h.protoTracker()
+
+	// pre-import the payloads gossiped by trusted peers
+	if h.blockGossip {
+		h.wg.Add(1)
+		go h.gossipImportLoop()
+	}
 }
 
 func (h *handler) Stop() {
@@ -597,6 +616,63 @@ func (h *handler) BroadcastBlock(block *types.Block, propagate bool) {
 	}
 }
 
+// GossipPayloadBlock propagates a locally built payload to the trusted peers right
+// after it's handed to the consensus client, before the block is confirmed by the
+// consensus layer, if block gossip is enabled. The replicas opt in by being added
+// as trusted peers of the sequencer, and pre-import it if they trust the local
+// node in turn, so the block is already known when their consensus client inserts
+// it. Other peers would disconnect on an unconfirmed block, so they are skipped.
+func (h *handler) GossipPayloadBlock(block *types.Block) {
+	if !h.blockGossip {
+		return
+	}
+	parent := h.chain.GetTd(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		log.Debug("Not gossiping dangling payload", "number", block.Number(), "hash", block.Hash())
+		return
+	}
+	var (
+		td    = new(big.Int).Add(parent, block.Difficulty())
+		peers []*ethPeer
+	)
+	for _, peer := range h.peers.peersWithoutBlock(block.Hash()) {
+		if peer.Peer.Info().Network.Trusted {
+			peers = append(peers, peer)
+		}
+	}
+	for _, peer := range peers {
+		peer.AsyncSendNewBlock(block, td)
+	}
+	log.Debug("Gossiped payload block", "number", block.Number(), "hash", block.Hash(), "recipients", len(peers))
+}
+
+// gossipImportLoop pre-imports the blocks gossiped by trusted peers, without
+// setting them as head.
+func (h *handler) gossipImportLoop() {
+	defer h.wg.Done()
+
+	for {
+		select {
+		case block := <-h.gossipCh:
+			if h.chain.HasBlock(block.Hash(), block.NumberU64()) {
+				continue
+			}
+			if !h.chain.HasBlockAndState(block.ParentHash(), block.NumberU64()-1) {
+				log.Debug("Skipping gossiped block with unknown parent", "number", block.Number(), "hash", block.Hash())
+				continue
+			}
+			start := time.Now()
+			if err := h.chain.InsertBlockWithoutSetHead(block); err != nil {
+				log.Debug("Failed to pre-import gossiped block", "number", block.Number(), "hash", block.Hash(), "err", err)
+				continue
+			}
+			log.Debug("Pre-imported gossiped block", "number", block.Number(), "hash", block.Hash(), "elapsed", common.PrettyDuration(time.Since(start)))
+		case <-h.quitSync:
+			return
+		}
+	}
+}
+
 // BroadcastTransactions will propagate a batch of transactions
 // - To a square root of all peers for non-blob transactions
 // - And, separately, as announcements to all peers which are not known to
@@ -26,6 +26,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 )
 
@@ -62,6 +63,15 @@ func (h *ethHandler) PeerInfo(id enode.ID) interface{} {
 	return nil
 }
 
+// ConsensusClient retrieves what the consensus client driving the node reported
+// about itself, or nil if unknown.
+func (h *ethHandler) ConsensusClient() interface{} {
+	if h.consensusClient == nil {
+		return nil
+	}
+	return h.consensusClient()
+}
+
 // AcceptTxs retrieves whether transaction processing is enabled on the node
 // or if inbound transactions should simply be dropped.
 func (h *ethHandler) AcceptTxs() bool {
@@ -136,9 +146,18 @@ func (h *ethHandler) handleBlockAnnounces(peer *eth.Peer, hashes []common.Hash,
 func (h *ethHandler) handleBlockBroadcast(peer *eth.Peer, block *types.Block, td *big.Int) error {
 	// Drop all incoming block announces from the p2p network if
 	// the chain already entered the pos stage and disconnect the
-	// remote peer.
+	// remote peer, unless the block is a payload gossiped by a
+	// trusted peer, which is pre-imported.
 	if h.merger.PoSFinalized() {
-		return errors.New("disallowed block broadcast")
+		if !h.blockGossip || !peer.Info().Network.Trusted {
+			return errors.New("disallowed block broadcast")
+		}
+		select {
+		case h.gossipCh <- block:
+		default:
+			log.Debug("Dropping gossiped block, pre-import queue full", "number", block.Number(), "hash", block.Hash())
+		}
+		return nil
 	}
 	// Schedule the block for import
 	h.blockFetcher.Enqueue(peer.ID(), block)
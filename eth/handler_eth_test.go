@@ -537,6 +537,68 @@ func testBroadcastBlock(t *testing.T, peers, bcasts int) {
 	}
 }
 
+// Tests that locally built payloads are gossiped to the trusted peers only.
+func TestGossipPayloadBlock(t *testing.T) {
+	t.Parallel()
+
+	source := newTestHandlerWithBlocks(1)
+	defer source.close()
+	source.handler.blockGossip = true
+
+	var (
+		genesis = source.chain.Genesis()
+		td      = source.chain.GetTd(genesis.Hash(), genesis.NumberU64())
+		sinks   = []*testEthHandler{new(testEthHandler), new(testEthHandler)}
+	)
+	for i, sink := range sinks {
+		sourcePipe, sinkPipe := p2p.MsgPipe()
+		defer sourcePipe.Close()
+		defer sinkPipe.Close()
+
+		// Only the first sink is trusted by the source
+		p2pPeer := p2p.NewPeerPipe(enode.ID{byte(i + 1)}, "", nil, sourcePipe)
+		if i == 0 {
+			p2pPeer = p2p.NewTrustedPeerPipe(enode.ID{byte(i + 1)}, "", nil, sourcePipe)
+		}
+		sourcePeer := eth.NewPeer(eth.ETH68, p2pPeer, sourcePipe, nil)
+		sinkPeer := eth.NewPeer(eth.ETH68, p2p.NewPeerPipe(enode.ID{0}, "", nil, sinkPipe), sinkPipe, nil)
+		defer sourcePeer.Close()
+		defer sinkPeer.Close()
+
+		go source.handler.runEthPeer(sourcePeer, func(peer *eth.Peer) error {
+			return eth.Handle((*ethHandler)(source.handler), peer)
+		})
+		if err := sinkPeer.Handshake(1, td, genesis.Hash(), genesis.Hash(), forkid.NewIDWithChain(source.chain), forkid.NewFilter(source.chain)); err != nil {
+			t.Fatalf("failed to run protocol handshake")
+		}
+		go eth.Handle(sink, sinkPeer)
+	}
+	blockChs := make([]chan *types.Block, len(sinks))
+	for i, sink := range sinks {
+		blockChs[i] = make(chan *types.Block, 1)
+		sub := sink.blockBroadcasts.Subscribe(blockChs[i])
+		defer sub.Unsubscribe()
+	}
+	time.Sleep(100 * time.Millisecond)
+	header := source.chain.CurrentBlock()
+	block := source.chain.GetBlock(header.Hash(), header.Number.Uint64())
+	source.handler.GossipPayloadBlock(block)
+
+	select {
+	case have := <-blockChs[0]:
+		if have.Hash() != block.Hash() {
+			t.Errorf("gossiped block mismatch: have %x, want %x", have.Hash(), block.Hash())
+		}
+	case <-time.After(time.Second):
+		t.Errorf("trusted peer didn't receive the payload block")
+	}
+	select {
+	case <-blockChs[1]:
+		t.Errorf("untrusted peer received the payload block")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 // Tests that a propagated malformed block (uncles or transactions don't match
 // with the hashes in the header) gets discarded and not broadcast forward.
 func TestBroadcastMalformedBlock67(t *testing.T) { testBroadcastMalformedBlock(t, eth.ETH67) }
@@ -0,0 +1,182 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// sequencerHealthTimeout is the time allowed for the sequencer endpoint to
+// respond to a reachability probe.
+const sequencerHealthTimeout = 2 * time.Second
+
+// sequencerHealthCacheTTL is the time the outcome of a sequencer reachability
+// probe is reused for, so that frequent readiness polls do not hammer it.
+const sequencerHealthCacheTTL = 5 * time.Second
+
+// HealthCheck is the outcome of a single health condition.
+type HealthCheck struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Message string `json:"message,omitempty"`
+}
+
+// HealthReport aggregates the health conditions of the node. Live reports
+// whether the node is making progress, Ready whether it should be serving
+// external traffic.
+type HealthReport struct {
+	Live   bool          `json:"live"`
+	Ready  bool          `json:"ready"`
+	Checks []HealthCheck `json:"checks"`
+}
+
+// MarkEngineUpdate records that the rollup node drove the execution engine
+// through the Engine API, which the health checks use as a heartbeat.
+func (s *Ethereum) MarkEngineUpdate() {
+	s.lastEngineUpdate.Store(time.Now().UnixNano())
+}
+
+// sequencerProbe caches the outcome of the last sequencer reachability probe.
+type sequencerProbe struct {
+	lock    sync.Mutex
+	check   HealthCheck
+	checked time.Time
+}
+
+// Health evaluates the rollup-aware health conditions of the node. The engine
+// heartbeat and head age determine liveness, readiness additionally requires
+// the node to be synced and its sequencer endpoint, if any, to be reachable.
+// The sequencer is probed at most once per sequencerHealthCacheTTL.
+func (s *Ethereum) Health(ctx context.Context) *HealthReport {
+	return s.health(ctx, true)
+}
+
+// Liveness evaluates the health conditions of the node like Health, but without
+// any network I/O: the sequencer reachability is the outcome of the last probe.
+func (s *Ethereum) Liveness() *HealthReport {
+	return s.health(context.Background(), false)
+}
+
+func (s *Ethereum) health(ctx context.Context, probe bool) *HealthReport {
+	var (
+		engine    = s.checkEngineHeartbeat()
+		head      = s.checkHeadAge()
+		synced    = HealthCheck{Name: "sync", Healthy: s.Synced()}
+		sequencer = s.checkSequencer(ctx, probe)
+		replica   = s.checkReplica()
+	)
+	if !synced.Healthy {
+		synced.Message = "node is syncing"
+	}
+	report := &HealthReport{
+		Live:   engine.Healthy && head.Healthy,
+		Checks: []HealthCheck{engine, head, synced, sequencer, replica},
+	}
+	report.Ready = report.Live && synced.Healthy && sequencer.Healthy && replica.Healthy
+	return report
+}
+
+func (s *Ethereum) checkEngineHeartbeat() HealthCheck {
+	check := HealthCheck{Name: "engine", Healthy: true}
+	if s.config.RollupHealthMaxEngineAge == 0 {
+		return check
+	}
+	last := s.lastEngineUpdate.Load()
+	if last == 0 {
+		check.Healthy, check.Message = false, "no engine API update received"
+		return check
+	}
+	if age := time.Since(time.Unix(0, last)); age > s.config.RollupHealthMaxEngineAge {
+		check.Healthy = false
+		check.Message = fmt.Sprintf("last engine API update %v ago", common.PrettyDuration(age))
+	}
+	return check
+}
+
+func (s *Ethereum) checkHeadAge() HealthCheck {
+	check := HealthCheck{Name: "head", Healthy: true}
+	if s.config.RollupHealthMaxHeadAge == 0 {
+		return check
+	}
+	head := s.blockchain.CurrentBlock()
+	if age := time.Since(time.Unix(int64(head.Time), 0)); age > s.config.RollupHealthMaxHeadAge {
+		check.Healthy = false
+		check.Message = fmt.Sprintf("unsafe head #%d is %v old", head.Number, common.PrettyDuration(age))
+	}
+	return check
+}
+
+// checkSequencer reports the reachability of the sequencer endpoint, probing it
+// if allowed and the last outcome is stale. Concurrent probes are serialized.
+func (s *Ethereum) checkSequencer(ctx context.Context, probe bool) HealthCheck {
+	check := HealthCheck{Name: "sequencer", Healthy: true}
+	sequencer := s.sequencerClient()
+	if sequencer == nil {
+		return check
+	}
+	s.sequencerHealth.lock.Lock()
+	defer s.sequencerHealth.lock.Unlock()
+
+	if last := s.sequencerHealth.checked; !last.IsZero() && (!probe || time.Since(last) < sequencerHealthCacheTTL) {
+		return s.sequencerHealth.check
+	}
+	if !probe {
+		check.Healthy, check.Message = false, "sequencer not probed yet"
+		return check
+	}
+	ctx, cancel := context.WithTimeout(ctx, sequencerHealthTimeout)
+	defer cancel()
+
+	var chainID string
+	if err := sequencer.CallContext(ctx, &chainID, "eth_chainId"); err != nil {
+		check.Healthy = false
+		check.Message = fmt.Sprintf("sequencer unreachable: %v", err)
+	}
+	s.sequencerHealth.check, s.sequencerHealth.checked = check, time.Now()
+	return check
+}
+
+func (s *Ethereum) checkReplica() HealthCheck {
+	check := HealthCheck{Name: "replica", Healthy: true}
+	if s.replicaChecker == nil {
+		return check
+	}
+	if div := s.replicaChecker.Divergence(); div != nil {
+		check.Healthy = false
+		check.Message = fmt.Sprintf("diverged from sequencer at block #%d (local %x, remote %x)", div.Number, div.LocalHash, div.RemoteHash)
+	}
+	return check
+}
+
+// healthHandler serves the liveness (/healthz) or readiness (/readyz) state of
+// the node for load balancers, replying 503 if the node is not healthy. Only the
+// readiness checks reach out to the sequencer, so that an upstream outage does
+// not get the node restarted.
+type healthHandler struct {
+	eth       *Ethereum
+	readiness bool
+}
+
+func (h *healthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		report  *HealthReport
+		healthy bool
+	)
+	if h.readiness {
+		report = h.eth.Health(r.Context())
+		healthy = report.Ready
+	} else {
+		report = h.eth.Liveness()
+		healthy = report.Live
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if !healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
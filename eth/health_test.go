@@ -0,0 +1,97 @@
+package eth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testSequencer is a sequencer endpoint counting its reachability probes.
+type testSequencer struct {
+	probes atomic.Int32
+}
+
+func (s *testSequencer) ChainId() hexutil.Uint64 {
+	s.probes.Add(1)
+	return 1
+}
+
+// newTestHealthNode creates a synced node forwarding to the given sequencer,
+// unreachable if nil.
+func newTestHealthNode(t *testing.T, sequencer *testSequencer) *Ethereum {
+	handler := newTestHandlerWithBlocks(1)
+	t.Cleanup(handler.close)
+	handler.handler.synced.Store(true)
+
+	server := rpc.NewServer()
+	if sequencer != nil {
+		if err := server.RegisterName("eth", sequencer); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(server.Stop)
+
+	return &Ethereum{
+		config:        &ethconfig.Config{},
+		blockchain:    handler.chain,
+		handler:       handler.handler,
+		seqRPCService: rpc.DialInProc(server),
+	}
+}
+
+func TestHealthSequencerProbe(t *testing.T) {
+	sequencer := new(testSequencer)
+	eth := newTestHealthNode(t, sequencer)
+
+	// Liveness never reaches out to the sequencer
+	if report := eth.Liveness(); !report.Live || report.Ready {
+		t.Fatalf("liveness mismatch before probing: live %v, ready %v", report.Live, report.Ready)
+	}
+	if probes := sequencer.probes.Load(); probes != 0 {
+		t.Fatalf("liveness probed the sequencer %d times", probes)
+	}
+	// Readiness probes it once, then reuses the outcome
+	for i := 0; i < 3; i++ {
+		if report := eth.Health(context.Background()); !report.Ready {
+			t.Fatalf("node not ready: %+v", report.Checks)
+		}
+	}
+	if probes := sequencer.probes.Load(); probes != 1 {
+		t.Fatalf("sequencer probe count mismatch: have %d, want 1", probes)
+	}
+	if report := eth.Liveness(); !report.Ready {
+		t.Fatalf("liveness not reporting the last probe: %+v", report.Checks)
+	}
+	// A stale outcome is probed again
+	eth.sequencerHealth.checked = time.Now().Add(-sequencerHealthCacheTTL)
+	eth.Health(context.Background())
+	if probes := sequencer.probes.Load(); probes != 2 {
+		t.Fatalf("sequencer probe count mismatch: have %d, want 2", probes)
+	}
+}
+
+// Tests that an unreachable sequencer fails the readiness but not the liveness
+// of the node.
+func TestHealthHandlerSequencerDown(t *testing.T) {
+	eth := newTestHealthNode(t, nil)
+
+	serve := func(readiness bool) int {
+		rec := httptest.NewRecorder()
+		handler := &healthHandler{eth: eth, readiness: readiness}
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rec.Code
+	}
+	if code := serve(true); code != http.StatusServiceUnavailable {
+		t.Fatalf("readiness status mismatch: have %d, want %d", code, http.StatusServiceUnavailable)
+	}
+	if code := serve(false); code != http.StatusOK {
+		t.Fatalf("liveness status mismatch: have %d, want %d", code, http.StatusOK)
+	}
+}
@@ -0,0 +1,226 @@
+// Package inclusion implements a monitor sampling the transactions submitted to
+// the node and measuring their time to inclusion and drop rate, to objectively
+// track the fairness and performance of the sequencer.
+package inclusion
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// dropTimeout is the time allowed for a sampled transaction to be included
+	// before it is counted as dropped.
+	dropTimeout = 10 * time.Minute
+
+	// expireInterval is the time between two scans for dropped transactions.
+	expireInterval = 30 * time.Second
+
+	// maxPending is the maximum number of sampled transactions awaiting
+	// inclusion, new samples being skipped beyond.
+	maxPending = 16384
+
+	// latencySamples is the number of recent inclusion latencies the reported
+	// percentiles are computed over.
+	latencySamples = 1024
+
+	// chainEventChanSize is the size of channel listening to ChainEvent.
+	chainEventChanSize = 64
+)
+
+var (
+	trackedMeter     = metrics.NewRegisteredMeter("inclusion/tracked", nil)
+	includedMeter    = metrics.NewRegisteredMeter("inclusion/included", nil)
+	droppedMeter     = metrics.NewRegisteredMeter("inclusion/dropped", nil)
+	pendingGauge     = metrics.NewRegisteredGauge("inclusion/pending", nil)
+	latencyHistogram = metrics.NewRegisteredHistogram("inclusion/latency", nil, metrics.NewExpDecaySample(1028, 0.015))
+)
+
+// BlockChain defines the minimal set of methods needed to back the monitor.
+type BlockChain interface {
+	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
+}
+
+// Report summarizes the inclusion of the sampled transactions since the monitor
+// started. Latencies are in milliseconds, over the most recent inclusions.
+type Report struct {
+	SampleRate uint64  `json:"sampleRate"` // One in SampleRate submitted transactions is tracked
+	Tracked    uint64  `json:"tracked"`
+	Included   uint64  `json:"included"`
+	Dropped    uint64  `json:"dropped"`
+	Pending    int     `json:"pending"`
+	DropRate   float64 `json:"dropRate"` // Dropped over settled (included or dropped) transactions
+	LatencyP50 int64   `json:"latencyP50"`
+	LatencyP90 int64   `json:"latencyP90"`
+	LatencyP99 int64   `json:"latencyP99"`
+	LatencyMax int64   `json:"latencyMax"`
+}
+
+// Monitor tracks one in every rate transactions submitted to the node until it
+// is included in the canonical chain or considered dropped.
+type Monitor struct {
+	chain BlockChain
+	rate  uint64
+
+	lock      sync.Mutex
+	submitted uint64                    // Number of transactions submitted, for sampling
+	pending   map[common.Hash]time.Time // Sampled transactions awaiting inclusion
+	latencies []time.Duration           // Ring of the most recent inclusion latencies
+	next      int                       // Next latency slot to overwrite
+	tracked   uint64
+	included  uint64
+	dropped   uint64
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates an inclusion monitor sampling one in every rate transactions.
+func New(chain BlockChain, rate uint64) *Monitor {
+	if rate == 0 {
+		rate = 1
+	}
+	return &Monitor{
+		chain:   chain,
+		rate:    rate,
+		pending: make(map[common.Hash]time.Time),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start launches the background loop watching the chain for the sampled
+// transactions.
+func (m *Monitor) Start() {
+	m.wg.Add(1)
+	go m.loop()
+}
+
+// Stop terminates the background loop.
+func (m *Monitor) Stop() {
+	close(m.quit)
+	m.wg.Wait()
+}
+
+// Submitted records a transaction accepted by the node, either pooled locally
+// or forwarded to the sequencer, tracking it if sampled.
+func (m *Monitor) Submitted(hash common.Hash) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.submitted++
+	if m.submitted%m.rate != 0 || len(m.pending) >= maxPending {
+		return
+	}
+	if _, ok := m.pending[hash]; ok {
+		return
+	}
+	m.pending[hash] = time.Now()
+	m.tracked++
+
+	trackedMeter.Mark(1)
+	pendingGauge.Update(int64(len(m.pending)))
+}
+
+// Report returns the inclusion statistics of the sampled transactions.
+func (m *Monitor) Report() *Report {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	report := &Report{
+		SampleRate: m.rate,
+		Tracked:    m.tracked,
+		Included:   m.included,
+		Dropped:    m.dropped,
+		Pending:    len(m.pending),
+	}
+	if settled := m.included + m.dropped; settled > 0 {
+		report.DropRate = float64(m.dropped) / float64(settled)
+	}
+	if len(m.latencies) > 0 {
+		sorted := make([]time.Duration, len(m.latencies))
+		copy(sorted, m.latencies)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		percentile := func(p int) int64 {
+			return sorted[(len(sorted)-1)*p/100].Milliseconds()
+		}
+		report.LatencyP50, report.LatencyP90, report.LatencyP99 = percentile(50), percentile(90), percentile(99)
+		report.LatencyMax = sorted[len(sorted)-1].Milliseconds()
+	}
+	return report
+}
+
+func (m *Monitor) loop() {
+	defer m.wg.Done()
+
+	events := make(chan core.ChainEvent, chainEventChanSize)
+	sub := m.chain.SubscribeChainEvent(events)
+	defer sub.Unsubscribe()
+
+	ticker := time.NewTicker(expireInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case ev := <-events:
+			m.includeBlock(ev)
+		case now := <-ticker.C:
+			m.expire(now)
+		case <-sub.Err():
+			return
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// includeBlock settles the sampled transactions included in a new canonical block.
+func (m *Monitor) includeBlock(ev core.ChainEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if len(m.pending) == 0 {
+		return
+	}
+	now := time.Now()
+	for _, tx := range ev.Block.Transactions() {
+		submitted, ok := m.pending[tx.Hash()]
+		if !ok {
+			continue
+		}
+		delete(m.pending, tx.Hash())
+		m.included++
+
+		latency := now.Sub(submitted)
+		if len(m.latencies) < latencySamples {
+			m.latencies = append(m.latencies, latency)
+		} else {
+			m.latencies[m.next] = latency
+			m.next = (m.next + 1) % latencySamples
+		}
+		includedMeter.Mark(1)
+		latencyHistogram.Update(latency.Milliseconds())
+	}
+	pendingGauge.Update(int64(len(m.pending)))
+}
+
+// expire counts the sampled transactions not included in time as dropped.
+func (m *Monitor) expire(now time.Time) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for hash, submitted := range m.pending {
+		if now.Sub(submitted) > dropTimeout {
+			delete(m.pending, hash)
+			m.dropped++
+			droppedMeter.Mark(1)
+		}
+	}
+	pendingGauge.Update(int64(len(m.pending)))
+}
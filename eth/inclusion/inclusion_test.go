@@ -0,0 +1,40 @@
+package inclusion
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestInclusion(t *testing.T) {
+	var txs []*types.Transaction
+	for i := 0; i < 6; i++ {
+		txs = append(txs, types.NewTransaction(uint64(i), common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil))
+	}
+	// Sample one in two transactions, tracking the odd ones
+	monitor := New(nil, 2)
+	for _, tx := range txs {
+		monitor.Submitted(tx.Hash())
+	}
+	if report := monitor.Report(); report.Tracked != 3 || report.Pending != 3 {
+		t.Fatalf("tracked mismatch: have %d/%d pending, want 3/3", report.Tracked, report.Pending)
+	}
+	// Include two of the sampled transactions, along with an unsampled one
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}).WithBody(txs[:4], nil)
+	monitor.includeBlock(core.ChainEvent{Block: block})
+
+	// Drop the last sampled transaction
+	monitor.expire(time.Now().Add(dropTimeout + time.Second))
+
+	report := monitor.Report()
+	if report.Included != 2 || report.Dropped != 1 || report.Pending != 0 {
+		t.Fatalf("settlement mismatch: have %d included, %d dropped, %d pending, want 2, 1, 0", report.Included, report.Dropped, report.Pending)
+	}
+	if want := 1.0 / 3; report.DropRate != want {
+		t.Errorf("drop rate mismatch: have %v, want %v", report.DropRate, want)
+	}
+}
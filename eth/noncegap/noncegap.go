@@ -0,0 +1,162 @@
+// Package noncegap implements a monitor detecting the accounts whose queued
+// transactions are blocked by a gap in their nonces, optionally evicting them
+// once stale.
+package noncegap
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// scanInterval is the time between two scans of the transaction pool.
+const scanInterval = 30 * time.Second
+
+var (
+	gappedGauge  = metrics.NewRegisteredGauge("noncegap/accounts", nil)
+	staleGauge   = metrics.NewRegisteredGauge("noncegap/stale", nil)
+	evictedMeter = metrics.NewRegisteredMeter("noncegap/evicted", nil)
+)
+
+// TxPool defines the minimal set of methods needed to back the monitor.
+type TxPool interface {
+	Content() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction)
+	Nonce(addr common.Address) uint64
+	Remove(hash common.Hash) bool
+}
+
+// Gap describes an account whose queued transactions are blocked by a nonce gap.
+type Gap struct {
+	Address     common.Address `json:"address"`
+	PoolNonce   uint64         `json:"poolNonce"`   // Next nonce executable by the pool
+	QueuedNonce uint64         `json:"queuedNonce"` // Lowest nonce of the queued transactions
+	Queued      int            `json:"queued"`      // Number of queued transactions
+	Since       time.Time      `json:"since"`       // First time the gap was detected
+	Stale       bool           `json:"stale"`       // Whether the gap is older than the threshold
+}
+
+// Monitor periodically scans the transaction pool for nonce gaps.
+type Monitor struct {
+	pool      TxPool
+	threshold time.Duration
+	evict     bool
+
+	lock sync.Mutex
+	gaps map[common.Address]*Gap
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a monitor reporting the gaps older than the threshold as stale,
+// evicting their queued transactions if requested.
+func New(pool TxPool, threshold time.Duration, evict bool) *Monitor {
+	return &Monitor{
+		pool:      pool,
+		threshold: threshold,
+		evict:     evict,
+		gaps:      make(map[common.Address]*Gap),
+		quit:      make(chan struct{}),
+	}
+}
+
+// Start launches the background loop scanning the pool.
+func (m *Monitor) Start() {
+	m.wg.Add(1)
+	go m.loop()
+}
+
+// Stop terminates the background loop.
+func (m *Monitor) Stop() {
+	close(m.quit)
+	m.wg.Wait()
+}
+
+// Gaps returns the accounts currently blocked by a nonce gap, the oldest first.
+func (m *Monitor) Gaps() []*Gap {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	gaps := make([]*Gap, 0, len(m.gaps))
+	for _, gap := range m.gaps {
+		g := *gap
+		gaps = append(gaps, &g)
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i].Since.Before(gaps[j].Since) })
+	return gaps
+}
+
+func (m *Monitor) loop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(scanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			m.scan(now)
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// scan updates the gaps from the content of the pool, evicting the queued
+// transactions of the stale ones if enabled.
+func (m *Monitor) scan(now time.Time) {
+	_, queued := m.pool.Content()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var (
+		gaps  = make(map[common.Address]*Gap)
+		stale int
+	)
+	for addr, txs := range queued {
+		if len(txs) == 0 {
+			continue
+		}
+		nonce := m.pool.Nonce(addr)
+		if txs[0].Nonce() <= nonce {
+			continue // Queued for another reason than a gap, e.g. underfunded
+		}
+		gap := &Gap{Address: addr, PoolNonce: nonce, QueuedNonce: txs[0].Nonce(), Queued: len(txs), Since: now}
+		if prev, ok := m.gaps[addr]; ok {
+			gap.Since = prev.Since
+		}
+		if now.Sub(gap.Since) < m.threshold {
+			gaps[addr] = gap
+			continue
+		}
+		gap.Stale = true
+		if !m.evict {
+			gaps[addr] = gap
+			stale++
+			continue
+		}
+		var evicted int
+		for _, tx := range txs {
+			if m.pool.Remove(tx.Hash()) {
+				evicted++
+			}
+		}
+		evictedMeter.Mark(int64(evicted))
+		log.Warn("Evicted transactions blocked by a nonce gap", "address", addr, "pooled", nonce, "queued", gap.QueuedNonce, "evicted", evicted, "since", gap.Since)
+	}
+	for addr, gap := range gaps {
+		if prev, ok := m.gaps[addr]; gap.Stale && (!ok || !prev.Stale) {
+			log.Warn("Transactions blocked by a nonce gap", "address", addr, "pooled", gap.PoolNonce, "queued", gap.QueuedNonce, "count", gap.Queued, "since", gap.Since)
+		}
+	}
+	m.gaps = gaps
+
+	gappedGauge.Update(int64(len(gaps)))
+	staleGauge.Update(int64(stale))
+}
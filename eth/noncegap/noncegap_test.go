@@ -0,0 +1,73 @@
+package noncegap
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type testPool struct {
+	queued  map[common.Address][]*types.Transaction
+	nonces  map[common.Address]uint64
+	removed map[common.Hash]bool
+}
+
+func (p *testPool) Content() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
+	return nil, p.queued
+}
+
+func (p *testPool) Nonce(addr common.Address) uint64 { return p.nonces[addr] }
+
+func (p *testPool) Remove(hash common.Hash) bool {
+	p.removed[hash] = true
+	return true
+}
+
+func TestNonceGaps(t *testing.T) {
+	var (
+		gapped   = common.Address{0x01}
+		underpay = common.Address{0x02}
+		tx       = func(nonce uint64) *types.Transaction {
+			return types.NewTransaction(nonce, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+		}
+		pool = &testPool{
+			queued: map[common.Address][]*types.Transaction{
+				gapped:   {tx(5), tx(6)},
+				underpay: {tx(2)},
+			},
+			nonces:  map[common.Address]uint64{gapped: 3, underpay: 2},
+			removed: make(map[common.Hash]bool),
+		}
+		start = time.Now()
+	)
+	monitor := New(pool, time.Minute, false)
+	monitor.scan(start)
+
+	gaps := monitor.Gaps()
+	if len(gaps) != 1 || gaps[0].Address != gapped || gaps[0].Stale {
+		t.Fatalf("gaps mismatch: %+v", gaps)
+	}
+	if gaps[0].PoolNonce != 3 || gaps[0].QueuedNonce != 5 || gaps[0].Queued != 2 {
+		t.Fatalf("gap details mismatch: %+v", gaps[0])
+	}
+	// The gap must be stale once it persisted beyond the threshold
+	monitor.scan(start.Add(2 * time.Minute))
+	if gaps := monitor.Gaps(); len(gaps) != 1 || !gaps[0].Stale || !gaps[0].Since.Equal(start) {
+		t.Fatalf("stale gap mismatch: %+v", gaps)
+	}
+	if len(pool.removed) != 0 {
+		t.Fatal("transactions evicted while eviction disabled")
+	}
+	// Stale gaps must be evicted if enabled
+	monitor.evict = true
+	monitor.scan(start.Add(3 * time.Minute))
+	if len(pool.removed) != 2 {
+		t.Fatalf("evicted count mismatch: have %d, want 2", len(pool.removed))
+	}
+	if gaps := monitor.Gaps(); len(gaps) != 0 {
+		t.Fatalf("evicted gap still reported: %+v", gaps)
+	}
+}
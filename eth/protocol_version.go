@@ -0,0 +1,137 @@
+package eth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	requiredVersionGauge    = metrics.NewRegisteredGaugeInfo("rollup/protocol/required", nil)
+	recommendedVersionGauge = metrics.NewRegisteredGaugeInfo("rollup/protocol/recommended", nil)
+	haltScheduledGauge      = metrics.NewRegisteredGauge("rollup/protocol/halt", nil)
+)
+
+// ProtocolVersionStatus describes the protocol versions signaled by the rollup
+// node through the Engine API, along with the halt scheduled if any.
+type ProtocolVersionStatus struct {
+	Local       params.ProtocolVersion  `json:"local"`
+	Required    *params.ProtocolVersion `json:"required,omitempty"`
+	Recommended *params.ProtocolVersion `json:"recommended,omitempty"`
+	Signaled    *time.Time              `json:"signaled,omitempty"` // Time of the last signal
+	HaltAt      *time.Time              `json:"haltAt,omitempty"`   // Time the node is scheduled to halt at
+	HaltReason  string                  `json:"haltReason,omitempty"`
+}
+
+// protocolVersions tracks the protocol versions signaled through the Engine API
+// and the halt scheduled.
+type protocolVersions struct {
+	lock        sync.Mutex
+	required    *params.ProtocolVersion
+	recommended *params.ProtocolVersion
+	signaled    time.Time
+
+	haltAt     time.Time
+	haltReason string
+	haltTimer  *time.Timer
+}
+
+// SetProtocolVersionSignal records the protocol versions signaled by the rollup
+// node, to be reported by oasys_protocolVersions.
+func (s *Ethereum) SetProtocolVersionSignal(recommended, required params.ProtocolVersion) {
+	s.protocolVersions.lock.Lock()
+	defer s.protocolVersions.lock.Unlock()
+
+	s.protocolVersions.recommended = &recommended
+	s.protocolVersions.required = &required
+	s.protocolVersions.signaled = time.Now()
+
+	requiredVersionGauge.Update(metrics.GaugeInfoValue{"version": required.String()})
+	recommendedVersionGauge.Update(metrics.GaugeInfoValue{"version": recommended.String()})
+}
+
+// ProtocolVersions returns the local and signaled protocol versions, and the
+// halt scheduled if any.
+func (s *Ethereum) ProtocolVersions() *ProtocolVersionStatus {
+	s.protocolVersions.lock.Lock()
+	defer s.protocolVersions.lock.Unlock()
+
+	status := &ProtocolVersionStatus{
+		Local:       params.OPStackSupport,
+		Required:    s.protocolVersions.required,
+		Recommended: s.protocolVersions.recommended,
+	}
+	if signaled := s.protocolVersions.signaled; !signaled.IsZero() {
+		status.Signaled = &signaled
+	}
+	if haltAt := s.protocolVersions.haltAt; !haltAt.IsZero() {
+		status.HaltAt, status.HaltReason = &haltAt, s.protocolVersions.haltReason
+	}
+	return status
+}
+
+// ScheduleHalt schedules the node to shut down at the given time, replacing the
+// halt previously scheduled. A zero time cancels the scheduled halt.
+func (s *Ethereum) ScheduleHalt(at time.Time, reason string) {
+	s.protocolVersions.lock.Lock()
+	defer s.protocolVersions.lock.Unlock()
+
+	s.scheduleHalt(at, reason)
+}
+
+func (s *Ethereum) scheduleHalt(at time.Time, reason string) {
+	pv := &s.protocolVersions
+	if pv.haltTimer != nil {
+		pv.haltTimer.Stop()
+		pv.haltTimer = nil
+	}
+	pv.haltAt, pv.haltReason = at, reason
+	if at.IsZero() {
+		log.Info("Cancelled scheduled halt")
+		haltScheduledGauge.Update(0)
+		return
+	}
+	log.Warn("Scheduled node halt", "at", at, "in", common.PrettyDuration(time.Until(at)), "reason", reason)
+	haltScheduledGauge.Update(at.Unix())
+
+	pv.haltTimer = time.AfterFunc(time.Until(at), func() {
+		log.Error("Halting node as scheduled", "reason", reason)
+		if err := s.nodeCloser(); err != nil {
+			log.Error("Failed to halt node", "err", err)
+		}
+	})
+}
+
+// haltOnProtocolVersion halts the node on an incompatible required protocol
+// version, after the configured delay if any. An earlier halt already scheduled
+// is kept.
+func (s *Ethereum) haltOnProtocolVersion(required params.ProtocolVersion) error {
+	delay := s.config.RollupHaltDelay
+	if delay == 0 {
+		return s.nodeCloser()
+	}
+	s.protocolVersions.lock.Lock()
+	defer s.protocolVersions.lock.Unlock()
+
+	at := time.Now().Add(delay)
+	if haltAt := s.protocolVersions.haltAt; !haltAt.IsZero() && haltAt.Before(at) {
+		return nil
+	}
+	s.scheduleHalt(at, "unsupported required protocol version "+required.String())
+	return nil
+}
+
+// stopHalt cancels the scheduled halt timer on shutdown.
+func (s *Ethereum) stopHalt() {
+	s.protocolVersions.lock.Lock()
+	defer s.protocolVersions.lock.Unlock()
+
+	if s.protocolVersions.haltTimer != nil {
+		s.protocolVersions.haltTimer.Stop()
+		s.protocolVersions.haltTimer = nil
+	}
+}
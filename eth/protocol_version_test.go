@@ -0,0 +1,105 @@
+package eth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// newHaltTestEthereum creates a bare backend reporting its halts on the returned channel.
+func newHaltTestEthereum(delay time.Duration, closeErr error) (*Ethereum, chan struct{}) {
+	halted := make(chan struct{}, 1)
+	eth := &Ethereum{
+		config: &ethconfig.Config{RollupHaltOnIncompatibleProtocolVersion: "major", RollupHaltDelay: delay},
+		nodeCloser: func() error {
+			halted <- struct{}{}
+			return closeErr
+		},
+	}
+	return eth, halted
+}
+
+func TestProtocolVersionSignal(t *testing.T) {
+	eth, _ := newHaltTestEthereum(0, nil)
+
+	status := eth.ProtocolVersions()
+	if status.Local != params.OPStackSupport || status.Required != nil || status.Recommended != nil || status.Signaled != nil {
+		t.Fatalf("unexpected status before any signal: %+v", status)
+	}
+	var (
+		required    = params.ProtocolVersionV0{Major: 4}.Encode()
+		recommended = params.ProtocolVersionV0{Major: 5}.Encode()
+	)
+	eth.SetProtocolVersionSignal(recommended, required)
+
+	status = eth.ProtocolVersions()
+	if status.Required == nil || *status.Required != required || status.Recommended == nil || *status.Recommended != recommended {
+		t.Fatalf("signaled versions mismatch: %+v", status)
+	}
+	if status.Signaled == nil || status.HaltAt != nil {
+		t.Fatalf("signal status mismatch: %+v", status)
+	}
+}
+
+func TestHaltOnProtocolVersion(t *testing.T) {
+	var (
+		compatible   = params.OPStackSupport
+		incompatible = params.ProtocolVersionV0{Major: 1000}.Encode()
+	)
+	// Without delay, the node halts right away on an incompatible version only
+	eth, halted := newHaltTestEthereum(0, nil)
+	if err := eth.HandleRequiredProtocolVersion(compatible); err != nil {
+		t.Fatalf("compatible version rejected: %v", err)
+	}
+	select {
+	case <-halted:
+		t.Fatal("halted on a compatible version")
+	default:
+	}
+	if err := eth.HandleRequiredProtocolVersion(incompatible); err != nil {
+		t.Fatalf("failed to halt: %v", err)
+	}
+	select {
+	case <-halted:
+	default:
+		t.Fatal("not halted on an incompatible version")
+	}
+	// A failure to halt is reported
+	failure := errors.New("close failure")
+	eth, _ = newHaltTestEthereum(0, failure)
+	if err := eth.HandleRequiredProtocolVersion(incompatible); !errors.Is(err, failure) {
+		t.Fatalf("halt error mismatch: have %v, want %v", err, failure)
+	}
+	// With a delay, the halt is scheduled and the earliest one kept
+	eth, halted = newHaltTestEthereum(time.Hour, nil)
+	defer eth.stopHalt()
+
+	if err := eth.HandleRequiredProtocolVersion(incompatible); err != nil {
+		t.Fatalf("failed to schedule halt: %v", err)
+	}
+	status := eth.ProtocolVersions()
+	if status.HaltAt == nil || status.HaltReason == "" {
+		t.Fatalf("halt not scheduled: %+v", status)
+	}
+	haltAt := *status.HaltAt
+
+	eth.ScheduleHalt(haltAt.Add(-time.Minute), "earlier")
+	eth.HandleRequiredProtocolVersion(incompatible)
+	if status := eth.ProtocolVersions(); !status.HaltAt.Equal(haltAt.Add(-time.Minute)) || status.HaltReason != "earlier" {
+		t.Fatalf("earlier halt replaced: %+v", status)
+	}
+	// Cancelling the halt clears it, a new one fires when due
+	eth.ScheduleHalt(time.Time{}, "")
+	if status := eth.ProtocolVersions(); status.HaltAt != nil {
+		t.Fatalf("halt not cancelled: %+v", status)
+	}
+	eth.ScheduleHalt(time.Now().Add(10*time.Millisecond), "test")
+	select {
+	case <-halted:
+	case <-time.After(time.Second):
+		t.Fatal("scheduled halt not fired")
+	}
+}
@@ -112,7 +112,11 @@ func MakeProtocols(backend Backend, network uint64, dnsdisc enode.Iterator) []p2
 				})
 			},
 			NodeInfo: func() interface{} {
-				return nodeInfo(backend.Chain(), network)
+				info := nodeInfo(backend.Chain(), network)
+				if reporter, ok := backend.(consensusClientReporter); ok {
+					info.ConsensusClient = reporter.ConsensusClient()
+				}
+				return info
 			},
 			PeerInfo: func(id enode.ID) interface{} {
 				return backend.PeerInfo(id)
@@ -132,6 +136,14 @@ type NodeInfo struct {
 	Genesis    common.Hash         `json:"genesis"`    // SHA3 hash of the host's genesis block
 	Config     *params.ChainConfig `json:"config"`     // Chain configuration for the fork rules
 	Head       common.Hash         `json:"head"`       // Hex hash of the host's best owned block
+
+	ConsensusClient interface{} `json:"consensusClient,omitempty"` // Version of the consensus client driving the node, if reported
+}
+
+// consensusClientReporter is implemented by backends which know the consensus
+// client driving the node.
+type consensusClientReporter interface {
+	ConsensusClient() interface{}
 }
 
 // nodeInfo retrieves some `eth` protocol metadata about the running host node.
@@ -0,0 +1,199 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/eth/replicacheck"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// replicaPollInterval is how often the head of the primary sequencer is
+	// retrieved while in replica mode.
+	replicaPollInterval = 2 * time.Second
+
+	// replicaRequestTimeout is the time allowed for the primary sequencer to
+	// report its head.
+	replicaRequestTimeout = 2 * time.Second
+)
+
+var (
+	replicaGauge    = metrics.NewRegisteredGauge("rollup/replica/mode", nil)
+	replicaLagGauge = metrics.NewRegisteredGauge("rollup/replica/lag", nil)
+	promotionMeter  = metrics.NewRegisteredMeter("rollup/replica/promotion", nil)
+)
+
+// ReplicaStatus describes the failover replica state of the node. While in
+// replica mode, the node follows the primary sequencer it forwards transactions
+// to, and refuses to build blocks from its transaction pool until promoted.
+type ReplicaStatus struct {
+	Replica     bool            `json:"replica"`
+	Primary     string          `json:"primary,omitempty"`
+	PrimaryHead *hexutil.Uint64 `json:"primaryHead,omitempty"` // Last head reported by the primary
+	LocalHead   hexutil.Uint64  `json:"localHead"`
+	PromotedAt  *time.Time      `json:"promotedAt,omitempty"`
+}
+
+// failoverReplica tracks the replica mode of the node.
+type failoverReplica struct {
+	lock        sync.Mutex
+	enabled     bool
+	primaryHead *replicacheck.BlockRef
+	promoted    time.Time
+}
+
+// Replica returns whether the node is a replica not promoted yet.
+func (s *Ethereum) Replica() bool {
+	s.replica.lock.Lock()
+	defer s.replica.lock.Unlock()
+
+	return s.replica.enabled
+}
+
+// promoted returns whether the node was promoted from replica to sequencer.
+func (s *Ethereum) promoted() bool {
+	s.replica.lock.Lock()
+	defer s.replica.lock.Unlock()
+
+	return !s.replica.promoted.IsZero()
+}
+
+// ReplicaStatus returns the failover replica state of the node.
+func (s *Ethereum) ReplicaStatus() ReplicaStatus {
+	s.replica.lock.Lock()
+	defer s.replica.lock.Unlock()
+
+	status := ReplicaStatus{
+		Replica:   s.replica.enabled,
+		LocalHead: hexutil.Uint64(s.blockchain.CurrentBlock().Number.Uint64()),
+	}
+	if s.replica.enabled {
+		s.lock.RLock()
+		status.Primary = redactURL(s.config.RollupSequencerHTTP)
+		s.lock.RUnlock()
+
+		if head := s.replica.primaryHead; head != nil {
+			number := head.Number
+			status.PrimaryHead = &number
+		}
+	}
+	if !s.replica.promoted.IsZero() {
+		promoted := s.replica.promoted
+		status.PromotedAt = &promoted
+	}
+	return status
+}
+
+// CheckReplicaPromotion checks the replica can be promoted to sequencer: its
+// local unsafe head must be the given block, and must match the head of the
+// primary if it is still reachable, so that the promoted node does not fork the
+// chain. It queries the primary without holding any lock.
+func (s *Ethereum) CheckReplicaPromotion(ctx context.Context, head common.Hash) error {
+	if err := s.checkReplicaHead(head); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, replicaRequestTimeout)
+	defer cancel()
+
+	primary, err := replicacheck.NewRPCRemote(s.sequencerClient).BlockByNumber(ctx, rpc.LatestBlockNumber)
+	switch {
+	case err != nil:
+		log.Warn("Primary sequencer unreachable, promoting replica", "err", err)
+	case primary != nil && primary.Hash != head:
+		return fmt.Errorf("primary sequencer at head %d (%x), replica at %x", primary.Number, primary.Hash, head)
+	}
+	return nil
+}
+
+// checkReplicaHead checks the node is a replica whose local unsafe head is the
+// given block.
+func (s *Ethereum) checkReplicaHead(head common.Hash) error {
+	if !s.Replica() {
+		return errors.New("node is not a replica")
+	}
+	if local := s.blockchain.CurrentBlock(); local.Hash() != head {
+		return fmt.Errorf("unsafe head %d (%x) does not match expected head %x", local.Number, local.Hash(), head)
+	}
+	return nil
+}
+
+// PromoteReplica turns the replica into the sequencer, once checked by
+// CheckReplicaPromotion. The local unsafe head must still be the given block.
+// Transaction forwarding is disabled and local block building enabled at once,
+// the promotion being persisted so that the node keeps sequencing across restarts.
+func (s *Ethereum) PromoteReplica(head common.Hash) error {
+	s.replica.lock.Lock()
+	defer s.replica.lock.Unlock()
+
+	if !s.replica.enabled {
+		return errors.New("node is not a replica")
+	}
+	local := s.blockchain.CurrentBlock()
+	if local.Hash() != head {
+		return fmt.Errorf("unsafe head %d (%x) does not match expected head %x", local.Number, local.Hash(), head)
+	}
+	promoted := time.Now()
+	rawdb.WriteReplicaPromotion(s.chainDb, uint64(promoted.Unix()))
+
+	// Everything checked, stop forwarding and allow local block building
+	s.lock.Lock()
+	old, primaryURL := s.seqRPCService, s.config.RollupSequencerHTTP
+	s.seqRPCService = nil
+	s.config.RollupSequencerHTTP = ""
+	s.replica.enabled = false
+	s.replica.promoted = promoted
+	s.lock.Unlock()
+
+	if old != nil {
+		time.AfterFunc(replacedClientGracePeriod, old.Close)
+	}
+	replicaGauge.Update(0)
+	replicaLagGauge.Update(0)
+	promotionMeter.Mark(1)
+
+	log.Warn("Replica promoted to sequencer", "number", local.Number, "hash", local.Hash(), "primary", redactURL(primaryURL))
+	return nil
+}
+
+// replicaLoop tracks the head of the primary sequencer while the node is a
+// replica, recording how far behind the local chain is.
+func (s *Ethereum) replicaLoop() {
+	defer s.runtimeWg.Done()
+
+	replicaGauge.Update(1)
+	remote := replicacheck.NewRPCRemote(s.sequencerClient)
+
+	ticker := time.NewTicker(replicaPollInterval)
+	defer ticker.Stop()
+
+	for s.Replica() {
+		ctx, cancel := context.WithTimeout(context.Background(), replicaRequestTimeout)
+		head, err := remote.BlockByNumber(ctx, rpc.LatestBlockNumber)
+		cancel()
+
+		if err != nil {
+			log.Debug("Failed to retrieve primary sequencer head", "err", err)
+		} else if head != nil {
+			s.replica.lock.Lock()
+			if s.replica.enabled {
+				s.replica.primaryHead = head
+				replicaLagGauge.Update(int64(head.Number) - int64(s.blockchain.CurrentBlock().Number.Uint64()))
+			}
+			s.replica.lock.Unlock()
+		}
+		select {
+		case <-ticker.C:
+		case <-s.runtimeQuit:
+			return
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package eth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/replicacheck"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testPrimary is a primary sequencer reporting a fixed head.
+type testPrimary struct {
+	head *replicacheck.BlockRef
+}
+
+func (p *testPrimary) GetBlockByNumber(number rpc.BlockNumber, full bool) *replicacheck.BlockRef {
+	return p.head
+}
+
+// newTestReplica creates a replica following the given primary, nil if unreachable.
+func newTestReplica(t *testing.T, primary *testPrimary) (*Ethereum, *testHandler) {
+	handler := newTestHandlerWithBlocks(1)
+	t.Cleanup(handler.close)
+
+	eth := &Ethereum{
+		config:     &ethconfig.Config{RollupSequencerHTTP: "http://primary"},
+		chainDb:    handler.db,
+		blockchain: handler.chain,
+	}
+	eth.replica.enabled = true
+	if primary != nil {
+		server := rpc.NewServer()
+		if err := server.RegisterName("eth", primary); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(server.Stop)
+		eth.seqRPCService = rpc.DialInProc(server)
+	}
+	return eth, handler
+}
+
+func TestPromoteReplica(t *testing.T) {
+	primary := new(testPrimary)
+	eth, handler := newTestReplica(t, primary)
+	head := handler.chain.CurrentBlock()
+
+	// The expected head must be the local one
+	if err := eth.CheckReplicaPromotion(context.Background(), common.Hash{0x1}); err == nil {
+		t.Fatal("promotion check passed on a mismatching local head")
+	}
+	if err := eth.PromoteReplica(common.Hash{0x1}); err == nil {
+		t.Fatal("promoted on a mismatching local head")
+	}
+	// The primary must not be ahead of the replica
+	primary.head = &replicacheck.BlockRef{Number: hexutil.Uint64(head.Number.Uint64() + 1), Hash: common.Hash{0x2}}
+	if err := eth.CheckReplicaPromotion(context.Background(), head.Hash()); err == nil {
+		t.Fatal("promotion check passed with the primary ahead")
+	}
+	primary.head = &replicacheck.BlockRef{Number: hexutil.Uint64(head.Number.Uint64()), Hash: head.Hash()}
+	if err := eth.CheckReplicaPromotion(context.Background(), head.Hash()); err != nil {
+		t.Fatalf("promotion check failed: %v", err)
+	}
+	if rawdb.ReadReplicaPromotion(handler.db) != nil {
+		t.Fatal("promotion persisted by the check")
+	}
+	// Promotion switches to sequencing and persists it
+	if err := eth.PromoteReplica(head.Hash()); err != nil {
+		t.Fatalf("promotion failed: %v", err)
+	}
+	if eth.Replica() || !eth.promoted() || eth.sequencerClient() != nil || eth.config.RollupSequencerHTTP != "" {
+		t.Fatal("replica mode still active after promotion")
+	}
+	if promoted := rawdb.ReadReplicaPromotion(handler.db); promoted == nil {
+		t.Fatal("promotion not persisted")
+	}
+	// A promoted node cannot be promoted again
+	if err := eth.CheckReplicaPromotion(context.Background(), head.Hash()); err == nil {
+		t.Fatal("promotion check passed on a promoted node")
+	}
+	if err := eth.PromoteReplica(head.Hash()); err == nil {
+		t.Fatal("promoted node promoted again")
+	}
+}
+
+// Tests that a replica can be promoted when its primary is unreachable.
+func TestPromoteReplicaPrimaryDown(t *testing.T) {
+	eth, handler := newTestReplica(t, nil)
+	head := handler.chain.CurrentBlock()
+
+	if err := eth.CheckReplicaPromotion(context.Background(), head.Hash()); err != nil {
+		t.Fatalf("promotion check failed with the primary down: %v", err)
+	}
+	if err := eth.PromoteReplica(head.Hash()); err != nil {
+		t.Fatalf("promotion failed with the primary down: %v", err)
+	}
+	if rawdb.ReadReplicaPromotion(handler.db) == nil {
+		t.Fatal("promotion not persisted")
+	}
+}
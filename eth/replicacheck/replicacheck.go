@@ -0,0 +1,302 @@
+// Package replicacheck implements a background checker which compares the chain
+// of a replica with the one of its sequencer, detecting split-brain replicas.
+package replicacheck
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// requestTimeout is the time allowed for all requests of a single round.
+const requestTimeout = 5 * time.Second
+
+var (
+	checkedMeter    = metrics.NewRegisteredMeter("replicacheck/checked", nil)
+	divergenceMeter = metrics.NewRegisteredMeter("replicacheck/divergence", nil)
+	failureMeter    = metrics.NewRegisteredMeter("replicacheck/failure", nil)
+	divergedGauge   = metrics.NewRegisteredGauge("replicacheck/diverged", nil)
+)
+
+// BlockChain defines the minimal set of methods needed to back the checker.
+type BlockChain interface {
+	CurrentBlock() *types.Header
+	CurrentSafeBlock() *types.Header
+	CurrentFinalBlock() *types.Header
+	GetHeaderByNumber(number uint64) *types.Header
+}
+
+// BlockRef identifies a block of the remote chain.
+type BlockRef struct {
+	Number hexutil.Uint64 `json:"number"`
+	Hash   common.Hash    `json:"hash"`
+	Root   common.Hash    `json:"stateRoot"`
+}
+
+// Remote is the chain the local one is compared with.
+type Remote interface {
+	// BlockByNumber retrieves the block with the given number or label, nil if
+	// it does not exist.
+	BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*BlockRef, error)
+}
+
+// Divergence describes the first block on which the local chain differs from
+// the remote one.
+type Divergence struct {
+	Label      string      `json:"label"` // Head label the divergence was found on
+	Number     uint64      `json:"number"`
+	LocalHash  common.Hash `json:"localHash"`
+	RemoteHash common.Hash `json:"remoteHash"`
+	LocalRoot  common.Hash `json:"localRoot"`
+	RemoteRoot common.Hash `json:"remoteRoot"`
+	Detected   time.Time   `json:"detected"`
+}
+
+// Checker periodically compares the local head, safe and finalized blocks with
+// the sequencer, searching for the first mismatching block on divergence.
+type Checker struct {
+	chain    BlockChain
+	remote   Remote
+	interval time.Duration
+
+	lock       sync.Mutex
+	agreed     uint64      // Highest block number known to match the remote
+	divergence *Divergence // First divergence detected, nil if none
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a replica consistency checker comparing chain with remote every
+// interval.
+func New(chain BlockChain, remote Remote, interval time.Duration) *Checker {
+	return &Checker{
+		chain:    chain,
+		remote:   remote,
+		interval: interval,
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start launches the background loop comparing the chains.
+func (c *Checker) Start() {
+	c.wg.Add(1)
+	go c.loop()
+}
+
+// Stop terminates the background loop.
+func (c *Checker) Stop() {
+	close(c.quit)
+	c.wg.Wait()
+}
+
+// Divergence returns the detected divergence, or nil if the chains match.
+func (c *Checker) Divergence() *Divergence {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.divergence
+}
+
+func (c *Checker) loop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+			err := c.Check(ctx)
+			cancel()
+			if err != nil {
+				failureMeter.Mark(1)
+				log.Debug("Replica consistency check failed", "err", err)
+			}
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// Check compares the local head, safe and finalized blocks with the remote ones,
+// recording and alerting on the first divergence found. Once diverged, the chains
+// are rechecked each round to detect when they match again.
+func (c *Checker) Check(ctx context.Context) error {
+	checkedMeter.Mark(1)
+
+	var failure error
+	for _, label := range []struct {
+		name   string
+		number rpc.BlockNumber
+		local  *types.Header
+	}{
+		{"finalized", rpc.FinalizedBlockNumber, c.chain.CurrentFinalBlock()},
+		{"safe", rpc.SafeBlockNumber, c.chain.CurrentSafeBlock()},
+		{"head", rpc.LatestBlockNumber, c.chain.CurrentBlock()},
+	} {
+		if label.local == nil {
+			continue
+		}
+		remote, err := c.remote.BlockByNumber(ctx, label.number)
+		if err != nil {
+			// Keep checking the other labels, the remote may not know this one yet
+			if failure == nil {
+				failure = fmt.Errorf("%s block: %w", label.name, err)
+			}
+			continue
+		}
+		if remote == nil {
+			continue
+		}
+		// Compare at the lower of the two heights, as one side may lag behind
+		number := label.local.Number.Uint64()
+		if uint64(remote.Number) < number {
+			number = uint64(remote.Number)
+		}
+		match, err := c.matches(ctx, number, remote)
+		if err != nil {
+			return err
+		}
+		if !match {
+			return c.diverged(ctx, label.name, number)
+		}
+		c.agree(number)
+	}
+	if failure != nil {
+		return failure
+	}
+	c.lock.Lock()
+	if c.divergence != nil {
+		log.Info("Replica chain matches sequencer again", "number", c.agreed)
+		c.divergence = nil
+		divergedGauge.Update(0)
+	}
+	c.lock.Unlock()
+	return nil
+}
+
+// matches reports whether the local canonical block at the given number equals
+// the remote one. If ref is the remote block at that number, it is used instead
+// of retrieving it.
+func (c *Checker) matches(ctx context.Context, number uint64, ref *BlockRef) (bool, error) {
+	local := c.chain.GetHeaderByNumber(number)
+	if local == nil {
+		return false, fmt.Errorf("local block #%d missing", number)
+	}
+	remote, err := c.remoteAt(ctx, number, ref)
+	if err != nil {
+		return false, err
+	}
+	return local.Hash() == remote.Hash, nil
+}
+
+func (c *Checker) remoteAt(ctx context.Context, number uint64, ref *BlockRef) (*BlockRef, error) {
+	if ref != nil && uint64(ref.Number) == number {
+		return ref, nil
+	}
+	remote, err := c.remote.BlockByNumber(ctx, rpc.BlockNumber(number))
+	if err != nil {
+		return nil, fmt.Errorf("remote block #%d: %w", number, err)
+	}
+	if remote == nil {
+		return nil, fmt.Errorf("remote block #%d missing", number)
+	}
+	return remote, nil
+}
+
+func (c *Checker) agree(number uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if number > c.agreed {
+		c.agreed = number
+	}
+}
+
+// diverged searches the first mismatching block below the given one, which is
+// known to mismatch, and raises the divergence alert.
+func (c *Checker) diverged(ctx context.Context, label string, number uint64) error {
+	c.lock.Lock()
+	lo, known := c.agreed, c.divergence
+	c.lock.Unlock()
+
+	if lo >= number {
+		lo = 0 // the agreed block was reorged, search the whole chain
+	}
+	if known != nil && known.Number <= number && known.Number > lo {
+		return nil // already reported and still diverged
+	}
+	// Binary search the first mismatch in (lo, number]
+	hi := number
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		match, err := c.matches(ctx, mid, nil)
+		if err != nil {
+			return err
+		}
+		if match {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	local := c.chain.GetHeaderByNumber(hi)
+	if local == nil {
+		return fmt.Errorf("local block #%d missing", hi)
+	}
+	remote, err := c.remoteAt(ctx, hi, nil)
+	if err != nil {
+		return err
+	}
+	divergence := &Divergence{
+		Label:      label,
+		Number:     hi,
+		LocalHash:  local.Hash(),
+		RemoteHash: remote.Hash,
+		LocalRoot:  local.Root,
+		RemoteRoot: remote.Root,
+		Detected:   time.Now(),
+	}
+	c.lock.Lock()
+	c.agreed = lo
+	c.divergence = divergence
+	c.lock.Unlock()
+
+	divergenceMeter.Mark(1)
+	divergedGauge.Update(1)
+	log.Error("Replica diverged from sequencer", "label", label, "number", hi,
+		"local", divergence.LocalHash, "remote", divergence.RemoteHash,
+		"localroot", divergence.LocalRoot, "remoteroot", divergence.RemoteRoot)
+	return nil
+}
+
+// rpcRemote retrieves remote blocks over RPC.
+type rpcRemote func() *rpc.Client
+
+// NewRPCRemote creates a remote retrieving blocks through the RPC client returned
+// by client, which allows the endpoint to change at runtime.
+func NewRPCRemote(client func() *rpc.Client) Remote {
+	return rpcRemote(client)
+}
+
+func (r rpcRemote) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*BlockRef, error) {
+	client := r()
+	if client == nil {
+		return nil, fmt.Errorf("no remote endpoint")
+	}
+	var block *BlockRef
+	if err := client.CallContext(ctx, &block, "eth_getBlockByNumber", number, false); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
@@ -0,0 +1,85 @@
+package replicacheck
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testChain is a canonical chain of headers, serving both as the local chain
+// and as the remote one.
+type testChain []*types.Header
+
+func newTestChain(n int, fork int, extra byte) testChain {
+	chain := make(testChain, n)
+	for i := range chain {
+		header := &types.Header{Number: big.NewInt(int64(i)), Extra: []byte{0}}
+		if i >= fork {
+			header.Extra = []byte{extra}
+		}
+		if i > 0 {
+			header.ParentHash = chain[i-1].Hash()
+		}
+		chain[i] = header
+	}
+	return chain
+}
+
+func (c testChain) CurrentBlock() *types.Header      { return c[len(c)-1] }
+func (c testChain) CurrentSafeBlock() *types.Header  { return c[len(c)/2] }
+func (c testChain) CurrentFinalBlock() *types.Header { return c[len(c)/4] }
+
+func (c testChain) GetHeaderByNumber(number uint64) *types.Header {
+	if number >= uint64(len(c)) {
+		return nil
+	}
+	return c[number]
+}
+
+func (c testChain) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*BlockRef, error) {
+	var header *types.Header
+	switch number {
+	case rpc.LatestBlockNumber:
+		header = c.CurrentBlock()
+	case rpc.SafeBlockNumber:
+		header = c.CurrentSafeBlock()
+	case rpc.FinalizedBlockNumber:
+		header = c.CurrentFinalBlock()
+	default:
+		header = c.GetHeaderByNumber(uint64(number))
+	}
+	if header == nil {
+		return nil, nil
+	}
+	return &BlockRef{Number: hexutil.Uint64(header.Number.Uint64()), Hash: header.Hash(), Root: header.Root}, nil
+}
+
+func TestConsistentReplica(t *testing.T) {
+	checker := New(newTestChain(100, 100, 0), newTestChain(120, 120, 0), 0)
+	if err := checker.Check(context.Background()); err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if div := checker.Divergence(); div != nil {
+		t.Fatalf("unexpected divergence: %+v", div)
+	}
+}
+
+func TestDivergedReplica(t *testing.T) {
+	for _, fork := range []int{1, 10, 26, 51, 99} {
+		checker := New(newTestChain(100, fork, 1), newTestChain(100, fork, 2), 0)
+		if err := checker.Check(context.Background()); err != nil {
+			t.Fatalf("fork %d: check failed: %v", fork, err)
+		}
+		div := checker.Divergence()
+		if div == nil {
+			t.Fatalf("fork %d: divergence not detected", fork)
+		}
+		if div.Number != uint64(fork) {
+			t.Errorf("fork %d: first mismatch number wrong: have %d", fork, div.Number)
+		}
+	}
+}
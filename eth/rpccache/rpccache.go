@@ -0,0 +1,388 @@
+// Package rpccache implements a cache of RPC responses on immutable chain data,
+// such as blocks, receipts and traces, to avoid redoing the same work for every
+// client requesting them.
+package rpccache
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// entryOverhead approximates the memory used by an entry besides its key and
+// result, used for the size accounting.
+const entryOverhead = 128
+
+var (
+	hitMeter      = metrics.NewRegisteredMeter("rpc/cache/hit", nil)
+	missMeter     = metrics.NewRegisteredMeter("rpc/cache/miss", nil)
+	evictionMeter = metrics.NewRegisteredMeter("rpc/cache/reorg", nil)
+	sizeGauge     = metrics.NewRegisteredGauge("rpc/cache/size", nil)
+)
+
+// BlockChain defines the minimal set of methods needed to back the cache.
+type BlockChain interface {
+	CurrentFinalBlock() *types.Header
+	GetCanonicalHash(number uint64) common.Hash
+	GetHeaderByHash(hash common.Hash) *types.Header
+	GetTransactionLookup(hash common.Hash) *rawdb.LegacyTxLookupEntry
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+	SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription
+}
+
+// blockRef is the block a cached response was derived from.
+type blockRef struct {
+	Hash   common.Hash     `json:"hash"`
+	Number *hexutil.Uint64 `json:"number"`
+}
+
+// txRef is the block a transaction or receipt response was derived from.
+type txRef struct {
+	BlockHash   *common.Hash    `json:"blockHash"`
+	BlockNumber *hexutil.Uint64 `json:"blockNumber"`
+}
+
+type entry struct {
+	result  json.RawMessage
+	block   common.Hash
+	number  uint64
+	final   bool
+	expires time.Time
+}
+
+// Cache is an rpc.ResponseCache holding responses on canonical blocks. Responses
+// on finalized blocks are kept until they expire or are evicted due to the size
+// limit, while the ones on newer blocks are also dropped when their block gets
+// reorged out or rewound by a SetHead.
+type Cache struct {
+	chain   BlockChain
+	maxSize int
+	ttl     time.Duration
+
+	lock    sync.Mutex
+	entries lru.BasicLRU[string, *entry]
+	blocks  map[common.Hash]map[string]struct{} // Keys of the non-finalized entries per block
+	size    int
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a response cache using up to size bytes, serving responses for at
+// most ttl.
+func New(chain BlockChain, size int, ttl time.Duration) *Cache {
+	return &Cache{
+		chain:   chain,
+		maxSize: size,
+		ttl:     ttl,
+		entries: lru.NewBasicLRU[string, *entry](size / entryOverhead),
+		blocks:  make(map[common.Hash]map[string]struct{}),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start launches the background loop dropping responses on reorged blocks.
+func (c *Cache) Start() {
+	var (
+		sideCh  = make(chan core.ChainSideEvent, 64)
+		sideSub = c.chain.SubscribeChainSideEvent(sideCh)
+
+		// SetHead rewinds post no side event, only a head not extending the last one
+		headCh  = make(chan core.ChainHeadEvent, 64)
+		headSub = c.chain.SubscribeChainHeadEvent(headCh)
+	)
+	c.wg.Add(1)
+	go c.loop(sideCh, sideSub, headCh, headSub)
+}
+
+// Stop terminates the background loop.
+func (c *Cache) Stop() {
+	close(c.quit)
+	c.wg.Wait()
+}
+
+func (c *Cache) loop(sideCh <-chan core.ChainSideEvent, sideSub event.Subscription, headCh <-chan core.ChainHeadEvent, headSub event.Subscription) {
+	defer c.wg.Done()
+	defer sideSub.Unsubscribe()
+	defer headSub.Unsubscribe()
+
+	var last common.Hash
+	for {
+		select {
+		case ev := <-sideCh:
+			c.invalidate(ev.Block.Hash())
+		case ev := <-headCh:
+			if last != (common.Hash{}) && ev.Block.ParentHash() != last {
+				c.invalidateNonCanonical()
+			}
+			last = ev.Block.Hash()
+		case <-sideSub.Err():
+			return
+		case <-headSub.Err():
+			return
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// invalidate drops all responses derived from the given block.
+func (c *Cache) invalidate(hash common.Hash) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	keys := c.blocks[hash]
+	if len(keys) == 0 {
+		return
+	}
+	count := len(keys)
+	for key := range keys {
+		if e, ok := c.entries.Peek(key); ok {
+			c.remove(key, e)
+		}
+	}
+	delete(c.blocks, hash)
+
+	evictionMeter.Mark(int64(count))
+	log.Debug("Dropped cached RPC responses of reorged block", "hash", hash, "count", count)
+}
+
+// invalidateNonCanonical drops all responses derived from non-finalized blocks
+// no longer canonical.
+func (c *Cache) invalidateNonCanonical() {
+	c.lock.Lock()
+	var dropped []common.Hash
+	for hash, keys := range c.blocks {
+		for key := range keys {
+			if e, ok := c.entries.Peek(key); ok && c.chain.GetCanonicalHash(e.number) != hash {
+				dropped = append(dropped, hash)
+			}
+			break
+		}
+	}
+	c.lock.Unlock()
+
+	for _, hash := range dropped {
+		c.invalidate(hash)
+	}
+}
+
+// Get returns the cached result of the given call, if any.
+func (c *Cache) Get(method string, params json.RawMessage) (json.RawMessage, bool) {
+	if !cacheable(method) {
+		return nil, false
+	}
+	key, ok := cacheKey(method, params)
+	if !ok {
+		return nil, false
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e, ok := c.entries.Get(key)
+	if !ok {
+		missMeter.Mark(1)
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		c.remove(key, e)
+		missMeter.Mark(1)
+		return nil, false
+	}
+	hitMeter.Mark(1)
+	return e.result, true
+}
+
+// Add caches the result of the given call if it was derived from a canonical
+// block, and the call does not depend on the chain head.
+func (c *Cache) Add(method string, params json.RawMessage, result json.RawMessage) {
+	if !cacheable(method) || len(result) == 0 || string(result) == "null" {
+		return
+	}
+	key, ok := cacheKey(method, params)
+	if !ok {
+		return
+	}
+	hash, number, finalOnly, ok := c.resolve(method, params, result)
+	if !ok {
+		return
+	}
+	size := len(key) + len(result) + entryOverhead
+	if size > c.maxSize {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Check the block is canonical while holding the lock, so a reorg either
+	// prevents caching or drops the entry right after.
+	if c.chain.GetCanonicalHash(number) != hash {
+		return
+	}
+	final := false
+	if head := c.chain.CurrentFinalBlock(); head != nil && number <= head.Number.Uint64() {
+		final = true
+	}
+	if finalOnly && !final {
+		return
+	}
+	if old, ok := c.entries.Peek(key); ok {
+		c.remove(key, old)
+	}
+	// Make room before inserting, so the entry count never reaches the capacity
+	// of the LRU, which would evict entries behind the size accounting.
+	for c.size+size > c.maxSize {
+		oldest, old, ok := c.entries.GetOldest()
+		if !ok {
+			break
+		}
+		c.remove(oldest, old)
+	}
+	e := &entry{result: result, block: hash, number: number, final: final, expires: time.Now().Add(c.ttl)}
+	c.entries.Add(key, e)
+	c.size += size
+	if !final {
+		if c.blocks[hash] == nil {
+			c.blocks[hash] = make(map[string]struct{})
+		}
+		c.blocks[hash][key] = struct{}{}
+	}
+	sizeGauge.Update(int64(c.size))
+}
+
+// remove drops an entry from the cache. The lock is assumed to be held.
+func (c *Cache) remove(key string, e *entry) {
+	c.entries.Remove(key)
+	c.size -= len(key) + len(e.result) + entryOverhead
+	if !e.final {
+		if keys := c.blocks[e.block]; keys != nil {
+			delete(keys, key)
+			if len(keys) == 0 {
+				delete(c.blocks, e.block)
+			}
+		}
+	}
+	sizeGauge.Update(int64(c.size))
+}
+
+// resolve determines the block a response was derived from, and whether it may
+// only be cached once that block is finalized.
+func (c *Cache) resolve(method string, params json.RawMessage, result json.RawMessage) (common.Hash, uint64, bool, bool) {
+	switch method {
+	case "eth_getBlockByHash", "eth_getBlockByNumber":
+		// Only cache blocks requested by number, labels follow the chain head
+		if method == "eth_getBlockByNumber" && !numericParam(params) {
+			return common.Hash{}, 0, false, false
+		}
+		var ref blockRef
+		if err := json.Unmarshal(result, &ref); err != nil || ref.Number == nil {
+			return common.Hash{}, 0, false, false
+		}
+		return ref.Hash, uint64(*ref.Number), false, true
+
+	case "eth_getTransactionByHash", "eth_getTransactionReceipt":
+		var ref txRef
+		if err := json.Unmarshal(result, &ref); err != nil || ref.BlockHash == nil || ref.BlockNumber == nil {
+			return common.Hash{}, 0, false, false // pending transaction
+		}
+		return *ref.BlockHash, uint64(*ref.BlockNumber), false, true
+
+	case "eth_getBlockReceipts":
+		if !numericParam(params) && !hashParam(params) {
+			return common.Hash{}, 0, false, false
+		}
+		var refs []txRef
+		if err := json.Unmarshal(result, &refs); err != nil || len(refs) == 0 || refs[0].BlockHash == nil || refs[0].BlockNumber == nil {
+			return common.Hash{}, 0, false, false
+		}
+		return *refs[0].BlockHash, uint64(*refs[0].BlockNumber), false, true
+
+	case "debug_traceBlockByHash":
+		var hash common.Hash
+		if !firstParam(params, &hash) {
+			return common.Hash{}, 0, false, false
+		}
+		header := c.chain.GetHeaderByHash(hash)
+		if header == nil {
+			return common.Hash{}, 0, false, false
+		}
+		return hash, header.Number.Uint64(), true, true
+
+	case "debug_traceTransaction":
+		var hash common.Hash
+		if !firstParam(params, &hash) {
+			return common.Hash{}, 0, false, false
+		}
+		lookup := c.chain.GetTransactionLookup(hash)
+		if lookup == nil {
+			return common.Hash{}, 0, false, false
+		}
+		return lookup.BlockHash, lookup.BlockIndex, true, true
+	}
+	return common.Hash{}, 0, false, false
+}
+
+// cacheable reports whether responses of the given method may be cached.
+func cacheable(method string) bool {
+	switch method {
+	case "eth_getBlockByHash", "eth_getBlockByNumber", "eth_getTransactionByHash",
+		"eth_getTransactionReceipt", "eth_getBlockReceipts", "debug_traceBlockByHash",
+		"debug_traceTransaction":
+		return true
+	}
+	return false
+}
+
+// cacheKey derives the key of a call from its method and compacted parameters.
+func cacheKey(method string, params json.RawMessage) (string, bool) {
+	var buf bytes.Buffer
+	buf.WriteString(method)
+	buf.WriteByte(0)
+	if len(params) > 0 {
+		if err := json.Compact(&buf, params); err != nil {
+			return "", false
+		}
+	}
+	return buf.String(), true
+}
+
+// firstParam decodes the first positional parameter into v.
+func firstParam(params json.RawMessage, v interface{}) bool {
+	var args []json.RawMessage
+	if err := json.Unmarshal(params, &args); err != nil || len(args) == 0 {
+		return false
+	}
+	return json.Unmarshal(args[0], v) == nil
+}
+
+// numericParam reports whether the first parameter is a block number rather
+// than a label.
+func numericParam(params json.RawMessage) bool {
+	var number string
+	if !firstParam(params, &number) {
+		return false
+	}
+	_, err := hexutil.DecodeUint64(number)
+	return err == nil
+}
+
+// hashParam reports whether the first parameter is a block hash.
+func hashParam(params json.RawMessage) bool {
+	var hash string
+	if !firstParam(params, &hash) {
+		return false
+	}
+	return strings.HasPrefix(hash, "0x") && len(hash) == 2+2*common.HashLength
+}
@@ -0,0 +1,207 @@
+package rpccache
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+type testChain struct {
+	final     uint64
+	canonical map[uint64]common.Hash
+	headFeed  event.Feed
+	sideFeed  event.Feed
+}
+
+func newTestChain(head uint64, final uint64) *testChain {
+	chain := &testChain{final: final, canonical: make(map[uint64]common.Hash)}
+	for i := uint64(0); i <= head; i++ {
+		chain.canonical[i] = common.Hash{byte(i + 1)}
+	}
+	return chain
+}
+
+func (c *testChain) CurrentFinalBlock() *types.Header {
+	return &types.Header{Number: new(big.Int).SetUint64(c.final)}
+}
+
+func (c *testChain) GetCanonicalHash(number uint64) common.Hash {
+	return c.canonical[number]
+}
+
+func (c *testChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	for number, canonical := range c.canonical {
+		if canonical == hash {
+			return &types.Header{Number: new(big.Int).SetUint64(number)}
+		}
+	}
+	return nil
+}
+
+func (c *testChain) GetTransactionLookup(hash common.Hash) *rawdb.LegacyTxLookupEntry {
+	number := uint64(hash[0])
+	return &rawdb.LegacyTxLookupEntry{BlockHash: c.canonical[number], BlockIndex: number}
+}
+
+func (c *testChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return c.headFeed.Subscribe(ch)
+}
+
+func (c *testChain) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription {
+	return c.sideFeed.Subscribe(ch)
+}
+
+func blockResult(hash common.Hash, number uint64) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{"hash":"%s","number":"0x%x"}`, hash.Hex(), number))
+}
+
+func params(args ...interface{}) json.RawMessage {
+	blob, _ := json.Marshal(args)
+	return blob
+}
+
+func TestCacheBlocks(t *testing.T) {
+	chain := newTestChain(10, 5)
+	cache := New(chain, 1024*1024, time.Minute)
+
+	// Blocks requested by number or hash are cached, labels are not
+	cache.Add("eth_getBlockByNumber", params("0x3", false), blockResult(chain.canonical[3], 3))
+	cache.Add("eth_getBlockByNumber", params("latest", false), blockResult(chain.canonical[10], 10))
+	cache.Add("eth_getBlockByHash", params(chain.canonical[8], false), blockResult(chain.canonical[8], 8))
+
+	if _, ok := cache.Get("eth_getBlockByNumber", json.RawMessage(`[ "0x3", false ]`)); !ok {
+		t.Error("block by number not cached")
+	}
+	if _, ok := cache.Get("eth_getBlockByNumber", params("latest", false)); ok {
+		t.Error("block by label cached")
+	}
+	if _, ok := cache.Get("eth_getBlockByHash", params(chain.canonical[8], false)); !ok {
+		t.Error("block by hash not cached")
+	}
+	// Non-canonical blocks are not cached
+	side := common.Hash{0xff}
+	cache.Add("eth_getBlockByHash", params(side, false), blockResult(side, 9))
+	if _, ok := cache.Get("eth_getBlockByHash", params(side, false)); ok {
+		t.Error("side block cached")
+	}
+	// Unknown methods are never cached
+	cache.Add("eth_blockNumber", nil, json.RawMessage(`"0xa"`))
+	if _, ok := cache.Get("eth_blockNumber", nil); ok {
+		t.Error("head dependent method cached")
+	}
+}
+
+func TestCacheReorg(t *testing.T) {
+	chain := newTestChain(10, 5)
+	cache := New(chain, 1024*1024, time.Minute)
+
+	cache.Add("eth_getBlockByNumber", params("0x3", false), blockResult(chain.canonical[3], 3))
+	cache.Add("eth_getBlockByNumber", params("0x8", false), blockResult(chain.canonical[8], 8))
+
+	// Reorging the non-finalized block drops its entry only
+	cache.invalidate(chain.canonical[8])
+	cache.invalidate(chain.canonical[3])
+
+	if _, ok := cache.Get("eth_getBlockByNumber", params("0x3", false)); !ok {
+		t.Error("finalized block dropped on reorg")
+	}
+	if _, ok := cache.Get("eth_getBlockByNumber", params("0x8", false)); ok {
+		t.Error("reorged block still cached")
+	}
+}
+
+// Tests that the responses on the blocks rewound by a SetHead are dropped, as
+// no side event is posted for them.
+func TestCacheSetHead(t *testing.T) {
+	chain := newTestChain(10, 5)
+	cache := New(chain, 1024*1024, time.Minute)
+	cache.Start()
+	defer cache.Stop()
+
+	head := func(number uint64) {
+		parent := chain.canonical[number-1]
+		chain.headFeed.Send(core.ChainHeadEvent{Block: types.NewBlockWithHeader(&types.Header{ParentHash: parent, Number: new(big.Int).SetUint64(number)})})
+	}
+	head(10)
+	for _, number := range []uint64{3, 7, 9} {
+		cache.Add("eth_getBlockByNumber", params(hexutil.EncodeUint64(number), false), blockResult(chain.canonical[number], number))
+	}
+	// Rewind the chain to block 7, extending it from there
+	delete(chain.canonical, 10)
+	delete(chain.canonical, 9)
+	chain.canonical[8] = common.Hash{0xff}
+	head(8)
+
+	cached := func(number uint64) bool {
+		_, ok := cache.Get("eth_getBlockByNumber", params(hexutil.EncodeUint64(number), false))
+		return ok
+	}
+	for i := 0; i < 100 && cached(9); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if cached(9) {
+		t.Error("rewound block still cached")
+	}
+	if !cached(3) || !cached(7) {
+		t.Error("canonical blocks dropped on rewind")
+	}
+}
+
+func TestCacheTraces(t *testing.T) {
+	chain := newTestChain(10, 5)
+	cache := New(chain, 1024*1024, time.Minute)
+
+	// Traces are only cached once finalized
+	cache.Add("debug_traceBlockByHash", params(chain.canonical[4]), json.RawMessage(`[]`))
+	cache.Add("debug_traceBlockByHash", params(chain.canonical[7]), json.RawMessage(`[]`))
+	cache.Add("debug_traceTransaction", params(common.Hash{4}), json.RawMessage(`{}`))
+	cache.Add("debug_traceTransaction", params(common.Hash{7}), json.RawMessage(`{}`))
+
+	if _, ok := cache.Get("debug_traceBlockByHash", params(chain.canonical[4])); !ok {
+		t.Error("finalized block trace not cached")
+	}
+	if _, ok := cache.Get("debug_traceBlockByHash", params(chain.canonical[7])); ok {
+		t.Error("unfinalized block trace cached")
+	}
+	if _, ok := cache.Get("debug_traceTransaction", params(common.Hash{4})); !ok {
+		t.Error("finalized transaction trace not cached")
+	}
+	if _, ok := cache.Get("debug_traceTransaction", params(common.Hash{7})); ok {
+		t.Error("unfinalized transaction trace cached")
+	}
+}
+
+func TestCacheLimits(t *testing.T) {
+	chain := newTestChain(10, 10)
+
+	// Entries expire after the TTL
+	cache := New(chain, 1024*1024, time.Millisecond)
+	cache.Add("eth_getBlockByNumber", params("0x3", false), blockResult(chain.canonical[3], 3))
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := cache.Get("eth_getBlockByNumber", params("0x3", false)); ok {
+		t.Error("expired entry served")
+	}
+	// The oldest entries are evicted to respect the size limit
+	cache = New(chain, 3*entryOverhead+300, time.Minute)
+	for i := uint64(0); i <= 10; i++ {
+		cache.Add("eth_getBlockByNumber", params(fmt.Sprintf("0x%x", i), false), blockResult(chain.canonical[i], i))
+	}
+	if cache.size > cache.maxSize {
+		t.Errorf("size limit exceeded: have %d, limit %d", cache.size, cache.maxSize)
+	}
+	if _, ok := cache.Get("eth_getBlockByNumber", params("0x0", false)); ok {
+		t.Error("oldest entry not evicted")
+	}
+	if _, ok := cache.Get("eth_getBlockByNumber", params("0xa", false)); !ok {
+		t.Error("newest entry evicted")
+	}
+}
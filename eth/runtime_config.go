@@ -0,0 +1,253 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// runtimeConfigPollInterval is how often the runtime configuration file is
+	// checked for modifications.
+	runtimeConfigPollInterval = 5 * time.Second
+
+	// runtimeConfigDialTimeout is the time allowed to connect to a new
+	// forwarding endpoint before the change is rejected.
+	runtimeConfigDialTimeout = 5 * time.Second
+
+	// replacedClientGracePeriod is how long a replaced forwarding client is
+	// kept open for requests still in flight.
+	replacedClientGracePeriod = time.Minute
+)
+
+// RuntimeConfig is the subset of the node configuration which can be changed
+// while the node is running, either through the runtime configuration file or
+// the admin_setRuntimeConfig RPC method. Nil fields are left unchanged.
+type RuntimeConfig struct {
+	GasCeil       *hexutil.Uint64 `json:"gasCeil,omitempty"`
+	GasPrice      *hexutil.Big    `json:"gasPrice,omitempty"`
+	SequencerHTTP *string         `json:"sequencerHTTP,omitempty"`
+	HistoricalRPC *string         `json:"historicalRPC,omitempty"`
+}
+
+// RuntimeConfig returns the currently effective runtime configuration.
+func (s *Ethereum) RuntimeConfig() RuntimeConfig {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var (
+		gasCeil       = hexutil.Uint64(s.gasCeil)
+		sequencerHTTP = redactURL(s.config.RollupSequencerHTTP)
+		historicalRPC = redactURL(s.config.RollupHistoricalRPC)
+	)
+	cfg := RuntimeConfig{
+		GasCeil:       &gasCeil,
+		SequencerHTTP: &sequencerHTTP,
+		HistoricalRPC: &historicalRPC,
+	}
+	if s.gasPrice != nil {
+		cfg.GasPrice = (*hexutil.Big)(new(big.Int).Set(s.gasPrice))
+	}
+	return cfg
+}
+
+// ApplyRuntimeConfig validates and applies the non-nil fields of the given
+// runtime configuration. Either all changes are applied or none. Every change
+// is recorded in the log along with its source, for auditing.
+func (s *Ethereum) ApplyRuntimeConfig(cfg *RuntimeConfig, source string) error {
+	s.runtimeLock.Lock()
+	defer s.runtimeLock.Unlock()
+
+	// Validate all fields before changing anything
+	if cfg.GasCeil != nil && uint64(*cfg.GasCeil) < params.MinGasLimit {
+		return fmt.Errorf("gas ceiling %d below minimum %d", *cfg.GasCeil, params.MinGasLimit)
+	}
+	if cfg.GasPrice != nil && cfg.GasPrice.ToInt().Sign() < 0 {
+		return errors.New("negative gas price")
+	}
+	s.lock.RLock()
+	var (
+		oldSequencer  = s.config.RollupSequencerHTTP
+		oldHistorical = s.config.RollupHistoricalRPC
+	)
+	s.lock.RUnlock()
+
+	if cfg.SequencerHTTP != nil && *cfg.SequencerHTTP != oldSequencer {
+		// Transaction admission into the local pool is decided at startup based
+		// on whether forwarding is enabled, so it cannot be toggled here.
+		if oldSequencer == "" || *cfg.SequencerHTTP == "" {
+			return errors.New("sequencer forwarding cannot be enabled or disabled at runtime")
+		}
+	}
+	var sequencer, historical *rpc.Client
+	if cfg.SequencerHTTP != nil && *cfg.SequencerHTTP != oldSequencer {
+		client, err := dialRuntimeClient(*cfg.SequencerHTTP)
+		if err != nil {
+			return fmt.Errorf("invalid sequencer endpoint: %w", err)
+		}
+		sequencer = client
+	}
+	if cfg.HistoricalRPC != nil && *cfg.HistoricalRPC != oldHistorical && *cfg.HistoricalRPC != "" {
+		client, err := dialRuntimeClient(*cfg.HistoricalRPC)
+		if err != nil {
+			if sequencer != nil {
+				sequencer.Close()
+			}
+			return fmt.Errorf("invalid historical endpoint: %w", err)
+		}
+		historical = client
+	}
+	// Everything validated, apply the changes
+	if cfg.GasCeil != nil {
+		s.lock.Lock()
+		old := s.gasCeil
+		s.gasCeil = uint64(*cfg.GasCeil)
+		s.lock.Unlock()
+
+		if old != uint64(*cfg.GasCeil) {
+			s.miner.SetGasCeil(uint64(*cfg.GasCeil))
+			log.Warn("Runtime configuration changed", "source", source, "field", "gasCeil", "old", old, "new", uint64(*cfg.GasCeil))
+		}
+	}
+	if cfg.GasPrice != nil {
+		price := new(big.Int).Set(cfg.GasPrice.ToInt())
+
+		s.lock.Lock()
+		old := s.gasPrice
+		s.gasPrice = price
+		s.lock.Unlock()
+
+		if old == nil || old.Cmp(price) != 0 {
+			s.txPool.SetGasTip(price)
+			log.Warn("Runtime configuration changed", "source", source, "field", "gasPrice", "old", old, "new", price)
+		}
+	}
+	if sequencer != nil {
+		s.lock.Lock()
+		old := s.seqRPCService
+		s.seqRPCService = sequencer
+		s.config.RollupSequencerHTTP = *cfg.SequencerHTTP
+		s.lock.Unlock()
+
+		time.AfterFunc(replacedClientGracePeriod, old.Close)
+		log.Warn("Runtime configuration changed", "source", source, "field", "sequencerHTTP", "old", redactURL(oldSequencer), "new", redactURL(*cfg.SequencerHTTP))
+	}
+	if cfg.HistoricalRPC != nil && *cfg.HistoricalRPC != oldHistorical {
+		s.lock.Lock()
+		old := s.historicalRPCService
+		s.historicalRPCService = historical
+		s.config.RollupHistoricalRPC = *cfg.HistoricalRPC
+		s.lock.Unlock()
+
+		if old != nil {
+			time.AfterFunc(replacedClientGracePeriod, old.Close)
+		}
+		log.Warn("Runtime configuration changed", "source", source, "field", "historicalRPC", "old", redactURL(oldHistorical), "new", redactURL(*cfg.HistoricalRPC))
+	}
+	return nil
+}
+
+// sequencerClient returns the RPC client transactions are forwarded through,
+// or nil if forwarding is disabled.
+func (s *Ethereum) sequencerClient() *rpc.Client {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.seqRPCService
+}
+
+// historicalClient returns the RPC client serving pre-Bedrock data, or nil if
+// none is configured.
+func (s *Ethereum) historicalClient() *rpc.Client {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.historicalRPCService
+}
+
+func dialRuntimeClient(endpoint string) (*rpc.Client, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), runtimeConfigDialTimeout)
+	defer cancel()
+
+	return rpc.DialContext(ctx, endpoint)
+}
+
+// redactURL strips any credentials from an endpoint before it is logged.
+func redactURL(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	return u.Redacted()
+}
+
+// runtimeConfigLoop watches the runtime configuration file and applies it
+// whenever it is modified.
+func (s *Ethereum) runtimeConfigLoop(path string) {
+	defer s.runtimeWg.Done()
+
+	var lastMod time.Time
+	check := func() {
+		info, err := os.Stat(path)
+		if err != nil {
+			log.Warn("Failed to stat runtime configuration", "path", path, "err", err)
+			return
+		}
+		if info.ModTime().Equal(lastMod) {
+			return
+		}
+		lastMod = info.ModTime()
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Warn("Failed to read runtime configuration", "path", path, "err", err)
+			return
+		}
+		var cfg RuntimeConfig
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			log.Error("Invalid runtime configuration", "path", path, "err", err)
+			return
+		}
+		if err := s.ApplyRuntimeConfig(&cfg, "file"); err != nil {
+			log.Error("Rejected runtime configuration", "path", path, "err", err)
+		}
+	}
+	check()
+
+	ticker := time.NewTicker(runtimeConfigPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			check()
+		case <-s.runtimeQuit:
+			return
+		}
+	}
+}
+
+// governedGasCeil applies the gas ceilings set by the gas governor, keeping the
+// reported runtime configuration in sync.
+type governedGasCeil struct {
+	e *Ethereum
+}
+
+// SetGasCeil implements gasgovernor.Miner.
+func (g governedGasCeil) SetGasCeil(ceil uint64) {
+	g.e.lock.Lock()
+	g.e.gasCeil = ceil
+	g.e.lock.Unlock()
+
+	g.e.miner.SetGasCeil(ceil)
+}
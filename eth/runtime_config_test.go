@@ -0,0 +1,107 @@
+package eth
+
+import (
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// newTestEthereum creates a full node, not started, forwarding its transactions
+// to the given sequencer endpoint if any.
+func newTestEthereum(t *testing.T, sequencerHTTP string) *Ethereum {
+	stack, err := node.New(&node.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { stack.Close() })
+
+	config := ethconfig.Defaults
+	config.Genesis = core.DeveloperGenesisBlock(10_000_000, testAddr)
+	config.SyncMode = downloader.FullSync
+	config.RollupSequencerHTTP = sequencerHTTP
+
+	eth, err := New(stack, &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return eth
+}
+
+// newTestEndpoint starts an HTTP RPC endpoint serving nothing.
+func newTestEndpoint(t *testing.T) string {
+	server := httptest.NewServer(rpc.NewServer())
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestApplyRuntimeConfig(t *testing.T) {
+	var (
+		primary   = newTestEndpoint(t)
+		secondary = newTestEndpoint(t)
+		eth       = newTestEthereum(t, primary)
+	)
+	var (
+		ceil       = hexutil.Uint64(20_000_000)
+		price      = (*hexutil.Big)(big.NewInt(2 * params.GWei))
+		historical = secondary
+	)
+	if err := eth.ApplyRuntimeConfig(&RuntimeConfig{GasCeil: &ceil, GasPrice: price, SequencerHTTP: &secondary, HistoricalRPC: &historical}, "test"); err != nil {
+		t.Fatalf("failed to apply runtime config: %v", err)
+	}
+	cfg := eth.RuntimeConfig()
+	if *cfg.GasCeil != ceil || cfg.GasPrice.ToInt().Cmp(price.ToInt()) != 0 {
+		t.Fatalf("gas settings mismatch: have ceil %d price %v, want ceil %d price %v", *cfg.GasCeil, cfg.GasPrice, ceil, price)
+	}
+	if *cfg.SequencerHTTP != secondary || *cfg.HistoricalRPC != secondary {
+		t.Fatalf("endpoints mismatch: have sequencer %s historical %s, want %s", *cfg.SequencerHTTP, *cfg.HistoricalRPC, secondary)
+	}
+	if eth.sequencerClient() == nil || eth.historicalClient() == nil {
+		t.Fatal("forwarding clients not replaced")
+	}
+	// Nil fields are left unchanged
+	if err := eth.ApplyRuntimeConfig(&RuntimeConfig{}, "test"); err != nil {
+		t.Fatalf("failed to apply empty runtime config: %v", err)
+	}
+	if cfg := eth.RuntimeConfig(); *cfg.GasCeil != ceil || *cfg.SequencerHTTP != secondary {
+		t.Fatal("empty runtime config changed the settings")
+	}
+}
+
+// Tests that an invalid runtime configuration is rejected as a whole.
+func TestApplyRuntimeConfigInvalid(t *testing.T) {
+	var (
+		primary = newTestEndpoint(t)
+		eth     = newTestEthereum(t, primary)
+		ceil    = hexutil.Uint64(20_000_000)
+		old     = eth.RuntimeConfig()
+	)
+	var (
+		lowCeil  = hexutil.Uint64(params.MinGasLimit - 1)
+		negative = (*hexutil.Big)(big.NewInt(-1))
+		disabled = ""
+		invalid  = "invalid://endpoint"
+	)
+	tests := []*RuntimeConfig{
+		{GasCeil: &lowCeil},
+		{GasCeil: &ceil, GasPrice: negative},
+		{GasCeil: &ceil, SequencerHTTP: &disabled},
+		{GasCeil: &ceil, SequencerHTTP: &invalid},
+		{GasCeil: &ceil, HistoricalRPC: &invalid},
+	}
+	for i, cfg := range tests {
+		if err := eth.ApplyRuntimeConfig(cfg, "test"); err == nil {
+			t.Errorf("test %d: invalid runtime config accepted", i)
+		}
+	}
+	if cfg := eth.RuntimeConfig(); *cfg.GasCeil != *old.GasCeil || *cfg.SequencerHTTP != primary {
+		t.Fatalf("rejected runtime config partially applied: have ceil %d sequencer %s", *cfg.GasCeil, *cfg.SequencerHTTP)
+	}
+}
@@ -22,7 +22,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math/big"
 	"os"
 	"runtime"
 	"sync"
@@ -277,7 +276,7 @@ func (api *API) traceChain(start, end *types.Block, config *TraceConfig, closed
 						TxIndex:     i,
 						TxHash:      tx.Hash(),
 					}
-					res, err := api.traceTx(ctx, msg, txctx, blockCtx, task.statedb, config)
+					res, err := api.traceTx(ctx, msg, txctx, blockCtx, task.statedb, api.backend.ChainConfig(), config)
 					if err != nil {
 						task.results[i] = &txTraceResult{TxHash: tx.Hash(), Error: err.Error()}
 						log.Warn("Tracing failed", "hash", tx.Hash(), "block", task.block.NumberU64(), "err", err)
@@ -432,49 +431,64 @@ func (api *API) traceChain(start, end *types.Block, config *TraceConfig, closed
 // TraceBlockByNumber returns the structured logs created during the execution of
 // EVM and returns them as a JSON object.
 func (api *API) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber, config *TraceConfig) ([]*txTraceResult, error) {
+	// Route pre-Bedrock blocks and the ones whose body was pruned locally to the
+	// historical endpoint, checking the header only as the body may be missing.
+	if header, _ := api.backend.HeaderByNumber(ctx, number); header != nil {
+		client, err := ethapi.HistoricalClient(api.backend, header.Number.Uint64())
+		if err != nil {
+			return nil, err
+		}
+		if client != nil {
+			return api.traceHistoricalBlock(ctx, client, "debug_traceBlockByNumber", number, config)
+		}
+	}
 	block, err := api.blockByNumber(ctx, number)
 	if err != nil {
 		return nil, err
 	}
-
-	if api.backend.ChainConfig().IsOptimismPreBedrock(block.Number()) {
-		if api.backend.HistoricalRPCService() != nil {
-			var histResult []*txTraceResult
-			err = api.backend.HistoricalRPCService().CallContext(ctx, &histResult, "debug_traceBlockByNumber", number, config)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return histResult, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
-		}
-	}
-
 	return api.traceBlock(ctx, block, config)
 }
 
 // TraceBlockByHash returns the structured logs created during the execution of
 // EVM and returns them as a JSON object.
 func (api *API) TraceBlockByHash(ctx context.Context, hash common.Hash, config *TraceConfig) ([]*txTraceResult, error) {
+	// Route pre-Bedrock blocks and the ones whose body was pruned locally to the
+	// historical endpoint, checking the header only as the body may be missing.
+	if header, _ := api.backend.HeaderByHash(ctx, hash); header != nil {
+		client, err := ethapi.HistoricalClient(api.backend, header.Number.Uint64())
+		if err != nil {
+			return nil, err
+		}
+		if client != nil {
+			return api.traceHistoricalBlock(ctx, client, "debug_traceBlockByHash", hash, config)
+		}
+	}
 	block, err := api.blockByHash(ctx, hash)
 	if err != nil {
 		return nil, err
 	}
+	return api.traceBlock(ctx, block, config)
+}
 
-	if api.backend.ChainConfig().IsOptimismPreBedrock(block.Number()) {
-		if api.backend.HistoricalRPCService() != nil {
-			var histResult []*txTraceResult
-			err = api.backend.HistoricalRPCService().CallContext(ctx, &histResult, "debug_traceBlockByHash", hash, config)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return histResult, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
+// traceHistoricalBlock traces a block through the historical endpoint, translating
+// the results to the schema of the local tracers.
+func (api *API) traceHistoricalBlock(ctx context.Context, client *rpc.Client, method string, arg interface{}, config *TraceConfig) ([]*txTraceResult, error) {
+	var histResult []struct {
+		TxHash common.Hash     `json:"txHash"`
+		Result json.RawMessage `json:"result"`
+		Error  string          `json:"error"`
+	}
+	if err := ethapi.CallHistorical(ctx, client, &histResult, method, arg, config); err != nil {
+		return nil, fmt.Errorf("historical backend error: %w", err)
+	}
+	results := make([]*txTraceResult, len(histResult))
+	for i, res := range histResult {
+		results[i] = &txTraceResult{TxHash: res.TxHash, Error: res.Error}
+		if len(res.Result) > 0 && string(res.Result) != "null" {
+			results[i].Result = ethapi.TranslateHistoricalTrace(res.Result)
 		}
 	}
-
-	return api.traceBlock(ctx, block, config)
+	return results, nil
 }
 
 // TraceBlock returns the structured logs created during the execution of EVM
@@ -641,7 +655,7 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 			TxIndex:     i,
 			TxHash:      tx.Hash(),
 		}
-		res, err := api.traceTx(ctx, msg, txctx, blockCtx, statedb, config)
+		res, err := api.traceTx(ctx, msg, txctx, blockCtx, statedb, api.backend.ChainConfig(), config)
 		if err != nil {
 			return nil, err
 		}
@@ -684,7 +698,7 @@ func (api *API) traceBlockParallel(ctx context.Context, block *types.Block, stat
 					TxIndex:     task.index,
 					TxHash:      txs[task.index].Hash(),
 				}
-				res, err := api.traceTx(ctx, msg, txctx, blockCtx, task.statedb, config)
+				res, err := api.traceTx(ctx, msg, txctx, blockCtx, task.statedb, api.backend.ChainConfig(), config)
 				if err != nil {
 					results[task.index] = &txTraceResult{TxHash: txs[task.index].Hash(), Error: err.Error()}
 					continue
@@ -862,17 +876,19 @@ func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *
 		return nil, err
 	}
 
-	if api.backend.ChainConfig().IsOptimismPreBedrock(new(big.Int).SetUint64(blockNumber)) {
-		if api.backend.HistoricalRPCService() != nil {
-			var histResult json.RawMessage
-			err := api.backend.HistoricalRPCService().CallContext(ctx, &histResult, "debug_traceTransaction", hash, config)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return histResult, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
+	// Transactions unknown locally report block zero, routing them to the
+	// historical endpoint if the transaction index was pruned.
+	client, err := ethapi.HistoricalClient(api.backend, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if client != nil {
+		var histResult json.RawMessage
+		err := ethapi.CallHistorical(ctx, client, &histResult, "debug_traceTransaction", hash, config)
+		if err != nil {
+			return nil, fmt.Errorf("historical backend error: %w", err)
 		}
+		return ethapi.TranslateHistoricalTrace(histResult), nil
 	}
 
 	// It shouldn't happen in practice.
@@ -899,7 +915,7 @@ func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *
 		TxIndex:     int(index),
 		TxHash:      hash,
 	}
-	return api.traceTx(ctx, msg, txctx, vmctx, statedb, config)
+	return api.traceTx(ctx, msg, txctx, vmctx, statedb, api.backend.ChainConfig(), config)
 }
 
 // TraceCall lets you trace a given eth_call. It collects the structured logs
@@ -945,7 +961,13 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 	}
 	defer release()
 
-	vmctx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil, api.backend.ChainConfig(), statedb)
+	// Enable or disable forks and change rollup fee parameters for this call
+	// only, if requested. The overrides are applied to a copy of the config.
+	chainConfig := api.backend.ChainConfig()
+	if config != nil && config.Config != nil && config.Overrides != nil {
+		chainConfig, _ = overrideConfig(chainConfig, config.Overrides)
+	}
+	vmctx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil, chainConfig, statedb)
 	// Apply the customization rules if required.
 	if config != nil {
 		if err := config.StateOverrides.Apply(statedb); err != nil {
@@ -963,13 +985,13 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 	if config != nil {
 		traceConfig = &config.TraceConfig
 	}
-	return api.traceTx(ctx, msg, new(Context), vmctx, statedb, traceConfig)
+	return api.traceTx(ctx, msg, new(Context), vmctx, statedb, chainConfig, traceConfig)
 }
 
 // traceTx configures a new tracer according to the provided configuration, and
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
-func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
+func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, chainConfig *params.ChainConfig, config *TraceConfig) (interface{}, error) {
 	var (
 		tracer    Tracer
 		err       error
@@ -979,6 +1001,19 @@ func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Conte
 	if config == nil {
 		config = &TraceConfig{}
 	}
+	// Expose the rollup-specific balance changes made by the state transition
+	if message.IsDepositTx {
+		txctx.IsDepositTx = true
+		txctx.IsSystemTx = message.IsSystemTx
+		txctx.Mint = message.Mint
+	} else if !message.SkipAccountChecks {
+		if vmctx.L1CostFunc != nil {
+			txctx.L1Cost = vmctx.L1CostFunc(vmctx.BlockNumber.Uint64(), vmctx.Time, message.RollupDataGas, false)
+		}
+		if vmctx.OperatorCostFunc != nil {
+			txctx.OperatorCost = vmctx.OperatorCostFunc(message.GasLimit, vmctx.Time)
+		}
+	}
 	// Default tracer is the struct logger
 	tracer = logger.NewStructLogger(config.Config)
 	if config.Tracer != nil {
@@ -987,7 +1022,7 @@ func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Conte
 			return nil, err
 		}
 	}
-	vmenv := vm.NewEVM(vmctx, txContext, statedb, api.backend.ChainConfig(), vm.Config{Tracer: tracer, NoBaseFee: true})
+	vmenv := vm.NewEVM(vmctx, txContext, statedb, chainConfig, vm.Config{Tracer: tracer, NoBaseFee: true})
 
 	// Define a meaningful timeout of a single transaction trace
 	if config.Timeout != nil {
@@ -1071,5 +1106,92 @@ func overrideConfig(original *params.ChainConfig, override *params.ChainConfig)
 		canon = false
 	}
 
+	// Apply the optimism forks and fee parameters to the copy.
+	if block := override.BedrockBlock; block != nil {
+		copy.BedrockBlock = block
+		canon = false
+	}
+	if timestamp := override.RegolithTime; timestamp != nil {
+		copy.RegolithTime = timestamp
+		canon = false
+	}
+	if timestamp := override.CanyonTime; timestamp != nil {
+		copy.CanyonTime = timestamp
+		canon = false
+	}
+	if timestamp := override.OasysOperatorFeeTime; timestamp != nil {
+		copy.OasysOperatorFeeTime = timestamp
+		canon = false
+	}
+	if timestamp := override.JovianTime; timestamp != nil {
+		copy.JovianTime = timestamp
+		canon = false
+	}
+	if timestamp := override.OasysL1VerifierTime; timestamp != nil {
+		copy.OasysL1VerifierTime = timestamp
+		canon = false
+	}
+	if timestamp := override.OasysStateGrowthTime; timestamp != nil {
+		copy.OasysStateGrowthTime = timestamp
+		canon = false
+	}
+	if timestamp := override.OasysGasDimensionsTime; timestamp != nil {
+		copy.OasysGasDimensionsTime = timestamp
+		canon = false
+	}
+	if timestamp := override.OasysBaseFeeCapTime; timestamp != nil {
+		copy.OasysBaseFeeCapTime = timestamp
+		canon = false
+	}
+	if timestamp := override.OasysBlockSizeTime; timestamp != nil {
+		copy.OasysBlockSizeTime = timestamp
+		canon = false
+	}
+	if forks := override.EVMForks; forks != nil {
+		copy.EVMForks = forks
+		canon = false
+	}
+	if timestamp := override.InteropTime; timestamp != nil {
+		copy.InteropTime = timestamp
+		canon = false
+	}
+	if times := override.ZeroFeeTimes; times != nil {
+		copy.ZeroFeeTimes = times
+		canon = false
+	}
+	if policies := override.PriorityFeePolicies; policies != nil {
+		copy.PriorityFeePolicies = policies
+		canon = false
+	}
+	if op := override.Optimism; op != nil && original.Optimism != nil {
+		merged := *original.Optimism
+		if op.EIP1559Elasticity != 0 {
+			merged.EIP1559Elasticity = op.EIP1559Elasticity
+		}
+		if op.EIP1559Denominator != 0 {
+			merged.EIP1559Denominator = op.EIP1559Denominator
+		}
+		if op.EIP1559DenominatorCanyon != 0 {
+			merged.EIP1559DenominatorCanyon = op.EIP1559DenominatorCanyon
+		}
+		if op.EIP1559MaxChangeBps != 0 {
+			merged.EIP1559MaxChangeBps = op.EIP1559MaxChangeBps
+		}
+		if op.MinTipContract != nil {
+			merged.MinTipContract = op.MinTipContract
+		}
+		if op.StateGrowthLimit != 0 {
+			merged.StateGrowthLimit = op.StateGrowthLimit
+		}
+		if op.GasDimensions != nil {
+			merged.GasDimensions = op.GasDimensions
+		}
+		if op.MaxBlockSize != 0 {
+			merged.MaxBlockSize = op.MaxBlockSize
+		}
+		copy.Optimism = &merged
+		canon = false
+	}
+
 	return copy, canon
 }
@@ -1001,6 +1001,86 @@ func newStates(keys []common.Hash, vals []common.Hash) *map[common.Hash]common.H
 	return &m
 }
 
+func TestOverrideConfigOptimism(t *testing.T) {
+	t.Parallel()
+
+	var (
+		canyon   = uint64(100)
+		original = *params.TestChainConfig
+	)
+	original.BedrockBlock = big.NewInt(0)
+	original.Optimism = &params.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50, EIP1559DenominatorCanyon: 250}
+
+	// An empty override must leave the config canonical
+	if _, canon := overrideConfig(&original, new(params.ChainConfig)); !canon {
+		t.Fatalf("empty override reported as non-canonical")
+	}
+	// Activate Canyon early and tweak a single fee parameter
+	override := &params.ChainConfig{
+		CanyonTime: &canyon,
+		Optimism:   &params.OptimismConfig{EIP1559DenominatorCanyon: 100},
+	}
+	have, canon := overrideConfig(&original, override)
+	if canon {
+		t.Fatalf("override reported as canonical")
+	}
+	if have.CanyonTime == nil || *have.CanyonTime != canyon {
+		t.Errorf("canyon time not overridden: have %v", have.CanyonTime)
+	}
+	if have.Optimism.EIP1559DenominatorCanyon != 100 || have.Optimism.EIP1559Denominator != 50 || have.Optimism.EIP1559Elasticity != 6 {
+		t.Errorf("optimism params not merged: have %+v", have.Optimism)
+	}
+	// The original config must not be modified
+	if original.CanyonTime != nil || original.Optimism.EIP1559DenominatorCanyon != 250 {
+		t.Errorf("original config modified")
+	}
+
+	// Schedule the Oasys forks along with their parameters
+	var (
+		fork     = uint64(200)
+		evmForks = []params.EVMForkConfig{{Name: "nopush0", Time: fork, DisableEIPs: []int{3855}}}
+		policies = []params.PriorityFeePolicy{{Time: fork, Mode: params.PriorityFeeToVault, Vault: &common.Address{1}}}
+	)
+	override = &params.ChainConfig{
+		OasysL1VerifierTime:    &fork,
+		OasysStateGrowthTime:   &fork,
+		OasysGasDimensionsTime: &fork,
+		OasysBaseFeeCapTime:    &fork,
+		OasysBlockSizeTime:     &fork,
+		EVMForks:               evmForks,
+		PriorityFeePolicies:    policies,
+		Optimism: &params.OptimismConfig{
+			StateGrowthLimit: 10,
+			GasDimensions:    &params.GasDimensionsConfig{MaxDABytes: 1000},
+			MaxBlockSize:     2000,
+		},
+	}
+	have, canon = overrideConfig(&original, override)
+	if canon {
+		t.Fatalf("override reported as canonical")
+	}
+	for name, time := range map[string]*uint64{
+		"l1 verifier":    have.OasysL1VerifierTime,
+		"state growth":   have.OasysStateGrowthTime,
+		"gas dimensions": have.OasysGasDimensionsTime,
+		"base fee cap":   have.OasysBaseFeeCapTime,
+		"block size":     have.OasysBlockSizeTime,
+	} {
+		if time == nil || *time != fork {
+			t.Errorf("%s time not overridden: have %v", name, time)
+		}
+	}
+	if !reflect.DeepEqual(have.EVMForks, evmForks) {
+		t.Errorf("evm forks not overridden: have %v", have.EVMForks)
+	}
+	if !reflect.DeepEqual(have.PriorityFeePolicies, policies) {
+		t.Errorf("priority fee policies not overridden: have %v", have.PriorityFeePolicies)
+	}
+	if have.Optimism.StateGrowthLimit != 10 || have.Optimism.GasDimensions.MaxDABytes != 1000 || have.Optimism.MaxBlockSize != 2000 || have.Optimism.EIP1559Denominator != 50 {
+		t.Errorf("optimism limits not merged: have %+v", have.Optimism)
+	}
+}
+
 func TestTraceChain(t *testing.T) {
 	// Initialize test accounts
 	accounts := newAccounts(3)
@@ -25,11 +25,13 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/tests"
 )
 
@@ -149,3 +151,61 @@ func testPrestateDiffTracer(tracerName string, dirPath string, t *testing.T) {
 		})
 	}
 }
+
+// TestPrestateTracerDeposit checks that the sender balance reported for a deposit
+// excludes the amount minted by the state transition before execution.
+func TestPrestateTracerDeposit(t *testing.T) {
+	var (
+		from    = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		to      = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		balance = big.NewInt(params.Ether)
+		mint    = big.NewInt(2 * params.Ether)
+		zero    = uint64(0)
+		config  = *params.TestChainConfig
+	)
+	config.BedrockBlock = big.NewInt(0)
+	config.RegolithTime = &zero
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50}
+
+	alloc := core.GenesisAlloc{from: {Balance: balance}}
+	triedb, _, statedb := tests.MakePreState(rawdb.NewMemoryDatabase(), alloc, false, rawdb.HashScheme)
+	defer triedb.Close()
+
+	msg := &core.Message{
+		From:        from,
+		To:          &to,
+		Value:       big.NewInt(params.GWei),
+		GasLimit:    100_000,
+		GasPrice:    new(big.Int),
+		GasFeeCap:   new(big.Int),
+		GasTipCap:   new(big.Int),
+		IsDepositTx: true,
+		Mint:        mint,
+	}
+	tracer, err := tracers.DefaultDirectory.New("prestateTracer", &tracers.Context{IsDepositTx: true, Mint: mint}, nil)
+	if err != nil {
+		t.Fatalf("failed to create prestate tracer: %v", err)
+	}
+	context := vm.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		BlockNumber: big.NewInt(1),
+		GasLimit:    30_000_000,
+		BaseFee:     big.NewInt(params.InitialBaseFee),
+	}
+	evm := vm.NewEVM(context, core.NewEVMTxContext(msg), statedb, &config, vm.Config{Tracer: tracer})
+	if _, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(msg.GasLimit)); err != nil {
+		t.Fatalf("failed to execute deposit: %v", err)
+	}
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
+	}
+	var trace prestateTrace
+	if err := json.Unmarshal(res, &trace); err != nil {
+		t.Fatalf("failed to parse trace result: %v", err)
+	}
+	if have, want := trace[from].Balance, hexutil.EncodeBig(balance); have != want {
+		t.Fatalf("sender balance mismatch: have %v, want %v", have, want)
+	}
+}
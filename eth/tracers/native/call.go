@@ -103,11 +103,14 @@ type callFrameMarshaling struct {
 
 type callTracer struct {
 	noopTracer
+	ctx       *tracers.Context
 	callstack []callFrame
 	config    callTracerConfig
 	gasLimit  uint64
 	interrupt atomic.Bool // Atomic flag to signal execution interruption
 	reason    error       // Textual reason for the interruption
+
+	preRegolith bool // Whether deposits report their gas usage in the legacy way
 }
 
 type callTracerConfig struct {
@@ -124,13 +127,17 @@ func newCallTracer(ctx *tracers.Context, cfg json.RawMessage) (tracers.Tracer, e
 			return nil, err
 		}
 	}
+	if ctx == nil {
+		ctx = new(tracers.Context)
+	}
 	// First callframe contains tx context info
 	// and is populated on start and end.
-	return &callTracer{callstack: make([]callFrame, 1), config: config}, nil
+	return &callTracer{ctx: ctx, callstack: make([]callFrame, 1), config: config}, nil
 }
 
 // CaptureStart implements the EVMLogger interface to initialize the tracing operation.
 func (t *callTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.preRegolith = !env.ChainConfig().IsRegolith(env.Context.Time)
 	toCopy := to
 	t.callstack[0] = callFrame{
 		Type:  vm.CALL,
@@ -249,6 +256,14 @@ func (t *callTracer) CaptureTxStart(gasLimit uint64) {
 
 func (t *callTracer) CaptureTxEnd(restGas uint64) {
 	t.callstack[0].GasUsed = t.gasLimit - restGas
+	// Before Regolith, deposits are recorded as using all their gas, except
+	// for system transactions which are recorded as using none.
+	if t.ctx.IsDepositTx && t.preRegolith {
+		t.callstack[0].GasUsed = t.gasLimit
+		if t.ctx.IsSystemTx {
+			t.callstack[0].GasUsed = 0
+		}
+	}
 	if t.config.WithLog {
 		// Logs are not emitted when the call fails
 		clearFailedLogs(&t.callstack[0], false)
@@ -0,0 +1,120 @@
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("depositTracer", newDepositTracer, false)
+}
+
+// depositResult is the output of the deposit tracer.
+type depositResult struct {
+	IsDeposit  bool            `json:"isDeposit"`
+	IsSystemTx bool            `json:"isSystemTx,omitempty"`
+	From       common.Address  `json:"from"`
+	To         *common.Address `json:"to,omitempty"`
+	Mint       *hexutil.Big    `json:"mint,omitempty"`
+	Value      *hexutil.Big    `json:"value,omitempty"`
+	L1Cost     *hexutil.Big    `json:"l1Cost,omitempty"`
+	Gas        hexutil.Uint64  `json:"gas"`
+	GasUsed    hexutil.Uint64  `json:"gasUsed"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// depositTracer surfaces the rollup-specific context of a transaction, which is
+// applied by the state transition outside of the EVM: the amount minted by a
+// deposit, whether it is a system transaction and the L1 data fee charged to
+// regular transactions.
+//
+// Example:
+//
+//	> debug.traceTransaction("0x7e2f...", {tracer: "depositTracer"})
+//	{
+//	  isDeposit: true,
+//	  from: "0xdeaddeaddeaddeaddeaddeaddeaddeaddead0001",
+//	  to: "0x4200000000000000000000000000000000000015",
+//	  gas: "0xf4240",
+//	  gasUsed: "0xb4e5"
+//	}
+type depositTracer struct {
+	noopTracer
+	result      depositResult
+	gasLimit    uint64
+	preRegolith bool  // Whether deposits report their gas usage in the legacy way
+	reason      error // Textual reason for the interruption
+}
+
+// newDepositTracer returns a native go tracer which reports the rollup-specific
+// context of a transaction, and implements vm.EVMLogger.
+func newDepositTracer(ctx *tracers.Context, _ json.RawMessage) (tracers.Tracer, error) {
+	if ctx == nil {
+		ctx = new(tracers.Context)
+	}
+	t := &depositTracer{
+		result: depositResult{
+			IsDeposit:  ctx.IsDepositTx,
+			IsSystemTx: ctx.IsSystemTx,
+			Mint:       (*hexutil.Big)(ctx.Mint),
+			L1Cost:     (*hexutil.Big)(ctx.L1Cost),
+		},
+	}
+	return t, nil
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *depositTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.preRegolith = !env.ChainConfig().IsRegolith(env.Context.Time)
+	t.result.From = from
+	if !create {
+		t.result.To = &to
+	}
+	if value != nil && value.Sign() > 0 {
+		t.result.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *depositTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	if err != nil {
+		t.result.Error = err.Error()
+	}
+}
+
+func (t *depositTracer) CaptureTxStart(gasLimit uint64) {
+	t.gasLimit = gasLimit
+	t.result.Gas = hexutil.Uint64(gasLimit)
+}
+
+func (t *depositTracer) CaptureTxEnd(restGas uint64) {
+	t.result.GasUsed = hexutil.Uint64(t.gasLimit - restGas)
+	// Before Regolith, deposits are recorded as using all their gas, except
+	// for system transactions which are recorded as using none.
+	if t.result.IsDeposit && t.preRegolith {
+		t.result.GasUsed = t.result.Gas
+		if t.result.IsSystemTx {
+			t.result.GasUsed = 0
+		}
+	}
+}
+
+// GetResult returns the json-encoded deposit context, and any error arising
+// from the encoding or forceful termination (via `Stop`).
+func (t *depositTracer) GetResult() (json.RawMessage, error) {
+	res, err := json.Marshal(t.result)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *depositTracer) Stop(err error) {
+	t.reason = err
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 //go:generate go run github.com/fjl/gencodec -type account -field-override accountMarshaling -out gen_account_json.go
@@ -56,6 +57,7 @@ type accountMarshaling struct {
 
 type prestateTracer struct {
 	noopTracer
+	ctx       *tracers.Context
 	env       *vm.EVM
 	pre       state
 	post      state
@@ -80,7 +82,11 @@ func newPrestateTracer(ctx *tracers.Context, cfg json.RawMessage) (tracers.Trace
 			return nil, err
 		}
 	}
+	if ctx == nil {
+		ctx = new(tracers.Context)
+	}
 	return &prestateTracer{
+		ctx:     ctx,
 		pre:     state{},
 		post:    state{},
 		config:  config,
@@ -99,6 +105,15 @@ func (t *prestateTracer) CaptureStart(env *vm.EVM, from common.Address, to commo
 	t.lookupAccount(to)
 	t.lookupAccount(env.Context.Coinbase)
 
+	// The fee vaults are credited by the state transition on rollups.
+	if env.ChainConfig().Optimism != nil && !t.ctx.IsDepositTx {
+		t.lookupAccount(params.OptimismBaseFeeRecipient)
+		t.lookupAccount(params.OptimismL1FeeRecipient)
+		if env.ChainConfig().IsOasysOperatorFee(env.Context.Time) {
+			t.lookupAccount(params.OptimismOperatorFeeRecipient)
+		}
+	}
+
 	// The recipient balance includes the value transferred.
 	toBal := new(big.Int).Sub(t.pre[to].Balance, value)
 	t.pre[to].Balance = toBal
@@ -109,6 +124,18 @@ func (t *prestateTracer) CaptureStart(env *vm.EVM, from common.Address, to commo
 	gasPrice := env.TxContext.GasPrice
 	consumedGas := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(t.gasLimit))
 	fromBal.Add(fromBal, new(big.Int).Add(value, consumedGas))
+
+	// On rollups the sender was also charged the L1 data and operator fees, or
+	// for deposits, credited the minted amount before execution.
+	if t.ctx.L1Cost != nil {
+		fromBal.Add(fromBal, t.ctx.L1Cost)
+	}
+	if t.ctx.OperatorCost != nil {
+		fromBal.Add(fromBal, t.ctx.OperatorCost)
+	}
+	if t.ctx.Mint != nil {
+		fromBal.Sub(fromBal, t.ctx.Mint)
+	}
 	t.pre[from].Balance = fromBal
 	t.pre[from].Nonce--
 
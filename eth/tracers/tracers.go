@@ -34,6 +34,15 @@ type Context struct {
 	BlockNumber *big.Int    // Number of the block the tx is contained within (zero if dangling tx or call)
 	TxIndex     int         // Index of the transaction within a block (zero if dangling tx or call)
 	TxHash      common.Hash // Hash of the transaction being traced (zero if dangling call)
+
+	// Rollup-specific transaction properties, which are applied by the state
+	// transition outside of the EVM and are thus not observable by tracers.
+	IsDepositTx bool     // Whether the transaction being traced is a deposit
+	IsSystemTx  bool     // Whether the deposit being traced is a system transaction
+	Mint        *big.Int // Amount minted to the sender of a deposit before execution (nil if none)
+	L1Cost      *big.Int // L1 data fee charged to the sender before execution (nil if none)
+
+	OperatorCost *big.Int // Operator fee of the gas limit charged to the sender before execution (nil if none)
 }
 
 // Tracer interface extends vm.EVMLogger and additionally
@@ -0,0 +1,233 @@
+// Package txwal implements the write-ahead log of the transactions accepted by a
+// sequencer, replayed into the transaction pool on restart so that no accepted
+// transaction is lost across crashes.
+package txwal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// compactInterval is the time between two compactions of the log.
+	compactInterval = time.Hour
+
+	// retainRecent is the age below which entries are kept on compaction, covering
+	// the transactions logged but not yet added to the pool.
+	retainRecent = time.Minute
+)
+
+var (
+	appendMeter   = metrics.NewRegisteredMeter("txwal/append", nil)
+	failureMeter  = metrics.NewRegisteredMeter("txwal/failure", nil)
+	appendTimer   = metrics.NewRegisteredTimer("txwal/append/duration", nil)
+	replayedGauge = metrics.NewRegisteredGauge("txwal/replayed", nil)
+)
+
+// Entry is a transaction recorded in the log.
+type Entry struct {
+	Time uint64             // Unix time in milliseconds the transaction was accepted at
+	Tx   *types.Transaction // Transaction accepted
+}
+
+// Pool is the transaction pool the log is replayed into.
+type Pool interface {
+	Has(hash common.Hash) bool
+	Add(txs []*types.Transaction, local bool, sync bool) []error
+}
+
+// WAL is an append-only log of the accepted transactions. Each entry is synced
+// to disk before returning, and the log is periodically rewritten to retain the
+// transactions still pooled only.
+type WAL struct {
+	path string
+	pool Pool
+
+	lock sync.Mutex
+	file *os.File
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New opens the log at path, replaying its transactions into the pool and
+// dropping the ones which are not pooled afterwards.
+func New(path string, pool Pool) (*WAL, error) {
+	w := &WAL{
+		path: path,
+		pool: pool,
+		quit: make(chan struct{}),
+	}
+	var txs []*types.Transaction
+	if err := Read(path, func(entry *Entry) error {
+		txs = append(txs, entry.Tx)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if len(txs) > 0 {
+		var dropped int
+		for _, err := range pool.Add(txs, true, true) {
+			if err != nil {
+				dropped++
+			}
+		}
+		replayedGauge.Update(int64(len(txs) - dropped))
+		log.Info("Replayed transaction write-ahead log", "transactions", len(txs), "dropped", dropped)
+	}
+	if err := w.rotate(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Start launches the background loop compacting the log.
+func (w *WAL) Start() {
+	w.wg.Add(1)
+	go w.loop()
+}
+
+// Stop terminates the background loop and closes the log.
+func (w *WAL) Stop() {
+	close(w.quit)
+	w.wg.Wait()
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.file != nil {
+		w.file.Close()
+		w.file = nil
+	}
+}
+
+// Append records a transaction accepted by the node, returning once the entry
+// is synced to disk.
+func (w *WAL) Append(tx *types.Transaction) error {
+	start := time.Now()
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.file == nil {
+		return errors.New("transaction write-ahead log closed")
+	}
+	if err := rlp.Encode(w.file, &Entry{Time: uint64(start.UnixMilli()), Tx: tx}); err != nil {
+		failureMeter.Mark(1)
+		return fmt.Errorf("failed to log transaction: %w", err)
+	}
+	if err := w.file.Sync(); err != nil {
+		failureMeter.Mark(1)
+		return fmt.Errorf("failed to sync transaction log: %w", err)
+	}
+	appendMeter.Mark(1)
+	appendTimer.UpdateSince(start)
+	return nil
+}
+
+func (w *WAL) loop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(compactInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.rotate(); err != nil {
+				log.Warn("Failed to compact transaction write-ahead log", "err", err)
+			}
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+// rotate rewrites the log with the transactions still pooled, and reopens it for
+// appending.
+func (w *WAL) rotate() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var entries []*Entry
+	if err := Read(w.path, func(entry *Entry) error {
+		if w.pool.Has(entry.Tx.Hash()) || time.Since(time.UnixMilli(int64(entry.Time))) < retainRecent {
+			entries = append(entries, entry)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	replacement, err := os.OpenFile(w.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err = rlp.Encode(replacement, entry); err != nil {
+			replacement.Close()
+			return err
+		}
+	}
+	if err := replacement.Sync(); err != nil {
+		replacement.Close()
+		return err
+	}
+	replacement.Close()
+
+	if w.file != nil {
+		w.file.Close()
+		w.file = nil
+	}
+	if err = os.Rename(w.path+".new", w.path); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	w.file = file
+	log.Debug("Compacted transaction write-ahead log", "transactions", len(entries))
+	return nil
+}
+
+// Read iterates over the entries of the log at path. An entry truncated by a
+// crash at the end of the log is ignored.
+func Read(path string, fn func(entry *Entry) error) error {
+	input, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	stream := rlp.NewStream(input, 0)
+	for {
+		entry := new(Entry)
+		if err := stream.Decode(entry); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				log.Warn("Ignoring truncated transaction write-ahead log entry", "path", path)
+				return nil
+			}
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package txwal
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type testPool struct {
+	txs map[common.Hash]*types.Transaction
+}
+
+func (p *testPool) Has(hash common.Hash) bool {
+	return p.txs[hash] != nil
+}
+
+func (p *testPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
+	for _, tx := range txs {
+		p.txs[tx.Hash()] = tx
+	}
+	return make([]error, len(txs))
+}
+
+func TestReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "txwal.rlp")
+
+	pool := &testPool{txs: make(map[common.Hash]*types.Transaction)}
+	wal, err := New(path, pool)
+	if err != nil {
+		t.Fatalf("failed to open log: %v", err)
+	}
+	var txs []*types.Transaction
+	for i := 0; i < 3; i++ {
+		tx := types.NewTransaction(uint64(i), common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+		if err := wal.Append(tx); err != nil {
+			t.Fatalf("failed to append transaction %d: %v", i, err)
+		}
+		txs = append(txs, tx)
+	}
+	wal.Stop()
+
+	// Simulate a crash in the middle of an entry
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("failed to open log: %v", err)
+	}
+	file.Write([]byte{0xf8, 0x60, 0x01})
+	file.Close()
+
+	// All transactions must be replayed into the pool on restart
+	pool = &testPool{txs: make(map[common.Hash]*types.Transaction)}
+	if wal, err = New(path, pool); err != nil {
+		t.Fatalf("failed to reopen log: %v", err)
+	}
+	defer wal.Stop()
+
+	for i, tx := range txs {
+		if !pool.Has(tx.Hash()) {
+			t.Errorf("transaction %d not replayed", i)
+		}
+	}
+	// The truncated entry must be dropped by the compaction on startup
+	var count int
+	if err := Read(path, func(entry *Entry) error {
+		count++
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to read compacted log: %v", err)
+	}
+	if count != len(txs) {
+		t.Errorf("compacted log entry count mismatch: have %d, want %d", count, len(txs))
+	}
+}
@@ -0,0 +1,154 @@
+package withdrawalindex
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxWithdrawals is the maximum number of withdrawals returned by a query.
+const maxWithdrawals = 1000
+
+// API serves the withdrawal index under the oasys namespace.
+type API struct {
+	idx *indexer
+}
+
+// Withdrawal is a withdrawal initiated on L2, along with the block context
+// needed to prove it on L1.
+type Withdrawal struct {
+	BlockHash        common.Hash                   `json:"blockHash"`
+	BlockNumber      hexutil.Uint64                `json:"blockNumber"`
+	StateRoot        common.Hash                   `json:"stateRoot"`
+	TransactionHash  common.Hash                   `json:"transactionHash"`
+	TransactionIndex hexutil.Uint64                `json:"transactionIndex"`
+	LogIndex         hexutil.Uint64                `json:"logIndex"`
+	WithdrawalHash   common.Hash                   `json:"withdrawalHash"`
+	Withdrawal       *ethapi.WithdrawalTransaction `json:"withdrawal"`
+}
+
+// GetWithdrawals returns the withdrawals involving the given address in the given
+// range of blocks, both ends included, in chain order. The blocks not indexed yet
+// are scanned.
+func (api *API) GetWithdrawals(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber) ([]*Withdrawal, error) {
+	head := rawdb.ReadHeaderNumber(api.idx.chainDb, rawdb.ReadHeadBlockHash(api.idx.chainDb))
+	if head == nil {
+		return nil, errors.New("chain head unknown")
+	}
+	from, err := resolveNumber(fromBlock, *head)
+	if err != nil {
+		return nil, err
+	}
+	to, err := resolveNumber(toBlock, *head)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, errors.New("invalid block range")
+	}
+	var (
+		withdrawals []*withdrawal
+		indexed     = api.idx.indexed()
+	)
+	// Retrieve the withdrawals of the indexed sections
+	if from < indexed {
+		prefix := append(append([]byte{}, entryPrefix...), address.Bytes()...)
+		it := api.idx.indexDb.NewIterator(prefix, encodeNumber(from))
+		for it.Next() {
+			if len(it.Key()) != entryKeyLength {
+				continue
+			}
+			number := binary.BigEndian.Uint64(it.Key()[len(prefix):])
+			if number > to || number >= indexed {
+				break
+			}
+			w := &withdrawal{Number: number, LogIndex: binary.BigEndian.Uint64(it.Key()[len(prefix)+8:])}
+			if err := rlp.DecodeBytes(it.Value(), &w.entry); err != nil {
+				it.Release()
+				return nil, err
+			}
+			if len(withdrawals) == maxWithdrawals {
+				it.Release()
+				return nil, fmt.Errorf("more than %d withdrawals, reduce the block range", maxWithdrawals)
+			}
+			withdrawals = append(withdrawals, w)
+		}
+		it.Release()
+		if err := it.Error(); err != nil {
+			return nil, err
+		}
+	}
+	// Scan the blocks not indexed yet
+	if indexed > from {
+		from = indexed
+	}
+	for number := from; number <= to; number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		hash := rawdb.ReadCanonicalHash(api.idx.chainDb, number)
+		header := rawdb.ReadHeader(api.idx.chainDb, hash, number)
+		if header == nil {
+			return nil, fmt.Errorf("block #%d not found", number)
+		}
+		found, err := scanBlock(api.idx.chainDb, header)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range found {
+			for _, addr := range w.addresses() {
+				if addr != address {
+					continue
+				}
+				if len(withdrawals) == maxWithdrawals {
+					return nil, fmt.Errorf("more than %d withdrawals, reduce the block range", maxWithdrawals)
+				}
+				withdrawals = append(withdrawals, w)
+			}
+		}
+	}
+	return newWithdrawals(withdrawals)
+}
+
+// resolveNumber resolves a block number of a query against the chain head.
+func resolveNumber(number rpc.BlockNumber, head uint64) (uint64, error) {
+	switch {
+	case number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber:
+		return head, nil
+	case number < 0:
+		return 0, fmt.Errorf("block tag %d not supported", number)
+	case uint64(number) > head:
+		return head, nil
+	}
+	return uint64(number), nil
+}
+
+// newWithdrawals decodes the withdrawals of the located MessagePassed events.
+func newWithdrawals(located []*withdrawal) ([]*Withdrawal, error) {
+	withdrawals := make([]*Withdrawal, 0, len(located))
+	for _, w := range located {
+		tx, err := ethapi.ParseMessagePassed(w.Log)
+		if err != nil {
+			return nil, fmt.Errorf("block #%d, transaction %x: %w", w.Number, w.TxHash, err)
+		}
+		withdrawals = append(withdrawals, &Withdrawal{
+			BlockHash:        w.BlockHash,
+			BlockNumber:      hexutil.Uint64(w.Number),
+			StateRoot:        w.StateRoot,
+			TransactionHash:  w.TxHash,
+			TransactionIndex: hexutil.Uint64(w.TxIndex),
+			LogIndex:         hexutil.Uint64(w.LogIndex),
+			WithdrawalHash:   tx.Hash(),
+			Withdrawal:       tx,
+		})
+	}
+	return withdrawals, nil
+}
@@ -0,0 +1,280 @@
+// Package withdrawalindex implements a custom chain index of the withdrawals
+// initiated on L2, i.e. the MessagePassed events of the L2ToL1MessagePasser, by
+// the addresses involved: the sender and target of the withdrawal and, for the
+// messages relayed by the cross domain messenger, those of the message and of
+// the bridge transfer it finalizes. It is enabled with --rollup.indexers=withdrawals
+// and serves oasys_getWithdrawals, sparing the bridge UIs heavy log scans.
+package withdrawalindex
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// Name is the name of the index, as enabled with --rollup.indexers.
+	Name = "withdrawals"
+
+	// sectionSize is the number of blocks of an indexed section. The blocks not
+	// indexed yet are scanned on query, so it bounds the cost of the queries.
+	sectionSize = 128
+
+	// confirms is the number of confirmations before a section is indexed.
+	confirms = 32
+)
+
+var (
+	entryPrefix    = []byte("a") // entryPrefix + address + number (uint64 big endian) + log index (uint64 big endian) -> entry
+	sectionPrefix  = []byte("s") // sectionPrefix + section (uint64 big endian) -> keys of the section entries
+	progressKey    = []byte("p") // Number of sections indexed
+	entryKeyLength = len(entryPrefix) + common.AddressLength + 16
+)
+
+// Selectors of the calls a withdrawal is decoded through to find the addresses
+// of the end users.
+var (
+	relayMessageSelector        = crypto.Keccak256([]byte("relayMessage(uint256,address,address,uint256,uint256,bytes)"))[:4]
+	finalizeBridgeETHSelector   = crypto.Keccak256([]byte("finalizeBridgeETH(address,address,uint256,bytes)"))[:4]
+	finalizeBridgeERC20Selector = crypto.Keccak256([]byte("finalizeBridgeERC20(address,address,address,address,uint256,bytes)"))[:4]
+)
+
+func init() {
+	core.RegisterIndexer(core.IndexerModule{
+		Name:        Name,
+		SectionSize: sectionSize,
+		Confirms:    confirms,
+		New: func(chainDb ethdb.Database, indexDb ethdb.Database) (core.ChainIndexerBackend, error) {
+			return &indexer{chainDb: chainDb, indexDb: indexDb}, nil
+		},
+	})
+}
+
+// entry is the storage encoding of an indexed withdrawal, the block number and
+// log index being part of its key.
+type entry struct {
+	BlockHash common.Hash
+	StateRoot common.Hash
+	TxHash    common.Hash
+	TxIndex   uint64
+	Log       *types.Log // Address, topics and data of the MessagePassed event
+}
+
+// withdrawal is a withdrawal located in the chain.
+type withdrawal struct {
+	entry
+	Number   uint64
+	LogIndex uint64
+}
+
+// indexer is the chain indexer backend of the withdrawal index.
+type indexer struct {
+	chainDb ethdb.Database
+	indexDb ethdb.Database
+
+	section uint64
+	batch   ethdb.Batch
+	keys    [][]byte // Keys of the entries written in the current section
+}
+
+// Reset implements core.ChainIndexerBackend, dropping the entries of the section
+// if it is reprocessed after a reorg.
+func (idx *indexer) Reset(ctx context.Context, section uint64, prevHead common.Hash) error {
+	idx.section, idx.batch, idx.keys = section, idx.indexDb.NewBatch(), nil
+
+	if blob, _ := idx.indexDb.Get(sectionKey(section)); len(blob) > 0 {
+		var stale [][]byte
+		if err := rlp.DecodeBytes(blob, &stale); err != nil {
+			return err
+		}
+		for _, key := range stale {
+			idx.batch.Delete(key)
+		}
+	}
+	return idx.indexDb.Put(progressKey, encodeNumber(section))
+}
+
+// Process implements core.ChainIndexerBackend, indexing the withdrawals of the
+// given block.
+func (idx *indexer) Process(ctx context.Context, header *types.Header) error {
+	withdrawals, err := scanBlock(idx.chainDb, header)
+	if err != nil {
+		return err
+	}
+	for _, w := range withdrawals {
+		blob, err := rlp.EncodeToBytes(&w.entry)
+		if err != nil {
+			return err
+		}
+		for _, addr := range w.addresses() {
+			key := entryKey(addr, w.Number, w.LogIndex)
+			if err := idx.batch.Put(key, blob); err != nil {
+				return err
+			}
+			idx.keys = append(idx.keys, key)
+		}
+	}
+	return nil
+}
+
+// Commit implements core.ChainIndexerBackend, writing the entries of the section.
+func (idx *indexer) Commit() error {
+	blob, err := rlp.EncodeToBytes(idx.keys)
+	if err != nil {
+		return err
+	}
+	if err := idx.batch.Put(sectionKey(idx.section), blob); err != nil {
+		return err
+	}
+	if err := idx.batch.Put(progressKey, encodeNumber(idx.section+1)); err != nil {
+		return err
+	}
+	return idx.batch.Write()
+}
+
+// Prune implements core.ChainIndexerBackend. The index is never pruned.
+func (idx *indexer) Prune(threshold uint64) error {
+	return nil
+}
+
+// APIs returns the RPC APIs served by the index.
+func (idx *indexer) APIs() []rpc.API {
+	return []rpc.API{{
+		Namespace: "oasys",
+		Service:   &API{idx},
+	}}
+}
+
+// indexed returns the number of the first block not indexed yet.
+func (idx *indexer) indexed() uint64 {
+	blob, _ := idx.indexDb.Get(progressKey)
+	if len(blob) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(blob) * sectionSize
+}
+
+// scanBlock collects the withdrawals initiated in the given block.
+func scanBlock(db ethdb.Reader, header *types.Header) ([]*withdrawal, error) {
+	var (
+		hash     = header.Hash()
+		number   = header.Number.Uint64()
+		body     = rawdb.ReadBody(db, hash, number)
+		receipts = rawdb.ReadRawReceipts(db, hash, number)
+	)
+	if body == nil {
+		return nil, fmt.Errorf("block #%d body not found", number)
+	}
+	if len(receipts) != len(body.Transactions) {
+		return nil, fmt.Errorf("block #%d receipts not found", number)
+	}
+	var (
+		withdrawals []*withdrawal
+		logIndex    uint64
+	)
+	for i, receipt := range receipts {
+		for _, l := range receipt.Logs {
+			if l.Address == params.OptimismL2ToL1MessagePasser && len(l.Topics) == 4 && l.Topics[0] == ethapi.MessagePassedTopic {
+				withdrawals = append(withdrawals, &withdrawal{
+					entry: entry{
+						BlockHash: hash,
+						StateRoot: header.Root,
+						TxHash:    body.Transactions[i].Hash(),
+						TxIndex:   uint64(i),
+						Log:       l,
+					},
+					Number:   number,
+					LogIndex: logIndex,
+				})
+			}
+			logIndex++
+		}
+	}
+	return withdrawals, nil
+}
+
+// addresses returns the distinct addresses involved in the withdrawal: its sender
+// and target, the sender and target of the message it relays if any, and the
+// origin and recipient of the bridge transfer the message finalizes if any.
+func (w *withdrawal) addresses() []common.Address {
+	addrs := []common.Address{
+		common.BytesToAddress(w.Log.Topics[2].Bytes()),
+		common.BytesToAddress(w.Log.Topics[3].Bytes()),
+	}
+	if tx, err := ethapi.ParseMessagePassed(w.Log); err == nil {
+		if args, message := decodeCall(tx.Data, relayMessageSelector, 6, 5); args != nil {
+			addrs = append(addrs, common.BytesToAddress(args[1]), common.BytesToAddress(args[2]))
+			if args, _ := decodeCall(message, finalizeBridgeETHSelector, 4, 3); args != nil {
+				addrs = append(addrs, common.BytesToAddress(args[0]), common.BytesToAddress(args[1]))
+			} else if args, _ := decodeCall(message, finalizeBridgeERC20Selector, 6, 5); args != nil {
+				addrs = append(addrs, common.BytesToAddress(args[2]), common.BytesToAddress(args[3]))
+			}
+		}
+	}
+	distinct := addrs[:0]
+	for _, addr := range addrs {
+		seen := false
+		for _, have := range distinct {
+			seen = seen || have == addr
+		}
+		if !seen {
+			distinct = append(distinct, addr)
+		}
+	}
+	return distinct
+}
+
+// decodeCall splits the calldata of a call with the given selector into its n
+// head words, also decoding the bytes argument at the given position. Nil is
+// returned if the calldata does not match.
+func decodeCall(data []byte, selector []byte, n int, bytesArg int) ([][]byte, []byte) {
+	if len(data) < 4+32*n || !bytes.Equal(data[:4], selector) {
+		return nil, nil
+	}
+	data = data[4:]
+	args := make([][]byte, n)
+	for i := range args {
+		args[i] = data[32*i : 32*(i+1)]
+	}
+	offset := new(big.Int).SetBytes(args[bytesArg])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(data))-32 {
+		return nil, nil
+	}
+	start := offset.Uint64() + 32
+	size := new(big.Int).SetBytes(data[start-32 : start])
+	if !size.IsUint64() || size.Uint64() > uint64(len(data))-start {
+		return nil, nil
+	}
+	return args, data[start : start+size.Uint64()]
+}
+
+func encodeNumber(number uint64) []byte {
+	var enc [8]byte
+	binary.BigEndian.PutUint64(enc[:], number)
+	return enc[:]
+}
+
+func entryKey(addr common.Address, number uint64, logIndex uint64) []byte {
+	key := make([]byte, 0, entryKeyLength)
+	key = append(key, entryPrefix...)
+	key = append(key, addr.Bytes()...)
+	key = append(key, encodeNumber(number)...)
+	return append(key, encodeNumber(logIndex)...)
+}
+
+func sectionKey(section uint64) []byte {
+	return append(append([]byte{}, sectionPrefix...), encodeNumber(section)...)
+}
@@ -0,0 +1,175 @@
+package withdrawalindex
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	senderA = common.Address{0xa}
+	senderB = common.Address{0xb}
+	target  = common.Address{0x7}
+	user    = common.Address{0x5}
+)
+
+// word left pads the given value to an ABI word.
+func word(b []byte) []byte {
+	return common.LeftPadBytes(b, 32)
+}
+
+// relayedBridgeETH returns the calldata of a message relaying the finalization
+// of an ETH bridge transfer from the given address.
+func relayedBridgeETH(from common.Address) []byte {
+	var finalize []byte
+	finalize = append(finalize, finalizeBridgeETHSelector...)
+	finalize = append(finalize, word(from.Bytes())...)
+	finalize = append(finalize, word(from.Bytes())...)
+	finalize = append(finalize, word([]byte{1})...)
+	finalize = append(finalize, word([]byte{4 * 32})...)
+	finalize = append(finalize, word(nil)...)
+
+	var relay []byte
+	relay = append(relay, relayMessageSelector...)
+	relay = append(relay, word([]byte{1})...)
+	relay = append(relay, word(common.Address{0xb1}.Bytes())...)
+	relay = append(relay, word(common.Address{0xb2}.Bytes())...)
+	relay = append(relay, word([]byte{1})...)
+	relay = append(relay, word([]byte{100})...)
+	relay = append(relay, word([]byte{6 * 32})...)
+	relay = append(relay, word(big.NewInt(int64(len(finalize))).Bytes())...)
+	return append(relay, common.RightPadBytes(finalize, (len(finalize)+31)/32*32)...)
+}
+
+func messagePassedLog(nonce uint64, sender common.Address, payload []byte) *types.Log {
+	withdrawal := &ethapi.WithdrawalTransaction{
+		Nonce:    (*hexutil.Big)(new(big.Int).SetUint64(nonce)),
+		Sender:   sender,
+		Target:   target,
+		Value:    (*hexutil.Big)(big.NewInt(1)),
+		GasLimit: (*hexutil.Big)(big.NewInt(100000)),
+		Data:     payload,
+	}
+	data := make([]byte, 0, 7*32)
+	data = append(data, common.BigToHash(withdrawal.Value.ToInt()).Bytes()...)
+	data = append(data, common.BigToHash(withdrawal.GasLimit.ToInt()).Bytes()...)
+	data = append(data, common.BigToHash(big.NewInt(4*32)).Bytes()...)
+	data = append(data, withdrawal.Hash().Bytes()...)
+	data = append(data, common.BigToHash(big.NewInt(int64(len(withdrawal.Data)))).Bytes()...)
+	data = append(data, common.RightPadBytes(withdrawal.Data, (len(withdrawal.Data)+31)/32*32)...)
+	return &types.Log{
+		Address: params.OptimismL2ToL1MessagePasser,
+		Topics: []common.Hash{
+			ethapi.MessagePassedTopic,
+			common.BigToHash(withdrawal.Nonce.ToInt()),
+			common.BytesToHash(withdrawal.Sender.Bytes()),
+			common.BytesToHash(withdrawal.Target.Bytes()),
+		},
+		Data: data,
+	}
+}
+
+// newTestChain writes a chain of n blocks with one transaction each, every tenth
+// one initiating a withdrawal, alternately sent by senderA and senderB. Blocks 5
+// and 135 also bridge ETH from the user through the messenger.
+func newTestChain(n int) (ethdb.Database, []*types.Header) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		headers []*types.Header
+		parent  common.Hash
+	)
+	for i := 0; i < n; i++ {
+		tx := types.NewTransaction(uint64(i), common.Address{}, big.NewInt(1), 100000, big.NewInt(1), nil)
+		receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 50000, Logs: []*types.Log{}}
+		if i%10 == 0 {
+			sender := senderA
+			if (i/10)%2 == 1 {
+				sender = senderB
+			}
+			receipt.Logs = append(receipt.Logs, &types.Log{Address: common.Address{0x1}}, messagePassedLog(uint64(i), sender, []byte{0xca, 0xfe}))
+		}
+		if i == 5 || i == 135 {
+			receipt.Logs = append(receipt.Logs, &types.Log{Address: common.Address{0x1}}, messagePassedLog(uint64(i), common.Address{0xe}, relayedBridgeETH(user)))
+		}
+		header := &types.Header{ParentHash: parent, Number: big.NewInt(int64(i)), Difficulty: new(big.Int), Root: common.Hash{byte(i)}}
+		block := types.NewBlockWithHeader(header).WithBody([]*types.Transaction{tx}, nil)
+
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), types.Receipts{receipt})
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+
+		headers = append(headers, block.Header())
+		parent = block.Hash()
+	}
+	return db, headers
+}
+
+func indexSection(t *testing.T, idx *indexer, headers []*types.Header, section uint64) {
+	if err := idx.Reset(context.Background(), section, common.Hash{}); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+	for _, header := range headers[section*sectionSize : (section+1)*sectionSize] {
+		if err := idx.Process(context.Background(), header); err != nil {
+			t.Fatalf("processing failed: %v", err)
+		}
+	}
+	if err := idx.Commit(); err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+}
+
+func TestWithdrawalIndex(t *testing.T) {
+	db, headers := newTestChain(150)
+	idx := &indexer{chainDb: db, indexDb: rawdb.NewTable(db, "idx-")}
+	api := &API{idx}
+
+	// Index the first section, the remaining blocks being scanned on query
+	indexSection(t, idx, headers, 0)
+	if indexed := idx.indexed(); indexed != sectionSize {
+		t.Fatalf("indexed blocks mismatch: have %d, want %d", indexed, sectionSize)
+	}
+	check := func(addr common.Address, from, to rpc.BlockNumber, want []uint64) {
+		t.Helper()
+		withdrawals, err := api.GetWithdrawals(context.Background(), addr, from, to)
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		if len(withdrawals) != len(want) {
+			t.Fatalf("withdrawal count mismatch: have %d, want %d", len(withdrawals), len(want))
+		}
+		for i, w := range withdrawals {
+			if uint64(w.BlockNumber) != want[i] || w.Withdrawal.Nonce.ToInt().Uint64() != want[i] {
+				t.Fatalf("withdrawal %d mismatch: have block #%d, want #%d", i, w.BlockNumber, want[i])
+			}
+			if w.LogIndex != 1 || w.StateRoot != headers[want[i]].Root || w.WithdrawalHash != w.Withdrawal.Hash() {
+				t.Fatalf("withdrawal %d context mismatch: %+v", i, w)
+			}
+		}
+	}
+	check(senderA, 0, rpc.LatestBlockNumber, []uint64{0, 20, 40, 60, 80, 100, 120, 140})
+	check(senderA, 30, 140, []uint64{40, 60, 80, 100, 120, 140})
+	check(senderB, 0, 127, []uint64{10, 30, 50, 70, 90, 110})
+	check(target, 100, rpc.LatestBlockNumber, []uint64{100, 110, 120, 130, 135, 140})
+	check(common.Address{0x1}, 0, rpc.LatestBlockNumber, nil)
+	check(user, 0, rpc.LatestBlockNumber, []uint64{5, 135})
+	check(common.Address{0xb1}, 0, 100, []uint64{5})
+
+	// Reprocessing a section drops its stale entries
+	if err := idx.Reset(context.Background(), 0, common.Hash{}); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+	if err := idx.Commit(); err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+	check(senderA, 0, 127, nil)
+}
@@ -0,0 +1,125 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// witnessReexec is the number of blocks re-executed at most to regenerate the
+// parent state of a block whose witness is requested.
+const witnessReexec = uint64(128)
+
+// ExecutionWitness is the data needed to execute a block statelessly on top of
+// its parent state: the headers from the parent back to the oldest ancestor
+// whose hash is accessed, the codes of the contracts accessed, and the trie
+// nodes proving the state accessed and modified.
+type ExecutionWitness struct {
+	Headers []*types.Header `json:"headers"`
+	Codes   []hexutil.Bytes `json:"codes"`
+	State   []hexutil.Bytes `json:"state"`
+}
+
+// executionWitness regenerates the witness of the given block by re-executing
+// it on top of its parent state, reading all the state through the tries.
+func (eth *Ethereum) executionWitness(ctx context.Context, block *types.Block) (*ExecutionWitness, error) {
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not executed")
+	}
+	parent := eth.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent %#x not found", block.ParentHash())
+	}
+	statedb, release, err := eth.stateAtBlock(ctx, parent, witnessReexec, nil, true, false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	statedb.RecordWitness()
+	tracer := &blockHashTracer{number: block.NumberU64(), oldest: parent.NumberU64()}
+	if _, _, _, err := eth.blockchain.Processor().Process(block, statedb, vm.Config{Tracer: tracer}); err != nil {
+		return nil, err
+	}
+	if root := statedb.IntermediateRoot(eth.blockchain.Config().IsEIP158(block.Number())); root != block.Root() {
+		return nil, fmt.Errorf("state root mismatch: have %x, want %x", root, block.Root())
+	}
+	nodes, codes, err := statedb.Witness()
+	if err != nil {
+		return nil, err
+	}
+	witness := &ExecutionWitness{
+		Headers: []*types.Header{parent.Header()},
+		Codes:   sortedBlobs(codes),
+		State:   sortedBlobs(nodes),
+	}
+	for header := parent.Header(); header.Number.Uint64() > tracer.oldest; {
+		header = eth.blockchain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+		if header == nil {
+			return nil, fmt.Errorf("ancestor #%d of block #%d not found", tracer.oldest, block.NumberU64())
+		}
+		witness.Headers = append(witness.Headers, header)
+	}
+	return witness, nil
+}
+
+// sortedBlobs returns the given set of blobs in lexicographic order.
+func sortedBlobs(set map[string]struct{}) []hexutil.Bytes {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	blobs := make([]hexutil.Bytes, len(keys))
+	for i, key := range keys {
+		blobs[i] = hexutil.Bytes(key)
+	}
+	return blobs
+}
+
+// blockHashTracer tracks the oldest ancestor whose hash is accessed by the
+// BLOCKHASH opcodes executed in a block.
+type blockHashTracer struct {
+	number uint64 // Number of the block executed
+	oldest uint64 // Oldest ancestor accessed, the parent if none
+}
+
+func (t *blockHashTracer) CaptureTxStart(gasLimit uint64) {}
+
+func (t *blockHashTracer) CaptureTxEnd(restGas uint64) {}
+
+func (t *blockHashTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+}
+
+func (t *blockHashTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {}
+
+func (t *blockHashTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+}
+
+func (t *blockHashTracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+func (t *blockHashTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if op != vm.BLOCKHASH || err != nil || len(scope.Stack.Data()) == 0 {
+		return
+	}
+	num := scope.Stack.Back(0)
+	if !num.IsUint64() {
+		return
+	}
+	// Only the 256 most recent ancestors are served, zero hashes otherwise
+	n := num.Uint64()
+	if n < t.number && t.number-n <= 256 && n < t.oldest {
+		t.oldest = n
+	}
+}
+
+func (t *blockHashTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
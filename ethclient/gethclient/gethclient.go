@@ -260,21 +260,52 @@ type OverrideAccount struct {
 
 	// StateDiff allows overriding individual storage slots.
 	StateDiff map[common.Hash]common.Hash
+
+	// Delegation simulates an EIP-7702 delegation of the account to the given
+	// address, executing the delegate's code in the context of the account.
+	// It can't be combined with Code.
+	Delegation *common.Address
+
+	// Deposit executes the call as a deposit transaction. It is only allowed
+	// on the sender of the call.
+	Deposit *DepositOverride
+}
+
+// DepositOverride specifies the deposit transaction context of a call.
+type DepositOverride struct {
+	// Mint is the amount minted to the sender before execution.
+	Mint *big.Int
+
+	// IsSystemTx simulates a (pre-Regolith) system transaction.
+	IsSystemTx bool
 }
 
 func (a OverrideAccount) MarshalJSON() ([]byte, error) {
+	type deposit struct {
+		Mint       *hexutil.Big `json:"mint,omitempty"`
+		IsSystemTx bool         `json:"isSystemTx,omitempty"`
+	}
 	type acc struct {
-		Nonce     hexutil.Uint64              `json:"nonce,omitempty"`
-		Code      string                      `json:"code,omitempty"`
-		Balance   *hexutil.Big                `json:"balance,omitempty"`
-		State     interface{}                 `json:"state,omitempty"`
-		StateDiff map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+		Nonce      hexutil.Uint64              `json:"nonce,omitempty"`
+		Code       string                      `json:"code,omitempty"`
+		Balance    *hexutil.Big                `json:"balance,omitempty"`
+		State      interface{}                 `json:"state,omitempty"`
+		StateDiff  map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+		Delegation *common.Address             `json:"delegation,omitempty"`
+		Deposit    *deposit                    `json:"deposit,omitempty"`
 	}
 
 	output := acc{
-		Nonce:     hexutil.Uint64(a.Nonce),
-		Balance:   (*hexutil.Big)(a.Balance),
-		StateDiff: a.StateDiff,
+		Nonce:      hexutil.Uint64(a.Nonce),
+		Balance:    (*hexutil.Big)(a.Balance),
+		StateDiff:  a.StateDiff,
+		Delegation: a.Delegation,
+	}
+	if a.Deposit != nil {
+		output.Deposit = &deposit{
+			Mint:       (*hexutil.Big)(a.Deposit.Mint),
+			IsSystemTx: a.Deposit.IsSystemTx,
+		}
 	}
 	if a.Code != nil {
 		output.Code = hexutil.Encode(a.Code)
@@ -469,6 +469,10 @@ func TestOverrideAccountMarshal(t *testing.T) {
 			// is ignored because it makes no difference.
 			StateDiff: map[common.Hash]common.Hash{},
 		},
+		{0xdd}: {
+			Delegation: &common.Address{0xee},
+			Deposit:    &DepositOverride{Mint: big.NewInt(1)},
+		},
 	}
 
 	marshalled, err := json.MarshalIndent(&om, "", "  ")
@@ -488,6 +492,12 @@ func TestOverrideAccountMarshal(t *testing.T) {
     "code": "0x",
     "balance": "0x0",
     "state": {}
+  },
+  "0xdd00000000000000000000000000000000000000": {
+    "delegation": "0xee00000000000000000000000000000000000000",
+    "deposit": {
+      "mint": "0x1"
+    }
   }
 }`
 
@@ -116,6 +116,10 @@ type AncientWriter interface {
 	// After the truncation, the latest item can be accessed it item_n-1(start from 0).
 	TruncateHead(n uint64) (uint64, error)
 
+	// RewriteAncient overwrites an ancient item in place, repairing corrupted
+	// data. The encoded item must have the same size as the stored one.
+	RewriteAncient(kind string, number uint64, item []byte) error
+
 	// TruncateTail discards the first n ancient data from the ancient store. The already
 	// deleted items are ignored. After the truncation, the earliest item can be accessed
 	// is item_n(start from 0). The deleted items may not be removed from the ancient store
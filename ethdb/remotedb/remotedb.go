@@ -106,6 +106,10 @@ func (db *Database) TruncateTail(n uint64) (uint64, error) {
 	panic("not supported")
 }
 
+func (db *Database) RewriteAncient(kind string, number uint64, item []byte) error {
+	panic("not supported")
+}
+
 func (db *Database) Sync() error {
 	return nil
 }
@@ -65,6 +65,18 @@ func (*HandlerT) Vmodule(pattern string) error {
 	return glogger.Vmodule(pattern)
 }
 
+// SetModuleVerbosity sets the log verbosity of a subsystem (engine, txpool, miner,
+// downloader, p2p, rpc), overriding the global verbosity in both directions. A
+// negative level removes the override.
+func (*HandlerT) SetModuleVerbosity(module string, level int) error {
+	return modules.SetLevel(module, level)
+}
+
+// ModuleVerbosity returns the configured subsystem log verbosity levels.
+func (*HandlerT) ModuleVerbosity() map[string]int {
+	return modules.Levels()
+}
+
 // BacktraceAt sets the log backtrace location. See package log for details on
 // the pattern syntax.
 func (*HandlerT) BacktraceAt(location string) error {
@@ -52,6 +52,11 @@ var (
 		Value:    "",
 		Category: flags.LoggingCategory,
 	}
+	logModulesFlag = &cli.StringFlag{
+		Name:     "log.modules",
+		Usage:    "Per-subsystem verbosity overriding --verbosity in both directions: comma-separated list of <module>=<level> (modules: engine, txpool, miner, downloader, p2p, rpc)",
+		Category: flags.LoggingCategory,
+	}
 	vmoduleFlag = &cli.StringFlag{
 		Name:     "vmodule",
 		Usage:    "Per-module verbosity: comma-separated list of <pattern>=<level> (e.g. eth/*=5,p2p=4)",
@@ -159,6 +164,7 @@ var (
 var Flags = []cli.Flag{
 	verbosityFlag,
 	logVmoduleFlag,
+	logModulesFlag,
 	vmoduleFlag,
 	backtraceAtFlag,
 	debugFlag,
@@ -181,13 +187,16 @@ var Flags = []cli.Flag{
 
 var (
 	glogger         *log.GlogHandler
+	modules         *moduleHandler
 	logOutputStream log.Handler
 )
 
 func init() {
-	glogger = log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+	ostream := log.StreamHandler(os.Stderr, log.TerminalFormat(false))
+	glogger = log.NewGlogHandler(ostream)
 	glogger.Verbosity(log.LvlInfo)
-	log.Root().SetHandler(glogger)
+	modules = newModuleHandler(glogger, ostream)
+	log.Root().SetHandler(modules)
 }
 
 // Setup initializes profiling and logging based on the CLI flags.
@@ -258,6 +267,7 @@ func Setup(ctx *cli.Context) error {
 		context = append(context, "location", logFile)
 	}
 	glogger.SetHandler(ostream)
+	modules.SetOrigin(ostream)
 
 	// logging
 	verbosity := ctx.Int(verbosityFlag.Name)
@@ -272,6 +282,16 @@ func Setup(ctx *cli.Context) error {
 	}
 	glogger.Vmodule(vmodule)
 
+	levels, err := parseModuleLevels(ctx.String(logModulesFlag.Name))
+	if err != nil {
+		return err
+	}
+	for module, level := range levels {
+		if err := modules.SetLevel(module, level); err != nil {
+			return err
+		}
+	}
+
 	debug := ctx.Bool(debugFlag.Name)
 	if ctx.IsSet(debugFlag.Name) {
 		debug = ctx.Bool(debugFlag.Name)
@@ -281,7 +301,7 @@ func Setup(ctx *cli.Context) error {
 	backtrace := ctx.String(backtraceAtFlag.Name)
 	glogger.BacktraceAt(backtrace)
 
-	log.Root().SetHandler(glogger)
+	log.Root().SetHandler(modules)
 
 	// profiling, tracing
 	runtime.MemProfileRate = memprofilerateFlag.Value
@@ -0,0 +1,162 @@
+package debug
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// logModules maps the subsystem names accepted by --log.modules and
+// debug_setModuleVerbosity to the source trees they cover.
+var logModules = map[string][]string{
+	"engine":     {"eth/catalyst"},
+	"txpool":     {"core/txpool"},
+	"miner":      {"miner"},
+	"downloader": {"eth/downloader"},
+	"p2p":        {"p2p"},
+	"rpc":        {"rpc", "internal/ethapi"},
+}
+
+// moduleHandler applies per-subsystem verbosity levels in front of the glog
+// handler. Unlike vmodule patterns, which can only raise the verbosity above
+// the global level, module levels both raise and lower it.
+type moduleHandler struct {
+	next   log.Handler // Handler for records outside of any configured module
+	origin log.Handler // Output handler for records of configured modules
+
+	lock     sync.RWMutex
+	levels   map[string]log.Lvl
+	matchers map[string]*regexp.Regexp
+	cache    map[uintptr]string // Call site to module name, "" for none
+}
+
+func newModuleHandler(next, origin log.Handler) *moduleHandler {
+	h := &moduleHandler{
+		next:     next,
+		origin:   origin,
+		levels:   make(map[string]log.Lvl),
+		matchers: make(map[string]*regexp.Regexp),
+		cache:    make(map[uintptr]string),
+	}
+	for name, trees := range logModules {
+		var alts []string
+		for _, tree := range trees {
+			alts = append(alts, regexp.QuoteMeta(tree))
+		}
+		h.matchers[name] = regexp.MustCompile(`.*/(` + strings.Join(alts, "|") + `)(/.*)?/[^/]+\.go$`)
+	}
+	return h
+}
+
+// SetOrigin sets the output handler used for records of configured modules.
+func (h *moduleHandler) SetOrigin(origin log.Handler) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.origin = origin
+}
+
+// SetLevel sets the verbosity of a module, or removes its override if the level
+// is negative.
+func (h *moduleHandler) SetLevel(module string, level int) error {
+	if _, ok := logModules[module]; !ok {
+		return fmt.Errorf("unknown log module %q, want one of %s", module, strings.Join(moduleNames(), ", "))
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if level < 0 {
+		delete(h.levels, module)
+	} else {
+		h.levels[module] = log.Lvl(level)
+		log.EnableCallSites() // Records are routed by their call site
+	}
+	h.cache = make(map[uintptr]string)
+	return nil
+}
+
+// Levels returns the configured module verbosity levels.
+func (h *moduleHandler) Levels() map[string]int {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	levels := make(map[string]int, len(h.levels))
+	for module, level := range h.levels {
+		levels[module] = int(level)
+	}
+	return levels
+}
+
+// Log implements log.Handler.
+func (h *moduleHandler) Log(r *log.Record) error {
+	h.lock.RLock()
+	if len(h.levels) == 0 {
+		h.lock.RUnlock()
+		return h.next.Log(r)
+	}
+	module, cached := h.cache[r.Call.Frame().PC]
+	h.lock.RUnlock()
+
+	if !cached {
+		file := fmt.Sprintf("%+s", r.Call)
+
+		h.lock.Lock()
+		for name := range h.levels {
+			if h.matchers[name].MatchString(file) {
+				module = name
+				break
+			}
+		}
+		h.cache[r.Call.Frame().PC] = module
+		h.lock.Unlock()
+	}
+	if module == "" {
+		return h.next.Log(r)
+	}
+	h.lock.RLock()
+	level, ok := h.levels[module]
+	origin := h.origin
+	h.lock.RUnlock()
+
+	if !ok {
+		return h.next.Log(r)
+	}
+	if r.Lvl > level {
+		return nil
+	}
+	return origin.Log(r)
+}
+
+// parseModuleLevels parses a comma separated list of <module>=<level> rules.
+func parseModuleLevels(spec string) (map[string]int, error) {
+	levels := make(map[string]int)
+	for _, rule := range strings.Split(spec, ",") {
+		if rule = strings.TrimSpace(rule); rule == "" {
+			continue
+		}
+		module, lvl, ok := strings.Cut(rule, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid log module rule %q, want <module>=<level>", rule)
+		}
+		level, err := strconv.Atoi(strings.TrimSpace(lvl))
+		if err != nil {
+			return nil, fmt.Errorf("invalid log module level %q: %v", lvl, err)
+		}
+		levels[strings.TrimSpace(module)] = level
+	}
+	return levels, nil
+}
+
+func moduleNames() []string {
+	names := make([]string, 0, len(logModules))
+	for name := range logModules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,202 @@
+package debug
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// testRecorder collects the messages of the records it handles.
+type testRecorder struct {
+	msgs []string
+}
+
+func (r *testRecorder) handler() log.Handler {
+	return log.FuncHandler(func(rec *log.Record) error {
+		r.msgs = append(r.msgs, rec.Msg)
+		return nil
+	})
+}
+
+func (r *testRecorder) take() []string {
+	msgs := r.msgs
+	r.msgs = nil
+	return msgs
+}
+
+// newTestModuleHandler creates a module handler in front of a glog handler at
+// info level, with this package registered as the "debug" module so that the
+// records logged by the tests are routed by their call site.
+func newTestModuleHandler(t *testing.T) (*moduleHandler, log.Logger, *testRecorder, *testRecorder) {
+	logModules["debug"] = []string{"internal/debug"}
+	t.Cleanup(func() { delete(logModules, "debug") })
+
+	var glogged, moduled testRecorder
+	glog := log.NewGlogHandler(glogged.handler())
+	glog.Verbosity(log.LvlInfo)
+
+	h := newModuleHandler(glog, moduled.handler())
+	logger := log.New()
+	logger.SetHandler(h)
+	return h, logger, &glogged, &moduled
+}
+
+// logAll logs a record at every level, each from the same call site across
+// invocations.
+func logAll(logger log.Logger) {
+	logger.Error(log.LvlError.String())
+	logger.Warn(log.LvlWarn.String())
+	logger.Info(log.LvlInfo.String())
+	logger.Debug(log.LvlDebug.String())
+	logger.Trace(log.LvlTrace.String())
+}
+
+func TestModuleMatchers(t *testing.T) {
+	h := newModuleHandler(log.DiscardHandler(), log.DiscardHandler())
+
+	tests := []struct {
+		file   string
+		module string
+	}{
+		{"github.com/ethereum/go-ethereum/eth/catalyst/api.go", "engine"},
+		{"github.com/ethereum/go-ethereum/core/txpool/legacypool/legacypool.go", "txpool"},
+		{"github.com/ethereum/go-ethereum/miner/worker.go", "miner"},
+		{"github.com/ethereum/go-ethereum/eth/downloader/downloader.go", "downloader"},
+		{"github.com/ethereum/go-ethereum/p2p/discover/table.go", "p2p"},
+		{"github.com/ethereum/go-ethereum/rpc/handler.go", "rpc"},
+		{"github.com/ethereum/go-ethereum/internal/ethapi/api.go", "rpc"},
+		{"github.com/ethereum/go-ethereum/eth/catalystx/api.go", ""},
+		{"github.com/ethereum/go-ethereum/core/blockchain.go", ""},
+		{"github.com/ethereum/go-ethereum/cmd/geth/miner.go", ""},
+	}
+	for _, test := range tests {
+		var module string
+		for name, matcher := range h.matchers {
+			if matcher.MatchString(test.file) {
+				if module != "" {
+					t.Errorf("%s: matched by both %s and %s", test.file, module, name)
+				}
+				module = name
+			}
+		}
+		if module != test.module {
+			t.Errorf("%s: matched module %q, want %q", test.file, module, test.module)
+		}
+	}
+}
+
+func TestModuleHandlerLevels(t *testing.T) {
+	h, logger, glogged, moduled := newTestModuleHandler(t)
+
+	// Without module levels, everything goes through glog
+	logAll(logger)
+	if have, want := glogged.take(), []string{"eror", "warn", "info"}; !equalMsgs(have, want) {
+		t.Errorf("glog records: have %v, want %v", have, want)
+	}
+	// Raise the verbosity of the module above the global level
+	if err := h.SetLevel("debug", int(log.LvlTrace)); err != nil {
+		t.Fatal(err)
+	}
+	logAll(logger)
+	if have, want := moduled.take(), []string{"eror", "warn", "info", "dbug", "trce"}; !equalMsgs(have, want) {
+		t.Errorf("raised module records: have %v, want %v", have, want)
+	}
+	// Lower it below the global level
+	if err := h.SetLevel("debug", int(log.LvlError)); err != nil {
+		t.Fatal(err)
+	}
+	logAll(logger)
+	if have, want := moduled.take(), []string{"eror"}; !equalMsgs(have, want) {
+		t.Errorf("lowered module records: have %v, want %v", have, want)
+	}
+	if have := glogged.take(); len(have) != 0 {
+		t.Errorf("module records leaked to glog: %v", have)
+	}
+	// Removing the override hands the records back to glog
+	if err := h.SetLevel("debug", -1); err != nil {
+		t.Fatal(err)
+	}
+	logAll(logger)
+	if have, want := glogged.take(), []string{"eror", "warn", "info"}; !equalMsgs(have, want) {
+		t.Errorf("glog records after reset: have %v, want %v", have, want)
+	}
+	if have := moduled.take(); len(have) != 0 {
+		t.Errorf("records routed to the removed module: %v", have)
+	}
+	if err := h.SetLevel("unknown", 3); err == nil {
+		t.Errorf("unknown module accepted")
+	}
+}
+
+func TestModuleHandlerCacheReset(t *testing.T) {
+	h, logger, glogged, moduled := newTestModuleHandler(t)
+
+	// Cache the call site as outside of any configured module
+	if err := h.SetLevel("miner", int(log.LvlTrace)); err != nil {
+		t.Fatal(err)
+	}
+	logAll(logger)
+	if have, want := glogged.take(), []string{"eror", "warn", "info"}; !equalMsgs(have, want) {
+		t.Errorf("glog records: have %v, want %v", have, want)
+	}
+	// Configuring the module of the call site must invalidate the cache
+	if err := h.SetLevel("debug", int(log.LvlDebug)); err != nil {
+		t.Fatal(err)
+	}
+	logAll(logger)
+	if have, want := moduled.take(), []string{"eror", "warn", "info", "dbug"}; !equalMsgs(have, want) {
+		t.Errorf("module records: have %v, want %v", have, want)
+	}
+	if levels := h.Levels(); len(levels) != 2 || levels["miner"] != int(log.LvlTrace) || levels["debug"] != int(log.LvlDebug) {
+		t.Errorf("unexpected levels: %v", levels)
+	}
+}
+
+func TestParseModuleLevels(t *testing.T) {
+	tests := []struct {
+		spec   string
+		levels map[string]int
+		fail   bool
+	}{
+		{spec: "", levels: map[string]int{}},
+		{spec: "engine=5", levels: map[string]int{"engine": 5}},
+		{spec: " txpool = 1 , rpc=4,", levels: map[string]int{"txpool": 1, "rpc": 4}},
+		{spec: "engine", fail: true},
+		{spec: "engine=debug", fail: true},
+		{spec: "engine=5,miner", fail: true},
+	}
+	for _, test := range tests {
+		levels, err := parseModuleLevels(test.spec)
+		if test.fail {
+			if err == nil {
+				t.Errorf("%q: expected error", test.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.spec, err)
+			continue
+		}
+		if len(levels) != len(test.levels) {
+			t.Errorf("%q: have %v, want %v", test.spec, levels, test.levels)
+			continue
+		}
+		for module, level := range test.levels {
+			if levels[module] != level {
+				t.Errorf("%q: have %v, want %v", test.spec, levels, test.levels)
+			}
+		}
+	}
+}
+
+func equalMsgs(have, want []string) bool {
+	if len(have) != len(want) {
+		return false
+	}
+	for i := range have {
+		if have[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
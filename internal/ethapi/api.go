@@ -162,8 +162,9 @@ func NewTxPoolAPI(b Backend) *TxPoolAPI {
 	return &TxPoolAPI{b}
 }
 
-// Content returns the transactions contained within the transaction pool.
-func (s *TxPoolAPI) Content() map[string]map[string]map[string]*RPCTransaction {
+// Content returns the transactions contained within the transaction pool, selected
+// by the optional filter.
+func (s *TxPoolAPI) Content(filter *TxPoolFilter) map[string]map[string]map[string]*RPCTransaction {
 	content := map[string]map[string]map[string]*RPCTransaction{
 		"pending": make(map[string]map[string]*RPCTransaction),
 		"queued":  make(map[string]map[string]*RPCTransaction),
@@ -174,17 +175,25 @@ func (s *TxPoolAPI) Content() map[string]map[string]map[string]*RPCTransaction {
 	for account, txs := range pending {
 		dump := make(map[string]*RPCTransaction)
 		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = NewRPCPendingTransaction(tx, curHeader, s.b.ChainConfig())
+			if filter.matches(account, tx) {
+				dump[fmt.Sprintf("%d", tx.Nonce())] = NewRPCPendingTransaction(tx, curHeader, s.b.ChainConfig())
+			}
+		}
+		if len(dump) > 0 {
+			content["pending"][account.Hex()] = dump
 		}
-		content["pending"][account.Hex()] = dump
 	}
 	// Flatten the queued transactions
 	for account, txs := range queue {
 		dump := make(map[string]*RPCTransaction)
 		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = NewRPCPendingTransaction(tx, curHeader, s.b.ChainConfig())
+			if filter.matches(account, tx) {
+				dump[fmt.Sprintf("%d", tx.Nonce())] = NewRPCPendingTransaction(tx, curHeader, s.b.ChainConfig())
+			}
+		}
+		if len(dump) > 0 {
+			content["queued"][account.Hex()] = dump
 		}
-		content["queued"][account.Hex()] = dump
 	}
 	return content
 }
@@ -221,9 +230,9 @@ func (s *TxPoolAPI) Status() map[string]hexutil.Uint {
 	}
 }
 
-// Inspect retrieves the content of the transaction pool and flattens it into an
-// easily inspectable list.
-func (s *TxPoolAPI) Inspect() map[string]map[string]map[string]string {
+// Inspect retrieves the content of the transaction pool, selected by the optional
+// filter, and flattens it into an easily inspectable list.
+func (s *TxPoolAPI) Inspect(filter *TxPoolFilter) map[string]map[string]map[string]string {
 	content := map[string]map[string]map[string]string{
 		"pending": make(map[string]map[string]string),
 		"queued":  make(map[string]map[string]string),
@@ -241,17 +250,25 @@ func (s *TxPoolAPI) Inspect() map[string]map[string]map[string]string {
 	for account, txs := range pending {
 		dump := make(map[string]string)
 		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = format(tx)
+			if filter.matches(account, tx) {
+				dump[fmt.Sprintf("%d", tx.Nonce())] = format(tx)
+			}
+		}
+		if len(dump) > 0 {
+			content["pending"][account.Hex()] = dump
 		}
-		content["pending"][account.Hex()] = dump
 	}
 	// Flatten the queued transactions
 	for account, txs := range queue {
 		dump := make(map[string]string)
 		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = format(tx)
+			if filter.matches(account, tx) {
+				dump[fmt.Sprintf("%d", tx.Nonce())] = format(tx)
+			}
+		}
+		if len(dump) > 0 {
+			content["queued"][account.Hex()] = dump
 		}
-		content["queued"][account.Hex()] = dump
 	}
 	return content
 }
@@ -624,10 +641,14 @@ func (api *BlockChainAPI) ChainId() *hexutil.Big {
 	return (*hexutil.Big)(api.b.ChainConfig().ChainID)
 }
 
-// BlockNumber returns the block number of the chain head.
-func (s *BlockChainAPI) BlockNumber() hexutil.Uint64 {
-	header, _ := s.b.HeaderByNumber(context.Background(), rpc.LatestBlockNumber) // latest header should always be available
-	return hexutil.Uint64(header.Number.Uint64())
+// BlockNumber returns the block number of the chain head, which may be mapped to
+// the safe or finalized head for the request.
+func (s *BlockChainAPI) BlockNumber(ctx context.Context) (hexutil.Uint64, error) {
+	header, err := s.b.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(header.Number.Uint64()), nil
 }
 
 // GetBalance returns the amount of wei for the given address in the state of the
@@ -642,7 +663,7 @@ func (s *BlockChainAPI) GetBalance(ctx context.Context, address common.Address,
 	if s.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
 		if s.b.HistoricalRPCService() != nil {
 			var res hexutil.Big
-			err := s.b.HistoricalRPCService().CallContext(ctx, &res, "eth_getBalance", address, blockNrOrHash)
+			err := CallHistorical(ctx, s.b.HistoricalRPCService(), &res, "eth_getBalance", address, blockNrOrHash)
 			if err != nil {
 				return nil, fmt.Errorf("historical backend error: %w", err)
 			}
@@ -698,7 +719,7 @@ func (s *BlockChainAPI) GetProof(ctx context.Context, address common.Address, st
 	if s.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
 		if s.b.HistoricalRPCService() != nil {
 			var res AccountResult
-			err := s.b.HistoricalRPCService().CallContext(ctx, &res, "eth_getProof", address, storageKeys, blockNrOrHash)
+			err := CallHistorical(ctx, s.b.HistoricalRPCService(), &res, "eth_getProof", address, storageKeys, blockNrOrHash)
 			if err != nil {
 				return nil, fmt.Errorf("historical backend error: %w", err)
 			}
@@ -919,7 +940,7 @@ func (s *BlockChainAPI) GetCode(ctx context.Context, address common.Address, blo
 	if s.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
 		if s.b.HistoricalRPCService() != nil {
 			var res hexutil.Bytes
-			err := s.b.HistoricalRPCService().CallContext(ctx, &res, "eth_getCode", address, blockNrOrHash)
+			err := CallHistorical(ctx, s.b.HistoricalRPCService(), &res, "eth_getCode", address, blockNrOrHash)
 			if err != nil {
 				return nil, fmt.Errorf("historical backend error: %w", err)
 			}
@@ -950,7 +971,7 @@ func (s *BlockChainAPI) GetStorageAt(ctx context.Context, address common.Address
 	if s.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
 		if s.b.HistoricalRPCService() != nil {
 			var res hexutil.Bytes
-			err := s.b.HistoricalRPCService().CallContext(ctx, &res, "eth_getStorageAt", address, hexKey, blockNrOrHash)
+			err := CallHistorical(ctx, s.b.HistoricalRPCService(), &res, "eth_getStorageAt", address, hexKey, blockNrOrHash)
 			if err != nil {
 				return nil, fmt.Errorf("historical backend error: %w", err)
 			}
@@ -1004,10 +1025,11 @@ func (s *BlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rpc.
 
 	// Derive the sender.
 	signer := types.MakeSigner(s.b.ChainConfig(), block.Number(), block.Time())
+	fee := operatorFeeAt(ctx, s.b, block.Header())
 
 	result := make([]map[string]interface{}, len(receipts))
 	for i, receipt := range receipts {
-		result[i] = marshalReceipt(receipt, block.Hash(), block.NumberU64(), signer, txs[i], i, s.b.ChainConfig())
+		result[i] = marshalReceipt(receipt, block.Hash(), block.NumberU64(), signer, txs[i], i, s.b.ChainConfig(), fee)
 	}
 
 	return result, nil
@@ -1019,12 +1041,25 @@ func (s *BlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rpc.
 // set, message execution will only use the data in the given state. Otherwise
 // if statDiff is set, all diff will be applied first and then execute the call
 // message.
+//
+// Delegation simulates an EIP-7702 delegation of the account to the given
+// address, by executing the code of the delegate in the context of the account.
+// It can't be combined with code. Deposit executes the call as a deposit
+// transaction and is only allowed on the sender of the call.
 type OverrideAccount struct {
-	Nonce     *hexutil.Uint64              `json:"nonce"`
-	Code      *hexutil.Bytes               `json:"code"`
-	Balance   **hexutil.Big                `json:"balance"`
-	State     *map[common.Hash]common.Hash `json:"state"`
-	StateDiff *map[common.Hash]common.Hash `json:"stateDiff"`
+	Nonce      *hexutil.Uint64              `json:"nonce"`
+	Code       *hexutil.Bytes               `json:"code"`
+	Balance    **hexutil.Big                `json:"balance"`
+	State      *map[common.Hash]common.Hash `json:"state"`
+	StateDiff  *map[common.Hash]common.Hash `json:"stateDiff"`
+	Delegation *common.Address              `json:"delegation"`
+	Deposit    *DepositOverride             `json:"deposit"`
+}
+
+// DepositOverride is the deposit transaction context of a simulated call.
+type DepositOverride struct {
+	Mint       *hexutil.Big `json:"mint"`       // Amount minted to the sender before execution
+	IsSystemTx bool         `json:"isSystemTx"` // Whether to simulate a (pre-Regolith) system transaction
 }
 
 // StateOverride is the collection of overridden accounts.
@@ -1036,6 +1071,9 @@ func (diff *StateOverride) Apply(state *state.StateDB) error {
 		return nil
 	}
 	for addr, account := range *diff {
+		if account.Code != nil && account.Delegation != nil {
+			return fmt.Errorf("account %s has both 'code' and 'delegation'", addr.Hex())
+		}
 		// Override account nonce.
 		if account.Nonce != nil {
 			state.SetNonce(addr, uint64(*account.Nonce))
@@ -1062,6 +1100,13 @@ func (diff *StateOverride) Apply(state *state.StateDB) error {
 			}
 		}
 	}
+	// Resolve delegations once all codes are overridden, so accounts may delegate
+	// to overridden code.
+	for addr, account := range *diff {
+		if account.Delegation != nil {
+			state.SetCode(addr, state.GetCode(*account.Delegation))
+		}
+	}
 	// Now finalize the changes. Finalize is normally performed between transactions.
 	// By using finalize, the overrides are semantically behaving as
 	// if they were created in a transaction just before the tracing occur.
@@ -1069,6 +1114,25 @@ func (diff *StateOverride) Apply(state *state.StateDB) error {
 	return nil
 }
 
+// applyDeposit sets the deposit context of a call from the override of its
+// sender, if any.
+func (diff *StateOverride) applyDeposit(args *TransactionArgs) error {
+	if diff == nil {
+		return nil
+	}
+	from := args.from()
+	for addr, account := range *diff {
+		if account.Deposit == nil {
+			continue
+		}
+		if addr != from {
+			return fmt.Errorf("account %s has 'deposit' but is not the sender", addr.Hex())
+		}
+		args.deposit = account.Deposit
+	}
+	return nil
+}
+
 // BlockOverrides is a set of header fields to override.
 type BlockOverrides struct {
 	Number      *hexutil.Big
@@ -1144,10 +1208,13 @@ func (context *ChainContext) GetHeader(hash common.Hash, number uint64) *types.H
 	return header
 }
 
-func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.StateDB, header *types.Header, overrides *StateOverride, blockOverrides *BlockOverrides, timeout time.Duration, globalGasCap uint64) (*core.ExecutionResult, error) {
+func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.StateDB, header *types.Header, overrides *StateOverride, blockOverrides *BlockOverrides, timeout time.Duration, globalGasCap uint64, tracer vm.EVMLogger) (*core.ExecutionResult, error) {
 	if err := overrides.Apply(state); err != nil {
 		return nil, err
 	}
+	if err := overrides.applyDeposit(&args); err != nil {
+		return nil, err
+	}
 	// Setup context so it may be cancelled the call has completed
 	// or, in case of unmetered gas, setup a context with a timeout.
 	var cancel context.CancelFunc
@@ -1169,7 +1236,8 @@ func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.S
 	if blockOverrides != nil {
 		blockOverrides.Apply(&blockCtx)
 	}
-	evm, vmError := b.GetEVM(ctx, msg, state, header, &vm.Config{NoBaseFee: true}, &blockCtx)
+	memoryLimit := b.RPCEVMMemoryLimit()
+	evm, vmError := b.GetEVM(ctx, msg, state, header, &vm.Config{NoBaseFee: true, MemoryLimit: memoryLimit, Tracer: tracer}, &blockCtx)
 
 	// Wait for the context to be done and cancel the evm. Even if the
 	// EVM has finished, cancelling may be done (repeatedly)
@@ -1178,6 +1246,17 @@ func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.S
 		evm.Cancel()
 	}()
 
+	// Abort the execution if it loads too much from the database. The state may
+	// be shared by multiple calls, so the limit is lifted afterwards.
+	var readsExceeded bool
+	if readLimit := b.RPCEVMReadLimit(); readLimit > 0 {
+		state.SetReadLimit(int(readLimit), func() {
+			readsExceeded = true
+			evm.Cancel()
+		})
+		defer state.SetReadLimit(0, nil)
+	}
+
 	// Execute the message.
 	gp := new(core.GasPool).AddGas(math.MaxUint64)
 	result, err := core.ApplyMessage(evm, msg, gp)
@@ -1185,9 +1264,15 @@ func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.S
 		return nil, err
 	}
 
-	// If the timer caused an abort, return an appropriate error message
+	// If a resource limit or the timer caused an abort, return an appropriate error
+	if evm.MemoryExceeded() {
+		return nil, newResourceExceededError(ResourceMemory, memoryLimit)
+	}
+	if readsExceeded {
+		return nil, newResourceExceededError(ResourceStateReads, b.RPCEVMReadLimit())
+	}
 	if evm.Cancelled() {
-		return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+		return nil, newResourceExceededError(ResourceTime, timeout)
 	}
 	if err != nil {
 		return result, fmt.Errorf("err: %w (supplied gas %d)", err, msg.GasLimit)
@@ -1203,7 +1288,7 @@ func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash
 		return nil, err
 	}
 
-	return doCall(ctx, b, args, state, header, overrides, blockOverrides, timeout, globalGasCap)
+	return doCall(ctx, b, args, state, header, overrides, blockOverrides, timeout, globalGasCap, nil)
 }
 
 func newRevertError(result *core.ExecutionResult) *revertError {
@@ -1256,7 +1341,7 @@ func (s *BlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrO
 	if s.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
 		if s.b.HistoricalRPCService() != nil {
 			var res hexutil.Bytes
-			err := s.b.HistoricalRPCService().CallContext(ctx, &res, "eth_call", args, blockNrOrHash, overrides)
+			err := CallHistorical(ctx, s.b.HistoricalRPCService(), &res, "eth_call", args, blockNrOrHash, overrides)
 			if err != nil {
 				return nil, fmt.Errorf("historical backend error: %w", err)
 			}
@@ -1282,7 +1367,7 @@ func (s *BlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrO
 // error means execution failed due to reasons unrelated to the gas limit.
 func executeEstimate(ctx context.Context, b Backend, args TransactionArgs, state *state.StateDB, header *types.Header, gasCap uint64, gasLimit uint64) (bool, *core.ExecutionResult, error) {
 	args.Gas = (*hexutil.Uint64)(&gasLimit)
-	result, err := doCall(ctx, b, args, state, header, nil, nil, 0, gasCap)
+	result, err := doCall(ctx, b, args, state, header, nil, nil, 0, gasCap, nil)
 	if err != nil {
 		if errors.Is(err, core.ErrIntrinsicGas) {
 			return true, nil, nil // Special case, raise gas limit
@@ -1297,6 +1382,22 @@ func executeEstimate(ctx context.Context, b Backend, args TransactionArgs, state
 // there are unexpected failures. The gas limit is capped by both `args.Gas` (if non-nil &
 // non-zero) and `gasCap` (if non-zero).
 func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, gasCap uint64) (hexutil.Uint64, error) {
+	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return 0, err
+	}
+	if err := overrides.Apply(state); err != nil {
+		return 0, err
+	}
+	if err := overrides.applyDeposit(&args); err != nil {
+		return 0, err
+	}
+	return doEstimateGas(ctx, b, args, state, header, gasCap)
+}
+
+// doEstimateGas runs the gas limit binary search of DoEstimateGas on the given
+// state, which is never modified as every execution operates on a copy.
+func doEstimateGas(ctx context.Context, b Backend, args TransactionArgs, state *state.StateDB, header *types.Header, gasCap uint64) (hexutil.Uint64, error) {
 	// Binary search the gas limit, as it may need to be higher than the amount used
 	var (
 		lo uint64 // lowest-known gas limit where tx execution fails
@@ -1310,15 +1411,8 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 	if args.Gas != nil && uint64(*args.Gas) >= params.TxGas {
 		hi = uint64(*args.Gas)
 	} else {
-		// Retrieve the block to act as the gas ceiling
-		block, err := b.BlockByNumberOrHash(ctx, blockNrOrHash)
-		if err != nil {
-			return 0, err
-		}
-		if block == nil {
-			return 0, errors.New("block not found")
-		}
-		hi = block.GasLimit()
+		// Use the block gas limit as the gas ceiling
+		hi = header.GasLimit
 	}
 	// Normalize the max fee per gas the call is willing to spend.
 	var feeCap *big.Int
@@ -1332,16 +1426,9 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 		feeCap = common.Big0
 	}
 
-	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
-	if state == nil || err != nil {
-		return 0, err
-	}
-	if err := overrides.Apply(state); err != nil {
-		return 0, err
-	}
-
-	// Recap the highest gas limit with account's available balance.
-	if feeCap.BitLen() != 0 {
+	// Recap the highest gas limit with account's available balance. Deposits
+	// don't pay for gas.
+	if feeCap.BitLen() != 0 && args.deposit == nil {
 		balance := state.GetBalance(*args.From) // from can't be nil
 		available := new(big.Int).Set(balance)
 		if args.Value != nil {
@@ -1352,6 +1439,19 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 		}
 		allowance := new(big.Int).Div(available, feeCap)
 
+		// The operator fee is charged on top of the gas from its fork:
+		// gas * feeCap + gas * scalar / 1e6 + constant must be fundable.
+		if config := b.ChainConfig(); config.IsOasysOperatorFee(header.Time) && !config.IsFeeZero(header.Time) {
+			scalar, constant := types.ExtractOperatorFeeParams(state.GetState(types.L1BlockAddr, types.OperatorFeeParamsSlot))
+			fundable := new(big.Int).Sub(available, new(big.Int).SetUint64(constant))
+			if fundable.Sign() < 0 {
+				fundable.SetUint64(0)
+			}
+			perGas := new(big.Int).Mul(feeCap, big.NewInt(1_000_000))
+			perGas.Add(perGas, new(big.Int).SetUint64(scalar))
+			allowance = fundable.Mul(fundable, big.NewInt(1_000_000)).Div(fundable, perGas)
+		}
+
 		// If the allowance is larger than maximum uint64, skip checking
 		if allowance.IsUint64() && hi > allowance.Uint64() {
 			transfer := args.Value
@@ -1434,7 +1534,7 @@ func (s *BlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, b
 	if s.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
 		if s.b.HistoricalRPCService() != nil {
 			var res hexutil.Uint64
-			err := s.b.HistoricalRPCService().CallContext(ctx, &res, "eth_estimateGas", args, blockNrOrHash)
+			err := CallHistorical(ctx, s.b.HistoricalRPCService(), &res, "eth_estimateGas", args, blockNrOrHash)
 			if err != nil {
 				return 0, fmt.Errorf("historical backend error: %w", err)
 			}
@@ -1447,6 +1547,93 @@ func (s *BlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, b
 	return DoEstimateGas(ctx, s.b, args, bNrOrHash, overrides, s.b.RPCGasCap())
 }
 
+// maxEstimateGasBulkCalls is the maximum number of calls accepted by a single
+// eth_estimateGasBulk request.
+const maxEstimateGasBulkCalls = 1000
+
+// estimateGasBulkResult is the result of a single call of eth_estimateGasBulk.
+// Exactly one of Gas and Error is set.
+type estimateGasBulkResult struct {
+	Gas   *hexutil.Uint64     `json:"gas,omitempty"`
+	Error *estimateGasBulkErr `json:"error,omitempty"`
+}
+
+// estimateGasBulkErr reports the failure of a single call of eth_estimateGasBulk
+// without failing the whole request.
+type estimateGasBulkErr struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// EstimateGasBulk estimates the gas of many calls against a single snapshot of
+// the state at block `blockNrOrHash`, or the latest block if unspecified. The
+// state is loaded (and overridden) only once and shared by all calls, which are
+// estimated independently from each other.
+//
+// Each call is capped by its own `gas` field (if set) and the backend's RPCGasCap,
+// and is bounded by the backend's RPCEVMTimeout. Failures of individual calls are
+// reported per item instead of failing the entire request.
+func (s *BlockChainAPI) EstimateGasBulk(ctx context.Context, calls []TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash, overrides *StateOverride) ([]estimateGasBulkResult, error) {
+	if len(calls) > maxEstimateGasBulkCalls {
+		return nil, fmt.Errorf("too many calls: %d, limit %d", len(calls), maxEstimateGasBulkCalls)
+	}
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	state, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, bNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if s.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
+		return nil, rpc.ErrNoHistoricalFallback
+	}
+	if err := overrides.Apply(state); err != nil {
+		return nil, err
+	}
+	var (
+		gasCap  = s.b.RPCGasCap()
+		timeout = s.b.RPCEVMTimeout()
+		results = make([]estimateGasBulkResult, len(calls))
+	)
+	for i, args := range calls {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var (
+			callCtx context.Context
+			cancel  context.CancelFunc
+		)
+		if timeout > 0 {
+			callCtx, cancel = context.WithTimeout(ctx, timeout)
+		} else {
+			callCtx, cancel = context.WithCancel(ctx)
+		}
+		err := overrides.applyDeposit(&args)
+		var gas hexutil.Uint64
+		if err == nil {
+			gas, err = doEstimateGas(callCtx, s.b, args, state, header, gasCap)
+		}
+		cancel()
+
+		if err != nil {
+			results[i].Error = &estimateGasBulkErr{Code: -32000, Message: err.Error()}
+			var lerr *ResourceExceededError
+			if rerr, ok := err.(*revertError); ok {
+				results[i].Error.Code = rerr.ErrorCode()
+				results[i].Error.Data = rerr.ErrorData()
+			} else if errors.As(err, &lerr) {
+				results[i].Error.Code = lerr.ErrorCode()
+				results[i].Error.Data = lerr.ErrorData()
+			}
+			continue
+		}
+		results[i].Gas = &gas
+	}
+	return results, nil
+}
+
 // RPCMarshalHeader converts the given header to the RPC output .
 func RPCMarshalHeader(head *types.Header) map[string]interface{} {
 	result := map[string]interface{}{
@@ -1753,7 +1940,7 @@ func (s *BlockChainAPI) CreateAccessList(ctx context.Context, args TransactionAr
 	if err == nil && header != nil && s.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
 		if s.b.HistoricalRPCService() != nil {
 			var res accessListResult
-			err := s.b.HistoricalRPCService().CallContext(ctx, &res, "eth_createAccessList", args, blockNrOrHash)
+			err := CallHistorical(ctx, s.b.HistoricalRPCService(), &res, "eth_createAccessList", args, blockNrOrHash)
 			if err != nil {
 				return nil, fmt.Errorf("historical backend error: %w", err)
 			}
@@ -1921,7 +2108,7 @@ func (s *TransactionAPI) GetTransactionCount(ctx context.Context, address common
 	if s.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
 		if s.b.HistoricalRPCService() != nil {
 			var res hexutil.Uint64
-			err := s.b.HistoricalRPCService().CallContext(ctx, &res, "eth_getTransactionCount", address, blockNrOrHash)
+			err := CallHistorical(ctx, s.b.HistoricalRPCService(), &res, "eth_getTransactionCount", address, blockNrOrHash)
 			if err != nil {
 				return nil, fmt.Errorf("historical backend error: %w", err)
 			}
@@ -1940,6 +2127,14 @@ func (s *TransactionAPI) GetTransactionCount(ctx context.Context, address common
 	return (*hexutil.Uint64)(&nonce), state.Error()
 }
 
+// beyondLatest reports whether the block with the given number is beyond the
+// head the "latest" tag resolves to for the request, e.g. an unsafe block hidden
+// from the consumers pinned to the safe head.
+func beyondLatest(ctx context.Context, b Backend, number uint64) bool {
+	head, err := b.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	return head == nil || err != nil || number > head.Number.Uint64()
+}
+
 // GetTransactionByHash returns the transaction for the given hash
 func (s *TransactionAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) (*RPCTransaction, error) {
 	// Try to return an already finalized transaction
@@ -1947,6 +2142,9 @@ func (s *TransactionAPI) GetTransactionByHash(ctx context.Context, hash common.H
 	if err != nil {
 		return nil, err
 	}
+	if tx != nil && beyondLatest(ctx, s.b, blockNumber) {
+		return nil, nil
+	}
 	if tx != nil {
 		header, err := s.b.HeaderByHash(ctx, blockHash)
 		if err != nil {
@@ -1964,13 +2162,38 @@ func (s *TransactionAPI) GetTransactionByHash(ctx context.Context, hash common.H
 	return nil, nil
 }
 
+// GetTransactionBySenderAndNonce returns the transaction sent by the given account
+// with the given nonce, included in the indexed history or still pooled.
+func (s *TransactionAPI) GetTransactionBySenderAndNonce(ctx context.Context, sender common.Address, nonce hexutil.Uint64) (*RPCTransaction, error) {
+	hash, err := s.b.GetTransactionHashBySenderAndNonce(ctx, sender, uint64(nonce))
+	if err != nil {
+		return nil, err
+	}
+	if hash != nil {
+		return s.GetTransactionByHash(ctx, *hash)
+	}
+	// Not included, look for it in the pool
+	pending, queued := s.b.TxPoolContentFrom(sender)
+	for _, txs := range []types.Transactions{pending, queued} {
+		for _, tx := range txs {
+			if tx.Nonce() == uint64(nonce) {
+				return NewRPCPendingTransaction(tx, s.b.CurrentHeader(), s.b.ChainConfig()), nil
+			}
+		}
+	}
+	return nil, nil
+}
+
 // GetRawTransactionByHash returns the bytes of the transaction for the given hash.
 func (s *TransactionAPI) GetRawTransactionByHash(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
 	// Retrieve a finalized transaction, or a pooled otherwise
-	tx, _, _, _, err := s.b.GetTransaction(ctx, hash)
+	tx, _, blockNumber, _, err := s.b.GetTransaction(ctx, hash)
 	if err != nil {
 		return nil, err
 	}
+	if tx != nil && beyondLatest(ctx, s.b, blockNumber) {
+		return nil, nil
+	}
 	if tx == nil {
 		if tx = s.b.GetPoolTransaction(hash); tx == nil {
 			// Transaction not found anywhere, abort
@@ -1982,9 +2205,10 @@ func (s *TransactionAPI) GetRawTransactionByHash(ctx context.Context, hash commo
 }
 
 // GetTransactionReceipt returns the transaction receipt for the given transaction hash.
-func (s *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+// The receipt is annotated with the confirmation level of its block if requested.
+func (s *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash, opts *ReceiptOptions) (map[string]interface{}, error) {
 	tx, blockHash, blockNumber, index, err := s.b.GetTransaction(ctx, hash)
-	if tx == nil || err != nil {
+	if tx == nil || err != nil || beyondLatest(ctx, s.b, blockNumber) {
 		// When the transaction doesn't exist, the RPC method should return JSON null
 		// as per specification.
 		return nil, nil
@@ -2004,11 +2228,92 @@ func (s *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash common.
 
 	// Derive the sender.
 	signer := types.MakeSigner(s.b.ChainConfig(), header.Number, header.Time)
-	return marshalReceipt(receipt, blockHash, blockNumber, signer, tx, int(index), s.b.ChainConfig()), nil
+	fields := marshalReceipt(receipt, blockHash, blockNumber, signer, tx, int(index), s.b.ChainConfig(), operatorFeeAt(ctx, s.b, header))
+	if opts != nil && opts.Confirmation {
+		if err := annotateConfirmation(ctx, s.b, fields, header); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
 }
 
-// marshalReceipt marshals a transaction receipt into a JSON object.
-func marshalReceipt(receipt *types.Receipt, blockHash common.Hash, blockNumber uint64, signer types.Signer, tx *types.Transaction, txIndex int, chainConfig *params.ChainConfig) map[string]interface{} {
+// maxTransactionReceipts is the maximum number of hashes accepted by a single
+// eth_getTransactionReceipts request.
+const maxTransactionReceipts = 1000
+
+// GetTransactionReceipts returns the receipts of the given transactions, in the
+// order of the hashes. The transactions are looked up in a single pass and the
+// receipts of each block are loaded once. The result is partial: the receipt of
+// a transaction which isn't found, or whose block can't be read, is null.
+func (s *TransactionAPI) GetTransactionReceipts(ctx context.Context, hashes []common.Hash, opts *ReceiptOptions) ([]map[string]interface{}, error) {
+	if len(hashes) > maxTransactionReceipts {
+		return nil, fmt.Errorf("too many transactions, maximum %d", maxTransactionReceipts)
+	}
+	type lookup struct {
+		tx    *types.Transaction
+		index uint64
+		pos   int // Position of the hash in the request
+	}
+	var (
+		results = make([]map[string]interface{}, len(hashes))
+		blocks  = make(map[common.Hash][]lookup)
+		order   []common.Hash
+	)
+	head, err := s.b.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	for i, hash := range hashes {
+		tx, blockHash, blockNumber, index, err := s.b.GetTransaction(ctx, hash)
+		if tx == nil || err != nil || blockNumber > head.Number.Uint64() {
+			continue
+		}
+		if _, ok := blocks[blockHash]; !ok {
+			order = append(order, blockHash)
+		}
+		blocks[blockHash] = append(blocks[blockHash], lookup{tx, index, i})
+	}
+	for _, blockHash := range order {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		header, err := s.b.HeaderByHash(ctx, blockHash)
+		if header == nil || err != nil {
+			continue
+		}
+		receipts, err := s.b.GetReceipts(ctx, blockHash)
+		if err != nil {
+			continue
+		}
+		// The confirmation annotations are shared by the receipts of the block
+		var annotations map[string]interface{}
+		if opts != nil && opts.Confirmation {
+			annotations = make(map[string]interface{})
+			if err := annotateConfirmation(ctx, s.b, annotations, header); err != nil {
+				return nil, err
+			}
+		}
+		var (
+			signer = types.MakeSigner(s.b.ChainConfig(), header.Number, header.Time)
+			fee    = operatorFeeAt(ctx, s.b, header)
+		)
+		for _, l := range blocks[blockHash] {
+			if uint64(len(receipts)) <= l.index {
+				continue
+			}
+			fields := marshalReceipt(receipts[l.index], blockHash, header.Number.Uint64(), signer, l.tx, int(l.index), s.b.ChainConfig(), fee)
+			for key, value := range annotations {
+				fields[key] = value
+			}
+			results[l.pos] = fields
+		}
+	}
+	return results, nil
+}
+
+// marshalReceipt marshals a transaction receipt into a JSON object, along with
+// the operator fee parameters of its block if known.
+func marshalReceipt(receipt *types.Receipt, blockHash common.Hash, blockNumber uint64, signer types.Signer, tx *types.Transaction, txIndex int, chainConfig *params.ChainConfig, fee *operatorFee) map[string]interface{} {
 	from, _ := types.Sender(signer, tx)
 
 	fields := map[string]interface{}{
@@ -2032,6 +2337,10 @@ func marshalReceipt(receipt *types.Receipt, blockHash common.Hash, blockNumber u
 		fields["l1GasUsed"] = (*hexutil.Big)(receipt.L1GasUsed)
 		fields["l1Fee"] = (*hexutil.Big)(receipt.L1Fee)
 		fields["l1FeeScalar"] = receipt.FeeScalar.String()
+		if fee != nil {
+			fields["operatorFeeScalar"] = fee.Scalar
+			fields["operatorFeeConstant"] = fee.Constant
+		}
 	}
 	if chainConfig.Optimism != nil && tx.IsDepositTx() && receipt.DepositNonce != nil {
 		fields["depositNonce"] = hexutil.Uint64(*receipt.DepositNonce)
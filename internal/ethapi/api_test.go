@@ -41,7 +41,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
-	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -609,6 +609,8 @@ func (b testBackend) AccountManager() *accounts.Manager { return nil }
 func (b testBackend) ExtRPCEnabled() bool               { return false }
 func (b testBackend) RPCGasCap() uint64                 { return 10000000 }
 func (b testBackend) RPCEVMTimeout() time.Duration      { return time.Second }
+func (b testBackend) RPCEVMMemoryLimit() uint64         { return 0 }
+func (b testBackend) RPCEVMReadLimit() uint64           { return 0 }
 func (b testBackend) RPCTxFeeCap() float64              { return 0 }
 func (b testBackend) UnprotectedAllowed() bool          { return false }
 func (b testBackend) SetHead(number uint64)             {}
@@ -678,7 +680,15 @@ func (b testBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOr
 	if blockNr, ok := blockNrOrHash.Number(); ok {
 		return b.StateAndHeaderByNumber(ctx, blockNr)
 	}
-	panic("only implemented for number")
+	if blockHash, ok := blockNrOrHash.Hash(); ok {
+		header := b.chain.GetHeaderByHash(blockHash)
+		if header == nil {
+			return nil, nil, errors.New("header not found")
+		}
+		stateDb, err := b.chain.StateAt(header.Root)
+		return stateDb, header, err
+	}
+	panic("only implemented for number and hash")
 }
 func (b testBackend) PendingBlockAndReceipts() (*types.Block, types.Receipts) { panic("implement me") }
 func (b testBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
@@ -723,6 +733,9 @@ func (b testBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*t
 	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(b.db, txHash)
 	return tx, blockHash, blockNumber, index, nil
 }
+func (b testBackend) GetTransactionHashBySenderAndNonce(ctx context.Context, sender common.Address, nonce uint64) (*common.Hash, error) {
+	return nil, nil
+}
 func (b testBackend) GetPoolTransactions() (types.Transactions, error)         { panic("implement me") }
 func (b testBackend) GetPoolTransaction(txHash common.Hash) *types.Transaction { panic("implement me") }
 func (b testBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error) {
@@ -752,10 +765,7 @@ func (b testBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscripti
 func (b testBackend) SubscribePendingLogsEvent(ch chan<- []*types.Log) event.Subscription {
 	panic("implement me")
 }
-func (b testBackend) BloomStatus() (uint64, uint64) { panic("implement me") }
-func (b testBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
-	panic("implement me")
-}
+func (b testBackend) LogIndex() filtermaps.LogIndex { panic("implement me") }
 func (b testBackend) HistoricalRPCService() *rpc.Client {
 	panic("implement me")
 }
@@ -907,6 +917,57 @@ func TestEstimateGas(t *testing.T) {
 	}
 }
 
+func TestEstimateGasBulk(t *testing.T) {
+	t.Parallel()
+	// Initialize test accounts
+	var (
+		accounts = newAccounts(2)
+		genesis  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+				accounts[1].addr: {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		genBlocks      = 10
+		signer         = types.HomesteadSigner{}
+		randomAccounts = newAccounts(1)
+	)
+	api := NewBlockChainAPI(newTestBackend(t, genBlocks, genesis, ethash.NewFaker(), func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{Nonce: uint64(i), To: &accounts[1].addr, Value: big.NewInt(1000), Gas: params.TxGas, GasPrice: b.BaseFee(), Data: nil}), signer, accounts[0].key)
+		b.AddTx(tx)
+	}))
+	calls := []TransactionArgs{
+		// simple transfer
+		{From: &accounts[0].addr, To: &accounts[1].addr, Value: (*hexutil.Big)(big.NewInt(1000))},
+		// transfer with insufficient funds
+		{From: &randomAccounts[0].addr, To: &accounts[1].addr, Value: (*hexutil.Big)(big.NewInt(1000))},
+		// empty create
+		{},
+	}
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	results, err := api.EstimateGasBulk(context.Background(), calls, &latest, nil)
+	if err != nil {
+		t.Fatalf("failed to estimate gas: %v", err)
+	}
+	if len(results) != len(calls) {
+		t.Fatalf("result count mismatch: have %d, want %d", len(results), len(calls))
+	}
+	if results[0].Error != nil || results[0].Gas == nil || uint64(*results[0].Gas) != 21000 {
+		t.Errorf("transfer: have %v (err %v), want 21000", results[0].Gas, results[0].Error)
+	}
+	if results[1].Error == nil || results[1].Gas != nil {
+		t.Errorf("unfunded transfer: expected per-item error, have gas %v", results[1].Gas)
+	}
+	if results[2].Error != nil || results[2].Gas == nil || uint64(*results[2].Gas) != 53000 {
+		t.Errorf("empty create: have %v (err %v), want 53000", results[2].Gas, results[2].Error)
+	}
+	// Ensure the request is rejected if it exceeds the call limit
+	if _, err := api.EstimateGasBulk(context.Background(), make([]TransactionArgs, maxEstimateGasBulkCalls+1), &latest, nil); err == nil {
+		t.Errorf("expected error for oversized request")
+	}
+}
+
 func TestCall(t *testing.T) {
 	t.Parallel()
 	// Initialize test accounts
@@ -1036,6 +1097,66 @@ func TestCall(t *testing.T) {
 			},
 			want: "0x000000000000000000000000000000000000000000000000000000000000007b",
 		},
+		// Delegated code is executed in the context of the delegating account
+		{
+			blockNumber: rpc.LatestBlockNumber,
+			call: TransactionArgs{
+				From: &randomAccounts[0].addr,
+				To:   &randomAccounts[1].addr,
+				Data: hex2Bytes("8381f58a"), // call number()
+			},
+			overrides: StateOverride{
+				randomAccounts[1].addr: OverrideAccount{
+					Delegation: &randomAccounts[2].addr,
+					StateDiff:  &map[common.Hash]common.Hash{{}: common.BigToHash(big.NewInt(456))},
+				},
+				randomAccounts[2].addr: OverrideAccount{
+					Code: hex2Bytes("6080604052348015600f57600080fd5b506004361060285760003560e01c80638381f58a14602d575b600080fd5b60336049565b6040518082815260200191505060405180910390f35b6000548156fea2646970667358221220eab35ffa6ab2adfe380772a48b8ba78e82a1b820a18fcb6f59aa4efb20a5f60064736f6c63430007040033"),
+				},
+			},
+			want: "0x00000000000000000000000000000000000000000000000000000000000001c8",
+		},
+		// Delegation and code overrides are exclusive
+		{
+			blockNumber: rpc.LatestBlockNumber,
+			call: TransactionArgs{
+				From: &randomAccounts[0].addr,
+				To:   &randomAccounts[1].addr,
+			},
+			overrides: StateOverride{
+				randomAccounts[1].addr: OverrideAccount{
+					Code:       hex2Bytes("00"),
+					Delegation: &randomAccounts[2].addr,
+				},
+			},
+			expectErr: fmt.Errorf("account %s has both 'code' and 'delegation'", randomAccounts[1].addr.Hex()),
+		},
+		// Deposits mint to the sender before execution
+		{
+			blockNumber: rpc.LatestBlockNumber,
+			call: TransactionArgs{
+				From:  &randomAccounts[0].addr,
+				To:    &randomAccounts[1].addr,
+				Value: (*hexutil.Big)(big.NewInt(1000)),
+			},
+			overrides: StateOverride{
+				randomAccounts[0].addr: OverrideAccount{Deposit: &DepositOverride{Mint: (*hexutil.Big)(big.NewInt(1000))}},
+			},
+			want: "0x",
+		},
+		// Deposit context is only allowed on the sender
+		{
+			blockNumber: rpc.LatestBlockNumber,
+			call: TransactionArgs{
+				From:  &randomAccounts[0].addr,
+				To:    &randomAccounts[1].addr,
+				Value: (*hexutil.Big)(big.NewInt(1000)),
+			},
+			overrides: StateOverride{
+				randomAccounts[1].addr: OverrideAccount{Deposit: &DepositOverride{Mint: (*hexutil.Big)(big.NewInt(1000))}},
+			},
+			expectErr: fmt.Errorf("account %s has 'deposit' but is not the sender", randomAccounts[1].addr.Hex()),
+		},
 		// Block overrides should work
 		{
 			blockNumber: rpc.LatestBlockNumber,
@@ -1722,7 +1843,7 @@ func TestRPCGetTransactionReceipt(t *testing.T) {
 			result interface{}
 			err    error
 		)
-		result, err = api.GetTransactionReceipt(context.Background(), tt.txHash)
+		result, err = api.GetTransactionReceipt(context.Background(), tt.txHash, nil)
 		if err != nil {
 			t.Errorf("test %d: want no error, have %v", i, err)
 			continue
@@ -1731,6 +1852,186 @@ func TestRPCGetTransactionReceipt(t *testing.T) {
 	}
 }
 
+func TestRPCGetTransactionReceipts(t *testing.T) {
+	t.Parallel()
+
+	var (
+		backend, txHashes = setupReceiptBackend(t, 6)
+		api               = NewTransactionAPI(backend, new(AddrLocker))
+		ctx               = context.Background()
+	)
+	// Unknown transactions are null, the others match the single receipt lookups
+	hashes := []common.Hash{txHashes[2], common.HexToHash("deadbeef"), txHashes[0], txHashes[2]}
+	results, err := api.GetTransactionReceipts(ctx, hashes, nil)
+	if err != nil {
+		t.Fatalf("failed to get receipts: %v", err)
+	}
+	if len(results) != len(hashes) {
+		t.Fatalf("result count mismatch: have %d, want %d", len(results), len(hashes))
+	}
+	for i, hash := range hashes {
+		want, err := api.GetTransactionReceipt(ctx, hash, nil)
+		if err != nil {
+			t.Fatalf("failed to get receipt %d: %v", i, err)
+		}
+		if !reflect.DeepEqual(results[i], want) {
+			t.Errorf("receipt %d mismatch: have %v, want %v", i, results[i], want)
+		}
+	}
+	if _, err := api.GetTransactionReceipts(ctx, make([]common.Hash, maxTransactionReceipts+1), nil); err == nil {
+		t.Errorf("oversized request accepted")
+	}
+}
+
+// pinnedBackend resolves the "latest" tag to a block behind the chain head, as
+// a backend pinned to the safe head does.
+type pinnedBackend struct {
+	*testBackend
+	head uint64
+}
+
+func (b pinnedBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number == rpc.LatestBlockNumber {
+		number = rpc.BlockNumber(b.head)
+	}
+	return b.testBackend.HeaderByNumber(ctx, number)
+}
+
+func TestRPCTransactionsBeyondLatest(t *testing.T) {
+	t.Parallel()
+
+	var (
+		backend, txHashes = setupReceiptBackend(t, 6)
+		api               = NewTransactionAPI(pinnedBackend{backend, 3}, new(AddrLocker))
+		ctx               = context.Background()
+	)
+	if n, err := NewBlockChainAPI(pinnedBackend{backend, 3}).BlockNumber(ctx); err != nil || n != 3 {
+		t.Fatalf("block number: have %d (%v), want 3", n, err)
+	}
+	for i, hash := range txHashes {
+		_, _, number, _, err := backend.GetTransaction(ctx, hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		visible := number <= 3
+
+		receipt, err := api.GetTransactionReceipt(ctx, hash, nil)
+		if err != nil {
+			t.Fatalf("tx %d: receipt error: %v", i, err)
+		}
+		if (receipt != nil) != visible {
+			t.Errorf("tx %d in block %d: receipt visible %v, want %v", i, number, receipt != nil, visible)
+		}
+		tx, err := api.GetTransactionByHash(ctx, hash)
+		if err != nil {
+			t.Fatalf("tx %d: lookup error: %v", i, err)
+		}
+		if (tx != nil) != visible {
+			t.Errorf("tx %d in block %d: transaction visible %v, want %v", i, number, tx != nil, visible)
+		}
+		raw, err := api.GetRawTransactionByHash(ctx, hash)
+		if err != nil {
+			t.Fatalf("tx %d: raw lookup error: %v", i, err)
+		}
+		if (raw != nil) != visible {
+			t.Errorf("tx %d in block %d: raw transaction visible %v, want %v", i, number, raw != nil, visible)
+		}
+		receipts, err := api.GetTransactionReceipts(ctx, []common.Hash{hash}, nil)
+		if err != nil {
+			t.Fatalf("tx %d: receipts error: %v", i, err)
+		}
+		if (receipts[0] != nil) != visible {
+			t.Errorf("tx %d in block %d: batched receipt visible %v, want %v", i, number, receipts[0] != nil, visible)
+		}
+	}
+}
+
+// poolBackend serves the pending state from the chain head, along with a fixed
+// set of pooled transactions.
+type poolBackend struct {
+	*testBackend
+	pending map[common.Address][]*types.Transaction
+	queued  map[common.Address][]*types.Transaction
+}
+
+func (b poolBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	if number == rpc.PendingBlockNumber {
+		number = rpc.LatestBlockNumber
+	}
+	return b.testBackend.StateAndHeaderByNumber(ctx, number)
+}
+
+func (b poolBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error) {
+	if txs := b.pending[addr]; len(txs) > 0 {
+		return txs[len(txs)-1].Nonce() + 1, nil
+	}
+	state, _, err := b.StateAndHeaderByNumber(ctx, rpc.PendingBlockNumber)
+	if err != nil {
+		return 0, err
+	}
+	return state.GetNonce(addr), nil
+}
+
+func (b poolBackend) TxPoolContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction) {
+	return b.pending[addr], b.queued[addr]
+}
+
+func TestGetPendingAccountStates(t *testing.T) {
+	t.Parallel()
+
+	var (
+		pooled  = common.Address{0x01}
+		queued  = common.Address{0x02}
+		idle    = common.Address{0x03}
+		genesis = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				pooled: {Balance: big.NewInt(100), Nonce: 3},
+				queued: {Balance: big.NewInt(200), Nonce: 7},
+				idle:   {Balance: big.NewInt(300)},
+			},
+		}
+		newTx = func(nonce uint64) *types.Transaction {
+			return types.NewTx(&types.LegacyTx{Nonce: nonce, Gas: 21000, GasPrice: big.NewInt(1)})
+		}
+		backend = poolBackend{
+			testBackend: newTestBackend(t, 1, genesis, ethash.NewFaker(), nil),
+			pending: map[common.Address][]*types.Transaction{
+				pooled: {newTx(3), newTx(4)},
+			},
+			queued: map[common.Address][]*types.Transaction{
+				pooled: {newTx(9)},
+				queued: {newTx(9), newTx(10)},
+			},
+		}
+		api = NewRollupAPI(backend)
+	)
+	// The results follow the order of the request, unknown accounts included
+	addresses := []common.Address{idle, queued, {0x04}, pooled}
+	states, err := api.GetPendingAccountStates(context.Background(), addresses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*PendingAccountState{
+		{Address: idle, Nonce: 0, Balance: (*hexutil.Big)(big.NewInt(300))},
+		{Address: queued, Nonce: 7, Balance: (*hexutil.Big)(big.NewInt(200)), Queued: 2},
+		{Address: common.Address{0x04}, Nonce: 0, Balance: (*hexutil.Big)(new(big.Int))},
+		{Address: pooled, Nonce: 5, Balance: (*hexutil.Big)(big.NewInt(100)), Pending: 2, Queued: 1},
+	}
+	if len(states) != len(want) {
+		t.Fatalf("result count mismatch: have %d, want %d", len(states), len(want))
+	}
+	for i := range want {
+		have, want := states[i], want[i]
+		if have.Address != want.Address || have.Nonce != want.Nonce || have.Balance.ToInt().Cmp(want.Balance.ToInt()) != 0 || have.Pending != want.Pending || have.Queued != want.Queued {
+			t.Errorf("state %d mismatch: have %+v, want %+v", i, have, want)
+		}
+	}
+	if _, err := api.GetPendingAccountStates(context.Background(), make([]common.Address, maxPendingAccountStates+1)); err == nil {
+		t.Errorf("oversized request accepted")
+	}
+}
+
 func TestRPCGetBlockReceipts(t *testing.T) {
 	t.Parallel()
 
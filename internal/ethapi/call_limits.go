@@ -0,0 +1,60 @@
+package ethapi
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// Resources an EVM call executed over RPC is limited in.
+const (
+	ResourceTime       = "time"
+	ResourceMemory     = "memory"
+	ResourceStateReads = "stateReads"
+)
+
+var (
+	callTimeExceededMeter   = metrics.NewRegisteredMeter("rpc/call/exceeded/time", nil)
+	callMemoryExceededMeter = metrics.NewRegisteredMeter("rpc/call/exceeded/memory", nil)
+	callReadsExceededMeter  = metrics.NewRegisteredMeter("rpc/call/exceeded/reads", nil)
+)
+
+// ResourceExceededError is returned when an EVM call is aborted for exceeding one
+// of the limits the node puts on calls executed over RPC.
+type ResourceExceededError struct {
+	Resource string // Resource whose limit was exceeded
+	Limit    string // Configured limit of the resource
+}
+
+func newResourceExceededError(resource string, limit interface{}) *ResourceExceededError {
+	switch resource {
+	case ResourceTime:
+		callTimeExceededMeter.Mark(1)
+	case ResourceMemory:
+		callMemoryExceededMeter.Mark(1)
+	case ResourceStateReads:
+		callReadsExceededMeter.Mark(1)
+	}
+	return &ResourceExceededError{Resource: resource, Limit: fmt.Sprint(limit)}
+}
+
+func (e *ResourceExceededError) Error() string {
+	if e.Resource == ResourceTime {
+		return fmt.Sprintf("execution aborted (timeout = %s)", e.Limit)
+	}
+	return fmt.Sprintf("execution aborted (%s limit = %s)", e.Resource, e.Limit)
+}
+
+// ErrorCode returns the JSON error code for exceeded limits.
+// See: https://eips.ethereum.org/EIPS/eip-1474#error-codes
+func (e *ResourceExceededError) ErrorCode() int {
+	return -32005
+}
+
+// ErrorData returns the exceeded resource and its limit.
+func (e *ResourceExceededError) ErrorData() interface{} {
+	return map[string]string{
+		"resource": e.Resource,
+		"limit":    e.Limit,
+	}
+}
@@ -0,0 +1,249 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// callManyMaxCalls is the maximum number of calls simulated by eth_callMany.
+const callManyMaxCalls = 256
+
+// CallManyResult is the outcome of a call simulated by eth_callMany.
+type CallManyResult struct {
+	ReturnData hexutil.Bytes  `json:"returnData"`
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	Error      string         `json:"error,omitempty"`
+	Logs       []*types.Log   `json:"logs"`
+	StateDiff  *StateDiff     `json:"stateDiff,omitempty"`
+}
+
+// StateDiff is the state of the accounts modified by a call, before and after
+// it. The accounts created by the call are missing from the pre state and the
+// ones deleted from the post state.
+type StateDiff struct {
+	Pre  map[common.Address]*AccountState `json:"pre"`
+	Post map[common.Address]*AccountState `json:"post"`
+}
+
+// AccountState is the part of an account modified by a call.
+type AccountState struct {
+	Balance *hexutil.Big                `json:"balance,omitempty"`
+	Nonce   *hexutil.Uint64             `json:"nonce,omitempty"`
+	Code    hexutil.Bytes               `json:"code,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// CallMany simulates the given calls in order on the state of the given block,
+// each call seeing the state changes of the previous ones. The block overrides
+// apply to all the calls. A call failing doesn't abort the simulation, its error
+// is returned in its result, but exceeding the resource limits of the node does.
+//
+// The gas cap of the node applies to the calls altogether.
+func (s *BlockChainAPI) CallMany(ctx context.Context, calls []TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides) ([]*CallManyResult, error) {
+	if len(calls) == 0 {
+		return nil, errors.New("empty call list")
+	}
+	if len(calls) > callManyMaxCalls {
+		return nil, fmt.Errorf("too many calls: %d, maximum %d", len(calls), callManyMaxCalls)
+	}
+	if blockNrOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &latest
+	}
+	state, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if s.b.ChainConfig().IsOptimismPreBedrock(header.Number) {
+		return nil, errors.New("calls on pre-bedrock blocks not supported")
+	}
+	if err := overrides.Apply(state); err != nil {
+		return nil, err
+	}
+	// Execute the messages with the overridden header, so that the fees are
+	// also computed against the overridden base fee
+	header = types.CopyHeader(header)
+	if blockOverrides != nil {
+		if blockOverrides.BaseFee != nil {
+			header.BaseFee = blockOverrides.BaseFee.ToInt()
+		}
+		if blockOverrides.Coinbase != nil {
+			header.Coinbase = *blockOverrides.Coinbase
+		}
+	}
+	var (
+		gasCap  = s.b.RPCGasCap()
+		gasLeft = gasCap
+		results = make([]*CallManyResult, 0, len(calls))
+		logs    int
+	)
+	for i, args := range calls {
+		if gasCap != 0 && gasLeft == 0 {
+			return nil, fmt.Errorf("gas cap %d exhausted at call %d", gasCap, i)
+		}
+		if err := overrides.applyDeposit(&args); err != nil {
+			return nil, err
+		}
+		var (
+			pre    = state.Copy()
+			touch  = newCallTouches()
+			result = new(CallManyResult)
+		)
+		state.SetTxContext(common.Hash{}, i)
+		res, err := doCall(ctx, s.b, args, state, header, nil, blockOverrides, s.b.RPCEVMTimeout(), gasLeft, touch)
+		if err != nil {
+			var exceeded *ResourceExceededError
+			if errors.As(err, &exceeded) {
+				return nil, err
+			}
+			result.Error = err.Error()
+		}
+		if res != nil {
+			result.ReturnData = res.Return()
+			result.GasUsed = hexutil.Uint64(res.UsedGas)
+			if res.Err != nil {
+				result.Error = res.Err.Error()
+				if len(res.Revert()) > 0 {
+					result.Error = newRevertError(res).Error()
+					result.ReturnData = res.Revert()
+				}
+			}
+			if gasCap != 0 {
+				if res.UsedGas >= gasLeft {
+					gasLeft = 0
+				} else {
+					gasLeft -= res.UsedGas
+				}
+			}
+		}
+		// Collect the logs emitted by the call, appended to the ones of the
+		// previous calls as they share the same transaction hash
+		all := state.GetLogs(common.Hash{}, header.Number.Uint64(), common.Hash{})
+		result.Logs = all[logs:]
+		logs = len(all)
+
+		// Finalise the call like a transaction, clearing its refund and its
+		// destructed accounts before the next one
+		state.Finalise(s.b.ChainConfig().IsEIP158(header.Number))
+		if res != nil {
+			touch.addr(header.Coinbase)
+			if s.b.ChainConfig().Optimism != nil {
+				touch.addr(params.OptimismBaseFeeRecipient)
+				touch.addr(params.OptimismL1FeeRecipient)
+			}
+			touch.addr(args.from())
+			result.StateDiff = touch.diff(pre, state)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// callTouches is an EVM logger collecting the accounts and storage slots a call
+// may have modified.
+type callTouches struct {
+	accounts map[common.Address]map[common.Hash]struct{}
+}
+
+func newCallTouches() *callTouches {
+	return &callTouches{accounts: make(map[common.Address]map[common.Hash]struct{})}
+}
+
+func (t *callTouches) addr(addr common.Address) {
+	if _, ok := t.accounts[addr]; !ok {
+		t.accounts[addr] = make(map[common.Hash]struct{})
+	}
+}
+
+func (t *callTouches) CaptureTxStart(gasLimit uint64) {}
+
+func (t *callTouches) CaptureTxEnd(restGas uint64) {}
+
+func (t *callTouches) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.addr(from)
+	t.addr(to)
+}
+
+func (t *callTouches) CaptureEnd(output []byte, gasUsed uint64, err error) {}
+
+func (t *callTouches) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.addr(from)
+	t.addr(to)
+}
+
+func (t *callTouches) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+func (t *callTouches) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if err != nil {
+		return
+	}
+	stack := scope.Stack.Data()
+	switch {
+	case op == vm.SSTORE && len(stack) >= 1:
+		addr := scope.Contract.Address()
+		t.addr(addr)
+		t.accounts[addr][common.Hash(stack[len(stack)-1].Bytes32())] = struct{}{}
+	case op == vm.SELFDESTRUCT && len(stack) >= 1:
+		t.addr(scope.Contract.Address())
+		t.addr(common.Address(stack[len(stack)-1].Bytes20()))
+	}
+}
+
+func (t *callTouches) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// diff compares the touched accounts between the states before and after the
+// call, leaving out the ones left unchanged.
+func (t *callTouches) diff(pre, post *state.StateDB) *StateDiff {
+	diff := &StateDiff{
+		Pre:  make(map[common.Address]*AccountState),
+		Post: make(map[common.Address]*AccountState),
+	}
+	for addr, slots := range t.accounts {
+		var (
+			preAcc, postAcc = new(AccountState), new(AccountState)
+			modified        bool
+		)
+		if preBal, postBal := pre.GetBalance(addr), post.GetBalance(addr); preBal.Cmp(postBal) != 0 {
+			preAcc.Balance, postAcc.Balance = (*hexutil.Big)(preBal), (*hexutil.Big)(postBal)
+			modified = true
+		}
+		if preNonce, postNonce := pre.GetNonce(addr), post.GetNonce(addr); preNonce != postNonce {
+			preAcc.Nonce, postAcc.Nonce = (*hexutil.Uint64)(&preNonce), (*hexutil.Uint64)(&postNonce)
+			modified = true
+		}
+		if pre.GetCodeHash(addr) != post.GetCodeHash(addr) {
+			preAcc.Code, postAcc.Code = pre.GetCode(addr), post.GetCode(addr)
+			modified = true
+		}
+		for slot := range slots {
+			if preVal, postVal := pre.GetState(addr, slot), post.GetState(addr, slot); preVal != postVal {
+				if preAcc.Storage == nil {
+					preAcc.Storage, postAcc.Storage = make(map[common.Hash]common.Hash), make(map[common.Hash]common.Hash)
+				}
+				preAcc.Storage[slot], postAcc.Storage[slot] = preVal, postVal
+				modified = true
+			}
+		}
+		if !modified {
+			continue
+		}
+		if pre.Exist(addr) {
+			diff.Pre[addr] = preAcc
+		}
+		if post.Exist(addr) {
+			diff.Post[addr] = postAcc
+		}
+	}
+	return diff
+}
@@ -0,0 +1,80 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestCallMany(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(3)
+		genesis  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+				accounts[1].addr: {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		signer = types.HomesteadSigner{}
+	)
+	api := NewBlockChainAPI(newTestBackend(t, 2, genesis, ethash.NewFaker(), func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{Nonce: uint64(i), To: &accounts[1].addr, Value: big.NewInt(1000), Gas: params.TxGas, GasPrice: b.BaseFee()}), signer, accounts[0].key)
+		b.AddTx(tx)
+	}))
+	transfer := TransactionArgs{
+		From:  &accounts[0].addr,
+		To:    &accounts[1].addr,
+		Value: (*hexutil.Big)(big.NewInt(1000)),
+	}
+	broke := TransactionArgs{
+		From:  &accounts[2].addr,
+		To:    &accounts[1].addr,
+		Value: (*hexutil.Big)(big.NewInt(1000)),
+	}
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	results, err := api.CallMany(context.Background(), []TransactionArgs{transfer, broke, transfer}, &latest, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to simulate the calls: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("result count mismatch: have %d, want 3", len(results))
+	}
+	if results[1].Error == "" || results[1].StateDiff != nil {
+		t.Fatalf("failing call not reported: %+v", results[1])
+	}
+	for i, result := range []*CallManyResult{results[0], results[2]} {
+		if result.Error != "" {
+			t.Fatalf("call %d failed: %v", i, result.Error)
+		}
+		if result.GasUsed != hexutil.Uint64(params.TxGas) {
+			t.Fatalf("call %d gas mismatch: have %d, want %d", i, result.GasUsed, params.TxGas)
+		}
+		pre, post := result.StateDiff.Pre[accounts[1].addr], result.StateDiff.Post[accounts[1].addr]
+		if pre == nil || post == nil {
+			t.Fatalf("call %d recipient missing from the diff", i)
+		}
+		if have := new(big.Int).Sub(post.Balance.ToInt(), pre.Balance.ToInt()); have.Cmp(big.NewInt(1000)) != 0 {
+			t.Fatalf("call %d transferred amount mismatch: have %v, want 1000", i, have)
+		}
+		if sender := result.StateDiff.Post[accounts[0].addr]; sender == nil || sender.Nonce == nil {
+			t.Fatalf("call %d sender nonce missing from the diff", i)
+		}
+	}
+	// The last call must have been executed on top of the first one
+	if have, want := results[2].StateDiff.Pre[accounts[1].addr].Balance.ToInt(), results[0].StateDiff.Post[accounts[1].addr].Balance.ToInt(); have.Cmp(want) != 0 {
+		t.Fatalf("calls not chained: have pre balance %v, want %v", have, want)
+	}
+	if _, err := api.CallMany(context.Background(), nil, &latest, nil, nil); err == nil {
+		t.Fatal("empty call list not rejected")
+	}
+}
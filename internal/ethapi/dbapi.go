@@ -21,6 +21,21 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
+// maxDbKeys is the maximum number of keys returned by DbKeys.
+const maxDbKeys = 1024
+
+// DbKey is a key stored in the database, along with the size of its value.
+type DbKey struct {
+	Key  hexutil.Bytes `json:"key"`
+	Size int           `json:"size"`
+}
+
+// DbKeyRange is a page of the keys stored in the database.
+type DbKeyRange struct {
+	Keys []DbKey       `json:"keys"`
+	Next hexutil.Bytes `json:"next"` // Start of the next page after the prefix, nil if none left
+}
+
 // DbGet returns the raw value of a key stored in the database.
 func (api *DebugAPI) DbGet(key string) (hexutil.Bytes, error) {
 	blob, err := common.ParseHexOrString(key)
@@ -30,6 +45,27 @@ func (api *DebugAPI) DbGet(key string) (hexutil.Bytes, error) {
 	return api.b.ChainDb().Get(blob)
 }
 
+// DbKeys returns the keys with the given prefix stored in the database from the
+// given start, which excludes the prefix, in lexicographic order, at most limit
+// or 1024 of them. The values are not returned, only their size.
+func (api *DebugAPI) DbKeys(prefix hexutil.Bytes, start hexutil.Bytes, limit int) (*DbKeyRange, error) {
+	if limit <= 0 || limit > maxDbKeys {
+		limit = maxDbKeys
+	}
+	it := api.b.ChainDb().NewIterator(prefix, start)
+	defer it.Release()
+
+	result := &DbKeyRange{Keys: []DbKey{}}
+	for it.Next() {
+		if len(result.Keys) == limit {
+			result.Next = common.CopyBytes(it.Key()[len(prefix):])
+			break
+		}
+		result.Keys = append(result.Keys, DbKey{Key: common.CopyBytes(it.Key()), Size: len(it.Value())})
+	}
+	return result, it.Error()
+}
+
 // DbAncient retrieves an ancient binary blob from the append-only immutable files.
 // It is a mapping to the `AncientReaderOp.Ancient` method
 func (api *DebugAPI) DbAncient(kind string, number uint64) (hexutil.Bytes, error) {
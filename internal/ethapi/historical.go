@@ -0,0 +1,95 @@
+package ethapi
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// HistoricalBackend is the part of the API backends routing requests to the
+// historical endpoint.
+type HistoricalBackend interface {
+	ChainConfig() *params.ChainConfig
+	ChainDb() ethdb.Database
+	HistoricalRPCService() *rpc.Client
+}
+
+// HistoricalClient returns the client serving the given block from the historical
+// endpoint, nil if the block is served locally. Pre-Bedrock blocks fail with
+// rpc.ErrNoHistoricalFallback if no historical endpoint is configured, while the
+// blocks whose history was pruned locally are routed only if one is.
+func HistoricalClient(b HistoricalBackend, number uint64) (*rpc.Client, error) {
+	client := b.HistoricalRPCService()
+	if b.ChainConfig().IsOptimismPreBedrock(new(big.Int).SetUint64(number)) {
+		if client == nil {
+			return nil, rpc.ErrNoHistoricalFallback
+		}
+		return client, nil
+	}
+	if client == nil {
+		return nil, nil
+	}
+	if tail, err := b.ChainDb().Tail(); err == nil && number < tail {
+		return client, nil
+	}
+	return nil, nil
+}
+
+// historicalNamespaceMeters records the requests routed to the historical
+// endpoint per namespace.
+func historicalNamespaceMeters(method string) (success, failure metrics.Meter) {
+	namespace, _, ok := strings.Cut(method, "_")
+	if !ok {
+		namespace = "unknown"
+	}
+	success = metrics.GetOrRegisterMeter("rollup/historical/"+namespace+"/success", nil)
+	failure = metrics.GetOrRegisterMeter("rollup/historical/"+namespace+"/failure", nil)
+	return success, failure
+}
+
+// TranslateHistoricalTrace converts a trace produced by the historical endpoint
+// to the schema of the local tracers. The struct logs of the legacy tracers carry
+// their stack items as unprefixed 32 byte words, which are converted to hex
+// quantities. Other traces are returned unchanged.
+func TranslateHistoricalTrace(result json.RawMessage) json.RawMessage {
+	var trace map[string]json.RawMessage
+	if err := json.Unmarshal(result, &trace); err != nil || trace["structLogs"] == nil {
+		return result
+	}
+	var logs []map[string]json.RawMessage
+	if err := json.Unmarshal(trace["structLogs"], &logs); err != nil {
+		return result
+	}
+	for _, entry := range logs {
+		var stack []string
+		if entry["stack"] == nil || json.Unmarshal(entry["stack"], &stack) != nil {
+			continue
+		}
+		for i, item := range stack {
+			if strings.HasPrefix(item, "0x") {
+				continue
+			}
+			if value, ok := new(big.Int).SetString(item, 16); ok {
+				stack[i] = hexutil.EncodeBig(value)
+			}
+		}
+		entry["stack"], _ = json.Marshal(stack)
+	}
+	logsJSON, err := json.Marshal(logs)
+	if err != nil {
+		return result
+	}
+	trace["structLogs"] = logsJSON
+
+	translated, err := json.Marshal(trace)
+	if err != nil {
+		return result
+	}
+	return translated
+}
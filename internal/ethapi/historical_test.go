@@ -0,0 +1,39 @@
+package ethapi
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTranslateHistoricalTrace(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		// Legacy struct logs carry unprefixed 32 byte stack words
+		{
+			input: `{"failed":false,"gas":21000,"returnValue":"","structLogs":[{"op":"PUSH1","stack":["0000000000000000000000000000000000000000000000000000000000000080","0000000000000000000000000000000000000000000000000000000000000000"]}]}`,
+			want:  `{"failed":false,"gas":21000,"returnValue":"","structLogs":[{"op":"PUSH1","stack":["0x80","0x0"]}]}`,
+		},
+		// Struct logs without stack are left untouched
+		{
+			input: `{"failed":false,"gas":21000,"returnValue":"","structLogs":[{"op":"STOP"}]}`,
+			want:  `{"failed":false,"gas":21000,"returnValue":"","structLogs":[{"op":"STOP"}]}`,
+		},
+		// Other tracers are returned unchanged
+		{
+			input: `{"type":"CALL","from":"0x0000000000000000000000000000000000000001","gas":"0x5208"}`,
+			want:  `{"type":"CALL","from":"0x0000000000000000000000000000000000000001","gas":"0x5208"}`,
+		},
+		{
+			input: `"0x1234"`,
+			want:  `"0x1234"`,
+		},
+	}
+	for i, tt := range tests {
+		have := TranslateHistoricalTrace(json.RawMessage(tt.input))
+		if string(have) != tt.want {
+			t.Errorf("test %d: translation mismatch\nhave %s\nwant %s", i, have, tt.want)
+		}
+	}
+}
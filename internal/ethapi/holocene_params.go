@@ -0,0 +1,72 @@
+package ethapi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+)
+
+// Holocene1559Params describes EIP-1559 parameters in the encodings used by the
+// Holocene payload attributes and block header extraData.
+type Holocene1559Params struct {
+	Denominator   hexutil.Uint64 `json:"denominator"`
+	Elasticity    hexutil.Uint64 `json:"elasticity"`
+	EIP1559Params hexutil.Bytes  `json:"eip1559Params"`
+	ExtraData     hexutil.Bytes  `json:"extraData"`
+}
+
+func newHolocene1559Params(denominator, elasticity uint64) *Holocene1559Params {
+	return &Holocene1559Params{
+		Denominator:   hexutil.Uint64(denominator),
+		Elasticity:    hexutil.Uint64(elasticity),
+		EIP1559Params: eip1559.EncodeHolocene1559Params(uint32(denominator), uint32(elasticity)),
+		ExtraData:     eip1559.EncodeHoloceneExtraData(uint32(denominator), uint32(elasticity)),
+	}
+}
+
+// EncodeHolocene1559Params encodes the given EIP-1559 denominator and elasticity
+// into the Holocene eip1559Params and extraData formats.
+func (api *RollupAPI) EncodeHolocene1559Params(denominator hexutil.Uint64, elasticity hexutil.Uint64) (*Holocene1559Params, error) {
+	if denominator > math.MaxUint32 || elasticity > math.MaxUint32 {
+		return nil, errors.New("denominator and elasticity must fit in 32 bits")
+	}
+	params := newHolocene1559Params(uint64(denominator), uint64(elasticity))
+	if err := eip1559.ValidateHolocene1559Params(params.EIP1559Params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// DecodeHolocene1559Params decodes either the 8-byte eip1559Params of the payload
+// attributes, or the 9-byte extraData of a block header.
+func (api *RollupAPI) DecodeHolocene1559Params(data hexutil.Bytes) (*Holocene1559Params, error) {
+	var denominator, elasticity uint64
+	switch len(data) {
+	case 8:
+		if err := eip1559.ValidateHolocene1559Params(data); err != nil {
+			return nil, err
+		}
+		denominator, elasticity = eip1559.DecodeHolocene1559Params(data)
+	case 9:
+		if err := eip1559.ValidateHoloceneExtraData(data); err != nil {
+			return nil, err
+		}
+		denominator, elasticity = eip1559.DecodeHoloceneExtraData(data)
+	default:
+		return nil, fmt.Errorf("expected 8-byte eip1559Params or 9-byte extraData, got %d bytes", len(data))
+	}
+	return newHolocene1559Params(denominator, elasticity), nil
+}
+
+// ValidateHolocene1559Params checks the given eip1559Params as the Holocene fork
+// validates the payload attributes, returning true or the reason of the failure.
+// Zero for both values is accepted, selecting the parameters of the chain config.
+func (api *RollupAPI) ValidateHolocene1559Params(params hexutil.Bytes) (bool, error) {
+	if err := eip1559.ValidateHolocene1559Params(params); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,88 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RPCL1BlockInfo is the L1 origin information of an L2 block, as carried by its
+// L1 attributes deposit.
+type RPCL1BlockInfo struct {
+	Number         hexutil.Uint64 `json:"number"`
+	Hash           common.Hash    `json:"hash"`
+	Time           hexutil.Uint64 `json:"timestamp"`
+	BaseFee        *hexutil.Big   `json:"baseFee"`
+	SequenceNumber hexutil.Uint64 `json:"sequenceNumber"`
+	BatcherAddress common.Address `json:"batcherAddress"`
+	L1FeeOverhead  *hexutil.Big   `json:"l1FeeOverhead"`
+	L1FeeScalar    *hexutil.Big   `json:"l1FeeScalar"`
+
+	OperatorFeeScalar   *hexutil.Uint64 `json:"operatorFeeScalar,omitempty"`
+	OperatorFeeConstant *hexutil.Uint64 `json:"operatorFeeConstant,omitempty"`
+}
+
+// operatorFee is the operator fee parameters the transactions of a block are
+// charged with.
+type operatorFee struct {
+	Scalar   hexutil.Uint64
+	Constant hexutil.Uint64
+}
+
+// operatorFeeAt returns the operator fee parameters of the given block, read from
+// the L1Block predeploy where the L1 attributes deposit starting the block sets
+// them. Nil is returned before the operator fee fork, or if the state of the
+// block is not available.
+func operatorFeeAt(ctx context.Context, b Backend, header *types.Header) *operatorFee {
+	if !b.ChainConfig().IsOasysOperatorFee(header.Time) {
+		return nil
+	}
+	statedb, _, err := b.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithHash(header.Hash(), false))
+	if statedb == nil || err != nil {
+		return nil
+	}
+	scalar, constant := types.ExtractOperatorFeeParams(statedb.GetState(types.L1BlockAddr, types.OperatorFeeParamsSlot))
+	return &operatorFee{Scalar: hexutil.Uint64(scalar), Constant: hexutil.Uint64(constant)}
+}
+
+// GetL1BlockInfo returns the L1 origin information of the given L2 block, decoded
+// from the L1 attributes deposit it starts with.
+func (api *RollupAPI) GetL1BlockInfo(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*RPCL1BlockInfo, error) {
+	block, err := api.b.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	if api.b.ChainConfig().IsOptimismPreBedrock(block.Number()) {
+		return nil, errors.New("pre-bedrock blocks carry no L1 attributes deposit")
+	}
+	txs := block.Transactions()
+	if len(txs) == 0 || !txs[0].IsDepositTx() {
+		return nil, errors.New("block does not start with an L1 attributes deposit")
+	}
+	info, err := types.ParseL1BlockInfo(txs[0].Data())
+	if err != nil {
+		return nil, err
+	}
+	result := newRPCL1BlockInfo(info)
+	if fee := operatorFeeAt(ctx, api.b, block.Header()); fee != nil {
+		result.OperatorFeeScalar, result.OperatorFeeConstant = &fee.Scalar, &fee.Constant
+	}
+	return result, nil
+}
+
+func newRPCL1BlockInfo(info *types.L1BlockInfo) *RPCL1BlockInfo {
+	return &RPCL1BlockInfo{
+		Number:         hexutil.Uint64(info.Number),
+		Hash:           info.BlockHash,
+		Time:           hexutil.Uint64(info.Time),
+		BaseFee:        (*hexutil.Big)(info.BaseFee),
+		SequenceNumber: hexutil.Uint64(info.SequenceNumber),
+		BatcherAddress: common.BytesToAddress(info.BatcherHash.Bytes()),
+		L1FeeOverhead:  (*hexutil.Big)(info.L1FeeOverhead),
+		L1FeeScalar:    (*hexutil.Big)(info.L1FeeScalar),
+	}
+}
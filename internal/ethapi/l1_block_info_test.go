@@ -0,0 +1,117 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestGetL1BlockInfo(t *testing.T) {
+	var (
+		config = *params.TestChainConfig
+		info   = &types.L1BlockInfo{
+			Number:         100,
+			Time:           1_700_000_000,
+			BaseFee:        big.NewInt(params.GWei),
+			BlockHash:      common.Hash{0x01},
+			SequenceNumber: 3,
+			BatcherHash:    common.BytesToHash(common.Address{0xba}.Bytes()),
+			L1FeeOverhead:  big.NewInt(188),
+			L1FeeScalar:    big.NewInt(684_000),
+		}
+	)
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 2, EIP1559Denominator: 8}
+	config.BedrockBlock = big.NewInt(0)
+
+	// Encode the L1 attributes as passed to setL1BlockValues
+	data := []byte{0x01, 0x5d, 0x8e, 0xb9}
+	for _, arg := range []*big.Int{
+		new(big.Int).SetUint64(info.Number), new(big.Int).SetUint64(info.Time), info.BaseFee, info.BlockHash.Big(),
+		new(big.Int).SetUint64(info.SequenceNumber), info.BatcherHash.Big(), info.L1FeeOverhead, info.L1FeeScalar,
+	} {
+		data = append(data, common.LeftPadBytes(arg.Bytes(), 32)...)
+	}
+	genesis := &core.Genesis{Config: &config, Alloc: core.GenesisAlloc{}}
+	backend := newTestBackend(t, 2, genesis, ethash.NewFaker(), func(i int, b *core.BlockGen) {
+		// Only the first block starts with an L1 attributes deposit
+		if i == 0 {
+			b.AddTx(types.NewTx(&types.DepositTx{
+				From: common.Address{0xde, 0xad},
+				To:   &types.L1BlockAddr,
+				Gas:  1_000_000,
+				Data: data,
+			}))
+		}
+	})
+	api := NewRollupAPI(backend)
+
+	result, err := api.GetL1BlockInfo(context.Background(), rpc.BlockNumberOrHashWithNumber(1))
+	if err != nil {
+		t.Fatalf("failed to get L1 block info: %v", err)
+	}
+	want := newRPCL1BlockInfo(info)
+	if result.Number != want.Number || result.Hash != want.Hash || result.Time != want.Time || result.SequenceNumber != want.SequenceNumber ||
+		result.BatcherAddress != (common.Address{0xba}) || result.BaseFee.ToInt().Cmp(info.BaseFee) != 0 ||
+		result.L1FeeOverhead.ToInt().Cmp(info.L1FeeOverhead) != 0 || result.L1FeeScalar.ToInt().Cmp(info.L1FeeScalar) != 0 {
+		t.Fatalf("L1 block info mismatch: have %+v, want %+v", result, want)
+	}
+	// Blocks not starting with an L1 attributes deposit are rejected
+	if _, err := api.GetL1BlockInfo(context.Background(), rpc.BlockNumberOrHashWithNumber(2)); err == nil {
+		t.Fatal("L1 block info decoded from a block without deposit")
+	}
+	// Pre-bedrock blocks carry no L1 attributes
+	config.BedrockBlock = big.NewInt(10)
+	if _, err := api.GetL1BlockInfo(context.Background(), rpc.BlockNumberOrHashWithNumber(1)); err == nil {
+		t.Fatal("L1 block info decoded from a pre-bedrock block")
+	}
+}
+
+func TestOperatorFeeAt(t *testing.T) {
+	var (
+		config = *params.TestChainConfig
+		fork   = uint64(15)
+	)
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 2, EIP1559Denominator: 8}
+	config.OasysOperatorFeeTime = &fork
+
+	genesis := &core.Genesis{
+		Config: &config,
+		Alloc: core.GenesisAlloc{
+			types.L1BlockAddr: {
+				Balance: new(big.Int),
+				Storage: map[common.Hash]common.Hash{types.OperatorFeeParamsSlot: types.OperatorFeeParamsHash(1_500_000, 42)},
+			},
+		},
+	}
+	backend := newTestBackend(t, 2, genesis, ethash.NewFaker(), nil)
+
+	// Blocks before the fork report no operator fee
+	if fee := operatorFeeAt(context.Background(), backend, backend.chain.GetHeaderByNumber(1)); fee != nil {
+		t.Fatalf("operator fee reported before the fork: %+v", fee)
+	}
+	fee := operatorFeeAt(context.Background(), backend, backend.chain.GetHeaderByNumber(2))
+	if fee == nil || fee.Scalar != 1_500_000 || fee.Constant != 42 {
+		t.Fatalf("operator fee mismatch: have %+v, want scalar 1500000 constant 42", fee)
+	}
+	// The parameters are reported along with the receipts of the other transactions
+	var (
+		signer  = types.LatestSigner(&config)
+		tx      = types.NewTransaction(0, common.Address{}, new(big.Int), 21000, big.NewInt(1), nil)
+		deposit = types.NewTx(&types.DepositTx{})
+	)
+	fields := marshalReceipt(&types.Receipt{FeeScalar: new(big.Float)}, common.Hash{}, 2, signer, tx, 1, &config, fee)
+	if fields["operatorFeeScalar"] != fee.Scalar || fields["operatorFeeConstant"] != fee.Constant {
+		t.Fatalf("receipt operator fee mismatch: have %v %v", fields["operatorFeeScalar"], fields["operatorFeeConstant"])
+	}
+	fields = marshalReceipt(&types.Receipt{}, common.Hash{}, 2, signer, deposit, 0, &config, fee)
+	if _, ok := fields["operatorFeeScalar"]; ok {
+		t.Fatalf("operator fee reported for a deposit")
+	}
+}
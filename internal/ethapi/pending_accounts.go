@@ -0,0 +1,55 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxPendingAccountStates is the maximum number of accounts queried by a single
+// oasys_getPendingAccountStates call.
+const maxPendingAccountStates = 10000
+
+// PendingAccountState is the state of an account at the pending block, combined
+// with its transactions in the pool.
+type PendingAccountState struct {
+	Address common.Address `json:"address"`
+	Nonce   hexutil.Uint64 `json:"nonce"`   // Next nonce, accounting for the pooled transactions
+	Balance *hexutil.Big   `json:"balance"` // Balance at the pending block
+	Pending hexutil.Uint   `json:"pending"` // Number of executable pooled transactions
+	Queued  hexutil.Uint   `json:"queued"`  // Number of non-executable pooled transactions
+}
+
+// GetPendingAccountStates returns the pending nonce and balance, and the number
+// of pooled transactions of the given accounts, in the same order.
+func (api *RollupAPI) GetPendingAccountStates(ctx context.Context, addresses []common.Address) ([]*PendingAccountState, error) {
+	if len(addresses) > maxPendingAccountStates {
+		return nil, fmt.Errorf("too many addresses: %d, limit %d", len(addresses), maxPendingAccountStates)
+	}
+	state, _, err := api.b.StateAndHeaderByNumber(ctx, rpc.PendingBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	results := make([]*PendingAccountState, len(addresses))
+	for i, addr := range addresses {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		nonce, err := api.b.GetPoolNonce(ctx, addr)
+		if err != nil {
+			return nil, err
+		}
+		pending, queued := api.b.TxPoolContentFrom(addr)
+		results[i] = &PendingAccountState{
+			Address: addr,
+			Nonce:   hexutil.Uint64(nonce),
+			Balance: (*hexutil.Big)(state.GetBalance(addr)),
+			Pending: hexutil.Uint(len(pending)),
+			Queued:  hexutil.Uint(len(queued)),
+		}
+	}
+	return results, state.Error()
+}
@@ -0,0 +1,56 @@
+package ethapi
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ReceiptOptions are the optional settings of eth_getTransactionReceipt.
+type ReceiptOptions struct {
+	// Confirmation annotates the receipt with the confirmation level of the
+	// containing block and its L1 origin.
+	Confirmation bool `json:"confirmation"`
+}
+
+// confirmationLevel returns the label of the most advanced head the block with
+// the given number is included in.
+func confirmationLevel(number uint64, safe, finalized *types.Header) string {
+	switch {
+	case finalized != nil && number <= finalized.Number.Uint64():
+		return core.HeadFinalized
+	case safe != nil && number <= safe.Number.Uint64():
+		return core.HeadSafe
+	default:
+		return core.HeadUnsafe
+	}
+}
+
+// annotateConfirmation adds the confirmation level of the given canonical block
+// and its L1 origin to the receipt fields. The L1 origin is null if the block
+// carries no L1 attributes deposit.
+func annotateConfirmation(ctx context.Context, b Backend, fields map[string]interface{}, header *types.Header) error {
+	// Safe and finalized heads are unknown until signalled by the rollup node
+	safe, _ := b.HeaderByNumber(ctx, rpc.SafeBlockNumber)
+	finalized, _ := b.HeaderByNumber(ctx, rpc.FinalizedBlockNumber)
+	fields["confirmation"] = confirmationLevel(header.Number.Uint64(), safe, finalized)
+
+	var origin *RPCL1BlockInfo
+	if b.ChainConfig().IsOptimismBedrock(header.Number) {
+		block, err := b.BlockByHash(ctx, header.Hash())
+		if err != nil {
+			return err
+		}
+		if block != nil {
+			if txs := block.Transactions(); len(txs) > 0 && txs[0].IsDepositTx() {
+				if info, err := types.ParseL1BlockInfo(txs[0].Data()); err == nil {
+					origin = newRPCL1BlockInfo(info)
+				}
+			}
+		}
+	}
+	fields["l1Origin"] = origin
+	return nil
+}
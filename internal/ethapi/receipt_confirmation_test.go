@@ -0,0 +1,35 @@
+package ethapi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestConfirmationLevel(t *testing.T) {
+	var (
+		safe      = &types.Header{Number: big.NewInt(20)}
+		finalized = &types.Header{Number: big.NewInt(10)}
+	)
+	tests := []struct {
+		number    uint64
+		safe      *types.Header
+		finalized *types.Header
+		want      string
+	}{
+		{5, safe, finalized, core.HeadFinalized},
+		{10, safe, finalized, core.HeadFinalized},
+		{11, safe, finalized, core.HeadSafe},
+		{20, safe, finalized, core.HeadSafe},
+		{21, safe, finalized, core.HeadUnsafe},
+		{5, nil, nil, core.HeadUnsafe},
+		{5, safe, nil, core.HeadSafe},
+	}
+	for i, tt := range tests {
+		if have := confirmationLevel(tt.number, tt.safe, tt.finalized); have != tt.want {
+			t.Errorf("test %d: confirmation level mismatch: have %s, want %s", i, have, tt.want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package ethapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	historicalRPCSuccessMeter = metrics.NewRegisteredMeter("rollup/historical/success", nil)
+	historicalRPCFailureMeter = metrics.NewRegisteredMeter("rollup/historical/failure", nil)
+	historicalRPCTimer        = metrics.NewRegisteredTimer("rollup/historical/duration", nil)
+)
+
+// CallHistorical forwards a request for pre-Bedrock or locally pruned data to the
+// historical RPC backend, recording the fallback in the rollup metrics, both in
+// total and per namespace.
+func CallHistorical(ctx context.Context, client *rpc.Client, result interface{}, method string, args ...interface{}) error {
+	start := time.Now()
+	err := client.CallContext(ctx, result, method, args...)
+	historicalRPCTimer.UpdateSince(start)
+
+	success, failure := historicalNamespaceMeters(method)
+	if err != nil {
+		historicalRPCFailureMeter.Mark(1)
+		failure.Mark(1)
+	} else {
+		historicalRPCSuccessMeter.Mark(1)
+		success.Mark(1)
+	}
+	return err
+}
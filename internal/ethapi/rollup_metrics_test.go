@@ -0,0 +1,58 @@
+package ethapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testHistoricalService is a historical endpoint serving a single method.
+type testHistoricalService struct{}
+
+func (s *testHistoricalService) Ping() string { return "pong" }
+
+func TestCallHistoricalMetrics(t *testing.T) {
+	enabled, success, failure := metrics.Enabled, historicalRPCSuccessMeter, historicalRPCFailureMeter
+	metrics.Enabled = true
+	historicalRPCSuccessMeter, historicalRPCFailureMeter = metrics.NewMeter(), metrics.NewMeter()
+	defer func() {
+		historicalRPCSuccessMeter.Stop()
+		historicalRPCFailureMeter.Stop()
+		metrics.Enabled, historicalRPCSuccessMeter, historicalRPCFailureMeter = enabled, success, failure
+	}()
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("histtest", new(testHistoricalService)); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	var res string
+	if err := CallHistorical(context.Background(), client, &res, "histtest_ping"); err != nil || res != "pong" {
+		t.Fatalf("historical call failed: %q, %v", res, err)
+	}
+	if err := CallHistorical(context.Background(), client, &res, "histtest_missing"); err == nil {
+		t.Fatal("call of a missing method succeeded")
+	}
+	if err := CallHistorical(context.Background(), client, &res, "histtest_missing"); err == nil {
+		t.Fatal("call of a missing method succeeded")
+	}
+	for name, want := range map[string]int64{
+		"rollup/historical/histtest/success": 1,
+		"rollup/historical/histtest/failure": 2,
+	} {
+		if n := metrics.GetOrRegisterMeter(name, nil).Snapshot().Count(); n != want {
+			t.Errorf("%s: have %d, want %d", name, n, want)
+		}
+	}
+	if n := historicalRPCSuccessMeter.Snapshot().Count(); n != 1 {
+		t.Errorf("historical successes: have %d, want 1", n)
+	}
+	if n := historicalRPCFailureMeter.Snapshot().Count(); n != 2 {
+		t.Errorf("historical failures: have %d, want 2", n)
+	}
+}
@@ -53,6 +53,10 @@ type TransactionArgs struct {
 	// Introduced by AccessListTxType transaction.
 	AccessList *types.AccessList `json:"accessList,omitempty"`
 	ChainID    *hexutil.Big      `json:"chainId,omitempty"`
+
+	// Deposit context of simulated calls, set through the state override of the
+	// sender.
+	deposit *DepositOverride
 }
 
 // from retrieves the transaction sender address.
@@ -276,6 +280,13 @@ func (args *TransactionArgs) ToMessage(globalGasCap uint64, baseFee *big.Int) (*
 		AccessList:        accessList,
 		SkipAccountChecks: true,
 	}
+	if args.deposit != nil {
+		msg.IsDepositTx = true
+		msg.IsSystemTx = args.deposit.IsSystemTx
+		if args.deposit.Mint != nil {
+			msg.Mint = args.deposit.Mint.ToInt()
+		}
+	}
 	return msg, nil
 }
 
@@ -0,0 +1,115 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Verdicts of the admission checks of the pool, re-evaluated against the head.
+const (
+	verdictFeeCapBelowBaseFee  = "feeCapBelowBaseFee"
+	verdictBelowGovernedMinTip = "belowGovernedMinTip"
+	verdictNonceGap            = "nonceGap"
+)
+
+// TxPoolFilter selects the pooled transactions by sender and destination. Unset
+// fields match any transaction.
+type TxPoolFilter struct {
+	From *common.Address `json:"from"`
+	To   *common.Address `json:"to"`
+}
+
+// matches reports whether the transaction sent by the given account is selected
+// by the filter, which may be nil.
+func (f *TxPoolFilter) matches(from common.Address, tx *types.Transaction) bool {
+	if f == nil {
+		return true
+	}
+	if f.From != nil && *f.From != from {
+		return false
+	}
+	if f.To != nil && (tx.To() == nil || *tx.To() != *f.To) {
+		return false
+	}
+	return true
+}
+
+// RPCPoolTransaction is a pooled transaction with the details used to debug its
+// inclusion by the sequencer.
+type RPCPoolTransaction struct {
+	*RPCTransaction
+	L1Fee      *hexutil.Big   `json:"l1Fee,omitempty"` // Estimated L1 fee at the head, nil if not applicable
+	TimeInPool hexutil.Uint64 `json:"timeInPool"`      // Seconds since the transaction entered the pool
+	Verdicts   []string       `json:"verdicts,omitempty"`
+}
+
+// ContentDetailed returns the transactions of the pool selected by the optional
+// filter, with their estimated L1 fee, time in the pool and the verdicts of the
+// admission checks which would currently hold them back.
+func (s *TxPoolAPI) ContentDetailed(ctx context.Context, filter *TxPoolFilter) (map[string]map[string]map[string]*RPCPoolTransaction, error) {
+	statedb, head, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	var (
+		config  = s.b.ChainConfig()
+		costFn  = types.NewL1CostFunc(config, statedb)
+		minTip  = types.GovernedMinTip(config, statedb, head.Time)
+		now     = time.Now()
+		content = map[string]map[string]map[string]*RPCPoolTransaction{
+			"pending": make(map[string]map[string]*RPCPoolTransaction),
+			"queued":  make(map[string]map[string]*RPCPoolTransaction),
+		}
+	)
+	detail := func(from common.Address, tx *types.Transaction, queued bool) (*RPCPoolTransaction, error) {
+		result := &RPCPoolTransaction{
+			RPCTransaction: NewRPCPendingTransaction(tx, head, config),
+			TimeInPool:     hexutil.Uint64(now.Sub(tx.Time()) / time.Second),
+		}
+		if fee := costFn(head.Number.Uint64(), head.Time, tx.RollupDataGas(), tx.IsDepositTx()); fee != nil {
+			result.L1Fee = (*hexutil.Big)(fee)
+		}
+		if head.BaseFee != nil && tx.GasFeeCapIntCmp(head.BaseFee) < 0 {
+			result.Verdicts = append(result.Verdicts, verdictFeeCapBelowBaseFee)
+		}
+		if minTip != nil && tx.EffectiveGasTipIntCmp(minTip, head.BaseFee) < 0 {
+			result.Verdicts = append(result.Verdicts, verdictBelowGovernedMinTip)
+		}
+		if queued {
+			nonce, err := s.b.GetPoolNonce(ctx, from)
+			if err != nil {
+				return nil, err
+			}
+			if tx.Nonce() > nonce {
+				result.Verdicts = append(result.Verdicts, verdictNonceGap)
+			}
+		}
+		return result, nil
+	}
+	pending, queue := s.b.TxPoolContent()
+	for kind, accounts := range map[string]map[common.Address][]*types.Transaction{"pending": pending, "queued": queue} {
+		for account, txs := range accounts {
+			dump := make(map[string]*RPCPoolTransaction)
+			for _, tx := range txs {
+				if !filter.matches(account, tx) {
+					continue
+				}
+				result, err := detail(account, tx, kind == "queued")
+				if err != nil {
+					return nil, err
+				}
+				dump[fmt.Sprintf("%d", tx.Nonce())] = result
+			}
+			if len(dump) > 0 {
+				content[kind][account.Hex()] = dump
+			}
+		}
+	}
+	return content, nil
+}
@@ -0,0 +1,38 @@
+package ethapi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestTxPoolFilter(t *testing.T) {
+	var (
+		from   = common.Address{0x01}
+		other  = common.Address{0x02}
+		to     = common.Address{0x03}
+		call   = types.NewTransaction(0, to, new(big.Int), 21000, new(big.Int), nil)
+		deploy = types.NewContractCreation(0, new(big.Int), 21000, new(big.Int), nil)
+	)
+	tests := []struct {
+		filter *TxPoolFilter
+		from   common.Address
+		tx     *types.Transaction
+		want   bool
+	}{
+		{nil, from, call, true},
+		{&TxPoolFilter{}, from, deploy, true},
+		{&TxPoolFilter{From: &from}, from, call, true},
+		{&TxPoolFilter{From: &from}, other, call, false},
+		{&TxPoolFilter{To: &to}, from, call, true},
+		{&TxPoolFilter{To: &to}, from, deploy, false},
+		{&TxPoolFilter{From: &other, To: &to}, from, call, false},
+	}
+	for i, tt := range tests {
+		if have := tt.filter.matches(tt.from, tt.tx); have != tt.want {
+			t.Errorf("test %d: match mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// MessagePassedTopic is the topic of the MessagePassed event emitted by the
+// L2ToL1MessagePasser for every withdrawal initiated.
+var MessagePassedTopic = crypto.Keccak256Hash([]byte("MessagePassed(uint256,address,address,uint256,uint256,bytes,bytes32)"))
+
+// WithdrawalTransaction mirrors Types.WithdrawalTransaction of the L1 contracts.
+type WithdrawalTransaction struct {
+	Nonce    *hexutil.Big   `json:"nonce"`
+	Sender   common.Address `json:"sender"`
+	Target   common.Address `json:"target"`
+	Value    *hexutil.Big   `json:"value"`
+	GasLimit *hexutil.Big   `json:"gasLimit"`
+	Data     hexutil.Bytes  `json:"data"`
+}
+
+// Hash returns the withdrawal hash, as computed by Hashing.hashWithdrawal.
+func (w *WithdrawalTransaction) Hash() common.Hash {
+	enc := make([]byte, 0, 7*32+len(w.Data)+31)
+	enc = append(enc, common.BigToHash(w.Nonce.ToInt()).Bytes()...)
+	enc = append(enc, common.BytesToHash(w.Sender.Bytes()).Bytes()...)
+	enc = append(enc, common.BytesToHash(w.Target.Bytes()).Bytes()...)
+	enc = append(enc, common.BigToHash(w.Value.ToInt()).Bytes()...)
+	enc = append(enc, common.BigToHash(w.GasLimit.ToInt()).Bytes()...)
+	enc = append(enc, common.BigToHash(big.NewInt(6*32)).Bytes()...) // offset of data
+	enc = append(enc, common.BigToHash(big.NewInt(int64(len(w.Data)))).Bytes()...)
+	enc = append(enc, common.RightPadBytes(w.Data, (len(w.Data)+31)/32*32)...)
+	return crypto.Keccak256Hash(enc)
+}
+
+// OutputRootProof mirrors Types.OutputRootProof of the L1 contracts.
+type OutputRootProof struct {
+	Version                  common.Hash `json:"version"`
+	StateRoot                common.Hash `json:"stateRoot"`
+	MessagePasserStorageRoot common.Hash `json:"messagePasserStorageRoot"`
+	LatestBlockhash          common.Hash `json:"latestBlockhash"`
+}
+
+// Root returns the output root committed to by the proof.
+func (p *OutputRootProof) Root() common.Hash {
+	return crypto.Keccak256Hash(p.Version[:], p.StateRoot[:], p.MessagePasserStorageRoot[:], p.LatestBlockhash[:])
+}
+
+// WithdrawalProof contains the arguments of OptimismPortal.proveWithdrawalTransaction
+// for a withdrawal, besides the index of the L2 output which is only known on L1.
+type WithdrawalProof struct {
+	Withdrawal      WithdrawalTransaction `json:"withdrawal"`
+	WithdrawalHash  common.Hash           `json:"withdrawalHash"`
+	StorageSlot     common.Hash           `json:"storageSlot"`
+	BlockNumber     hexutil.Uint64        `json:"blockNumber"` // L2 block the output root is derived from
+	OutputRoot      common.Hash           `json:"outputRoot"`
+	OutputRootProof OutputRootProof       `json:"outputRootProof"`
+	WithdrawalProof []string              `json:"withdrawalProof"`
+}
+
+// RollupAPI provides rollup specific helpers under the oasys namespace.
+type RollupAPI struct {
+	b Backend
+}
+
+// NewRollupAPI creates a new RollupAPI instance.
+func NewRollupAPI(b Backend) *RollupAPI {
+	return &RollupAPI{b}
+}
+
+// GetWithdrawalProof returns the proof of the index-th withdrawal initiated by the
+// given transaction against the output root of the given block, formatted for
+// OptimismPortal.proveWithdrawalTransaction. The block needs to be the one of the
+// L2 output proposed on L1 the withdrawal is proven with.
+func (api *RollupAPI) GetWithdrawalProof(ctx context.Context, txHash common.Hash, index hexutil.Uint, blockNrOrHash rpc.BlockNumberOrHash) (*WithdrawalProof, error) {
+	tx, blockHash, blockNumber, txIndex, err := api.b.GetTransaction(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, errors.New("transaction not found")
+	}
+	receipts, err := api.b.GetReceipts(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(receipts)) <= txIndex {
+		return nil, errors.New("transaction receipt not found")
+	}
+	var withdrawals []*WithdrawalTransaction
+	for _, log := range receipts[txIndex].Logs {
+		if log.Address != params.OptimismL2ToL1MessagePasser || len(log.Topics) != 4 || log.Topics[0] != MessagePassedTopic {
+			continue
+		}
+		withdrawal, err := ParseMessagePassed(log)
+		if err != nil {
+			return nil, err
+		}
+		withdrawals = append(withdrawals, withdrawal)
+	}
+	if int(index) >= len(withdrawals) {
+		return nil, fmt.Errorf("withdrawal %d not found, transaction initiated %d", index, len(withdrawals))
+	}
+	withdrawal := withdrawals[index]
+
+	statedb, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	if header.Number.Uint64() < blockNumber {
+		return nil, fmt.Errorf("block %d precedes withdrawal block %d", header.Number.Uint64(), blockNumber)
+	}
+	// The withdrawal is recorded in the sentMessages mapping at slot 0
+	var (
+		hash    = withdrawal.Hash()
+		slot    = crypto.Keccak256Hash(hash[:], common.Hash{}.Bytes())
+		address = params.OptimismL2ToL1MessagePasser
+	)
+	if statedb.GetState(address, slot) == (common.Hash{}) {
+		return nil, fmt.Errorf("withdrawal %x not found in state of block %d", hash, header.Number.Uint64())
+	}
+	storageRoot := statedb.GetStorageRoot(address)
+	id := trie.StorageTrieID(header.Root, crypto.Keccak256Hash(address.Bytes()), storageRoot)
+	storageTrie, err := trie.NewStateTrie(id, statedb.Database().TrieDB())
+	if err != nil {
+		return nil, err
+	}
+	var proof proofList
+	if err := storageTrie.Prove(crypto.Keccak256(slot.Bytes()), &proof); err != nil {
+		return nil, err
+	}
+	outputRootProof := OutputRootProof{
+		StateRoot:                header.Root,
+		MessagePasserStorageRoot: storageRoot,
+		LatestBlockhash:          header.Hash(),
+	}
+	return &WithdrawalProof{
+		Withdrawal:      *withdrawal,
+		WithdrawalHash:  hash,
+		StorageSlot:     slot,
+		BlockNumber:     hexutil.Uint64(header.Number.Uint64()),
+		OutputRoot:      outputRootProof.Root(),
+		OutputRootProof: outputRootProof,
+		WithdrawalProof: proof,
+	}, statedb.Error()
+}
+
+// ParseMessagePassed decodes a MessagePassed event into the withdrawal it was
+// emitted for, checking the withdrawal hash it contains.
+func ParseMessagePassed(log *types.Log) (*WithdrawalTransaction, error) {
+	// Non-indexed fields: value, gasLimit, offset of data, withdrawalHash, data
+	if len(log.Data) < 5*32 {
+		return nil, errors.New("invalid MessagePassed event")
+	}
+	offset := new(big.Int).SetBytes(log.Data[64:96])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(log.Data))-32 {
+		return nil, errors.New("invalid MessagePassed event data offset")
+	}
+	start := offset.Uint64() + 32
+	size := new(big.Int).SetBytes(log.Data[start-32 : start])
+	if !size.IsUint64() || size.Uint64() > uint64(len(log.Data))-start {
+		return nil, errors.New("invalid MessagePassed event data length")
+	}
+	withdrawal := &WithdrawalTransaction{
+		Nonce:    (*hexutil.Big)(log.Topics[1].Big()),
+		Sender:   common.BytesToAddress(log.Topics[2].Bytes()),
+		Target:   common.BytesToAddress(log.Topics[3].Bytes()),
+		Value:    (*hexutil.Big)(new(big.Int).SetBytes(log.Data[:32])),
+		GasLimit: (*hexutil.Big)(new(big.Int).SetBytes(log.Data[32:64])),
+		Data:     common.CopyBytes(log.Data[start : start+size.Uint64()]),
+	}
+	if hash := common.BytesToHash(log.Data[96:128]); hash != withdrawal.Hash() {
+		return nil, fmt.Errorf("withdrawal hash mismatch: event %x, computed %x", hash, withdrawal.Hash())
+	}
+	return withdrawal, nil
+}
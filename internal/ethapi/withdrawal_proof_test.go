@@ -0,0 +1,59 @@
+package ethapi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestParseMessagePassed(t *testing.T) {
+	withdrawal := &WithdrawalTransaction{
+		Nonce:    (*hexutil.Big)(big.NewInt(7)),
+		Sender:   common.Address{0x01},
+		Target:   common.Address{0x02},
+		Value:    (*hexutil.Big)(big.NewInt(1000)),
+		GasLimit: (*hexutil.Big)(big.NewInt(100000)),
+		Data:     hexutil.Bytes{0xde, 0xad, 0xbe, 0xef},
+	}
+	hash := withdrawal.Hash()
+
+	var data []byte
+	data = append(data, common.BigToHash(withdrawal.Value.ToInt()).Bytes()...)
+	data = append(data, common.BigToHash(withdrawal.GasLimit.ToInt()).Bytes()...)
+	data = append(data, common.BigToHash(big.NewInt(4*32)).Bytes()...)
+	data = append(data, hash.Bytes()...)
+	data = append(data, common.BigToHash(big.NewInt(int64(len(withdrawal.Data)))).Bytes()...)
+	data = append(data, common.RightPadBytes(withdrawal.Data, 32)...)
+
+	log := &types.Log{
+		Address: params.OptimismL2ToL1MessagePasser,
+		Topics: []common.Hash{
+			MessagePassedTopic,
+			common.BigToHash(withdrawal.Nonce.ToInt()),
+			common.BytesToHash(withdrawal.Sender.Bytes()),
+			common.BytesToHash(withdrawal.Target.Bytes()),
+		},
+		Data: data,
+	}
+	have, err := ParseMessagePassed(log)
+	if err != nil {
+		t.Fatalf("failed to parse event: %v", err)
+	}
+	if have.Hash() != hash || have.Sender != withdrawal.Sender || have.Target != withdrawal.Target || string(have.Data) != string(withdrawal.Data) {
+		t.Fatalf("withdrawal mismatch: have %+v, want %+v", have, withdrawal)
+	}
+	// Events whose hash does not match the withdrawal are rejected
+	log.Data[3*32] ^= 0xff
+	if _, err := ParseMessagePassed(log); err == nil {
+		t.Error("event with invalid withdrawal hash accepted")
+	}
+	// Truncated events are rejected
+	log.Data = log.Data[:5*32]
+	if _, err := ParseMessagePassed(log); err == nil {
+		t.Error("truncated event accepted")
+	}
+}
@@ -42,7 +42,7 @@ func (s *DirectoryString) String() string {
 }
 
 func (s *DirectoryString) Set(value string) error {
-	*s = DirectoryString(expandPath(value))
+	*s = DirectoryString(ExpandPath(value))
 	return nil
 }
 
@@ -346,7 +346,7 @@ func GlobalBig(ctx *cli.Context, name string) *big.Int {
 // 2. expands embedded environment variables
 // 3. cleans the path, e.g. /a/b/../c -> /a/c
 // Note, it has limitations, e.g. ~someuser/tmp will not be expanded
-func expandPath(p string) string {
+func ExpandPath(p string) string {
 	// Named pipes are not file paths on windows, ignore
 	if strings.HasPrefix(p, `\\.\pipe`) {
 		return p
@@ -0,0 +1,329 @@
+// Package payloadfuzz implements a differential fuzzer of the payload validation
+// of the engine API. It generates randomized payloads on top of the head of the
+// local node, around the fork boundaries of the chain, and compares the outcome
+// of engine_newPayload on the local node and on a reference implementation.
+package payloadfuzz
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Config configures a fuzzing run.
+type Config struct {
+	Local       *rpc.Client         // Engine API of the node under test
+	Reference   *rpc.Client         // Engine API of the reference implementation
+	ChainConfig *params.ChainConfig // Chain configuration shared by both nodes
+	Iterations  int                 // Number of payloads to generate
+	Seed        int64               // Seed of the payload generation
+}
+
+// Outcome is the result of a newPayload call.
+type Outcome struct {
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// conclusive reports whether the outcome is a validity verdict, as opposed to
+// the node lacking the data to judge the payload.
+func (o Outcome) conclusive() bool {
+	return o.Error != "" || o.Status == engine.VALID || o.Status == engine.INVALID
+}
+
+func (o Outcome) matches(other Outcome) bool {
+	if (o.Error != "") != (other.Error != "") {
+		return false
+	}
+	return o.Error != "" || o.Status == other.Status
+}
+
+// Mismatch is a payload the local node and the reference disagree on.
+type Mismatch struct {
+	Mutations []string               `json:"mutations"`
+	Payload   *engine.ExecutableData `json:"payload"`
+	Local     Outcome                `json:"local"`
+	Reference Outcome                `json:"reference"`
+}
+
+// Report summarizes a fuzzing run.
+type Report struct {
+	Payloads     int         `json:"payloads"`
+	Agreed       int         `json:"agreed"`
+	Inconclusive int         `json:"inconclusive"` // Payloads either node could not judge
+	Mismatches   []*Mismatch `json:"mismatches"`
+}
+
+// Run generates the configured number of payloads on top of the head of the
+// local node and submits each one to both nodes, reporting the disagreements.
+func Run(ctx context.Context, cfg *Config) (*Report, error) {
+	var parent *types.Header
+	if err := cfg.Local.CallContext(ctx, &parent, "eth_getBlockByNumber", "latest", false); err != nil {
+		return nil, fmt.Errorf("failed to retrieve local head: %w", err)
+	}
+	if parent == nil {
+		return nil, errors.New("local head not found")
+	}
+	var (
+		gen    = newGenerator(cfg.ChainConfig, parent, rand.New(rand.NewSource(cfg.Seed)))
+		report = new(Report)
+	)
+	for i := 0; i < cfg.Iterations; i++ {
+		payload, beaconRoot, mutations := gen.next()
+
+		local := newPayload(ctx, cfg.Local, cfg.ChainConfig, payload, beaconRoot)
+		reference := newPayload(ctx, cfg.Reference, cfg.ChainConfig, payload, beaconRoot)
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+		report.Payloads++
+		switch {
+		case !local.conclusive() || !reference.conclusive():
+			report.Inconclusive++
+		case local.matches(reference):
+			report.Agreed++
+		default:
+			report.Mismatches = append(report.Mismatches, &Mismatch{
+				Mutations: mutations,
+				Payload:   payload,
+				Local:     local,
+				Reference: reference,
+			})
+		}
+	}
+	return report, nil
+}
+
+// newPayload submits the payload with the engine API version matching its fork.
+func newPayload(ctx context.Context, client *rpc.Client, config *params.ChainConfig, payload *engine.ExecutableData, beaconRoot *common.Hash) Outcome {
+	var (
+		status engine.PayloadStatusV1
+		err    error
+	)
+	if config.IsCancun(new(big.Int).SetUint64(payload.Number), payload.Timestamp) {
+		err = client.CallContext(ctx, &status, "engine_newPayloadV3", payload, []common.Hash{}, beaconRoot)
+	} else {
+		err = client.CallContext(ctx, &status, "engine_newPayloadV2", payload)
+	}
+	if err != nil {
+		return Outcome{Error: err.Error()}
+	}
+	return Outcome{Status: status.Status}
+}
+
+// generator produces randomized payloads building on top of a parent header.
+type generator struct {
+	config     *params.ChainConfig
+	parent     *types.Header
+	rand       *rand.Rand
+	boundaries []uint64 // Fork and zero-fee window timestamps after the parent
+}
+
+func newGenerator(config *params.ChainConfig, parent *types.Header, rand *rand.Rand) *generator {
+	g := &generator{config: config, parent: parent, rand: rand}
+	for _, time := range []*uint64{
+		config.ShanghaiTime, config.CancunTime, config.RegolithTime, config.CanyonTime,
+		config.OasysOperatorFeeTime, config.JovianTime, config.InteropTime,
+	} {
+		if time != nil && *time > parent.Time {
+			g.boundaries = append(g.boundaries, *time)
+		}
+	}
+	for _, time := range config.ZeroFeeTimes {
+		if time > parent.Time {
+			g.boundaries = append(g.boundaries, time)
+		}
+	}
+	sort.Slice(g.boundaries, func(i, j int) bool { return g.boundaries[i] < g.boundaries[j] })
+	return g
+}
+
+// next generates a payload with random mutations of a well-formed empty block,
+// returning it along with its parent beacon root and the applied mutations.
+func (g *generator) next() (*engine.ExecutableData, *common.Hash, []string) {
+	var mutations []string
+
+	// Pick the timestamp first as it selects the rules of the block
+	timestamp := g.parent.Time + 1 + uint64(g.rand.Intn(12))
+	if len(g.boundaries) > 0 && g.rand.Intn(2) == 0 {
+		boundary := g.boundaries[g.rand.Intn(len(g.boundaries))]
+		timestamp = boundary - 1 + uint64(g.rand.Intn(3))
+		if timestamp <= g.parent.Time {
+			timestamp = boundary
+		}
+		mutations = append(mutations, fmt.Sprintf("timestamp=boundary%+d", int64(timestamp)-int64(boundary)))
+	}
+	number := new(big.Int).Add(g.parent.Number, common.Big1)
+	payload := &engine.ExecutableData{
+		ParentHash:    g.parent.Hash(),
+		StateRoot:     g.parent.Root,
+		ReceiptsRoot:  types.EmptyReceiptsHash,
+		LogsBloom:     make([]byte, types.BloomByteLength),
+		Number:        number.Uint64(),
+		GasLimit:      g.parent.GasLimit,
+		Timestamp:     timestamp,
+		ExtraData:     []byte{},
+		BaseFeePerGas: eip1559.CalcBaseFee(g.config, g.parent, timestamp),
+		Transactions:  [][]byte{},
+	}
+	g.rand.Read(payload.Random[:])
+	g.rand.Read(payload.FeeRecipient[:])
+
+	if g.config.IsShanghai(number, timestamp) {
+		payload.Withdrawals = []*types.Withdrawal{}
+	}
+	var beaconRoot *common.Hash
+	if g.config.IsCancun(number, timestamp) {
+		var excess uint64
+		if g.config.IsCancun(g.parent.Number, g.parent.Time) && g.parent.ExcessBlobGas != nil && g.parent.BlobGasUsed != nil {
+			excess = eip4844.CalcExcessBlobGas(*g.parent.ExcessBlobGas, *g.parent.BlobGasUsed)
+		}
+		used := uint64(0)
+		payload.ExcessBlobGas, payload.BlobGasUsed = &excess, &used
+		beaconRoot = new(common.Hash)
+		g.rand.Read(beaconRoot[:])
+	}
+	// Apply a random subset of the field mutations
+	for _, mutate := range []func(*engine.ExecutableData) string{
+		g.mutateExtraData,
+		g.mutateWithdrawals,
+		g.mutateBaseFee,
+		g.mutateGas,
+	} {
+		if g.rand.Intn(3) == 0 {
+			mutations = append(mutations, mutate(payload))
+		}
+	}
+	payload.BlockHash = payloadHash(payload, beaconRoot)
+	return payload, beaconRoot, mutations
+}
+
+// mutateExtraData sets an extraData of random length, or encoded like the
+// EIP-1559 parameters of Holocene: a zero version byte followed by the
+// denominator and elasticity.
+func (g *generator) mutateExtraData(payload *engine.ExecutableData) string {
+	if g.rand.Intn(2) == 0 {
+		extra := make([]byte, 9)
+		extra[0] = byte(g.rand.Intn(2))
+		binary.BigEndian.PutUint32(extra[1:5], uint32(g.rand.Intn(300)))
+		binary.BigEndian.PutUint32(extra[5:9], uint32(g.rand.Intn(20)))
+		payload.ExtraData = extra
+		return fmt.Sprintf("extraData=holocene(v%d)", extra[0])
+	}
+	payload.ExtraData = make([]byte, g.rand.Intn(34))
+	g.rand.Read(payload.ExtraData)
+	return fmt.Sprintf("extraData=len%d", len(payload.ExtraData))
+}
+
+// mutateWithdrawals toggles between nil, empty and non-empty withdrawals, which
+// also set the withdrawalsRoot of the header.
+func (g *generator) mutateWithdrawals(payload *engine.ExecutableData) string {
+	switch g.rand.Intn(3) {
+	case 0:
+		payload.Withdrawals = nil
+		return "withdrawals=nil"
+	case 1:
+		payload.Withdrawals = []*types.Withdrawal{}
+		return "withdrawals=empty"
+	default:
+		withdrawal := &types.Withdrawal{Index: g.rand.Uint64(), Validator: g.rand.Uint64(), Amount: uint64(g.rand.Intn(1000))}
+		g.rand.Read(withdrawal.Address[:])
+		payload.Withdrawals = []*types.Withdrawal{withdrawal}
+		return "withdrawals=one"
+	}
+}
+
+// mutateBaseFee sets a base fee off by one, zero or random, the former being
+// the expected one in zero-fee windows.
+func (g *generator) mutateBaseFee(payload *engine.ExecutableData) string {
+	switch g.rand.Intn(4) {
+	case 0:
+		payload.BaseFeePerGas = new(big.Int)
+		return "baseFee=zero"
+	case 1:
+		payload.BaseFeePerGas = new(big.Int).Add(payload.BaseFeePerGas, common.Big1)
+		return "baseFee=+1"
+	case 2:
+		if payload.BaseFeePerGas.Sign() > 0 {
+			payload.BaseFeePerGas = new(big.Int).Sub(payload.BaseFeePerGas, common.Big1)
+			return "baseFee=-1"
+		}
+		fallthrough
+	default:
+		payload.BaseFeePerGas = new(big.Int).SetUint64(g.rand.Uint64())
+		return "baseFee=random"
+	}
+}
+
+// mutateGas moves the gas limit around the bounds of its allowed change, or
+// sets a gas usage above it.
+func (g *generator) mutateGas(payload *engine.ExecutableData) string {
+	bound := g.parent.GasLimit / params.GasLimitBoundDivisor
+	switch g.rand.Intn(4) {
+	case 0:
+		payload.GasLimit = g.parent.GasLimit + bound
+		return "gasLimit=+bound"
+	case 1:
+		payload.GasLimit = g.parent.GasLimit - bound
+		return "gasLimit=-bound"
+	case 2:
+		payload.GasLimit = params.MinGasLimit - 1
+		return "gasLimit=belowMin"
+	default:
+		payload.GasUsed = payload.GasLimit + 1
+		return "gasUsed=aboveLimit"
+	}
+}
+
+// payloadHash computes the hash of the block the payload encodes, the same way
+// engine.ExecutableDataToBlock does, so that the mutated payloads are judged on
+// their contents rather than rejected for a hash mismatch.
+func payloadHash(payload *engine.ExecutableData, beaconRoot *common.Hash) common.Hash {
+	var txs types.Transactions
+	for _, blob := range payload.Transactions {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(blob); err == nil {
+			txs = append(txs, tx)
+		}
+	}
+	var withdrawalsRoot *common.Hash
+	if payload.Withdrawals != nil {
+		h := types.DeriveSha(types.Withdrawals(payload.Withdrawals), trie.NewStackTrie(nil))
+		withdrawalsRoot = &h
+	}
+	header := &types.Header{
+		ParentHash:       payload.ParentHash,
+		UncleHash:        types.EmptyUncleHash,
+		Coinbase:         payload.FeeRecipient,
+		Root:             payload.StateRoot,
+		TxHash:           types.DeriveSha(txs, trie.NewStackTrie(nil)),
+		ReceiptHash:      payload.ReceiptsRoot,
+		Bloom:            types.BytesToBloom(payload.LogsBloom),
+		Difficulty:       common.Big0,
+		Number:           new(big.Int).SetUint64(payload.Number),
+		GasLimit:         payload.GasLimit,
+		GasUsed:          payload.GasUsed,
+		Time:             payload.Timestamp,
+		BaseFee:          payload.BaseFeePerGas,
+		Extra:            payload.ExtraData,
+		MixDigest:        payload.Random,
+		WithdrawalsHash:  withdrawalsRoot,
+		ExcessBlobGas:    payload.ExcessBlobGas,
+		BlobGasUsed:      payload.BlobGasUsed,
+		ParentBeaconRoot: beaconRoot,
+	}
+	return header.Hash()
+}
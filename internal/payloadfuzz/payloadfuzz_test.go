@@ -0,0 +1,64 @@
+package payloadfuzz
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the generated payloads carry the hash of the block they encode,
+// so that the nodes judge them on their contents.
+func TestGeneratedPayloadHash(t *testing.T) {
+	var (
+		canyon      = uint64(10)
+		operatorFee = uint64(20)
+		config      = *params.OptimismTestConfig
+		parent      = &types.Header{Number: big.NewInt(5), Time: 8, GasLimit: 30_000_000, BaseFee: big.NewInt(params.InitialBaseFee), Difficulty: new(big.Int)}
+	)
+	config.CanyonTime, config.OasysOperatorFeeTime = &canyon, &operatorFee
+	config.Optimism = &params.OptimismConfig{EIP1559Elasticity: 50, EIP1559Denominator: 10, EIP1559DenominatorCanyon: 250}
+	config.ZeroFeeTimes = []uint64{15, 18}
+
+	gen := newGenerator(&config, parent, rand.New(rand.NewSource(1)))
+	if len(gen.boundaries) == 0 {
+		t.Fatal("no fork boundaries found")
+	}
+	for i := 0; i < 200; i++ {
+		payload, beaconRoot, mutations := gen.next()
+		if payload.ParentHash != parent.Hash() || payload.Timestamp <= parent.Time {
+			t.Fatalf("payload %d (%v): not a child of the parent", i, mutations)
+		}
+		if len(payload.ExtraData) > 32 {
+			continue // Rejected before the hash is checked
+		}
+		if _, err := engine.ExecutableDataToBlock(*payload, nil, beaconRoot); err != nil {
+			t.Fatalf("payload %d (%v): %v", i, mutations, err)
+		}
+	}
+}
+
+func TestOutcomeComparison(t *testing.T) {
+	tests := []struct {
+		a, b       Outcome
+		conclusive bool
+		match      bool
+	}{
+		{Outcome{Status: engine.VALID}, Outcome{Status: engine.VALID}, true, true},
+		{Outcome{Status: engine.VALID}, Outcome{Status: engine.INVALID}, true, false},
+		{Outcome{Error: "a"}, Outcome{Error: "b"}, true, true},
+		{Outcome{Error: "a"}, Outcome{Status: engine.INVALID}, true, false},
+		{Outcome{Status: engine.SYNCING}, Outcome{Status: engine.VALID}, false, false},
+	}
+	for i, tt := range tests {
+		if conclusive := tt.a.conclusive() && tt.b.conclusive(); conclusive != tt.conclusive {
+			t.Errorf("test %d: conclusive mismatch: have %v, want %v", i, conclusive, tt.conclusive)
+		}
+		if tt.conclusive && tt.a.matches(tt.b) != tt.match {
+			t.Errorf("test %d: match mismatch: have %v, want %v", i, !tt.match, tt.match)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package shutdowncheck
+
+import (
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	uncleanShutdownGauge    = metrics.NewRegisteredGauge("shutdown/unclean", nil)
+	recoveryRewoundGauge    = metrics.NewRegisteredGauge("shutdown/recovery/rewound", nil)
+	recoverySnapshotGauge   = metrics.NewRegisteredGauge("shutdown/recovery/snapshot", nil)
+	recoveryRemainingGauge  = metrics.NewRegisteredGauge("shutdown/recovery/remaining", nil)
+	recoveryEstimationGauge = metrics.NewRegisteredGauge("shutdown/recovery/estimate", nil)
+)
+
+// HeadMarker identifies a head block.
+type HeadMarker struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+}
+
+// JournalStatus describes the transaction journal left by the previous run.
+type JournalStatus struct {
+	Path     string     `json:"path"`
+	Exists   bool       `json:"exists"`
+	Size     int64      `json:"size"`
+	Modified *time.Time `json:"modified,omitempty"`
+}
+
+// RecoveryReport describes how the node is recovering from an unclean shutdown
+// of its previous run. Automation can use it to decide whether the node should
+// be kept out of rotation until it caught up.
+type RecoveryReport struct {
+	UncleanShutdowns  []time.Time    `json:"uncleanShutdowns"`
+	HeadBefore        HeadMarker     `json:"headBefore"`      // Head persisted by the previous run
+	HeadAfter         HeadMarker     `json:"headAfter"`       // Head after loading the chain
+	HeadCurrent       HeadMarker     `json:"headCurrent"`     // Head at the time of the report
+	RewoundBlocks     uint64         `json:"rewoundBlocks"`   // Blocks lost due to missing state
+	SnapshotRebuild   bool           `json:"snapshotRebuild"` // Whether the snapshot had to be regenerated
+	SnapshotReady     bool           `json:"snapshotReady"`   // Whether the snapshot is usable again
+	TxJournal         *JournalStatus `json:"txJournal,omitempty"`
+	Recovered         bool           `json:"recovered"`
+	EstimatedRecovery string         `json:"estimatedRecovery,omitempty"` // Based on the import rate since startup
+}
+
+// RecoveryChain is the chain whose recovery progress is tracked.
+type RecoveryChain interface {
+	CurrentBlock() *types.Header
+	Snapshots() *snapshot.Tree
+}
+
+// recoveryBase is the recovery state captured right after startup.
+type recoveryBase struct {
+	chain           RecoveryChain
+	headAfter       HeadMarker
+	snapshotRebuild bool
+	journal         *JournalStatus
+}
+
+// ReadJournalStatus inspects the transaction journal at the given path, which
+// is empty if journaling is disabled. It needs to be called before the pool
+// loads and regenerates the journal.
+func ReadJournalStatus(path string) *JournalStatus {
+	if path == "" {
+		return nil
+	}
+	status := &JournalStatus{Path: path}
+	if info, err := os.Stat(path); err == nil {
+		modified := info.ModTime()
+		status.Exists, status.Size, status.Modified = true, info.Size(), &modified
+	}
+	return status
+}
+
+// InspectRecovery captures the state of the freshly loaded chain along with the
+// transaction journal status if the previous run ended uncleanly, and logs a
+// summary. It must be called after MarkStartup.
+func (t *ShutdownTracker) InspectRecovery(chain RecoveryChain, journal *JournalStatus) {
+	if len(t.unclean) == 0 {
+		return
+	}
+	head := chain.CurrentBlock()
+	base := &recoveryBase{
+		chain:     chain,
+		headAfter: HeadMarker{Number: head.Number.Uint64(), Hash: head.Hash()},
+		journal:   journal,
+	}
+	if snaps := chain.Snapshots(); snaps != nil {
+		base.snapshotRebuild = snaps.Generating()
+	}
+	t.recovery = base
+
+	report := t.RecoveryReport()
+	log.Warn("Recovering from unclean shutdown", "before", report.HeadBefore.Number, "after", report.HeadAfter.Number,
+		"rewound", report.RewoundBlocks, "snapshotrebuild", report.SnapshotRebuild)
+}
+
+// RecoveryReport returns the current recovery state, or nil if the previous run
+// was shut down cleanly.
+func (t *ShutdownTracker) RecoveryReport() *RecoveryReport {
+	base := t.recovery
+	if base == nil {
+		return nil
+	}
+	current := base.chain.CurrentBlock()
+	report := &RecoveryReport{
+		UncleanShutdowns: t.unclean,
+		HeadBefore:       t.headBefore,
+		HeadAfter:        base.headAfter,
+		HeadCurrent:      HeadMarker{Number: current.Number.Uint64(), Hash: current.Hash()},
+		SnapshotRebuild:  base.snapshotRebuild,
+		SnapshotReady:    true,
+		TxJournal:        base.journal,
+	}
+	if t.headBefore.Number > base.headAfter.Number {
+		report.RewoundBlocks = t.headBefore.Number - base.headAfter.Number
+	}
+	if snaps := base.chain.Snapshots(); snaps != nil {
+		report.SnapshotReady = !snaps.Generating()
+	}
+	var remaining uint64
+	if t.headBefore.Number > report.HeadCurrent.Number {
+		remaining = t.headBefore.Number - report.HeadCurrent.Number
+	}
+	report.Recovered = remaining == 0 && report.SnapshotReady
+
+	// Extrapolate the time to reach the previous head from the import rate
+	var estimate time.Duration
+	if remaining > 0 && report.HeadCurrent.Number > base.headAfter.Number {
+		imported := report.HeadCurrent.Number - base.headAfter.Number
+		estimate = time.Duration(float64(time.Since(t.started)) / float64(imported) * float64(remaining))
+		report.EstimatedRecovery = common.PrettyDuration(estimate).String()
+	}
+	recoveryRewoundGauge.Update(int64(report.RewoundBlocks))
+	if report.SnapshotReady {
+		recoverySnapshotGauge.Update(0)
+	} else {
+		recoverySnapshotGauge.Update(1)
+	}
+	recoveryRemainingGauge.Update(int64(remaining))
+	recoveryEstimationGauge.Update(int64(estimate / time.Second))
+	return report
+}
+
+// readHeadMarker retrieves the head block persisted in the database.
+func readHeadMarker(db ethdb.Database) HeadMarker {
+	hash := rawdb.ReadHeadBlockHash(db)
+	if number := rawdb.ReadHeaderNumber(db, hash); number != nil {
+		return HeadMarker{Number: *number, Hash: hash}
+	}
+	return HeadMarker{Hash: hash}
+}
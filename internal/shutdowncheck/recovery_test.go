@@ -0,0 +1,89 @@
+package shutdowncheck
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type testChain struct {
+	head *types.Header
+}
+
+func (c *testChain) CurrentBlock() *types.Header { return c.head }
+func (c *testChain) Snapshots() *snapshot.Tree   { return nil }
+
+func TestRecoveryReport(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+
+	// Simulate a previous run crashing with its head at block 10
+	before := &types.Header{Number: big.NewInt(10)}
+	rawdb.WriteHeader(db, before)
+	rawdb.WriteHeadBlockHash(db, before.Hash())
+	if _, _, err := rawdb.PushUncleanShutdownMarker(db); err != nil {
+		t.Fatal(err)
+	}
+	tracker := NewShutdownTracker(db)
+	tracker.MarkStartup()
+
+	chain := &testChain{head: &types.Header{Number: big.NewInt(7)}}
+	tracker.InspectRecovery(chain, nil)
+
+	report := tracker.RecoveryReport()
+	if report == nil {
+		t.Fatal("no recovery report after an unclean shutdown")
+	}
+	if len(report.UncleanShutdowns) != 1 {
+		t.Fatalf("unclean shutdown count mismatch: have %d, want 1", len(report.UncleanShutdowns))
+	}
+	if report.HeadBefore.Number != 10 || report.HeadBefore.Hash != before.Hash() {
+		t.Fatalf("head before mismatch: have %+v", report.HeadBefore)
+	}
+	if report.HeadAfter.Number != 7 || report.RewoundBlocks != 3 || report.Recovered {
+		t.Fatalf("recovery mismatch: after %d, rewound %d, recovered %v", report.HeadAfter.Number, report.RewoundBlocks, report.Recovered)
+	}
+	// Import some blocks, the recovery time can be estimated
+	chain.head = &types.Header{Number: big.NewInt(8)}
+	if report := tracker.RecoveryReport(); report.Recovered || report.EstimatedRecovery == "" {
+		t.Fatalf("partial recovery mismatch: recovered %v, estimate %q", report.Recovered, report.EstimatedRecovery)
+	}
+	// Catch up with the previous head
+	chain.head = &types.Header{Number: big.NewInt(10)}
+	if report := tracker.RecoveryReport(); !report.Recovered || report.RewoundBlocks != 3 || report.EstimatedRecovery != "" {
+		t.Fatalf("complete recovery mismatch: recovered %v, rewound %d, estimate %q", report.Recovered, report.RewoundBlocks, report.EstimatedRecovery)
+	}
+}
+
+// Tests that no recovery is reported after a clean shutdown.
+func TestRecoveryReportClean(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+
+	tracker := NewShutdownTracker(db)
+	tracker.MarkStartup()
+	tracker.InspectRecovery(&testChain{head: &types.Header{Number: big.NewInt(0)}}, nil)
+
+	if report := tracker.RecoveryReport(); report != nil {
+		t.Fatalf("recovery reported after a clean shutdown: %+v", report)
+	}
+}
+
+func TestReadJournalStatus(t *testing.T) {
+	if status := ReadJournalStatus(""); status != nil {
+		t.Fatalf("journal status reported with journaling disabled: %+v", status)
+	}
+	path := filepath.Join(t.TempDir(), "transactions.rlp")
+	if status := ReadJournalStatus(path); status.Exists || status.Modified != nil {
+		t.Fatalf("missing journal reported: %+v", status)
+	}
+	if err := os.WriteFile(path, []byte{0x01, 0x02, 0x03}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if status := ReadJournalStatus(path); !status.Exists || status.Size != 3 || status.Modified == nil {
+		t.Fatalf("journal status mismatch: %+v", status)
+	}
+}
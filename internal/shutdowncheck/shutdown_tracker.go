@@ -31,14 +31,22 @@ import (
 type ShutdownTracker struct {
 	db     ethdb.Database
 	stopCh chan struct{}
+
+	started    time.Time     // Time the tracker was created, i.e. node startup
+	headBefore HeadMarker    // Head block persisted by the previous run
+	unclean    []time.Time   // Boot times of the runs which ended uncleanly
+	recovery   *recoveryBase // Recovery state captured at startup, nil if clean
 }
 
-// NewShutdownTracker creates a new ShutdownTracker instance and has
-// no other side-effect.
+// NewShutdownTracker creates a new ShutdownTracker instance. It records the head
+// block persisted by the previous run, so it needs to be created before the
+// chain is loaded, but has no other side-effect.
 func NewShutdownTracker(db ethdb.Database) *ShutdownTracker {
 	return &ShutdownTracker{
-		db:     db,
-		stopCh: make(chan struct{}),
+		db:         db,
+		stopCh:     make(chan struct{}),
+		started:    time.Now(),
+		headBefore: readHeadMarker(db),
 	}
 }
 
@@ -53,10 +61,12 @@ func (t *ShutdownTracker) MarkStartup() {
 			log.Warn("Old unclean shutdowns found", "count", discards)
 		}
 		for _, tstamp := range uncleanShutdowns {
-			t := time.Unix(int64(tstamp), 0)
-			log.Warn("Unclean shutdown detected", "booted", t,
-				"age", common.PrettyAge(t))
+			booted := time.Unix(int64(tstamp), 0)
+			log.Warn("Unclean shutdown detected", "booted", booted,
+				"age", common.PrettyAge(booted))
+			t.unclean = append(t.unclean, booted)
 		}
+		uncleanShutdownGauge.Update(int64(len(uncleanShutdowns)))
 	}
 }
 
@@ -69,6 +79,7 @@ func (t *ShutdownTracker) Start() {
 			select {
 			case <-ticker.C:
 				rawdb.UpdateUncleanShutdownMarker(t.db)
+				t.RecoveryReport() // refresh the recovery metrics
 			case <-t.stopCh:
 				return
 			}
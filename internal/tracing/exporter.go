@@ -0,0 +1,240 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	exportQueueSize    = 4096
+	exportBatchSize    = 512
+	exportInterval     = 5 * time.Second
+	exportTimeout      = 10 * time.Second
+	instrumentationLib = "github.com/ethereum/go-ethereum"
+)
+
+// Exporter batches finished spans and posts them to an OpenTelemetry collector
+// using the OTLP/HTTP JSON encoding. It implements node.Lifecycle.
+type Exporter struct {
+	endpoint string
+	service  string
+	client   *http.Client
+
+	queue chan *Span
+	quit  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewExporter creates an exporter posting to the given collector endpoint, e.g.
+// http://localhost:4318. Spans are tagged with the given service name.
+func NewExporter(endpoint, service string) *Exporter {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(endpoint, "/v1/traces") {
+		endpoint += "/v1/traces"
+	}
+	return &Exporter{
+		endpoint: endpoint,
+		service:  service,
+		client:   &http.Client{Timeout: exportTimeout},
+		queue:    make(chan *Span, exportQueueSize),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start enables span recording and launches the export loop.
+func (e *Exporter) Start() error {
+	if !active.CompareAndSwap(nil, e) {
+		return fmt.Errorf("tracing exporter already running")
+	}
+	e.wg.Add(1)
+	go e.loop()
+	log.Info("Started tracing exporter", "endpoint", e.endpoint, "service", e.service)
+	return nil
+}
+
+// Stop disables span recording and flushes the queued spans.
+func (e *Exporter) Stop() error {
+	active.CompareAndSwap(e, nil)
+	close(e.quit)
+	e.wg.Wait()
+	return nil
+}
+
+func (e *Exporter) enqueue(s *Span) {
+	select {
+	case e.queue <- s:
+	default: // Drop spans rather than block instrumented code paths
+	}
+}
+
+func (e *Exporter) loop() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	var batch []*Span
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.export(batch); err != nil {
+			log.Debug("Failed to export tracing spans", "spans", len(batch), "err", err)
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case s := <-e.queue:
+			if batch = append(batch, s); len(batch) >= exportBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.quit:
+			for {
+				select {
+				case s := <-e.queue:
+					batch = append(batch, s)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// OTLP/JSON encoding types, see opentelemetry-proto's trace/v1/trace.proto.
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Status            *otlpStatus    `json:"status,omitempty"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string                 `json:"key"`
+		Value map[string]interface{} `json:"value"`
+	}
+)
+
+func (e *Exporter) export(spans []*Span) error {
+	encoded := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		encoded = append(encoded, encodeSpan(s))
+	}
+	req := otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpKeyValue{
+			{Key: "service.name", Value: encodeValue(e.service)},
+		}},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: instrumentationLib},
+			Spans: encoded,
+		}},
+	}}}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	hreq.Header.Set("Content-Type", "application/json")
+	res, err := e.client.Do(hreq)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("collector returned %s", res.Status)
+	}
+	return nil
+}
+
+func encodeSpan(s *Span) otlpSpan {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	span := otlpSpan{
+		TraceID:           fmt.Sprintf("%x", s.context.trace),
+		SpanID:            fmt.Sprintf("%x", s.context.span),
+		Name:              s.name,
+		Kind:              s.kind,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+	}
+	if s.parent != (spanID{}) {
+		span.ParentSpanID = fmt.Sprintf("%x", s.parent)
+	}
+	for i := 0; i+1 < len(s.attrs); i += 2 {
+		span.Attributes = append(span.Attributes, otlpKeyValue{
+			Key:   fmt.Sprint(s.attrs[i]),
+			Value: encodeValue(s.attrs[i+1]),
+		})
+	}
+	if s.err != nil {
+		span.Status = &otlpStatus{Code: 2, Message: s.err.Error()}
+	}
+	return span
+}
+
+func encodeValue(v interface{}) map[string]interface{} {
+	switch v := v.(type) {
+	case bool:
+		return map[string]interface{}{"boolValue": v}
+	case int:
+		return map[string]interface{}{"intValue": strconv.FormatInt(int64(v), 10)}
+	case int64:
+		return map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+	case uint64:
+		return map[string]interface{}{"intValue": strconv.FormatUint(v, 10)}
+	case float64:
+		return map[string]interface{}{"doubleValue": v}
+	case time.Duration:
+		return map[string]interface{}{"intValue": strconv.FormatInt(int64(v), 10)}
+	case fmt.Stringer:
+		return map[string]interface{}{"stringValue": v.String()}
+	default:
+		return map[string]interface{}{"stringValue": fmt.Sprint(v)}
+	}
+}
@@ -0,0 +1,178 @@
+// Package tracing implements lightweight distributed tracing spans, propagated
+// through W3C trace context headers and exported to an OpenTelemetry collector
+// over OTLP/HTTP. Spans are only recorded while an exporter is running, so
+// instrumented code paths are free when tracing is disabled.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Span kinds as defined by OTLP.
+const (
+	KindInternal = 1
+	KindServer   = 2
+	KindClient   = 3
+)
+
+const traceparentHeader = "traceparent"
+
+// active is the running exporter, nil if tracing is disabled.
+var active atomic.Pointer[Exporter]
+
+type (
+	traceID [16]byte
+	spanID  [8]byte
+)
+
+// spanContext identifies a span, either a local one or a remote parent.
+type spanContext struct {
+	trace traceID
+	span  spanID
+}
+
+type spanContextKey struct{}
+
+// Span is a single timed operation within a trace. A nil span is valid and
+// ignores all calls, which is what Start returns when tracing is disabled.
+type Span struct {
+	exporter *Exporter
+	context  spanContext
+	parent   spanID
+	name     string
+	kind     int
+	start    time.Time
+
+	lock  sync.Mutex
+	end   time.Time
+	attrs []interface{}
+	err   error
+}
+
+// Enabled returns whether spans are currently being recorded.
+func Enabled() bool {
+	return active.Load() != nil
+}
+
+// Start creates a new internal span as a child of the span in ctx, if any,
+// returning a context carrying the new span. Attributes are given as
+// alternating keys and values, as with the log package.
+func Start(ctx context.Context, name string, attrs ...interface{}) (context.Context, *Span) {
+	return StartKind(ctx, name, KindInternal, attrs...)
+}
+
+// StartKind is like Start, but sets the kind of the span.
+func StartKind(ctx context.Context, name string, kind int, attrs ...interface{}) (context.Context, *Span) {
+	exporter := active.Load()
+	if exporter == nil {
+		return ctx, nil
+	}
+	span := &Span{
+		exporter: exporter,
+		name:     name,
+		kind:     kind,
+		start:    time.Now(),
+		attrs:    attrs,
+	}
+	if parent, ok := ctx.Value(spanContextKey{}).(spanContext); ok {
+		span.context.trace, span.parent = parent.trace, parent.span
+	} else {
+		rand.Read(span.context.trace[:])
+	}
+	rand.Read(span.context.span[:])
+	return context.WithValue(ctx, spanContextKey{}, span.context), span
+}
+
+// SetAttributes adds attributes, given as alternating keys and values.
+func (s *Span) SetAttributes(attrs ...interface{}) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// SetError marks the span as failed, if err is non-nil.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.err = err
+}
+
+// End completes the span and queues it for export.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	if !s.end.IsZero() {
+		s.lock.Unlock()
+		return
+	}
+	s.end = time.Now()
+	s.lock.Unlock()
+
+	s.exporter.enqueue(s)
+}
+
+// Extract returns a copy of ctx carrying the remote parent span described by the
+// W3C traceparent header, if present and valid.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	parent, ok := parseTraceparent(header.Get(traceparentHeader))
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, spanContextKey{}, parent)
+}
+
+// Inject sets the W3C traceparent header to the span carried by ctx, if any.
+func Inject(ctx context.Context, header http.Header) {
+	if sc, ok := ctx.Value(spanContextKey{}).(spanContext); ok {
+		header.Set(traceparentHeader, fmt.Sprintf("00-%x-%x-01", sc.trace, sc.span))
+	}
+}
+
+// parseTraceparent parses a version 00 W3C traceparent header value.
+func parseTraceparent(value string) (spanContext, bool) {
+	var sc spanContext
+
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 4 || parts[0] != "00" || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.trace[:], []byte(parts[1])); err != nil {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.span[:], []byte(parts[2])); err != nil {
+		return sc, false
+	}
+	if sc.trace == (traceID{}) || sc.span == (spanID{}) {
+		return sc, false
+	}
+	return sc, true
+}
+
+// Handler returns a http.Handler extracting the trace context of incoming
+// requests, so that spans created while serving them join the caller's trace.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if Enabled() {
+			r = r.WithContext(Extract(r.Context(), r.Header))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
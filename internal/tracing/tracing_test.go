@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestTraceparent(t *testing.T) {
+	tests := []struct {
+		value string
+		ok    bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01", false},
+		{"garbage", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if _, ok := parseTraceparent(tt.value); ok != tt.ok {
+			t.Errorf("%q: validity mismatch: have %v, want %v", tt.value, ok, tt.ok)
+		}
+	}
+}
+
+func TestSpanExport(t *testing.T) {
+	var (
+		lock     sync.Mutex
+		received []otlpSpan
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req otlpRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid export request: %v", err)
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				received = append(received, ss.Spans...)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	// Spans are not recorded while tracing is disabled
+	if _, span := Start(context.Background(), "disabled"); span != nil {
+		t.Fatal("span recorded with tracing disabled")
+	}
+	exporter := NewExporter(srv.URL, "test")
+	if err := exporter.Start(); err != nil {
+		t.Fatal(err)
+	}
+	header := make(http.Header)
+	header.Set(traceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx, parent := StartKind(Extract(context.Background(), header), "parent", KindServer)
+	_, child := Start(ctx, "child", "number", 1)
+	child.End()
+	parent.End()
+	exporter.Stop()
+
+	lock.Lock()
+	defer lock.Unlock()
+	if len(received) != 2 {
+		t.Fatalf("exported span count mismatch: have %d, want 2", len(received))
+	}
+	for _, span := range received {
+		if span.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("span %s: trace id mismatch: have %s", span.Name, span.TraceID)
+		}
+	}
+	if received[0].ParentSpanID != received[1].SpanID {
+		t.Errorf("child parent mismatch: have %s, want %s", received[0].ParentSpanID, received[1].SpanID)
+	}
+	if received[1].ParentSpanID != "00f067aa0ba902b7" {
+		t.Errorf("remote parent mismatch: have %s", received[1].ParentSpanID)
+	}
+}
@@ -31,6 +31,8 @@ var Modules = map[string]string{
 	"les":      LESJs,
 	"vflux":    VfluxJs,
 	"dev":      DevJs,
+	"oasys":    OasysJs,
+	"deposits": DepositsJs,
 }
 
 const CliqueJs = `
@@ -153,6 +155,61 @@ web3._extend({
 			call: 'admin_importChain',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'setRuntimeConfig',
+			call: 'admin_setRuntimeConfig',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'runtimeConfig',
+			call: 'admin_runtimeConfig',
+		}),
+		new web3._extend.Method({
+			name: 'recoveryReport',
+			call: 'admin_recoveryReport',
+		}),
+		new web3._extend.Method({
+			name: 'freeze',
+			call: 'admin_freeze',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'unfreeze',
+			call: 'admin_unfreeze',
+		}),
+		new web3._extend.Method({
+			name: 'freezeStatus',
+			call: 'admin_freezeStatus',
+		}),
+		new web3._extend.Method({
+			name: 'scheduleHalt',
+			call: 'admin_scheduleHalt',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'txIndexProgress',
+			call: 'admin_txIndexProgress',
+		}),
+		new web3._extend.Method({
+			name: 'setTxIndexLimit',
+			call: 'admin_setTxIndexLimit',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'reindexTransactions',
+			call: 'admin_reindexTransactions',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'verifyAncients',
+			call: 'admin_verifyAncients',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'ancientCheckStatus',
+			call: 'admin_ancientCheckStatus',
+		}),
 		new web3._extend.Method({
 			name: 'sleepBlocks',
 			call: 'admin_sleepBlocks',
@@ -190,6 +247,10 @@ web3._extend({
 			name: 'stopWS',
 			call: 'admin_stopWS'
 		}),
+		new web3._extend.Method({
+			name: 'reloadJwtSecret',
+			call: 'admin_reloadJWTSecret'
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -260,6 +321,12 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'dumpBlockFiltered',
+			call: 'debug_dumpBlockFiltered',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter, null]
+		}),
 		new web3._extend.Method({
 			name: 'chaindbProperty',
 			call: 'debug_chaindbProperty',
@@ -280,6 +347,16 @@ web3._extend({
 			call: 'debug_vmodule',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'setModuleVerbosity',
+			call: 'debug_setModuleVerbosity',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'moduleVerbosity',
+			call: 'debug_moduleVerbosity',
+			params: 0
+		}),
 		new web3._extend.Method({
 			name: 'backtraceAt',
 			call: 'debug_backtraceAt',
@@ -470,6 +547,11 @@ web3._extend({
 			call: 'debug_freezeClient',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'executionWitness',
+			call: 'debug_executionWitness',
+			params: 1,
+		}),
 		new web3._extend.Method({
 			name: 'getAccessibleState',
 			call: 'debug_getAccessibleState',
@@ -481,6 +563,11 @@ web3._extend({
 			call: 'debug_dbGet',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'dbKeys',
+			call: 'debug_dbKeys',
+			params: 3
+		}),
 		new web3._extend.Method({
 			name: 'dbAncient',
 			call: 'debug_dbAncient',
@@ -501,6 +588,47 @@ web3._extend({
 			call: 'debug_getTrieFlushInterval',
 			params: 0
 		}),
+		new web3._extend.Method({
+			name: 'gasProfile',
+			call: 'debug_gasProfile',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'inspectDatabase',
+			call: 'debug_inspectDatabase',
+		}),
+		new web3._extend.Method({
+			name: 'databaseStats',
+			call: 'debug_databaseStats',
+		}),
+		new web3._extend.Method({
+			name: 'altDAInput',
+			call: 'debug_altDAInput',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'kzgBackend',
+			call: 'debug_kzgBackend',
+		}),
+		new web3._extend.Method({
+			name: 'setKzgBackend',
+			call: 'debug_setKzgBackend',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'warmCacheStats',
+			call: 'debug_warmCacheStats',
+		}),
+		new web3._extend.Method({
+			name: 'snapshotGeneration',
+			call: 'debug_snapshotGeneration',
+		}),
+		new web3._extend.Method({
+			name: 'setSnapshotGenerationRate',
+			call: 'debug_setSnapshotGenerationRate',
+			params: 1,
+		}),
 	],
 	properties: []
 });
@@ -540,6 +668,18 @@ web3._extend({
 			inputFormatter: [web3._extend.formatters.inputCallFormatter, web3._extend.formatters.inputBlockNumberFormatter, null],
 			outputFormatter: web3._extend.utils.toDecimal
 		}),
+		new web3._extend.Method({
+			name: 'estimateGasBulk',
+			call: 'eth_estimateGasBulk',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionReceipts',
+			call: 'eth_getTransactionReceipts',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
 		new web3._extend.Method({
 			name: 'submitTransaction',
 			call: 'eth_submitTransaction',
@@ -580,6 +720,18 @@ web3._extend({
 			call: 'eth_getRawTransactionByHash',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getTransactionBySenderAndNonce',
+			call: 'eth_getTransactionBySenderAndNonce',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.utils.toHex]
+		}),
+		new web3._extend.Method({
+			name: 'callMany',
+			call: 'eth_callMany',
+			params: 4,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter, null, null]
+		}),
 		new web3._extend.Method({
 			name: 'getRawTransactionFromBlock',
 			call: function(args) {
@@ -804,6 +956,12 @@ web3._extend({
 			call: 'txpool_contentFrom',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'contentDetailed',
+			call: 'txpool_contentDetailed',
+			params: 1,
+			inputFormatter: [null]
+		}),
 	]
 });
 `
@@ -908,6 +1066,171 @@ web3._extend({
 			call: 'dev_setFeeRecipient',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'addDeposit',
+			call: 'dev_addDeposit',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'setGasLimit',
+			call: 'dev_setGasLimit',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'setNoTxPool',
+			call: 'dev_setNoTxPool',
+			params: 1
+		}),
 	],
 });
 `
+
+const OasysJs = `
+web3._extend({
+	property: 'oasys',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'health',
+			call: 'oasys_health',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'clientIdentity',
+			call: 'oasys_clientIdentity',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'protocolVersions',
+			call: 'oasys_protocolVersions',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'replicaStatus',
+			call: 'oasys_replicaStatus',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'pendingBlock',
+			call: 'oasys_pendingBlock',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'pendingTransactionsSince',
+			call: 'oasys_pendingTransactionsSince',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'inclusionReport',
+			call: 'oasys_inclusionReport',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'blockUsage',
+			call: 'oasys_blockUsage',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'nonceGaps',
+			call: 'oasys_nonceGaps',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'gasGovernor',
+			call: 'oasys_gasGovernor',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'forkRehearsals',
+			call: 'oasys_forkRehearsals',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'indexers',
+			call: 'oasys_indexers',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getOrderingAudit',
+			call: 'oasys_getOrderingAudit',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getWithdrawalProof',
+			call: 'oasys_getWithdrawalProof',
+			params: 3,
+			inputFormatter: [null, null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getWithdrawals',
+			call: 'oasys_getWithdrawals',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getL1BlockInfo',
+			call: 'oasys_getL1BlockInfo',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'encodeHolocene1559Params',
+			call: 'oasys_encodeHolocene1559Params',
+			params: 2,
+			inputFormatter: [web3._extend.utils.fromDecimal, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'decodeHolocene1559Params',
+			call: 'oasys_decodeHolocene1559Params',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'validateHolocene1559Params',
+			call: 'oasys_validateHolocene1559Params',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionStatus',
+			call: 'oasys_getTransactionStatus',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getPendingAccountStates',
+			call: 'oasys_getPendingAccountStates',
+			params: 1
+		}),
+	],
+});
+`
+
+const DepositsJs = `
+web3._extend({
+	property: 'deposits',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'getDeposits',
+			call: 'deposits_getDeposits',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getDeposit',
+			call: 'deposits_getDeposit',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getWithdrawals',
+			call: 'deposits_getWithdrawals',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+	],
+	properties: [
+		new web3._extend.Property({
+			name: 'syncStatus',
+			getter: 'deposits_syncStatus'
+		}),
+	]
+});
+`
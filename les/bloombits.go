@@ -17,9 +17,12 @@
 package les
 
 import (
+	"context"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/bitutil"
+	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/light"
 )
 
@@ -73,3 +76,67 @@ func (eth *LightEthereum) startBloomHandlers(sectionSize uint64) {
 		}()
 	}
 }
+
+// bloomLogIndex serves the log filters from the bloombits retrieved on demand.
+type bloomLogIndex struct {
+	backend *LesApiBackend
+}
+
+// IndexedRange implements filtermaps.LogIndex, returning the blocks of the
+// processed bloombits sections.
+func (idx *bloomLogIndex) IndexedRange() (uint64, uint64, bool) {
+	size, sections := idx.backend.BloomStatus()
+	if sections == 0 {
+		return 0, 0, false
+	}
+	return 0, size*sections - 1, true
+}
+
+// Candidates implements filtermaps.LogIndex, matching the bloombits of the range
+// against the filter.
+func (idx *bloomLogIndex) Candidates(ctx context.Context, begin, end uint64, addresses []common.Address, topics [][]common.Hash) ([]uint64, error) {
+	// Flatten the address and topic filter clauses into a single bloombits filter
+	// system. Since the bloombits are not positional, nil topics are permitted,
+	// which get flattened into a nil byte slice.
+	var filters [][][]byte
+	if len(addresses) > 0 {
+		filter := make([][]byte, len(addresses))
+		for i, address := range addresses {
+			filter[i] = address.Bytes()
+		}
+		filters = append(filters, filter)
+	}
+	for _, topicList := range topics {
+		filter := make([][]byte, len(topicList))
+		for i, topic := range topicList {
+			filter[i] = topic.Bytes()
+		}
+		filters = append(filters, filter)
+	}
+	size, _ := idx.backend.BloomStatus()
+
+	// Create a matcher session and request servicing from the backend
+	matches := make(chan uint64, 64)
+	session, err := bloombits.NewMatcher(size, filters).Start(ctx, begin, end, matches)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	idx.backend.ServiceFilter(ctx, session)
+
+	var blocks []uint64
+	for {
+		select {
+		case number, ok := <-matches:
+			// Abort if all matches have been fulfilled
+			if !ok {
+				return blocks, session.Error()
+			}
+			blocks = append(blocks, number)
+
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -47,7 +47,6 @@ const defaultConnectedBias = time.Minute * 3
 type ethBackend interface {
 	ArchiveMode() bool
 	BlockChain() *core.BlockChain
-	BloomIndexer() *core.ChainIndexer
 	ChainDb() ethdb.Database
 	Synced() bool
 	TxPool() *txpool.TxPool
@@ -56,12 +55,13 @@ type ethBackend interface {
 type LesServer struct {
 	lesCommons
 
-	archiveMode bool // Flag whether the ethereum node runs in archive mode.
-	handler     *serverHandler
-	peers       *clientPeerSet
-	serverset   *serverSet
-	vfluxServer *vfs.Server
-	privateKey  *ecdsa.PrivateKey
+	archiveMode  bool               // Flag whether the ethereum node runs in archive mode.
+	bloomIndexer *core.ChainIndexer // Bloom indexer feeding the bloom trie, the full node using the log index
+	handler      *serverHandler
+	peers        *clientPeerSet
+	serverset    *serverSet
+	vfluxServer  *vfs.Server
+	privateKey   *ecdsa.PrivateKey
 
 	// Flow control and capacity management
 	fcManager    *flowcontrol.ClientManager
@@ -102,6 +102,7 @@ func NewLesServer(node *node.Node, e ethBackend, config *ethconfig.Config) (*Les
 			closeCh:          make(chan struct{}),
 		},
 		archiveMode:  e.ArchiveMode(),
+		bloomIndexer: core.NewBloomIndexer(e.ChainDb(), params.BloomBitsBlocks, params.BloomConfirms),
 		peers:        newClientPeerSet(),
 		serverset:    newServerSet(),
 		vfluxServer:  vfs.NewServer(time.Millisecond * 10),
@@ -119,7 +120,7 @@ func NewLesServer(node *node.Node, e ethBackend, config *ethconfig.Config) (*Les
 	srv.costTracker, srv.minCapacity = newCostTracker(e.ChainDb(), config)
 
 	// Initialize the bloom trie indexer.
-	e.BloomIndexer().AddChildIndexer(srv.bloomTrieIndexer)
+	srv.bloomIndexer.AddChildIndexer(srv.bloomTrieIndexer)
 
 	// Initialize server capacity management fields.
 	srv.defParams = flowcontrol.ServerParams{
@@ -142,6 +143,7 @@ func NewLesServer(node *node.Node, e ethBackend, config *ethconfig.Config) (*Les
 	srv.clientPool.SetDefaultFactors(defaultPosFactors, defaultNegFactors)
 	srv.vfluxServer.Register(srv.clientPool, "les", "Ethereum light client service")
 	srv.chtIndexer.Start(e.BlockChain())
+	srv.bloomIndexer.Start(e.BlockChain())
 
 	node.RegisterProtocols(srv.Protocols())
 	node.RegisterAPIs(srv.APIs())
@@ -209,6 +211,9 @@ func (s *LesServer) Stop() error {
 	}
 
 	// Note, bloom trie indexer is closed by parent bloombits indexer.
+	if s.bloomIndexer != nil {
+		s.bloomIndexer.Close()
+	}
 	if s.chtIndexer != nil {
 		s.chtIndexer.Close()
 	}
@@ -191,6 +191,13 @@ func (t *odrTrie) Hash() common.Hash {
 	return t.trie.Hash()
 }
 
+func (t *odrTrie) Witness() map[string]struct{} {
+	if t.trie == nil {
+		return nil
+	}
+	return t.trie.Witness()
+}
+
 func (t *odrTrie) NodeIterator(startkey []byte) (trie.NodeIterator, error) {
 	return newNodeIterator(t, startkey), nil
 }
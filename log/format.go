@@ -46,6 +46,12 @@ func PrintOrigins(print bool) {
 	}
 }
 
+// EnableCallSites enables the storage of the log call sites, needed by handlers
+// routing the records by their origin.
+func EnableCallSites() {
+	stackEnabled.Store(true)
+}
+
 // stackEnabled is an atomic flag controlling whether the log handler needs
 // to store the callsite stack. This is needed in case any handler wants to
 // print locations (locationEnabled), use vmodule, or print full stacks (BacktraceAt).
@@ -19,9 +19,11 @@ package miner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -38,6 +40,10 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 )
 
+// ErrDraining is returned when a payload build is requested after the miner
+// started draining for shutdown.
+var ErrDraining = errors.New("miner is draining for shutdown")
+
 // Backend wraps all methods required for mining. Only full node is capable
 // to offer all the functions here.
 type Backend interface {
@@ -45,6 +51,12 @@ type Backend interface {
 	TxPool() *txpool.TxPool
 }
 
+// DAChecker checks the availability of the data the transactions commit to,
+// e.g. the inputs of alt-DA commitments, before they are included in a block.
+type DAChecker interface {
+	CheckAvailability(tx *types.Transaction) error
+}
+
 type BackendWithHistoricalState interface {
 	StateAtBlock(ctx context.Context, block *types.Block, reexec uint64, base *state.StateDB, readOnly bool, preferDisk bool) (*state.StateDB, tracers.StateReleaseFunc, error)
 }
@@ -60,7 +72,10 @@ type Config struct {
 
 	NewPayloadTimeout time.Duration // The maximum time allowance for creating a new payload
 
-	RollupComputePendingBlock bool // Compute the pending block from tx-pool, instead of copying the latest-block
+	RollupComputePendingBlock bool          // Compute the pending block from tx-pool, instead of copying the latest-block
+	RollupTxTimeBudget        time.Duration // Maximum execution time of a tx-pool transaction when building blocks (0 = unlimited)
+	RollupTxTimeBudgetEvict   bool          // Evict the transactions exceeding the execution time budget from the tx-pool
+	RollupOrderingAudit       bool          // Record the arrival time and ordering rationale of the transactions of built blocks
 }
 
 // DefaultConfig contains default settings for miner.
@@ -87,6 +102,10 @@ type Miner struct {
 	worker  *worker
 
 	wg sync.WaitGroup
+
+	drainLock  sync.RWMutex  // Orders payload builds against draining
+	draining   bool          // Whether new payload builds are refused
+	lastNumber atomic.Uint64 // Block number of the most recently built payload
 }
 
 func New(eth Backend, config *Config, chainConfig *params.ChainConfig, mux *event.TypeMux, engine consensus.Engine, isLocalBlock func(header *types.Header) bool) *Miner {
@@ -232,10 +251,31 @@ func (miner *Miner) PendingBlockAndReceipts() (*types.Block, types.Receipts) {
 	return miner.worker.pendingBlockAndReceipts()
 }
 
+// PendingSequenced returns the currently pending block along with its sequence
+// number, incremented on every update of the pending block. The returned block
+// can be nil in case the pending block is not initialized.
+func (miner *Miner) PendingSequenced() (uint64, *types.Block) {
+	return miner.worker.pendingSequenced()
+}
+
+// PendingSince returns the transactions added to the pending block since the
+// given sequence number, or all its transactions if the block was replaced in
+// the meantime. Nil is returned if the pending block is not initialized.
+func (miner *Miner) PendingSince(seq uint64) *PendingDelta {
+	return miner.worker.pendingSince(seq)
+}
+
 func (miner *Miner) SetEtherbase(addr common.Address) {
 	miner.worker.setEtherbase(addr)
 }
 
+// SetDAChecker sets the checker of the data availability of the transactions
+// pulled from the pool, those whose data is unavailable being left out of the
+// blocks built. A nil checker disables the checks.
+func (miner *Miner) SetDAChecker(checker DAChecker) {
+	miner.worker.setDAChecker(checker)
+}
+
 // SetGasCeil sets the gaslimit to strive for when mining blocks post 1559.
 // For pre-1559 blocks, it sets the ceiling.
 func (miner *Miner) SetGasCeil(ceil uint64) {
@@ -248,7 +288,41 @@ func (miner *Miner) SubscribePendingLogs(ch chan<- []*types.Log) event.Subscript
 	return miner.worker.pendingLogsFeed.Subscribe(ch)
 }
 
-// BuildPayload builds the payload according to the provided parameters.
-func (miner *Miner) BuildPayload(args *BuildPayloadArgs) (*Payload, error) {
-	return miner.worker.buildPayload(args)
+// BuildPayload builds the payload according to the provided parameters. The
+// building spans are recorded as children of the span in ctx, if any.
+func (miner *Miner) BuildPayload(ctx context.Context, args *BuildPayloadArgs) (*Payload, error) {
+	miner.drainLock.RLock()
+	defer miner.drainLock.RUnlock()
+
+	if miner.draining {
+		return nil, ErrDraining
+	}
+	payload, err := miner.worker.buildPayload(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	miner.lastNumber.Store(payload.empty.NumberU64())
+	return payload, nil
+}
+
+// Drain stops accepting new payload builds and waits up to the given timeout
+// for the payloads in flight to be delivered. It returns the block number of
+// the most recently built payload (0 if none) and whether all in-flight
+// payloads completed in time. Draining cannot be undone.
+func (miner *Miner) Drain(timeout time.Duration) (uint64, bool) {
+	miner.drainLock.Lock()
+	miner.draining = true
+	miner.drainLock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		miner.worker.payloads.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return miner.lastNumber.Load(), true
+	case <-time.After(timeout):
+		return miner.lastNumber.Load(), false
+	}
 }
@@ -88,6 +88,8 @@ type transactionsByPriceAndNonce struct {
 	heads   txByPriceAndTime                             // Next transaction for each unique account (price heap)
 	signer  types.Signer                                 // Signer for the set of transactions
 	baseFee *big.Int                                     // Current base fee
+
+	prioritized bool // Whether the set is prioritized over the others, for the ordering audits
 }
 
 // newTransactionsByPriceAndNonce creates a transaction set that can retrieve
@@ -126,6 +128,25 @@ func (t *transactionsByPriceAndNonce) Peek() *txpool.LazyTransaction {
 	return t.heads[0].tx
 }
 
+// PeekRationale returns the effective tip of the next transaction, and the reason
+// it is ordered next: prioritized set, highest tip, or earliest arrival among
+// the transactions tied on tip.
+func (t *transactionsByPriceAndNonce) PeekRationale() (*big.Int, string) {
+	if len(t.heads) == 0 {
+		return nil, ""
+	}
+	reason := OrderTip
+	for _, i := range []int{1, 2} { // runner-ups of the heap root
+		if i < len(t.heads) && t.heads[i].fees.Cmp(t.heads[0].fees) == 0 {
+			reason = OrderArrival
+		}
+	}
+	if t.prioritized {
+		reason = OrderPriority
+	}
+	return t.heads[0].fees, reason
+}
+
 // Shift replaces the current best head with the next one from the same account.
 func (t *transactionsByPriceAndNonce) Shift() {
 	acc := t.heads[0].from
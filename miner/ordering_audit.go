@@ -0,0 +1,106 @@
+package miner
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Reasons a transaction was placed at its position in a built block.
+const (
+	OrderForced   = "forced"   // Included by the payload attributes, ahead of the pool
+	OrderPriority = "priority" // Sent by a local account, prioritized over remote ones
+	OrderTip      = "tip"      // Highest effective tip among the executable transactions
+	OrderArrival  = "arrival"  // Tied on effective tip, first seen earliest
+)
+
+// BackendWithDatabase is implemented by the backends giving access to the chain
+// database, in which the ordering audits are stored.
+type BackendWithDatabase interface {
+	ChainDb() ethdb.Database
+}
+
+// OrderingEntry records the arrival time of a transaction included in a built
+// block and the rationale of its position.
+type OrderingEntry struct {
+	Hash    common.Hash    `json:"hash"`
+	From    common.Address `json:"from"`
+	Arrival time.Time      `json:"arrival"`       // Time the transaction was first seen by the node
+	Tip     *hexutil.Big   `json:"tip,omitempty"` // Effective tip the transaction was ordered by
+	Reason  string         `json:"reason"`
+}
+
+// OrderingAudit is the auditable record of the transaction ordering of a block
+// built by the node.
+type OrderingAudit struct {
+	BlockHash    common.Hash      `json:"blockHash"`
+	ParentHash   common.Hash      `json:"parentHash"`
+	Number       hexutil.Uint64   `json:"number"`
+	BuiltAt      time.Time        `json:"builtAt"`
+	BaseFee      *hexutil.Big     `json:"baseFee,omitempty"`
+	Transactions []*OrderingEntry `json:"transactions"`
+}
+
+// recordOrdering appends the ordering entry of a transaction committed to the
+// block being built.
+func (env *environment) recordOrdering(tx *types.Transaction, from common.Address, tip *big.Int, arrival time.Time, reason string) {
+	entry := &OrderingEntry{
+		Hash:    tx.Hash(),
+		From:    from,
+		Arrival: arrival,
+		Reason:  reason,
+	}
+	if tip != nil {
+		entry.Tip = (*hexutil.Big)(new(big.Int).Set(tip))
+	}
+	env.ordering = append(env.ordering, entry)
+}
+
+// writeOrderingAudit stores the ordering audit of a built block, if enabled and
+// the backend gives access to the chain database.
+func (w *worker) writeOrderingAudit(block *types.Block, env *environment) {
+	if !w.config.RollupOrderingAudit {
+		return
+	}
+	backend, ok := w.eth.(BackendWithDatabase)
+	if !ok {
+		return
+	}
+	audit := &OrderingAudit{
+		BlockHash:    block.Hash(),
+		ParentHash:   block.ParentHash(),
+		Number:       hexutil.Uint64(block.NumberU64()),
+		BuiltAt:      time.Now(),
+		Transactions: env.ordering,
+	}
+	if baseFee := block.BaseFee(); baseFee != nil {
+		audit.BaseFee = (*hexutil.Big)(baseFee)
+	}
+	blob, err := json.Marshal(audit)
+	if err != nil {
+		log.Warn("Failed to encode ordering audit", "hash", block.Hash(), "err", err)
+		return
+	}
+	rawdb.WriteOrderingAudit(backend.ChainDb(), block.Hash(), blob)
+}
+
+// ReadOrderingAudit retrieves the ordering audit of a block built by the node,
+// nil if none was recorded.
+func ReadOrderingAudit(db ethdb.KeyValueReader, hash common.Hash) (*OrderingAudit, error) {
+	blob := rawdb.ReadOrderingAudit(db, hash)
+	if len(blob) == 0 {
+		return nil, nil
+	}
+	audit := new(OrderingAudit)
+	if err := json.Unmarshal(blob, audit); err != nil {
+		return nil, err
+	}
+	return audit, nil
+}
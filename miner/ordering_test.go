@@ -20,6 +20,7 @@ import (
 	"crypto/ecdsa"
 	"math/big"
 	"math/rand"
+	"reflect"
 	"testing"
 	"time"
 
@@ -190,3 +191,41 @@ func TestTransactionTimeSort(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the ordering rationale reports whether a transaction is ordered by
+// tip or by arrival among the transactions tied on tip.
+func TestTransactionOrderingRationale(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	signer := types.HomesteadSigner{}
+
+	// Two transactions tied on price, and a cheaper one
+	groups := map[common.Address][]*txpool.LazyTransaction{}
+	for i, price := range []int64{2, 2, 1} {
+		tx, _ := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(100), 100, big.NewInt(price), nil), signer, keys[i])
+		tx.SetTime(time.Unix(0, int64(i)))
+
+		groups[crypto.PubkeyToAddress(keys[i].PublicKey)] = []*txpool.LazyTransaction{{
+			Hash:      tx.Hash(),
+			Tx:        tx,
+			Time:      tx.Time(),
+			GasFeeCap: tx.GasFeeCap(),
+			GasTipCap: tx.GasTipCap(),
+			Gas:       tx.Gas(),
+		}}
+	}
+	txset := newTransactionsByPriceAndNonce(signer, groups, nil)
+
+	var reasons []string
+	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
+		_, reason := txset.PeekRationale()
+		reasons = append(reasons, reason)
+		txset.Shift()
+	}
+	want := []string{OrderArrival, OrderTip, OrderTip}
+	if !reflect.DeepEqual(reasons, want) {
+		t.Errorf("rationale mismatch: have %v, want %v", reasons, want)
+	}
+}
@@ -17,6 +17,7 @@
 package miner
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/binary"
 	"math/big"
@@ -26,6 +27,7 @@ import (
 	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/tracing"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -84,6 +86,7 @@ func (args *BuildPayloadArgs) Id() engine.PayloadID {
 // will be set/updated afterwards.
 type Payload struct {
 	id       engine.PayloadID
+	args     *BuildPayloadArgs
 	empty    *types.Block
 	full     *types.Block
 	sidecars []*types.BlobTxSidecar
@@ -97,6 +100,7 @@ type Payload struct {
 func newPayload(empty *types.Block, id engine.PayloadID) *Payload {
 	payload := &Payload{
 		id:    id,
+		args:  new(BuildPayloadArgs),
 		empty: empty,
 		stop:  make(chan struct{}),
 	}
@@ -139,6 +143,23 @@ func (payload *Payload) update(r *newPayloadResult, elapsed time.Duration) {
 	payload.cond.Broadcast() // fire signal for notifying full block
 }
 
+// Args returns the parameters the payload is built with.
+func (payload *Payload) Args() *BuildPayloadArgs {
+	return payload.args
+}
+
+// Block returns the latest built version of the payload, without terminating
+// the background thread updating it.
+func (payload *Payload) Block() *types.Block {
+	payload.lock.Lock()
+	defer payload.lock.Unlock()
+
+	if payload.full != nil {
+		return payload.full
+	}
+	return payload.empty
+}
+
 // Resolve returns the latest built payload and also terminates the background
 // thread for updating payload. It's safe to be called multiple times.
 func (payload *Payload) Resolve() *engine.ExecutionPayloadEnvelope {
@@ -192,7 +213,7 @@ func (payload *Payload) ResolveFull() *engine.ExecutionPayloadEnvelope {
 }
 
 // buildPayload builds the payload according to the provided parameters.
-func (w *worker) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
+func (w *worker) buildPayload(ctx context.Context, args *BuildPayloadArgs) (*Payload, error) {
 	// Build the initial version with no transaction included. It should be fast
 	// enough to run. The empty payload can at least make sure there is something
 	// to deliver for not missing slot.
@@ -209,13 +230,17 @@ func (w *worker) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
 		txs:         args.Transactions,
 		gasLimit:    args.GasLimit,
 	}
+	_, span := tracing.Start(ctx, "miner.buildEmptyPayload", "payload", args.Id(), "parent", args.Parent, "txs", len(args.Transactions))
 	empty := w.getSealingBlock(emptyParams)
+	span.SetError(empty.err)
+	span.End()
 	if empty.err != nil {
 		return nil, empty.err
 	}
 
 	// Construct a payload object for return.
 	payload := newPayload(empty.block, args.Id())
+	payload.args = args
 	if args.NoTxPool { // don't start the background payload updating job if there is no tx pool to pull from
 		// make sure to make it appear as full, otherwise it will wait indefinitely for payload building to complete.
 		payload.full = empty.block
@@ -225,7 +250,10 @@ func (w *worker) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
 
 	// Spin up a routine for updating the payload in background. This strategy
 	// can maximum the revenue for including transactions with highest fee.
+	w.payloads.Add(1)
 	go func() {
+		defer w.payloads.Done()
+
 		// Setup the timer for re-building the payload. The initial clock is kept
 		// for triggering process immediately.
 		timer := time.NewTimer(0)
@@ -253,10 +281,16 @@ func (w *worker) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
 			select {
 			case <-timer.C:
 				start := time.Now()
+				// The caller's context only parents the spans, its cancellation
+				// once the payload returned does not stop the building
+				_, span := tracing.Start(ctx, "miner.buildFullPayload", "payload", payload.id, "parent", args.Parent)
 				r := w.getSealingBlock(fullParams)
 				if r.err == nil {
+					span.SetAttributes("txs", len(r.block.Transactions()), "gasUsed", r.block.GasUsed())
 					payload.update(r, time.Since(start))
 				}
+				span.SetError(r.err)
+				span.End()
 				timer.Reset(w.recommit)
 			case <-payload.stop:
 				log.Info("Stopping work on payload", "id", payload.id, "reason", "delivery")
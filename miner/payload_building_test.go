@@ -17,6 +17,7 @@
 package miner
 
 import (
+	"context"
 	"reflect"
 	"testing"
 	"time"
@@ -44,7 +45,7 @@ func TestBuildPayload(t *testing.T) {
 		Random:       common.Hash{},
 		FeeRecipient: recipient,
 	}
-	payload, err := w.buildPayload(args)
+	payload, err := w.buildPayload(context.Background(), args)
 	if err != nil {
 		t.Fatalf("Failed to build payload %v", err)
 	}
@@ -81,6 +82,38 @@ func TestBuildPayload(t *testing.T) {
 	}
 }
 
+func TestPayloadDrain(t *testing.T) {
+	w, b := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	miner := &Miner{worker: w}
+	args := &BuildPayloadArgs{
+		Parent:       b.chain.CurrentBlock().Hash(),
+		Timestamp:    uint64(time.Now().Unix()),
+		FeeRecipient: common.HexToAddress("0xdeadbeef"),
+	}
+	payload, err := miner.BuildPayload(context.Background(), args)
+	if err != nil {
+		t.Fatalf("Failed to build payload %v", err)
+	}
+	// The payload is still being updated, draining must time out
+	if _, done := miner.Drain(100 * time.Millisecond); done {
+		t.Fatal("Drain completed with payload in flight")
+	}
+	if _, err := miner.BuildPayload(context.Background(), args); err != ErrDraining {
+		t.Fatalf("Payload build while draining: have %v, want %v", err, ErrDraining)
+	}
+	// Once delivered, draining completes with the number of the built block
+	payload.Resolve()
+	number, done := miner.Drain(time.Second)
+	if !done {
+		t.Fatal("Drain timed out after payload delivery")
+	}
+	if want := b.chain.CurrentBlock().Number.Uint64() + 1; number != want {
+		t.Fatalf("Drained block number mismatch: have %d, want %d", number, want)
+	}
+}
+
 func TestPayloadId(t *testing.T) {
 	ids := make(map[string]int)
 	for i, tt := range []*BuildPayloadArgs{
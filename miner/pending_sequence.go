@@ -0,0 +1,70 @@
+package miner
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// PendingDelta is the change of the pending block since a given sequence number.
+type PendingDelta struct {
+	Seq        uint64      // Sequence number of the current pending block
+	Number     uint64      // Number of the pending block
+	ParentHash common.Hash // Parent of the pending block
+
+	// Reset reports whether the pending block was replaced since the requested
+	// sequence number, e.g. on a new head or a reordering, in which case the
+	// transactions are all the ones of the pending block.
+	Reset        bool
+	Transactions []*types.Transaction
+}
+
+// pendingSequence numbers the successive snapshots of the pending block, tracking
+// the snapshot each of its transactions was added in. While the pending block is
+// only extended, the transactions added since a snapshot are known.
+type pendingSequence struct {
+	seq    uint64                 // Sequence number of the last snapshot
+	epoch  uint64                 // First snapshot of which the last one is an extension
+	parent common.Hash            // Parent of the last snapshot
+	hashes []common.Hash          // Transactions of the last snapshot
+	added  map[common.Hash]uint64 // Snapshot each transaction was added in
+}
+
+// update numbers a new snapshot of the pending block.
+func (s *pendingSequence) update(block *types.Block) {
+	s.seq++
+
+	txs := block.Transactions()
+	extended := block.ParentHash() == s.parent && len(txs) >= len(s.hashes)
+	for i := 0; extended && i < len(s.hashes); i++ {
+		extended = txs[i].Hash() == s.hashes[i]
+	}
+	if !extended {
+		s.epoch = s.seq
+		s.added = make(map[common.Hash]uint64)
+	}
+	hashes := make([]common.Hash, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash()
+		if _, ok := s.added[hashes[i]]; !ok {
+			s.added[hashes[i]] = s.seq
+		}
+	}
+	s.parent, s.hashes = block.ParentHash(), hashes
+}
+
+// since returns the change of the given snapshot of the pending block since the
+// given sequence number.
+func (s *pendingSequence) since(block *types.Block, seq uint64) *PendingDelta {
+	delta := &PendingDelta{
+		Seq:        s.seq,
+		Number:     block.NumberU64(),
+		ParentHash: block.ParentHash(),
+		Reset:      seq < s.epoch || seq > s.seq,
+	}
+	for _, tx := range block.Transactions() {
+		if delta.Reset || s.added[tx.Hash()] > seq {
+			delta.Transactions = append(delta.Transactions, tx)
+		}
+	}
+	return delta
+}
@@ -0,0 +1,56 @@
+package miner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestPendingSequence(t *testing.T) {
+	var (
+		txs = make([]*types.Transaction, 4)
+		seq pendingSequence
+	)
+	for i := range txs {
+		txs[i] = types.NewTransaction(uint64(i), common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	}
+	block := func(parent common.Hash, txs ...*types.Transaction) *types.Block {
+		return types.NewBlockWithHeader(&types.Header{ParentHash: parent, Number: big.NewInt(1)}).WithBody(txs, nil)
+	}
+	check := func(delta *PendingDelta, seq uint64, reset bool, want ...*types.Transaction) {
+		t.Helper()
+		if delta.Seq != seq || delta.Reset != reset {
+			t.Fatalf("delta mismatch: have seq %d reset %v, want seq %d reset %v", delta.Seq, delta.Reset, seq, reset)
+		}
+		if len(delta.Transactions) != len(want) {
+			t.Fatalf("transaction count mismatch: have %d, want %d", len(delta.Transactions), len(want))
+		}
+		for i, tx := range want {
+			if delta.Transactions[i].Hash() != tx.Hash() {
+				t.Fatalf("transaction %d mismatch", i)
+			}
+		}
+	}
+	b1 := block(common.Hash{1}, txs[0])
+	seq.update(b1)
+	check(seq.since(b1, 0), 1, true, txs[0])
+
+	b2 := block(common.Hash{1}, txs[0], txs[1], txs[2])
+	seq.update(b2)
+	check(seq.since(b2, 1), 2, false, txs[1], txs[2])
+	check(seq.since(b2, 2), 2, false)
+	check(seq.since(b2, 5), 2, true, txs[0], txs[1], txs[2]) // from before a restart
+
+	// Reordered transactions replace the pending block
+	b3 := block(common.Hash{1}, txs[1], txs[0], txs[2], txs[3])
+	seq.update(b3)
+	check(seq.since(b3, 2), 3, true, txs[1], txs[0], txs[2], txs[3])
+
+	// So does a new head
+	b4 := block(common.Hash{2}, txs[3])
+	seq.update(b4)
+	check(seq.since(b4, 3), 4, true, txs[3])
+	check(seq.since(b4, 4), 4, false)
+}
@@ -0,0 +1,46 @@
+package miner
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BuildDryRun builds a block filled from the transaction pool on top of the
+// current head with the given timestamp, and verifies it against the consensus
+// rules, without delivering it anywhere. It rehearses the block production under
+// the rules active at the timestamp, e.g. those of an upcoming fork.
+func (miner *Miner) BuildDryRun(timestamp uint64) (*types.Block, error) {
+	var (
+		chain  = miner.worker.chain
+		config = chain.Config()
+		parent = chain.CurrentBlock()
+		number = new(big.Int).Add(parent.Number, common.Big1)
+	)
+	params := &generateParams{
+		timestamp:  timestamp,
+		forceTime:  true,
+		parentHash: parent.Hash(),
+		coinbase:   miner.worker.etherbase(),
+		dryRun:     true,
+	}
+	if config.IsShanghai(number, timestamp) {
+		params.withdrawals = types.Withdrawals{}
+	}
+	if config.IsCancun(number, timestamp) {
+		params.beaconRoot = new(common.Hash)
+	}
+	result := miner.worker.getSealingBlock(params)
+	if result.err != nil {
+		return nil, result.err
+	}
+	if err := chain.Engine().VerifyHeader(chain, result.block.Header()); err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	if err := chain.Validator().ValidateBody(result.block); err != nil {
+		return nil, fmt.Errorf("invalid body: %w", err)
+	}
+	return result.block, nil
+}
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/misc"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
@@ -38,7 +39,9 @@ import (
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 )
 
@@ -74,6 +77,10 @@ const (
 
 	// staleThreshold is the maximum depth of the acceptable stale block.
 	staleThreshold = 7
+
+	// blockSizeReserve is the room kept in size limited blocks for the header and
+	// the encoding of the body lists, when packing transactions.
+	blockSizeReserve = 1024
 )
 
 var (
@@ -82,13 +89,19 @@ var (
 	errBlockInterruptedByTimeout  = errors.New("timeout while building block")
 )
 
+// stateGrowthSkipMeter counts the transactions left out of the blocks built for
+// exceeding the state growth limit.
+var stateGrowthSkipMeter = metrics.NewRegisteredMeter("miner/stategrowth/skip", nil)
+
 // environment is the worker's current environment and holds all
 // information of the sealing block generation.
 type environment struct {
 	signer   types.Signer
 	state    *state.StateDB // apply state changes here
 	tcount   int            // tx count in cycle
+	size     uint64         // encoded size of the transactions packed, see blockTxSize
 	gasPool  *core.GasPool  // available gas used to pack transactions
+	usage    core.GasUsage  // resources used per gas dimension, if enabled
 	coinbase common.Address
 
 	header   *types.Header
@@ -96,6 +109,8 @@ type environment struct {
 	receipts []*types.Receipt
 	sidecars []*types.BlobTxSidecar
 	blobs    int
+
+	ordering []*OrderingEntry // ordering rationale of the included transactions
 }
 
 // copy creates a deep copy of environment.
@@ -104,6 +119,7 @@ func (env *environment) copy() *environment {
 		signer:   env.signer,
 		state:    env.state.Copy(),
 		tcount:   env.tcount,
+		size:     env.size,
 		coinbase: env.coinbase,
 		header:   types.CopyHeader(env.header),
 		receipts: copyReceipts(env.receipts),
@@ -112,12 +128,19 @@ func (env *environment) copy() *environment {
 		gasPool := *env.gasPool
 		cpy.gasPool = &gasPool
 	}
+	if env.usage != nil {
+		cpy.usage = make(core.GasUsage, len(env.usage))
+		copy(cpy.usage, env.usage)
+	}
 	cpy.txs = make([]*types.Transaction, len(env.txs))
 	copy(cpy.txs, env.txs)
 
 	cpy.sidecars = make([]*types.BlobTxSidecar, len(env.sidecars))
 	copy(cpy.sidecars, env.sidecars)
 
+	cpy.ordering = make([]*OrderingEntry, len(env.ordering))
+	copy(cpy.ordering, env.ordering)
+
 	return cpy
 }
 
@@ -201,13 +224,15 @@ type worker struct {
 	resubmitIntervalCh chan time.Duration
 	resubmitAdjustCh   chan *intervalAdjust
 
-	wg sync.WaitGroup
+	wg       sync.WaitGroup
+	payloads sync.WaitGroup // Background payload updating routines, waited for on drain
 
 	current *environment // An environment for current running cycle.
 
-	mu       sync.RWMutex // The lock used to protect the coinbase and extra fields
-	coinbase common.Address
-	extra    []byte
+	mu        sync.RWMutex // The lock used to protect the coinbase, extra and daChecker fields
+	coinbase  common.Address
+	extra     []byte
+	daChecker DAChecker
 
 	pendingMu    sync.RWMutex
 	pendingTasks map[common.Hash]*task
@@ -216,6 +241,7 @@ type worker struct {
 	snapshotBlock    *types.Block
 	snapshotReceipts types.Receipts
 	snapshotState    *state.StateDB
+	snapshotSeq      pendingSequence
 
 	// atomic status counters
 	running atomic.Bool  // The indicator whether the consensus engine is running or not.
@@ -330,6 +356,13 @@ func (w *worker) setExtra(extra []byte) {
 	w.extra = extra
 }
 
+// setDAChecker sets the checker of the data availability of the transactions.
+func (w *worker) setDAChecker(checker DAChecker) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.daChecker = checker
+}
+
 // setRecommitInterval updates the interval for miner sealing work recommitting.
 func (w *worker) setRecommitInterval(interval time.Duration) {
 	select {
@@ -366,6 +399,24 @@ func (w *worker) pendingBlock() *types.Block {
 	return w.snapshotBlock
 }
 
+// pendingSequenced returns the pending block along with its sequence number.
+func (w *worker) pendingSequenced() (uint64, *types.Block) {
+	w.snapshotMu.RLock()
+	defer w.snapshotMu.RUnlock()
+	return w.snapshotSeq.seq, w.snapshotBlock
+}
+
+// pendingSince returns the change of the pending block since the given sequence
+// number, nil if the pending block is not initialized.
+func (w *worker) pendingSince(seq uint64) *PendingDelta {
+	w.snapshotMu.RLock()
+	defer w.snapshotMu.RUnlock()
+	if w.snapshotBlock == nil {
+		return nil
+	}
+	return w.snapshotSeq.since(w.snapshotBlock, seq)
+}
+
 // pendingBlockAndReceipts returns pending block and corresponding receipts.
 // The returned values can be nil in case the pending block is not initialized.
 func (w *worker) pendingBlockAndReceipts() (*types.Block, types.Receipts) {
@@ -751,7 +802,11 @@ func (w *worker) makeEnv(parent *types.Header, header *types.Header, coinbase co
 	if err != nil {
 		return nil, err
 	}
+	state.SetWarmCache(w.chain.WarmCache())
 	state.StartPrefetcher("miner")
+	if w.chainConfig.MaxStateGrowth(header.Time) != 0 {
+		state.TrackStateGrowth()
+	}
 
 	// Note the passed coinbase may be different with header.Coinbase.
 	env := &environment{
@@ -779,6 +834,7 @@ func (w *worker) updateSnapshot(env *environment) {
 	)
 	w.snapshotReceipts = copyReceipts(env.receipts)
 	w.snapshotState = env.state.Copy()
+	w.snapshotSeq.update(w.snapshotBlock)
 }
 
 func (w *worker) commitTransaction(env *environment, tx *types.Transaction) ([]*types.Log, error) {
@@ -791,6 +847,7 @@ func (w *worker) commitTransaction(env *environment, tx *types.Transaction) ([]*
 	}
 	env.txs = append(env.txs, tx)
 	env.receipts = append(env.receipts, receipt)
+	env.size += blockTxSize(tx)
 	return receipt.Logs, nil
 }
 
@@ -812,6 +869,7 @@ func (w *worker) commitBlobTransaction(env *environment, tx *types.Transaction)
 	}
 	env.txs = append(env.txs, tx.WithoutBlobTxSidecar())
 	env.receipts = append(env.receipts, receipt)
+	env.size += blockTxSize(env.txs[len(env.txs)-1])
 	env.sidecars = append(env.sidecars, sc)
 	env.blobs += len(sc.Blobs)
 	*env.header.BlobGasUsed += receipt.BlobGasUsed
@@ -819,12 +877,46 @@ func (w *worker) commitBlobTransaction(env *environment, tx *types.Transaction)
 }
 
 // applyTransaction runs the transaction. If execution fails, state and gas pool are reverted.
+// Deposits are not subject to the execution time budget, the other transactions
+// being aborted once it is exhausted.
 func (w *worker) applyTransaction(env *environment, tx *types.Transaction) (*types.Receipt, error) {
 	var (
 		snap = env.state.Snapshot()
 		gp   = env.gasPool.Gas()
 	)
-	receipt, err := core.ApplyTransaction(w.chainConfig, w.chain, &env.coinbase, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, *w.chain.GetVMConfig())
+	msg, err := core.TransactionToMessage(tx, env.signer, env.header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	blockContext := core.NewEVMBlockContext(env.header, w.chain, &env.coinbase, w.chainConfig, env.state)
+	vmenv := vm.NewEVM(blockContext, vm.TxContext{}, env.state, w.chainConfig, *w.chain.GetVMConfig())
+	var timer *time.Timer
+	if budget := w.config.RollupTxTimeBudget; budget > 0 && !tx.IsDepositTx() {
+		timer = time.AfterFunc(budget, vmenv.Cancel)
+	}
+	var (
+		dimensions = core.GasDimensionsEnabled(w.chainConfig, env.header.Time)
+		growth     = w.chainConfig.MaxStateGrowth(env.header.Time) != 0
+		check      func(*types.Receipt) error
+	)
+	if dimensions || growth {
+		check = func(receipt *types.Receipt) error {
+			if err := core.CheckStateGrowth(w.chainConfig, env.header, tx, env.state); err != nil {
+				stateGrowthSkipMeter.Mark(1)
+				return err
+			}
+			if dimensions {
+				return env.usage.Add(w.chainConfig, env.header, tx, receipt, env.state)
+			}
+			return nil
+		}
+	}
+	receipt, err := core.ApplyTransactionWithEVM(msg, w.chainConfig, env.gasPool, env.state, env.header.Number, env.header.Hash(), tx, &env.header.GasUsed, vmenv, check)
+	if timer != nil {
+		// Stop the budget as soon as the transaction is applied, not to cancel
+		// the EVM once the outcome of the transaction is decided
+		timer.Stop()
+	}
 	if err != nil {
 		env.state.RevertToSnapshot(snap)
 		env.gasPool.SetGas(gp)
@@ -839,6 +931,10 @@ func (w *worker) commitTransactions(env *environment, txs *transactionsByPriceAn
 	}
 	var coalescedLogs []*types.Log
 
+	w.mu.RLock()
+	daChecker := w.daChecker
+	w.mu.RUnlock()
+
 	for {
 		// Check interruption signal and abort building if it's fired.
 		if interrupt != nil {
@@ -874,6 +970,12 @@ func (w *worker) commitTransactions(env *environment, txs *transactionsByPriceAn
 			txs.Pop()
 			continue
 		}
+		// If the transaction doesn't fit in the block size limit, skip the account.
+		if limit := w.chainConfig.MaxBlockSize(env.header.Time); limit != 0 && env.size+blockTxSize(tx)+blockSizeReserve > limit {
+			log.Trace("Not enough space left for transaction", "hash", ltx.Hash, "used", env.size, "needed", blockTxSize(tx), "limit", limit)
+			txs.Pop()
+			continue
+		}
 		// Error may be ignored here. The error has already been checked
 		// during transaction acceptance is the transaction pool.
 		from, _ := types.Sender(env.signer, tx)
@@ -885,9 +987,18 @@ func (w *worker) commitTransactions(env *environment, txs *transactionsByPriceAn
 			txs.Pop()
 			continue
 		}
+		// Skip the account if the data the transaction commits to is unavailable
+		if daChecker != nil {
+			if err := daChecker.CheckAvailability(tx); err != nil {
+				log.Debug("Skipping transaction with unavailable data", "hash", ltx.Hash, "sender", from, "err", err)
+				txs.Pop()
+				continue
+			}
+		}
 		// Start executing the transaction
 		env.state.SetTxContext(tx.Hash(), env.tcount)
 
+		tip, reason := txs.PeekRationale()
 		logs, err := w.commitTransaction(env, tx)
 		switch {
 		case errors.Is(err, core.ErrNonceTooLow):
@@ -895,9 +1006,19 @@ func (w *worker) commitTransactions(env *environment, txs *transactionsByPriceAn
 			log.Trace("Skipping transaction with low nonce", "hash", ltx.Hash, "sender", from, "nonce", tx.Nonce())
 			txs.Shift()
 
+		case errors.Is(err, core.ErrTxExecutionAborted):
+			// Transaction exhausted the execution time budget, drop all consecutive
+			// transactions from the same sender as they depend on it.
+			log.Warn("Transaction exceeded execution time budget", "hash", ltx.Hash, "sender", from, "budget", w.config.RollupTxTimeBudget)
+			if w.config.RollupTxTimeBudgetEvict {
+				w.eth.TxPool().Remove(ltx.Hash)
+			}
+			txs.Pop()
+
 		case errors.Is(err, nil):
 			// Everything ok, collect the logs and shift in the next transaction from the same account
 			coalescedLogs = append(coalescedLogs, logs...)
+			env.recordOrdering(tx, from, tip, ltx.Time, reason)
 			env.tcount++
 			txs.Shift()
 
@@ -936,6 +1057,7 @@ type generateParams struct {
 	withdrawals types.Withdrawals // List of withdrawals to include in block.
 	beaconRoot  *common.Hash      // The beacon root (cancun field).
 	noTxs       bool              // Flag whether an empty block without any transaction is expected
+	dryRun      bool              // Flag whether the block is only built for rehearsal, never delivered
 
 	txs      types.Transactions // Deposit transactions to include at the start of the block
 	gasLimit *uint64            // Optional gas limit override
@@ -1044,10 +1166,16 @@ func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) err
 			localTxs[account] = txs
 		}
 	}
+	// Enforce the on-chain governed tip floor on remote transactions, mirroring
+	// the transaction pool which exempts locals from tip requirements.
+	if minTip := types.GovernedMinTip(w.chainConfig, env.state, env.header.Time); minTip != nil && minTip.Sign() > 0 {
+		remoteTxs = filterBelowTip(remoteTxs, minTip, env.header.BaseFee)
+	}
 
 	// Fill the block with all available pending transactions.
 	if len(localTxs) > 0 {
 		txs := newTransactionsByPriceAndNonce(env.signer, localTxs, env.header.BaseFee)
+		txs.prioritized = true
 		if err := w.commitTransactions(env, txs, interrupt); err != nil {
 			return err
 		}
@@ -1061,6 +1189,28 @@ func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) err
 	return nil
 }
 
+// filterBelowTip caps each account's transaction list at the first transaction
+// paying an effective tip below the given threshold, keeping nonce order intact.
+func filterBelowTip(pending map[common.Address][]*txpool.LazyTransaction, minTip, baseFee *big.Int) map[common.Address][]*txpool.LazyTransaction {
+	filtered := make(map[common.Address][]*txpool.LazyTransaction, len(pending))
+	for addr, txs := range pending {
+		for i, tx := range txs {
+			tip := tx.GasTipCap
+			if baseFee != nil {
+				tip = math.BigMin(tip, new(big.Int).Sub(tx.GasFeeCap, baseFee))
+			}
+			if tip.Cmp(minTip) < 0 {
+				txs = txs[:i]
+				break
+			}
+		}
+		if len(txs) > 0 {
+			filtered[addr] = txs
+		}
+	}
+	return filtered
+}
+
 // generateWork generates a sealing block based on the given parameters.
 func (w *worker) generateWork(genParams *generateParams) *newPayloadResult {
 	work, err := w.prepareWork(genParams)
@@ -1081,6 +1231,7 @@ func (w *worker) generateWork(genParams *generateParams) *newPayloadResult {
 		if err != nil {
 			return &newPayloadResult{err: fmt.Errorf("failed to force-include tx: %s type: %d sender: %s nonce: %d, err: %w", tx.Hash(), tx.Type(), from, tx.Nonce(), err)}
 		}
+		work.recordOrdering(tx, from, nil, tx.Time(), OrderForced)
 		work.tcount++
 	}
 
@@ -1101,9 +1252,17 @@ func (w *worker) generateWork(genParams *generateParams) *newPayloadResult {
 	if err != nil {
 		return &newPayloadResult{err: err}
 	}
+	// The pool transactions are packed within the size limit, only the forced
+	// ones can push the block beyond, making it invalid.
+	if limit := w.chainConfig.MaxBlockSize(block.Time()); limit != 0 && block.Size() > limit {
+		return &newPayloadResult{err: fmt.Errorf("%w: %d bytes, limit %d", core.ErrBlockTooLarge, block.Size(), limit)}
+	}
+	if !genParams.dryRun {
+		w.writeOrderingAudit(block, work)
+	}
 	return &newPayloadResult{
 		block:    block,
-		fees:     totalFees(block, work.receipts),
+		fees:     totalFees(w.chainConfig, block, work.receipts),
 		sidecars: work.sidecars,
 	}
 }
@@ -1194,7 +1353,7 @@ func (w *worker) commit(env *environment, interval func(), update bool, start ti
 		if !w.isTTDReached(block.Header()) {
 			select {
 			case w.taskCh <- &task{receipts: env.receipts, state: env.state, block: block, createdAt: time.Now()}:
-				fees := totalFees(block, env.receipts)
+				fees := totalFees(w.chainConfig, block, env.receipts)
 				feesInEther := new(big.Float).Quo(new(big.Float).SetInt(fees), big.NewFloat(params.Ether))
 				log.Info("Commit new sealing work", "number", block.Number(), "sealhash", w.engine.SealHash(block.Header()),
 					"txs", env.tcount, "gas", block.GasUsed(), "fees", feesInEther,
@@ -1245,8 +1404,12 @@ func copyReceipts(receipts []*types.Receipt) []*types.Receipt {
 }
 
 // totalFees computes total consumed miner fees in Wei. Block transactions and receipts have to have the same order.
-func totalFees(block *types.Block, receipts []*types.Receipt) *big.Int {
+// The priority fees redirected to a vault or burnt by the chain config are not earned by the fee recipient.
+func totalFees(config *params.ChainConfig, block *types.Block, receipts []*types.Receipt) *big.Int {
 	feesWei := new(big.Int)
+	if recipient, ok := config.PriorityFeeRecipient(block.Coinbase(), block.Time()); !ok || recipient != block.Coinbase() {
+		return feesWei
+	}
 	for i, tx := range block.Transactions() {
 		minerFee, _ := tx.EffectiveGasTip(block.BaseFee())
 		feesWei.Add(feesWei, new(big.Int).Mul(new(big.Int).SetUint64(receipts[i].GasUsed), minerFee))
@@ -1268,3 +1431,13 @@ func signalToErr(signal int32) error {
 		panic(fmt.Errorf("undefined signal %d", signal))
 	}
 }
+
+// blockTxSize returns the size of a transaction in the RLP encoding of a block,
+// typed transactions being wrapped into a byte string.
+func blockTxSize(tx *types.Transaction) uint64 {
+	if tx.Type() == types.LegacyTxType {
+		return tx.Size()
+	}
+	// String headers are sized as the list ones
+	return rlp.ListSize(tx.Size())
+}
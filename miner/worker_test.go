@@ -17,6 +17,7 @@
 package miner
 
 import (
+	"errors"
 	"math/big"
 	"sync/atomic"
 	"testing"
@@ -500,3 +501,92 @@ func testGetSealingWork(t *testing.T, chainConfig *params.ChainConfig, engine co
 		}
 	}
 }
+
+func TestFilterBelowTip(t *testing.T) {
+	var (
+		addrA   = common.Address{0xa}
+		addrB   = common.Address{0xb}
+		baseFee = big.NewInt(10)
+	)
+	lazy := func(feeCap, tipCap int64) *txpool.LazyTransaction {
+		return &txpool.LazyTransaction{GasFeeCap: big.NewInt(feeCap), GasTipCap: big.NewInt(tipCap)}
+	}
+	pending := map[common.Address][]*txpool.LazyTransaction{
+		addrA: {lazy(20, 5), lazy(20, 2), lazy(20, 5)}, // second tx underpriced, cut from there
+		addrB: {lazy(12, 5)},                           // effective tip capped by the fee cap
+	}
+	filtered := filterBelowTip(pending, big.NewInt(3), baseFee)
+	if have := len(filtered[addrA]); have != 1 {
+		t.Errorf("account A: have %d txs, want 1", have)
+	}
+	if _, ok := filtered[addrB]; ok {
+		t.Errorf("account B: expected all txs to be filtered")
+	}
+}
+
+func TestBlockSizeLimit(t *testing.T) {
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	chainConfig := *ethashChainConfig
+	chainConfig.Optimism = &params.OptimismConfig{EIP1559Elasticity: 2, EIP1559Denominator: 8}
+	chainConfig.OasysBlockSizeTime = new(uint64)
+
+	backend := newTestWorkerBackend(t, &chainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	backend.txPool.Add(pendingTxs, true, false)
+	w := newWorker(testConfig, &chainConfig, engine, backend, new(event.TypeMux), nil, false)
+	w.setEtherbase(testBankAddress)
+	defer w.close()
+
+	build := func(limit uint64, forced types.Transactions) *newPayloadResult {
+		chainConfig.Optimism.MaxBlockSize = limit
+		return w.getSealingBlock(&generateParams{
+			parentHash: backend.chain.CurrentBlock().Hash(),
+			timestamp:  uint64(time.Now().Unix()),
+			txs:        forced,
+			forceTime:  true,
+		})
+	}
+	// The pool transactions not fitting in the limit are left out
+	if r := build(blockSizeReserve+1, nil); r.err != nil || len(r.block.Transactions()) != 0 {
+		t.Fatalf("transactions packed beyond the size limit: %v", r.err)
+	}
+	if r := build(0, nil); r.err != nil || len(r.block.Transactions()) != len(pendingTxs) {
+		t.Fatalf("transactions not packed without limit: %v", r.err)
+	}
+	// The forced transactions pushing the block beyond the limit fail the build
+	if r := build(1, pendingTxs); !errors.Is(r.err, core.ErrBlockTooLarge) {
+		t.Fatalf("oversized block built: %v", r.err)
+	}
+}
+
+func TestTotalFeesPriorityFeePolicy(t *testing.T) {
+	var (
+		coinbase = common.Address{0xc}
+		vault    = common.Address{0xf}
+		header   = &types.Header{Number: big.NewInt(1), Time: 10, Coinbase: coinbase, BaseFee: big.NewInt(10)}
+		tx       = types.NewTx(&types.DynamicFeeTx{Gas: 21000, GasFeeCap: big.NewInt(20), GasTipCap: big.NewInt(3)})
+		block    = types.NewBlockWithHeader(header).WithBody([]*types.Transaction{tx}, nil)
+		receipts = []*types.Receipt{{GasUsed: 21000}}
+	)
+	tests := []struct {
+		policy *params.PriorityFeePolicy
+		want   int64
+	}{
+		{nil, 3 * 21000},
+		{&params.PriorityFeePolicy{Time: 10, Mode: params.PriorityFeeToRecipient}, 3 * 21000},
+		{&params.PriorityFeePolicy{Time: 11, Mode: params.PriorityFeeBurn}, 3 * 21000},
+		{&params.PriorityFeePolicy{Time: 10, Mode: params.PriorityFeeBurn}, 0},
+		{&params.PriorityFeePolicy{Time: 10, Mode: params.PriorityFeeToVault, Vault: &vault}, 0},
+		{&params.PriorityFeePolicy{Time: 10, Mode: params.PriorityFeeToVault, Vault: &coinbase}, 3 * 21000},
+	}
+	for i, tt := range tests {
+		config := *params.TestChainConfig
+		if tt.policy != nil {
+			config.PriorityFeePolicies = []params.PriorityFeePolicy{*tt.policy}
+		}
+		if have := totalFees(&config, block, receipts); have.Cmp(big.NewInt(tt.want)) != 0 {
+			t.Errorf("test %d: fees mismatch: have %v, want %d", i, have, tt.want)
+		}
+	}
+}
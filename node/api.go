@@ -18,6 +18,7 @@ package node
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -179,6 +180,10 @@ func (api *adminAPI) StartHTTP(host *string, port *int, cors *string, apis *stri
 		rpcEndpointConfig: rpcEndpointConfig{
 			batchItemLimit:         api.node.config.BatchRequestLimit,
 			batchResponseSizeLimit: api.node.config.BatchResponseMaxSize,
+			requestSizeLimit:       api.node.config.RequestMaxSize,
+			inflightLimit:          api.node.config.InflightRequestLimit,
+			responseCache:          api.node.responseCache,
+			execPools:              api.node.execPools,
 		},
 	}
 	if cors != nil {
@@ -253,10 +258,15 @@ func (api *adminAPI) StartWS(host *string, port *int, allowedOrigins *string, ap
 	config := wsConfig{
 		Modules: api.node.config.WSModules,
 		Origins: api.node.config.WSOrigins,
+		limits:  api.node.config.WSLimits,
 		// ExposeAll: api.node.config.WSExposeAll,
 		rpcEndpointConfig: rpcEndpointConfig{
 			batchItemLimit:         api.node.config.BatchRequestLimit,
 			batchResponseSizeLimit: api.node.config.BatchResponseMaxSize,
+			requestSizeLimit:       api.node.config.RequestMaxSize,
+			inflightLimit:          api.node.config.InflightRequestLimit,
+			responseCache:          api.node.responseCache,
+			execPools:              api.node.execPools,
 		},
 	}
 	if apis != nil {
@@ -320,6 +330,21 @@ func (api *adminAPI) Datadir() string {
 	return api.node.DataDir()
 }
 
+// ReloadJWTSecret reloads the JWT secret of the authenticated endpoints from its
+// source, keeping the previous one accepted for the grace period if it rotated.
+func (api *adminAPI) ReloadJWTSecret() (bool, error) {
+	api.node.lock.Lock()
+	defer api.node.lock.Unlock()
+
+	if api.node.jwtSecrets == nil {
+		return false, errors.New("authenticated RPC endpoints not enabled")
+	}
+	if err := api.node.jwtSecrets.reload(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // web3API offers helper utils
 type web3API struct {
 	stack *Node
@@ -0,0 +1,250 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/time/rate"
+)
+
+const (
+	apiKeyHeader         = "X-API-Key"
+	apiKeyQueryParam     = "apikey"
+	apiKeyRefreshPeriod  = time.Minute
+	apiKeyFetchTimeout   = 10 * time.Second
+	apiKeyMaxDocumentLen = 16 * 1024 * 1024
+)
+
+// apiKeyPolicy is the access policy of a single API key.
+type apiKeyPolicy struct {
+	Name      string   `json:"name"`
+	Key       string   `json:"key"`
+	Methods   []string `json:"methods,omitempty"`   // Allowed methods, "ns_*" matches a namespace; empty allows all
+	RateLimit float64  `json:"rateLimit,omitempty"` // Calls per second, zero for unlimited
+	Burst     int      `json:"burst,omitempty"`     // Maximum burst of calls, defaults to the rate limit
+	LatestTag string   `json:"latestTag,omitempty"` // Head the "latest" block tag resolves to (unsafe, safe, finalized)
+
+	limiter   *rate.Limiter
+	latestTag *rpc.BlockNumber
+}
+
+// apiKeyError is returned to clients whose call is rejected by their key policy.
+type apiKeyError struct {
+	code    int
+	message string
+}
+
+func (e *apiKeyError) Error() string  { return e.message }
+func (e *apiKeyError) ErrorCode() int { return e.code }
+
+// init validates the policy and sets up its derived fields.
+func (p *apiKeyPolicy) init() error {
+	if p.Key == "" {
+		return fmt.Errorf("api key %q: empty key", p.Name)
+	}
+	if p.RateLimit < 0 {
+		return fmt.Errorf("api key %q: negative rate limit", p.Name)
+	}
+	if p.RateLimit > 0 {
+		burst := p.Burst
+		if burst <= 0 {
+			burst = int(p.RateLimit)
+			if burst < 1 {
+				burst = 1
+			}
+		}
+		p.limiter = rate.NewLimiter(rate.Limit(p.RateLimit), burst)
+	}
+	if p.LatestTag != "" {
+		tag, err := rpc.ParseLatestBlockTag(p.LatestTag)
+		if err != nil {
+			return fmt.Errorf("api key %q: %w", p.Name, err)
+		}
+		p.latestTag = &tag
+	}
+	return nil
+}
+
+// AllowCall implements rpc.CallPolicy.
+func (p *apiKeyPolicy) AllowCall(method string) error {
+	if len(p.Methods) > 0 && !p.allowed(method) {
+		return &apiKeyError{code: -32601, message: fmt.Sprintf("method %s not allowed for api key", method)}
+	}
+	if p.limiter != nil && !p.limiter.Allow() {
+		return &apiKeyError{code: -32005, message: "api key rate limit exceeded"}
+	}
+	return nil
+}
+
+func (p *apiKeyPolicy) allowed(method string) bool {
+	for _, allowed := range p.Methods {
+		if allowed == method {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok && strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// apiKeyStore holds the API key policies loaded from a file or a remote service,
+// periodically reloading them.
+type apiKeyStore struct {
+	source string
+
+	mu   sync.RWMutex
+	keys map[string]*apiKeyPolicy
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newAPIKeyStore creates a key store, loading the initial set of keys from the
+// given path or http(s) URL.
+func newAPIKeyStore(source string) (*apiKeyStore, error) {
+	s := &apiKeyStore{source: source}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// start launches the background reload loop.
+func (s *apiKeyStore) start() {
+	s.quit = make(chan struct{})
+	s.wg.Add(1)
+	go s.loop()
+}
+
+// stop terminates the background reload loop.
+func (s *apiKeyStore) stop() {
+	if s.quit == nil {
+		return
+	}
+	close(s.quit)
+	s.wg.Wait()
+	s.quit = nil
+}
+
+func (s *apiKeyStore) loop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(apiKeyRefreshPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.reload(); err != nil {
+				log.Warn("Failed to reload API keys, keeping previous set", "source", s.source, "err", err)
+			}
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// reload fetches the key policies and atomically replaces the current set. The
+// rate limiter state of keys with an unchanged limit is carried over.
+func (s *apiKeyStore) reload() error {
+	data, err := s.fetch()
+	if err != nil {
+		return err
+	}
+	var policies []*apiKeyPolicy
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return fmt.Errorf("invalid API key document: %w", err)
+	}
+	keys := make(map[string]*apiKeyPolicy, len(policies))
+	for _, policy := range policies {
+		if err := policy.init(); err != nil {
+			return err
+		}
+		if _, ok := keys[policy.Key]; ok {
+			return fmt.Errorf("api key %q: duplicate key", policy.Name)
+		}
+		keys[policy.Key] = policy
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, policy := range keys {
+		if old, ok := s.keys[key]; ok && old.limiter != nil && policy.limiter != nil &&
+			old.RateLimit == policy.RateLimit && old.limiter.Burst() == policy.limiter.Burst() {
+			policy.limiter = old.limiter
+		}
+	}
+	if len(s.keys) != len(keys) {
+		log.Info("Loaded API keys", "source", s.source, "count", len(keys))
+	}
+	s.keys = keys
+	return nil
+}
+
+func (s *apiKeyStore) fetch() ([]byte, error) {
+	if !strings.HasPrefix(s.source, "http://") && !strings.HasPrefix(s.source, "https://") {
+		return os.ReadFile(s.source)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), apiKeyFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API key service returned %s", res.Status)
+	}
+	return io.ReadAll(io.LimitReader(res.Body, apiKeyMaxDocumentLen))
+}
+
+// lookup returns the policy of the given key, or nil if the key is unknown.
+func (s *apiKeyStore) lookup(key string) *apiKeyPolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.keys[key]
+}
+
+// newAPIKeyHandler creates a http.Handler which rejects requests without a
+// known API key, and attaches the key's policy to the request context. Keys
+// are read from the X-API-Key header, or the apikey query parameter for clients
+// unable to set headers on websocket upgrades.
+func newAPIKeyHandler(store *apiKeyStore, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(apiKeyHeader)
+		if key == "" {
+			key = r.URL.Query().Get(apiKeyQueryParam)
+		}
+		if key == "" {
+			http.Error(w, "missing api key", http.StatusUnauthorized)
+			return
+		}
+		policy := store.lookup(key)
+		if policy == nil {
+			http.Error(w, "invalid api key", http.StatusUnauthorized)
+			return
+		}
+		ctx := rpc.WithCallPolicy(r.Context(), policy)
+		if policy.latestTag != nil {
+			ctx = rpc.WithLatestBlockTag(ctx, *policy.latestTag)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,74 @@
+package node
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestAPIKeyPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	doc := `[
+		{"name": "exchange", "key": "k1", "methods": ["eth_*", "net_version"], "rateLimit": 1, "burst": 2, "latestTag": "finalized"},
+		{"name": "internal", "key": "k2"}
+	]`
+	if err := os.WriteFile(path, []byte(doc), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := newAPIKeyStore(path)
+	if err != nil {
+		t.Fatalf("failed to load keys: %v", err)
+	}
+	exchange := store.lookup("k1")
+	if exchange == nil {
+		t.Fatal("key k1 not loaded")
+	}
+	if err := exchange.AllowCall("eth_blockNumber"); err != nil {
+		t.Errorf("eth_blockNumber rejected: %v", err)
+	}
+	if err := exchange.AllowCall("debug_traceTransaction"); err == nil {
+		t.Error("debug_traceTransaction allowed")
+	}
+	// The burst is exhausted by now, with one call left at most.
+	if err := exchange.AllowCall("net_version"); err == nil {
+		if err := exchange.AllowCall("net_version"); err == nil {
+			t.Error("rate limit not enforced")
+		}
+	}
+	if internal := store.lookup("k2"); internal == nil || internal.AllowCall("debug_traceTransaction") != nil {
+		t.Error("unrestricted key rejected")
+	}
+
+	var (
+		seenTag rpc.BlockNumber
+		seenOk  bool
+	)
+	handler := newAPIKeyHandler(store, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenTag, seenOk = rpc.LatestBlockTagFromContext(r.Context())
+	}))
+	for _, tt := range []struct {
+		key  string
+		code int
+	}{
+		{"", http.StatusUnauthorized},
+		{"unknown", http.StatusUnauthorized},
+		{"k1", http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		if tt.key != "" {
+			req.Header.Set(apiKeyHeader, tt.key)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.code {
+			t.Errorf("key %q: status mismatch: have %d, want %d", tt.key, rec.Code, tt.code)
+		}
+	}
+	if !seenOk || seenTag != rpc.FinalizedBlockNumber {
+		t.Errorf("latest tag not attached: have %v (%v), want %v", seenTag, seenOk, rpc.FinalizedBlockNumber)
+	}
+}
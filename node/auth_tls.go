@@ -0,0 +1,45 @@
+package node
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// authTLSConfig returns the TLS configuration of the authenticated APIs, nil if
+// they are served over plain HTTP. Client certificates are required and
+// verified if a client CA is configured.
+func (c *Config) authTLSConfig() (*tls.Config, error) {
+	if c.AuthTLSCert == "" && c.AuthTLSKey == "" {
+		if c.AuthTLSClientCA != "" {
+			return nil, errors.New("authenticated RPC client CA requires a TLS certificate")
+		}
+		return nil, nil
+	}
+	if c.AuthTLSCert == "" || c.AuthTLSKey == "" {
+		return nil, errors.New("authenticated RPC TLS requires both a certificate and a key")
+	}
+	cert, err := tls.LoadX509KeyPair(c.AuthTLSCert, c.AuthTLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load authenticated RPC TLS certificate: %w", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if c.AuthTLSClientCA != "" {
+		data, err := os.ReadFile(c.AuthTLSClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read authenticated RPC client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificate found in authenticated RPC client CA %s", c.AuthTLSClientCA)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
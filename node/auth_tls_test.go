@@ -0,0 +1,144 @@
+package node
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/internal/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// issueTestCert creates a certificate signed by the given parent, self-signed if
+// nil, and writes it with its key as PEM files in dir.
+func issueTestCert(t *testing.T, dir, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, tls.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	if parent == nil {
+		template.IsCA, template.BasicConstraintsValid = true, true
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	if err := os.WriteFile(filepath.Join(dir, name+".crt"), certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".key"), keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key, pair
+}
+
+func TestAuthTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey, _ := issueTestCert(t, dir, "ca", nil, nil)
+	issueTestCert(t, dir, "server", ca, caKey)
+	_, _, client := issueTestCert(t, dir, "client", ca, caKey)
+
+	config := &Config{
+		AuthTLSCert:     filepath.Join(dir, "server.crt"),
+		AuthTLSKey:      filepath.Join(dir, "server.key"),
+		AuthTLSClientCA: filepath.Join(dir, "ca.crt"),
+	}
+	tlsConfig, err := config.authTLSConfig()
+	if err != nil {
+		t.Fatalf("failed to create TLS config: %v", err)
+	}
+	srv := newHTTPServer(testlog.Logger(t, log.LvlDebug), rpc.DefaultHTTPTimeouts)
+	if err := srv.enableRPC(apis(), httpConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.setListenAddr("127.0.0.1", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.setTLSConfig(tlsConfig); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.start(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.stop()
+
+	url := srv.scheme(false) + "://" + srv.listenAddr()
+	if !strings.HasPrefix(url, "https://") {
+		t.Fatalf("server URL not using TLS: %s", url)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	request := func(certs []tls.Certificate) error {
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certs},
+		}}
+		body := `{"jsonrpc":"2.0","id":1,"method":"rpc_modules"}`
+		resp, err := client.Post(url, "application/json", strings.NewReader(body))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+	if err := request(nil); err == nil {
+		t.Error("request without client certificate accepted")
+	}
+	if err := request([]tls.Certificate{client}); err != nil {
+		t.Errorf("request with client certificate rejected: %v", err)
+	}
+}
+
+func TestAuthTLSConfigValidation(t *testing.T) {
+	for i, config := range []*Config{
+		{AuthTLSCert: "server.crt"},
+		{AuthTLSKey: "server.key"},
+		{AuthTLSClientCA: "ca.crt"},
+	} {
+		if _, err := config.authTLSConfig(); err == nil {
+			t.Errorf("test %d: invalid configuration accepted", i)
+		}
+	}
+	if tlsConfig, err := new(Config).authTLSConfig(); tlsConfig != nil || err != nil {
+		t.Errorf("plain HTTP configuration mismatch: %v, %v", tlsConfig, err)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -147,6 +148,17 @@ type Config struct {
 	// for the authenticated api. This is by default {'localhost'}.
 	AuthVirtualHosts []string `toml:",omitempty"`
 
+	// AuthTLSCert and AuthTLSKey are the paths to the PEM encoded certificate and
+	// private key the authenticated APIs are served over TLS with. Plain HTTP is
+	// served if unset.
+	AuthTLSCert string `toml:",omitempty"`
+	AuthTLSKey  string `toml:",omitempty"`
+
+	// AuthTLSClientCA is the path to the PEM encoded certificates of the
+	// authorities client certificates are verified against. If set, clients of
+	// the authenticated APIs must present a certificate signed by one of them.
+	AuthTLSClientCA string `toml:",omitempty"`
+
 	// WSHost is the host interface on which to start the websocket RPC server. If
 	// this field is empty, no websocket API endpoint will be started.
 	WSHost string
@@ -176,6 +188,10 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// WSLimits are the connection, idle and subscription limits applied to the
+	// websocket RPC clients, protecting public nodes from misbehaving subscribers.
+	WSLimits rpc.WebsocketLimits `toml:",omitempty"`
+
 	// GraphQLCors is the Cross-Origin Resource Sharing header to send to requesting
 	// clients. Please be aware that CORS is a browser enforced security, it's fully
 	// useless for custom HTTP clients.
@@ -204,13 +220,47 @@ type Config struct {
 	// BatchResponseMaxSize is the maximum number of bytes returned from a batched rpc call.
 	BatchResponseMaxSize int `toml:",omitempty"`
 
-	// JWTSecret is the path to the hex-encoded jwt secret.
+	// RequestMaxSize is the maximum number of bytes of an rpc request.
+	RequestMaxSize int `toml:",omitempty"`
+
+	// InflightRequestLimit is the maximum number of rpc requests processed
+	// concurrently per WebSocket or IPC connection. Zero means unlimited.
+	InflightRequestLimit int `toml:",omitempty"`
+
+	// ExecutionPools are the worker pools executing the rpc calls per namespace,
+	// shared by the HTTP, WebSocket, authenticated and in-process endpoints. The
+	// calls of the namespaces without pool are executed right away.
+	ExecutionPools []rpc.ExecutionPoolConfig `toml:",omitempty"`
+
+	// JWTSecret is the path to the hex-encoded jwt secret. It can also reference a
+	// HashiCorp Vault secret as vault:<path>#<field>, or a command printing the
+	// secret, such as a KMS client, as exec:<command>.
 	JWTSecret string `toml:",omitempty"`
 
+	// JWTSecretRefresh is the interval at which the jwt secret is reloaded from
+	// its source, rotating it if changed. Zero disables reloading.
+	JWTSecretRefresh time.Duration `toml:",omitempty"`
+
+	// JWTSecretGrace is how long the previous jwt secret is still accepted after
+	// a rotation.
+	JWTSecretGrace time.Duration `toml:",omitempty"`
+
+	// APIKeys is the path or http(s) URL of a JSON document listing the API keys
+	// accepted by the HTTP and WebSocket servers, along with their policies. If
+	// set, requests without a known key are rejected.
+	APIKeys string `toml:",omitempty"`
+
 	// EnablePersonal enables the deprecated personal namespace.
 	EnablePersonal bool `toml:"-"`
 
 	DBEngine string `toml:",omitempty"`
+
+	// StateDir is the directory the state databases are kept in, each in a
+	// subdirectory named after its database, instead of along with the chain
+	// data. Relative paths are resolved within the data directory. Both
+	// directories must be backed up and restored together: the databases are
+	// paired on creation and refuse to be opened with another one.
+	StateDir string `toml:",omitempty"`
 }
 
 // IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
@@ -358,6 +408,15 @@ func (c *Config) ResolvePath(path string) string {
 	return filepath.Join(c.instanceDir(), path)
 }
 
+// ResolveState returns the absolute path of the separate state database with the
+// given name, empty if the state is kept along with the chain data.
+func (c *Config) ResolveState(name string) string {
+	if c.StateDir == "" || c.DataDir == "" {
+		return ""
+	}
+	return filepath.Join(c.ResolvePath(c.StateDir), name)
+}
+
 func (c *Config) instanceDir() string {
 	if c.DataDir == "" {
 		return ""
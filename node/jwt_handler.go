@@ -27,17 +27,17 @@ import (
 const jwtExpiryTimeout = 60 * time.Second
 
 type jwtHandler struct {
-	keyFunc func(token *jwt.Token) (interface{}, error)
+	secrets func() [][]byte // Secrets accepted, tried in order
 	next    http.Handler
 }
 
-// newJWTHandler creates a http.Handler with jwt authentication support.
-func newJWTHandler(secret []byte, next http.Handler) http.Handler {
+// newJWTHandler creates a http.Handler with jwt authentication support. Tokens
+// signed with any of the secrets returned by the given function are accepted,
+// allowing the secret to be rotated.
+func newJWTHandler(secrets func() [][]byte, next http.Handler) http.Handler {
 	return &jwtHandler{
-		keyFunc: func(token *jwt.Token) (interface{}, error) {
-			return secret, nil
-		},
-		next: next,
+		secrets: secrets,
+		next:    next,
 	}
 }
 
@@ -57,14 +57,25 @@ func (handler *jwtHandler) ServeHTTP(out http.ResponseWriter, r *http.Request) {
 	// We explicitly set only HS256 allowed, and also disables the
 	// claim-check: the RegisteredClaims internally requires 'iat' to
 	// be no later than 'now', but we allow for a bit of drift.
-	token, err := jwt.ParseWithClaims(strToken, &claims, handler.keyFunc,
-		jwt.WithValidMethods([]string{"HS256"}),
-		jwt.WithoutClaimsValidation())
+	var (
+		token *jwt.Token
+		err   error
+	)
+	for _, secret := range handler.secrets() {
+		secret := secret
+		claims = jwt.RegisteredClaims{}
+		token, err = jwt.ParseWithClaims(strToken, &claims, func(token *jwt.Token) (interface{}, error) {
+			return secret, nil
+		}, jwt.WithValidMethods([]string{"HS256"}), jwt.WithoutClaimsValidation())
+		if err == nil {
+			break
+		}
+	}
 
 	switch {
 	case err != nil:
 		http.Error(out, err.Error(), http.StatusUnauthorized)
-	case !token.Valid:
+	case token == nil || !token.Valid:
 		http.Error(out, "invalid token", http.StatusUnauthorized)
 	case !claims.VerifyExpiresAt(time.Now(), false): // optional
 		http.Error(out, "token is expired", http.StatusUnauthorized)
@@ -0,0 +1,242 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	jwtSecretFetchTimeout = 10 * time.Second
+	jwtSecretMaxLen       = 64 * 1024
+
+	// Prefixes of the JWT secret sources other than plain files.
+	jwtSourceVault = "vault:" // vault:<path>#<field>, using VAULT_ADDR and VAULT_TOKEN
+	jwtSourceExec  = "exec:"  // exec:<command> printing the hex secret, e.g. a KMS client
+)
+
+// IsExternalJWTSource returns whether the JWT secret is fetched from an external
+// secret manager rather than read from a file.
+func IsExternalJWTSource(source string) bool {
+	return strings.HasPrefix(source, jwtSourceVault) || strings.HasPrefix(source, jwtSourceExec)
+}
+
+// jwtSecretStore holds the JWT secret of the authenticated endpoints, read from
+// a file, a HashiCorp Vault secret or the output of an external command such as
+// a KMS client, and periodically reloaded. When the secret is rotated, the
+// previous one remains accepted for a grace period, so the consensus client can
+// switch over without downtime.
+type jwtSecretStore struct {
+	source  string
+	refresh time.Duration
+	grace   time.Duration
+
+	mu       sync.RWMutex
+	current  []byte
+	previous []byte    // Secret replaced by the last rotation, if still accepted
+	expiry   time.Time // Time the previous secret stops being accepted
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newJWTSecretStore creates a secret store for the given source. The initial
+// secret is loaded from the source unless given.
+func newJWTSecretStore(source string, secret []byte, refresh, grace time.Duration) (*jwtSecretStore, error) {
+	s := &jwtSecretStore{
+		source:  source,
+		refresh: refresh,
+		grace:   grace,
+		current: secret,
+	}
+	if secret == nil {
+		if err := s.reload(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// start launches the background reload loop, if periodic reloading is enabled.
+func (s *jwtSecretStore) start() {
+	if s.refresh == 0 || s.source == "" {
+		return
+	}
+	s.quit = make(chan struct{})
+	s.wg.Add(1)
+	go s.loop()
+}
+
+// stop terminates the background reload loop.
+func (s *jwtSecretStore) stop() {
+	if s.quit == nil {
+		return
+	}
+	close(s.quit)
+	s.wg.Wait()
+	s.quit = nil
+}
+
+func (s *jwtSecretStore) loop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.refresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.reload(); err != nil {
+				log.Warn("Failed to reload JWT secret, keeping current one", "source", s.redactedSource(), "err", err)
+			}
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// secret returns the current JWT secret.
+func (s *jwtSecretStore) secret() []byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.current
+}
+
+// secrets returns the JWT secrets currently accepted, the current one first.
+func (s *jwtSecretStore) secrets() [][]byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.previous != nil && time.Now().Before(s.expiry) {
+		return [][]byte{s.current, s.previous}
+	}
+	return [][]byte{s.current}
+}
+
+// reload fetches the secret from its source, rotating it if it changed.
+func (s *jwtSecretStore) reload() error {
+	if s.source == "" {
+		return errors.New("ephemeral JWT secret cannot be reloaded")
+	}
+	data, err := s.fetch()
+	if err != nil {
+		return err
+	}
+	secret := common.FromHex(strings.TrimSpace(string(data)))
+	if len(secret) != 32 {
+		return fmt.Errorf("invalid JWT secret length %d", len(secret))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case s.current == nil:
+		log.Info("Loaded JWT secret", "source", s.redactedSource(), "crc32", fmt.Sprintf("%#x", crc32.ChecksumIEEE(secret)))
+	case !bytes.Equal(s.current, secret):
+		s.previous, s.expiry = s.current, time.Now().Add(s.grace)
+		log.Warn("Rotated JWT secret", "source", s.redactedSource(), "crc32", fmt.Sprintf("%#x", crc32.ChecksumIEEE(secret)), "grace", common.PrettyDuration(s.grace))
+	}
+	s.current = secret
+	return nil
+}
+
+func (s *jwtSecretStore) fetch() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), jwtSecretFetchTimeout)
+	defer cancel()
+
+	switch {
+	case strings.HasPrefix(s.source, jwtSourceVault):
+		return fetchVaultSecret(ctx, strings.TrimPrefix(s.source, jwtSourceVault))
+	case strings.HasPrefix(s.source, jwtSourceExec):
+		args := strings.Fields(strings.TrimPrefix(s.source, jwtSourceExec))
+		if len(args) == 0 {
+			return nil, errors.New("empty JWT secret command")
+		}
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("JWT secret command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return out, nil
+	default:
+		return os.ReadFile(s.source)
+	}
+}
+
+// redactedSource returns the source of the secret, without the arguments of the
+// command which may hold credentials.
+func (s *jwtSecretStore) redactedSource() string {
+	if strings.HasPrefix(s.source, jwtSourceExec) {
+		if args := strings.Fields(strings.TrimPrefix(s.source, jwtSourceExec)); len(args) > 0 {
+			return jwtSourceExec + args[0]
+		}
+	}
+	return s.source
+}
+
+// fetchVaultSecret reads a field of a HashiCorp Vault secret, given as
+// <path>#<field>. Both the KV version 1 and 2 secret engines are supported. The
+// server and token are taken from the standard VAULT_ADDR, VAULT_TOKEN and
+// VAULT_NAMESPACE environment variables.
+func fetchVaultSecret(ctx context.Context, ref string) ([]byte, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || field == "" {
+		return nil, fmt.Errorf("invalid vault secret reference %q, want <path>#<field>", ref)
+	}
+	addr, token := os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return nil, errors.New("VAULT_ADDR and VAULT_TOKEN must be set")
+	}
+	url := strings.TrimSuffix(addr, "/") + "/v1/" + strings.TrimPrefix(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned %s", res.Status)
+	}
+	var secret struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, jwtSecretMaxLen)).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("invalid vault response: %w", err)
+	}
+	data := secret.Data
+	if nested, ok := data["data"]; ok { // KV version 2 nests the secret data
+		data = nil
+		if err := json.Unmarshal(nested, &data); err != nil {
+			return nil, fmt.Errorf("invalid vault secret data: %w", err)
+		}
+	}
+	var value string
+	if err := json.Unmarshal(data[field], &value); err != nil {
+		return nil, fmt.Errorf("vault secret field %q missing or not a string", field)
+	}
+	return []byte(value), nil
+}
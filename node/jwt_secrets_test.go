@@ -0,0 +1,58 @@
+package node
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestJWTSecretRotation(t *testing.T) {
+	var (
+		path    = filepath.Join(t.TempDir(), "jwtsecret")
+		old     = common.HexToHash("0x01").Bytes()
+		rotated = common.HexToHash("0x02").Bytes()
+	)
+	if err := os.WriteFile(path, []byte(hexutil.Encode(old)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := newJWTSecretStore(path, nil, 0, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to load secret: %v", err)
+	}
+	if !bytes.Equal(store.secret(), old) {
+		t.Fatalf("initial secret mismatch: have %x, want %x", store.secret(), old)
+	}
+	// Rotate the secret, both must be accepted during the grace period
+	if err := os.WriteFile(path, []byte(hexutil.Encode(rotated)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.reload(); err != nil {
+		t.Fatalf("failed to reload secret: %v", err)
+	}
+	if secrets := store.secrets(); len(secrets) != 2 || !bytes.Equal(secrets[0], rotated) || !bytes.Equal(secrets[1], old) {
+		t.Fatalf("accepted secrets mismatch after rotation: %x", secrets)
+	}
+	// The previous secret must be rejected past the grace period
+	store.mu.Lock()
+	store.expiry = time.Now().Add(-time.Second)
+	store.mu.Unlock()
+
+	if secrets := store.secrets(); len(secrets) != 1 || !bytes.Equal(secrets[0], rotated) {
+		t.Fatalf("accepted secrets mismatch after grace period: %x", secrets)
+	}
+	// An invalid secret must not replace the current one
+	if err := os.WriteFile(path, []byte("0x1234"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.reload(); err == nil {
+		t.Fatal("invalid secret loaded")
+	}
+	if !bytes.Equal(store.secret(), rotated) {
+		t.Fatalf("secret replaced by invalid one: %x", store.secret())
+	}
+}
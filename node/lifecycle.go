@@ -29,3 +29,11 @@ type Lifecycle interface {
 	// are all terminated.
 	Stop() error
 }
+
+// Drainer is an optional interface for lifecycles which need to wind down work
+// while the RPC endpoints are still being served, e.g. to deliver a block that
+// is being built. Drain is called on shutdown before the RPC servers and any
+// lifecycle are stopped.
+type Drainer interface {
+	Drain()
+}
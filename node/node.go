@@ -18,6 +18,7 @@ package node
 
 import (
 	crand "crypto/rand"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"hash/crc32"
@@ -55,14 +56,18 @@ type Node struct {
 	state         int           // Tracks state of node lifecycle
 
 	lock          sync.Mutex
-	lifecycles    []Lifecycle // All registered backends, services, and auxiliary services that have a lifecycle
-	rpcAPIs       []rpc.API   // List of APIs currently provided by the node
-	http          *httpServer //
-	ws            *httpServer //
-	httpAuth      *httpServer //
-	wsAuth        *httpServer //
-	ipc           *ipcServer  // Stores information about the ipc http server
-	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
+	lifecycles    []Lifecycle         // All registered backends, services, and auxiliary services that have a lifecycle
+	rpcAPIs       []rpc.API           // List of APIs currently provided by the node
+	http          *httpServer         //
+	ws            *httpServer         //
+	httpAuth      *httpServer         //
+	wsAuth        *httpServer         //
+	ipc           *ipcServer          // Stores information about the ipc http server
+	inprocHandler *rpc.Server         // In-process RPC request handler to process the API requests
+	apiKeys       *apiKeyStore        // API key policies of the public HTTP and WS servers, if enabled
+	jwtSecrets    *jwtSecretStore     // JWT secrets of the authenticated servers, if enabled
+	responseCache rpc.ResponseCache   // Cache of the public HTTP and WS servers, if enabled
+	execPools     *rpc.ExecutionPools // Worker pools shared by the rpc servers, if configured
 
 	databases map[*closeTrackingDB]struct{} // All open databases
 }
@@ -103,9 +108,16 @@ func New(conf *Config) (*Node, error) {
 	}
 	server := rpc.NewServer()
 	server.SetBatchLimits(conf.BatchRequestLimit, conf.BatchResponseMaxSize)
+	server.SetRequestLimits(conf.RequestMaxSize, conf.InflightRequestLimit)
+	execPools, err := rpc.NewExecutionPools(conf.ExecutionPools)
+	if err != nil {
+		return nil, err
+	}
+	server.SetExecutionPools(execPools)
 	node := &Node{
 		config:        conf,
 		inprocHandler: server,
+		execPools:     execPools,
 		eventmux:      new(event.TypeMux),
 		log:           conf.Logger,
 		stop:          make(chan struct{}),
@@ -291,6 +303,12 @@ func containsLifecycle(lfs []Lifecycle, l Lifecycle) bool {
 // stopServices terminates running services, RPC and p2p networking.
 // It is the inverse of Start.
 func (n *Node) stopServices(running []Lifecycle) error {
+	// Let lifecycles finish pending work while the RPC endpoints are still up.
+	for i := len(running) - 1; i >= 0; i-- {
+		if drainer, ok := running[i].(Drainer); ok {
+			drainer.Drain()
+		}
+	}
 	n.stopRPC()
 
 	// Stop running lifecycles in reverse order.
@@ -373,6 +391,23 @@ func (n *Node) obtainJWTSecret(cliParam string) ([]byte, error) {
 	return jwtSecret, nil
 }
 
+// openJWTSecretStore opens the store of the jwt-secret, loading it from an external
+// secret manager, or else from the file obtained by obtainJWTSecret. The secret
+// is periodically reloaded if configured, so that it can be rotated.
+func (n *Node) openJWTSecretStore(source string) (*jwtSecretStore, error) {
+	if IsExternalJWTSource(source) {
+		return newJWTSecretStore(source, nil, n.config.JWTSecretRefresh, n.config.JWTSecretGrace)
+	}
+	secret, err := n.obtainJWTSecret(source)
+	if err != nil {
+		return nil, err
+	}
+	if source == "" {
+		source = n.ResolvePath(datadirJWTKey)
+	}
+	return newJWTSecretStore(source, secret, n.config.JWTSecretRefresh, n.config.JWTSecretGrace)
+}
+
 // startRPC is a helper method to configure all the various RPC endpoints during node
 // startup. It's not meant to be called at any time afterwards as it makes certain
 // assumptions about the state of the node.
@@ -404,9 +439,22 @@ func (n *Node) startRPC() error {
 		openAPIs, allAPIs = n.getAPIs()
 	)
 
+	if n.config.APIKeys != "" {
+		store, err := newAPIKeyStore(n.config.APIKeys)
+		if err != nil {
+			return fmt.Errorf("failed to load API keys: %w", err)
+		}
+		store.start()
+		n.apiKeys = store
+	}
 	rpcConfig := rpcEndpointConfig{
+		apiKeys:                n.apiKeys,
 		batchItemLimit:         n.config.BatchRequestLimit,
 		batchResponseSizeLimit: n.config.BatchResponseMaxSize,
+		requestSizeLimit:       n.config.RequestMaxSize,
+		inflightLimit:          n.config.InflightRequestLimit,
+		responseCache:          n.responseCache,
+		execPools:              n.execPools,
 	}
 
 	initHttp := func(server *httpServer, port int) error {
@@ -435,6 +483,7 @@ func (n *Node) startRPC() error {
 			Modules:           n.config.WSModules,
 			Origins:           n.config.WSOrigins,
 			prefix:            n.config.WSPathPrefix,
+			limits:            n.config.WSLimits,
 			rpcEndpointConfig: rpcConfig,
 		}); err != nil {
 			return err
@@ -443,16 +492,21 @@ func (n *Node) startRPC() error {
 		return nil
 	}
 
-	initAuth := func(port int, secret []byte) error {
+	initAuth := func(port int, secrets *jwtSecretStore, tlsConfig *tls.Config) error {
 		// Enable auth via HTTP
 		server := n.httpAuth
 		if err := server.setListenAddr(n.config.AuthAddr, port); err != nil {
 			return err
 		}
+		if err := server.setTLSConfig(tlsConfig); err != nil {
+			return err
+		}
 		sharedConfig := rpcEndpointConfig{
-			jwtSecret:              secret,
+			jwtSecret:              secrets.secret(),
+			jwtSecrets:             secrets,
 			batchItemLimit:         engineAPIBatchItemLimit,
 			batchResponseSizeLimit: engineAPIBatchResponseSizeLimit,
+			execPools:              n.execPools,
 		}
 		if err := server.enableRPC(allAPIs, httpConfig{
 			CorsAllowedOrigins: DefaultAuthCors,
@@ -470,6 +524,9 @@ func (n *Node) startRPC() error {
 		if err := server.setListenAddr(n.config.AuthAddr, port); err != nil {
 			return err
 		}
+		if err := server.setTLSConfig(tlsConfig); err != nil {
+			return err
+		}
 		if err := server.enableWS(allAPIs, wsConfig{
 			Modules:           DefaultAuthModules,
 			Origins:           DefaultAuthOrigins,
@@ -498,11 +555,18 @@ func (n *Node) startRPC() error {
 	}
 	// Configure authenticated API
 	if len(openAPIs) != len(allAPIs) {
-		jwtSecret, err := n.obtainJWTSecret(n.config.JWTSecret)
+		tlsConfig, err := n.config.authTLSConfig()
 		if err != nil {
 			return err
 		}
-		if err := initAuth(n.config.AuthPort, jwtSecret); err != nil {
+		secrets, err := n.openJWTSecretStore(n.config.JWTSecret)
+		if err != nil {
+			return err
+		}
+		secrets.start()
+		n.jwtSecrets = secrets
+
+		if err := initAuth(n.config.AuthPort, secrets, tlsConfig); err != nil {
 			return err
 		}
 	}
@@ -533,6 +597,14 @@ func (n *Node) stopRPC() {
 	n.wsAuth.stop()
 	n.ipc.stop()
 	n.stopInProc()
+	if n.apiKeys != nil {
+		n.apiKeys.stop()
+		n.apiKeys = nil
+	}
+	if n.jwtSecrets != nil {
+		n.jwtSecrets.stop()
+		n.jwtSecrets = nil
+	}
 }
 
 // startInProc registers all RPC APIs on the inproc server.
@@ -591,6 +663,18 @@ func (n *Node) RegisterAPIs(apis []rpc.API) {
 	n.rpcAPIs = append(n.rpcAPIs, apis...)
 }
 
+// RegisterResponseCache sets the cache used by the public HTTP and WebSocket
+// servers to answer calls for immutable data.
+func (n *Node) RegisterResponseCache(cache rpc.ResponseCache) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.state != initializingState {
+		panic("can't register response cache on running/stopped node")
+	}
+	n.responseCache = cache
+}
+
 // getAPIs return two sets of APIs, both the ones that do not require
 // authentication, and the complete set
 func (n *Node) getAPIs() (unauthenticated, all []rpc.API) {
@@ -691,15 +775,15 @@ func (n *Node) WSEndpoint() string {
 
 // HTTPAuthEndpoint returns the URL of the authenticated HTTP server.
 func (n *Node) HTTPAuthEndpoint() string {
-	return "http://" + n.httpAuth.listenAddr()
+	return n.httpAuth.scheme(false) + "://" + n.httpAuth.listenAddr()
 }
 
 // WSAuthEndpoint returns the current authenticated JSON-RPC over WebSocket endpoint.
 func (n *Node) WSAuthEndpoint() string {
 	if n.httpAuth.wsAllowed() {
-		return "ws://" + n.httpAuth.listenAddr() + n.httpAuth.wsConfig.prefix
+		return n.httpAuth.scheme(true) + "://" + n.httpAuth.listenAddr() + n.httpAuth.wsConfig.prefix
 	}
-	return "ws://" + n.wsAuth.listenAddr() + n.wsAuth.wsConfig.prefix
+	return n.wsAuth.scheme(true) + "://" + n.wsAuth.listenAddr() + n.wsAuth.wsConfig.prefix
 }
 
 // EventMux retrieves the event multiplexer used by all the network services in
@@ -759,6 +843,7 @@ func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, ancient
 			Type:              n.config.DBEngine,
 			Directory:         n.ResolvePath(name),
 			AncientsDirectory: n.ResolveAncient(name, ancient),
+			StateDirectory:    n.ResolveState(name),
 			Namespace:         namespace,
 			Cache:             cache,
 			Handles:           handles,
@@ -788,6 +873,12 @@ func (n *Node) ResolveAncient(name string, ancient string) string {
 	return ancient
 }
 
+// ResolveState returns the absolute path of the separate state database with the
+// given name, empty if the state is kept along with the chain data.
+func (n *Node) ResolveState(name string) string {
+	return n.config.ResolveState(name)
+}
+
 // closeTrackingDB wraps the Close method of a database. When the database is closed by the
 // service, the wrapper removes it from the node's database map. This ensures that Node
 // won't auto-close the database if it is closed by the service that opened it.
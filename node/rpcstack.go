@@ -19,6 +19,7 @@ package node
 import (
 	"compress/gzip"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net"
@@ -30,6 +31,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum/internal/tracing"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/rs/cors"
@@ -49,13 +51,20 @@ type wsConfig struct {
 	Origins []string
 	Modules []string
 	prefix  string // path prefix on which to mount ws handler
+	limits  rpc.WebsocketLimits
 	rpcEndpointConfig
 }
 
 type rpcEndpointConfig struct {
-	jwtSecret              []byte // optional JWT secret
+	jwtSecret              []byte          // optional JWT secret
+	jwtSecrets             *jwtSecretStore // optional rotating JWT secrets, overriding jwtSecret
+	apiKeys                *apiKeyStore    // optional API key policies
 	batchItemLimit         int
 	batchResponseSizeLimit int
+	requestSizeLimit       int                 // maximum size of a request, transport default if zero
+	inflightLimit          int                 // maximum number of concurrent requests per connection, unlimited if zero
+	responseCache          rpc.ResponseCache   // optional cache of immutable call results
+	execPools              *rpc.ExecutionPools // optional worker pools executing the calls
 }
 
 type rpcHandler struct {
@@ -86,6 +95,8 @@ type httpServer struct {
 	host     string
 	port     int
 
+	tlsConfig *tls.Config // set by setTLSConfig, serves plain HTTP if nil
+
 	handlerNames map[string]string
 }
 
@@ -116,6 +127,36 @@ func (h *httpServer) setListenAddr(host string, port int) error {
 	return nil
 }
 
+// setTLSConfig configures the server to serve over TLS.
+// The configuration can only be set while the server isn't running.
+func (h *httpServer) setTLSConfig(config *tls.Config) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.listener != nil && config != h.tlsConfig {
+		return fmt.Errorf("HTTP server already running on %s", h.endpoint)
+	}
+	h.tlsConfig = config
+	return nil
+}
+
+// scheme returns the URL scheme of the server, for plain or websocket requests.
+func (h *httpServer) scheme(ws bool) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch {
+	case ws && h.tlsConfig != nil:
+		return "wss"
+	case ws:
+		return "ws"
+	case h.tlsConfig != nil:
+		return "https"
+	default:
+		return "http"
+	}
+}
+
 // listenAddr returns the listening address of the server.
 func (h *httpServer) listenAddr() string {
 	h.mu.Lock()
@@ -155,11 +196,16 @@ func (h *httpServer) start() error {
 		h.disableWS()
 		return err
 	}
+	scheme, wsScheme := "http", "ws"
+	if h.tlsConfig != nil {
+		listener = tls.NewListener(listener, h.tlsConfig)
+		scheme, wsScheme = "https", "wss"
+	}
 	h.listener = listener
 	go h.server.Serve(listener)
 
 	if h.wsAllowed() {
-		url := fmt.Sprintf("ws://%v", listener.Addr())
+		url := fmt.Sprintf("%s://%v", wsScheme, listener.Addr())
 		if h.wsConfig.prefix != "" {
 			url += h.wsConfig.prefix
 		}
@@ -172,6 +218,7 @@ func (h *httpServer) start() error {
 	// Log http endpoint.
 	h.log.Info("HTTP server started",
 		"endpoint", listener.Addr(), "auth", (h.httpConfig.jwtSecret != nil),
+		"tls", h.tlsConfig != nil,
 		"prefix", h.httpConfig.prefix,
 		"cors", strings.Join(h.httpConfig.CorsAllowedOrigins, ","),
 		"vhosts", strings.Join(h.httpConfig.Vhosts, ","),
@@ -187,7 +234,7 @@ func (h *httpServer) start() error {
 	for _, path := range paths {
 		name := h.handlerNames[path]
 		if !logged[name] {
-			log.Info(name+" enabled", "url", "http://"+listener.Addr().String()+path)
+			log.Info(name+" enabled", "url", scheme+"://"+listener.Addr().String()+path)
 			logged[name] = true
 		}
 	}
@@ -304,12 +351,21 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	// Create RPC server and handler.
 	srv := rpc.NewServer()
 	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	srv.SetRequestLimits(config.requestSizeLimit, config.inflightLimit)
+	srv.SetExecutionPools(config.execPools)
+	if config.responseCache != nil {
+		srv.SetResponseCache(config.responseCache)
+	}
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
+	var handler http.Handler = srv
+	if config.apiKeys != nil {
+		handler = newAPIKeyHandler(config.apiKeys, handler)
+	}
 	h.httpConfig = config
 	h.httpHandler.Store(&rpcHandler{
-		Handler: NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
+		Handler: newHTTPHandlerStack(handler, config.CorsAllowedOrigins, config.Vhosts, config.jwtKeys()),
 		server:  srv,
 	})
 	return nil
@@ -336,12 +392,22 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	// Create RPC server and handler.
 	srv := rpc.NewServer()
 	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	srv.SetRequestLimits(config.requestSizeLimit, config.inflightLimit)
+	srv.SetExecutionPools(config.execPools)
+	srv.SetWebsocketLimits(config.limits)
+	if config.responseCache != nil {
+		srv.SetResponseCache(config.responseCache)
+	}
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
+	handler := srv.WebsocketHandler(config.Origins)
+	if config.apiKeys != nil {
+		handler = newAPIKeyHandler(config.apiKeys, handler)
+	}
 	h.wsConfig = config
 	h.wsHandler.Store(&rpcHandler{
-		Handler: NewWSHandlerStack(srv.WebsocketHandler(config.Origins), config.jwtSecret),
+		Handler: newWSHandlerStack(handler, config.jwtKeys()),
 		server:  srv,
 	})
 	return nil
@@ -385,21 +451,47 @@ func isWebsocket(r *http.Request) bool {
 		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
 }
 
+// jwtKeys returns the JWT secrets accepted by the endpoint, or nil if it is not
+// authenticated.
+func (config rpcEndpointConfig) jwtKeys() func() [][]byte {
+	if config.jwtSecrets != nil {
+		return config.jwtSecrets.secrets
+	}
+	return staticJWTKeys(config.jwtSecret)
+}
+
+// staticJWTKeys returns the given JWT secret as the only one accepted, or nil if
+// the secret is empty.
+func staticJWTKeys(secret []byte) func() [][]byte {
+	if len(secret) == 0 {
+		return nil
+	}
+	return func() [][]byte { return [][]byte{secret} }
+}
+
 // NewHTTPHandlerStack returns wrapped http-related handlers
 func NewHTTPHandlerStack(srv http.Handler, cors []string, vhosts []string, jwtSecret []byte) http.Handler {
+	return newHTTPHandlerStack(srv, cors, vhosts, staticJWTKeys(jwtSecret))
+}
+
+func newHTTPHandlerStack(srv http.Handler, cors []string, vhosts []string, jwtKeys func() [][]byte) http.Handler {
 	// Wrap the CORS-handler within a host-handler
-	handler := newCorsHandler(srv, cors)
+	handler := newCorsHandler(tracing.Handler(srv), cors)
 	handler = newVHostHandler(vhosts, handler)
-	if len(jwtSecret) != 0 {
-		handler = newJWTHandler(jwtSecret, handler)
+	if jwtKeys != nil {
+		handler = newJWTHandler(jwtKeys, handler)
 	}
 	return newGzipHandler(handler)
 }
 
 // NewWSHandlerStack returns a wrapped ws-related handler.
 func NewWSHandlerStack(srv http.Handler, jwtSecret []byte) http.Handler {
-	if len(jwtSecret) != 0 {
-		return newJWTHandler(jwtSecret, srv)
+	return newWSHandlerStack(srv, staticJWTKeys(jwtSecret))
+}
+
+func newWSHandlerStack(srv http.Handler, jwtKeys func() [][]byte) http.Handler {
+	if jwtKeys != nil {
+		return newJWTHandler(jwtKeys, srv)
 	}
 	return srv
 }
@@ -147,6 +147,13 @@ func NewPeerPipe(id enode.ID, name string, caps []Cap, pipe *MsgPipeRW) *Peer {
 	return p
 }
 
+// NewTrustedPeerPipe creates a trusted peer for testing purposes.
+func NewTrustedPeerPipe(id enode.ID, name string, caps []Cap, pipe *MsgPipeRW) *Peer {
+	p := NewPeerPipe(id, name, caps, pipe)
+	p.rw.set(trustedConn, true)
+	return p
+}
+
 // ID returns the node's public key.
 func (p *Peer) ID() enode.ID {
 	return p.rw.node.ID()
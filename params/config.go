@@ -369,14 +369,32 @@ type ChainConfig struct {
 	RegolithTime *uint64  `json:"regolithTime,omitempty"` // Regolith switch time (nil = no fork, 0 = already on optimism regolith)
 	CanyonTime   *uint64  `json:"canyonTime,omitempty"`   // Canyon switch time (nil = no fork, 0 = already on optimism canyon)
 	// Delta: the Delta upgrade does not affect the execution-layer, and is thus not configurable in the chain config.
+	JovianTime *uint64 `json:"jovianTime,omitempty"` // Jovian switch time (nil = no fork, 0 = already on optimism jovian)
 
 	InteropTime *uint64 `json:"interopTime,omitempty"` // Interop switch time (nil = no fork, 0 = already on optimism interop)
 
+	OasysOperatorFeeTime *uint64 `json:"oasysOperatorFeeTime,omitempty"` // Operator fee switch time (nil = no fork, 0 = already enabled)
+	OasysL1VerifierTime  *uint64 `json:"oasysL1VerifierTime,omitempty"`  // L1 header and receipt verifier precompile switch time (nil = no fork, 0 = already enabled)
+	OasysStateGrowthTime *uint64 `json:"oasysStateGrowthTime,omitempty"` // Per-block state growth limit switch time (nil = no fork, 0 = already enabled)
+
+	OasysGasDimensionsTime *uint64 `json:"oasysGasDimensionsTime,omitempty"` // Per-block gas dimension limits switch time (nil = no fork, 0 = already enabled)
+	OasysBaseFeeCapTime    *uint64 `json:"oasysBaseFeeCapTime,omitempty"`    // Per-block base fee change cap switch time (nil = no fork, 0 = already enabled)
+	OasysBlockSizeTime     *uint64 `json:"oasysBlockSizeTime,omitempty"`     // Block size limit switch time (nil = no fork, 0 = already enabled)
+
+	// EVMForks schedules custom timestamp forks enabling or disabling individual
+	// EIPs in the EVM, on top of the instruction set of the standard forks.
+	EVMForks []EVMForkConfig `json:"evmForks,omitempty"`
+
 	// Toggle for enabling/disabling zero transaction fee
 	// From the timestamps set at even indices, transaction fees becomes zero.
 	// From the timestamps set at odd indices, transaction fees becomes required.
 	ZeroFeeTimes []uint64 `json:"zeroFeeTimes,omitempty"`
 
+	// PriorityFeePolicies schedules where the priority fees of the transactions
+	// are paid, in ascending order of time. Before the first policy, or without
+	// any, they are paid to the fee recipient of the block.
+	PriorityFeePolicies []PriorityFeePolicy `json:"priorityFeePolicies,omitempty"`
+
 	// TerminalTotalDifficulty is the amount of total difficulty reached by
 	// the network that triggers the consensus upgrade.
 	TerminalTotalDifficulty *big.Int `json:"terminalTotalDifficulty,omitempty"`
@@ -419,6 +437,39 @@ type OptimismConfig struct {
 	EIP1559Elasticity        uint64 `json:"eip1559Elasticity"`
 	EIP1559Denominator       uint64 `json:"eip1559Denominator"`
 	EIP1559DenominatorCanyon uint64 `json:"eip1559DenominatorCanyon"`
+
+	// EIP1559MaxChangeBps caps the per-block base fee change, in basis points of
+	// the parent base fee, from the base fee cap fork. It allows smoothing out
+	// short traffic bursts beyond what the change denominator alone permits. Zero
+	// disables the cap.
+	EIP1559MaxChangeBps uint64 `json:"eip1559MaxChangeBps,omitempty"`
+
+	// MinTipContract is the address of the contract whose storage holds the
+	// on-chain governed minimum priority fee enforced by the transaction pool
+	// and the block builder. Nil disables the governed fee floor.
+	MinTipContract *common.Address `json:"minTipContract,omitempty"`
+
+	// StateGrowthLimit caps the net number of accounts and storage slots created
+	// per block by the transactions other than deposits, from the state growth
+	// fork. Zero disables the limit.
+	StateGrowthLimit uint64 `json:"stateGrowthLimit,omitempty"`
+
+	// GasDimensions sets per-block limits on the resources accounted separately
+	// from the execution gas, from the gas dimensions fork. Nil disables the
+	// multi-dimensional accounting.
+	GasDimensions *GasDimensionsConfig `json:"gasDimensions,omitempty"`
+
+	// MaxBlockSize caps the RLP-encoded size of the blocks in bytes, from the
+	// block size fork, bounding the data posted to L1 for each block. Zero
+	// disables the limit.
+	MaxBlockSize uint64 `json:"maxBlockSize,omitempty"`
+}
+
+// GasDimensionsConfig holds the per-block limits of the gas dimensions. Zero
+// limits are not enforced.
+type GasDimensionsConfig struct {
+	MaxDABytes     uint64 `json:"maxDABytes,omitempty"`     // Encoded size of the transactions posted to L1
+	MaxStateGrowth uint64 `json:"maxStateGrowth,omitempty"` // Size of the contract code deployed
 }
 
 // String implements the stringer interface, returning the optimism fee config details.
@@ -426,6 +477,59 @@ func (o *OptimismConfig) String() string {
 	return "optimism"
 }
 
+// checkEVMForksCompatible checks the custom EVM forks already in effect at the
+// head are left unchanged by the new config.
+func (c *ChainConfig) checkEVMForksCompatible(newcfg *ChainConfig, headTimestamp uint64) *ConfigCompatError {
+	count := len(c.EVMForks)
+	if len(newcfg.EVMForks) > count {
+		count = len(newcfg.EVMForks)
+	}
+	for i := 0; i < count; i++ {
+		var stored, newer *EVMForkConfig
+		if i < len(c.EVMForks) {
+			stored = &c.EVMForks[i]
+		}
+		if i < len(newcfg.EVMForks) {
+			newer = &newcfg.EVMForks[i]
+		}
+		var storedTime, newTime *uint64
+		if stored != nil {
+			storedTime = &stored.Time
+		}
+		if newer != nil {
+			newTime = &newer.Time
+		}
+		if isForkTimestampIncompatible(storedTime, newTime, headTimestamp) {
+			return newTimestampCompatError(fmt.Sprintf("evmForks[%d] timestamp", i), storedTime, newTime)
+		}
+		if isTimestampForked(storedTime, headTimestamp) && (!equalEIPs(stored.EnableEIPs, newer.EnableEIPs) || !equalEIPs(stored.DisableEIPs, newer.DisableEIPs)) {
+			return newTimestampCompatError(fmt.Sprintf("evmForks[%d] eips", i), storedTime, newTime)
+		}
+	}
+	return nil
+}
+
+func equalEIPs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// EVMForkConfig is a custom timestamp fork enabling or disabling individual EIPs
+// in the EVM. The forks must be sorted by time.
+type EVMForkConfig struct {
+	Name        string `json:"name,omitempty"`
+	Time        uint64 `json:"time"`
+	EnableEIPs  []int  `json:"enableEIPs,omitempty"`
+	DisableEIPs []int  `json:"disableEIPs,omitempty"`
+}
+
 // Description returns a human-readable description of ChainConfig.
 func (c *ChainConfig) Description() string {
 	var banner string
@@ -585,9 +689,36 @@ func (c *ChainConfig) Description() string {
 	if c.CanyonTime != nil {
 		banner += fmt.Sprintf(" - Canyon:                      @%-10v\n", *c.CanyonTime)
 	}
+	if c.JovianTime != nil {
+		banner += fmt.Sprintf(" - Jovian:                      @%-10v\n", *c.JovianTime)
+	}
 	if c.InteropTime != nil {
 		banner += fmt.Sprintf(" - Interop:                     @%-10v\n", *c.InteropTime)
 	}
+	if c.OasysOperatorFeeTime != nil {
+		banner += fmt.Sprintf(" - Operator fee:                @%-10v\n", *c.OasysOperatorFeeTime)
+	}
+	if c.OasysL1VerifierTime != nil {
+		banner += fmt.Sprintf(" - L1 verifier precompile:      @%-10v\n", *c.OasysL1VerifierTime)
+	}
+	if c.OasysStateGrowthTime != nil {
+		banner += fmt.Sprintf(" - State growth limit:          @%-10v\n", *c.OasysStateGrowthTime)
+	}
+	if c.OasysGasDimensionsTime != nil {
+		banner += fmt.Sprintf(" - Gas dimension limits:        @%-10v\n", *c.OasysGasDimensionsTime)
+	}
+	if c.OasysBaseFeeCapTime != nil {
+		banner += fmt.Sprintf(" - Base fee change cap:         @%-10v\n", *c.OasysBaseFeeCapTime)
+	}
+	if c.OasysBlockSizeTime != nil {
+		banner += fmt.Sprintf(" - Block size limit:            @%-10v\n", *c.OasysBlockSizeTime)
+	}
+	for _, fork := range c.EVMForks {
+		banner += fmt.Sprintf(" - EVM fork %-19s @%-10v (enable: %v, disable: %v)\n", fork.Name+":", fork.Time, fork.EnableEIPs, fork.DisableEIPs)
+	}
+	for _, policy := range c.PriorityFeePolicies {
+		banner += fmt.Sprintf(" - Priority fees to %-11s @%-10v\n", policy.String()+":", policy.Time)
+	}
 	return banner
 }
 
@@ -706,6 +837,10 @@ func (c *ChainConfig) IsCanyon(time uint64) bool {
 	return isTimestampForked(c.CanyonTime, time)
 }
 
+func (c *ChainConfig) IsJovian(time uint64) bool {
+	return isTimestampForked(c.JovianTime, time)
+}
+
 func (c *ChainConfig) IsInterop(time uint64) bool {
 	return isTimestampForked(c.InteropTime, time)
 }
@@ -726,6 +861,83 @@ func (c *ChainConfig) IsOptimismRegolith(time uint64) bool {
 func (c *ChainConfig) IsOptimismCanyon(time uint64) bool {
 	return c.IsOptimism() && c.IsCanyon(time)
 }
+func (c *ChainConfig) IsOptimismJovian(time uint64) bool {
+	return c.IsOptimism() && c.IsJovian(time)
+}
+
+// EVMForkEIPs returns the EIPs enabled and disabled by the custom EVM forks active
+// at the given time, the later forks overriding the earlier ones.
+func (c *ChainConfig) EVMForkEIPs(time uint64) (enabled []int, disabled []int) {
+	// Called for every EVM, don't allocate without active forks
+	if len(c.EVMForks) == 0 || c.EVMForks[0].Time > time {
+		return nil, nil
+	}
+	var (
+		active = make(map[int]bool)
+		order  []int
+	)
+	set := func(eip int, enable bool) {
+		if _, ok := active[eip]; !ok {
+			order = append(order, eip)
+		}
+		active[eip] = enable
+	}
+	for _, fork := range c.EVMForks {
+		if fork.Time > time {
+			break
+		}
+		for _, eip := range fork.EnableEIPs {
+			set(eip, true)
+		}
+		for _, eip := range fork.DisableEIPs {
+			set(eip, false)
+		}
+	}
+	for _, eip := range order {
+		if active[eip] {
+			enabled = append(enabled, eip)
+		} else {
+			disabled = append(disabled, eip)
+		}
+	}
+	return enabled, disabled
+}
+
+// IsOasysOperatorFee returns whether the operator fee is charged to the
+// transactions at the given time.
+func (c *ChainConfig) IsOasysOperatorFee(time uint64) bool {
+	return c.IsOptimism() && isTimestampForked(c.OasysOperatorFeeTime, time)
+}
+
+// IsOasysL1Verifier returns whether the L1 header and receipt verifier precompile
+// is enabled at the given time.
+func (c *ChainConfig) IsOasysL1Verifier(time uint64) bool {
+	return c.IsOptimism() && isTimestampForked(c.OasysL1VerifierTime, time)
+}
+
+// IsOasysStateGrowth returns whether the per-block state growth limit is enforced
+// at the given time.
+func (c *ChainConfig) IsOasysStateGrowth(time uint64) bool {
+	return c.IsOptimism() && isTimestampForked(c.OasysStateGrowthTime, time)
+}
+
+// IsOasysGasDimensions returns whether the per-block gas dimension limits are
+// enforced at the given time.
+func (c *ChainConfig) IsOasysGasDimensions(time uint64) bool {
+	return c.IsOptimism() && isTimestampForked(c.OasysGasDimensionsTime, time)
+}
+
+// IsOasysBaseFeeCap returns whether the per-block base fee change cap applies to
+// the block at the given time.
+func (c *ChainConfig) IsOasysBaseFeeCap(time uint64) bool {
+	return c.IsOptimism() && isTimestampForked(c.OasysBaseFeeCapTime, time)
+}
+
+// IsOasysBlockSize returns whether the block size limit is enforced at the given
+// time.
+func (c *ChainConfig) IsOasysBlockSize(time uint64) bool {
+	return c.IsOptimism() && isTimestampForked(c.OasysBlockSizeTime, time)
+}
 
 // IsOptimismPreBedrock returns true iff this is an optimism node & bedrock is not yet active
 func (c *ChainConfig) IsOptimismPreBedrock(num *big.Int) bool {
@@ -870,7 +1082,7 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			}
 		}
 	}
-	return nil
+	return c.checkPriorityFeePolicies()
 }
 
 func (c *ChainConfig) checkCompatible(
@@ -973,6 +1185,27 @@ func (c *ChainConfig) checkCompatible(
 	if isForkTimestampIncompatible(c.VerkleTime, newcfg.VerkleTime, headTimestamp) {
 		return newTimestampCompatError("Verkle fork timestamp", c.VerkleTime, newcfg.VerkleTime)
 	}
+	if isForkTimestampIncompatible(c.JovianTime, newcfg.JovianTime, headTimestamp) {
+		return newTimestampCompatError("Jovian fork timestamp", c.JovianTime, newcfg.JovianTime)
+	}
+	if isForkTimestampIncompatible(c.OasysOperatorFeeTime, newcfg.OasysOperatorFeeTime, headTimestamp) {
+		return newTimestampCompatError("Oasys operator fee fork timestamp", c.OasysOperatorFeeTime, newcfg.OasysOperatorFeeTime)
+	}
+	if isForkTimestampIncompatible(c.OasysL1VerifierTime, newcfg.OasysL1VerifierTime, headTimestamp) {
+		return newTimestampCompatError("Oasys L1 verifier fork timestamp", c.OasysL1VerifierTime, newcfg.OasysL1VerifierTime)
+	}
+	if isForkTimestampIncompatible(c.OasysStateGrowthTime, newcfg.OasysStateGrowthTime, headTimestamp) {
+		return newTimestampCompatError("Oasys state growth fork timestamp", c.OasysStateGrowthTime, newcfg.OasysStateGrowthTime)
+	}
+	if isForkTimestampIncompatible(c.OasysGasDimensionsTime, newcfg.OasysGasDimensionsTime, headTimestamp) {
+		return newTimestampCompatError("Oasys gas dimensions fork timestamp", c.OasysGasDimensionsTime, newcfg.OasysGasDimensionsTime)
+	}
+	if isForkTimestampIncompatible(c.OasysBaseFeeCapTime, newcfg.OasysBaseFeeCapTime, headTimestamp) {
+		return newTimestampCompatError("Oasys base fee cap fork timestamp", c.OasysBaseFeeCapTime, newcfg.OasysBaseFeeCapTime)
+	}
+	if isForkTimestampIncompatible(c.OasysBlockSizeTime, newcfg.OasysBlockSizeTime, headTimestamp) {
+		return newTimestampCompatError("Oasys block size fork timestamp", c.OasysBlockSizeTime, newcfg.OasysBlockSizeTime)
+	}
 	if len(newcfg.ZeroFeeTimes) < len(c.ZeroFeeTimes) {
 		return errors.New("zeroFeeTimes: length of new config is shorter than stored config")
 	}
@@ -986,6 +1219,12 @@ func (c *ChainConfig) checkCompatible(
 			)
 		}
 	}
+	if err := c.checkPriorityFeePoliciesCompatible(newcfg, headTimestamp); err != nil {
+		return err
+	}
+	if err := c.checkEVMForksCompatible(newcfg, headTimestamp); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -1009,6 +1248,42 @@ func (c *ChainConfig) ElasticityMultiplier() uint64 {
 	return DefaultElasticityMultiplier
 }
 
+// MaxStateGrowth returns the maximum net number of accounts and storage slots
+// created by the transactions of a block at the given time, zero if unlimited.
+func (c *ChainConfig) MaxStateGrowth(time uint64) uint64 {
+	if c.IsOasysStateGrowth(time) {
+		return c.Optimism.StateGrowthLimit
+	}
+	return 0
+}
+
+// MaxBlockSize returns the maximum RLP-encoded size in bytes of a block at the
+// given time, zero if unlimited.
+func (c *ChainConfig) MaxBlockSize(time uint64) uint64 {
+	if c.IsOasysBlockSize(time) {
+		return c.Optimism.MaxBlockSize
+	}
+	return 0
+}
+
+// GasDimensionLimits returns the per-block limits of the gas dimensions at the
+// given time, nil if the multi-dimensional accounting is disabled.
+func (c *ChainConfig) GasDimensionLimits(time uint64) *GasDimensionsConfig {
+	if c.IsOasysGasDimensions(time) {
+		return c.Optimism.GasDimensions
+	}
+	return nil
+}
+
+// BaseFeeMaxChangeBps returns the cap on the base fee change of the block at the
+// given time in basis points of the parent base fee, or zero if not capped.
+func (c *ChainConfig) BaseFeeMaxChangeBps(time uint64) uint64 {
+	if c.IsOasysBaseFeeCap(time) {
+		return c.Optimism.EIP1559MaxChangeBps
+	}
+	return 0
+}
+
 // isForkBlockIncompatible returns true if a fork scheduled at block s1 cannot be
 // rescheduled to block s2 because head is already past the fork.
 func isForkBlockIncompatible(s1, s2, head *big.Int) bool {
@@ -1156,7 +1431,9 @@ type Rules struct {
 	IsMerge, IsShanghai, IsCancun, IsPrague                 bool
 	IsVerkle                                                bool
 	IsOptimismBedrock, IsOptimismRegolith                   bool
-	IsOptimismCanyon                                        bool
+	IsOptimismCanyon, IsOptimismJovian                      bool
+	IsOasysOperatorFee, IsOasysL1Verifier                   bool
+	EnabledEIPs, DisabledEIPs                               []int // EIPs toggled by the custom EVM forks
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -1165,6 +1442,7 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 	if chainID == nil {
 		chainID = new(big.Int)
 	}
+	enabledEIPs, disabledEIPs := c.EVMForkEIPs(timestamp)
 	return Rules{
 		ChainID:          new(big.Int).Set(chainID),
 		IsHomestead:      c.IsHomestead(num),
@@ -1186,5 +1464,11 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 		IsOptimismBedrock:  c.IsOptimismBedrock(num),
 		IsOptimismRegolith: c.IsOptimismRegolith(timestamp),
 		IsOptimismCanyon:   c.IsOptimismCanyon(timestamp),
+		IsOptimismJovian:   c.IsOptimismJovian(timestamp),
+		// Oasys
+		IsOasysOperatorFee: c.IsOasysOperatorFee(timestamp),
+		IsOasysL1Verifier:  c.IsOasysL1Verifier(timestamp),
+		EnabledEIPs:        enabledEIPs,
+		DisabledEIPs:       disabledEIPs,
 	}
 }
@@ -156,3 +156,80 @@ func TestConfigRulesRegolith(t *testing.T) {
 		t.Errorf("expected %v to be regolith", stamp)
 	}
 }
+
+func TestConfigRulesJovian(t *testing.T) {
+	c := &ChainConfig{
+		OasysOperatorFeeTime: newUint64(500),
+		JovianTime:           newUint64(1000),
+		Optimism:             &OptimismConfig{},
+	}
+	if r := c.Rules(big.NewInt(0), true, 999); r.IsOptimismJovian || !r.IsOasysOperatorFee {
+		t.Errorf("expected 999 to charge operator fees but not be jovian")
+	}
+	if r := c.Rules(big.NewInt(0), true, 1000); !r.IsOptimismJovian {
+		t.Errorf("expected 1000 to be jovian")
+	}
+	c.Optimism = nil
+	if r := c.Rules(big.NewInt(0), true, 1000); r.IsOptimismJovian {
+		t.Errorf("expected non-optimism chain to not be jovian")
+	}
+}
+
+func TestCheckJovianForkOrder(t *testing.T) {
+	jovian := &ChainConfig{JovianTime: newUint64(1000)}
+	if err := jovian.CheckConfigForkOrder(); err != nil {
+		t.Errorf("expected jovian without operator fees to be accepted: %v", err)
+	}
+	jovian.OasysOperatorFeeTime = newUint64(1001)
+	if err := jovian.CheckConfigForkOrder(); err != nil {
+		t.Errorf("expected jovian before operator fees to be accepted: %v", err)
+	}
+}
+
+func TestCheckJovianCompatible(t *testing.T) {
+	stored := &ChainConfig{JovianTime: newUint64(1000)}
+	if err := stored.CheckCompatible(&ChainConfig{JovianTime: newUint64(2000)}, 0, 500); err != nil {
+		t.Errorf("expected rescheduling a future jovian fork to be compatible: %v", err)
+	}
+	err, _ := stored.CheckCompatible(&ChainConfig{JovianTime: newUint64(2000)}, 0, 1500).(*ConfigCompatError)
+	if err == nil || err.What != "Jovian fork timestamp" {
+		t.Errorf("expected rescheduling an active jovian fork to be incompatible, got %v", err)
+	}
+	if err := stored.CheckCompatible(&ChainConfig{}, 0, 1500); err == nil {
+		t.Errorf("expected dropping an active jovian fork to be incompatible")
+	}
+}
+
+func TestCheckEVMForksCompatible(t *testing.T) {
+	stored := &ChainConfig{EVMForks: []EVMForkConfig{{Name: "nopush0", Time: 100, DisableEIPs: []int{3855}}}}
+	tests := []struct {
+		forks      []EVMForkConfig
+		head       uint64
+		compatible bool
+	}{
+		{[]EVMForkConfig{{Name: "renamed", Time: 100, DisableEIPs: []int{3855}}}, 150, true},
+		{[]EVMForkConfig{{Time: 100, DisableEIPs: []int{3855}}, {Time: 200, EnableEIPs: []int{5656}}}, 150, true},
+		{[]EVMForkConfig{{Time: 100, DisableEIPs: []int{5656}}}, 50, true},
+		{[]EVMForkConfig{{Time: 100, DisableEIPs: []int{5656}}}, 150, false},
+		{[]EVMForkConfig{{Time: 100, EnableEIPs: []int{3855}}}, 150, false},
+		{[]EVMForkConfig{{Time: 120, DisableEIPs: []int{3855}}}, 150, false},
+		{nil, 150, false},
+		{nil, 50, true},
+	}
+	for i, tt := range tests {
+		newcfg := &ChainConfig{EVMForks: tt.forks}
+		if err := stored.checkEVMForksCompatible(newcfg, tt.head); (err == nil) != tt.compatible {
+			t.Errorf("test %d: compatibility mismatch: have %v, want compatible %v", i, err, tt.compatible)
+		}
+	}
+}
+
+func TestEVMForkEIPsAllocs(t *testing.T) {
+	config := &ChainConfig{EVMForks: []EVMForkConfig{{Time: 100, DisableEIPs: []int{3855}}}}
+	if allocs := testing.AllocsPerRun(10, func() { config.EVMForkEIPs(50) }); allocs != 0 {
+		t.Errorf("allocations before the first fork: have %v, want 0", allocs)
+	}
+	if enabled, disabled := config.EVMForkEIPs(100); len(enabled) != 0 || len(disabled) != 1 || disabled[0] != 3855 {
+		t.Errorf("eips mismatch: enabled %v, disabled %v", enabled, disabled)
+	}
+}
@@ -0,0 +1,104 @@
+package params
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Modes of the priority fee policies.
+const (
+	PriorityFeeToRecipient = "recipient" // Paid to the fee recipient of the block
+	PriorityFeeToVault     = "vault"     // Paid to the vault of the policy
+	PriorityFeeBurn        = "burn"      // Not paid to anyone
+)
+
+// PriorityFeePolicy sets where the priority fees of the transactions are paid
+// from the given time.
+type PriorityFeePolicy struct {
+	Time  uint64          `json:"time"`
+	Mode  string          `json:"mode"`
+	Vault *common.Address `json:"vault,omitempty"` // Receiver of the fees in vault mode
+}
+
+func (p *PriorityFeePolicy) String() string {
+	if p.Mode == PriorityFeeToVault && p.Vault != nil {
+		return p.Vault.Hex()
+	}
+	return p.Mode
+}
+
+// PriorityFeeRecipient returns the account the priority fees of the transactions
+// of a block are paid to, given its fee recipient and time, or false if they are
+// burnt.
+func (c *ChainConfig) PriorityFeeRecipient(coinbase common.Address, time uint64) (common.Address, bool) {
+	for i := len(c.PriorityFeePolicies) - 1; i >= 0; i-- {
+		policy := &c.PriorityFeePolicies[i]
+		if policy.Time > time {
+			continue
+		}
+		switch policy.Mode {
+		case PriorityFeeToVault:
+			return *policy.Vault, true
+		case PriorityFeeBurn:
+			return common.Address{}, false
+		default:
+			return coinbase, true
+		}
+	}
+	return coinbase, true
+}
+
+// checkPriorityFeePolicies checks the policies are ordered and well formed.
+func (c *ChainConfig) checkPriorityFeePolicies() error {
+	for i, policy := range c.PriorityFeePolicies {
+		if i > 0 && policy.Time <= c.PriorityFeePolicies[i-1].Time {
+			return fmt.Errorf("priorityFeePolicies[%d]=@%d is not later than priorityFeePolicies[%d]=@%d", i, policy.Time, i-1, c.PriorityFeePolicies[i-1].Time)
+		}
+		switch policy.Mode {
+		case PriorityFeeToRecipient, PriorityFeeBurn:
+			if policy.Vault != nil {
+				return fmt.Errorf("priorityFeePolicies[%d]: vault set in %s mode", i, policy.Mode)
+			}
+		case PriorityFeeToVault:
+			if policy.Vault == nil {
+				return fmt.Errorf("priorityFeePolicies[%d]: vault mode without vault", i)
+			}
+		default:
+			return fmt.Errorf("priorityFeePolicies[%d]: unknown mode %q", i, policy.Mode)
+		}
+	}
+	return nil
+}
+
+// checkPriorityFeePoliciesCompatible checks the policies already in effect at the
+// head are left unchanged by the new config.
+func (c *ChainConfig) checkPriorityFeePoliciesCompatible(newcfg *ChainConfig, headTimestamp uint64) *ConfigCompatError {
+	count := len(c.PriorityFeePolicies)
+	if len(newcfg.PriorityFeePolicies) > count {
+		count = len(newcfg.PriorityFeePolicies)
+	}
+	for i := 0; i < count; i++ {
+		var stored, newer *PriorityFeePolicy
+		if i < len(c.PriorityFeePolicies) {
+			stored = &c.PriorityFeePolicies[i]
+		}
+		if i < len(newcfg.PriorityFeePolicies) {
+			newer = &newcfg.PriorityFeePolicies[i]
+		}
+		var storedTime, newTime *uint64
+		if stored != nil {
+			storedTime = &stored.Time
+		}
+		if newer != nil {
+			newTime = &newer.Time
+		}
+		if isForkTimestampIncompatible(storedTime, newTime, headTimestamp) {
+			return newTimestampCompatError(fmt.Sprintf("priorityFeePolicies[%d] timestamp", i), storedTime, newTime)
+		}
+		if isTimestampForked(storedTime, headTimestamp) && (stored.Mode != newer.Mode || stored.String() != newer.String()) {
+			return newTimestampCompatError(fmt.Sprintf("priorityFeePolicies[%d] policy", i), storedTime, newTime)
+		}
+	}
+	return nil
+}
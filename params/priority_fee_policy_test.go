@@ -0,0 +1,91 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestPriorityFeeRecipient(t *testing.T) {
+	var (
+		coinbase = common.HexToAddress("0xc0ffee")
+		vault    = common.HexToAddress("0x7a017")
+	)
+	c := &ChainConfig{
+		PriorityFeePolicies: []PriorityFeePolicy{
+			{Time: 100, Mode: PriorityFeeToVault, Vault: &vault},
+			{Time: 200, Mode: PriorityFeeBurn},
+			{Time: 300, Mode: PriorityFeeToRecipient},
+		},
+	}
+	if err := c.checkPriorityFeePolicies(); err != nil {
+		t.Fatalf("valid policies rejected: %v", err)
+	}
+	tests := []struct {
+		time      uint64
+		recipient common.Address
+		paid      bool
+	}{
+		{0, coinbase, true},
+		{99, coinbase, true},
+		{100, vault, true},
+		{199, vault, true},
+		{200, common.Address{}, false},
+		{300, coinbase, true},
+	}
+	for _, tt := range tests {
+		recipient, paid := c.PriorityFeeRecipient(coinbase, tt.time)
+		if recipient != tt.recipient || paid != tt.paid {
+			t.Errorf("time %d: recipient mismatch: have %v/%v, want %v/%v", tt.time, recipient, paid, tt.recipient, tt.paid)
+		}
+	}
+}
+
+func TestCheckPriorityFeePolicies(t *testing.T) {
+	vault := common.HexToAddress("0x7a017")
+	tests := []struct {
+		policies []PriorityFeePolicy
+		valid    bool
+	}{
+		{[]PriorityFeePolicy{{Time: 10, Mode: PriorityFeeBurn}, {Time: 20, Mode: PriorityFeeToVault, Vault: &vault}}, true},
+		{[]PriorityFeePolicy{{Time: 20, Mode: PriorityFeeBurn}, {Time: 10, Mode: PriorityFeeToRecipient}}, false},
+		{[]PriorityFeePolicy{{Time: 10, Mode: PriorityFeeBurn}, {Time: 10, Mode: PriorityFeeToRecipient}}, false},
+		{[]PriorityFeePolicy{{Time: 10, Mode: PriorityFeeToVault}}, false},
+		{[]PriorityFeePolicy{{Time: 10, Mode: PriorityFeeBurn, Vault: &vault}}, false},
+		{[]PriorityFeePolicy{{Time: 10, Mode: "unknown"}}, false},
+	}
+	for i, tt := range tests {
+		c := &ChainConfig{PriorityFeePolicies: tt.policies}
+		if err := c.checkPriorityFeePolicies(); (err == nil) != tt.valid {
+			t.Errorf("test %d: validity mismatch: have %v, want valid %v", i, err, tt.valid)
+		}
+	}
+}
+
+func TestCheckPriorityFeePoliciesCompatible(t *testing.T) {
+	var (
+		vault  = common.HexToAddress("0x7a017")
+		other  = common.HexToAddress("0x07e5")
+		stored = &ChainConfig{PriorityFeePolicies: []PriorityFeePolicy{{Time: 100, Mode: PriorityFeeToVault, Vault: &vault}}}
+	)
+	tests := []struct {
+		policies   []PriorityFeePolicy
+		head       uint64
+		compatible bool
+	}{
+		{[]PriorityFeePolicy{{Time: 100, Mode: PriorityFeeToVault, Vault: &vault}}, 150, true},
+		{[]PriorityFeePolicy{{Time: 100, Mode: PriorityFeeToVault, Vault: &vault}, {Time: 200, Mode: PriorityFeeBurn}}, 150, true},
+		{[]PriorityFeePolicy{{Time: 100, Mode: PriorityFeeToVault, Vault: &other}}, 50, true},
+		{[]PriorityFeePolicy{{Time: 100, Mode: PriorityFeeToVault, Vault: &other}}, 150, false},
+		{[]PriorityFeePolicy{{Time: 100, Mode: PriorityFeeBurn}}, 150, false},
+		{[]PriorityFeePolicy{{Time: 120, Mode: PriorityFeeToVault, Vault: &vault}}, 150, false},
+		{nil, 150, false},
+		{nil, 50, true},
+	}
+	for i, tt := range tests {
+		newcfg := &ChainConfig{PriorityFeePolicies: tt.policies}
+		if err := stored.checkPriorityFeePoliciesCompatible(newcfg, tt.head); (err == nil) != tt.compatible {
+			t.Errorf("test %d: compatibility mismatch: have %v, want compatible %v", i, err, tt.compatible)
+		}
+	}
+}
@@ -27,6 +27,10 @@ var (
 	OptimismBaseFeeRecipient = common.HexToAddress("0x4200000000000000000000000000000000000019")
 	// The L1 portion of the transaction fee accumulates at this predeploy
 	OptimismL1FeeRecipient = common.HexToAddress("0x420000000000000000000000000000000000001A")
+	// The operator portion of the transaction fee accumulates at this predeploy, from the operator fee fork
+	OptimismOperatorFeeRecipient = common.HexToAddress("0x420000000000000000000000000000000000001B")
+	// Withdrawals to L1 are initiated through this predeploy
+	OptimismL2ToL1MessagePasser = common.HexToAddress("0x4200000000000000000000000000000000000016")
 )
 
 const (
@@ -166,6 +170,11 @@ const (
 	Bls12381MapG1Gas          uint64 = 5500   // Gas price for BLS12-381 mapping field element to G1 operation
 	Bls12381MapG2Gas          uint64 = 110000 // Gas price for BLS12-381 mapping field element to G2 operation
 
+	L1VerifierBaseGas        uint64 = 3000 // Base price for the L1 header and receipt verifier precompile
+	L1VerifierPerWordGas     uint64 = 100  // Per-word price for decoding the headers and proof nodes of the L1 verifier precompile
+	L1VerifierStorageReadGas uint64 = 2100 // Price of the L1 origin read from the L1Block storage, as a cold SLOAD
+	L1VerifierMaxHeaders            = 256  // Maximum number of headers verified by the L1 verifier precompile
+
 	// The Refund Quotient is the cap on how much of the used gas can be refunded. Before EIP-3529,
 	// up to half the consumed gas could be refunded. Redefined as 1/5th in EIP-3529
 	RefundQuotient        uint64 = 2
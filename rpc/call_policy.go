@@ -0,0 +1,25 @@
+package rpc
+
+import "context"
+
+// CallPolicy restricts the methods a client may call. Policies are attached to
+// the request context by transport middleware, e.g. API key authentication, and
+// are consulted by the server before every call.
+type CallPolicy interface {
+	// AllowCall returns a non-nil error if the given method may not be called.
+	// Errors implementing Error are returned to the client with their code.
+	AllowCall(method string) error
+}
+
+type callPolicyKey struct{}
+
+// WithCallPolicy returns a copy of ctx with the given call policy attached.
+func WithCallPolicy(ctx context.Context, policy CallPolicy) context.Context {
+	return context.WithValue(ctx, callPolicyKey{}, policy)
+}
+
+// callPolicyFromContext returns the call policy attached to ctx, if any.
+func callPolicyFromContext(ctx context.Context) CallPolicy {
+	policy, _ := ctx.Value(callPolicyKey{}).(CallPolicy)
+	return policy
+}
@@ -88,6 +88,10 @@ type Client struct {
 	// config fields
 	batchItemLimit       int
 	batchResponseMaxSize int
+	inflightLimit        int
+	subscriptionLimit    int
+	responseCache        ResponseCache
+	execPools            *ExecutionPools
 
 	// writeConn is used for writing to the connection on the caller's goroutine. It should
 	// only be accessed outside of dispatch, with the write lock held. The write lock is
@@ -117,9 +121,16 @@ type clientConn struct {
 
 func (c *Client) newClientConn(conn ServerCodec) *clientConn {
 	ctx := context.Background()
+	if cc, ok := conn.(interface{ connContext() context.Context }); ok && cc.connContext() != nil {
+		ctx = cc.connContext()
+	}
 	ctx = context.WithValue(ctx, clientContextKey{}, c)
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, conn.peerInfo())
 	handler := newHandler(ctx, conn, c.idgen, c.services, c.batchItemLimit, c.batchResponseMaxSize)
+	handler.responseCache = c.responseCache
+	handler.inflightLimit = c.inflightLimit
+	handler.subscriptionLimit = c.subscriptionLimit
+	handler.execPools = c.execPools
 	return &clientConn{conn, handler}
 }
 
@@ -247,6 +258,10 @@ func initClient(conn ServerCodec, services *serviceRegistry, cfg *clientConfig)
 		idgen:                cfg.idgen,
 		batchItemLimit:       cfg.batchItemLimit,
 		batchResponseMaxSize: cfg.batchResponseLimit,
+		inflightLimit:        cfg.inflightLimit,
+		subscriptionLimit:    cfg.subscriptionLimit,
+		responseCache:        cfg.responseCache,
+		execPools:            cfg.execPools,
 		writeConn:            conn,
 		close:                make(chan struct{}),
 		closing:              make(chan struct{}),
@@ -41,6 +41,10 @@ type clientConfig struct {
 	idgen              func() ID
 	batchItemLimit     int
 	batchResponseLimit int
+	inflightLimit      int
+	subscriptionLimit  int
+	responseCache      ResponseCache
+	execPools          *ExecutionPools
 }
 
 func (cfg *clientConfig) initHeaders() {
@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type (
+	methodKey         struct{}
+	latestBlockTagKey struct{}
+)
+
+// MethodFromContext returns the name of the RPC method being served with the
+// given context, if any.
+func MethodFromContext(ctx context.Context) (string, bool) {
+	method, ok := ctx.Value(methodKey{}).(string)
+	return method, ok
+}
+
+// NamespaceFromContext returns the namespace of the RPC method being served with
+// the given context, if any.
+func NamespaceFromContext(ctx context.Context) (string, bool) {
+	method, ok := MethodFromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	namespace, _, found := strings.Cut(method, serviceMethodSeparator)
+	return namespace, found
+}
+
+// WithLatestBlockTag returns a copy of ctx in which the "latest" block tag is
+// resolved to the given tag by backends supporting it. This allows callers to
+// pin a request to the safe or finalized head.
+func WithLatestBlockTag(ctx context.Context, tag BlockNumber) context.Context {
+	return context.WithValue(ctx, latestBlockTagKey{}, tag)
+}
+
+// LatestBlockTagFromContext returns the block tag "latest" should be resolved to
+// for the given request context, if one was set.
+func LatestBlockTagFromContext(ctx context.Context) (BlockNumber, bool) {
+	tag, ok := ctx.Value(latestBlockTagKey{}).(BlockNumber)
+	return tag, ok
+}
+
+// ParseLatestBlockTag parses the head a "latest" block tag may be mapped to. The
+// unsafe head is accepted under both its rollup name "unsafe" and "latest".
+func ParseLatestBlockTag(tag string) (BlockNumber, error) {
+	switch strings.ToLower(strings.TrimSpace(tag)) {
+	case "", "unsafe", "latest":
+		return LatestBlockNumber, nil
+	case "safe":
+		return SafeBlockNumber, nil
+	case "finalized":
+		return FinalizedBlockNumber, nil
+	default:
+		return LatestBlockNumber, fmt.Errorf("invalid latest block tag %q, want unsafe, safe or finalized", tag)
+	}
+}
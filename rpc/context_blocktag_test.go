@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseLatestBlockTag(t *testing.T) {
+	tests := []struct {
+		input string
+		want  BlockNumber
+		fail  bool
+	}{
+		{input: "", want: LatestBlockNumber},
+		{input: "unsafe", want: LatestBlockNumber},
+		{input: "latest", want: LatestBlockNumber},
+		{input: " Safe ", want: SafeBlockNumber},
+		{input: "FINALIZED", want: FinalizedBlockNumber},
+		{input: "pending", fail: true},
+		{input: "0x1", fail: true},
+	}
+	for _, test := range tests {
+		tag, err := ParseLatestBlockTag(test.input)
+		if test.fail {
+			if err == nil {
+				t.Errorf("%q: expected error", test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.input, err)
+			continue
+		}
+		if tag != test.want {
+			t.Errorf("%q: got %d, want %d", test.input, tag, test.want)
+		}
+	}
+}
+
+func TestLatestBlockTagContext(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := LatestBlockTagFromContext(ctx); ok {
+		t.Fatal("tag found in empty context")
+	}
+	ctx = WithLatestBlockTag(ctx, SafeBlockNumber)
+	if tag, ok := LatestBlockTagFromContext(ctx); !ok || tag != SafeBlockNumber {
+		t.Fatalf("got %d (%v), want %d", tag, ok, SafeBlockNumber)
+	}
+	ctx = WithLatestBlockTag(ctx, FinalizedBlockNumber)
+	if tag, ok := LatestBlockTagFromContext(ctx); !ok || tag != FinalizedBlockNumber {
+		t.Fatalf("got %d (%v), want %d", tag, ok, FinalizedBlockNumber)
+	}
+}
+
+func TestNamespaceFromContext(t *testing.T) {
+	if _, ok := NamespaceFromContext(context.Background()); ok {
+		t.Fatal("namespace found in empty context")
+	}
+	ctx := context.WithValue(context.Background(), methodKey{}, "eth_blockNumber")
+	if namespace, ok := NamespaceFromContext(ctx); !ok || namespace != "eth" {
+		t.Fatalf("got %q (%v), want eth", namespace, ok)
+	}
+}
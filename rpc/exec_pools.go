@@ -0,0 +1,131 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const errMsgPoolQueueFull = "execution pool queue full"
+
+// ExecutionPoolConfig configures a pool of workers executing the calls of a set
+// of namespaces.
+type ExecutionPoolConfig struct {
+	Name       string   // Name of the pool, used in metrics
+	Namespaces []string // Namespaces of the calls executed by the pool, "*" matching all others
+	Workers    int      // Maximum number of calls executed concurrently
+	QueueSize  int      // Maximum number of calls waiting for a worker, rejected beyond
+}
+
+// ExecutionPools dispatches the calls to the worker pool of their namespace, so a
+// burst of calls in a namespace can't hold up the calls of the others. The pools
+// may be shared by several servers, bounding the calls across all endpoints. The
+// calls of namespaces without pool are executed right away.
+type ExecutionPools struct {
+	pools    map[string]*execPool // Pools by namespace
+	fallback *execPool            // Pool of the namespaces without dedicated pool, if any
+}
+
+// execPool bounds the number of running and waiting calls of its namespaces.
+type execPool struct {
+	name    string
+	admit   chan struct{} // Slots of the running and waiting calls
+	workers chan struct{} // Slots of the running calls
+
+	runningGauge  metrics.Gauge
+	queuedGauge   metrics.Gauge
+	rejectedMeter metrics.Meter
+}
+
+// NewExecutionPools creates the worker pools described by the given configs.
+func NewExecutionPools(configs []ExecutionPoolConfig) (*ExecutionPools, error) {
+	p := &ExecutionPools{pools: make(map[string]*execPool)}
+	names := make(map[string]bool)
+	for _, config := range configs {
+		if config.Name == "" || len(config.Namespaces) == 0 {
+			return nil, errors.New("execution pool without name or namespace")
+		}
+		if names[config.Name] {
+			return nil, fmt.Errorf("duplicate execution pool %q", config.Name)
+		}
+		names[config.Name] = true
+		if config.Workers <= 0 || config.QueueSize < 0 {
+			return nil, fmt.Errorf("execution pool %q: invalid workers %d or queue size %d", config.Name, config.Workers, config.QueueSize)
+		}
+		pool := &execPool{
+			name:          config.Name,
+			admit:         make(chan struct{}, config.Workers+config.QueueSize),
+			workers:       make(chan struct{}, config.Workers),
+			runningGauge:  metrics.GetOrRegisterGauge("rpc/pools/"+config.Name+"/running", nil),
+			queuedGauge:   metrics.GetOrRegisterGauge("rpc/pools/"+config.Name+"/queued", nil),
+			rejectedMeter: metrics.GetOrRegisterMeter("rpc/pools/"+config.Name+"/rejected", nil),
+		}
+		for _, namespace := range config.Namespaces {
+			if namespace == "*" {
+				if p.fallback != nil {
+					return nil, fmt.Errorf("execution pool %q: fallback pool already set", config.Name)
+				}
+				p.fallback = pool
+				continue
+			}
+			if _, ok := p.pools[namespace]; ok {
+				return nil, fmt.Errorf("execution pool %q: namespace %q in multiple pools", config.Name, namespace)
+			}
+			p.pools[namespace] = pool
+		}
+	}
+	return p, nil
+}
+
+// SetExecutionPools installs the worker pools executing the calls of the server.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetExecutionPools(pools *ExecutionPools) {
+	s.execPools = pools
+}
+
+// acquire waits for a worker of the pool of the method, returning the function
+// releasing it. An error is returned if the pool queue is full or if the context
+// is canceled while waiting.
+func (p *ExecutionPools) acquire(ctx context.Context, method string) (func(), error) {
+	if p == nil {
+		return func() {}, nil
+	}
+	namespace, _, _ := strings.Cut(method, serviceMethodSeparator)
+	pool, ok := p.pools[namespace]
+	if !ok {
+		pool = p.fallback
+	}
+	if pool == nil {
+		return func() {}, nil
+	}
+	return pool.acquire(ctx)
+}
+
+func (p *execPool) acquire(ctx context.Context) (func(), error) {
+	select {
+	case p.admit <- struct{}{}:
+	default:
+		p.rejectedMeter.Mark(1)
+		return nil, &limitExceededError{errMsgPoolQueueFull, cap(p.admit) - cap(p.workers)}
+	}
+	p.queuedGauge.Inc(1)
+	select {
+	case p.workers <- struct{}{}:
+		p.queuedGauge.Dec(1)
+	case <-ctx.Done():
+		p.queuedGauge.Dec(1)
+		<-p.admit
+		return nil, ctx.Err()
+	}
+	p.runningGauge.Inc(1)
+	return func() {
+		p.runningGauge.Dec(1)
+		<-p.workers
+		<-p.admit
+	}, nil
+}
@@ -0,0 +1,126 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExecutionPoolQueue(t *testing.T) {
+	pools, err := NewExecutionPools([]ExecutionPoolConfig{
+		{Name: "test", Namespaces: []string{"test"}, Workers: 1, QueueSize: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	release, err := pools.acquire(context.Background(), "test_block")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The second call waits for the worker, the third one is rejected
+	acquired := make(chan func())
+	go func() {
+		release, err := pools.acquire(context.Background(), "test_echo")
+		if err != nil {
+			t.Error(err)
+		}
+		acquired <- release
+	}()
+	for deadline := time.Now().Add(5 * time.Second); len(pools.pools["test"].admit) < 2; {
+		if time.Now().After(deadline) {
+			t.Fatal("call not queued")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	var exceeded *limitExceededError
+	if _, err := pools.acquire(context.Background(), "test_null"); !errors.As(err, &exceeded) {
+		t.Fatalf("queue limit not enforced: %v", err)
+	}
+	// Namespaces without pool are not affected
+	if release, err := pools.acquire(context.Background(), "eth_call"); err != nil {
+		t.Fatalf("call of other namespace rejected: %v", err)
+	} else {
+		release()
+	}
+	select {
+	case <-acquired:
+		t.Fatal("queued call executed while worker busy")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release()
+	select {
+	case release := <-acquired:
+		release()
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued call not executed after release")
+	}
+	// Queued calls are abandoned when their context is canceled
+	release, _ = pools.acquire(context.Background(), "test_block")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := pools.acquire(ctx, "test_echo"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wrong error on canceled wait: %v", err)
+	}
+	release()
+	if release, err := pools.acquire(context.Background(), "test_echo"); err != nil {
+		t.Fatalf("call rejected after canceled wait: %v", err)
+	} else {
+		release()
+	}
+}
+
+func TestExecutionPoolConfig(t *testing.T) {
+	for i, configs := range [][]ExecutionPoolConfig{
+		{{Name: "a", Namespaces: []string{"eth"}, Workers: 0}},
+		{{Name: "a", Namespaces: []string{"eth"}, Workers: 1, QueueSize: -1}},
+		{{Name: "a", Workers: 1}},
+		{{Name: "a", Namespaces: []string{"eth"}, Workers: 1}, {Name: "a", Namespaces: []string{"debug"}, Workers: 1}},
+		{{Name: "a", Namespaces: []string{"eth"}, Workers: 1}, {Name: "b", Namespaces: []string{"eth"}, Workers: 1}},
+		{{Name: "a", Namespaces: []string{"*"}, Workers: 1}, {Name: "b", Namespaces: []string{"*"}, Workers: 1}},
+	} {
+		if _, err := NewExecutionPools(configs); err == nil {
+			t.Errorf("config %d: invalid pools accepted", i)
+		}
+	}
+}
+
+func TestExecutionPoolServer(t *testing.T) {
+	pools, err := NewExecutionPools([]ExecutionPoolConfig{
+		{Name: "test", Namespaces: []string{"test"}, Workers: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newTestServer()
+	server.SetExecutionPools(pools)
+	defer server.Stop()
+
+	client := DialInProc(server)
+	defer client.Close()
+
+	// Occupy the only worker of the pool with a blocking call
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		client.CallContext(ctx, nil, "test_block")
+		close(done)
+	}()
+	var rpcErr Error
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		err := client.Call(nil, "test_null")
+		if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == errcodeLimitExceeded {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("pool limit not enforced")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// The other namespaces keep being served
+	if err := client.Call(nil, "nftest_echo", 1); err != nil {
+		t.Fatalf("call of other namespace rejected: %v", err)
+	}
+	cancel()
+	<-done
+}
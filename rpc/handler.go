@@ -23,8 +23,10 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum/internal/tracing"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -62,6 +64,11 @@ type handler struct {
 	allowSubscribe       bool
 	batchRequestLimit    int
 	batchResponseMaxSize int
+	responseCache        ResponseCache   // optional cache of call results
+	inflightLimit        int             // maximum number of calls processed concurrently, 0 if unlimited
+	inflight             atomic.Int32    // number of calls being processed
+	subscriptionLimit    int             // maximum number of subscriptions, 0 if unlimited
+	execPools            *ExecutionPools // optional worker pools executing the calls
 
 	subLock    sync.Mutex
 	serverSubs map[ID]*Subscription
@@ -70,6 +77,7 @@ type handler struct {
 type callProc struct {
 	ctx       context.Context
 	notifiers []*Notifier
+	release   func() // frees the in-flight slot held by the calls, if any
 }
 
 func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *serviceRegistry, batchRequestLimit, batchResponseMaxSize int) *handler {
@@ -176,6 +184,7 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 	}
 	// Apply limit on total number of requests.
 	if h.batchRequestLimit != 0 && len(msgs) > h.batchRequestLimit {
+		batchLimitMeter.Mark(1)
 		h.startCallProc(func(cp *callProc) {
 			h.respondWithBatchTooLarge(cp, msgs)
 		})
@@ -191,9 +200,18 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 	if len(calls) == 0 {
 		return
 	}
+	if !h.acquireInflight() {
+		h.startCallProc(func(cp *callProc) {
+			h.respondWithInflightLimit(cp, calls, true)
+		})
+		return
+	}
 
 	// Process calls on a goroutine because they may block indefinitely:
 	h.startCallProc(func(cp *callProc) {
+		cp.release = h.releaseInflight
+		defer cp.releaseInflight()
+
 		var (
 			timer      *time.Timer
 			cancel     context.CancelFunc
@@ -238,6 +256,7 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 		if timer != nil {
 			timer.Stop()
 		}
+		cp.releaseInflight()
 
 		h.addSubscriptions(cp.notifiers)
 		callBuffer.write(cp.ctx, h.conn)
@@ -265,7 +284,15 @@ func (h *handler) respondWithBatchTooLarge(cp *callProc, batch []*jsonrpcMessage
 func (h *handler) handleMsg(msg *jsonrpcMessage) {
 	msgs := []*jsonrpcMessage{msg}
 	h.handleResponses(msgs, func(msg *jsonrpcMessage) {
+		if !h.acquireInflight() {
+			h.startCallProc(func(cp *callProc) {
+				h.respondWithInflightLimit(cp, msgs, false)
+			})
+			return
+		}
 		h.startCallProc(func(cp *callProc) {
+			cp.release = h.releaseInflight
+			defer cp.releaseInflight()
 			h.handleNonBatchCall(cp, msg)
 		})
 	})
@@ -297,6 +324,7 @@ func (h *handler) handleNonBatchCall(cp *callProc, msg *jsonrpcMessage) {
 	if timer != nil {
 		timer.Stop()
 	}
+	cp.releaseInflight()
 	h.addSubscriptions(cp.notifiers)
 	if answer != nil {
 		responded.Do(func() {
@@ -491,6 +519,11 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	if policy := callPolicyFromContext(cp.ctx); policy != nil && !msg.isUnsubscribe() {
+		if err := policy.AllowCall(msg.Method); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg)
 	}
@@ -503,13 +536,36 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	if callb == nil {
 		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
 	}
+	cacheable := h.responseCache != nil && callb != h.unsubscribeCb
+	if cacheable {
+		if result, ok := h.responseCache.Get(msg.Method, msg.Params); ok {
+			return &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: result}
+		}
+	}
 
 	args, err := parsePositionalArguments(msg.Params, callb.argTypes)
 	if err != nil {
 		return msg.errorResponse(&invalidParamsError{err.Error()})
 	}
+	if callb != h.unsubscribeCb {
+		release, err := h.execPools.acquire(cp.ctx, msg.Method)
+		if err != nil {
+			return msg.errorResponse(err)
+		}
+		defer release()
+	}
 	start := time.Now()
-	answer := h.runMethod(cp.ctx, msg, callb, args)
+	ctx := context.WithValue(cp.ctx, methodKey{}, msg.Method)
+	ctx, span := tracing.StartKind(ctx, msg.Method, tracing.KindServer)
+	answer := h.runMethod(ctx, msg, callb, args)
+	if answer.Error != nil {
+		span.SetError(answer.Error)
+	}
+	span.End()
+
+	if cacheable && answer.Error == nil {
+		h.responseCache.Add(msg.Method, msg.Params, answer.Result)
+	}
 
 	// Collect the statistics for RPC calls if metrics is enabled.
 	// We only care about pure rpc call. Filter out subscription.
@@ -532,6 +588,9 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 	if !h.allowSubscribe {
 		return msg.errorResponse(ErrNotificationsUnsupported)
 	}
+	if !h.acquireSubscription() {
+		return msg.errorResponse(&limitExceededError{errMsgTooManySubscriptions, h.subscriptionLimit})
+	}
 
 	// Subscription method name is first argument.
 	name, err := parseSubscriptionName(msg.Params)
@@ -0,0 +1,116 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	batchLimitMeter    = metrics.NewRegisteredMeter("rpc/limits/batch", nil)
+	sizeLimitMeter     = metrics.NewRegisteredMeter("rpc/limits/size", nil)
+	inflightLimitMeter = metrics.NewRegisteredMeter("rpc/limits/inflight", nil)
+)
+
+const (
+	errcodeLimitExceeded = -32005
+
+	errMsgRequestTooLarge = "request too large"
+	errMsgTooManyInflight = "too many in-flight requests"
+)
+
+// limitExceededError is returned when a request exceeds one of the limits of the
+// server. The limit is returned as error data, so clients can back off or split
+// their requests.
+type limitExceededError struct {
+	message string
+	limit   int
+}
+
+func (e *limitExceededError) ErrorCode() int { return errcodeLimitExceeded }
+
+func (e *limitExceededError) Error() string { return e.message }
+
+func (e *limitExceededError) ErrorData() interface{} {
+	return map[string]int{"limit": e.limit}
+}
+
+// SetRequestLimits sets limits applied to the requests of the clients. 'maxSize' is the
+// maximum number of bytes of a request over HTTP, or of a message over WebSocket, the
+// transport default being used if zero. 'maxInflight' is the maximum number of requests
+// processed concurrently per connection, unlimited if zero. Requests over HTTP are served
+// one at a time, the in-flight limit only applies to persistent connections.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetRequestLimits(maxSize, maxInflight int) {
+	s.requestSizeLimit = maxSize
+	s.inflightLimit = maxInflight
+}
+
+// readLimit returns the maximum size of a request, the given default if unset.
+func (s *Server) readLimit(def int) int {
+	if s.requestSizeLimit > 0 {
+		return s.requestSizeLimit
+	}
+	return def
+}
+
+// acquireInflight reserves a slot for a call on the connection, reporting false if
+// the in-flight limit is reached.
+func (h *handler) acquireInflight() bool {
+	if h.inflightLimit == 0 {
+		return true
+	}
+	if h.inflight.Add(1) > int32(h.inflightLimit) {
+		h.inflight.Add(-1)
+		inflightLimitMeter.Mark(1)
+		return false
+	}
+	return true
+}
+
+// releaseInflight frees the slot reserved by acquireInflight.
+func (h *handler) releaseInflight() {
+	if h.inflightLimit != 0 {
+		h.inflight.Add(-1)
+	}
+}
+
+// releaseInflight frees the in-flight slot held by the calls of the proc once they
+// are processed, before their responses are written, so that the client can send
+// new calls as soon as it receives them.
+func (cp *callProc) releaseInflight() {
+	if cp.release != nil {
+		cp.release()
+		cp.release = nil
+	}
+}
+
+// respondWithInflightLimit rejects the given calls due to the in-flight limit.
+func (h *handler) respondWithInflightLimit(cp *callProc, calls []*jsonrpcMessage, batch bool) {
+	err := &limitExceededError{errMsgTooManyInflight, h.inflightLimit}
+	resps := make([]*jsonrpcMessage, 0, len(calls))
+	for _, msg := range calls {
+		if msg.isCall() {
+			resps = append(resps, msg.errorResponse(err))
+		}
+	}
+	switch {
+	case len(resps) == 0:
+		return
+	case batch:
+		h.conn.writeJSON(cp.ctx, resps, true)
+	default:
+		h.conn.writeJSON(cp.ctx, resps[0], true)
+	}
+}
+
+// writeHTTPLimitError responds to an HTTP request exceeding a limit with the given
+// status code and the error as a JSON-RPC error message.
+func writeHTTPLimitError(w http.ResponseWriter, code int, err *limitExceededError) {
+	w.Header().Set("content-type", contentType)
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(errorMessage(err))
+}
@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInflightLimit(t *testing.T) {
+	server := newTestServer()
+	server.SetRequestLimits(0, 1)
+	defer server.Stop()
+
+	client := DialInProc(server)
+	defer client.Close()
+
+	// Occupy the only slot of the connection with a slow call. Cancelling a call
+	// on the client side is not signalled to the server, the slot is only freed
+	// once the server is done with the call.
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Call(nil, "test_sleep", time.Second)
+	}()
+	var (
+		rpcErr Error
+		limit  bool
+	)
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+		err := client.Call(nil, "test_null")
+		if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == errcodeLimitExceeded {
+			limit = true
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !limit {
+		t.Fatal("in-flight limit not enforced")
+	}
+	var dataErr DataError
+	if !errors.As(rpcErr, &dataErr) || dataErr.ErrorData() == nil {
+		t.Fatal("limit error without data")
+	}
+	// Calls must be served again once the slot is released
+	if err := <-done; err != nil {
+		t.Fatalf("slow call failed: %v", err)
+	}
+	if err := client.Call(nil, "test_null"); err != nil {
+		t.Fatalf("call rejected after release: %v", err)
+	}
+}
+
+func TestRequestSizeLimit(t *testing.T) {
+	server := newTestServer()
+	server.SetRequestLimits(128, 0)
+	defer server.Stop()
+
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["` + strings.Repeat("x", 256) + `",1]}`
+	resp, err := http.Post(ts.URL, contentType, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status code mismatch: have %d, want %d", resp.StatusCode, http.StatusRequestEntityTooLarge)
+	}
+	var msg jsonrpcMessage
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	if msg.Error == nil || msg.Error.Code != errcodeLimitExceeded {
+		t.Fatalf("error mismatch: have %+v, want code %d", msg.Error, errcodeLimitExceeded)
+	}
+}
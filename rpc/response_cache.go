@@ -0,0 +1,20 @@
+package rpc
+
+import "encoding/json"
+
+// ResponseCache caches the results of method calls, so that repeated calls for
+// immutable data are answered without invoking the method.
+type ResponseCache interface {
+	// Get returns the cached result of the given call, if any.
+	Get(method string, params json.RawMessage) (json.RawMessage, bool)
+
+	// Add offers the result of a successful call for caching. The cache decides
+	// whether the result is cacheable.
+	Add(method string, params json.RawMessage, result json.RawMessage)
+}
+
+// SetResponseCache installs the given cache on the server. Calls served by the
+// server are answered from the cache when possible.
+func (s *Server) SetResponseCache(cache ResponseCache) {
+	s.responseCache = cache
+}
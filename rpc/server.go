@@ -51,6 +51,12 @@ type Server struct {
 	run                atomic.Bool
 	batchItemLimit     int
 	batchResponseLimit int
+	requestSizeLimit   int
+	inflightLimit      int
+	responseCache      ResponseCache
+	wsLimits           WebsocketLimits
+	wsConns            wsConnCounter
+	execPools          *ExecutionPools
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -103,6 +109,12 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 		idgen:              s.idgen,
 		batchItemLimit:     s.batchItemLimit,
 		batchResponseLimit: s.batchResponseLimit,
+		inflightLimit:      s.inflightLimit,
+		responseCache:      s.responseCache,
+		execPools:          s.execPools,
+	}
+	if _, ok := codec.(*websocketCodec); ok {
+		cfg.subscriptionLimit = s.wsLimits.MaxSubscriptions
 	}
 	c := initClient(codec, &s.services, cfg)
 	<-codec.closed()
@@ -137,6 +149,8 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 	}
 
 	h := newHandler(ctx, codec, s.idgen, &s.services, s.batchItemLimit, s.batchResponseLimit)
+	h.responseCache = s.responseCache
+	h.execPools = s.execPools
 	h.allowSubscribe = false
 	defer h.close(io.EOF, nil)
 
@@ -19,6 +19,7 @@ package rpc
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"io"
 	"net"
 	"os"
@@ -192,3 +193,59 @@ func TestServerBatchResponseSizeLimit(t *testing.T) {
 		}
 	}
 }
+
+// testResponseCache caches the results of the test_repeat method.
+type testResponseCache struct {
+	results map[string]json.RawMessage
+	added   int
+}
+
+func (c *testResponseCache) Get(method string, params json.RawMessage) (json.RawMessage, bool) {
+	result, ok := c.results[method+string(params)]
+	return result, ok
+}
+
+func (c *testResponseCache) Add(method string, params json.RawMessage, result json.RawMessage) {
+	if method == "test_repeat" {
+		c.results[method+string(params)] = result
+		c.added++
+	}
+}
+
+func TestServerResponseCache(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	cache := &testResponseCache{results: make(map[string]json.RawMessage)}
+	server.SetResponseCache(cache)
+
+	client := DialInProc(server)
+	defer client.Close()
+
+	var result string
+	for i := 0; i < 3; i++ {
+		if err := client.Call(&result, "test_repeat", "x", 2); err != nil {
+			t.Fatal("call failed:", err)
+		}
+		if result != "xx" {
+			t.Fatalf("wrong result: %q", result)
+		}
+	}
+	if cache.added != 1 {
+		t.Fatalf("wrong number of cached results: have %d, want 1", cache.added)
+	}
+	// Cached results are served without invoking the method
+	cache.results[`test_repeat["x",2]`] = json.RawMessage(`"cached"`)
+	if err := client.Call(&result, "test_repeat", "x", 2); err != nil {
+		t.Fatal("call failed:", err)
+	}
+	if result != "cached" {
+		t.Fatalf("cached result not served: %q", result)
+	}
+	// Failed calls are not cached
+	if err := client.Call(&result, "test_repeat", "x", "y"); err == nil {
+		t.Fatal("invalid call succeeded")
+	}
+	if cache.added != 1 {
+		t.Fatal("failed call result cached")
+	}
+}
@@ -190,7 +190,11 @@ func (n *Notifier) send(sub *Subscription, data json.RawMessage) error {
 		Method:  n.namespace + notificationMethodSuffix,
 		Params:  params,
 	}
-	return n.h.conn.writeJSON(ctx, msg, false)
+	err := n.h.conn.writeJSON(ctx, msg, false)
+	if err != nil {
+		evictSlowConsumer(n.h.conn, err)
+	}
+	return err
 }
 
 // A Subscription is created by a notifier and tied to that notifier. The client can use
@@ -25,6 +25,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	mapset "github.com/deckarep/golang-set/v2"
@@ -55,12 +56,22 @@ func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 		CheckOrigin:     wsHandshakeValidator(allowedOrigins),
 	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := wsRemoteIP(r)
+		if err := s.wsConns.acquire(ip, s.wsLimits); err != nil {
+			writeHTTPLimitError(w, http.StatusTooManyRequests, err)
+			return
+		}
+		defer s.wsConns.release(ip)
+
 		conn, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
 			log.Debug("WebSocket upgrade failed", "err", err)
 			return
 		}
-		codec := newWebsocketCodec(conn, r.Host, r.Header, wsDefaultReadLimit)
+		codec := newWebsocketCodec(conn, r.Host, r.Header, int64(s.readLimit(wsDefaultReadLimit)), s.wsLimits.IdleTimeout)
+		// The request context stays valid until the connection is closed, so
+		// values attached by HTTP middleware are carried into every call.
+		codec.connCtx = r.Context()
 		s.ServeCodec(codec, 0)
 	})
 }
@@ -255,7 +266,7 @@ func newClientTransportWS(endpoint string, cfg *clientConfig) (reconnectFunc, er
 		if cfg.wsMessageSizeLimit != nil && *cfg.wsMessageSizeLimit >= 0 {
 			messageSizeLimit = *cfg.wsMessageSizeLimit
 		}
-		return newWebsocketCodec(conn, dialURL, header, messageSizeLimit), nil
+		return newWebsocketCodec(conn, dialURL, header, messageSizeLimit, 0), nil
 	}
 	return connect, nil
 }
@@ -279,15 +290,19 @@ func wsClientHeaders(endpoint, origin string) (string, http.Header, error) {
 
 type websocketCodec struct {
 	*jsonCodec
-	conn *websocket.Conn
-	info PeerInfo
+	conn    *websocket.Conn
+	info    PeerInfo
+	connCtx context.Context // base context of server-side connections
 
 	wg           sync.WaitGroup
 	pingReset    chan struct{}
 	pongReceived chan struct{}
+
+	idleTimeout time.Duration // closes the connection without any message for that long, if set
+	lastActive  atomic.Int64  // unix nanoseconds of the last message read or written
 }
 
-func newWebsocketCodec(conn *websocket.Conn, host string, req http.Header, readLimit int64) ServerCodec {
+func newWebsocketCodec(conn *websocket.Conn, host string, req http.Header, readLimit int64, idleTimeout time.Duration) *websocketCodec {
 	conn.SetReadLimit(readLimit)
 	encode := func(v interface{}, isErrorResponse bool) error {
 		return conn.WriteJSON(v)
@@ -297,11 +312,13 @@ func newWebsocketCodec(conn *websocket.Conn, host string, req http.Header, readL
 		conn:         conn,
 		pingReset:    make(chan struct{}, 1),
 		pongReceived: make(chan struct{}),
+		idleTimeout:  idleTimeout,
 		info: PeerInfo{
 			Transport:  "ws",
 			RemoteAddr: conn.RemoteAddr().String(),
 		},
 	}
+	wc.lastActive.Store(time.Now().UnixNano())
 	// Fill in connection details.
 	wc.info.HTTP.Host = host
 	wc.info.HTTP.Origin = req.Get("Origin")
@@ -328,9 +345,23 @@ func (wc *websocketCodec) peerInfo() PeerInfo {
 	return wc.info
 }
 
+func (wc *websocketCodec) connContext() context.Context {
+	return wc.connCtx
+}
+
+func (wc *websocketCodec) readBatch() ([]*jsonrpcMessage, bool, error) {
+	msgs, batch, err := wc.jsonCodec.readBatch()
+	if err == nil {
+		wc.lastActive.Store(time.Now().UnixNano())
+	}
+	return msgs, batch, err
+}
+
 func (wc *websocketCodec) writeJSON(ctx context.Context, v interface{}, isError bool) error {
 	err := wc.jsonCodec.writeJSON(ctx, v, isError)
 	if err == nil {
+		wc.lastActive.Store(time.Now().UnixNano())
+
 		// Notify pingLoop to delay the next idle ping.
 		select {
 		case wc.pingReset <- struct{}{}:
@@ -340,17 +371,32 @@ func (wc *websocketCodec) writeJSON(ctx context.Context, v interface{}, isError
 	return err
 }
 
-// pingLoop sends periodic ping frames when the connection is idle.
+// pingLoop sends periodic ping frames when the connection is idle, and closes
+// it once idle for longer than the idle timeout.
 func (wc *websocketCodec) pingLoop() {
 	var pingTimer = time.NewTimer(wsPingInterval)
 	defer wc.wg.Done()
 	defer pingTimer.Stop()
 
+	var idleCheck <-chan time.Time
+	if wc.idleTimeout > 0 {
+		ticker := time.NewTicker(wc.idleTimeout / 4)
+		defer ticker.Stop()
+		idleCheck = ticker.C
+	}
 	for {
 		select {
 		case <-wc.closed():
 			return
 
+		case <-idleCheck:
+			if time.Since(time.Unix(0, wc.lastActive.Load())) >= wc.idleTimeout {
+				log.Debug("Closing idle WebSocket connection", "conn", wc.info.RemoteAddr, "timeout", wc.idleTimeout)
+				wsIdleEvictionMeter.Mark(1)
+				wc.jsonCodec.close()
+				return
+			}
+
 		case <-wc.pingReset:
 			if !pingTimer.Stop() {
 				<-pingTimer.C
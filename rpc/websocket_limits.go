@@ -0,0 +1,127 @@
+package rpc
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	wsConnGauge            = metrics.NewRegisteredGauge("rpc/ws/conns", nil)
+	wsConnLimitMeter       = metrics.NewRegisteredMeter("rpc/limits/wsconns", nil)
+	wsIPConnLimitMeter     = metrics.NewRegisteredMeter("rpc/limits/wsconns/ip", nil)
+	subscriptionLimitMeter = metrics.NewRegisteredMeter("rpc/limits/subscriptions", nil)
+	wsIdleEvictionMeter    = metrics.NewRegisteredMeter("rpc/ws/evicted/idle", nil)
+	slowConsumerMeter      = metrics.NewRegisteredMeter("rpc/ws/evicted/slow", nil)
+)
+
+const (
+	errMsgTooManyConns         = "too many connections"
+	errMsgTooManySubscriptions = "too many subscriptions"
+)
+
+// WebsocketLimits are the limits applied to the WebSocket connections of a server.
+// Zero values disable the corresponding limit.
+type WebsocketLimits struct {
+	MaxConns         int           // Maximum number of connections
+	MaxConnsPerIP    int           // Maximum number of connections from a single IP
+	IdleTimeout      time.Duration // Time without any message after which a connection is closed
+	MaxSubscriptions int           // Maximum number of subscriptions per connection
+}
+
+// SetWebsocketLimits sets the limits applied to the WebSocket connections. Clients
+// whose notifications can't be written in time are disconnected regardless.
+//
+// This method should be called before serving any connection via WebsocketHandler.
+func (s *Server) SetWebsocketLimits(limits WebsocketLimits) {
+	s.wsLimits = limits
+}
+
+// wsConnCounter counts the WebSocket connections of a server, in total and per IP.
+type wsConnCounter struct {
+	lock  sync.Mutex
+	total int
+	perIP map[string]int
+}
+
+// acquire reserves a connection slot for the given IP, returning an error if a
+// connection limit is reached.
+func (c *wsConnCounter) acquire(ip string, limits WebsocketLimits) *limitExceededError {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if limits.MaxConns > 0 && c.total >= limits.MaxConns {
+		wsConnLimitMeter.Mark(1)
+		return &limitExceededError{errMsgTooManyConns, limits.MaxConns}
+	}
+	if limits.MaxConnsPerIP > 0 && c.perIP[ip] >= limits.MaxConnsPerIP {
+		wsIPConnLimitMeter.Mark(1)
+		return &limitExceededError{errMsgTooManyConns, limits.MaxConnsPerIP}
+	}
+	if c.perIP == nil {
+		c.perIP = make(map[string]int)
+	}
+	c.total++
+	c.perIP[ip]++
+	wsConnGauge.Inc(1)
+	return nil
+}
+
+// release frees the connection slot reserved by acquire.
+func (c *wsConnCounter) release(ip string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.total--
+	if c.perIP[ip]--; c.perIP[ip] <= 0 {
+		delete(c.perIP, ip)
+	}
+	wsConnGauge.Dec(1)
+}
+
+// wsRemoteIP returns the IP address of the peer of the request.
+func wsRemoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// acquireSubscription reports whether the connection may create one more
+// subscription.
+func (h *handler) acquireSubscription() bool {
+	if h.subscriptionLimit == 0 {
+		return true
+	}
+	h.subLock.Lock()
+	defer h.subLock.Unlock()
+
+	if len(h.serverSubs) >= h.subscriptionLimit {
+		subscriptionLimitMeter.Mark(1)
+		return false
+	}
+	return true
+}
+
+// evictSlowConsumer disconnects a WebSocket client if the notification failed to
+// be written in time, so that a client not reading its notifications doesn't pile
+// them up in memory.
+func evictSlowConsumer(conn jsonWriter, err error) {
+	wc, ok := conn.(*websocketCodec)
+	if !ok {
+		return
+	}
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		return
+	}
+	log.Debug("Disconnecting slow WebSocket subscriber", "conn", wc.info.RemoteAddr)
+	slowConsumerMeter.Mark(1)
+	wc.jsonCodec.close()
+}
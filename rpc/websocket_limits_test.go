@@ -0,0 +1,109 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebsocketConnLimit(t *testing.T) {
+	srv := newTestServer()
+	srv.SetWebsocketLimits(WebsocketLimits{MaxConnsPerIP: 1})
+	defer srv.Stop()
+
+	httpsrv := httptest.NewServer(srv.WebsocketHandler([]string{"*"}))
+	defer httpsrv.Close()
+	wsURL := "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+
+	client, err := DialWebsocket(context.Background(), wsURL, "")
+	if err != nil {
+		t.Fatalf("can't dial: %v", err)
+	}
+	if err := client.Call(nil, "test_null"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	// A second connection from the same IP must be refused
+	_, err = DialWebsocket(context.Background(), wsURL, "")
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("second connection not refused: %v", err)
+	}
+	// The slot must be released once the first connection is closed
+	client.Close()
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		client, err = DialWebsocket(context.Background(), wsURL, "")
+		if err == nil {
+			client.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("connection refused after release: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWebsocketSubscriptionLimit(t *testing.T) {
+	srv := newTestServer()
+	srv.SetWebsocketLimits(WebsocketLimits{MaxSubscriptions: 2})
+	defer srv.Stop()
+
+	httpsrv := httptest.NewServer(srv.WebsocketHandler([]string{"*"}))
+	defer httpsrv.Close()
+
+	client, err := DialWebsocket(context.Background(), "ws:"+strings.TrimPrefix(httpsrv.URL, "http:"), "")
+	if err != nil {
+		t.Fatalf("can't dial: %v", err)
+	}
+	defer client.Close()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Subscribe(context.Background(), "nftest", make(chan int), "someSubscription", 0, i); err != nil {
+			t.Fatalf("subscription %d failed: %v", i, err)
+		}
+	}
+	_, err = client.Subscribe(context.Background(), "nftest", make(chan int), "someSubscription", 0, 2)
+	var rpcErr Error
+	if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != errcodeLimitExceeded {
+		t.Fatalf("subscription limit not enforced: %v", err)
+	}
+}
+
+func TestWebsocketIdleTimeout(t *testing.T) {
+	srv := newTestServer()
+	srv.SetWebsocketLimits(WebsocketLimits{IdleTimeout: 100 * time.Millisecond})
+	defer srv.Stop()
+
+	httpsrv := httptest.NewServer(srv.WebsocketHandler([]string{"*"}))
+	defer httpsrv.Close()
+
+	client, err := DialWebsocket(context.Background(), "ws:"+strings.TrimPrefix(httpsrv.URL, "http:"), "")
+	if err != nil {
+		t.Fatalf("can't dial: %v", err)
+	}
+	defer client.Close()
+
+	// Wait for the idle connection to be dropped by the server
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		srv.mutex.Lock()
+		conns := len(srv.codecs)
+		srv.mutex.Unlock()
+		if conns == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("idle connection not closed")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestWebsocketRemoteIP(t *testing.T) {
+	r := &http.Request{RemoteAddr: "192.0.2.1:30303"}
+	if ip := wsRemoteIP(r); ip != "192.0.2.1" {
+		t.Fatalf("remote IP mismatch: have %s, want 192.0.2.1", ip)
+	}
+}